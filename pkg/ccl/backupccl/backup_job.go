@@ -541,6 +541,16 @@ func (b *backupResumer) Resume(
 	}
 	b.deleteCheckpoint(ctx, p.ExecCfg())
 
+	// A new full backup in a collection becomes the one that later
+	// BACKUP INTO LATEST IN and RESTORE FROM LATEST IN statements resolve to.
+	if details.CollectionURI != "" {
+		if err := writeLatestFile(
+			ctx, details.CollectionURI, details.CollectionSubdir, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI,
+		); err != nil {
+			return errors.Wrap(err, "updating LATEST file in backup collection")
+		}
+	}
+
 	resultsCh <- tree.Datums{
 		tree.NewDInt(tree.DInt(*b.job.ID())),
 		tree.NewDString(string(jobs.StatusSucceeded)),
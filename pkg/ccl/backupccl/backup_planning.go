@@ -229,9 +229,11 @@ func backupJobDescription(
 	opts map[string]string,
 ) (string, error) {
 	b := &tree.Backup{
-		AsOf:    backup.AsOf,
-		Options: optsToKVOptions(opts),
-		Targets: backup.Targets,
+		AsOf:           backup.AsOf,
+		Options:        optsToKVOptions(opts),
+		Targets:        backup.Targets,
+		Nested:         backup.Nested,
+		AppendToLatest: backup.AppendToLatest,
 	}
 
 	for _, t := range to {
@@ -378,6 +380,29 @@ func backupPlanHook(
 			encryptionPassphrase = []byte(passphrase)
 		}
 
+		// BACKUP INTO writes each full backup to its own subdirectory of the
+		// collection, and appends incremental layers to the full backup that the
+		// collection's LATEST file points at.
+		collectionURIs := to
+		var collectionURI, collectionSubdir string
+		if backupStmt.Nested {
+			collectionURI, _, err = getURIsByLocalityKV(collectionURIs, "")
+			if err != nil {
+				return err
+			}
+			if backupStmt.AppendToLatest {
+				collectionSubdir, err = readLatestFile(ctx, collectionURI, makeCloudStorage)
+				if err != nil {
+					return err
+				}
+			} else {
+				collectionSubdir = endTime.GoTime().Format(dateBasedIntoFolderName)
+			}
+			if to, err = appendPaths(collectionURIs, collectionSubdir); err != nil {
+				return err
+			}
+		}
+
 		defaultURI, urisByLocalityKV, err := getURIsByLocalityKV(to, "")
 		if err != nil {
 			return err
@@ -440,6 +465,14 @@ func backupPlanHook(
 			if err != nil {
 				return err
 			}
+			if backupStmt.Nested {
+				if backupStmt.AppendToLatest && !exists {
+					return errors.Errorf("no full backup found in collection subdirectory %s to append to", collectionSubdir)
+				}
+				if !backupStmt.AppendToLatest && exists {
+					return errors.Errorf("a backup already exists in collection subdirectory %s", collectionSubdir)
+				}
+			}
 			if exists {
 				if encryptionPassphrase != nil {
 					encOpts, err := readEncryptionOptions(ctx, defaultStore)
@@ -614,7 +647,11 @@ func backupPlanHook(
 			return err
 		}
 
-		description, err := backupJobDescription(p, backupStmt, to, incrementalFrom, opts)
+		descriptionTo := to
+		if backupStmt.Nested {
+			descriptionTo = collectionURIs
+		}
+		description, err := backupJobDescription(p, backupStmt, descriptionTo, incrementalFrom, opts)
 		if err != nil {
 			return err
 		}
@@ -653,6 +690,10 @@ func backupPlanHook(
 			BackupManifest:   descBytes,
 			Encryption:       encryption,
 		}
+		if backupStmt.Nested && !backupStmt.AppendToLatest {
+			backupDetails.CollectionURI = collectionURI
+			backupDetails.CollectionSubdir = collectionSubdir
+		}
 		if len(spans) > 0 {
 			protectedtsID := uuid.MakeV4()
 			backupDetails.ProtectedTimestampRecord = &protectedtsID
@@ -705,6 +746,9 @@ func backupPlanHook(
 			if len(backupStmt.To) > 1 {
 				telemetry.Count("backup.partitioned")
 			}
+			if backupStmt.Nested {
+				telemetry.Count("backup.nested")
+			}
 			if mvccFilter == MVCCFilter_All {
 				telemetry.Count("backup.revision-history")
 			}
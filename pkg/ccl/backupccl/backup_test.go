@@ -436,6 +436,59 @@ func TestBackupRestoreAppend(t *testing.T) {
 	// TODO(dt): test restoring to other backups via AOST.
 }
 
+func TestBackupRestoreIntoCollection(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 1000
+	_, _, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	const collection = localFoo + "/collection"
+
+	sqlDB.ExpectErr(t, "could not find a full backup in the collection",
+		"BACKUP DATABASE data INTO LATEST IN $1", collection)
+	sqlDB.ExpectErr(t, "could not find a full backup in the collection",
+		"RESTORE DATABASE data FROM LATEST IN $1", collection)
+
+	// Take two full backups into the collection, appending an incremental
+	// layer to the second one.
+	sqlDB.Exec(t, "BACKUP DATABASE data INTO $1", collection)
+	sqlDB.Exec(t, "UPDATE data.bank SET balance = 100")
+	sqlDB.Exec(t, "BACKUP DATABASE data INTO $1", collection)
+	sqlDB.Exec(t, "UPDATE data.bank SET balance = 200")
+	rowsAfterInc := sqlDB.QueryStr(t, "SELECT * FROM data.bank ORDER BY id")
+	sqlDB.Exec(t, "BACKUP DATABASE data INTO LATEST IN $1", collection)
+
+	var backups []string
+	rows := sqlDB.Query(t, "SHOW BACKUPS IN $1", collection)
+	for rows.Next() {
+		var subdir string
+		if err := rows.Scan(&subdir); err != nil {
+			t.Fatal(err)
+		}
+		backups = append(backups, subdir)
+	}
+	require.NoError(t, rows.Err())
+	require.Len(t, backups, 2)
+
+	// LATEST resolves to the second full backup, including its incremental
+	// layer.
+	sqlDB.CheckQueryResults(t,
+		fmt.Sprintf("SELECT count(DISTINCT end_time) FROM [SHOW BACKUP LATEST IN '%s']", collection),
+		[][]string{{"2"}})
+
+	sqlDB.Exec(t, "DROP DATABASE data CASCADE")
+	sqlDB.Exec(t, "RESTORE DATABASE data FROM LATEST IN $1", collection)
+	sqlDB.CheckQueryResults(t, "SELECT * FROM data.bank ORDER BY id", rowsAfterInc)
+
+	// Restoring the first backup by its subdirectory gets the original data.
+	sqlDB.Exec(t, "DROP DATABASE data CASCADE")
+	sqlDB.Exec(t, "RESTORE DATABASE data FROM $1 IN $2", backups[0], collection)
+	sqlDB.CheckQueryResults(t,
+		"SELECT count(*) FROM data.bank WHERE balance = 0",
+		[][]string{{strconv.Itoa(numAccounts)}})
+}
+
 func TestBackupRestorePartitionedMergeDirectories(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -2650,6 +2703,68 @@ func TestTimestampMismatch(t *testing.T) {
 	})
 }
 
+func TestBackupVerify(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 1000
+	_, _, sqlDB, rawDir, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE TABLE data.parent (id INT PRIMARY KEY)`)
+	sqlDB.Exec(t, `CREATE TABLE data.child (id INT PRIMARY KEY, parent_id INT REFERENCES data.parent)`)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1`, localFoo)
+
+	// An intact backup verifies cleanly, including its rows.
+	sqlDB.CheckQueryResults(t, fmt.Sprintf(`SHOW BACKUP CHECK '%s' WITH verify_rows`, localFoo), [][]string{})
+	sqlDB.CheckQueryResults(t,
+		fmt.Sprintf(`RESTORE DATABASE data FROM '%s' WITH verify_only, verify_rows`, localFoo), [][]string{})
+	sqlDB.ExpectErr(t, "verify_rows can only be used with verify_only",
+		`RESTORE DATABASE data FROM $1 WITH verify_rows`, localFoo)
+
+	// Restoring only one side of a foreign key is reported.
+	sqlDB.CheckQueryResults(t,
+		fmt.Sprintf(`SELECT problem, object FROM [RESTORE data.child FROM '%s' WITH verify_only]`, localFoo),
+		[][]string{{"missing reference", "child"}})
+
+	// Corrupt one SST and remove another.
+	var ssts []string
+	if err := filepath.Walk(rawDir+"/foo", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if filepath.Ext(path) == ".sst" {
+			ssts = append(ssts, path)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ssts) < 2 {
+		t.Fatalf("expected at least 2 SSTs in backup, found %d", len(ssts))
+	}
+	data, err := ioutil.ReadFile(ssts[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)/2] ^= 0xff
+	if err := ioutil.WriteFile(ssts[0], data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(ssts[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	sqlDB.CheckQueryResults(t,
+		fmt.Sprintf(`SELECT problem, object FROM [SHOW BACKUP CHECK '%s'] ORDER BY problem`, localFoo),
+		[][]string{
+			{"checksum mismatch", filepath.Base(ssts[0])},
+			{"missing file", filepath.Base(ssts[1])},
+		})
+
+	// Verifying did not restore anything.
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM [SHOW JOBS] WHERE job_type = 'RESTORE'`, [][]string{{"0"}})
+}
+
 func TestBackupLevelDB(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -2845,6 +2960,63 @@ func TestRestoreInto(t *testing.T) {
 	sqlDB.CheckQueryResults(t, `SELECT * FROM "data 2".bank`, expected)
 }
 
+func TestRestoreTableAsNewName(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 1
+	_, _, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE SEQUENCE data.orders_seq`)
+	sqlDB.Exec(t, `CREATE TABLE data.orders (id INT PRIMARY KEY DEFAULT nextval('data.orders_seq'), v INT, INDEX (v))`)
+	sqlDB.Exec(t, `ALTER SEQUENCE data.orders_seq OWNED BY data.orders.id`)
+	sqlDB.Exec(t, `INSERT INTO data.orders (v) VALUES (10), (20), (30)`)
+	expected := sqlDB.QueryStr(t, `SELECT * FROM data.orders ORDER BY id`)
+
+	var ts string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&ts)
+	sqlDB.Exec(t, `DELETE FROM data.orders WHERE v > 10`)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 WITH revision_history`, localFoo)
+
+	sqlDB.ExpectErr(t, `relation "orders" already exists`,
+		fmt.Sprintf(`RESTORE TABLE data.orders AS data.orders FROM '%s'`, localFoo))
+
+	// Recover the table as it was before the delete next to the live table.
+	sqlDB.Exec(t, fmt.Sprintf(
+		`RESTORE TABLE data.orders AS data.orders_recovered FROM '%s' AS OF SYSTEM TIME %s`,
+		localFoo, ts))
+	sqlDB.CheckQueryResults(t, `SELECT * FROM data.orders_recovered ORDER BY id`, expected)
+	sqlDB.CheckQueryResults(t, `SELECT * FROM data.orders_recovered@orders_v_idx WHERE v = 20`, [][]string{{"2", "20"}})
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM data.orders`, [][]string{{"1"}})
+
+	// The recovered table keeps using the live table's sequence, which depends
+	// on it.
+	sqlDB.Exec(t, `INSERT INTO data.orders_recovered (v) VALUES (40)`)
+	sqlDB.CheckQueryResults(t, `SELECT * FROM data.orders_recovered WHERE v = 40`, [][]string{{"4", "40"}})
+	sqlDB.CheckQueryResults(t, `
+SELECT count(*) FROM data.crdb_internal.forward_dependencies
+WHERE descriptor_name = 'orders_seq' AND dependedonby_type = 'sequence'`,
+		[][]string{{"2"}})
+
+	// The recovered table does not take ownership of the live table's sequence,
+	// so dropping it leaves the sequence in place.
+	sqlDB.Exec(t, `DROP TABLE data.orders_recovered`)
+	sqlDB.Exec(t, `INSERT INTO data.orders (v) VALUES (50)`)
+	sqlDB.CheckQueryResults(t, `SELECT * FROM data.orders WHERE v = 50`, [][]string{{"5", "50"}})
+
+	// The sequence can only be kept when the table is restored into its
+	// database, since the default expression refers to the sequence by name.
+	sqlDB.ExpectErr(t, `cannot restore table "orders" without referenced sequence`,
+		fmt.Sprintf(`RESTORE TABLE data.orders AS defaultdb.orders FROM '%s'`, localFoo))
+
+	// Qualifying the new name with another database restores into it.
+	sqlDB.Exec(t, `CREATE DATABASE recovery`)
+	sqlDB.Exec(t, fmt.Sprintf(
+		`RESTORE TABLE data.orders AS recovery.orders FROM '%s' AS OF SYSTEM TIME %s WITH skip_missing_sequences`,
+		localFoo, ts))
+	sqlDB.CheckQueryResults(t, `SELECT * FROM recovery.orders ORDER BY id`, expected)
+}
+
 func TestBackupRestorePermissions(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -3494,6 +3666,79 @@ func TestBackupRestoreEmptyDB(t *testing.T) {
 	sqlDB.CheckQueryResults(t, `USE empty; SHOW TABLES;`, [][]string{})
 }
 
+func TestBackupRestoreUserDefinedFunctions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 1
+	_, tc, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE USER testuser`)
+	sqlDB.Exec(t, `
+		CREATE FUNCTION data.add_one(x INT) RETURNS INT LANGUAGE SQL IMMUTABLE AS 'SELECT x + 1';
+		CREATE FUNCTION data.add_one(x FLOAT) RETURNS FLOAT LANGUAGE SQL IMMUTABLE AS 'SELECT x + 1.5';
+		CREATE FUNCTION data.num_accounts() RETURNS INT LANGUAGE SQL STABLE AS 'SELECT count(*) FROM data.bank';
+		REVOKE EXECUTE ON FUNCTION data.add_one(INT), data.add_one(FLOAT) FROM public;
+		GRANT EXECUTE ON FUNCTION data.add_one(INT) TO testuser;
+	`)
+
+	const functionsQuery = `
+		SELECT function_name, create_statement
+		  FROM data.crdb_internal.create_function_statements
+		 ORDER BY function_name, create_statement`
+	const functionIDsQuery = `SELECT function_id FROM data.crdb_internal.create_function_statements`
+	expectedFunctions := sqlDB.QueryStr(t, functionsQuery)
+	oldIDs := make(map[string]struct{})
+	for _, row := range sqlDB.QueryStr(t, functionIDsQuery) {
+		oldIDs[row[0]] = struct{}{}
+	}
+
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1`, localFoo)
+	sqlDB.Exec(t, `DROP DATABASE data CASCADE`)
+	sqlDB.Exec(t, `RESTORE DATABASE data FROM $1`, localFoo)
+
+	sqlDB.CheckQueryResults(t, functionsQuery, expectedFunctions)
+	sqlDB.CheckQueryResults(t,
+		`SELECT data.add_one(1), data.add_one(1.0::FLOAT), data.num_accounts()`,
+		[][]string{{"2", "2.5", "1"}},
+	)
+
+	// The restored functions are given new IDs, since the IDs from the backup
+	// could otherwise be allocated again to new functions of the database.
+	for _, row := range sqlDB.QueryStr(t, functionIDsQuery) {
+		if _, ok := oldIDs[row[0]]; ok {
+			t.Fatalf("restored function kept its ID %s", row[0])
+		}
+	}
+	sqlDB.Exec(t, `CREATE FUNCTION data.add_two(x INT) RETURNS INT LANGUAGE SQL IMMUTABLE AS 'SELECT x + 2'`)
+	sqlDB.CheckQueryResults(t,
+		`SELECT count(DISTINCT function_id) FROM data.crdb_internal.create_function_statements`,
+		[][]string{{"4"}},
+	)
+
+	// The privileges of the functions are restored along with them.
+	pgURL, cleanupFunc := sqlutils.PGUrl(
+		t, tc.Server(0).ServingSQLAddr(), "TestBackupRestoreUserDefinedFunctions-testuser", url.User("testuser"),
+	)
+	defer cleanupFunc()
+	testuser, err := gosql.Open("postgres", pgURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testuser.Close()
+	var res int
+	if err := testuser.QueryRow(`SELECT data.add_one(1)`).Scan(&res); err != nil {
+		t.Fatal(err)
+	} else if res != 2 {
+		t.Fatalf("expected 2, got %d", res)
+	}
+	if _, err := testuser.Exec(`SELECT data.add_one(1.0::FLOAT)`); !testutils.IsError(
+		err, "user testuser does not have EXECUTE privilege on function add_one",
+	) {
+		t.Fatalf("expected privilege error, got %v", err)
+	}
+}
+
 func TestBackupRestoreSubsetCreatedStats(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
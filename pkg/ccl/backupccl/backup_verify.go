@@ -0,0 +1,306 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// The kinds of problems that verifying a backup can report.
+const (
+	backupCheckManifestChain = "manifest chain"
+	backupCheckMissingFile   = "missing file"
+	backupCheckCorruptFile   = "corrupt file"
+	backupCheckChecksum      = "checksum mismatch"
+	backupCheckReference     = "missing reference"
+	backupCheckRow           = "row integrity"
+)
+
+// backupCheckHeader is the header for the results of SHOW BACKUP CHECK and
+// RESTORE ... WITH verify_only. Each row describes one problem found in the
+// backup; a backup that verifies cleanly produces no rows.
+var backupCheckHeader = sqlbase.ResultColumns{
+	{Name: "problem", Typ: types.String},
+	{Name: "object", Typ: types.String},
+	{Name: "detail", Typ: types.String},
+}
+
+// backupVerifier checks that a resolved chain of backup layers could be
+// restored, without writing anything into the cluster.
+type backupVerifier struct {
+	mkStore    cloud.ExternalStorageFromURIFactory
+	encryption *roachpb.FileEncryptionOptions
+	// verifyRows, if set, additionally decodes every key in every file of the
+	// backup and checks it against the backed up table descriptors.
+	verifyRows bool
+
+	problems []tree.Datums
+}
+
+func (v *backupVerifier) report(problem, object, detail string, args ...interface{}) {
+	v.problems = append(v.problems, tree.Datums{
+		tree.NewDString(problem),
+		tree.NewDString(object),
+		tree.NewDString(fmt.Sprintf(detail, args...)),
+	})
+}
+
+// verifyBackup verifies the backup layers in manifests, whose default URIs
+// and locality info are as returned by resolveBackupManifests. Only the
+// references of the descriptors in targets are checked; all files in the
+// backup are checked regardless. It returns one row per problem found, in the
+// format of backupCheckHeader.
+func verifyBackup(
+	ctx context.Context,
+	mkStore cloud.ExternalStorageFromURIFactory,
+	defaultURIs []string,
+	manifests []BackupManifest,
+	localityInfo []jobspb.RestoreDetails_BackupLocalityInfo,
+	encryption *roachpb.FileEncryptionOptions,
+	targets []sqlbase.Descriptor,
+	verifyRows bool,
+) ([]tree.Datums, error) {
+	v := &backupVerifier{mkStore: mkStore, encryption: encryption, verifyRows: verifyRows}
+	v.checkManifestChain(defaultURIs, manifests)
+	v.checkReferences(targets)
+	for i := range manifests {
+		if err := v.checkFiles(ctx, defaultURIs[i], manifests[i], localityInfo[i]); err != nil {
+			return nil, err
+		}
+	}
+	return v.problems, nil
+}
+
+// checkManifestChain checks that each layer picks up where the previous one
+// left off and that all of them were taken of the same cluster.
+func (v *backupVerifier) checkManifestChain(defaultURIs []string, manifests []BackupManifest) {
+	for i := 1; i < len(manifests); i++ {
+		prev, cur := manifests[i-1], manifests[i]
+		if cur.StartTime != prev.EndTime {
+			v.report(backupCheckManifestChain, redactURI(defaultURIs[i]),
+				"layer starts at %s but the previous layer ends at %s", cur.StartTime, prev.EndTime)
+		}
+		if !cur.ClusterID.Equal(prev.ClusterID) {
+			v.report(backupCheckManifestChain, redactURI(defaultURIs[i]),
+				"layer belongs to cluster %s but the previous layer belongs to cluster %s",
+				cur.ClusterID, prev.ClusterID)
+		}
+	}
+}
+
+// checkReferences checks that the tables referenced by the targets' foreign
+// keys, sequence defaults, views and interleaves are also among the targets,
+// as RESTORE would otherwise refuse to restore them (or, with the skip_missing
+// options, drop the references).
+func (v *backupVerifier) checkReferences(targets []sqlbase.Descriptor) {
+	present := make(map[sqlbase.ID]struct{}, len(targets))
+	for i := range targets {
+		present[targets[i].GetID()] = struct{}{}
+	}
+	check := func(table *sqlbase.TableDescriptor, id sqlbase.ID, kind string) {
+		if _, ok := present[id]; !ok {
+			v.report(backupCheckReference, table.Name, "%s references table %d, which is not in the backup", kind, id)
+		}
+	}
+	for i := range targets {
+		table := targets[i].Table(hlc.Timestamp{})
+		if table == nil {
+			continue
+		}
+		for _, fk := range table.OutboundFKs {
+			check(table, fk.ReferencedTableID, "foreign key "+fk.Name)
+		}
+		for _, col := range table.Columns {
+			for _, seqID := range col.UsesSequenceIds {
+				check(table, seqID, "column "+col.Name)
+			}
+		}
+		for _, id := range table.DependsOn {
+			check(table, id, "view")
+		}
+		for _, idx := range table.AllNonDropIndexes() {
+			for _, ancestor := range idx.Interleave.Ancestors {
+				check(table, ancestor.TableID, "interleaved index "+idx.Name)
+			}
+		}
+	}
+}
+
+// checkFiles checks that every file of a single backup layer can be read from
+// the location RESTORE would read it from, and that its contents match the
+// checksum recorded when it was written.
+func (v *backupVerifier) checkFiles(
+	ctx context.Context,
+	defaultURI string,
+	manifest BackupManifest,
+	localityInfo jobspb.RestoreDetails_BackupLocalityInfo,
+) error {
+	stores := make(map[string]cloud.ExternalStorage)
+	defer func() {
+		for _, store := range stores {
+			store.Close()
+		}
+	}()
+
+	var kr *storageccl.KeyRewriter
+	if v.verifyRows {
+		var err error
+		if kr, err = makeIdentityKeyRewriter(manifest); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range manifest.Files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Partitioned backups write each file to the location of the locality
+		// it was exported from, falling back to the default location.
+		uri := defaultURI
+		if u, ok := localityInfo.URIsByOriginalLocalityKV[file.LocalityKV]; ok {
+			uri = u
+		}
+		store, ok := stores[uri]
+		if !ok {
+			var err error
+			if store, err = v.mkStore(ctx, uri); err != nil {
+				return errors.Wrapf(err, "make storage")
+			}
+			stores[uri] = store
+		}
+
+		r, err := store.ReadFile(ctx, file.Path)
+		if err != nil {
+			v.report(backupCheckMissingFile, file.Path, "%v", err)
+			continue
+		}
+		contents, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			v.report(backupCheckMissingFile, file.Path, "%v", err)
+			continue
+		}
+		if v.encryption != nil {
+			if contents, err = storageccl.DecryptFile(contents, v.encryption.Key); err != nil {
+				v.report(backupCheckCorruptFile, file.Path, "%v", err)
+				continue
+			}
+		}
+		if len(file.Sha512) > 0 {
+			checksum, err := storageccl.SHA512ChecksumData(contents)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(checksum, file.Sha512) {
+				v.report(backupCheckChecksum, file.Path, "contents do not match the checksum recorded in the backup")
+				continue
+			}
+		}
+		if kr != nil {
+			v.checkRows(file, contents, manifest.EndTime, kr)
+		}
+	}
+	return nil
+}
+
+// checkRows decodes every key in an SST from the backup, checking that it
+// falls within the file's span and backup interval, that it belongs to a
+// table in the backup and that its value's checksum is intact. Only the first
+// problem in each file is reported.
+func (v *backupVerifier) checkRows(
+	file BackupManifest_File, contents []byte, endTime hlc.Timestamp, kr *storageccl.KeyRewriter,
+) {
+	iter, err := storage.NewMemSSTIterator(contents, false)
+	if err != nil {
+		v.report(backupCheckCorruptFile, file.Path, "%v", err)
+		return
+	}
+	defer iter.Close()
+
+	for iter.SeekGE(storage.MVCCKey{Key: keys.MinKey}); ; iter.Next() {
+		ok, err := iter.Valid()
+		if err != nil {
+			v.report(backupCheckCorruptFile, file.Path, "%v", err)
+			return
+		}
+		if !ok {
+			return
+		}
+		key := iter.UnsafeKey()
+		if !file.Span.ContainsKey(key.Key) {
+			v.report(backupCheckRow, file.Path, "key %s is outside of the file's span %s", key.Key, file.Span)
+			return
+		}
+		if endTime.Less(key.Timestamp) {
+			v.report(backupCheckRow, file.Path, "key %s is newer than the backup's end time %s", key, endTime)
+			return
+		}
+		if _, ok, err := kr.RewriteKey(append([]byte(nil), key.Key...), false /* isFromSpan */); err != nil {
+			v.report(backupCheckRow, file.Path, "decoding key %s: %v", key.Key, err)
+			return
+		} else if !ok {
+			v.report(backupCheckRow, file.Path, "key %s does not belong to any table in the backup", key.Key)
+			return
+		}
+		if value := iter.UnsafeValue(); len(value) > 0 {
+			if err := (roachpb.Value{RawBytes: value}).Verify(key.Key); err != nil {
+				v.report(backupCheckRow, file.Path, "%v", err)
+				return
+			}
+		}
+	}
+}
+
+// makeIdentityKeyRewriter makes a KeyRewriter that maps the keys of every
+// table in the backup layer (including ones only present in its descriptor
+// revisions) onto themselves, which is used to check that each key decodes to
+// a known table and index.
+func makeIdentityKeyRewriter(manifest BackupManifest) (*storageccl.KeyRewriter, error) {
+	tables := make(map[sqlbase.ID]*sqlbase.TableDescriptor)
+	for i := range manifest.Descriptors {
+		if table := manifest.Descriptors[i].Table(hlc.Timestamp{}); table != nil {
+			tables[table.ID] = table
+		}
+	}
+	for _, rev := range manifest.DescriptorChanges {
+		if rev.Desc == nil {
+			continue
+		}
+		if table := rev.Desc.Table(hlc.Timestamp{}); table != nil {
+			if _, ok := tables[table.ID]; !ok {
+				tables[table.ID] = table
+			}
+		}
+	}
+	return storageccl.MakeKeyRewriter(tables)
+}
+
+// redactURI strips credentials from uri so it can be shown to the user.
+func redactURI(uri string) string {
+	sanitized, err := cloud.SanitizeExternalStorageURI(uri, nil /* extraParams */)
+	if err != nil {
+		return "<invalid URI>"
+	}
+	return sanitized
+}
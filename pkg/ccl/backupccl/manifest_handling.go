@@ -15,6 +15,7 @@ import (
 	"net/url"
 	"path"
 	"sort"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -48,6 +49,15 @@ const (
 	BackupFormatDescriptorTrackingVersion uint32 = 1
 )
 
+const (
+	// latestFileName is the name of the file in a backup collection that names
+	// the subdirectory of the most recent full backup written by BACKUP INTO.
+	latestFileName = "LATEST"
+	// dateBasedIntoFolderName is the format of the subdirectory, relative to
+	// the collection, that BACKUP INTO writes each new full backup to.
+	dateBasedIntoFolderName = "/2006/01/02-150405.00"
+)
+
 // BackupFileDescriptors is an alias on which to implement sort's interface.
 type BackupFileDescriptors []BackupManifest_File
 
@@ -315,6 +325,97 @@ func findPriorBackups(ctx context.Context, store cloud.ExternalStorage) ([]strin
 	return prev, nil
 }
 
+// findBackupsInCollection finds the full backups written to a collection by
+// BACKUP INTO, returning their subdirectories in the same form as the LATEST
+// file names them (e.g. /YYYY/MM/DD-HHmmss.ss).
+func findBackupsInCollection(ctx context.Context, store cloud.ExternalStorage) ([]string, error) {
+	manifests, err := store.ListFiles(ctx, "[0-9]*/[0-9]*/[0-9]*-[0-9]*.[0-9][0-9]/"+BackupManifestName)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing backups in collection")
+	}
+	subdirs := make([]string, len(manifests))
+	for i, manifest := range manifests {
+		subdirs[i] = "/" + path.Dir(manifest)
+	}
+	sort.Strings(subdirs)
+	return subdirs, nil
+}
+
+// readLatestFile reads the LATEST file of the backup collection at
+// collectionURI, returning the subdirectory of the most recent full backup in
+// that collection.
+func readLatestFile(
+	ctx context.Context,
+	collectionURI string,
+	makeExternalStorageFromURI cloud.ExternalStorageFromURIFactory,
+) (string, error) {
+	collection, err := makeExternalStorageFromURI(ctx, collectionURI)
+	if err != nil {
+		return "", err
+	}
+	defer collection.Close()
+	r, err := collection.ReadFile(ctx, latestFileName)
+	if err != nil {
+		return "", errors.Wrap(err, "could not find a full backup in the collection (reading LATEST file)")
+	}
+	defer r.Close()
+	latest, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	if len(latest) == 0 {
+		return "", errors.Errorf("malformed LATEST file in backup collection")
+	}
+	return string(latest), nil
+}
+
+// resolveCollectionSubdir resolves the subdirectory of a backup in the
+// collection at collectionURI, as named in a FROM ... IN or SHOW BACKUP ... IN
+// clause: LATEST refers to the subdirectory named by the collection's LATEST
+// file, and anything else is taken to be the subdirectory itself.
+func resolveCollectionSubdir(
+	ctx context.Context,
+	collectionURI string,
+	subdir string,
+	makeExternalStorageFromURI cloud.ExternalStorageFromURIFactory,
+) (string, error) {
+	if strings.EqualFold(subdir, latestFileName) {
+		return readLatestFile(ctx, collectionURI, makeExternalStorageFromURI)
+	}
+	return subdir, nil
+}
+
+// writeLatestFile records subdir as the most recent full backup of the backup
+// collection at collectionURI.
+func writeLatestFile(
+	ctx context.Context,
+	collectionURI string,
+	subdir string,
+	makeExternalStorageFromURI cloud.ExternalStorageFromURIFactory,
+) error {
+	collection, err := makeExternalStorageFromURI(ctx, collectionURI)
+	if err != nil {
+		return err
+	}
+	defer collection.Close()
+	return collection.WriteFile(ctx, latestFileName, bytes.NewReader([]byte(subdir)))
+}
+
+// appendPaths appends tailDir to the path of each of the given URIs, leaving
+// their query parameters (e.g. the locality of a partitioned backup) as is.
+func appendPaths(uris []string, tailDir string) ([]string, error) {
+	res := make([]string, len(uris))
+	for i, uri := range uris {
+		parsed, err := url.Parse(uri)
+		if err != nil {
+			return nil, err
+		}
+		parsed.Path = path.Join(parsed.Path, tailDir)
+		res[i] = parsed.String()
+	}
+	return res, nil
+}
+
 // resolveBackupManifests resolves a list of list of URIs that point to the
 // incremental layers (each of which can be partitioned) of backups into the
 // actual backup manifests and metadata required to RESTORE. If only one layer
@@ -786,6 +786,29 @@ func loadBackupSQLDescs(
 	return backupManifests, latestBackupManifest, sqlDescs, nil
 }
 
+// plannedLiveSequences returns the IDs of the sequences which are not
+// restored but are still used by the restored tables, as resolved when
+// planning a RESTORE TABLE ... AS statement (see resolveLiveSequences): these
+// are the sequences used by the table descriptors of the job details which
+// are not the new IDs of restored descriptors.
+func plannedLiveSequences(details jobspb.RestoreDetails) map[sqlbase.ID]struct{} {
+	restoredIDs := make(map[sqlbase.ID]struct{}, len(details.TableRewrites))
+	for _, rewrite := range details.TableRewrites {
+		restoredIDs[rewrite.TableID] = struct{}{}
+	}
+	liveSequences := make(map[sqlbase.ID]struct{})
+	for _, table := range details.TableDescs {
+		for i := range table.Columns {
+			for _, seqID := range table.Columns[i].UsesSequenceIds {
+				if _, ok := restoredIDs[seqID]; !ok {
+					liveSequences[seqID] = struct{}{}
+				}
+			}
+		}
+	}
+	return liveSequences
+}
+
 type restoreResumer struct {
 	job                *jobs.Job
 	settings           *cluster.Settings
@@ -911,9 +934,21 @@ func createImportingTables(
 
 	// Assign new IDs and privileges to the tables, and update all references to
 	// use the new IDs.
-	if err := RewriteTableDescs(tables, details.TableRewrites, details.OverrideDB); err != nil {
+	if err := RewriteTableDescs(
+		tables, details.TableRewrites, details.OverrideDB, plannedLiveSequences(details),
+	); err != nil {
 		return nil, nil, nil, nil, err
 	}
+	// RESTORE TABLE ... AS gave the table its new name when it was planned.
+	plannedTables := make(map[sqlbase.ID]*sqlbase.TableDescriptor, len(details.TableDescs))
+	for _, table := range details.TableDescs {
+		plannedTables[table.ID] = table
+	}
+	for _, table := range tables {
+		if planned, ok := plannedTables[table.ID]; ok {
+			table.Name = planned.Name
+		}
+	}
 
 	for _, desc := range tables {
 		desc.Version++
@@ -922,6 +957,22 @@ func createImportingTables(
 	}
 
 	if !details.PrepareCompleted {
+		// The user-defined functions of the restored databases keep their IDs
+		// in a full cluster restore, like the tables. Otherwise they are given
+		// new IDs, since the IDs from the backup may be allocated again in this
+		// cluster.
+		if details.DescriptorCoverage != tree.AllDescriptors {
+			for _, db := range databases {
+				for i := range db.Functions {
+					fn := &db.Functions[i]
+					id, err := sql.GenerateUniqueDescID(ctx, p.ExecCfg().DB)
+					if err != nil {
+						return nil, nil, nil, nil, err
+					}
+					fn.ID = id
+				}
+			}
+		}
 		err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
 			// Write the new TableDescriptors which are set in the OFFLINE state.
 			if err := WriteTableDescs(ctx, txn, databases, tables, details.DescriptorCoverage, r.job.Payload().Username, r.settings, nil /* extra */); err != nil {
@@ -1090,6 +1141,9 @@ func (r *restoreResumer) publishTables(ctx context.Context) error {
 				existingDescVal,
 			)
 		}
+		if err := addLiveSequenceBackReferences(ctx, txn, b, r.tables); err != nil {
+			return err
+		}
 
 		if err := txn.Run(ctx, b); err != nil {
 			return errors.Wrap(err, "publishing tables")
@@ -1117,6 +1171,63 @@ func (r *restoreResumer) publishTables(ctx context.Context) error {
 	return nil
 }
 
+// addLiveSequenceBackReferences adds to the batch the references from the
+// sequences which are used by the restored tables but were not restored (see
+// resolveLiveSequences) to the columns using them, so that these sequences
+// cannot be dropped while the restored tables use them.
+func addLiveSequenceBackReferences(
+	ctx context.Context, txn *kv.Txn, b *kv.Batch, tables []*sqlbase.TableDescriptor,
+) error {
+	restoredIDs := make(map[sqlbase.ID]struct{}, len(tables))
+	for _, table := range tables {
+		restoredIDs[table.ID] = struct{}{}
+	}
+	seqDescs := make(map[sqlbase.ID]*sqlbase.MutableTableDescriptor)
+	for _, table := range tables {
+		for i := range table.Columns {
+			col := &table.Columns[i]
+			for _, seqID := range col.UsesSequenceIds {
+				if _, ok := restoredIDs[seqID]; ok {
+					continue
+				}
+				seqDesc, ok := seqDescs[seqID]
+				if !ok {
+					var err error
+					seqDesc, err = sqlbase.GetMutableTableDescFromID(ctx, txn, seqID)
+					if err != nil {
+						return errors.Wrapf(err,
+							"looking up sequence %d used by table %q", errors.Safe(seqID), table.Name)
+					}
+					if seqDesc.Dropped() {
+						return errors.Errorf(
+							"sequence %q used by table %q was dropped during the restore", seqDesc.Name, table.Name)
+					}
+					seqDescs[seqID] = seqDesc
+				}
+				refIdx := -1
+				for i, reference := range seqDesc.DependedOnBy {
+					if reference.ID == table.ID {
+						refIdx = i
+					}
+				}
+				if refIdx == -1 {
+					seqDesc.DependedOnBy = append(seqDesc.DependedOnBy, sqlbase.TableDescriptor_Reference{
+						ID:        table.ID,
+						ColumnIDs: []sqlbase.ColumnID{col.ID},
+					})
+				} else {
+					seqDesc.DependedOnBy[refIdx].ColumnIDs = append(seqDesc.DependedOnBy[refIdx].ColumnIDs, col.ID)
+				}
+			}
+		}
+	}
+	for _, seqDesc := range seqDescs {
+		seqDesc.Version++
+		b.Put(sqlbase.MakeDescMetadataKey(seqDesc.ID), sqlbase.WrapDescriptor(seqDesc.TableDesc()))
+	}
+	return nil
+}
+
 // OnFailOrCancel is part of the jobs.Resumer interface. Removes KV data that
 // has been committed from a restore that has failed or been canceled. It does
 // this by adding the table descriptors in DROP state, which causes the schema
@@ -45,6 +45,8 @@ const (
 	restoreOptSkipMissingFKs       = "skip_missing_foreign_keys"
 	restoreOptSkipMissingSequences = "skip_missing_sequences"
 	restoreOptSkipMissingViews     = "skip_missing_views"
+	restoreOptVerifyOnly           = "verify_only"
+	restoreOptVerifyRows           = "verify_rows"
 
 	// The temporary database system tables will be restored into for full
 	// cluster backups.
@@ -56,6 +58,8 @@ var restoreOptionExpectValues = map[string]sql.KVStringOptValidate{
 	restoreOptSkipMissingFKs:       sql.KVStringOptRequireNoValue,
 	restoreOptSkipMissingSequences: sql.KVStringOptRequireNoValue,
 	restoreOptSkipMissingViews:     sql.KVStringOptRequireNoValue,
+	restoreOptVerifyOnly:           sql.KVStringOptRequireNoValue,
+	restoreOptVerifyRows:           sql.KVStringOptRequireNoValue,
 	backupOptEncPassphrase:         sql.KVStringOptRequireValue,
 }
 
@@ -137,6 +141,7 @@ func allocateTableRewrites(
 	restoreDBs []*sqlbase.DatabaseDescriptor,
 	descriptorCoverage tree.DescriptorCoverage,
 	opts map[string]string,
+	liveSequences map[sqlbase.ID]struct{},
 ) (TableRewriteMap, error) {
 	tableRewrites := make(TableRewriteMap)
 	overrideDB, renaming := opts[restoreOptIntoDB]
@@ -177,6 +182,9 @@ func allocateTableRewrites(
 		for i := range table.Columns {
 			col := &table.Columns[i]
 			for _, seqID := range col.UsesSequenceIds {
+				if _, ok := liveSequences[seqID]; ok {
+					continue
+				}
 				if _, ok := tablesByID[seqID]; !ok {
 					if _, ok := opts[restoreOptSkipMissingSequences]; !ok {
 						return nil, errors.Errorf(
@@ -188,6 +196,15 @@ func allocateTableRewrites(
 			}
 		}
 	}
+	// User-defined functions are stored in their database descriptor but draw
+	// their IDs from the same generator as the descriptors.
+	for _, db := range restoreDBs {
+		for i := range db.Functions {
+			if uint32(db.Functions[i].ID) > maxDescIDInBackup {
+				maxDescIDInBackup = uint32(db.Functions[i].ID)
+			}
+		}
+	}
 
 	needsNewParentIDs := make(map[string][]sqlbase.ID)
 
@@ -421,11 +438,119 @@ func maybeUpgradeTableDescsInBackupManifests(
 	return nil
 }
 
+// renameRestoredTable gives the single table being restored by a RESTORE
+// TABLE ... AS statement its new name. If the new name is qualified with a
+// database, the table is restored into that database as if by the into_db
+// option, so the returned options should be used for the rest of the restore.
+func renameRestoredTable(
+	asTable *tree.UnresolvedObjectName,
+	tablesByID map[sqlbase.ID]*sqlbase.TableDescriptor,
+	opts map[string]string,
+) (map[string]string, error) {
+	if len(tablesByID) != 1 {
+		return nil, errors.Errorf(
+			"RESTORE ... AS can only be used to restore a single table, found %d", len(tablesByID))
+	}
+
+	var newDB string
+	switch asTable.NumParts {
+	case 3:
+		if asTable.Parts[1] != tree.PublicSchema {
+			return nil, pgerror.Newf(pgcode.InvalidSchemaName,
+				"cannot restore table %q into schema %q", asTable.Parts[0], asTable.Parts[1])
+		}
+		newDB = asTable.Parts[2]
+	case 2:
+		if asTable.Parts[1] != tree.PublicSchema {
+			newDB = asTable.Parts[1]
+		}
+	}
+	if newDB != "" {
+		if intoDB, ok := opts[restoreOptIntoDB]; ok && intoDB != newDB {
+			return nil, errors.Errorf(
+				"cannot use %q option to restore table %q into a different database", restoreOptIntoDB, asTable)
+		}
+		// Copy opts so that the caller's description of the statement is left
+		// untouched.
+		renamedOpts := make(map[string]string, len(opts)+1)
+		for k, v := range opts {
+			renamedOpts[k] = v
+		}
+		renamedOpts[restoreOptIntoDB] = newDB
+		opts = renamedOpts
+	}
+
+	for _, table := range tablesByID {
+		table.Name = asTable.Parts[0]
+	}
+	return opts, nil
+}
+
+// resolveLiveSequences returns the IDs of the sequences used by the table
+// being restored by a RESTORE TABLE ... AS statement which are not restored
+// along with it but still exist in the cluster. The restored table keeps
+// using these sequences, e.g. when it is recovered next to the table it was
+// backed up from. Since the default expressions refer to the sequences by
+// name, this only applies if the table is restored into the database it was
+// backed up from.
+func resolveLiveSequences(
+	ctx context.Context,
+	p sql.PlanHookState,
+	databasesByID map[sqlbase.ID]*sqlbase.DatabaseDescriptor,
+	tablesByID map[sqlbase.ID]*sqlbase.TableDescriptor,
+	opts map[string]string,
+) (map[sqlbase.ID]struct{}, error) {
+	liveSequences := make(map[sqlbase.ID]struct{})
+	if err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		for _, table := range tablesByID {
+			targetDB, ok := opts[restoreOptIntoDB]
+			if !ok {
+				database, ok := databasesByID[table.ParentID]
+				if !ok {
+					continue
+				}
+				targetDB = database.Name
+			}
+			found, targetDBID, err := sqlbase.LookupDatabaseID(ctx, txn, targetDB)
+			if err != nil {
+				return err
+			}
+			if !found || targetDBID != table.ParentID {
+				continue
+			}
+			for i := range table.Columns {
+				for _, seqID := range table.Columns[i].UsesSequenceIds {
+					if _, ok := tablesByID[seqID]; ok {
+						continue
+					}
+					seq, err := sqlbase.GetTableDescFromID(ctx, txn, seqID)
+					if errors.Is(err, sqlbase.ErrDescriptorNotFound) {
+						continue
+					} else if err != nil {
+						return err
+					}
+					if seq.IsSequence() && !seq.Dropped() && seq.ParentID == table.ParentID {
+						liveSequences[seqID] = struct{}{}
+					}
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return liveSequences, nil
+}
+
 // RewriteTableDescs mutates tables to match the ID and privilege specified
 // in tableRewrites, as well as adjusting cross-table references to use the
-// new IDs. overrideDB can be specified to set database names in views.
+// new IDs. overrideDB can be specified to set database names in views. The
+// references to the liveSequences, which are not restored, are kept as is.
 func RewriteTableDescs(
-	tables []*sqlbase.TableDescriptor, tableRewrites TableRewriteMap, overrideDB string,
+	tables []*sqlbase.TableDescriptor,
+	tableRewrites TableRewriteMap,
+	overrideDB string,
+	liveSequences map[sqlbase.ID]struct{},
 ) error {
 	for _, table := range tables {
 		tableRewrite, ok := tableRewrites[table.ID]
@@ -536,6 +661,8 @@ func RewriteTableDescs(
 			for _, seqID := range col.UsesSequenceIds {
 				if rewrite, ok := tableRewrites[seqID]; ok {
 					newSeqRefs = append(newSeqRefs, rewrite.TableID)
+				} else if _, ok := liveSequences[seqID]; ok {
+					newSeqRefs = append(newSeqRefs, seqID)
 				} else {
 					// The referenced sequence isn't being restored.
 					// Strip the DEFAULT expression and sequence references.
@@ -547,6 +674,25 @@ func RewriteTableDescs(
 				}
 			}
 			col.UsesSequenceIds = newSeqRefs
+
+			// A restored column only keeps ownership of the sequences restored
+			// alongside it, so that it can never take ownership of (and later drop)
+			// a sequence that is not part of this restore.
+			var newOwnedSeqs []sqlbase.ID
+			for _, seqID := range col.OwnsSequenceIds {
+				if rewrite, ok := tableRewrites[seqID]; ok {
+					newOwnedSeqs = append(newOwnedSeqs, rewrite.TableID)
+				}
+			}
+			col.OwnsSequenceIds = newOwnedSeqs
+		}
+		if table.IsSequence() &&
+			!table.SequenceOpts.SequenceOwner.Equal(sqlbase.TableDescriptor_SequenceOpts_SequenceOwner{}) {
+			if rewrite, ok := tableRewrites[table.SequenceOpts.SequenceOwner.OwnerTableID]; ok {
+				table.SequenceOpts.SequenceOwner.OwnerTableID = rewrite.TableID
+			} else {
+				table.SequenceOpts.SequenceOwner = sqlbase.TableDescriptor_SequenceOpts_SequenceOwner{}
+			}
 		}
 
 		// since this is a "new" table in eyes of new cluster, any leftover change
@@ -570,6 +716,7 @@ func restoreJobDescription(
 		AsOf:    restore.AsOf,
 		Options: optsToKVOptions(opts),
 		Targets: restore.Targets,
+		AsTable: restore.AsTable,
 		From:    make([]tree.PartitionedBackup, len(restore.From)),
 	}
 
@@ -616,6 +763,15 @@ func restorePlanHook(
 		fromFns[i] = fromFn
 	}
 
+	var subdirFn func() (string, error)
+	if restoreStmt.Subdir != nil {
+		var err error
+		subdirFn, err = p.TypeAsString(restoreStmt.Subdir, "RESTORE")
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
 	optsFn, err := p.TypeAsStringOpts(restoreStmt.Options, restoreOptionExpectValues)
 	if err != nil {
 		return nil, nil, nil, false, err
@@ -647,6 +803,32 @@ func restorePlanHook(
 				return err
 			}
 		}
+
+		// RESTORE FROM <subdir> IN <collection> restores the backup in the given
+		// subdirectory of the collection (or the LATEST one).
+		if subdirFn != nil {
+			subdir, err := subdirFn()
+			if err != nil {
+				return err
+			}
+			if len(from) != 1 {
+				return errors.Errorf("RESTORE FROM ... IN can only be used with a single backup collection")
+			}
+			defaultCollectionURI, _, err := getURIsByLocalityKV(from[0], "")
+			if err != nil {
+				return err
+			}
+			subdir, err = resolveCollectionSubdir(
+				ctx, defaultCollectionURI, subdir, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI,
+			)
+			if err != nil {
+				return err
+			}
+			if from[0], err = appendPaths(from[0], subdir); err != nil {
+				return err
+			}
+		}
+
 		var endTime hlc.Timestamp
 		if restoreStmt.AsOf.Expr != nil {
 			var err error
@@ -662,9 +844,62 @@ func restorePlanHook(
 		}
 		return doRestorePlan(ctx, restoreStmt, p, from, endTime, opts, resultsCh)
 	}
+
+	// RESTORE ... WITH verify_only reports the problems found in the backup
+	// instead of the result of a restore job.
+	for _, opt := range restoreStmt.Options {
+		if opt.Key == restoreOptVerifyOnly {
+			return fn, backupCheckHeader, nil, false, nil
+		}
+	}
 	return fn, RestoreHeader, nil, false, nil
 }
 
+// verifyRestore implements RESTORE ... WITH verify_only: it checks that the
+// requested targets could be restored from the resolved backup layers and
+// reports any problems found, without creating a job or writing anything into
+// the cluster.
+func verifyRestore(
+	ctx context.Context,
+	restoreStmt *tree.Restore,
+	p sql.PlanHookState,
+	defaultURIs []string,
+	mainBackupManifests []BackupManifest,
+	localityInfo []jobspb.RestoreDetails_BackupLocalityInfo,
+	endTime hlc.Timestamp,
+	encryption *roachpb.FileEncryptionOptions,
+	opts map[string]string,
+	resultsCh chan<- tree.Datums,
+) error {
+	if err := maybeUpgradeTableDescsInBackupManifests(
+		ctx, mainBackupManifests, true, /*skipFKsWithNoMatchingTable*/
+	); err != nil {
+		return err
+	}
+	sqlDescs, _, err := selectTargets(ctx, p, mainBackupManifests, restoreStmt.Targets, restoreStmt.DescriptorCoverage, endTime)
+	if err != nil {
+		return err
+	}
+
+	_, verifyRows := opts[restoreOptVerifyRows]
+	problems, err := verifyBackup(
+		ctx, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, defaultURIs, mainBackupManifests,
+		localityInfo, encryption, sqlDescs, verifyRows,
+	)
+	if err != nil {
+		return err
+	}
+	telemetry.Count("restore.verify_only")
+	for _, row := range problems {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case resultsCh <- row:
+		}
+	}
+	return nil
+}
+
 func doRestorePlan(
 	ctx context.Context,
 	restoreStmt *tree.Restore,
@@ -711,6 +946,14 @@ func doRestorePlan(
 		return errors.Errorf("full cluster RESTORE can only be used on full cluster BACKUP files")
 	}
 
+	_, verifyOnly := opts[restoreOptVerifyOnly]
+	if _, verifyRows := opts[restoreOptVerifyRows]; verifyRows && !verifyOnly {
+		return errors.Errorf("%s can only be used with %s", restoreOptVerifyRows, restoreOptVerifyOnly)
+	}
+	if verifyOnly {
+		return verifyRestore(ctx, restoreStmt, p, defaultURIs, mainBackupManifests, localityInfo, endTime, encryption, opts, resultsCh)
+	}
+
 	// Ensure that no user table descriptors exist for a full cluster restore.
 	txn := p.ExecCfg().DB.NewTxn(ctx, "count-user-descs")
 	descCount, err := sql.CountUserDescriptors(ctx, txn)
@@ -747,11 +990,21 @@ func doRestorePlan(
 	if err != nil {
 		return err
 	}
-	tableRewrites, err := allocateTableRewrites(ctx, p, databasesByID, filteredTablesByID, restoreDBs, restoreStmt.DescriptorCoverage, opts)
+	description, err := restoreJobDescription(p, restoreStmt, from, opts)
 	if err != nil {
 		return err
 	}
-	description, err := restoreJobDescription(p, restoreStmt, from, opts)
+	var liveSequences map[sqlbase.ID]struct{}
+	if restoreStmt.AsTable != nil {
+		if opts, err = renameRestoredTable(restoreStmt.AsTable, filteredTablesByID, opts); err != nil {
+			return err
+		}
+		liveSequences, err = resolveLiveSequences(ctx, p, databasesByID, filteredTablesByID, opts)
+		if err != nil {
+			return err
+		}
+	}
+	tableRewrites, err := allocateTableRewrites(ctx, p, databasesByID, filteredTablesByID, restoreDBs, restoreStmt.DescriptorCoverage, opts, liveSequences)
 	if err != nil {
 		return err
 	}
@@ -760,7 +1013,7 @@ func doRestorePlan(
 	for _, desc := range filteredTablesByID {
 		tables = append(tables, desc)
 	}
-	if err := RewriteTableDescs(tables, tableRewrites, opts[restoreOptIntoDB]); err != nil {
+	if err := RewriteTableDescs(tables, tableRewrites, opts[restoreOptIntoDB], liveSequences); err != nil {
 		return err
 	}
 
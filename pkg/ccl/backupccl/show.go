@@ -48,14 +48,26 @@ func showBackupPlanHook(
 		return nil, nil, nil, false, err
 	}
 
+	if backup.Path == nil && backup.InCollection != nil {
+		return showBackupsInCollectionPlanHook(ctx, backup, p)
+	}
+
 	toFn, err := p.TypeAsString(backup.Path, "SHOW BACKUP")
 	if err != nil {
 		return nil, nil, nil, false, err
 	}
+	var inColFn func() (string, error)
+	if backup.InCollection != nil {
+		inColFn, err = p.TypeAsString(backup.InCollection, "SHOW BACKUP")
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
 
 	expected := map[string]sql.KVStringOptValidate{
 		backupOptEncPassphrase:  sql.KVStringOptRequireValue,
 		backupOptWithPrivileges: sql.KVStringOptRequireNoValue,
+		restoreOptVerifyRows:    sql.KVStringOptRequireNoValue,
 	}
 	optsFn, err := p.TypeAsStringOpts(backup.Options, expected)
 	if err != nil {
@@ -65,9 +77,16 @@ func showBackupPlanHook(
 	if err != nil {
 		return nil, nil, nil, false, err
 	}
+	if _, ok := opts[restoreOptVerifyRows]; ok && backup.Details != tree.BackupCheckDetails {
+		return nil, nil, nil, false, errors.Errorf("%s can only be used with SHOW BACKUP CHECK", restoreOptVerifyRows)
+	}
 
 	var shower backupShower
 	switch backup.Details {
+	case tree.BackupCheckDetails:
+		// SHOW BACKUP CHECK does not show the manifests themselves; it is
+		// handled separately below.
+		shower = backupShower{header: backupCheckHeader}
 	case tree.BackupRangeDetails:
 		shower = backupShowerRanges
 	case tree.BackupFileDetails:
@@ -85,6 +104,23 @@ func showBackupPlanHook(
 		if err != nil {
 			return err
 		}
+		if inColFn != nil {
+			collection, err := inColFn()
+			if err != nil {
+				return err
+			}
+			subdir, err := resolveCollectionSubdir(
+				ctx, collection, str, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI,
+			)
+			if err != nil {
+				return err
+			}
+			uris, err := appendPaths([]string{collection}, subdir)
+			if err != nil {
+				return err
+			}
+			str = uris[0]
+		}
 
 		store, err := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, str)
 		if err != nil {
@@ -102,6 +138,22 @@ func showBackupPlanHook(
 			encryption = &roachpb.FileEncryptionOptions{Key: encryptionKey}
 		}
 
+		if backup.Details == tree.BackupCheckDetails {
+			_, verifyRows := opts[restoreOptVerifyRows]
+			problems, err := checkBackup(ctx, p, str, store, encryption, verifyRows)
+			if err != nil {
+				return err
+			}
+			for _, row := range problems {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case resultsCh <- row:
+				}
+			}
+			return nil
+		}
+
 		incPaths, err := findPriorBackups(ctx, store)
 		if err != nil {
 			if errors.Is(err, cloud.ErrListingUnsupported) {
@@ -153,6 +205,72 @@ func showBackupPlanHook(
 	return fn, shower.header, nil, false, nil
 }
 
+// checkBackup verifies the backup at uri, including any incremental layers
+// appended to it, for SHOW BACKUP CHECK.
+func checkBackup(
+	ctx context.Context,
+	p sql.PlanHookState,
+	uri string,
+	store cloud.ExternalStorage,
+	encryption *roachpb.FileEncryptionOptions,
+	verifyRows bool,
+) ([]tree.Datums, error) {
+	mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
+	defaultURIs, manifests, localityInfo, err := resolveBackupManifests(
+		ctx, []cloud.ExternalStorage{store}, mkStore, [][]string{{uri}}, hlc.Timestamp{}, encryption,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := maybeUpgradeTableDescsInBackupManifests(
+		ctx, manifests, true, /*skipFKsWithNoMatchingTable*/
+	); err != nil {
+		return nil, err
+	}
+	targets := manifests[len(manifests)-1].Descriptors
+	return verifyBackup(ctx, mkStore, defaultURIs, manifests, localityInfo, encryption, targets, verifyRows)
+}
+
+// showBackupsInCollectionPlanHook implements SHOW BACKUPS IN, which lists the
+// full backups written to a collection by BACKUP INTO.
+func showBackupsInCollectionPlanHook(
+	ctx context.Context, backup *tree.ShowBackup, p sql.PlanHookState,
+) (sql.PlanHookRowFn, sqlbase.ResultColumns, []sql.PlanNode, bool, error) {
+	collectionFn, err := p.TypeAsString(backup.InCollection, "SHOW BACKUPS")
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, backup.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		collection, err := collectionFn()
+		if err != nil {
+			return err
+		}
+		store, err := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, collection)
+		if err != nil {
+			return errors.Wrapf(err, "make storage")
+		}
+		defer store.Close()
+
+		subdirs, err := findBackupsInCollection(ctx, store)
+		if err != nil {
+			return err
+		}
+		for _, subdir := range subdirs {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case resultsCh <- tree.Datums{tree.NewDString(subdir)}:
+			}
+		}
+		return nil
+	}
+	return fn, sqlbase.ResultColumns{{Name: "path", Typ: types.String}}, nil, false, nil
+}
+
 type backupShower struct {
 	header sqlbase.ResultColumns
 	fn     func([]BackupManifest) []tree.Datums
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/bufalloc"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -55,11 +56,12 @@ type emitEntry struct {
 // The returned closure is not threadsafe.
 func kvsToRows(
 	leaseMgr *sql.LeaseManager,
+	evalCtx *tree.EvalContext,
 	details jobspb.ChangefeedDetails,
 	inputFn func(context.Context) (kvfeed.Event, error),
 ) func(context.Context) ([]emitEntry, error) {
 	_, withDiff := details.Opts[changefeedbase.OptDiff]
-	rfCache := newRowFetcherCache(leaseMgr)
+	rfCache := newRowFetcherCache(leaseMgr, evalCtx)
 
 	var kvs row.SpanKVFetcher
 	appendEmitEntryForKV := func(
@@ -111,6 +113,11 @@ func kvsToRows(
 			r.row.datums = append(sqlbase.EncDatumRow(nil), r.row.datums...)
 			r.row.deleted = rf.RowIsDeleted()
 			r.row.updated = schemaTimestamp
+			if !r.row.deleted {
+				if err := rfCache.ComputeVirtualColumns(desc, r.row.datums); err != nil {
+					return nil, err
+				}
+			}
 
 			// Assert that we don't get a second row from the row.Fetcher. We
 			// fed it a single KV, so that would be surprising.
@@ -126,12 +133,12 @@ func kvsToRows(
 
 		// Get prev value, if necessary.
 		if withDiff {
-			prevRF := rf
+			prevRF, prevDesc := rf, desc
 			if prevSchemaTimestamp != schemaTimestamp {
 				// If the previous value is being interpreted under a different
 				// version of the schema, fetch the correct table descriptor and
 				// create a new row.Fetcher with it.
-				prevDesc, err := rfCache.TableDescForKey(ctx, kv.Key, prevSchemaTimestamp)
+				prevDesc, err = rfCache.TableDescForKey(ctx, kv.Key, prevSchemaTimestamp)
 				if err != nil {
 					return nil, err
 				}
@@ -159,6 +166,11 @@ func kvsToRows(
 			}
 			r.row.prevDatums = append(sqlbase.EncDatumRow(nil), r.row.prevDatums...)
 			r.row.prevDeleted = prevRF.RowIsDeleted()
+			if !r.row.prevDeleted {
+				if err := rfCache.ComputeVirtualColumns(prevDesc, r.row.prevDatums); err != nil {
+					return nil, err
+				}
+			}
 
 			// Assert that we don't get a second row from the row.Fetcher. We
 			// fed it a single KV, so that would be surprising.
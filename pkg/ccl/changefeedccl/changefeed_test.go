@@ -1257,6 +1257,44 @@ func TestChangefeedComputedColumn(t *testing.T) {
 	t.Run(`enterprise`, enterpriseTest(testFn))
 }
 
+func TestChangefeedVirtualColumn(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		// Virtual columns are not stored, so the changefeed computes them.
+		sqlDB.Exec(t, `CREATE TABLE vc (
+		a INT PRIMARY KEY, b INT, c INT AS (a + b) VIRTUAL, INDEX (c)
+	)`)
+		sqlDB.Exec(t, `INSERT INTO vc VALUES (1, 10)`)
+
+		vc := feed(t, f, `CREATE CHANGEFEED FOR vc WITH diff`)
+		defer closeFeed(t, vc)
+
+		assertPayloads(t, vc, []string{
+			`vc: [1]->{"after": {"a": 1, "b": 10, "c": 11}, "before": null}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO vc VALUES (2, NULL)`)
+		assertPayloads(t, vc, []string{
+			`vc: [2]->{"after": {"a": 2, "b": null, "c": null}, "before": null}`,
+		})
+
+		sqlDB.Exec(t, `UPDATE vc SET b = 20 WHERE a = 1`)
+		assertPayloads(t, vc, []string{
+			`vc: [1]->{"after": {"a": 1, "b": 20, "c": 21}, "before": {"a": 1, "b": 10, "c": 11}}`,
+		})
+
+		sqlDB.Exec(t, `DELETE FROM vc WHERE a = 1`)
+		assertPayloads(t, vc, []string{
+			`vc: [1]->{"after": null, "before": {"a": 1, "b": 20, "c": 21}}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
 func TestChangefeedUpdatePrimaryKey(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -14,6 +14,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
@@ -24,18 +26,26 @@ import (
 // with an mvcc timestamp, it retrieves the correct TableDescriptor for that key
 // and returns a Fetcher initialized with that table. This Fetcher's
 // StartScanFrom can be used to turn that key (or all the keys making up the
-// column families of one row) into a row.
+// column families of one row) into a row. The virtual computed columns of the
+// row, which are not stored, are then filled in by ComputeVirtualColumns.
 type rowFetcherCache struct {
-	leaseMgr *sql.LeaseManager
-	fetchers map[*sqlbase.ImmutableTableDescriptor]*row.Fetcher
+	leaseMgr    *sql.LeaseManager
+	fetchers    map[*sqlbase.ImmutableTableDescriptor]*row.Fetcher
+	virtualCols map[*sqlbase.ImmutableTableDescriptor]*virtualColumns
+
+	evalCtx *tree.EvalContext
+	iv      sqlbase.RowIndexedVarContainer
+	datums  tree.Datums
 
 	a sqlbase.DatumAlloc
 }
 
-func newRowFetcherCache(leaseMgr *sql.LeaseManager) *rowFetcherCache {
+func newRowFetcherCache(leaseMgr *sql.LeaseManager, evalCtx *tree.EvalContext) *rowFetcherCache {
 	return &rowFetcherCache{
-		leaseMgr: leaseMgr,
-		fetchers: make(map[*sqlbase.ImmutableTableDescriptor]*row.Fetcher),
+		leaseMgr:    leaseMgr,
+		fetchers:    make(map[*sqlbase.ImmutableTableDescriptor]*row.Fetcher),
+		virtualCols: make(map[*sqlbase.ImmutableTableDescriptor]*virtualColumns),
+		evalCtx:     evalCtx,
 	}
 }
 
@@ -121,3 +131,95 @@ func (c *rowFetcherCache) RowFetcherForTableDesc(
 	c.fetchers[tableDesc] = &rf
 	return &rf, nil
 }
+
+// ComputeVirtualColumns sets the values of the virtual computed columns of a
+// row returned by the Fetcher for tableDesc from the values of its other
+// columns.
+func (c *rowFetcherCache) ComputeVirtualColumns(
+	tableDesc *sqlbase.ImmutableTableDescriptor, row sqlbase.EncDatumRow,
+) error {
+	vc, ok := c.virtualCols[tableDesc]
+	if !ok {
+		var err error
+		if vc, err = c.makeVirtualColumns(tableDesc); err != nil {
+			return err
+		}
+		c.virtualCols[tableDesc] = vc
+	}
+	if vc.exprs == nil {
+		return nil
+	}
+
+	c.datums = c.datums[:0]
+	for i := range row {
+		if err := row[i].EnsureDecoded(&tableDesc.Columns[i].Type, &c.a); err != nil {
+			return err
+		}
+		c.datums = append(c.datums, row[i].Datum)
+	}
+	c.iv.Cols = tableDesc.Columns
+	c.iv.Mapping = vc.colIdxMap
+	c.iv.CurSourceRow = c.datums
+	c.evalCtx.PushIVarContainer(&c.iv)
+	defer c.evalCtx.PopIVarContainer()
+	for i, expr := range vc.exprs {
+		if expr == nil {
+			continue
+		}
+		d, err := expr.Eval(c.evalCtx)
+		if err != nil {
+			return err
+		}
+		row[i] = sqlbase.DatumToEncDatum(&tableDesc.Columns[i].Type, d)
+	}
+	return nil
+}
+
+// virtualColumns contains what is needed to compute the virtual columns of a
+// table.
+type virtualColumns struct {
+	// exprs contains the computed expressions of the virtual columns, indexed
+	// like the columns of the table. It is nil if the table has no virtual
+	// columns.
+	exprs     []tree.TypedExpr
+	colIdxMap map[sqlbase.ColumnID]int
+}
+
+// makeVirtualColumns creates the expressions used to compute the virtual
+// columns of the table.
+func (c *rowFetcherCache) makeVirtualColumns(
+	tableDesc *sqlbase.ImmutableTableDescriptor,
+) (*virtualColumns, error) {
+	vc := &virtualColumns{}
+	hasVirtual := false
+	for i := range tableDesc.Columns {
+		if tableDesc.Columns[i].Virtual {
+			hasVirtual = true
+			break
+		}
+	}
+	if !hasVirtual {
+		return vc, nil
+	}
+
+	var txCtx transform.ExprTransformContext
+	computedExprs, err := sqlbase.MakeComputedExprs(
+		tableDesc.Columns,
+		tableDesc,
+		tree.NewUnqualifiedTableName(tree.Name(tableDesc.Name)),
+		&txCtx,
+		c.evalCtx,
+		false, /* addingCols */
+	)
+	if err != nil {
+		return nil, err
+	}
+	vc.exprs = make([]tree.TypedExpr, len(tableDesc.Columns))
+	for i := range tableDesc.Columns {
+		if tableDesc.Columns[i].Virtual {
+			vc.exprs[i] = computedExprs[i]
+		}
+	}
+	vc.colIdxMap = tableDesc.ColumnIdxMap()
+	return vc, nil
+}
@@ -191,6 +191,48 @@ func importJobDescription(
 	return tree.AsStringWithFQNames(&stmt, ann), nil
 }
 
+// checkNoExclusionConstraints returns an error if the given table has an
+// exclusion constraint. IMPORT writes the rows directly rather than planning
+// mutations, so the exclusion constraints of the table would not be checked.
+func checkNoExclusionConstraints(desc *sqlbase.TableDescriptor) error {
+	for _, c := range desc.AllActiveAndInactiveExclusions() {
+		if c.Validity == sqlbase.ConstraintValidity_Dropping {
+			continue
+		}
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot IMPORT into table %q because it has exclusion constraint %q",
+			desc.Name, c.Name)
+	}
+	return nil
+}
+
+// checkNoDeferrableUniqueConstraints returns an error if the given table has
+// a DEFERRABLE UNIQUE constraint. Such a constraint is not backed by a unique
+// index, so IMPORT would not detect the duplicate keys.
+func checkNoDeferrableUniqueConstraints(desc *sqlbase.TableDescriptor) error {
+	for _, idx := range desc.AllNonDropIndexes() {
+		if idx.UniqueDeferrable {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot IMPORT into table %q because it has deferrable unique constraint %q",
+				desc.Name, idx.Name)
+		}
+	}
+	return nil
+}
+
+// checkNoInsertTriggers returns an error if the given table has a trigger
+// fired by INSERT. IMPORT writes the rows directly rather than planning
+// mutations, so the triggers of the table would not fire.
+func checkNoInsertTriggers(desc *sqlbase.TableDescriptor) error {
+	for i := range desc.Triggers {
+		if t := &desc.Triggers[i]; t.HasEvent(sqlbase.TriggerDescriptor_INSERT) {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot IMPORT into table %q because it has trigger %q", desc.Name, t.Name)
+		}
+	}
+	return nil
+}
+
 // importPlanHook implements sql.PlanHookFn.
 func importPlanHook(
 	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
@@ -685,6 +727,18 @@ func importPlanHook(
 			}
 		}
 
+		for i := range tableDetails {
+			if err := checkNoExclusionConstraints(tableDetails[i].Desc); err != nil {
+				return err
+			}
+			if err := checkNoDeferrableUniqueConstraints(tableDetails[i].Desc); err != nil {
+				return err
+			}
+			if err := checkNoInsertTriggers(tableDetails[i].Desc); err != nil {
+				return err
+			}
+		}
+
 		telemetry.CountBucketed("import.files", int64(len(files)))
 
 		// Here we create the job and protected timestamp records in a side
@@ -876,7 +930,7 @@ func prepareNewTableDescsForIngestion(
 		seqVals[id] = tableDesc.SeqVal
 	}
 
-	if err := backupccl.RewriteTableDescs(tableDescs, tableRewrites, ""); err != nil {
+	if err := backupccl.RewriteTableDescs(tableDescs, tableRewrites, "", nil /* liveSequences */); err != nil {
 		return nil, err
 	}
 
@@ -2294,6 +2294,50 @@ func TestImportIntoCSV(t *testing.T) {
 			fmt.Sprintf(`IMPORT INTO child (parent_id, child_id) CSV DATA (%s)`, testFiles.files[0]))
 	})
 
+	// IMPORT does not check exclusion constraints, so it rejects the tables that
+	// have them.
+	t.Run("import-rejects-exclusion-constraints", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE excl (a INT PRIMARY KEY, b INT, CONSTRAINT excl_b EXCLUDE (b WITH =))`)
+		defer sqlDB.Exec(t, `DROP TABLE excl`)
+
+		sqlDB.ExpectErr(
+			t, `cannot IMPORT into table "excl" because it has exclusion constraint "excl_b"`,
+			fmt.Sprintf(`IMPORT INTO excl (a, b) CSV DATA (%s)`, testFiles.files[0]))
+
+		sqlDB.ExpectErr(
+			t, `cannot IMPORT into table "excl2" because it has exclusion constraint "excl2_b"`,
+			fmt.Sprintf(`IMPORT TABLE excl2 (a INT PRIMARY KEY, b INT, CONSTRAINT excl2_b EXCLUDE (b WITH =)) CSV DATA (%s)`, testFiles.files[0]))
+	})
+
+	// IMPORT does not detect the duplicate keys of DEFERRABLE UNIQUE
+	// constraints, which are not backed by unique indexes.
+	t.Run("import-rejects-deferrable-unique-constraints", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE uniq (a INT PRIMARY KEY, b INT, CONSTRAINT uniq_b UNIQUE (b) DEFERRABLE)`)
+		defer sqlDB.Exec(t, `DROP TABLE uniq`)
+
+		sqlDB.ExpectErr(
+			t, `cannot IMPORT into table "uniq" because it has deferrable unique constraint "uniq_b"`,
+			fmt.Sprintf(`IMPORT INTO uniq (a, b) CSV DATA (%s)`, testFiles.files[0]))
+
+		sqlDB.ExpectErr(
+			t, `cannot IMPORT into table "uniq2" because it has deferrable unique constraint "uniq2_b_key"`,
+			fmt.Sprintf(`IMPORT TABLE uniq2 (a INT PRIMARY KEY, b INT UNIQUE DEFERRABLE) CSV DATA (%s)`, testFiles.files[0]))
+	})
+
+	// IMPORT does not fire row-level triggers, so it rejects the tables that
+	// have triggers fired by INSERT.
+	t.Run("import-rejects-insert-triggers", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE trig (a INT PRIMARY KEY, b INT)`)
+		defer sqlDB.Exec(t, `DROP TABLE trig`)
+		sqlDB.Exec(t, `CREATE TRIGGER trig_upd BEFORE UPDATE ON trig FOR EACH ROW SET b = 0`)
+		sqlDB.Exec(t, `CREATE TRIGGER trig_ins BEFORE INSERT OR UPDATE ON trig FOR EACH ROW SET b = 0`)
+
+		sqlDB.ExpectErr(
+			t, `cannot IMPORT into table "trig" because it has trigger "trig_ins"`,
+			fmt.Sprintf(`IMPORT INTO trig (a, b) CSV DATA (%s)`, testFiles.files[0]))
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM trig`, [][]string{{"0"}})
+	})
+
 	// This tests that consecutive imports from unique data sources into an
 	// existing table without an explicit PK, do not overwrite each other. It
 	// exercises the row_id generation in IMPORT.
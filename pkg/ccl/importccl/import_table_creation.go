@@ -19,6 +19,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -122,6 +124,12 @@ func MakeSimpleTableDescriptor(
 				return nil, err
 			}
 
+		case *tree.ExcludeConstraintTableDef:
+			// IMPORT writes the rows directly, so it would not check the constraint.
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot IMPORT into table %q because it has exclusion constraint %q",
+				create.Table.Table(), def.Name)
+
 		case *tree.ForeignKeyConstraintTableDef:
 			if !fks.allowed {
 				return nil, unimplemented.NewWithIssueDetailf(42846, "import.fk",
@@ -898,6 +898,15 @@ If specified, print the system config contents. Beware that the output will be
 long and not particularly human-readable.`,
 	}
 
+	ZipRedactLogs = FlagInfo{
+		Name: "redact-logs",
+		Description: `
+Redact the sensitive information of the log files included in the zip file.
+In the log entries produced with --redactable-logs, only the parts between
+redaction markers are removed; the messages of the other log entries are
+removed entirely.`,
+	}
+
 	Decommission = FlagInfo{
 		Name:        "decommission",
 		Description: `Deprecated: use 'node decommission' instead.`,
@@ -1065,6 +1074,30 @@ If non-empty, write log files in this directory. If empty, write log files to
 `,
 	}
 
+	LogConfigFile = FlagInfo{
+		Name: "log-config-file",
+		Description: `
+File containing the logging configuration, in the YAML format. The
+configuration maps the logging channels (e.g. OPS, HEALTH, SQL_SCHEMA,
+SESSIONS, SENSITIVE_ACCESS) to file, stderr and network sinks, and selects the
+format of the entries (crdb-v1 or json). For example:
+<PRE>
+
+  format: json
+  sinks:
+  - type: file
+    channels: [OPS, HEALTH]
+  - type: network
+    channels: all
+    threshold: WARNING
+    address: 127.0.0.1:5170
+
+</PRE>
+The channels which are not part of any file sink are written to the main log
+files.
+`,
+	}
+
 	LogDirMaxSize = FlagInfo{
 		Name: "log-dir-max-size",
 		Description: `
@@ -1086,6 +1119,16 @@ Minimum verbosity of messages written to the log file.
 `,
 	}
 
+	RedactableLogs = FlagInfo{
+		Name: "redactable-logs",
+		Description: `
+Enclose the sensitive information of the log messages, e.g. the keys and SQL
+values, between redaction markers, so that it can be stripped from the log
+files afterwards (see debug zip --redact-logs and debug merge-logs --redact).
+Enabled by default; use --redactable-logs=false to disable.
+`,
+	}
+
 	WriteSize = FlagInfo{
 		Name: "write-size",
 		Description: `
@@ -102,6 +102,7 @@ func initCLIDefaults() {
 	debugCtx.inputFile = ""
 	debugCtx.printSystemConfig = false
 	debugCtx.maxResults = 1000
+	debugCtx.redactLogs = false
 	debugCtx.ballastSize = base.SizeSpec{InBytes: 1000000000}
 
 	serverCfg.GoroutineDumpDirName = ""
@@ -127,6 +128,7 @@ func initCLIDefaults() {
 	startCtx.listeningURLFile = ""
 	startCtx.pidFile = ""
 	startCtx.inBackground = false
+	startCtx.logConfigFile = ""
 
 	quitCtx.serverDecommission = false
 	quitCtx.drainWait = 10 * time.Minute
@@ -289,6 +291,7 @@ var debugCtx struct {
 	ballastSize       base.SizeSpec
 	printSystemConfig bool
 	maxResults        int64
+	redactLogs        bool
 }
 
 // startCtx captures the command-line arguments for the `start` command.
@@ -325,6 +328,9 @@ var startCtx struct {
 
 	// logging settings specific to file logging.
 	logDir log.DirName
+
+	// logConfigFile is the file containing the logging configuration.
+	logConfigFile string
 }
 
 // quitCtx captures the command-line parameters of the `quit` and
@@ -1183,6 +1183,7 @@ var debugMergeLogsOpts = struct {
 	program *regexp.Regexp
 	file    *regexp.Regexp
 	prefix  string
+	redact  bool
 }{
 	program: regexp.MustCompile("^cockroach.*$"),
 	file:    regexp.MustCompile(log.FilePattern),
@@ -1195,7 +1196,7 @@ func runDebugMergeLogs(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	return writeLogStream(s, cmd.OutOrStdout(), o.filter, o.prefix)
+	return writeLogStream(s, cmd.OutOrStdout(), o.filter, o.prefix, o.redact)
 }
 
 // DebugCmdsForRocksDB lists debug commands that access rocksdb through the engine
@@ -1279,4 +1280,6 @@ func init() {
 			"if no such group exists, program-filter is ignored")
 	f.StringVar(&debugMergeLogsOpts.prefix, "prefix", "${host}> ",
 		"expansion template (see regexp.Expand) used as prefix to merged log messages evaluated on file-pattern")
+	f.BoolVar(&debugMergeLogsOpts.redact, "redact", false,
+		"redact the sensitive information of the log messages; see --redactable-logs")
 }
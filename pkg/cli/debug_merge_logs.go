@@ -36,7 +36,9 @@ type logStream interface {
 
 // writeLogStream pops messages off of s and writes them to out prepending
 // prefix per message and filtering messages which match filter.
-func writeLogStream(s logStream, out io.Writer, filter *regexp.Regexp, prefix string) error {
+func writeLogStream(
+	s logStream, out io.Writer, filter *regexp.Regexp, prefix string, redact bool,
+) error {
 	const chanSize = 1 << 16        // 64k
 	const maxWriteBufSize = 1 << 18 // 256kB
 
@@ -61,6 +63,9 @@ func writeLogStream(s logStream, out io.Writer, filter *regexp.Regexp, prefix st
 		if _, err = w.Write(prefixBytes); err != nil {
 			return err
 		}
+		if redact {
+			return log.RedactEntry(ei.Entry).Format(w)
+		}
 		return ei.Format(w)
 	}
 
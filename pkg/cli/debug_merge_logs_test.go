@@ -122,6 +122,16 @@ var cases = []testCase{
 		name:  "4.filter-npe-origin-stack-only",
 		args:  []string{"testdata/merge_logs/4/npe-repanic.log"}, // (?:panic\(.*)*
 		flags: []string{"--file-pattern", ".*", "--filter", `(?m)^(panic\(.*\n.*\n.*\n.*\n[^p].*)`},
+	},
+	{
+		name:  "5.no-redact",
+		args:  []string{"testdata/merge_logs/5/*"},
+		flags: []string{"--file-pattern", ".*", "--prefix", ""},
+	},
+	{
+		name:  "5.redact",
+		args:  []string{"testdata/merge_logs/5/*"},
+		flags: []string{"--file-pattern", ".*", "--prefix", "", "--redact"},
 	}}
 
 func (c testCase) run(t *testing.T) {
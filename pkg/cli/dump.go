@@ -73,6 +73,15 @@ func runDump(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// User-defined functions are only dumped along with the whole database.
+	var fnStmts []string
+	if tableNames == nil && dumpCtx.dumpMode != dumpDataOnly {
+		fnStmts, err = getFunctionStatements(conn, dbName, ts)
+		if err != nil {
+			return err
+		}
+	}
+
 	byID := make(map[int64]basicMetadata)
 	for _, md := range mds {
 		byID[md.ID] = md
@@ -106,10 +115,17 @@ func runDump(cmd *cobra.Command, args []string) error {
 	w := os.Stdout
 
 	if dumpCtx.dumpMode != dumpDataOnly {
-		for i, md := range mds {
+		// Functions are created first, as views may call them.
+		for i, stmt := range fnStmts {
 			if i > 0 {
 				fmt.Fprintln(w)
 			}
+			fmt.Fprintf(w, "%s;\n", stmt)
+		}
+		for i, md := range mds {
+			if i > 0 || len(fnStmts) > 0 {
+				fmt.Fprintln(w)
+			}
 			if err := dumpCreateTable(w, md); err != nil {
 				return err
 			}
@@ -126,12 +142,19 @@ func runDump(cmd *cobra.Command, args []string) error {
 				if err := dumpSequenceData(w, conn, ts, md); err != nil {
 					return err
 				}
-			case "view":
+			case "view", "materialized view":
 				continue
 			default:
 				panic("unknown descriptor type: " + md.kind)
 			}
 		}
+		// Materialized views are computed from the dumped data rather than
+		// dumped themselves, so they are refreshed once all data is loaded.
+		for _, md := range mds {
+			if md.kind == "materialized view" {
+				fmt.Fprintf(w, "\nREFRESH MATERIALIZED VIEW %s;\n", md.name)
+			}
+		}
 	}
 	// Put FK ALTERs at the end.
 	if dumpCtx.dumpMode != dumpDataOnly {
@@ -182,7 +205,7 @@ type basicMetadata struct {
 	name       *tree.TableName
 	createStmt string
 	dependsOn  []int64
-	kind       string // "string", "table", or "view"
+	kind       string // "string", "table", "view" or "materialized view"
 	alter      []string
 	validate   []string
 }
@@ -268,6 +291,41 @@ func getTableNames(conn *sqlConn, dbName string, ts string) (tableNames []string
 	return tableNames, nil
 }
 
+// getFunctionStatements retrieves the CREATE statements of the user-defined
+// functions in the given database, ordered by name.
+func getFunctionStatements(conn *sqlConn, dbName string, ts string) (stmts []string, err error) {
+	rows, err := conn.Query(fmt.Sprintf(`
+		SELECT create_statement
+		FROM %s.crdb_internal.create_function_statements
+		AS OF SYSTEM TIME %s
+		WHERE database_name = $1
+		ORDER BY function_name, function_id
+		`, tree.NameString(dbName), lex.EscapeSQLString(ts)), []driver.Value{dbName})
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]driver.Value, 1)
+	for {
+		if err := rows.Next(vals); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		stmt, ok := vals[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected value: %T", vals[0])
+		}
+		stmts = append(stmts, stmt)
+	}
+
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	return stmts, nil
+}
+
 func getBasicMetadata(conn *sqlConn, dbName, tableName string, ts string) (basicMetadata, error) {
 	name := tree.NewTableName(tree.Name(dbName), tree.Name(tableName))
 
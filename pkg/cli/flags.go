@@ -285,9 +285,10 @@ func init() {
 		case logflags.LogDirName,
 			logflags.LogFileMaxSizeName,
 			logflags.LogFilesCombinedMaxSizeName,
-			logflags.LogFileVerbosityThresholdName:
-			// The --log-dir* and --log-file* flags are specified only for the
-			// `start` and `demo` commands.
+			logflags.LogFileVerbosityThresholdName,
+			logflags.RedactableLogsName:
+			// The --log-dir*, --log-file* and --redactable-logs flags are
+			// specified only for the `start` and `demo` commands.
 			return
 		}
 		pf.AddFlag(flag)
@@ -424,6 +425,7 @@ func init() {
 	for _, cmd := range logCmds {
 		f := cmd.Flags()
 		VarFlag(f, &startCtx.logDir, cliflags.LogDir)
+		StringFlag(f, &startCtx.logConfigFile, cliflags.LogConfigFile, startCtx.logConfigFile)
 		VarFlag(f,
 			pflag.PFlagFromGoFlag(flag.Lookup(logflags.LogFilesCombinedMaxSizeName)).Value,
 			cliflags.LogDirMaxSize)
@@ -433,6 +435,13 @@ func init() {
 		VarFlag(f,
 			pflag.PFlagFromGoFlag(flag.Lookup(logflags.LogFileVerbosityThresholdName)).Value,
 			cliflags.LogFileVerbosity)
+		VarFlag(f,
+			pflag.PFlagFromGoFlag(flag.Lookup(logflags.RedactableLogsName)).Value,
+			cliflags.RedactableLogs)
+		// The servers produce redactable logs by default (see
+		// setupAndInitializeLoggingAndProfiling).
+		f.Lookup(cliflags.RedactableLogs.Name).DefValue = "true"
+		f.Lookup(cliflags.RedactableLogs.Name).NoOptDefVal = "true"
 	}
 
 	for _, cmd := range certCmds {
@@ -689,6 +698,10 @@ func init() {
 		f := debugBallastCmd.Flags()
 		VarFlag(f, &debugCtx.ballastSize, cliflags.Size)
 	}
+	{
+		f := debugZipCmd.Flags()
+		BoolFlag(f, &debugCtx.redactLogs, cliflags.ZipRedactLogs, debugCtx.redactLogs)
+	}
 }
 
 // processEnvVarDefaults injects the current value of flag-related
@@ -16,9 +16,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -36,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	readline "github.com/knz/go-libedit"
 	isatty "github.com/mattn/go-isatty"
@@ -64,6 +67,15 @@ Type:
   \dt               show the tables of the current schema in the current database.
   \du               list the users for all databases.
   \d [TABLE]        show details about columns in the specified table, or alias for '\dt' if no table is specified.
+  \dn               list the schemas in the current database.
+  \df [PATTERN]     list the built-in and user-defined functions, optionally only those matching the pattern.
+  \di [TABLE]       list the indexes of the specified table, or of all tables in the current database.
+  \copy ...         copy data between a table or query and a client-side file, e.g.
+                    \copy t FROM 'file.csv' WITH CSV HEADER, or \copy (SELECT ...) TO 'file.txt'.
+  \i FILE           execute the statements and commands in the file.
+  \o [FILE]         send the query results to the file, or (without argument) back to standard output.
+  \watch [SECONDS]  repeatedly run the current or last statement (every 2 seconds by default), until Ctrl+C.
+  \timing [on|off]  toggle or set the display of the execution times.
 %s
 More documentation about our SQL dialect and the CLI shell is available online:
 %s
@@ -170,6 +182,14 @@ type cliState struct {
 	// autoTrace, when non-empty, encloses the executed statements
 	// by suitable SET TRACING and SHOW TRACE FOR SESSION statements.
 	autoTrace string
+
+	// queryOutputFile, when non-nil, receives the query results instead
+	// of stdout. It is set by \o.
+	queryOutputFile *os.File
+
+	// lastStatement is the last statement sent to the server, which is
+	// run again by \watch.
+	lastStatement string
 }
 
 // cliStateEnum drives the CLI state machine in runInteractive().
@@ -640,6 +660,160 @@ func (c *cliState) pipeSyscmd(line string, nextState, errState cliStateEnum) cli
 	return nextState
 }
 
+// queryOutput returns the writer that receives the query results.
+func (c *cliState) queryOutput() io.Writer {
+	if c.queryOutputFile != nil {
+		return c.queryOutputFile
+	}
+	return os.Stdout
+}
+
+// handleQueryOutput supports the \o client-side command.
+func (c *cliState) handleQueryOutput(cmd []string, nextState, errState cliStateEnum) cliStateEnum {
+	if len(cmd) > 1 {
+		return c.invalidSyntax(errState, `%s. Try \? for help.`, c.lastInputLine)
+	}
+	if c.queryOutputFile != nil {
+		if err := c.queryOutputFile.Close(); err != nil {
+			fmt.Fprintf(stderr, "error closing %s: %v\n", c.queryOutputFile.Name(), err)
+		}
+		c.queryOutputFile = nil
+	}
+	if len(cmd) == 0 {
+		return nextState
+	}
+	f, err := os.OpenFile(cmd[0], os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		c.exitErr = err
+		return errState
+	}
+	c.queryOutputFile = f
+	return nextState
+}
+
+// handleInclude supports the \i client-side command. The lines of the
+// file are processed as if they had been entered by the user.
+func (c *cliState) handleInclude(cmd []string, nextState, errState cliStateEnum) cliStateEnum {
+	if len(cmd) != 1 {
+		return c.invalidSyntax(errState, `%s. Try \? for help.`, c.lastInputLine)
+	}
+	contents, err := ioutil.ReadFile(cmd[0])
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		c.exitErr = err
+		return errState
+	}
+	lines := strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n")
+	c.forwardLines = append(lines, c.forwardLines...)
+	return nextState
+}
+
+// handleTiming supports the \timing client-side command.
+func (c *cliState) handleTiming(cmd []string, nextState, errState cliStateEnum) cliStateEnum {
+	switch len(cmd) {
+	case 0:
+		sqlCtx.showTimes = !sqlCtx.showTimes
+	case 1:
+		switch strings.ToLower(cmd[0]) {
+		case "true", "1", "on":
+			sqlCtx.showTimes = true
+		case "false", "0", "off":
+			sqlCtx.showTimes = false
+		default:
+			return c.invalidSyntax(errState, `%s. Try \? for help.`, c.lastInputLine)
+		}
+	default:
+		return c.invalidSyntax(errState, `%s. Try \? for help.`, c.lastInputLine)
+	}
+	if sqlCtx.showTimes {
+		fmt.Println("Timing is on.")
+	} else {
+		fmt.Println("Timing is off.")
+	}
+	return nextState
+}
+
+// defaultWatchInterval is the interval between the executions of \watch
+// when none is specified.
+const defaultWatchInterval = 2 * time.Second
+
+// handleWatch supports the \watch client-side command. It runs the
+// statement entered so far, or otherwise the last statement, repeatedly
+// until interrupted with Ctrl+C or until it fails.
+func (c *cliState) handleWatch(
+	cmd []string, nextState, startState, errState cliStateEnum,
+) cliStateEnum {
+	interval := defaultWatchInterval
+	switch len(cmd) {
+	case 0:
+	case 1:
+		secs, err := strconv.ParseFloat(cmd[0], 64)
+		if err != nil || secs <= 0 {
+			return c.invalidSyntax(errState, `\watch: invalid interval %q. Try \? for help.`, cmd[0])
+		}
+		interval = time.Duration(secs * float64(time.Second))
+	default:
+		return c.invalidSyntax(errState, `%s. Try \? for help.`, c.lastInputLine)
+	}
+
+	query := c.lastStatement
+	if len(c.partialLines) > 0 {
+		// The statement entered so far is consumed by \watch.
+		query = strings.Trim(strings.Join(c.partialLines, "\n"), " \r\n\t\f;")
+		nextState = startState
+	}
+	if query == "" {
+		fmt.Fprintln(stderr, `\watch cannot be used with an empty query`)
+		c.exitErr = errInvalidSyntax
+		return errState
+	}
+	c.lastStatement = query
+
+	// Ctrl+C stops the loop, instead of the shell.
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	for {
+		w := c.queryOutput()
+		fmt.Fprintf(w, "%s (every %s)\n\n", timeutil.Now().Format(time.RFC1123), interval)
+		c.lastKnownTxnStatus = unknownTxnStatus
+		if err := runQueryAndFormatResults(c.conn, w, makeQuery(query)); err != nil {
+			cliOutputError(stderr, err, true /*showSeverity*/, false /*verbose*/)
+			c.exitErr = err
+			return errState
+		}
+		select {
+		case <-interrupt:
+			return nextState
+		case <-time.After(interval):
+		}
+	}
+}
+
+// describeFunctionsSource lists the built-in functions and the user-defined
+// functions of the current database. The signature of the latter is extracted
+// from their CREATE statement.
+const describeFunctionsSource = `(SELECT function, signature, category FROM crdb_internal.builtin_functions ` +
+	`UNION ALL SELECT function_name, ` +
+	`regexp_extract(create_statement, '^CREATE FUNCTION [^(]*(\(.*?\) RETURNS (?:SETOF )?\S+)'), ` +
+	`'User-defined' FROM crdb_internal.create_function_statements)`
+
+// makeDescribeFunctionsQuery returns the query run by \df. The
+// pattern, if any, uses the * and ? wildcards like psql.
+func makeDescribeFunctionsQuery(pattern string) string {
+	var buf bytes.Buffer
+	buf.WriteString(`SELECT function, signature, category FROM ` + describeFunctionsSource + ` AS f`)
+	if pattern != "" {
+		pattern = strings.NewReplacer("*", "%", "?", "_").Replace(pattern)
+		buf.WriteString(` WHERE function LIKE `)
+		lex.EncodeSQLString(&buf, pattern)
+	}
+	buf.WriteString(` ORDER BY function, signature`)
+	return buf.String()
+}
+
 // rePromptFmt recognizes every substitution pattern in the prompt format string.
 var rePromptFmt = regexp.MustCompile("(%.)")
 
@@ -1076,6 +1250,51 @@ func (c *cliState) doHandleCliCmd(loopState, nextState cliStateEnum) cliStateEnu
 		}
 		return c.invalidSyntax(errState, `%s. Try \? for help.`, c.lastInputLine)
 
+	case `\dn`:
+		c.concatLines = `SHOW SCHEMAS`
+		return cliRunStatement
+
+	case `\df`:
+		if len(cmd) > 2 {
+			return c.invalidSyntax(errState, `%s. Try \? for help.`, c.lastInputLine)
+		}
+		c.concatLines = makeDescribeFunctionsQuery(strings.Join(cmd[1:], ""))
+		return cliRunStatement
+
+	case `\di`:
+		if len(cmd) == 1 {
+			dbName := c.refreshDatabaseName()
+			if dbName == unknownDbName || dbName == "" {
+				fmt.Fprintln(stderr, "cannot determine the current database. Use \\di TABLE instead.")
+				c.exitErr = errInvalidSyntax
+				return errState
+			}
+			var buf bytes.Buffer
+			buf.WriteString(`SHOW INDEXES FROM DATABASE `)
+			lex.EncodeRestrictedSQLIdent(&buf, dbName, lex.EncNoFlags)
+			c.concatLines = buf.String()
+			return cliRunStatement
+		} else if len(cmd) == 2 {
+			c.concatLines = `SHOW INDEXES FROM ` + cmd[1]
+			return cliRunStatement
+		}
+		return c.invalidSyntax(errState, `%s. Try \? for help.`, c.lastInputLine)
+
+	case `\copy`:
+		return c.handleCopy(line, loopState, errState)
+
+	case `\i`:
+		return c.handleInclude(cmd[1:], loopState, errState)
+
+	case `\o`:
+		return c.handleQueryOutput(cmd[1:], loopState, errState)
+
+	case `\watch`:
+		return c.handleWatch(cmd[1:], loopState, cliStartLine, errState)
+
+	case `\timing`:
+		return c.handleTiming(cmd[1:], loopState, errState)
+
 	case `\demo`:
 		return c.handleDemo(cmd[1:], loopState, errState)
 
@@ -1217,7 +1436,8 @@ func (c *cliState) doRunStatement(nextState cliStateEnum) cliStateEnum {
 	}
 
 	// Now run the statement/query.
-	c.exitErr = runQueryAndFormatResults(c.conn, os.Stdout, makeQuery(c.concatLines))
+	c.lastStatement = c.concatLines
+	c.exitErr = runQueryAndFormatResults(c.conn, c.queryOutput(), makeQuery(c.concatLines))
 	if c.exitErr != nil {
 		cliOutputError(stderr, c.exitErr, true /*showSeverity*/, false /*verbose*/)
 	}
@@ -1244,7 +1464,7 @@ func (c *cliState) doRunStatement(nextState cliStateEnum) cliStateEnum {
 			if strings.Contains(c.autoTrace, "kv") {
 				traceType = "kv"
 			}
-			if err := runQueryAndFormatResults(c.conn, os.Stdout,
+			if err := runQueryAndFormatResults(c.conn, c.queryOutput(),
 				makeQuery(fmt.Sprintf("SHOW %s TRACE FOR SESSION", traceType))); err != nil {
 				cliOutputError(stderr, err, true /*showSeverity*/, false /*verbose*/)
 				if c.exitErr == nil {
@@ -1283,6 +1503,11 @@ func (c *cliState) doDecidePath() cliStateEnum {
 // a prompt to the user for each statement.
 func runInteractive(conn *sqlConn) (exitErr error) {
 	c := cliState{conn: conn}
+	defer func() {
+		if c.queryOutputFile != nil {
+			_ = c.queryOutputFile.Close()
+		}
+	}()
 
 	state := cliStart
 	for {
@@ -0,0 +1,491 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"bufio"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/errors"
+)
+
+// copyCmd is a parsed \copy client-side command:
+//
+//   \copy TABLE [(COLUMNS)] FROM 'FILE' [WITH] [CSV] [HEADER]
+//   \copy {TABLE [(COLUMNS)] | (QUERY)} TO 'FILE' [WITH] [CSV] [HEADER]
+//
+// Unlike the COPY statement, \copy reads and writes files local to the
+// client. The options can also be given as (FORMAT csv, HEADER).
+type copyCmd struct {
+	// target is the table, with its optional column list, or the
+	// parenthesized query.
+	target string
+	from   bool
+	file   string
+	csv    bool
+	header bool
+}
+
+// reCopyDirection splits a \copy command whose target is a table at the
+// FROM or TO keyword.
+var reCopyDirection = regexp.MustCompile(`(?is)^(.*?)\s+(from|to)\s+(.*)$`)
+
+// parseCopyCmd parses the arguments of a \copy command.
+func parseCopyCmd(args string) (copyCmd, error) {
+	var cmd copyCmd
+	args = strings.TrimSpace(args)
+	var direction, rest string
+	if strings.HasPrefix(args, "(") {
+		// A query: find the matching closing parenthesis, skipping over
+		// string literals.
+		depth, inString, end := 0, false, -1
+		for i := 0; i < len(args) && end < 0; i++ {
+			switch ch := args[i]; {
+			case ch == '\'':
+				inString = !inString
+			case inString:
+			case ch == '(':
+				depth++
+			case ch == ')':
+				depth--
+				if depth == 0 {
+					end = i
+				}
+			}
+		}
+		if end < 0 {
+			return cmd, errors.New("unterminated query")
+		}
+		cmd.target = args[:end+1]
+		fields := strings.SplitN(strings.TrimSpace(args[end+1:]), " ", 2)
+		direction = fields[0]
+		if len(fields) == 2 {
+			rest = fields[1]
+		}
+	} else {
+		m := reCopyDirection.FindStringSubmatch(args)
+		if m == nil {
+			return cmd, errors.New("missing FROM or TO")
+		}
+		cmd.target, direction, rest = m[1], m[2], m[3]
+	}
+	switch strings.ToLower(direction) {
+	case "from":
+		cmd.from = true
+		if strings.HasPrefix(cmd.target, "(") {
+			return cmd, errors.New("cannot copy from a file into a query")
+		}
+	case "to":
+	default:
+		return cmd, errors.New("missing FROM or TO")
+	}
+
+	// The file name is either a SQL string literal or a single word.
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "'") {
+		var b strings.Builder
+		i := 1
+		for ; i < len(rest); i++ {
+			if rest[i] == '\'' {
+				if i+1 < len(rest) && rest[i+1] == '\'' {
+					i++
+				} else {
+					break
+				}
+			}
+			b.WriteByte(rest[i])
+		}
+		if i >= len(rest) {
+			return cmd, errors.New("unterminated file name")
+		}
+		cmd.file, rest = b.String(), rest[i+1:]
+	} else {
+		fields := strings.SplitN(rest, " ", 2)
+		cmd.file, rest = fields[0], ""
+		if len(fields) == 2 {
+			rest = fields[1]
+		}
+	}
+	if cmd.file == "" {
+		return cmd, errors.New("missing file name")
+	}
+	if f := strings.ToLower(cmd.file); f == "stdin" || f == "stdout" {
+		return cmd, errors.Newf("%s is not supported, use a file", cmd.file)
+	}
+
+	// The options, in either the old or the parenthesized syntax.
+	opts := strings.Fields(strings.NewReplacer("(", " ", ")", " ", ",", " ").Replace(rest))
+	for i := 0; i < len(opts); i++ {
+		switch strings.ToLower(opts[i]) {
+		case "with":
+		case "csv":
+			cmd.csv = true
+		case "header":
+			cmd.header = true
+			if i+1 < len(opts) && strings.EqualFold(opts[i+1], "true") {
+				i++
+			}
+		case "format":
+			if i+1 == len(opts) {
+				return cmd, errors.New("missing format")
+			}
+			i++
+			switch strings.ToLower(opts[i]) {
+			case "csv":
+				cmd.csv = true
+			case "text":
+				cmd.csv = false
+			default:
+				return cmd, errors.Newf("unsupported format: %s", opts[i])
+			}
+		default:
+			return cmd, errors.Newf("unsupported option: %s", opts[i])
+		}
+	}
+	if cmd.header && !cmd.csv {
+		return cmd, errors.New("HEADER is only supported with CSV")
+	}
+	return cmd, nil
+}
+
+// handleCopy supports the \copy client-side command.
+func (c *cliState) handleCopy(line string, nextState, errState cliStateEnum) cliStateEnum {
+	cmd, err := parseCopyCmd(strings.TrimPrefix(line, `\copy`))
+	if err != nil {
+		return c.invalidSyntax(errState, `\copy: %v. Try \? for help.`, err)
+	}
+
+	var n int64
+	if cmd.from {
+		n, err = c.copyFromFile(cmd)
+	} else {
+		n, err = c.copyToFile(cmd)
+	}
+	if err != nil {
+		cliOutputError(stderr, err, true /*showSeverity*/, false /*verbose*/)
+		c.exitErr = err
+		return errState
+	}
+	fmt.Fprintf(c.queryOutput(), "COPY %d\n", n)
+	return nextState
+}
+
+// copyFromFile sends the rows of the file to the server using the COPY
+// protocol, and returns the number of rows copied.
+//
+// In the text format, the fields are separated by tabs, `\N` denotes NULL
+// and backslash escapes are recognized. In the CSV format, as in psql, the
+// unquoted empty fields denote NULL and the quoted ones ("") the empty
+// string.
+func (c *cliState) copyFromFile(cmd copyCmd) (int64, error) {
+	f, err := os.Open(cmd.file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var nextRow func() ([]driver.Value, error)
+	if cmd.csv {
+		r := bufio.NewReader(f)
+		if cmd.header {
+			if _, err := readCopyCSVRow(r); err != nil && err != io.EOF {
+				return 0, err
+			}
+		}
+		nextRow = func() ([]driver.Value, error) {
+			return readCopyCSVRow(r)
+		}
+	} else {
+		r := bufio.NewScanner(f)
+		r.Buffer(nil, 64<<20 /* max row size */)
+		nextRow = func() ([]driver.Value, error) {
+			if !r.Scan() {
+				if err := r.Err(); err != nil {
+					return nil, err
+				}
+				return nil, io.EOF
+			}
+			line := strings.TrimSuffix(r.Text(), "\r")
+			if line == `\.` {
+				// The end-of-data marker.
+				return nil, io.EOF
+			}
+			fields := strings.Split(line, "\t")
+			row := make([]driver.Value, len(fields))
+			for i, field := range fields {
+				if field != `\N` {
+					row[i] = decodeCopyTextField(field)
+				}
+			}
+			return row, nil
+		}
+	}
+
+	if err := c.conn.ensureConn(); err != nil {
+		return 0, err
+	}
+	defer c.conn.flushNotices()
+	// lib/pq implements the client side of the COPY protocol through
+	// prepared statements. Its COPY statement only implements the deprecated
+	// driver.Stmt.Exec and not driver.StmtExecContext, hence the lint
+	// suppressions below.
+	stmt, err := c.conn.conn.Prepare("COPY " + cmd.target + " FROM STDIN")
+	if err != nil {
+		return 0, c.conn.closeIfBadConn(err)
+	}
+	defer func() { _ = stmt.Close() }()
+	for {
+		row, err := nextRow()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, err
+		}
+		//lint:ignore SA1019 the COPY statement has no ExecContext
+		if _, err := stmt.Exec(row); err != nil {
+			return 0, c.conn.closeIfBadConn(err)
+		}
+	}
+	// An Exec without values ends the copy.
+	//lint:ignore SA1019 the COPY statement has no ExecContext
+	res, err := stmt.Exec(nil)
+	if err != nil {
+		return 0, c.conn.closeIfBadConn(err)
+	}
+	return res.RowsAffected()
+}
+
+// copyToFile writes the rows of the table or query to the file, and returns
+// the number of rows copied. The server does not support COPY ... TO STDOUT,
+// so the rows are retrieved with a regular query.
+func (c *cliState) copyToFile(cmd copyCmd) (int64, error) {
+	query := cmd.target
+	if strings.HasPrefix(query, "(") {
+		query = query[1 : len(query)-1]
+	} else {
+		table, cols := query, "*"
+		if i := strings.Index(query, "("); i >= 0 {
+			table, cols = query[:i], strings.TrimSuffix(strings.TrimSpace(query[i+1:]), ")")
+		}
+		query = "SELECT " + cols + " FROM " + table
+	}
+
+	rows, err := c.conn.Query(query, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	f, err := os.Create(cmd.file)
+	if err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriter(f)
+	sep := "\t"
+	if cmd.csv {
+		sep = ","
+		if cmd.header {
+			cols := make([]string, len(rows.Columns()))
+			for i, col := range rows.Columns() {
+				cols[i] = encodeCopyCSVField(col)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(cols, sep)); err != nil {
+				_ = f.Close()
+				return 0, err
+			}
+		}
+	}
+
+	var n int64
+	vals := make([]driver.Value, len(rows.Columns()))
+	record := make([]string, len(vals))
+	typNames := make([]string, len(vals))
+	for i := range typNames {
+		typNames[i] = rows.ColumnTypeDatabaseTypeName(i)
+	}
+	for {
+		if err := rows.Next(vals); err == io.EOF {
+			break
+		} else if err != nil {
+			_ = f.Close()
+			return 0, err
+		}
+		for i, v := range vals {
+			record[i] = encodeCopyField(v, typNames[i], cmd.csv)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(record, sep)); err != nil {
+			_ = f.Close()
+			return 0, err
+		}
+		n++
+	}
+	err = w.Flush()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return n, err
+}
+
+// closeIfBadConn closes the connection, to be reopened by the next
+// statement, if the error indicates that it cannot be used any more.
+func (c *sqlConn) closeIfBadConn(err error) error {
+	if err == driver.ErrBadConn {
+		c.reconnecting = true
+		c.Close()
+	}
+	return err
+}
+
+// decodeCopyTextField decodes the backslash escapes of a field in the COPY
+// text format.
+func decodeCopyTextField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// readCopyCSVRow reads a row of the COPY CSV format. An unquoted empty field
+// is read as NULL.
+func readCopyCSVRow(r *bufio.Reader) ([]driver.Value, error) {
+	var row []driver.Value
+	var field strings.Builder
+	quoted, inQuotes, empty := false, false, true
+	endField := func() {
+		if field.Len() == 0 && !quoted {
+			row = append(row, nil)
+		} else {
+			row = append(row, field.String())
+		}
+		field.Reset()
+		quoted = false
+	}
+	for {
+		c, err := r.ReadByte()
+		if err == io.EOF {
+			if inQuotes {
+				return nil, errors.New("unterminated CSV quoted field")
+			}
+			if empty {
+				return nil, io.EOF
+			}
+			endField()
+			return row, nil
+		} else if err != nil {
+			return nil, err
+		}
+		empty = false
+		switch {
+		case inQuotes && c == '"':
+			if next, err := r.Peek(1); err == nil && next[0] == '"' {
+				// A doubled quote is a literal quote.
+				_, _ = r.ReadByte()
+				field.WriteByte('"')
+			} else {
+				inQuotes = false
+			}
+		case inQuotes:
+			field.WriteByte(c)
+		case c == '"':
+			inQuotes, quoted = true, true
+		case c == ',':
+			endField()
+		case c == '\n':
+			endField()
+			return row, nil
+		case c == '\r':
+			if next, err := r.Peek(1); err == nil && next[0] == '\n' {
+				// Windows line ending.
+				continue
+			}
+			field.WriteByte(c)
+		default:
+			field.WriteByte(c)
+		}
+	}
+}
+
+// encodeCopyCSVField quotes a field of the COPY CSV format if needed. The
+// empty string is always quoted, to tell it apart from NULL.
+func encodeCopyCSVField(s string) string {
+	if s != "" && s != `\.` && !strings.ContainsAny(s, ",\"\r\n") {
+		return s
+	}
+	return `"` + strings.Replace(s, `"`, `""`, -1) + `"`
+}
+
+var copyTextEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// encodeCopyField formats a value of the given column type as a field of the
+// COPY text or CSV format.
+func encodeCopyField(val driver.Value, typName string, isCSV bool) string {
+	var s string
+	switch t := val.(type) {
+	case nil:
+		if isCSV {
+			return ""
+		}
+		return `\N`
+	case string:
+		s = t
+	case []byte:
+		// The driver only decodes the values of BYTES columns; the values of the
+		// types it does not know (e.g. DECIMAL, JSONB or arrays) are returned as
+		// their text representation, which can be copied as-is.
+		if typName == "BYTEA" {
+			s = lex.EncodeByteArrayToRawBytes(string(t),
+				sessiondata.BytesEncodeHex, false /* skipHexPrefix */)
+		} else {
+			s = string(t)
+		}
+	case time.Time:
+		s = t.Format(tree.TimestampOutputFormat)
+	default:
+		s = fmt.Sprint(val)
+	}
+	if isCSV {
+		return encodeCopyCSVField(s)
+	}
+	return copyTextEscaper.Replace(s)
+}
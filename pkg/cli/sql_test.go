@@ -11,15 +11,24 @@
 package cli
 
 import (
+	"bufio"
+	"database/sql/driver"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Example_sql_lex tests the usage of the lexer in the sql subcommand.
@@ -201,6 +210,10 @@ func TestHandleCliCmdSqlAlias(t *testing.T) {
 		{`\du`, `SHOW USERS`},
 		{`\d mytable`, `SHOW COLUMNS FROM mytable`},
 		{`\d`, `SHOW TABLES`},
+		{`\dn`, `SHOW SCHEMAS`},
+		{`\di mytable`, `SHOW INDEXES FROM mytable`},
+		{`\df`, `SELECT function, signature, category FROM ` + describeFunctionsSource + ` AS f ORDER BY function, signature`},
+		{`\df json*`, `SELECT function, signature, category FROM ` + describeFunctionsSource + ` AS f WHERE function LIKE 'json%' ORDER BY function, signature`},
 	}
 
 	var c cliState
@@ -217,7 +230,11 @@ func TestHandleCliCmdSqlAlias(t *testing.T) {
 func TestHandleCliCmdSlashDInvalidSyntax(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
-	clientSideCommandTests := []string{`\d goodarg badarg`, `\dz`}
+	clientSideCommandTests := []string{
+		`\d goodarg badarg`, `\dz`, `\di goodarg badarg`, `\df a b`,
+		`\copy t`, `\copy t FROM`, `\copy t FROM STDIN`, `\copy t TO 'f' WITH HEADER`,
+		`\watch 0`, `\timing maybe`, `\i`,
+	}
 
 	var c cliState
 	for _, tt := range clientSideCommandTests {
@@ -230,6 +247,186 @@ func TestHandleCliCmdSlashDInvalidSyntax(t *testing.T) {
 	}
 }
 
+func TestParseCopyCmd(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testData := []struct {
+		args     string
+		expected copyCmd
+		err      string
+	}{
+		{`t FROM 'f.txt'`, copyCmd{target: `t`, from: true, file: `f.txt`}, ``},
+		{`t (a, b) from f.csv csv header`,
+			copyCmd{target: `t (a, b)`, from: true, file: `f.csv`, csv: true, header: true}, ``},
+		{`t TO 'it''s.csv' WITH (FORMAT csv, HEADER true)`,
+			copyCmd{target: `t`, file: `it's.csv`, csv: true, header: true}, ``},
+		{`(SELECT ')' FROM t) TO 'f.txt'`, copyCmd{target: `(SELECT ')' FROM t)`, file: `f.txt`}, ``},
+		{`(SELECT 1) FROM 'f.txt'`, copyCmd{}, `cannot copy from a file into a query`},
+		{`(SELECT 1 TO 'f.txt'`, copyCmd{}, `unterminated query`},
+		{`t`, copyCmd{}, `missing FROM or TO`},
+		{`t FROM 'f.txt`, copyCmd{}, `unterminated file name`},
+		{`t FROM stdin`, copyCmd{}, `stdin is not supported, use a file`},
+		{`t FROM 'f' WITH (FORMAT binary)`, copyCmd{}, `unsupported format: binary`},
+		{`t FROM 'f' DELIMITER ','`, copyCmd{}, `unsupported option: DELIMITER`},
+		{`t FROM 'f' HEADER`, copyCmd{}, `HEADER is only supported with CSV`},
+	}
+	for _, tc := range testData {
+		t.Run(tc.args, func(t *testing.T) {
+			cmd, err := parseCopyCmd(tc.args)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, cmd)
+		})
+	}
+}
+
+func TestCopyTextFields(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, s := range []string{``, `abc`, "a\tb\nc\\d", `\N`} {
+		assert.Equal(t, s, decodeCopyTextField(encodeCopyField(s, "TEXT", false /* isCSV */)))
+	}
+	assert.Equal(t, `\N`, encodeCopyField(nil, "TEXT", false /* isCSV */))
+	assert.Equal(t, ``, encodeCopyField(nil, "TEXT", true /* isCSV */))
+	assert.Equal(t, `""`, encodeCopyField("", "TEXT", true /* isCSV */))
+	assert.Equal(t, `\\x6162`, encodeCopyField([]byte("ab"), "BYTEA", false /* isCSV */))
+	assert.Equal(t, `\x6162`, encodeCopyField([]byte("ab"), "BYTEA", true /* isCSV */))
+	assert.Equal(t, `"{""a"": 1}"`, encodeCopyField([]byte(`{"a": 1}`), "JSONB", true /* isCSV */))
+}
+
+func TestReadCopyCSVRow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	r := bufio.NewReader(strings.NewReader("a,,\"\",\"b,\"\"c\"\"\nd\r\n\"e\nf\"\n"))
+	for _, expected := range [][]driver.Value{
+		{"a", nil, "", `b,"c"`},
+		{"d"},
+		{"e\nf"},
+	} {
+		row, err := readCopyCSVRow(r)
+		require.NoError(t, err)
+		assert.Equal(t, expected, row)
+	}
+	_, err := readCopyCSVRow(r)
+	assert.Equal(t, io.EOF, err)
+
+	_, err = readCopyCSVRow(bufio.NewReader(strings.NewReader(`"a`)))
+	assert.EqualError(t, err, "unterminated CSV quoted field")
+	assert.Equal(t, `1.50`, encodeCopyField([]byte(`1.50`), "NUMERIC", false /* isCSV */))
+}
+
+// TestCopyRoundTrip checks that the rows copied to a file by \copy TO are
+// copied back identically by \copy FROM, for values of various types.
+func TestCopyRoundTrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	c := newCLITest(cliTestParams{t: t})
+	defer c.cleanup()
+
+	pgURL, cleanup := sqlutils.PGUrl(t, c.ServingSQLAddr(), t.Name(), url.User(security.RootUser))
+	defer cleanup()
+	conn := makeSQLConn(pgURL.String())
+	defer conn.Close()
+
+	if err := conn.Exec(`
+CREATE DATABASE t;
+CREATE TABLE t.src (
+  id INT PRIMARY KEY, s STRING, b BYTES, d DECIMAL, j JSONB, a INT[], u UUID, ts TIMESTAMP
+);
+CREATE TABLE t.dst (LIKE t.src);
+INSERT INTO t.src VALUES
+  (1, e'a\tb\\c', b'\x00\x01ab', 1.50, '{"a": [1, "x\ty"]}', ARRAY[1, NULL],
+   '63616665-6630-3064-6465-616462656566', '2020-01-02 03:04:05.123456'),
+  (2, NULL, NULL, NULL, NULL, NULL, NULL, NULL),
+  (3, '', b'', NULL, NULL, NULL, NULL, NULL)`, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, dirCleanup := testutils.TempDir(t)
+	defer dirCleanup()
+
+	cs := cliState{conn: conn}
+	for _, csv := range []bool{false, true} {
+		t.Run(fmt.Sprintf("csv=%t", csv), func(t *testing.T) {
+			if err := conn.Exec(`TRUNCATE t.dst`, nil); err != nil {
+				t.Fatal(err)
+			}
+			file := filepath.Join(dir, fmt.Sprintf("copy-%t", csv))
+			if n, err := cs.copyToFile(copyCmd{target: `t.src`, file: file, csv: csv}); err != nil {
+				t.Fatal(err)
+			} else if n != 3 {
+				t.Fatalf("expected 3 rows to be copied to the file, got %d", n)
+			}
+			if n, err := cs.copyFromFile(copyCmd{target: `t.dst`, from: true, file: file, csv: csv}); err != nil {
+				t.Fatal(err)
+			} else if n != 3 {
+				t.Fatalf("expected 3 rows to be copied from the file, got %d", n)
+			}
+
+			const q = `SELECT string_agg(%[1]s::STRING, ';' ORDER BY id) FROM t.%[1]s`
+			src, err := conn.QueryRow(fmt.Sprintf(q, "src"), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dst, err := conn.QueryRow(fmt.Sprintf(q, "dst"), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, src, dst)
+
+			// NULLs and empty strings are told apart.
+			nulls, err := conn.QueryRow(
+				`SELECT array_agg(id ORDER BY id)::STRING FROM t.dst WHERE s IS NULL OR b IS NULL`, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, []driver.Value{"{2}"}, nulls)
+			empty, err := conn.QueryRow(
+				`SELECT array_agg(id ORDER BY id)::STRING FROM t.dst WHERE s = '' AND b = b''`, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, []driver.Value{"{3}"}, empty)
+		})
+	}
+}
+
+// TestDescribeFunctions checks that \df lists the user-defined functions
+// along with the built-in ones.
+func TestDescribeFunctions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	c := newCLITest(cliTestParams{t: t})
+	defer c.cleanup()
+
+	pgURL, cleanup := sqlutils.PGUrl(t, c.ServingSQLAddr(), t.Name(), url.User(security.RootUser))
+	defer cleanup()
+	conn := makeSQLConn(pgURL.String())
+	defer conn.Close()
+
+	if err := conn.Exec(`
+CREATE DATABASE t;
+SET DATABASE = t;
+CREATE FUNCTION my_add_one(x INT) RETURNS INT LANGUAGE SQL IMMUTABLE AS 'SELECT x + 1';
+CREATE FUNCTION my_range(lo INT) RETURNS SETOF INT LANGUAGE SQL STABLE AS 'SELECT generate_series(lo, lo + 1)'`,
+		nil); err != nil {
+		t.Fatal(err)
+	}
+
+	_, rows, err := runQuery(conn, makeQuery(makeDescribeFunctionsQuery("my_*")), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{
+		{`my_add_one`, `(x INT8) RETURNS INT8`, `User-defined`},
+		{`my_range`, `(lo INT8) RETURNS SETOF INT8`, `User-defined`},
+	}
+	assert.Equal(t, expected, rows)
+}
+
 func setupTestCliState() cliState {
 	c := cliState{}
 	c.ins = noLineEditor
@@ -345,6 +345,34 @@ func (c *sqlConn) getServerValue(what, sql string) (driver.Value, bool) {
 	return dbVals[0], true
 }
 
+// getLastQueryServiceLatency retrieves the time the server spent
+// processing the last statement, from receiving it to the end of its
+// execution. If the server does not report it, `false` is returned in
+// the second result.
+func (c *sqlConn) getLastQueryServiceLatency() (time.Duration, bool) {
+	// The query is not echoed: it is an implementation detail of the
+	// timing display.
+	rows, err := c.conn.Query(
+		`SELECT extract(epoch FROM service_latency) FROM crdb_internal.session_last_query_statistics`, nil)
+	if err != nil {
+		if err == driver.ErrBadConn {
+			c.reconnecting = true
+			c.Close()
+		}
+		return 0, false
+	}
+	defer func() { _ = rows.Close() }()
+	var vals [1]driver.Value
+	if err := rows.Next(vals[:]); err != nil {
+		return 0, false
+	}
+	secs, ok := vals[0].(float64)
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(secs * float64(time.Second)), true
+}
+
 // sqlTxnShim implements the crdb.Tx interface.
 //
 // It exists to support crdb.ExecuteInTxn. Normally, we'd hand crdb.ExecuteInTxn
@@ -457,6 +485,7 @@ func (c *sqlConn) Close() {
 
 type sqlRowsI interface {
 	driver.RowsColumnTypeScanType
+	driver.RowsColumnTypeDatabaseTypeName
 	Result() driver.Result
 	Tag() string
 
@@ -526,6 +555,10 @@ func (r *sqlRows) ColumnTypeScanType(index int) reflect.Type {
 	return r.rows.ColumnTypeScanType(index)
 }
 
+func (r *sqlRows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.rows.ColumnTypeDatabaseTypeName(index)
+}
+
 func makeSQLConn(url string) *sqlConn {
 	return &sqlConn{
 		url: url,
@@ -702,6 +735,7 @@ func runQueryAndFormatResults(conn *sqlConn, w io.Writer, fn queryFunc) error {
 	defer func() {
 		_ = rows.Close()
 	}()
+	firstResultSet := true
 	for {
 		// lib/pq is not able to tell us before the first call to Next()
 		// whether a statement returns either
@@ -785,13 +819,39 @@ func runQueryAndFormatResults(conn *sqlConn, w io.Writer, fn queryFunc) error {
 			return err
 		}
 
+		more, err := rows.NextResultSet()
+		if err != nil {
+			return err
+		}
+
 		if sqlCtx.showTimes {
 			// Present the time since the last result, or since the
 			// beginning of execution. Currently the execution engine makes
 			// all the work upfront so most of the time is accounted for by
 			// the 1st result; this is subject to change once CockroachDB
 			// evolves to stream results as statements are executed.
-			fmt.Fprintf(w, "\nTime: %s\n", queryCompleteTime.Sub(startTime))
+			clientTime := queryCompleteTime.Sub(startTime)
+			// For a single statement, the server can also tell us how
+			// much of that time was spent executing it; the rest is
+			// accounted for by the network and the client.
+			var serverTime time.Duration
+			var haveServerTime bool
+			if firstResultSet && !more {
+				haveServerTime = rows.Close() == nil
+				if haveServerTime {
+					serverTime, haveServerTime = conn.getLastQueryServiceLatency()
+				}
+			}
+			if haveServerTime {
+				networkTime := clientTime - serverTime
+				if networkTime < 0 {
+					networkTime = 0
+				}
+				fmt.Fprintf(w, "\nTime: %s total (execution %s / network %s)\n",
+					clientTime, serverTime, networkTime)
+			} else {
+				fmt.Fprintf(w, "\nTime: %s\n", clientTime)
+			}
 			// Make users better understand any discrepancy they observe.
 			renderDelay := timeutil.Now().Sub(queryCompleteTime)
 			if renderDelay >= 1*time.Second {
@@ -805,11 +865,10 @@ func runQueryAndFormatResults(conn *sqlConn, w io.Writer, fn queryFunc) error {
 			startTime = timeutil.Now()
 		}
 
-		if more, err := rows.NextResultSet(); err != nil {
-			return err
-		} else if !more {
+		if !more {
 			return nil
 		}
+		firstResultSet = false
 	}
 }
 
@@ -659,7 +659,7 @@ If problems persist, please see ` + base.DocsURL("cluster-setup-troubleshooting.
 		} else {
 			// Don't shout to stderr since the server will have detached by
 			// the time this function gets called.
-			log.Warningf(ctx, msg)
+			log.Warningf(ctx, "%s", log.Safe(msg))
 		}
 	}
 
@@ -1139,6 +1139,15 @@ func logOutputDirectory() string {
 func setupAndInitializeLoggingAndProfiling(
 	ctx context.Context, cmd *cobra.Command,
 ) (stopper *stop.Stopper, err error) {
+	// Unless the user disabled them explicitly, the servers produce
+	// redactable logs, so that the sensitive information can be stripped
+	// from the log files without losing the rest of the log messages.
+	if rf := cmd.Flags().Lookup(logflags.RedactableLogsName); rf != nil && !rf.Changed {
+		if err := rf.Value.Set("true"); err != nil {
+			return nil, err
+		}
+	}
+
 	// Default the log directory to the "logs" subdirectory of the first
 	// non-memory store. If more than one non-memory stores is detected,
 	// print a warning.
@@ -1208,6 +1217,29 @@ func setupAndInitializeLoggingAndProfiling(
 		}()
 	}
 
+	if startCtx.logConfigFile != "" {
+		contents, err := ioutil.ReadFile(startCtx.logConfigFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read the logging configuration")
+		}
+		logConfig, err := log.ParseConfig(string(contents))
+		if err != nil {
+			return nil, err
+		}
+		cleanup, err := log.ApplyConfig(ctx, logConfig)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if stopper != nil {
+				stopper.AddCloser(stop.CloserFn(cleanup))
+			} else {
+				cleanup()
+			}
+		}()
+		log.Infof(ctx, "logging configuration loaded from %s", startCtx.logConfigFile)
+	}
+
 	// We want to be careful to still produce useful debug dumps if the
 	// server configuration has disabled logging to files.
 	outputDirectory := "."
@@ -1254,7 +1286,7 @@ func setupAndInitializeLoggingAndProfiling(
 	// We log build information to stdout (for the short summary), but also
 	// to stderr to coincide with the full logs.
 	info := build.GetInfo()
-	log.Infof(ctx, info.Short())
+	log.Infof(ctx, "%s", log.Safe(info.Short()))
 
 	initMemProfile(ctx, outputDirectory)
 	initCPUProfile(ctx, outputDirectory)
@@ -55,6 +55,7 @@ requires the cluster to be live.
 
 // Tables containing cluster-wide info that are collected in a debug zip.
 var debugZipTablesPerCluster = []string{
+	"crdb_internal.cluster_contention_events",
 	"crdb_internal.cluster_queries",
 	"crdb_internal.cluster_sessions",
 	"crdb_internal.cluster_settings",
@@ -533,6 +534,9 @@ func runDebugZip(cmd *cobra.Command, args []string) error {
 						return err
 					}
 					for _, e := range entries.Entries {
+						if debugCtx.redactLogs {
+							e = log.RedactEntry(e)
+						}
 						if err := e.Format(logOut); err != nil {
 							return err
 						}
@@ -58,17 +58,22 @@ table_name NOT IN (
 	-- whitelisted tables that don't need to be in debug zip
 	'backward_dependencies',
 	'builtin_functions',
+	'create_function_statements',
 	'create_statements',
 	'forward_dependencies',
 	'index_columns',
+	'index_usage_statistics',
 	'table_columns',
 	'table_indexes',
 	'ranges',
 	'ranges_no_leases',
 	'predefined_comments',
+	'session_last_query_statistics',
 	'session_trace',
 	'session_variables',
-	'tables'
+	'statement_statistics',
+	'tables',
+	'transaction_statistics'
 )
 ORDER BY name ASC`)
 	assert.NoError(t, err)
@@ -136,6 +141,7 @@ requesting data for debug/rangelog... writing: debug/rangelog.json
 requesting data for debug/liveness... writing: debug/liveness.json
 requesting data for debug/settings... writing: debug/settings.json
 requesting data for debug/reports/problemranges... writing: debug/reports/problemranges.json
+retrieving SQL data for crdb_internal.cluster_contention_events... writing: debug/crdb_internal.cluster_contention_events.txt
 retrieving SQL data for crdb_internal.cluster_queries... writing: debug/crdb_internal.cluster_queries.txt
 retrieving SQL data for crdb_internal.cluster_sessions... writing: debug/crdb_internal.cluster_sessions.txt
 retrieving SQL data for crdb_internal.cluster_settings... writing: debug/crdb_internal.cluster_settings.txt
@@ -233,10 +239,13 @@ requesting table details for system.reports_meta... writing: debug/schema/system
 requesting table details for system.role_members... writing: debug/schema/system/role_members.json
 requesting table details for system.role_options... writing: debug/schema/system/role_options.json
 requesting table details for system.settings... writing: debug/schema/system/settings.json
+requesting table details for system.slow_query_log... writing: debug/schema/system/slow_query_log.json
 requesting table details for system.statement_bundle_chunks... writing: debug/schema/system/statement_bundle_chunks.json
 requesting table details for system.statement_diagnostics... writing: debug/schema/system/statement_diagnostics.json
 requesting table details for system.statement_diagnostics_requests... writing: debug/schema/system/statement_diagnostics_requests.json
+requesting table details for system.statement_statistics... writing: debug/schema/system/statement_statistics.json
 requesting table details for system.table_statistics... writing: debug/schema/system/table_statistics.json
+requesting table details for system.transaction_statistics... writing: debug/schema/system/transaction_statistics.json
 requesting table details for system.ui... writing: debug/schema/system/ui.json
 requesting table details for system.users... writing: debug/schema/system/users.json
 requesting table details for system.web_sessions... writing: debug/schema/system/web_sessions.json
@@ -320,10 +329,13 @@ requesting table details for system.reports_meta... writing: debug/schema/system
 requesting table details for system.role_members... writing: debug/schema/system-1/role_members.json
 requesting table details for system.role_options... writing: debug/schema/system-1/role_options.json
 requesting table details for system.settings... writing: debug/schema/system-1/settings.json
+requesting table details for system.slow_query_log... writing: debug/schema/system-1/slow_query_log.json
 requesting table details for system.statement_bundle_chunks... writing: debug/schema/system-1/statement_bundle_chunks.json
 requesting table details for system.statement_diagnostics... writing: debug/schema/system-1/statement_diagnostics.json
 requesting table details for system.statement_diagnostics_requests... writing: debug/schema/system-1/statement_diagnostics_requests.json
+requesting table details for system.statement_statistics... writing: debug/schema/system-1/statement_statistics.json
 requesting table details for system.table_statistics... writing: debug/schema/system-1/table_statistics.json
+requesting table details for system.transaction_statistics... writing: debug/schema/system-1/transaction_statistics.json
 requesting table details for system.ui... writing: debug/schema/system-1/ui.json
 requesting table details for system.users... writing: debug/schema/system-1/users.json
 requesting table details for system.web_sessions... writing: debug/schema/system-1/web_sessions.json
@@ -428,6 +440,7 @@ requesting data for debug/rangelog... writing: debug/rangelog.json.err.txt
 requesting data for debug/liveness... writing: debug/liveness.json
 requesting data for debug/settings... writing: debug/settings.json
 requesting data for debug/reports/problemranges... writing: debug/reports/problemranges.json
+retrieving SQL data for crdb_internal.cluster_contention_events... writing: debug/crdb_internal.cluster_contention_events.txt
 retrieving SQL data for crdb_internal.cluster_queries... writing: debug/crdb_internal.cluster_queries.txt
 retrieving SQL data for crdb_internal.cluster_sessions... writing: debug/crdb_internal.cluster_sessions.txt
 retrieving SQL data for crdb_internal.cluster_settings... writing: debug/crdb_internal.cluster_settings.txt
@@ -550,6 +563,7 @@ requesting data for debug/rangelog... writing: debug/rangelog.json
 requesting data for debug/liveness... writing: debug/liveness.json
 requesting data for debug/settings... writing: debug/settings.json
 requesting data for debug/reports/problemranges... writing: debug/reports/problemranges.json
+retrieving SQL data for crdb_internal.cluster_contention_events... writing: debug/crdb_internal.cluster_contention_events.txt
 retrieving SQL data for crdb_internal.cluster_queries... writing: debug/crdb_internal.cluster_queries.txt
 retrieving SQL data for crdb_internal.cluster_sessions... writing: debug/crdb_internal.cluster_sessions.txt
 retrieving SQL data for crdb_internal.cluster_settings... writing: debug/crdb_internal.cluster_settings.txt
@@ -759,10 +773,13 @@ requesting table details for system.reports_meta... writing: debug/schema/system
 requesting table details for system.role_members... writing: debug/schema/system/role_members.json
 requesting table details for system.role_options... writing: debug/schema/system/role_options.json
 requesting table details for system.settings... writing: debug/schema/system/settings.json
+requesting table details for system.slow_query_log... writing: debug/schema/system/slow_query_log.json
 requesting table details for system.statement_bundle_chunks... writing: debug/schema/system/statement_bundle_chunks.json
 requesting table details for system.statement_diagnostics... writing: debug/schema/system/statement_diagnostics.json
 requesting table details for system.statement_diagnostics_requests... writing: debug/schema/system/statement_diagnostics_requests.json
+requesting table details for system.statement_statistics... writing: debug/schema/system/statement_statistics.json
 requesting table details for system.table_statistics... writing: debug/schema/system/table_statistics.json
+requesting table details for system.transaction_statistics... writing: debug/schema/system/transaction_statistics.json
 requesting table details for system.ui... writing: debug/schema/system/ui.json
 requesting table details for system.users... writing: debug/schema/system/users.json
 requesting table details for system.web_sessions... writing: debug/schema/system/web_sessions.json
@@ -61,6 +61,8 @@ const (
 	VersionTimePrecision
 	Version20_1
 	VersionStart20_2
+	VersionPersistedSQLStats
+	VersionSlowQueryLogTable
 
 	// Add new versions here (step one of two).
 )
@@ -468,6 +470,20 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     VersionStart20_2,
 		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 1},
 	},
+	{
+		// VersionPersistedSQLStats introduces the system.statement_statistics
+		// and system.transaction_statistics tables, into which the nodes
+		// save their SQL statistics when they are reset.
+		Key:     VersionPersistedSQLStats,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 2},
+	},
+	{
+		// VersionSlowQueryLogTable introduces the system.slow_query_log table,
+		// into which the nodes save the plans and traces of the slow statements
+		// that they capture.
+		Key:     VersionSlowQueryLogTable,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 3},
+	},
 
 	// Add new versions here (step two of two).
 
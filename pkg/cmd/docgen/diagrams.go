@@ -525,6 +525,9 @@ var specs = []stmtSpec{
 			regexp.MustCompile("'OPTIONS'")},
 		unlink: []string{"table_name", "sink", "option", "value"},
 	},
+	{
+		name: "create_function_stmt",
+	},
 	{
 		name:   "create_index_stmt",
 		inline: []string{"opt_unique", "opt_storing", "storing", "index_params", "index_elem", "opt_asc_desc", "opt_using_gin_btree"},
@@ -583,6 +586,10 @@ var specs = []stmtSpec{
 		name:   "create_table_stmt",
 		inline: []string{"opt_table_elem_list", "table_elem_list", "table_elem"},
 	},
+	{
+		name:   "create_trigger_stmt",
+		inline: []string{"trigger_action_time", "trigger_event_list", "trigger_event", "opt_trigger_when", "trigger_set_action"},
+	},
 	{
 		name:   "create_view_stmt",
 		inline: []string{"opt_column_list"},
@@ -655,6 +662,10 @@ var specs = []stmtSpec{
 		inline: []string{"opt_drop_behavior"},
 		match:  []*regexp.Regexp{regexp.MustCompile("'DROP' 'DATABASE'")},
 	},
+	{
+		name:   "drop_function_stmt",
+		inline: []string{"func_obj_list"},
+	},
 	{
 		name:   "drop_index",
 		stmt:   "drop_index_stmt",
@@ -685,11 +696,14 @@ var specs = []stmtSpec{
 		inline: []string{"opt_drop_behavior", "table_name_list"},
 		match:  []*regexp.Regexp{regexp.MustCompile("'DROP' 'TABLE'")},
 	},
+	{
+		name: "drop_trigger_stmt",
+	},
 	{
 		name:   "drop_view",
 		stmt:   "drop_view_stmt",
 		inline: []string{"opt_drop_behavior", "table_name_list"},
-		match:  []*regexp.Regexp{regexp.MustCompile("'DROP' 'VIEW'")},
+		match:  []*regexp.Regexp{regexp.MustCompile("'DROP' ('MATERIALIZED' )?'VIEW'")},
 	},
 	{
 		name:   "experimental_audit",
@@ -872,6 +886,12 @@ var specs = []stmtSpec{
 		replace: map[string]string{"opt_table_elem_list": "table_definition"},
 		unlink:  []string{"table_definition"},
 	},
+	{
+		name:    "merge_stmt",
+		inline:  []string{"opt_with_clause", "with_clause", "cte_list", "merge_target", "merge_when_list"},
+		unlink:  []string{"table_ref"},
+		nosplit: true,
+	},
 	{
 		name: "not_null_column_level",
 		stmt: "stmt_block",
@@ -899,6 +919,11 @@ var specs = []stmtSpec{
 		replace: map[string]string{"	stmt": "	'CREATE' 'TABLE' table_name '(' ( column_def ( ',' column_def )* ) ( 'CONSTRAINT' name | ) 'PRIMARY KEY' '(' ( column_name ( ',' column_name )* ) ')' ( table_constraints | ) ')'"},
 		unlink: []string{"table_name", "column_name", "table_constraints"},
 	},
+	{
+		name:  "refresh_materialized_view",
+		stmt:  "refresh_stmt",
+		match: []*regexp.Regexp{regexp.MustCompile("'REFRESH' 'MATERIALIZED' 'VIEW'")},
+	},
 	{
 		name:   "release_savepoint",
 		stmt:   "release_stmt",
@@ -1092,6 +1117,11 @@ var specs = []stmtSpec{
 			regexp.MustCompile("'SET' 'CLUSTER'"),
 		},
 	},
+	{
+		name:   "set_constraints",
+		stmt:   "set_constraints_stmt",
+		inline: []string{"constraints_set_mode"},
+	},
 	{
 		name: "set_transaction",
 		stmt: "nonpreparable_set_stmt",
@@ -136,6 +136,29 @@ func (g *Geometry) AsGeomT() (geom.T, error) {
 	return ewkb.Unmarshal(g.ewkb)
 }
 
+// BoundingBoxIntersects returns whether the 2D bounding boxes of the two
+// geometries intersect. Empty geometries do not intersect anything.
+func (g *Geometry) BoundingBoxIntersects(o *Geometry) (bool, error) {
+	t1, err := g.AsGeomT()
+	if err != nil {
+		return false, err
+	}
+	t2, err := o.AsGeomT()
+	if err != nil {
+		return false, err
+	}
+	if t1.Empty() || t2.Empty() {
+		return false, nil
+	}
+	b1, b2 := t1.Bounds(), t2.Bounds()
+	for dim := 0; dim < 2; dim++ {
+		if b1.Min(dim) > b2.Max(dim) || b2.Min(dim) > b1.Max(dim) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // Geography is a spherical spatial object.
 type Geography struct {
 	spatialObjectBase
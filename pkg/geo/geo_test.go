@@ -166,6 +166,33 @@ func TestGeographyAsS2(t *testing.T) {
 	}
 }
 
+func TestGeometryBoundingBoxIntersects(t *testing.T) {
+	testCases := []struct {
+		a        string
+		b        string
+		expected bool
+	}{
+		{"POINT(1.0 1.0)", "POINT(1.0 1.0)", true},
+		{"POINT(1.0 1.0)", "POINT(2.0 1.0)", false},
+		{"LINESTRING(0.0 0.0, 2.0 2.0)", "POINT(1.0 1.0)", true},
+		{"LINESTRING(0.0 0.0, 2.0 2.0)", "LINESTRING(2.0 2.0, 3.0 3.0)", true},
+		{"POLYGON((0.0 0.0, 1.0 0.0, 1.0 1.0, 0.0 0.0))", "POLYGON((2.0 2.0, 3.0 2.0, 3.0 3.0, 2.0 2.0))", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s && %s", tc.a, tc.b), func(t *testing.T) {
+			a, err := ParseGeometry(tc.a)
+			require.NoError(t, err)
+			b, err := ParseGeometry(tc.b)
+			require.NoError(t, err)
+
+			intersects, err := a.BoundingBoxIntersects(b)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, intersects)
+		})
+	}
+}
+
 func TestClipEWKBByRect(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -27,6 +27,8 @@ var _ Details = SchemaChangeDetails{}
 var _ Details = ChangefeedDetails{}
 var _ Details = CreateStatsDetails{}
 var _ Details = SchemaChangeGCDetails{}
+var _ Details = RevertTableDetails{}
+var _ Details = RefreshMaterializedViewDetails{}
 
 // ProgressDetails is a marker interface for job progress details proto structs.
 type ProgressDetails interface{}
@@ -37,6 +39,8 @@ var _ ProgressDetails = SchemaChangeProgress{}
 var _ ProgressDetails = ChangefeedProgress{}
 var _ ProgressDetails = CreateStatsProgress{}
 var _ ProgressDetails = SchemaChangeGCProgress{}
+var _ ProgressDetails = RevertTableProgress{}
+var _ ProgressDetails = RefreshMaterializedViewProgress{}
 
 // Type returns the payload's job type.
 func (p *Payload) Type() Type {
@@ -64,6 +68,10 @@ func DetailsType(d isPayload_Details) Type {
 		return TypeCreateStats
 	case *Payload_SchemaChangeGC:
 		return TypeSchemaChangeGC
+	case *Payload_RevertTable:
+		return TypeRevertTable
+	case *Payload_RefreshMaterializedView:
+		return TypeRefreshMaterializedView
 	default:
 		panic(fmt.Sprintf("Payload.Type called on a payload with an unknown details type: %T", d))
 	}
@@ -92,6 +100,10 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_CreateStats{CreateStats: &d}
 	case SchemaChangeGCProgress:
 		return &Progress_SchemaChangeGC{SchemaChangeGC: &d}
+	case RevertTableProgress:
+		return &Progress_RevertTable{RevertTable: &d}
+	case RefreshMaterializedViewProgress:
+		return &Progress_RefreshMaterializedView{RefreshMaterializedView: &d}
 	default:
 		panic(fmt.Sprintf("WrapProgressDetails: unknown details type %T", d))
 	}
@@ -115,6 +127,10 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.CreateStats
 	case *Payload_SchemaChangeGC:
 		return *d.SchemaChangeGC
+	case *Payload_RevertTable:
+		return *d.RevertTable
+	case *Payload_RefreshMaterializedView:
+		return *d.RefreshMaterializedView
 	default:
 		return nil
 	}
@@ -138,6 +154,10 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return *d.CreateStats
 	case *Progress_SchemaChangeGC:
 		return *d.SchemaChangeGC
+	case *Progress_RevertTable:
+		return *d.RevertTable
+	case *Progress_RefreshMaterializedView:
+		return *d.RefreshMaterializedView
 	default:
 		return nil
 	}
@@ -174,6 +194,10 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_CreateStats{CreateStats: &d}
 	case SchemaChangeGCDetails:
 		return &Payload_SchemaChangeGC{SchemaChangeGC: &d}
+	case RevertTableDetails:
+		return &Payload_RevertTable{RevertTable: &d}
+	case RefreshMaterializedViewDetails:
+		return &Payload_RefreshMaterializedView{RefreshMaterializedView: &d}
 	default:
 		panic(fmt.Sprintf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -367,6 +367,11 @@ const (
 	StatementDiagnosticsRequestsTableID = 35
 	StatementDiagnosticsTableID         = 36
 
+	StatementStatisticsTableID   = 37
+	TransactionStatisticsTableID = 38
+
+	SlowQueryLogTableID = 39
+
 	// CommentType is type for system.comments
 	DatabaseCommentType = 0
 	TableCommentType    = 1
@@ -1765,7 +1765,7 @@ func (ds *DistSender) sendToReplicas(
 		// Has the caller given up?
 		if ctx.Err() != nil {
 			errMsg := fmt.Sprintf("context done during DistSender.Send: %s", ctx.Err())
-			log.Eventf(ctx, errMsg)
+			log.Eventf(ctx, "%s", errMsg)
 			if ambiguousError != nil {
 				return nil, roachpb.NewAmbiguousResultError(errMsg)
 			}
@@ -25,6 +25,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/opentracing/opentracing-go"
 )
 
 // LockTableLivenessPushDelay sets the delay before pushing in order to detect
@@ -116,11 +118,15 @@ func (w *lockTableWaiterImpl) WaitOn(
 	var timer *timeutil.Timer
 	var timerC <-chan time.Time
 	var timerWaitingState waitingState
+	// Used to record the contention events in the trace of the request.
+	h := contentionEventTracer{ctx: ctx}
+	defer h.emit()
 	for {
 		select {
 		case <-newStateC:
 			timerC = nil
 			state := guard.CurState()
+			h.emitAndInit(state)
 			switch state.stateKind {
 			case waitFor, waitForDistinguished:
 				// waitFor indicates that the request is waiting on another
@@ -307,7 +313,7 @@ func (w *lockTableWaiterImpl) WaitOnLock(
 	if err != nil {
 		return roachpb.NewError(err)
 	}
-	return w.pushLockTxn(ctx, req, waitingState{
+	ws := waitingState{
 		stateKind:   waitFor,
 		txn:         &intent.Txn,
 		ts:          intent.Txn.WriteTimestamp,
@@ -316,7 +322,11 @@ func (w *lockTableWaiterImpl) WaitOnLock(
 		held:        true,
 		access:      spanset.SpanReadWrite,
 		guardAccess: sa,
-	})
+	}
+	h := contentionEventTracer{ctx: ctx}
+	defer h.emit()
+	h.emitAndInit(ws)
+	return w.pushLockTxn(ctx, req, ws)
 }
 
 // pushLockTxn pushes the holder of the provided lock.
@@ -469,6 +479,57 @@ func (w *lockTableWaiterImpl) watchForNotifications(
 	}
 }
 
+// contentionEventTracer records the contention events encountered by a
+// request, i.e. the time it spent waiting on the locks and lock reservations
+// of other transactions, in its trace. The trace returns them to the SQL
+// gateway, which aggregates them.
+type contentionEventTracer struct {
+	ctx context.Context
+	// ev is the event of the current conflict, if any. Its duration is only
+	// set when it is emitted.
+	ev    *roachpb.ContentionEvent
+	start time.Time
+}
+
+// emitAndInit emits the current contention event, unless the request is still
+// waiting on the same transaction and key, and starts a new event for the
+// conflict described by the waiting state, if any.
+func (h *contentionEventTracer) emitAndInit(s waitingState) {
+	sp := opentracing.SpanFromContext(h.ctx)
+	if sp == nil || !tracing.IsRecording(sp) {
+		return
+	}
+	switch s.stateKind {
+	case waitFor, waitForDistinguished, waitElsewhere:
+		if s.txn == nil {
+			h.emit()
+			return
+		}
+		if h.ev != nil && h.ev.TxnMeta.ID == s.txn.ID && h.ev.Key.Equal(s.key) {
+			return
+		}
+		h.emit()
+		h.ev = &roachpb.ContentionEvent{Key: s.key, TxnMeta: *s.txn}
+		h.start = timeutil.Now()
+	default:
+		h.emit()
+	}
+}
+
+// emit records the current contention event, if any, in a child span of the
+// request.
+func (h *contentionEventTracer) emit() {
+	if h.ev == nil {
+		return
+	}
+	h.ev.Duration = timeutil.Since(h.start)
+	log.VEventf(h.ctx, 2, "%s", h.ev)
+	_, sp := tracing.ChildSpan(h.ctx, "lock contention")
+	tracing.SetSpanStats(sp, h.ev)
+	tracing.FinishSpan(sp)
+	h.ev = nil
+}
+
 func hasMinPriority(txn *enginepb.TxnMeta) bool {
 	return txn != nil && txn.Priority == enginepb.MinTxnPriority
 }
@@ -23,6 +23,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/gogo/protobuf/types"
 	"github.com/stretchr/testify/require"
 )
 
@@ -394,3 +396,36 @@ func TestLockTableWaiterIntentResolverError(t *testing.T) {
 		}
 	})
 }
+
+// TestContentionEventTracer tests that the contention events are recorded in
+// the trace of the request, once per conflicting transaction and key.
+func TestContentionEventTracer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx, getRecording, cancel := tracing.ContextWithRecordingSpan(context.Background(), "test")
+	defer cancel()
+
+	txn1 := makeTxnProto("pushee1")
+	txn2 := makeTxnProto("pushee2")
+	h := contentionEventTracer{ctx: ctx}
+	h.emitAndInit(waitingState{stateKind: waitFor, txn: &txn1.TxnMeta, key: roachpb.Key("a")})
+	// Waiting on the same transaction and key continues the same event.
+	h.emitAndInit(waitingState{stateKind: waitForDistinguished, txn: &txn1.TxnMeta, key: roachpb.Key("a")})
+	h.emitAndInit(waitingState{stateKind: waitFor, txn: &txn2.TxnMeta, key: roachpb.Key("b")})
+	h.emitAndInit(waitingState{stateKind: doneWaiting})
+	h.emit()
+
+	var events []roachpb.ContentionEvent
+	for _, sp := range getRecording() {
+		if sp.Stats == nil {
+			continue
+		}
+		var ev roachpb.ContentionEvent
+		require.NoError(t, types.UnmarshalAny(sp.Stats, &ev))
+		events = append(events, ev)
+	}
+	require.Len(t, events, 2)
+	require.Equal(t, txn1.ID, events[0].TxnMeta.ID)
+	require.Equal(t, roachpb.Key("a"), events[0].Key)
+	require.Equal(t, txn2.ID, events[1].TxnMeta.ID)
+	require.Equal(t, roachpb.Key("b"), events[1].Key)
+}
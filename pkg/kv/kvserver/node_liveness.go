@@ -493,7 +493,7 @@ func (nl *NodeLiveness) StartHeartbeat(
 					}
 					return nil
 				}); err != nil {
-				log.Warningf(ctx, "failed node liveness heartbeat: %+v", err)
+				log.Health.Warningf(ctx, "failed node liveness heartbeat: %+v", err)
 			}
 
 			nl.heartbeatToken <- struct{}{}
@@ -568,7 +568,7 @@ func (nl *NodeLiveness) heartbeatInternal(
 		dur := timeutil.Now().Sub(start)
 		nl.metrics.HeartbeatLatency.RecordValue(dur.Nanoseconds())
 		if dur > time.Second {
-			log.Warningf(ctx, "slow heartbeat took %0.1fs", dur.Seconds())
+			log.Health.Warningf(ctx, "slow heartbeat took %0.1fs", dur.Seconds())
 		}
 	}(timeutil.Now())
 
@@ -1377,3 +1377,18 @@ func (rirr *ResolveIntentRangeRequest) AsLockUpdate() LockUpdate {
 		IgnoredSeqNums: rirr.IgnoredSeqNums,
 	}
 }
+
+// String implements the fmt.Stringer interface.
+func (c *ContentionEvent) String() string {
+	return fmt.Sprintf("conflicted with %s on %s for %.3fs", c.TxnMeta.ID, c.Key, c.Duration.Seconds())
+}
+
+// Stats implements the tracing.SpanStats interface, so that the contention
+// events can be recorded in traces.
+func (c *ContentionEvent) Stats() map[string]string {
+	return map[string]string{
+		"contention.key":      c.Key.String(),
+		"contention.txn":      c.TxnMeta.ID.String(),
+		"contention.duration": c.Duration.String(),
+	}
+}
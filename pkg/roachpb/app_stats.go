@@ -96,6 +96,13 @@ func (s *StatementStatistics) Add(other *StatementStatistics) {
 
 	s.BytesRead += other.BytesRead
 	s.RowsRead += other.RowsRead
+
+	// Only a sample of the executions record their contention time; avoid
+	// dividing by a zero count when neither side has any samples.
+	if other.ContentionSampleCount > 0 {
+		s.ContentionTime.Add(other.ContentionTime, s.ContentionSampleCount, other.ContentionSampleCount)
+		s.ContentionSampleCount += other.ContentionSampleCount
+	}
 	s.Count += other.Count
 }
 
@@ -113,5 +120,18 @@ func (s *StatementStatistics) AlmostEqual(other *StatementStatistics, eps float6
 		s.OverheadLat.AlmostEqual(other.OverheadLat, eps) &&
 		s.SensitiveInfo.Equal(other.SensitiveInfo) &&
 		s.BytesRead == other.BytesRead &&
-		s.RowsRead == other.RowsRead
+		s.RowsRead == other.RowsRead &&
+		s.ContentionSampleCount == other.ContentionSampleCount &&
+		s.ContentionTime.AlmostEqual(other.ContentionTime, eps)
+}
+
+// Add combines other into this IndexUsageStatistics.
+func (s *IndexUsageStatistics) Add(other *IndexUsageStatistics) {
+	s.TotalReadCount += other.TotalReadCount
+	s.ScanCount += other.ScanCount
+	s.LookupJoinCount += other.LookupJoinCount
+	s.ZigzagJoinCount += other.ZigzagJoinCount
+	if s.LastRead.Before(other.LastRead) {
+		s.LastRead = other.LastRead
+	}
 }
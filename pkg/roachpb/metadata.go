@@ -33,6 +33,9 @@ func (n NodeID) String() string {
 	return strconv.FormatInt(int64(n), 10)
 }
 
+// SafeValue implements the log.SafeValue interface.
+func (n NodeID) SafeValue() {}
+
 // StoreID is a custom type for a cockroach store ID.
 type StoreID int32
 
@@ -49,6 +52,9 @@ func (n StoreID) String() string {
 	return strconv.FormatInt(int64(n), 10)
 }
 
+// SafeValue implements the log.SafeValue interface.
+func (n StoreID) SafeValue() {}
+
 // A RangeID is a unique ID associated to a Raft consensus group.
 type RangeID int64
 
@@ -57,6 +63,9 @@ func (r RangeID) String() string {
 	return strconv.FormatInt(int64(r), 10)
 }
 
+// SafeValue implements the log.SafeValue interface.
+func (r RangeID) SafeValue() {}
+
 // RangeIDSlice implements sort.Interface.
 type RangeIDSlice []RangeID
 
@@ -72,6 +81,9 @@ func (r ReplicaID) String() string {
 	return strconv.FormatInt(int64(r), 10)
 }
 
+// SafeValue implements the log.SafeValue interface.
+func (r ReplicaID) SafeValue() {}
+
 // Equals returns whether the Attributes lists are equivalent. Attributes lists
 // are treated as sets, meaning that ordering and duplicates are ignored.
 func (a Attributes) Equals(b Attributes) bool {
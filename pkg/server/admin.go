@@ -619,6 +619,58 @@ func (s *adminServer) TableDetails(
 		resp.RangeCount = rangeCount
 	}
 
+	// Marshal the index usage statistics.
+	rows, cols, err = s.server.sqlServer.internalExecutor.QueryWithCols(
+		ctx, "admin-index-usage", nil, /* txn */
+		sqlbase.InternalExecutorSessionDataOverride{User: userName},
+		fmt.Sprintf(`SELECT index_name, total_reads, scan_count, lookup_join_count,
+       zigzag_join_count, last_read
+  FROM %s.crdb_internal.index_usage_statistics
+ WHERE descriptor_id = $1
+ ORDER BY index_id`, escDBName),
+		tableID,
+	)
+	if err != nil {
+		return nil, s.serverError(err)
+	}
+	{
+		const (
+			nameCol       = "index_name"
+			totalReadsCol = "total_reads"
+			scanCol       = "scan_count"
+			lookupJoinCol = "lookup_join_count"
+			zigzagJoinCol = "zigzag_join_count"
+			lastReadCol   = "last_read"
+		)
+		scanner := makeResultScanner(cols)
+		for _, row := range rows {
+			var usage serverpb.TableDetailsResponse_IndexUsage
+			if err := scanner.Scan(row, nameCol, &usage.Name); err != nil {
+				return nil, err
+			}
+			if err := scanner.Scan(row, totalReadsCol, &usage.Statistics.TotalReadCount); err != nil {
+				return nil, err
+			}
+			if err := scanner.Scan(row, scanCol, &usage.Statistics.ScanCount); err != nil {
+				return nil, err
+			}
+			if err := scanner.Scan(row, lookupJoinCol, &usage.Statistics.LookupJoinCount); err != nil {
+				return nil, err
+			}
+			if err := scanner.Scan(row, zigzagJoinCol, &usage.Statistics.ZigzagJoinCount); err != nil {
+				return nil, err
+			}
+			var lastRead *time.Time
+			if err := scanner.Scan(row, lastReadCol, &lastRead); err != nil {
+				return nil, err
+			}
+			if lastRead != nil {
+				usage.Statistics.LastRead = *lastRead
+			}
+			resp.IndexUsage = append(resp.IndexUsage, usage)
+		}
+	}
+
 	return &resp, nil
 }
 
@@ -587,6 +587,7 @@ func TestAdminAPITableDetails(t *testing.T) {
 				fmt.Sprintf("CREATE USER app"),
 				fmt.Sprintf("GRANT SELECT ON %s.%s TO readonly", escDBName, escTblName),
 				fmt.Sprintf("GRANT SELECT,UPDATE,DELETE ON %s.%s TO app", escDBName, escTblName),
+				fmt.Sprintf("SELECT default2 FROM %s.%s@descidx", escDBName, escTblName),
 			}
 
 			for _, q := range setupQueries {
@@ -660,6 +661,20 @@ func TestAdminAPITableDetails(t *testing.T) {
 				}
 			}
 
+			// Verify index usage. The primary index may be scanned by the automatic
+			// statistics collection, so only the secondary index is checked.
+			if a, e := len(resp.IndexUsage), 2; a != e {
+				t.Fatalf("# of index usage entries %d != expected %d (got: %#v)", a, e, resp.IndexUsage)
+			}
+			if a, e := resp.IndexUsage[0].Name, "primary"; a != e {
+				t.Fatalf("index usage of %s, expected %s", a, e)
+			}
+			if usage := resp.IndexUsage[1]; usage.Name != "descidx" ||
+				usage.Statistics.TotalReadCount != 1 || usage.Statistics.ScanCount != 1 ||
+				usage.Statistics.LastRead.IsZero() {
+				t.Fatalf("unexpected index usage: %#v", usage)
+			}
+
 			// Verify range count.
 			if a, e := resp.RangeCount, int64(1); a != e {
 				t.Fatalf("# of ranges %d != expected %d", a, e)
@@ -0,0 +1,100 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IndexUsageStatistics returns the index usage statistics of the requested
+// node, or of the whole cluster if no node is specified. The statistics of
+// the nodes are merged per index.
+func (s *statusServer) IndexUsageStatistics(
+	ctx context.Context, req *serverpb.IndexUsageStatisticsRequest,
+) (*serverpb.IndexUsageStatisticsResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	localReq := &serverpb.IndexUsageStatisticsRequest{
+		NodeID: "local",
+	}
+
+	if len(req.NodeID) > 0 {
+		requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		if local {
+			return &serverpb.IndexUsageStatisticsResponse{
+				Statistics: s.indexUsageStats.Serialize(),
+			}, nil
+		}
+		status, err := s.dialNode(ctx, requestedNodeID)
+		if err != nil {
+			return nil, err
+		}
+		return status.IndexUsageStatistics(ctx, localReq)
+	}
+
+	merged := make(map[roachpb.IndexUsageKey]*roachpb.IndexUsageStatistics)
+	dialFn := func(ctx context.Context, nodeID roachpb.NodeID) (interface{}, error) {
+		client, err := s.dialNode(ctx, nodeID)
+		return client, err
+	}
+	nodeFn := func(ctx context.Context, client interface{}, _ roachpb.NodeID) (interface{}, error) {
+		status := client.(serverpb.StatusClient)
+		return status.IndexUsageStatistics(ctx, localReq)
+	}
+	responseFn := func(_ roachpb.NodeID, nodeResp interface{}) {
+		resp := nodeResp.(*serverpb.IndexUsageStatisticsResponse)
+		for i := range resp.Statistics {
+			stats := &resp.Statistics[i]
+			if existing, ok := merged[stats.Key]; ok {
+				existing.Add(&stats.Stats)
+			} else {
+				merged[stats.Key] = &stats.Stats
+			}
+		}
+	}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		// The statistics of the other nodes are still useful; they're merely
+		// incomplete.
+		log.Warningf(ctx, "unable to retrieve the index usage statistics of node %d: %v", nodeID, err)
+	}
+	if err := s.iterateNodes(
+		ctx, "index usage statistics", dialFn, nodeFn, responseFn, errorFn,
+	); err != nil {
+		return nil, err
+	}
+
+	response := &serverpb.IndexUsageStatisticsResponse{
+		Statistics: make([]roachpb.CollectedIndexUsageStatistics, 0, len(merged)),
+	}
+	for key, stats := range merged {
+		response.Statistics = append(response.Statistics,
+			roachpb.CollectedIndexUsageStatistics{Key: key, Stats: *stats})
+	}
+	sort.Slice(response.Statistics, func(i, j int) bool {
+		a, b := response.Statistics[i].Key, response.Statistics[j].Key
+		if a.TableID != b.TableID {
+			return a.TableID < b.TableID
+		}
+		return a.IndexID < b.IndexID
+	})
+	return response, nil
+}
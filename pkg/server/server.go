@@ -55,7 +55,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/contention"
 	_ "github.com/cockroachdb/cockroach/pkg/sql/gcjob" // register jobs declared outside of pkg/sql
+	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
@@ -471,7 +473,8 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		Storage:  protectedtsProvider,
 		Cache:    protectedtsProvider,
 		StatusFuncs: ptreconcile.StatusFuncs{
-			jobsprotectedts.MetaType: jobsprotectedts.MakeStatusFunc(jobRegistry),
+			jobsprotectedts.MetaType:  jobsprotectedts.MakeStatusFunc(jobRegistry),
+			sql.ProtectedReadMetaType: sql.MakeProtectedReadStatusFunc(clock, nodeLiveness.IsLive),
 		},
 	})
 	registry.AddMetricStruct(protectedtsReconciler.Metrics())
@@ -480,6 +483,8 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	// TODO(tbg): give adminServer only what it needs (and avoid circular deps).
 	adminServer := newAdminServer(lateBoundServer)
 	sessionRegistry := sql.NewSessionRegistry()
+	contentionRegistry := contention.NewRegistry()
+	indexUsageStats := idxusage.NewLocalIndexUsageStats(st)
 
 	statusServer := newStatusServer(
 		cfg.AmbientCtx,
@@ -495,6 +500,8 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		node.stores,
 		stopper,
 		sessionRegistry,
+		contentionRegistry,
+		indexUsageStats,
 		internalExecutor,
 	)
 	// TODO(tbg): don't pass all of Server into this to avoid this hack.
@@ -528,6 +535,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		externalStorageFromURI:   externalStorageFromURI,
 		jobRegistry:              jobRegistry,
 		isMeta1Leaseholder:       node.stores.IsMeta1Leaseholder,
+		tsServer:                 &tsServer,
 	})
 	if err != nil {
 		return nil, err
@@ -50,6 +50,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sqlmigrations"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/ts"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -147,6 +148,9 @@ type sqlServerArgs struct {
 	// The protected timestamps KV subsystem depends on this, so it is bound
 	// early but only gets filled in newSQLServer.
 	jobRegistry *jobs.Registry
+
+	// Used by the executor config to serve crdb_internal.timeseries_query().
+	tsServer *ts.Server
 }
 
 func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*sqlServer, error) {
@@ -308,6 +312,8 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*sqlServer, error) {
 
 		ExternalStorage:        cfg.externalStorage,
 		ExternalStorageFromURI: cfg.externalStorageFromURI,
+
+		IndexUsageStats: cfg.status.indexUsageStats,
 	}
 	cfg.TempStorageConfig.Mon.SetMetrics(distSQLMetrics.CurDiskBytesCount, distSQLMetrics.MaxDiskBytesHist)
 	if distSQLTestingKnobs := cfg.TestingKnobs.DistSQL; distSQLTestingKnobs != nil {
@@ -356,6 +362,9 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*sqlServer, error) {
 		DistSQLSrv:              distSQLServer,
 		StatusServer:            cfg.status,
 		SessionRegistry:         sessionRegistry,
+		ContentionRegistry:      cfg.status.contentionRegistry,
+		TimeSeriesServer:        cfg.tsServer,
+		IsMeta1Leaseholder:      cfg.isMeta1Leaseholder,
 		JobRegistry:             jobRegistry,
 		VirtualSchemas:          virtualSchemas,
 		HistogramWindowInterval: cfg.HistogramWindowInterval(),
@@ -423,6 +432,11 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*sqlServer, error) {
 		ProtectedTimestampProvider: cfg.protectedtsProvider,
 	}
 
+	execCfg.ExecLogger.SetChannel(log.ChannelSQLExec)
+	execCfg.AuthLogger.SetChannel(log.ChannelSessions)
+	execCfg.AuditLogger.SetChannel(log.ChannelSensitiveAccess)
+	execCfg.SlowQueryLogger.SetChannel(log.ChannelSQLPerf)
+
 	cfg.stopper.AddCloser(execCfg.ExecLogger)
 	cfg.stopper.AddCloser(execCfg.AuditLogger)
 	cfg.stopper.AddCloser(execCfg.SlowQueryLogger)
@@ -12,13 +12,17 @@ package server
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/tests"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
@@ -177,3 +181,116 @@ func TestSQLStatCollection(t *testing.T) {
 		t.Fatal("expected to find stats for insert query in reported pool, but didn't")
 	}
 }
+
+func TestPersistedSQLStats(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	params, _ := tests.CreateTestServerParams()
+	s, db, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(ctx)
+
+	sqlServer := s.(*TestServer).Server.sqlServer.pgServer.SQLServer
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `
+CREATE DATABASE t;
+CREATE TABLE t.test (x INT PRIMARY KEY);
+INSERT INTO t.test VALUES (1);
+INSERT INTO t.test VALUES (2);
+INSERT INTO t.test VALUES (3);
+`)
+
+	// Resetting the SQL stats flushes them into the system tables.
+	sqlServer.ResetSQLStats(ctx)
+	var n int
+	sqlDB.QueryRow(t, `
+SELECT count(*) FROM system.statement_statistics
+WHERE fingerprint LIKE 'INSERT INTO t.test VALUES%' AND node_id = $1`,
+		s.NodeID()).Scan(&n)
+	if n != 1 {
+		t.Fatalf("expected 1 persisted row, found %d", n)
+	}
+	sqlDB.CheckQueryResults(t, `
+SELECT sum(count) FROM crdb_internal.statement_statistics
+WHERE key LIKE 'INSERT INTO t.test VALUES%'`,
+		[][]string{{"3"}})
+
+	// The crdb_internal views combine the persisted and the in-memory stats.
+	sqlDB.Exec(t, `
+INSERT INTO t.test VALUES (4);
+INSERT INTO t.test VALUES (5);
+`)
+	sqlDB.CheckQueryResults(t, `
+SELECT sum(count) FROM crdb_internal.statement_statistics
+WHERE key LIKE 'INSERT INTO t.test VALUES%'`,
+		[][]string{{"5"}})
+
+	// Flushing the stats again doesn't change the cluster-wide stats.
+	sqlServer.ResetSQLStats(ctx)
+	sqlDB.CheckQueryResults(t, `
+SELECT sum(count) FROM crdb_internal.statement_statistics
+WHERE key LIKE 'INSERT INTO t.test VALUES%'`,
+		[][]string{{"5"}})
+	sqlDB.CheckQueryResults(t, `
+SELECT count(*) > 0 FROM crdb_internal.transaction_statistics WHERE txn_count > 0`,
+		[][]string{{"true"}})
+
+	// Resetting the SQL stats keeps the in-memory transaction stats, and only
+	// flushes the transactions recorded since the previous reset.
+	sqlDB.Exec(t, `
+SET application_name = 'persisted_txn_stats';
+SELECT 1;
+SELECT 1;
+SELECT 1;
+RESET application_name;
+`)
+	const nodeTxnCountQuery = `
+SELECT txn_count FROM crdb_internal.node_txn_stats
+WHERE application_name = 'persisted_txn_stats'`
+	const txnCountQuery = `
+SELECT sum(txn_count) FROM crdb_internal.transaction_statistics
+WHERE application_name = 'persisted_txn_stats'`
+	var txnCount int
+	sqlDB.QueryRow(t, nodeTxnCountQuery).Scan(&txnCount)
+	if txnCount < 3 {
+		t.Fatalf("expected at least 3 transactions, found %d", txnCount)
+	}
+	expected := [][]string{{strconv.Itoa(txnCount)}}
+	sqlDB.CheckQueryResults(t, txnCountQuery, expected)
+	for i := 0; i < 2; i++ {
+		sqlServer.ResetSQLStats(ctx)
+		sqlDB.CheckQueryResults(t, nodeTxnCountQuery, expected)
+		sqlDB.CheckQueryResults(t, txnCountQuery, expected)
+	}
+
+	// The node which holds the meta1 lease compacts the old statistics of all
+	// the nodes into per-day rows, and deletes the ones older than the TTL,
+	// when it flushes its own statistics.
+	day := timeutil.Now().Add(-3 * 24 * time.Hour).Truncate(24 * time.Hour)
+	insertStmtStats := func(ts time.Time, nodeID int, count int64) {
+		data, err := protoutil.Marshal(&roachpb.StatementStatistics{Count: count})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sqlDB.Exec(t, `
+INSERT INTO system.statement_statistics
+VALUES ($1, 'compacted_stats', 'SELECT _', false, false, true, $2, $3)`,
+			ts, nodeID, data)
+	}
+	insertStmtStats(day.Add(time.Hour), 1, 2)
+	insertStmtStats(day.Add(2*time.Hour), 2, 3)
+	insertStmtStats(day.Add(-10*24*time.Hour), 1, 4)
+	sqlServer.ResetSQLStats(ctx)
+	var compacted bool
+	sqlDB.QueryRow(t, `
+SELECT count(*), bool_and(aggregated_ts = $1 AND node_id = 0) FROM system.statement_statistics
+WHERE app_name = 'compacted_stats'`,
+		day).Scan(&n, &compacted)
+	if n != 1 || !compacted {
+		t.Fatalf("expected a single compacted row, found %d rows (compacted: %t)", n, compacted)
+	}
+	sqlDB.CheckQueryResults(t, `
+SELECT sum(count) FROM crdb_internal.statement_statistics
+WHERE application_name = 'compacted_stats'`,
+		[][]string{{"5"}})
+}
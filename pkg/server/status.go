@@ -50,10 +50,13 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/contention"
+	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
@@ -113,6 +116,7 @@ var (
 type metricMarshaler interface {
 	json.Marshaler
 	PrintAsText(io.Writer) error
+	PrintAsOpenMetrics(io.Writer) error
 }
 
 func propagateGatewayMetadata(ctx context.Context) context.Context {
@@ -138,6 +142,8 @@ type statusServer struct {
 	stores                   *kvserver.Stores
 	stopper                  *stop.Stopper
 	sessionRegistry          *sql.SessionRegistry
+	contentionRegistry       *contention.Registry
+	indexUsageStats          *idxusage.LocalIndexUsageStats
 	si                       systemInfoOnce
 	stmtDiagnosticsRequester StmtDiagnosticsRequester
 	internalExecutor         *sql.InternalExecutor
@@ -169,24 +175,28 @@ func newStatusServer(
 	stores *kvserver.Stores,
 	stopper *stop.Stopper,
 	sessionRegistry *sql.SessionRegistry,
+	contentionRegistry *contention.Registry,
+	indexUsageStats *idxusage.LocalIndexUsageStats,
 	internalExecutor *sql.InternalExecutor,
 ) *statusServer {
 	ambient.AddLogTag("status", nil)
 	server := &statusServer{
-		AmbientContext:   ambient,
-		st:               st,
-		cfg:              cfg,
-		admin:            adminServer,
-		db:               db,
-		gossip:           gossip,
-		metricSource:     metricSource,
-		nodeLiveness:     nodeLiveness,
-		storePool:        storePool,
-		rpcCtx:           rpcCtx,
-		stores:           stores,
-		stopper:          stopper,
-		sessionRegistry:  sessionRegistry,
-		internalExecutor: internalExecutor,
+		AmbientContext:     ambient,
+		st:                 st,
+		cfg:                cfg,
+		admin:              adminServer,
+		db:                 db,
+		gossip:             gossip,
+		metricSource:       metricSource,
+		nodeLiveness:       nodeLiveness,
+		storePool:          storePool,
+		rpcCtx:             rpcCtx,
+		stores:             stores,
+		stopper:            stopper,
+		sessionRegistry:    sessionRegistry,
+		contentionRegistry: contentionRegistry,
+		indexUsageStats:    indexUsageStats,
+		internalExecutor:   internalExecutor,
 	}
 
 	return server
@@ -1235,9 +1245,19 @@ func (s *statusServer) RaftDebug(
 	return &mu.resp, nil
 }
 
+// handleVars serves the metrics in the prometheus text format or, if requested
+// with ?format=openmetrics, in the OpenMetrics text format. The format is
+// opt-in rather than negotiated with the Accept header, so that the scrapers
+// which accept both formats keep receiving the prometheus text format.
 func (s *statusServer) handleVars(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set(httputil.ContentTypeHeader, httputil.PlaintextContentType)
-	err := s.metricSource.PrintAsText(w)
+	var err error
+	if r.URL.Query().Get("format") == "openmetrics" {
+		w.Header().Set(httputil.ContentTypeHeader, metric.OpenMetricsContentType)
+		err = s.metricSource.PrintAsOpenMetrics(w)
+	} else {
+		w.Header().Set(httputil.ContentTypeHeader, httputil.PlaintextContentType)
+		err = s.metricSource.PrintAsText(w)
+	}
 	if err != nil {
 		log.Error(r.Context(), err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1722,6 +1742,74 @@ func (s *statusServer) ListSessions(
 	return response, nil
 }
 
+// ListLocalContentionEvents returns the most recent contention events
+// encountered by the statements executed on this node.
+func (s *statusServer) ListLocalContentionEvents(
+	ctx context.Context, req *serverpb.ListContentionEventsRequest,
+) (*serverpb.ListContentionEventsResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	if !debug.GatewayRemoteAllowed(ctx, s.st) {
+		return nil, remoteDebuggingErr
+	}
+
+	// Contention events reveal keys and statements of all users.
+	_, isAdmin, err := s.admin.getUserAndRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, errInsufficientPrivilege
+	}
+
+	events := s.contentionRegistry.Serialize()
+	nodeID := s.gossip.NodeID.Get()
+	for i := range events {
+		events[i].NodeID = nodeID
+	}
+	return &serverpb.ListContentionEventsResponse{Events: events}, nil
+}
+
+// ListContentionEvents returns the most recent contention events encountered
+// by the statements executed on all nodes in the cluster.
+func (s *statusServer) ListContentionEvents(
+	ctx context.Context, req *serverpb.ListContentionEventsRequest,
+) (*serverpb.ListContentionEventsResponse, error) {
+	if !debug.GatewayRemoteAllowed(ctx, s.st) {
+		return nil, remoteDebuggingErr
+	}
+
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	response := &serverpb.ListContentionEventsResponse{
+		Events: make([]serverpb.ContentionEventRecord, 0),
+		Errors: make([]serverpb.ListContentionEventsError, 0),
+	}
+
+	dialFn := func(ctx context.Context, nodeID roachpb.NodeID) (interface{}, error) {
+		client, err := s.dialNode(ctx, nodeID)
+		return client, err
+	}
+	nodeFn := func(ctx context.Context, client interface{}, _ roachpb.NodeID) (interface{}, error) {
+		status := client.(serverpb.StatusClient)
+		return status.ListLocalContentionEvents(ctx, req)
+	}
+	responseFn := func(_ roachpb.NodeID, nodeResp interface{}) {
+		events := nodeResp.(*serverpb.ListContentionEventsResponse)
+		response.Events = append(response.Events, events.Events...)
+	}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		errResponse := serverpb.ListContentionEventsError{NodeID: nodeID, Message: err.Error()}
+		response.Errors = append(response.Errors, errResponse)
+	}
+
+	if err := s.iterateNodes(ctx, "contention events", dialFn, nodeFn, responseFn, errorFn); err != nil {
+		err := serverpb.ListContentionEventsError{Message: err.Error()}
+		response.Errors = append(response.Errors, err)
+	}
+	return response, nil
+}
+
 // CancelSession responds to a session cancellation request by canceling the
 // target session's associated context.
 func (s *statusServer) CancelSession(
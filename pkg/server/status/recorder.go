@@ -275,6 +275,16 @@ func (mr *MetricsRecorder) lockAndPrintAsText(w io.Writer) error {
 	return mr.promMu.prometheusExporter.PrintAsText(w)
 }
 
+// PrintAsOpenMetrics writes the current metrics values to the writer in the
+// OpenMetrics text format. Like ExportToGraphite, it creates a new exporter
+// each time rather than sharing mr.promMu.prometheusExporter, as the
+// OpenMetrics exporter scrapes histograms differently.
+func (mr *MetricsRecorder) PrintAsOpenMetrics(w io.Writer) error {
+	pm := metric.MakeOpenMetricsExporter()
+	mr.scrapeIntoPrometheus(&pm)
+	return pm.PrintAsOpenMetrics(w)
+}
+
 // ExportToGraphite sends the current metric values to a Graphite server.
 // It creates a new PrometheusExporter each time to avoid needing to worry
 // about races with mr.promMu.prometheusExporter. We are not as worried
@@ -594,7 +604,7 @@ func GetTotalMemory(ctx context.Context) (int64, error) {
 		return 0, err
 	}
 	if warning != "" {
-		log.Infof(ctx, warning)
+		log.Infof(ctx, "%s", log.Safe(warning))
 	}
 	return memory, nil
 }
@@ -372,6 +372,7 @@ func TestMetricsRecorder(t *testing.T) {
 				t.Error(err)
 			}
 			_ = recorder.PrintAsText(ioutil.Discard)
+			_ = recorder.PrintAsOpenMetrics(ioutil.Discard)
 			_ = recorder.GetTimeSeriesData()
 			wg.Done()
 		}()
@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -1003,6 +1004,58 @@ func TestStatusVars(t *testing.T) {
 	}
 }
 
+// TestStatusVarsOpenMetrics verifies that the OpenMetrics format is only
+// served by the /_status/vars endpoint when requested explicitly.
+func TestStatusVarsOpenMetrics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+
+	httpClient, err := s.GetAdminAuthenticatedHTTPClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(url string, accept string) (contentType string, body []byte) {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accept != "" {
+			req.Header.Set(httputil.AcceptHeader, accept)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err = ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Header.Get(httputil.ContentTypeHeader), body
+	}
+
+	// A scraper accepting OpenMetrics still gets the prometheus text format.
+	url := s.AdminURL() + statusPrefix + "vars"
+	contentType, body := get(url, "application/openmetrics-text; version=1.0.0,text/plain;q=0.5")
+	if contentType != httputil.PlaintextContentType {
+		t.Errorf("expected %s, got %s", httputil.PlaintextContentType, contentType)
+	}
+	if bytes.Contains(body, []byte("# EOF")) {
+		t.Errorf("unexpected OpenMetrics output: %s", body)
+	}
+
+	contentType, body = get(url+"?format=openmetrics", "")
+	if contentType != metric.OpenMetricsContentType {
+		t.Errorf("expected %s, got %s", metric.OpenMetricsContentType, contentType)
+	}
+	if !bytes.Contains(body, []byte("# TYPE sql_bytesout counter\n")) ||
+		!bytes.Contains(body, []byte("\nsql_bytesout_total")) ||
+		!bytes.HasSuffix(body, []byte("# EOF\n")) {
+		t.Errorf("expected OpenMetrics output, got: %s", body)
+	}
+}
+
 func TestSpanStatsResponse(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	ts := startServer(t)
@@ -1262,6 +1315,8 @@ func TestRemoteDebugModeSetting(t *testing.T) {
 			{"logfiles/local/cockroach.log", &serverpb.LogEntriesResponse{}},
 			{"local_sessions", &serverpb.ListSessionsResponse{}},
 			{"sessions", &serverpb.ListSessionsResponse{}},
+			{"local_contention_events", &serverpb.ListContentionEventsResponse{}},
+			{"contention_events", &serverpb.ListContentionEventsResponse{}},
 		} {
 			err := getStatusJSONProto(ts, tc.path, tc.response)
 			if !testutils.IsError(err, "403 Forbidden") {
@@ -1306,6 +1361,12 @@ func TestRemoteDebugModeSetting(t *testing.T) {
 	if _, err := client.ListSessions(ctx, &serverpb.ListSessionsRequest{}); err != nil {
 		t.Error(err)
 	}
+	if _, err := client.ListLocalContentionEvents(ctx, &serverpb.ListContentionEventsRequest{}); err != nil {
+		t.Error(err)
+	}
+	if _, err := client.ListContentionEvents(ctx, &serverpb.ListContentionEventsRequest{}); err != nil {
+		t.Error(err)
+	}
 
 	// Check that keys are properly omitted from the Ranges, HotRanges, and
 	// RangeLog endpoints.
@@ -184,6 +184,9 @@ func (p *planner) AlterPrimaryKey(
 	// to consider the indexed columns to be newPrimaryIndexDesc.ColumnIDs.
 	newPrimaryIndexDesc.StoreColumnNames, newPrimaryIndexDesc.StoreColumnIDs = nil, nil
 	for _, col := range tableDesc.Columns {
+		if col.Virtual {
+			continue
+		}
 		containsCol := false
 		for _, colID := range newPrimaryIndexDesc.ColumnIDs {
 			if colID == col.ID {
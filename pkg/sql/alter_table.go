@@ -276,9 +276,9 @@ func (n *alterTableNode) startExec(params runParams) error {
 				}
 				idx := sqlbase.IndexDescriptor{
 					Name:             string(d.Name),
-					Unique:           true,
 					StoreColumnNames: d.Storing.ToStrings(),
 				}
+				idx.SetUniqueDeferrability(d.Deferrability)
 				if err := idx.FillColumns(d.Columns); err != nil {
 					return err
 				}
@@ -315,6 +315,38 @@ func (n *alterTableNode) startExec(params runParams) error {
 				}
 				n.tableDesc.AddCheckMutation(ck, sqlbase.DescriptorMutation_ADD)
 
+			case *tree.ExcludeConstraintTableDef:
+				c, err := MakeExclusionConstraint(params.ctx,
+					n.tableDesc, d, inuseNames, &params.p.semaCtx, *tn)
+				if err != nil {
+					return err
+				}
+				if t.ValidationBehavior == tree.ValidationDefault {
+					c.Validity = sqlbase.ConstraintValidity_Validating
+				} else {
+					c.Validity = sqlbase.ConstraintValidity_Unvalidated
+				}
+				idx, err := makeExclusionIndex(n.tableDesc, c)
+				if err != nil {
+					return err
+				}
+				if idx != nil {
+					if err := n.tableDesc.AddIndexMutation(idx, sqlbase.DescriptorMutation_ADD); err != nil {
+						return err
+					}
+					// Allocate the ID of the new index so that it can be recorded in
+					// the constraint, which drops the index with it.
+					if err := n.tableDesc.AllocateIDs(); err != nil {
+						return err
+					}
+					added, _, err := n.tableDesc.FindIndexByName(idx.Name)
+					if err != nil {
+						return err
+					}
+					c.IndexID = added.ID
+				}
+				n.tableDesc.AddExclusionMutation(c, sqlbase.DescriptorMutation_ADD)
+
 			case *tree.ForeignKeyConstraintTableDef:
 				for _, colName := range d.FromCols {
 					col, err := n.tableDesc.FindActiveColumnByName(string(colName))
@@ -459,6 +491,11 @@ func (n *alterTableNode) startExec(params runParams) error {
 				return err
 			}
 
+			// Nor if there are triggers that use it.
+			if err := checkColumnHasNoTriggerDependencies(n.tableDesc, col); err != nil {
+				return err
+			}
+
 			if n.tableDesc.PrimaryIndex.ContainsColumnID(col.ID) {
 				return pgerror.Newf(pgcode.InvalidColumnReference,
 					"column %q is referenced by the primary key", col.Name)
@@ -555,6 +592,23 @@ func (n *alterTableNode) startExec(params runParams) error {
 				descriptorChanged = true
 			}
 
+			// Drop exclusion constraints which reference the column.
+			validExclusions := n.tableDesc.Exclusions[:0]
+			for _, c := range n.tableDesc.Exclusions {
+				if c.UsesColumn(col.ID) {
+					if c.Validity == sqlbase.ConstraintValidity_Validating {
+						return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+							"referencing constraint %q in the middle of being added, try again later", c.Name)
+					}
+				} else {
+					validExclusions = append(validExclusions, c)
+				}
+			}
+			if len(validExclusions) != len(n.tableDesc.Exclusions) {
+				n.tableDesc.Exclusions = validExclusions
+				descriptorChanged = true
+			}
+
 			if err != nil {
 				return err
 			}
@@ -595,6 +649,10 @@ func (n *alterTableNode) startExec(params runParams) error {
 				return pgerror.Newf(pgcode.UndefinedObject,
 					"constraint %q does not exist", t.Constraint)
 			}
+			var exclusionIndexID sqlbase.IndexID
+			if details.Kind == sqlbase.ConstraintTypeExclusion {
+				exclusionIndexID = details.ExclusionConstraint.IndexID
+			}
 			if err := n.tableDesc.DropConstraint(
 				params.ctx,
 				name, details,
@@ -603,6 +661,19 @@ func (n *alterTableNode) startExec(params runParams) error {
 				}, params.ExecCfg().Settings); err != nil {
 				return err
 			}
+			// The index that was created to back the checks of an exclusion
+			// constraint is dropped with it, unless it was already dropped.
+			if exclusionIndexID != 0 {
+				if idx, err := n.tableDesc.FindIndexByID(exclusionIndexID); err == nil {
+					if err := params.p.dropIndexByName(
+						params.ctx, tn, tree.UnrestrictedName(idx.Name), n.tableDesc, true, /* ifExists */
+						t.DropBehavior, checkIdxConstraint,
+						tree.AsStringWithFQNames(n.n, params.Ann()),
+					); err != nil {
+						return err
+					}
+				}
+			}
 			descriptorChanged = true
 			if err := n.tableDesc.Validate(params.ctx, params.p.txn); err != nil {
 				return err
@@ -666,6 +737,24 @@ func (n *alterTableNode) startExec(params runParams) error {
 				}
 				foundFk.Validity = sqlbase.ConstraintValidity_Validated
 
+			case sqlbase.ConstraintTypeExclusion:
+				c, err := n.tableDesc.FindExclusionByName(name)
+				if err != nil {
+					return err
+				}
+				// If the constraint is still being validated, don't allow VALIDATE
+				// CONSTRAINT to run.
+				if c.Validity == sqlbase.ConstraintValidity_Validating {
+					return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+						"constraint %q in the middle of being added, try again later", t.Constraint)
+				}
+				if err := validateExclusionInTxn(
+					params.ctx, params.p.LeaseMgr(), params.EvalContext(), n.tableDesc, params.EvalContext().Txn, name,
+				); err != nil {
+					return err
+				}
+				c.Validity = sqlbase.ConstraintValidity_Validated
+
 			default:
 				return pgerror.Newf(pgcode.WrongObjectType,
 					"constraint %q of relation %q is not a foreign key or check constraint",
@@ -1059,6 +1148,10 @@ func applyColumnMutation(
 			return pgerror.Newf(pgcode.InvalidColumnDefinition,
 				"column %q is not a computed column", col.Name)
 		}
+		if col.Virtual {
+			return pgerror.Newf(pgcode.InvalidColumnDefinition,
+				"column %q is a virtual computed column", col.Name)
+		}
 		col.ComputeExpr = nil
 	}
 	return nil
@@ -62,6 +62,10 @@ type transactionStats struct {
 	mu struct {
 		syncutil.Mutex
 		roachpb.TxnStats
+		// unflushed holds the statistics recorded since the last reset, which
+		// are the ones not yet persisted in the system tables. Unlike
+		// TxnStats, it is cleared by resetStats.
+		unflushed roachpb.TxnStats
 	}
 }
 
@@ -131,6 +135,8 @@ func (a *appStats) recordStatement(
 	err error,
 	parseLat, planLat, runLat, svcLat, ovhLat float64,
 	bytesRead, rowsRead int64,
+	contentionSampled bool,
+	contentionTime float64,
 ) {
 	if !stmtStatsEnable.Get(&a.st.SV) {
 		return
@@ -167,6 +173,10 @@ func (a *appStats) recordStatement(
 	s.data.OverheadLat.Record(s.data.Count, ovhLat)
 	s.data.BytesRead = bytesRead
 	s.data.RowsRead = rowsRead
+	if contentionSampled {
+		s.data.ContentionSampleCount++
+		s.data.ContentionTime.Record(s.data.ContentionSampleCount, contentionTime)
+	}
 	s.Unlock()
 }
 
@@ -260,16 +270,38 @@ func (s *transactionStats) getStats() (
 	return txnCount, txnTimeAvg, txnTimeVar, committedCount, implicitCount
 }
 
+// getUnflushed returns the statistics recorded since the last reset.
+func (s *transactionStats) getUnflushed() roachpb.TxnStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mu.unflushed
+}
+
+// resetUnflushed clears the statistics recorded since the last reset and
+// returns their previous value.
+func (s *transactionStats) resetUnflushed() roachpb.TxnStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.mu.unflushed
+	s.mu.unflushed = roachpb.TxnStats{}
+	return prev
+}
+
 func (s *transactionStats) recordTransaction(txnTimeSec float64, ev txnEvent, implicit bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.mu.TxnCount++
-	s.mu.TxnTimeSec.Record(s.mu.TxnCount, txnTimeSec)
+	recordTxnStats(&s.mu.TxnStats, txnTimeSec, ev, implicit)
+	recordTxnStats(&s.mu.unflushed, txnTimeSec, ev, implicit)
+}
+
+func recordTxnStats(stats *roachpb.TxnStats, txnTimeSec float64, ev txnEvent, implicit bool) {
+	stats.TxnCount++
+	stats.TxnTimeSec.Record(stats.TxnCount, txnTimeSec)
 	if ev == txnCommit {
-		s.mu.CommittedCount++
+		stats.CommittedCount++
 	}
 	if implicit {
-		s.mu.ImplicitCount++
+		stats.ImplicitCount++
 	}
 }
 
@@ -312,6 +344,9 @@ type sqlStats struct {
 	lastReset time.Time
 	// apps is the container for all the per-application statistics objects.
 	apps map[string]*appStats
+	// flush, if set, is called by resetStats with the statement statistics it
+	// cleared and the transaction statistics recorded since the last reset.
+	flush func(ctx context.Context, snap *statsSnapshot)
 }
 
 func (s *sqlStats) getStatsForApplication(appName string) *appStats {
@@ -343,7 +378,7 @@ func (s *sqlStats) Add(other *sqlStats) {
 }
 
 // resetStats clears all the stored per-app and per-statement
-// statistics.
+// statistics. The transaction statistics are kept.
 func (s *sqlStats) resetStats(ctx context.Context) {
 	// Note: we do not clear the entire s.apps map here. We would need
 	// to do so to prevent problems with a runaway client running `SET
@@ -357,6 +392,15 @@ func (s *sqlStats) resetStats(ctx context.Context) {
 	// different application_names seen so far.
 
 	s.Lock()
+	// The statement statistics being cleared and the transaction statistics
+	// recorded since the last reset are set aside for the flush.
+	start := s.lastReset
+	var stmts map[string]map[stmtKey]*stmtStats
+	var txns map[string]roachpb.TxnStats
+	if s.flush != nil {
+		stmts = make(map[string]map[stmtKey]*stmtStats, len(s.apps))
+		txns = make(map[string]roachpb.TxnStats, len(s.apps))
+	}
 	// Clear the per-apps maps manually,
 	// because any SQL session currently open has cached the
 	// pointer to its appStats object and will continue to
@@ -366,13 +410,15 @@ func (s *sqlStats) resetStats(ctx context.Context) {
 		a.Lock()
 
 		// Save the existing data to logs.
-		// TODO(knz/dt): instead of dumping the stats to the log, save
-		// them in a SQL table so they can be inspected by the DBA and/or
-		// the UI.
 		if dumpStmtStatsToLogBeforeReset.Get(&a.st.SV) {
 			dumpStmtStats(ctx, appName, a.stmts)
 		}
 
+		if s.flush != nil {
+			stmts[appName] = a.stmts
+			txns[appName] = a.txns.resetUnflushed()
+		}
+
 		// Clear the map, to release the memory; make the new map somewhat already
 		// large for the likely future workload.
 		a.stmts = make(map[stmtKey]*stmtStats, len(a.stmts)/2)
@@ -380,6 +426,12 @@ func (s *sqlStats) resetStats(ctx context.Context) {
 	}
 	s.lastReset = timeutil.Now()
 	s.Unlock()
+
+	// Persist the statistics outside of the locks, so that the collection of
+	// new statistics is not blocked.
+	if s.flush != nil {
+		s.flush(ctx, makeStatsSnapshot(start, stmts, txns))
+	}
 }
 
 func (s *sqlStats) getLastReset() time.Time {
@@ -201,6 +201,11 @@ func (sc *SchemaChanger) runBackfill(ctx context.Context) error {
 					// NOT NULL constraints are always validated before they can be added
 					constraintsToAddBeforeValidation = append(constraintsToAddBeforeValidation, *t.Constraint)
 					constraintsToValidate = append(constraintsToValidate, *t.Constraint)
+				case sqlbase.ConstraintToUpdate_EXCLUSION:
+					if t.Constraint.Exclusion.Validity == sqlbase.ConstraintValidity_Validating {
+						constraintsToAddBeforeValidation = append(constraintsToAddBeforeValidation, *t.Constraint)
+						constraintsToValidate = append(constraintsToValidate, *t.Constraint)
+					}
 				}
 			case *sqlbase.DescriptorMutation_PrimaryKeySwap:
 				// The backfiller doesn't need to do anything here.
@@ -367,6 +372,23 @@ func (sc *SchemaChanger) dropConstraints(
 						constraint,
 					)
 				}
+			case sqlbase.ConstraintToUpdate_EXCLUSION:
+				found := false
+				for j := range scTable.Exclusions {
+					if scTable.Exclusions[j].Name == constraint.Name {
+						scTable.Exclusions = append(scTable.Exclusions[:j], scTable.Exclusions[j+1:]...)
+						found = true
+						break
+					}
+				}
+				if !found {
+					log.VEventf(
+						ctx, 2,
+						"backfiller tried to drop constraint %+v but it was not found, "+
+							"presumably due to a retry or rollback",
+						constraint,
+					)
+				}
 			}
 		}
 		return nil
@@ -464,6 +486,20 @@ func (sc *SchemaChanger) addConstraints(
 					}
 					backrefTable.InboundFKs = append(backrefTable.InboundFKs, constraint.ForeignKey)
 				}
+			case sqlbase.ConstraintToUpdate_EXCLUSION:
+				if c, err := scTable.FindExclusionByName(constraint.Name); err == nil {
+					log.VEventf(
+						ctx, 2,
+						"backfiller tried to add constraint %+v but found existing constraint %+v, "+
+							"presumably due to a retry or rollback",
+						constraint, c,
+					)
+					// Ensure the constraint on the descriptor is set to Validating, in
+					// case we're in the middle of rolling back DROP CONSTRAINT
+					c.Validity = sqlbase.ConstraintValidity_Validating
+				} else {
+					scTable.Exclusions = append(scTable.Exclusions, constraint.Exclusion)
+				}
 			}
 		}
 		return nil
@@ -553,6 +589,10 @@ func (sc *SchemaChanger) validateConstraints(
 						// return a different error code in the former case
 						return errors.Wrap(err, "validation of NOT NULL constraint failed")
 					}
+				case sqlbase.ConstraintToUpdate_EXCLUSION:
+					if err := validateExclusionInTxn(ctx, sc.leaseMgr, &evalCtx.EvalContext, desc, txn, c.Name); err != nil {
+						return err
+					}
 				default:
 					return errors.Errorf("unsupported constraint type: %d", c.ConstraintType)
 				}
@@ -1191,6 +1231,9 @@ func (sc *SchemaChanger) validateForwardIndexes(
 					return err
 				}
 				idxLen = int64(tree.MustBeDInt(row[0]))
+				if idx.UniqueDeferrable {
+					return validateDeferrableUnique(ctx, desc.TableDesc(), idx, ie, txn)
+				}
 				return nil
 			}); err != nil {
 				return err
@@ -1323,6 +1366,9 @@ func runSchemaChangesInTxn(
 	doneColumnBackfill := false
 	// Checks are validated after all other mutations have been applied.
 	var constraintsToValidate []sqlbase.ConstraintToUpdate
+	// The DEFERRABLE UNIQUE constraints backed by the new indexes are also
+	// validated at the end.
+	var uniquesToValidate []string
 
 	// We use a range loop here as the processing of some mutations
 	// such as the primary key swap mutations result in queueing more
@@ -1346,9 +1392,12 @@ func runSchemaChangesInTxn(
 				doneColumnBackfill = true
 
 			case *sqlbase.DescriptorMutation_Index:
-				if err := indexBackfillInTxn(ctx, planner.Txn(), immutDesc, traceKV); err != nil {
+				if err := indexBackfillInTxn(ctx, planner.Txn(), planner.EvalContext(), immutDesc, traceKV); err != nil {
 					return err
 				}
+				if t.Index.UniqueDeferrable {
+					uniquesToValidate = append(uniquesToValidate, t.Index.Name)
+				}
 
 			case *sqlbase.DescriptorMutation_Constraint:
 				switch t.Constraint.ConstraintType {
@@ -1381,6 +1430,11 @@ func runSchemaChangesInTxn(
 							return err
 						}
 					}
+				case sqlbase.ConstraintToUpdate_EXCLUSION:
+					// Unvalidated constraints are added by MakeMutationComplete.
+					if t.Constraint.Exclusion.Validity == sqlbase.ConstraintValidity_Validating {
+						tableDesc.Exclusions = append(tableDesc.Exclusions, t.Constraint.Exclusion)
+					}
 				default:
 					return errors.AssertionFailedf(
 						"unsupported constraint type: %d", errors.Safe(t.Constraint.ConstraintType))
@@ -1433,6 +1487,13 @@ func runSchemaChangesInTxn(
 							break
 						}
 					}
+				case sqlbase.ConstraintToUpdate_EXCLUSION:
+					for i := range tableDesc.Exclusions {
+						if tableDesc.Exclusions[i].Name == t.Constraint.Name {
+							tableDesc.Exclusions = append(tableDesc.Exclusions[:i], tableDesc.Exclusions[i+1:]...)
+							break
+						}
+					}
 				default:
 					return errors.AssertionFailedf(
 						"unsupported constraint type: %d", errors.Safe(t.Constraint.ConstraintType))
@@ -1529,11 +1590,26 @@ func runSchemaChangesInTxn(
 					break
 				}
 			}
+		case sqlbase.ConstraintToUpdate_EXCLUSION:
+			if c.Exclusion.Validity == sqlbase.ConstraintValidity_Validating {
+				if err := validateExclusionInTxn(
+					ctx, planner.Tables().leaseMgr, planner.EvalContext(), tableDesc, planner.txn, c.Name,
+				); err != nil {
+					return err
+				}
+			}
 		default:
 			return errors.AssertionFailedf(
 				"unsupported constraint type: %d", errors.Safe(c.ConstraintType))
 		}
 	}
+	for _, name := range uniquesToValidate {
+		if err := validateDeferrableUniqueInTxn(
+			ctx, planner.Tables().leaseMgr, planner.EvalContext(), tableDesc, planner.txn, name,
+		); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1633,6 +1709,84 @@ func validateFkInTxn(
 	return validateForeignKey(ctx, tableDesc.TableDesc(), fk, ie, txn)
 }
 
+// validateExclusionInTxn validates exclusion constraints within the provided
+// transaction. If the provided table descriptor version is newer than the
+// cluster version, it will be used in the InternalExecutor that performs the
+// validation query.
+//
+// It operates entirely on the current goroutine and is thus able to
+// reuse an existing kv.Txn safely.
+func validateExclusionInTxn(
+	ctx context.Context,
+	leaseMgr *LeaseManager,
+	evalCtx *tree.EvalContext,
+	tableDesc *MutableTableDescriptor,
+	txn *kv.Txn,
+	name string,
+) error {
+	ie := evalCtx.InternalExecutor.(*InternalExecutor)
+	if tableDesc.Version > tableDesc.ClusterVersion.Version {
+		newTc := &TableCollection{
+			leaseMgr: leaseMgr,
+			settings: evalCtx.Settings,
+		}
+		// pretend that the schema has been modified.
+		if err := newTc.addUncommittedTable(*tableDesc); err != nil {
+			return err
+		}
+
+		ie.tcModifier = newTc
+		defer func() {
+			ie.tcModifier = nil
+		}()
+	}
+
+	c, err := tableDesc.FindExclusionByName(name)
+	if err != nil {
+		return err
+	}
+	return validateExclusion(ctx, tableDesc.TableDesc(), c, ie, txn)
+}
+
+// validateDeferrableUniqueInTxn validates the DEFERRABLE UNIQUE constraint
+// backed by the index with the given name within the provided transaction. If
+// the provided table descriptor version is newer than the cluster version, it
+// will be used in the InternalExecutor that performs the validation query.
+//
+// It operates entirely on the current goroutine and is thus able to
+// reuse an existing kv.Txn safely.
+func validateDeferrableUniqueInTxn(
+	ctx context.Context,
+	leaseMgr *LeaseManager,
+	evalCtx *tree.EvalContext,
+	tableDesc *MutableTableDescriptor,
+	txn *kv.Txn,
+	name string,
+) error {
+	ie := evalCtx.InternalExecutor.(*InternalExecutor)
+	if tableDesc.Version > tableDesc.ClusterVersion.Version {
+		newTc := &TableCollection{
+			leaseMgr: leaseMgr,
+			settings: evalCtx.Settings,
+		}
+		// pretend that the schema has been modified.
+		if err := newTc.addUncommittedTable(*tableDesc); err != nil {
+			return err
+		}
+
+		ie.tcModifier = newTc
+		defer func() {
+			ie.tcModifier = nil
+		}()
+	}
+
+	idx, _, err := tableDesc.FindIndexByName(name)
+	if err != nil {
+		return err
+	}
+	return validateDeferrableUnique(ctx, tableDesc.TableDesc(), idx, ie, txn)
+}
+
 // columnBackfillInTxn backfills columns for all mutation columns in
 // the mutation list.
 //
@@ -1698,10 +1852,14 @@ func columnBackfillInTxn(
 // It operates entirely on the current goroutine and is thus able to
 // reuse an existing kv.Txn safely.
 func indexBackfillInTxn(
-	ctx context.Context, txn *kv.Txn, tableDesc *sqlbase.ImmutableTableDescriptor, traceKV bool,
+	ctx context.Context,
+	txn *kv.Txn,
+	evalCtx *tree.EvalContext,
+	tableDesc *sqlbase.ImmutableTableDescriptor,
+	traceKV bool,
 ) error {
 	var backfiller backfill.IndexBackfiller
-	if err := backfiller.Init(tableDesc); err != nil {
+	if err := backfiller.Init(evalCtx, tableDesc); err != nil {
 		return err
 	}
 	sp := tableDesc.PrimaryIndexSpan()
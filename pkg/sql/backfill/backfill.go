@@ -109,9 +109,13 @@ func (cb *ColumnBackfiller) Init(
 		cb.updateExprs[j+len(cb.added)] = tree.DNull
 	}
 
-	// We need all the columns.
+	// We need all the stored columns.
 	var valNeededForCol util.FastIntSet
-	valNeededForCol.AddRange(0, len(desc.Columns)-1)
+	for i := range desc.Columns {
+		if !desc.Columns[i].Virtual {
+			valNeededForCol.Add(i)
+		}
+	}
 
 	tableArgs := row.FetcherTableArgs{
 		Desc:            desc,
@@ -299,6 +303,13 @@ type IndexBackfiller struct {
 
 	types   []types.T
 	rowVals tree.Datums
+
+	// virtualExprs contains the computed expressions of the virtual columns
+	// needed by the added indexes, indexed like the fetched columns. Virtual
+	// columns are not stored in the primary index, so their values are
+	// computed from the other fetched columns.
+	virtualExprs []tree.TypedExpr
+	evalCtx      *tree.EvalContext
 }
 
 // ContainsInvertedIndex returns true if backfilling an inverted index.
@@ -312,7 +323,10 @@ func (ib *IndexBackfiller) ContainsInvertedIndex() bool {
 }
 
 // Init initializes an IndexBackfiller.
-func (ib *IndexBackfiller) Init(desc *sqlbase.ImmutableTableDescriptor) error {
+func (ib *IndexBackfiller) Init(
+	evalCtx *tree.EvalContext, desc *sqlbase.ImmutableTableDescriptor,
+) error {
+	ib.evalCtx = evalCtx
 	numCols := len(desc.Columns)
 	cols := desc.Columns
 	if len(desc.Mutations) > 0 {
@@ -346,6 +360,32 @@ func (ib *IndexBackfiller) Init(desc *sqlbase.ImmutableTableDescriptor) error {
 		}
 	}
 
+	// Virtual columns are computed from all the stored columns rather than
+	// fetched.
+	var needVirtual bool
+	for i := range cols {
+		if cols[i].Virtual && valNeededForCol.Contains(i) {
+			needVirtual = true
+			valNeededForCol.Remove(i)
+		}
+	}
+	if needVirtual {
+		var txCtx transform.ExprTransformContext
+		computedExprs, err := sqlbase.MakeComputedExprs(cols, desc,
+			tree.NewUnqualifiedTableName(tree.Name(desc.Name)), &txCtx, ib.evalCtx, false /* addingCols */)
+		if err != nil {
+			return err
+		}
+		ib.virtualExprs = make([]tree.TypedExpr, len(cols))
+		for i := range cols {
+			if cols[i].Virtual {
+				ib.virtualExprs[i] = computedExprs[i]
+			} else {
+				valNeededForCol.Add(i)
+			}
+		}
+	}
+
 	ib.types = make([]types.T, len(cols))
 	for i := range cols {
 		ib.types[i] = cols[i].Type
@@ -403,6 +443,15 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 		return nil, nil, err
 	}
 
+	var iv *sqlbase.RowIndexedVarContainer
+	if ib.virtualExprs != nil {
+		iv = &sqlbase.RowIndexedVarContainer{
+			Cols:    tableDesc.Columns,
+			Mapping: ib.colIdxMap,
+		}
+		ib.evalCtx.IVarContainer = iv
+	}
+
 	buffer := make([]sqlbase.IndexEntry, len(ib.added))
 	for i := int64(0); i < chunkSize; i++ {
 		encRow, _, _, err := ib.fetcher.NextRow(ctx)
@@ -418,6 +467,19 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 		if err := sqlbase.EncDatumRowToDatums(ib.types, ib.rowVals, encRow, &ib.alloc); err != nil {
 			return nil, nil, err
 		}
+		if iv != nil {
+			iv.CurSourceRow = ib.rowVals
+			for j, e := range ib.virtualExprs {
+				if e == nil {
+					continue
+				}
+				val, err := e.Eval(ib.evalCtx)
+				if err != nil {
+					return nil, nil, sqlbase.NewInvalidSchemaDefinitionError(err)
+				}
+				ib.rowVals[j] = val
+			}
+		}
 
 		// We're resetting the length of this slice for variable length indexes such as inverted
 		// indexes which can append entries to the end of the slice. If we don't do this, then everything
@@ -314,6 +314,198 @@ func formatValues(colNames []string, values tree.Datums) string {
 	return pairs.String()
 }
 
+// qualifyColumnRefs returns a copy of the expression in which all unqualified
+// column references are qualified with the given table alias.
+func qualifyColumnRefs(expr tree.Expr, alias string) (tree.Expr, error) {
+	return tree.SimpleVisit(expr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if n, ok := expr.(*tree.UnresolvedName); ok && n.NumParts == 1 && !n.Star {
+			return false, tree.NewUnresolvedName(alias, n.Parts[0]), nil
+		}
+		return true, expr, nil
+	})
+}
+
+// exclusionConflictQuery generates and returns a query for pairs of distinct
+// rows in the table that conflict under the given exclusion constraint. The
+// query returns the primary key columns of both rows.
+//
+// For example, the constraint EXCLUDE (room WITH =, tsrange(s, e) WITH &&) on
+// a table with primary key id would require the following query:
+//
+// SELECT a.id, b.id FROM [53 AS a], [53 AS b]
+// WHERE a.id != b.id AND a.room = b.room AND
+//   (a.s IS NULL OR b.e IS NULL OR a.s < b.e) AND ...
+// LIMIT 1
+//
+func exclusionConflictQuery(
+	tableDesc *sqlbase.TableDescriptor, c *sqlbase.TableDescriptor_ExclusionConstraint,
+) (sql string, pkColNames []string, _ error) {
+	pkColNames = tableDesc.PrimaryIndex.ColumnNames
+	cols := make([]string, 0, 2*len(pkColNames))
+	var conds []tree.Expr
+	for _, alias := range []string{"a", "b"} {
+		for _, n := range pkColNames {
+			cols = append(cols, fmt.Sprintf("%s.%s", alias, tree.NameString(n)))
+		}
+	}
+	// The two rows must be distinct.
+	var distinct tree.Expr
+	for _, n := range pkColNames {
+		ne := &tree.ComparisonExpr{
+			Operator: tree.NE,
+			Left:     tree.NewUnresolvedName("a", n),
+			Right:    tree.NewUnresolvedName("b", n),
+		}
+		if distinct == nil {
+			distinct = ne
+		} else {
+			distinct = &tree.OrExpr{Left: distinct, Right: ne}
+		}
+	}
+	conds = append(conds, distinct)
+
+	// qualify parses the given expression twice, once qualified by each of the
+	// table aliases.
+	qualify := func(expr tree.Expr) (a, b tree.Expr, err error) {
+		if a, err = qualifyColumnRefs(expr, "a"); err != nil {
+			return nil, nil, err
+		}
+		if b, err = qualifyColumnRefs(expr, "b"); err != nil {
+			return nil, nil, err
+		}
+		return a, b, nil
+	}
+
+	def, err := c.TableDef()
+	if err != nil {
+		return "", nil, err
+	}
+	if def.Where != nil {
+		a, b, err := qualify(def.Where)
+		if err != nil {
+			return "", nil, err
+		}
+		conds = append(conds, a, b)
+	}
+	for _, elem := range def.Elems {
+		r, isRange, err := sqlbase.MakeExclusionRange(elem.Expr)
+		if err != nil {
+			return "", nil, err
+		}
+		if !isRange {
+			a, b, err := qualify(elem.Expr)
+			if err != nil {
+				return "", nil, err
+			}
+			conds = append(conds, &tree.ComparisonExpr{Operator: elem.Operator, Left: a, Right: b})
+			continue
+		}
+		loA, loB, err := qualify(r.Lower)
+		if err != nil {
+			return "", nil, err
+		}
+		hiA, hiB, err := qualify(r.Upper)
+		if err != nil {
+			return "", nil, err
+		}
+		conds = append(conds, r.OverlapsExpr(loA, hiA, loB, hiB))
+	}
+
+	where := make([]string, len(conds))
+	for i := range conds {
+		where[i] = "(" + tree.AsStringWithFlags(conds[i], tree.FmtParsable) + ")"
+	}
+	return fmt.Sprintf(
+		`SELECT %[1]s FROM [%[2]d AS a], [%[2]d AS b] WHERE %[3]s LIMIT 1`,
+		strings.Join(cols, ", "),     // 1
+		tableDesc.ID,                 // 2
+		strings.Join(where, " AND "), // 3
+	), pkColNames, nil
+}
+
+// validateExclusion verifies that no two rows in the table conflict under the
+// given exclusion constraint.
+//
+// It operates entirely on the current goroutine and is thus able to
+// reuse an existing client.Txn safely.
+func validateExclusion(
+	ctx context.Context,
+	tableDesc *sqlbase.TableDescriptor,
+	c *sqlbase.TableDescriptor_ExclusionConstraint,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+) error {
+	query, colNames, err := exclusionConflictQuery(tableDesc, c)
+	if err != nil {
+		return err
+	}
+	log.Infof(ctx, "Validating exclusion constraint %q with query %q", c.Name, query)
+
+	values, err := ie.QueryRow(ctx, "validate exclusion constraint", txn, query)
+	if err != nil {
+		return err
+	}
+	if values.Len() > 0 {
+		n := len(colNames)
+		return pgerror.Newf(pgcode.ExclusionViolation,
+			"validation of EXCLUDE constraint %q failed: row %s conflicts with row %s",
+			c.Name, formatValues(colNames, values[:n]), formatValues(colNames, values[n:]))
+	}
+	return nil
+}
+
+// duplicateKeyQuery generates a query that returns the first key that is used
+// by more than one row of the table on the columns of the index. NULL keys are
+// ignored. For example, for an index on columns a and b:
+//
+//   SELECT a, b FROM [<table ID> AS t]
+//   WHERE a IS NOT NULL AND b IS NOT NULL
+//   GROUP BY a, b HAVING count(*) > 1 LIMIT 1
+//
+func duplicateKeyQuery(tableDesc *sqlbase.TableDescriptor, idx *sqlbase.IndexDescriptor) string {
+	cols := make([]string, len(idx.ColumnNames))
+	notNull := make([]string, len(idx.ColumnNames))
+	for i := range idx.ColumnNames {
+		cols[i] = tree.NameString(idx.ColumnNames[i])
+		notNull[i] = cols[i] + " IS NOT NULL"
+	}
+	return fmt.Sprintf(
+		`SELECT %[1]s FROM [%[2]d AS t] WHERE %[3]s GROUP BY %[1]s HAVING count(*) > 1 LIMIT 1`,
+		strings.Join(cols, ", "),       // 1
+		tableDesc.ID,                   // 2
+		strings.Join(notNull, " AND "), // 3
+	)
+}
+
+// validateDeferrableUnique verifies that no two rows in the table have the
+// same key for the DEFERRABLE UNIQUE constraint backed by the given index.
+// Such an index is not unique, so the duplicate keys are not detected by the
+// backfill.
+//
+// It operates entirely on the current goroutine and is thus able to
+// reuse an existing client.Txn safely.
+func validateDeferrableUnique(
+	ctx context.Context,
+	tableDesc *sqlbase.TableDescriptor,
+	idx *sqlbase.IndexDescriptor,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+) error {
+	query := duplicateKeyQuery(tableDesc, idx)
+	log.Infof(ctx, "Validating unique constraint %q with query %q", idx.Name, query)
+
+	values, err := ie.QueryRow(ctx, "validate unique constraint", txn, query)
+	if err != nil {
+		return err
+	}
+	if values.Len() > 0 {
+		return pgerror.Newf(pgcode.UniqueViolation,
+			"validation of UNIQUE constraint %q failed: key %s is duplicated",
+			idx.Name, formatValues(idx.ColumnNames, values))
+	}
+	return nil
+}
+
 // checkSet contains a subset of checks, as ordinals into
 // ImmutableTableDescriptor.ActiveChecks. These checks have boolean columns
 // produced as input to mutations, indicating the result of evaluating the
@@ -253,6 +253,14 @@ func makeAggregateFuncs(
 			funcs[i] = newBoolAndAgg()
 		case execinfrapb.AggregatorSpec_BOOL_OR:
 			funcs[i] = newBoolOrAgg()
+		case execinfrapb.AggregatorSpec_RANK_IMPL:
+			funcs[i] = newHypotheticalSetAgg(hypotheticalRank)
+		case execinfrapb.AggregatorSpec_DENSE_RANK_IMPL:
+			funcs[i], err = newHypotheticalDenseRankAgg(aggTyps[i][1])
+		case execinfrapb.AggregatorSpec_PERCENT_RANK_IMPL:
+			funcs[i] = newHypotheticalSetAgg(hypotheticalPercentRank)
+		case execinfrapb.AggregatorSpec_CUME_DIST_IMPL:
+			funcs[i] = newHypotheticalSetAgg(hypotheticalCumeDist)
 		default:
 			return nil, errors.Errorf("unsupported columnar aggregate function %s", aggFns[i].String())
 		}
@@ -277,6 +285,10 @@ func makeAggregateFuncsOutputTypes(
 			// TODO(jordan): this is a somewhat of a hack. The aggregate functions
 			// should come with their own output types, somehow.
 			outTyps[i] = coltypes.Int64
+		case execinfrapb.AggregatorSpec_RANK_IMPL, execinfrapb.AggregatorSpec_DENSE_RANK_IMPL:
+			outTyps[i] = coltypes.Int64
+		case execinfrapb.AggregatorSpec_PERCENT_RANK_IMPL, execinfrapb.AggregatorSpec_CUME_DIST_IMPL:
+			outTyps[i] = coltypes.Float64
 		case
 			execinfrapb.AggregatorSpec_ANY_NOT_NULL,
 			execinfrapb.AggregatorSpec_AVG,
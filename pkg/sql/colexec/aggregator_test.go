@@ -444,6 +444,63 @@ func TestAggregatorMultiFunc(t *testing.T) {
 			},
 			name: "BoolAndOrBatch",
 		},
+		{
+			aggFns: []execinfrapb.AggregatorSpec_Func{
+				execinfrapb.AggregatorSpec_RANK_IMPL,
+				execinfrapb.AggregatorSpec_PERCENT_RANK_IMPL,
+				execinfrapb.AggregatorSpec_CUME_DIST_IMPL,
+			},
+			aggCols: [][]uint32{
+				{1}, {1}, {1},
+			},
+			input: tuples{
+				{0, true},
+				{1, false},
+				{2, true},
+				{2, false},
+				{2, true},
+				{2, false},
+				{3, nil},
+				{3, true},
+				{4, nil},
+			},
+			colTypes: []coltypes.T{coltypes.Int64, coltypes.Bool},
+			expected: tuples{
+				{2, 1.0, 1.0},
+				{1, 0.0, 0.5},
+				{3, 0.5, 0.6},
+				{2, 1.0, 1.0},
+				{1, 0.0, 1.0},
+			},
+			name: "HypotheticalSetAggregates",
+		},
+		{
+			aggFns: []execinfrapb.AggregatorSpec_Func{
+				execinfrapb.AggregatorSpec_DENSE_RANK_IMPL,
+			},
+			aggCols: [][]uint32{
+				{1, 2},
+			},
+			input: tuples{
+				{0, true, 1},
+				{0, true, 1},
+				{0, true, 2},
+				{0, false, 3},
+				{1, false, 5},
+				{1, nil, 5},
+				{2, true, nil},
+				{2, true, nil},
+				{2, true, 4},
+				{2, true, 4},
+			},
+			colTypes: []coltypes.T{coltypes.Int64, coltypes.Bool, coltypes.Int64},
+			expected: tuples{
+				{3},
+				{1},
+				{3},
+			},
+			name: "HypotheticalDenseRank",
+		},
 		{
 			aggFns: []execinfrapb.AggregatorSpec_Func{
 				execinfrapb.AggregatorSpec_ANY_NOT_NULL,
@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
@@ -44,6 +45,10 @@ type colBatchScan struct {
 	// maxResults is non-zero if there is a limit on the total number of rows
 	// that the colBatchScan will read.
 	maxResults uint64
+	// tableID and indexID identify the scanned index, for the index usage
+	// statistics.
+	tableID sqlbase.ID
+	indexID sqlbase.IndexID
 	// init is true after Init() has been called.
 	init bool
 }
@@ -56,6 +61,7 @@ func (s *colBatchScan) Init() {
 
 	limitBatches := execinfra.ScanShouldLimitBatches(s.maxResults, s.limitHint, s.flowCtx)
 
+	s.flowCtx.Cfg.IndexUsageStats.RecordRead(s.tableID, s.indexID, idxusage.ScanRead)
 	if err := s.rf.StartScan(
 		s.ctx, s.flowCtx.Txn, s.spans,
 		limitBatches, s.limitHint, s.flowCtx.TraceKV,
@@ -125,10 +131,11 @@ func newColBatchScan(
 
 	columnIdxMap := spec.Table.ColumnIdxMapWithMutations(returnMutations)
 	fetcher := cFetcher{}
-	if _, _, err := initCRowFetcher(
+	index, _, err := initCRowFetcher(
 		allocator, &fetcher, &spec.Table, int(spec.IndexIdx), columnIdxMap, spec.Reverse,
 		neededColumns, spec.IsCheck, spec.Visibility, spec.LockingStrength,
-	); err != nil {
+	)
+	if err != nil {
 		return nil, err
 	}
 
@@ -143,6 +150,8 @@ func newColBatchScan(
 		rf:         &fetcher,
 		limitHint:  limitHint,
 		maxResults: spec.MaxResults,
+		tableID:    spec.Table.ID,
+		indexID:    index.ID,
 	}, nil
 }
 
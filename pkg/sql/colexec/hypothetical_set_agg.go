@@ -0,0 +1,307 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// hypotheticalSetAggKind distinguishes the hypothetical-set aggregates that
+// are supported by hypotheticalSetAgg.
+type hypotheticalSetAggKind int
+
+const (
+	// hypotheticalRank is RANK_IMPL, which returns one plus the number of rows
+	// for which the input is true.
+	hypotheticalRank hypotheticalSetAggKind = iota
+	// hypotheticalPercentRank is PERCENT_RANK_IMPL, which returns the fraction
+	// of non-NULL input rows for which the input is true.
+	hypotheticalPercentRank
+	// hypotheticalCumeDist is CUME_DIST_IMPL, which returns the fraction of the
+	// non-NULL input rows for which the input is true, counting one additional
+	// row (the hypothetical row itself) in both.
+	hypotheticalCumeDist
+)
+
+// newHypotheticalSetAgg creates an aggregate for the given kind of
+// hypothetical-set aggregate. The input of these aggregates is a boolean column
+// that indicates whether the row sorts before the hypothetical row (or, for
+// CUME_DIST_IMPL, whether it does not sort after it).
+func newHypotheticalSetAgg(kind hypotheticalSetAggKind) *hypotheticalSetAgg {
+	return &hypotheticalSetAgg{kind: kind}
+}
+
+// hypotheticalSetAgg supports the RANK_IMPL, PERCENT_RANK_IMPL and
+// CUME_DIST_IMPL aggregates, which are distinguished by kind. The output
+// column is an Int64 column for RANK_IMPL and a Float64 column otherwise.
+type hypotheticalSetAgg struct {
+	kind     hypotheticalSetAggKind
+	groups   []bool
+	intVec   []int64
+	floatVec []float64
+	nulls    *coldata.Nulls
+	curIdx   int
+	// count is the number of rows in the current group for which the input is
+	// true, and total is the number of rows with a non-NULL input.
+	count int64
+	total int64
+	done  bool
+}
+
+var _ aggregateFunc = &hypotheticalSetAgg{}
+
+func (a *hypotheticalSetAgg) Init(groups []bool, vec coldata.Vec) {
+	a.groups = groups
+	if a.kind == hypotheticalRank {
+		a.intVec = vec.Int64()
+	} else {
+		a.floatVec = vec.Float64()
+	}
+	a.nulls = vec.Nulls()
+	a.Reset()
+}
+
+func (a *hypotheticalSetAgg) Reset() {
+	a.curIdx = -1
+	a.count = 0
+	a.total = 0
+	a.nulls.UnsetNulls()
+	a.done = false
+}
+
+func (a *hypotheticalSetAgg) CurrentOutputIndex() int {
+	return a.curIdx
+}
+
+func (a *hypotheticalSetAgg) SetOutputIndex(idx int) {
+	if a.curIdx != -1 {
+		a.curIdx = idx
+		a.nulls.UnsetNullsAfter(idx + 1)
+	}
+}
+
+func (a *hypotheticalSetAgg) Compute(b coldata.Batch, inputIdxs []uint32) {
+	if a.done {
+		return
+	}
+	inputLen := b.Length()
+	if inputLen == 0 {
+		a.setOutput(a.curIdx)
+		a.curIdx++
+		a.done = true
+		return
+	}
+	vec, sel := b.ColVec(int(inputIdxs[0])), b.Selection()
+	col, nulls := vec.Bool(), vec.Nulls()
+	if sel != nil {
+		for _, i := range sel[:inputLen] {
+			a.accumulate(col, nulls, i)
+		}
+	} else {
+		for i := range col[:inputLen] {
+			a.accumulate(col, nulls, i)
+		}
+	}
+}
+
+// accumulate aggregates the boolean value at index i into the current group,
+// flushing the result of the previous group if a new group starts at i.
+func (a *hypotheticalSetAgg) accumulate(col []bool, nulls *coldata.Nulls, i int) {
+	if a.groups[i] {
+		if a.curIdx != -1 {
+			a.setOutput(a.curIdx)
+		}
+		a.curIdx++
+		a.count = 0
+		a.total = 0
+	}
+	if !nulls.NullAt(i) {
+		if col[i] {
+			a.count++
+		}
+		a.total++
+	}
+}
+
+// setOutput writes the result of the current group to the given index of the
+// output column.
+func (a *hypotheticalSetAgg) setOutput(idx int) {
+	switch a.kind {
+	case hypotheticalRank:
+		a.intVec[idx] = a.count + 1
+	case hypotheticalPercentRank:
+		if a.total == 0 {
+			a.floatVec[idx] = 0
+		} else {
+			a.floatVec[idx] = float64(a.count) / float64(a.total)
+		}
+	case hypotheticalCumeDist:
+		a.floatVec[idx] = float64(a.count+1) / float64(a.total+1)
+	}
+}
+
+func (a *hypotheticalSetAgg) HandleEmptyInputScalar() {
+	a.count = 0
+	a.total = 0
+	a.setOutput(0)
+}
+
+// newHypotheticalDenseRankAgg creates a DENSE_RANK_IMPL aggregate whose key
+// column has the given type.
+func newHypotheticalDenseRankAgg(keyTyp coltypes.T) (*hypotheticalDenseRankAgg, error) {
+	switch keyTyp {
+	case coltypes.Bool, coltypes.Bytes, coltypes.Decimal, coltypes.Int16, coltypes.Int32,
+		coltypes.Int64, coltypes.Float64, coltypes.Timestamp, coltypes.Interval:
+	default:
+		return nil, errors.Errorf("unsupported dense_rank_impl key type %s", keyTyp)
+	}
+	return &hypotheticalDenseRankAgg{keyTyp: keyTyp}, nil
+}
+
+// hypotheticalDenseRankAgg supports the DENSE_RANK_IMPL aggregate, which
+// returns one plus the number of distinct keys of the rows for which the first
+// input is true. The second input is the key column, whose values are counted
+// by their key encoding, so that values that compare equal (like 1.0 and 1.00)
+// are counted once. The output column is an Int64 column.
+type hypotheticalDenseRankAgg struct {
+	keyTyp coltypes.T
+	groups []bool
+	vec    []int64
+	nulls  *coldata.Nulls
+	curIdx int
+	// seen contains the encodings of the keys counted in the current group.
+	seen    map[string]struct{}
+	scratch []byte
+	done    bool
+}
+
+var _ aggregateFunc = &hypotheticalDenseRankAgg{}
+
+func (a *hypotheticalDenseRankAgg) Init(groups []bool, vec coldata.Vec) {
+	a.groups = groups
+	a.vec = vec.Int64()
+	a.nulls = vec.Nulls()
+	a.Reset()
+}
+
+func (a *hypotheticalDenseRankAgg) Reset() {
+	a.curIdx = -1
+	a.seen = make(map[string]struct{})
+	a.nulls.UnsetNulls()
+	a.done = false
+}
+
+func (a *hypotheticalDenseRankAgg) CurrentOutputIndex() int {
+	return a.curIdx
+}
+
+func (a *hypotheticalDenseRankAgg) SetOutputIndex(idx int) {
+	if a.curIdx != -1 {
+		a.curIdx = idx
+		a.nulls.UnsetNullsAfter(idx + 1)
+	}
+}
+
+func (a *hypotheticalDenseRankAgg) Compute(b coldata.Batch, inputIdxs []uint32) {
+	if a.done {
+		return
+	}
+	inputLen := b.Length()
+	if inputLen == 0 {
+		a.vec[a.curIdx] = int64(len(a.seen)) + 1
+		a.curIdx++
+		a.done = true
+		return
+	}
+	precedesVec, keyVec := b.ColVec(int(inputIdxs[0])), b.ColVec(int(inputIdxs[1]))
+	precedes, precedesNulls := precedesVec.Bool(), precedesVec.Nulls()
+	if sel := b.Selection(); sel != nil {
+		for _, i := range sel[:inputLen] {
+			a.accumulate(precedes, precedesNulls, keyVec, i)
+		}
+	} else {
+		for i := range precedes[:inputLen] {
+			a.accumulate(precedes, precedesNulls, keyVec, i)
+		}
+	}
+}
+
+// accumulate counts the key at index i if it belongs to a row that sorts
+// before the hypothetical row, flushing the result of the previous group if a
+// new group starts at i.
+func (a *hypotheticalDenseRankAgg) accumulate(
+	precedes []bool, precedesNulls *coldata.Nulls, keyVec coldata.Vec, i int,
+) {
+	if a.groups[i] {
+		if a.curIdx != -1 {
+			a.vec[a.curIdx] = int64(len(a.seen)) + 1
+		}
+		a.curIdx++
+		a.seen = make(map[string]struct{})
+	}
+	if precedesNulls.NullAt(i) || !precedes[i] {
+		return
+	}
+	a.scratch = a.encodeKey(a.scratch[:0], keyVec, i)
+	if _, ok := a.seen[string(a.scratch)]; !ok {
+		a.seen[string(a.scratch)] = struct{}{}
+	}
+}
+
+// encodeKey appends the key encoding of the value at index i of the key
+// column to b. NULLs are encoded as well, since they form a peer group of
+// their own.
+func (a *hypotheticalDenseRankAgg) encodeKey(b []byte, keyVec coldata.Vec, i int) []byte {
+	if keyVec.Nulls().NullAt(i) {
+		return encoding.EncodeNullAscending(b)
+	}
+	switch a.keyTyp {
+	case coltypes.Bool:
+		if keyVec.Bool()[i] {
+			return encoding.EncodeVarintAscending(b, 1)
+		}
+		return encoding.EncodeVarintAscending(b, 0)
+	case coltypes.Bytes:
+		return encoding.EncodeBytesAscending(b, keyVec.Bytes().Get(i))
+	case coltypes.Decimal:
+		return encoding.EncodeDecimalAscending(b, &keyVec.Decimal()[i])
+	case coltypes.Int16:
+		return encoding.EncodeVarintAscending(b, int64(keyVec.Int16()[i]))
+	case coltypes.Int32:
+		return encoding.EncodeVarintAscending(b, int64(keyVec.Int32()[i]))
+	case coltypes.Int64:
+		return encoding.EncodeVarintAscending(b, keyVec.Int64()[i])
+	case coltypes.Float64:
+		return encoding.EncodeFloatAscending(b, keyVec.Float64()[i])
+	case coltypes.Timestamp:
+		return encoding.EncodeTimeAscending(b, keyVec.Timestamp()[i])
+	case coltypes.Interval:
+		b, err := encoding.EncodeDurationAscending(b, keyVec.Interval()[i])
+		if err != nil {
+			execerror.NonVectorizedPanic(err)
+		}
+		return b
+	default:
+		execerror.VectorizedInternalPanic(fmt.Sprintf("unhandled type %s", a.keyTyp))
+		// This code is unreachable, but the compiler cannot infer that.
+		return nil
+	}
+}
+
+func (a *hypotheticalDenseRankAgg) HandleEmptyInputScalar() {
+	a.vec[0] = 1
+}
@@ -252,6 +252,10 @@ type Server struct {
 	// dbCache is a cache for database descriptors, maintained through Gossip
 	// updates.
 	dbCache *databaseCacheHolder
+
+	// slowQueries tracks the fingerprints of the slow statements of the node
+	// (see sql.log.slow_query.capture.enabled).
+	slowQueries slowQueryCapturer
 }
 
 // Metrics collects timeseries data about SQL activity.
@@ -275,7 +279,7 @@ type Metrics struct {
 // is used.
 func NewServer(cfg *ExecutorConfig, pool *mon.BytesMonitor) *Server {
 	systemCfg := config.NewSystemConfig(cfg.DefaultZoneConfig)
-	return &Server{
+	s := &Server{
 		cfg:             cfg,
 		Metrics:         makeMetrics(false /*internal*/),
 		InternalMetrics: makeMetrics(true /*internal*/),
@@ -286,6 +290,10 @@ func NewServer(cfg *ExecutorConfig, pool *mon.BytesMonitor) *Server {
 		reportedStats: sqlStats{st: cfg.Settings, apps: make(map[string]*appStats)},
 		reCache:       tree.NewRegexpCache(512),
 	}
+	// The node's statistics are persisted when they are reset. The reported
+	// stats are only a copy of them.
+	s.sqlStats.flush = s.persistSQLStats
+	return s
 }
 
 func makeMetrics(internal bool) Metrics {
@@ -318,6 +326,7 @@ func makeMetrics(internal bool) Metrics {
 
 // Start starts the Server's background processing.
 func (s *Server) Start(ctx context.Context, stopper *stop.Stopper) {
+	s.slowQueries.stopper = stopper
 	gossipUpdateC := s.cfg.Gossip.RegisterSystemConfigChannel()
 	stopper.RunWorker(ctx, func(ctx context.Context) {
 		for {
@@ -339,6 +348,9 @@ func (s *Server) Start(ctx context.Context, stopper *stop.Stopper) {
 	s.PeriodicallyClearSQLStats(ctx, stopper, maxSQLStatReset, &s.reportedStats)
 	// Start a second loop to clear SQL stats at the requested interval.
 	s.PeriodicallyClearSQLStats(ctx, stopper, sqlStatReset, &s.sqlStats)
+	// Start a loop to flush the SQL stats into the system tables at the end of
+	// each aggregation interval.
+	s.periodicallyPersistSQLStats(ctx, stopper)
 }
 
 // ResetSQLStats resets the executor's collected sql statistics.
@@ -660,6 +672,7 @@ func (s *Server) newConnExecutor(
 
 	ex.sessionTracing.ex = ex
 	ex.transitionCtx.sessionTracing = &ex.sessionTracing
+	ex.transitionCtx.sessionData = ex.sessionData
 	ex.statsCollector = ex.newStatsCollector()
 	ex.initPlanner(ctx, &ex.planner)
 
@@ -728,7 +741,9 @@ func (s *Server) newConnExecutorWithTxn(
 var sqlStatReset = settings.RegisterPublicNonNegativeDurationSettingWithMaximum(
 	"diagnostics.sql_stat_reset.interval",
 	"interval controlling how often SQL statement statistics should "+
-		"be reset (should be less than diagnostics.forced_sql_stat_reset.interval). It has a max value of 24H.",
+		"be reset (should be less than diagnostics.forced_sql_stat_reset.interval). It has a max value of 24H. "+
+		"If sql.metrics.persisted_stats.enabled is set, the statistics are also reset at the end of "+
+		"each sql.metrics.persisted_stats.aggregation_interval.",
 	time.Hour,
 	time.Hour*24,
 )
@@ -998,8 +1013,28 @@ type connExecutor struct {
 		// processing the command at position txnRewindPos. When rewinding, we're
 		// going to restore this snapshot.
 		savepointsAtTxnRewindPos savepointStack
+
+		// deferredConstraints holds the state of the deferred constraints of the
+		// transaction (see SET CONSTRAINTS).
+		deferredConstraints deferredConstraints
+		// deferredConstraintsAtTxnRewindPos is a snapshot of deferredConstraints
+		// before processing the command at position txnRewindPos.
+		deferredConstraintsAtTxnRewindPos deferredConstraints
+
+		// onCommitTempTables holds the temporary tables created by the
+		// transaction with ON COMMIT DELETE ROWS or ON COMMIT DROP.
+		onCommitTempTables onCommitTempTables
+		// onCommitTempTablesAtTxnRewindPos is a snapshot of onCommitTempTables
+		// before processing the command at position txnRewindPos.
+		onCommitTempTablesAtTxnRewindPos onCommitTempTables
 	}
 
+	// deleteRowsTempTables is the set of the IDs of the temporary tables
+	// created by the session with ON COMMIT DELETE ROWS. Their rows are deleted
+	// before each transaction that wrote to them commits (see
+	// runTempTableOnCommitActions).
+	deleteRowsTempTables util.FastIntSet
+
 	// sessionData contains the user-configurable connection variables.
 	sessionData *sessiondata.SessionData
 	// dataMutator is nil for session-bound internal executors; we shouldn't issue
@@ -1175,6 +1210,8 @@ func (ex *connExecutor) resetExtraTxnState(
 	switch ev {
 	case txnCommit, txnRollback:
 		ex.extraTxnState.savepoints.clear()
+		ex.extraTxnState.deferredConstraints.reset()
+		ex.extraTxnState.onCommitTempTables.reset()
 		// After txn is finished, we need to call onTxnFinish (if it's non-nil).
 		if ex.extraTxnState.onTxnFinish != nil {
 			ex.extraTxnState.onTxnFinish(ev)
@@ -1546,6 +1583,8 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 	case rewind:
 		ex.rewindPrepStmtNamespace(ctx)
 		ex.extraTxnState.savepoints = ex.extraTxnState.savepointsAtTxnRewindPos
+		ex.extraTxnState.deferredConstraints = ex.extraTxnState.deferredConstraintsAtTxnRewindPos
+		ex.extraTxnState.onCommitTempTables = ex.extraTxnState.onCommitTempTablesAtTxnRewindPos
 		advInfo.rewCap.rewindAndUnlock(ctx)
 	case stayInPlace:
 		// Nothing to do. The same statement will be executed again.
@@ -1673,6 +1712,8 @@ func (ex *connExecutor) setTxnRewindPos(ctx context.Context, pos CmdPos) {
 	ex.stmtBuf.ltrim(ctx, pos)
 	ex.commitPrepStmtNamespace(ctx)
 	ex.extraTxnState.savepointsAtTxnRewindPos = ex.extraTxnState.savepoints.clone()
+	ex.extraTxnState.deferredConstraintsAtTxnRewindPos = ex.extraTxnState.deferredConstraints.snapshot()
+	ex.extraTxnState.onCommitTempTablesAtTxnRewindPos = ex.extraTxnState.onCommitTempTables.snapshot()
 }
 
 // stmtDoesntNeedRetry returns true if the given statement does not need to be
@@ -1752,8 +1793,10 @@ func (ex *connExecutor) execCopyIn(
 		// state machine, but the copyMachine manages its own transactions without
 		// going through the state machine.
 		ex.state.sqlTimestamp = txnTS
+		previousPhaseTimes := ex.statsCollector.previousPhaseTimes
 		ex.statsCollector = ex.newStatsCollector()
 		ex.statsCollector.reset(&ex.server.sqlStats, ex.appStats, &ex.phaseTimes)
+		ex.statsCollector.previousPhaseTimes = previousPhaseTimes
 		ex.initPlanner(ctx, p)
 		ex.resetPlanner(ctx, p, txn, stmtTS)
 	}
@@ -1985,6 +2028,14 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 		schemaAccessors:   scInterface,
 		sqlStatsCollector: ex.statsCollector,
 	}
+	// The checks of the statements run by internal executors are never
+	// deferred, and they can't create tables with ON COMMIT actions: an
+	// internal executor running under an outer transaction doesn't commit it.
+	if ex.executorType != executorTypeInternal {
+		evalCtx.DeferredConstraints = &ex.extraTxnState.deferredConstraints
+		evalCtx.OnCommitTempTables = &ex.extraTxnState.onCommitTempTables
+		evalCtx.DeleteRowsTempTables = &ex.deleteRowsTempTables
+	}
 }
 
 // resetEvalCtx initializes the fields of evalCtx that can change
@@ -2055,6 +2106,7 @@ func (ex *connExecutor) resetPlanner(
 	p.semaCtx.SearchPath = ex.sessionData.SearchPath
 	p.semaCtx.AsOfTimestamp = nil
 	p.semaCtx.Annotations = nil
+	p.semaCtx.FunctionResolver = p
 
 	ex.resetEvalCtx(&p.extendedEvalCtx, txn, stmtTS)
 
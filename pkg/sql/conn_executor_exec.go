@@ -182,6 +182,20 @@ func (ex *connExecutor) execStmtInOpenState(
 		}
 	}()
 
+	// When the txn's trace goes to an external tracing system, the statement
+	// gets its own span, which allows the statement to be identified there.
+	if sp := opentracing.SpanFromContext(ctx); sp != nil && tracing.HasShadowSpan(sp) {
+		var stmtSp opentracing.Span
+		ctx, stmtSp = tracing.ChildSpan(ctx, "sql query")
+		stmtSp.SetTag("stmt.tag", stmt.AST.StatementTag())
+		fingerprint := stmt.AnonymizedStr
+		if fingerprint == "" {
+			fingerprint = anonymizeStmt(stmt.AST)
+		}
+		stmtSp.SetTag("stmt.fingerprint", fingerprint)
+		defer stmtSp.Finish()
+	}
+
 	p := &ex.planner
 	stmtTS := ex.server.cfg.Clock.PhysicalTime()
 	ex.statsCollector.reset(&ex.server.sqlStats, ex.appStats, &ex.phaseTimes)
@@ -191,6 +205,9 @@ func (ex *connExecutor) execStmtInOpenState(
 
 	var shouldCollectDiagnostics bool
 	var finishCollectionDiagnostics StmtDiagnosticsTraceFinishFunc
+	// captureSlowQuery is set if the statement is traced because a recent
+	// execution of its fingerprint was slow (see slow_query_capture.go).
+	var captureSlowQuery bool
 
 	if explainBundle, ok := stmt.AST.(*tree.ExplainAnalyzeDebug); ok {
 		telemetry.Inc(sqltelemetry.ExplainAnalyzeDebugUseCounter)
@@ -215,6 +232,9 @@ func (ex *connExecutor) execStmtInOpenState(
 		shouldCollectDiagnostics, finishCollectionDiagnostics = ex.stmtDiagnosticsRecorder.ShouldCollectDiagnostics(ctx, stmt.AST)
 		if shouldCollectDiagnostics {
 			telemetry.Inc(sqltelemetry.StatementDiagnosticsCollectedCounter)
+		} else if ex.shouldTraceForSlowQueryCapture(ctx, &stmt) {
+			shouldCollectDiagnostics = true
+			captureSlowQuery = true
 		}
 	}
 
@@ -232,7 +252,9 @@ func (ex *connExecutor) execStmtInOpenState(
 			sp.Finish()
 			trace := tracing.GetRecording(sp)
 			ie := p.extendedEvalCtx.InternalExecutor.(*InternalExecutor)
-			if finishCollectionDiagnostics != nil {
+			if captureSlowQuery {
+				ex.finishSlowQueryCapture(origCtx, p, trace, ie)
+			} else if finishCollectionDiagnostics != nil {
 				bundle, collectionErr := buildStatementBundle(
 					origCtx, ex.server.cfg.DB, ie, &p.curPlan, trace,
 				)
@@ -477,7 +499,12 @@ func (ex *connExecutor) execStmtInOpenState(
 	ex.phaseTimes[plannerStartExecStmt] = timeutil.Now()
 	p.stmt = &stmt
 	p.cancelChecker = sqlbase.NewCancelChecker(ctx)
-	p.autoCommit = os.ImplicitTxn.Get() && !ex.server.cfg.TestingKnobs.DisableAutoCommit
+	// The statements can't commit the transaction themselves if deferred
+	// constraints need to be checked before it commits. Neither can the
+	// statements that write to ON COMMIT DELETE ROWS tables (see
+	// recordTempTableWrite).
+	p.autoCommit = os.ImplicitTxn.Get() && !ex.server.cfg.TestingKnobs.DisableAutoCommit &&
+		!ex.extraTxnState.deferredConstraints.hasKeys()
 	if err := ex.dispatchToExecutionEngine(ctx, p, res); err != nil {
 		return nil, nil, err
 	}
@@ -645,6 +672,15 @@ func (ex *connExecutor) commitSQLTransactionInternal(
 		return err
 	}
 
+	if err := ex.checkDeferredConstraints(ctx, nil /* filter */); err != nil {
+		return err
+	}
+
+	deleteRowsTempTables, err := ex.runTempTableOnCommitActions(ctx)
+	if err != nil {
+		return err
+	}
+
 	if err := ex.checkTableTwoVersionInvariant(ctx); err != nil {
 		return err
 	}
@@ -652,6 +688,7 @@ func (ex *connExecutor) commitSQLTransactionInternal(
 	if err := ex.state.mu.txn.Commit(ctx); err != nil {
 		return err
 	}
+	ex.deleteRowsTempTables = deleteRowsTempTables
 
 	// Now that we've committed, if we modified any table we need to make sure
 	// to release the leases for them so that the schema change can proceed and
@@ -662,6 +699,20 @@ func (ex *connExecutor) commitSQLTransactionInternal(
 	return nil
 }
 
+// checkDeferredConstraints checks the constraints for the keys saved by the
+// deferred checks of the transaction (see deferredConstraints.check).
+func (ex *connExecutor) checkDeferredConstraints(
+	ctx context.Context, filter func(check *deferrableCheck) bool,
+) error {
+	if len(ex.extraTxnState.deferredConstraints.keys) == 0 {
+		return nil
+	}
+	ie := MakeInternalExecutor(ctx, ex.server, ex.memMetrics, ex.server.cfg.Settings)
+	ie.SetSessionData(ex.sessionData)
+	ie.tcModifier = &ex.extraTxnState.tables
+	return ex.extraTxnState.deferredConstraints.check(ctx, &ie, ex.state.mu.txn, filter)
+}
+
 // rollbackSQLTransaction executes a ROLLBACK statement: the KV transaction is
 // rolled-back and an event is produced.
 func (ex *connExecutor) rollbackSQLTransaction(ctx context.Context) (fsm.Event, fsm.EventPayload) {
@@ -723,6 +774,19 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 		return nil
 	}
 
+	// Historical reads may ask for their timestamp to be protected from
+	// garbage collection for as long as they run. This covers both AS OF SYSTEM
+	// TIME statements and every statement of a BEGIN AS OF SYSTEM TIME
+	// transaction, which read at the transaction's fixed timestamp.
+	if ex.sessionData.ProtectReadTimestamp && ex.state.isHistorical {
+		release, err := ex.protectReadTimestamp(ctx, planner, ex.state.getReadTimestamp())
+		if err != nil {
+			res.SetError(err)
+			return nil
+		}
+		defer release()
+	}
+
 	var cols sqlbase.ResultColumns
 	if stmt.AST.StatementType() == tree.Rows {
 		cols = planColumns(planner.curPlan.plan)
@@ -773,16 +837,32 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 		planner.curPlan.flags.Set(planFlagDistSQLLocal)
 	}
 	ex.sessionTracing.TraceExecStart(ctx, "distributed")
-	bytesRead, rowsRead, err := ex.execWithDistSQLEngine(ctx, planner, stmt.AST.StatementType(), res, distributePlan, progAtomic)
+	execCtx, contentionTrace := ex.maybeStartContentionTrace(ctx)
+	if contentionTrace != nil {
+		// The eval context must use the traced context too, so that the
+		// contention events of the KV requests it issues are recorded.
+		planner.extendedEvalCtx.Context = execCtx
+	}
+	bytesRead, rowsRead, err := ex.execWithDistSQLEngine(execCtx, planner, stmt.AST.StatementType(), res, distributePlan, progAtomic)
+	var contentionTime time.Duration
+	if contentionTrace != nil {
+		planner.extendedEvalCtx.Context = ctx
+		contentionTime = contentionTrace.finish(ex, planner, stmt)
+	}
 	ex.sessionTracing.TraceExecEnd(ctx, res.Err(), res.RowsAffected())
 	ex.statsCollector.phaseTimes[plannerEndExecStmt] = timeutil.Now()
+	ex.statsCollector.previousPhaseTimes = ex.statsCollector.phaseTimes
 
 	// Record the statement summary. This also closes the plan if the
 	// plan has not been closed earlier.
 	ex.recordStatementSummary(
 		ctx, planner,
 		ex.extraTxnState.autoRetryCounter, res.RowsAffected(), res.Err(), bytesRead, rowsRead,
+		contentionTrace != nil, contentionTime,
 	)
+	if !planner.collectBundle {
+		ex.maybeArmSlowQueryCapture(ctx, planner)
+	}
 	if ex.server.cfg.TestingKnobs.AfterExecute != nil {
 		ex.server.cfg.TestingKnobs.AfterExecute(ctx, stmt.String(), res.Err())
 	}
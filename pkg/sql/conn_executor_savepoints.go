@@ -115,6 +115,9 @@ func (ex *connExecutor) execSavepointInOpenState(
 		commitOnRelease: commitOnRelease,
 		kvToken:         token,
 		numDDL:          ex.extraTxnState.numDDL,
+
+		deferredConstraints: ex.extraTxnState.deferredConstraints.snapshot(),
+		onCommitTempTables:  ex.extraTxnState.onCommitTempTables.snapshot(),
 	}
 	savepoints.push(sp)
 
@@ -212,6 +215,8 @@ func (ex *connExecutor) execRollbackToSavepointInOpenState(
 	}
 
 	ex.extraTxnState.savepoints.popToIdx(idx)
+	ex.extraTxnState.deferredConstraints = entry.deferredConstraints.snapshot()
+	ex.extraTxnState.onCommitTempTables = entry.onCommitTempTables.snapshot()
 
 	if entry.kvToken.Initial() {
 		return eventTxnRestart{}, nil
@@ -286,6 +291,8 @@ func (ex *connExecutor) execRollbackToSavepointInAbortedState(
 	}
 
 	ex.extraTxnState.savepoints.popToIdx(idx)
+	ex.extraTxnState.deferredConstraints = entry.deferredConstraints.snapshot()
+	ex.extraTxnState.onCommitTempTables = entry.onCommitTempTables.snapshot()
 
 	// Special case for mixed-cluster versions, where regular savepoints
 	// are not yet enabled but we still support cockroach_restart. In
@@ -348,6 +355,15 @@ type savepoint struct {
 	// more DDL statements were executed since the savepoint's creation.
 	// TODO(knz): support partial DDL cancellation in pending txns.
 	numDDL int
+
+	// The state of the deferred constraints at the time the savepoint was
+	// created. Rolling back to the savepoint restores it: the keys saved by the
+	// deferred checks of the rolled back statements are discarded.
+	deferredConstraints deferredConstraints
+
+	// The temporary tables with ON COMMIT actions created by the transaction at
+	// the time the savepoint was created.
+	onCommitTempTables onCommitTempTables
 }
 
 type savepointStack []savepoint
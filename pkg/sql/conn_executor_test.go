@@ -467,6 +467,42 @@ func TestAppNameStatisticsInitialization(t *testing.T) {
 	}
 }
 
+// TestSessionLastQueryStatisticsExtendedProtocol checks that the latencies of
+// the previous statement are reported by
+// crdb_internal.session_last_query_statistics when the statements are sent
+// through the extended protocol, which prepares each of them before executing
+// it.
+func TestSessionLastQueryStatisticsExtendedProtocol(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	// The statements must run in the same session.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// lib/pq uses the extended protocol for the statements with arguments.
+	if _, err := conn.ExecContext(ctx, `SELECT pg_sleep($1)`, 0.1); err != nil {
+		t.Fatal(err)
+	}
+	var execLatencyOK, serviceLatencyOK bool
+	if err := conn.QueryRowContext(ctx, `
+SELECT exec_latency >= $1::INTERVAL, service_latency >= exec_latency
+FROM crdb_internal.session_last_query_statistics`, "100ms",
+	).Scan(&execLatencyOK, &serviceLatencyOK); err != nil {
+		t.Fatal(err)
+	}
+	if !execLatencyOK || !serviceLatencyOK {
+		t.Errorf("expected the latencies of pg_sleep, got exec_latency >= 100ms: %t, "+
+			"service_latency >= exec_latency: %t", execLatencyOK, serviceLatencyOK)
+	}
+}
+
 func TestQueryProgress(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
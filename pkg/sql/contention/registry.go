@@ -0,0 +1,76 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package contention
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// DefaultCapacity is the number of contention events retained by a Registry
+// created with NewRegistry.
+const DefaultCapacity = 1000
+
+// Registry retains the most recent contention events encountered by the
+// statements executed on this node. Once the registry is full, new events
+// overwrite the oldest ones.
+type Registry struct {
+	mu struct {
+		syncutil.Mutex
+		// events is used as a ring buffer; next is the position at which the
+		// next event will be stored.
+		events []serverpb.ContentionEventRecord
+		next   int
+		full   bool
+	}
+}
+
+// NewRegistry creates a new Registry with the default capacity.
+func NewRegistry() *Registry {
+	return NewRegistryWithCapacity(DefaultCapacity)
+}
+
+// NewRegistryWithCapacity creates a new Registry retaining at most capacity
+// events.
+func NewRegistryWithCapacity(capacity int) *Registry {
+	r := &Registry{}
+	r.mu.events = make([]serverpb.ContentionEventRecord, capacity)
+	return r
+}
+
+// Add records the given contention events.
+func (r *Registry) Add(records ...serverpb.ContentionEventRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.mu.events) == 0 {
+		return
+	}
+	for i := range records {
+		r.mu.events[r.mu.next] = records[i]
+		r.mu.next++
+		if r.mu.next == len(r.mu.events) {
+			r.mu.next = 0
+			r.mu.full = true
+		}
+	}
+}
+
+// Serialize returns a copy of the retained events, oldest first.
+func (r *Registry) Serialize() []serverpb.ContentionEventRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.mu.full {
+		return append([]serverpb.ContentionEventRecord(nil), r.mu.events[:r.mu.next]...)
+	}
+	res := make([]serverpb.ContentionEventRecord, 0, len(r.mu.events))
+	res = append(res, r.mu.events[r.mu.next:]...)
+	return append(res, r.mu.events[:r.mu.next]...)
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package contention
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	r := NewRegistryWithCapacity(3)
+	require.Empty(t, r.Serialize())
+
+	rec := func(stmt string) serverpb.ContentionEventRecord {
+		return serverpb.ContentionEventRecord{WaitingStatement: stmt}
+	}
+	stmts := func(records []serverpb.ContentionEventRecord) []string {
+		var res []string
+		for _, rec := range records {
+			res = append(res, rec.WaitingStatement)
+		}
+		return res
+	}
+
+	r.Add(rec("a"), rec("b"))
+	require.Equal(t, []string{"a", "b"}, stmts(r.Serialize()))
+
+	r.Add(rec("c"))
+	require.Equal(t, []string{"a", "b", "c"}, stmts(r.Serialize()))
+
+	// Once full, the oldest events are overwritten.
+	r.Add(rec("d"), rec("e"))
+	require.Equal(t, []string{"c", "d", "e"}, stmts(r.Serialize()))
+
+	// A registry without capacity drops all events.
+	r = NewRegistryWithCapacity(0)
+	r.Add(rec("a"))
+	require.Empty(t, r.Serialize())
+}
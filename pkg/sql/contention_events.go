@@ -0,0 +1,143 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+	"github.com/opentracing/opentracing-go"
+)
+
+// contentionSampleRate is the probability with which a statement is traced to
+// collect the contention events it encounters.
+var contentionSampleRate = func() *settings.FloatSetting {
+	s := settings.RegisterValidatedFloatSetting(
+		"sql.contention.sample_rate",
+		"the probability that a given statement is traced to collect the contention "+
+			"events it encounters (see crdb_internal.cluster_contention_events); "+
+			"set to 0 to disable",
+		0.01,
+		func(v float64) error {
+			if v < 0 || v > 1 {
+				return errors.Errorf("sample rate must be between 0 and 1, got %v", v)
+			}
+			return nil
+		},
+	)
+	s.SetVisibility(settings.Public)
+	return s
+}()
+
+// contentionTrace collects the contention events encountered by the
+// execution of a sampled statement.
+type contentionTrace struct {
+	sp opentracing.Span
+}
+
+// maybeStartContentionTrace decides whether the contention events of the
+// statement about to be executed are collected. If so, it returns a context
+// containing a span with its own recording, as well as a contentionTrace which
+// must be finished once the statement has executed.
+//
+// Statements executed while their context is already being recorded (e.g.
+// with session tracing enabled) are not sampled; the contention events they
+// encounter are part of that recording already.
+func (ex *connExecutor) maybeStartContentionTrace(
+	ctx context.Context,
+) (context.Context, *contentionTrace) {
+	if ex.server.cfg.ContentionRegistry == nil {
+		return ctx, nil
+	}
+	rate := contentionSampleRate.Get(&ex.server.cfg.Settings.SV)
+	if rate == 0 || rand.Float64() >= rate {
+		return ctx, nil
+	}
+	if sp := opentracing.SpanFromContext(ctx); sp != nil && tracing.IsRecording(sp) {
+		return ctx, nil
+	}
+	tr := ex.server.cfg.AmbientCtx.Tracer
+	ctx, sp := tracing.StartSnowballTrace(ctx, tr, "contention trace")
+	return ctx, &contentionTrace{sp: sp}
+}
+
+// finish stops the recording, records the contention events encountered by
+// the statement in the node's contention registry and returns the total time
+// that the statement spent waiting on other transactions.
+func (t *contentionTrace) finish(
+	ex *connExecutor, planner *planner, stmt *Statement,
+) (contentionTime time.Duration) {
+	t.sp.Finish()
+	events := contentionEventsFromRecording(tracing.GetRecording(t.sp))
+	if len(events) == 0 {
+		return 0
+	}
+
+	fingerprint := stmt.AnonymizedStr
+	if fingerprint == "" {
+		fingerprint = anonymizeStmt(stmt.AST)
+	}
+	records := make([]serverpb.ContentionEventRecord, len(events))
+	now := timeutil.Now()
+	for i := range events {
+		contentionTime += events[i].Duration
+		records[i] = serverpb.ContentionEventRecord{
+			ApplicationName:  ex.sessionData.ApplicationName,
+			WaitingStatement: fingerprint,
+			Event:            events[i],
+			RecordedAt:       now,
+		}
+		if planner.txn != nil {
+			records[i].WaitingTxnID = planner.txn.ID()
+		}
+	}
+	ex.server.cfg.ContentionRegistry.Add(records...)
+	return contentionTime
+}
+
+// contentionEventsFromRecording extracts the contention events from the
+// stats of the given recording, including the spans recorded on remote nodes.
+func contentionEventsFromRecording(rec tracing.Recording) []roachpb.ContentionEvent {
+	var events []roachpb.ContentionEvent
+	for i := range rec {
+		if rec[i].Stats == nil {
+			continue
+		}
+		var da pbtypes.DynamicAny
+		if err := pbtypes.UnmarshalAny(rec[i].Stats, &da); err != nil {
+			continue
+		}
+		if ev, ok := da.Message.(*roachpb.ContentionEvent); ok {
+			events = append(events, *ev)
+		}
+	}
+	return events
+}
+
+// contentionTimeDatums returns the mean and variance of the contention time
+// of the sampled executions of a statement, or NULLs if none of its
+// executions were sampled.
+func contentionTimeDatums(s *roachpb.StatementStatistics) (avg, variance tree.Datum) {
+	if s.ContentionSampleCount == 0 {
+		return tree.DNull, tree.DNull
+	}
+	return tree.NewDFloat(tree.DFloat(s.ContentionTime.Mean)),
+		tree.NewDFloat(tree.DFloat(s.ContentionTime.GetVariance(s.ContentionSampleCount)))
+}
@@ -40,11 +40,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"gopkg.in/yaml.v2"
 )
@@ -65,43 +68,49 @@ const crdbInternalName = "crdb_internal"
 var crdbInternal = virtualSchema{
 	name: crdbInternalName,
 	tableDefs: map[sqlbase.ID]virtualSchemaDef{
-		sqlbase.CrdbInternalBackwardDependenciesTableID: crdbInternalBackwardDependenciesTable,
-		sqlbase.CrdbInternalBuildInfoTableID:            crdbInternalBuildInfoTable,
-		sqlbase.CrdbInternalBuiltinFunctionsTableID:     crdbInternalBuiltinFunctionsTable,
-		sqlbase.CrdbInternalClusterQueriesTableID:       crdbInternalClusterQueriesTable,
-		sqlbase.CrdbInternalClusterTransactionsTableID:  crdbInternalClusterTxnsTable,
-		sqlbase.CrdbInternalClusterSessionsTableID:      crdbInternalClusterSessionsTable,
-		sqlbase.CrdbInternalClusterSettingsTableID:      crdbInternalClusterSettingsTable,
-		sqlbase.CrdbInternalCreateStmtsTableID:          crdbInternalCreateStmtsTable,
-		sqlbase.CrdbInternalFeatureUsageID:              crdbInternalFeatureUsage,
-		sqlbase.CrdbInternalForwardDependenciesTableID:  crdbInternalForwardDependenciesTable,
-		sqlbase.CrdbInternalGossipNodesTableID:          crdbInternalGossipNodesTable,
-		sqlbase.CrdbInternalGossipAlertsTableID:         crdbInternalGossipAlertsTable,
-		sqlbase.CrdbInternalGossipLivenessTableID:       crdbInternalGossipLivenessTable,
-		sqlbase.CrdbInternalGossipNetworkTableID:        crdbInternalGossipNetworkTable,
-		sqlbase.CrdbInternalIndexColumnsTableID:         crdbInternalIndexColumnsTable,
-		sqlbase.CrdbInternalJobsTableID:                 crdbInternalJobsTable,
-		sqlbase.CrdbInternalKVNodeStatusTableID:         crdbInternalKVNodeStatusTable,
-		sqlbase.CrdbInternalKVStoreStatusTableID:        crdbInternalKVStoreStatusTable,
-		sqlbase.CrdbInternalLeasesTableID:               crdbInternalLeasesTable,
-		sqlbase.CrdbInternalLocalQueriesTableID:         crdbInternalLocalQueriesTable,
-		sqlbase.CrdbInternalLocalTransactionsTableID:    crdbInternalLocalTxnsTable,
-		sqlbase.CrdbInternalLocalSessionsTableID:        crdbInternalLocalSessionsTable,
-		sqlbase.CrdbInternalLocalMetricsTableID:         crdbInternalLocalMetricsTable,
-		sqlbase.CrdbInternalPartitionsTableID:           crdbInternalPartitionsTable,
-		sqlbase.CrdbInternalPredefinedCommentsTableID:   crdbInternalPredefinedCommentsTable,
-		sqlbase.CrdbInternalRangesNoLeasesTableID:       crdbInternalRangesNoLeasesTable,
-		sqlbase.CrdbInternalRangesViewID:                crdbInternalRangesView,
-		sqlbase.CrdbInternalRuntimeInfoTableID:          crdbInternalRuntimeInfoTable,
-		sqlbase.CrdbInternalSchemaChangesTableID:        crdbInternalSchemaChangesTable,
-		sqlbase.CrdbInternalSessionTraceTableID:         crdbInternalSessionTraceTable,
-		sqlbase.CrdbInternalSessionVariablesTableID:     crdbInternalSessionVariablesTable,
-		sqlbase.CrdbInternalStmtStatsTableID:            crdbInternalStmtStatsTable,
-		sqlbase.CrdbInternalTableColumnsTableID:         crdbInternalTableColumnsTable,
-		sqlbase.CrdbInternalTableIndexesTableID:         crdbInternalTableIndexesTable,
-		sqlbase.CrdbInternalTablesTableID:               crdbInternalTablesTable,
-		sqlbase.CrdbInternalTxnStatsTableID:             crdbInternalTxnStatsTable,
-		sqlbase.CrdbInternalZonesTableID:                crdbInternalZonesTable,
+		sqlbase.CrdbInternalBackwardDependenciesTableID:       crdbInternalBackwardDependenciesTable,
+		sqlbase.CrdbInternalBuildInfoTableID:                  crdbInternalBuildInfoTable,
+		sqlbase.CrdbInternalBuiltinFunctionsTableID:           crdbInternalBuiltinFunctionsTable,
+		sqlbase.CrdbInternalClusterContentionEventsTableID:    crdbInternalClusterContentionEventsTable,
+		sqlbase.CrdbInternalClusterQueriesTableID:             crdbInternalClusterQueriesTable,
+		sqlbase.CrdbInternalClusterTransactionsTableID:        crdbInternalClusterTxnsTable,
+		sqlbase.CrdbInternalClusterSessionsTableID:            crdbInternalClusterSessionsTable,
+		sqlbase.CrdbInternalClusterSettingsTableID:            crdbInternalClusterSettingsTable,
+		sqlbase.CrdbInternalCreateFunctionStmtsTableID:        crdbInternalCreateFunctionStmtsTable,
+		sqlbase.CrdbInternalCreateStmtsTableID:                crdbInternalCreateStmtsTable,
+		sqlbase.CrdbInternalFeatureUsageID:                    crdbInternalFeatureUsage,
+		sqlbase.CrdbInternalForwardDependenciesTableID:        crdbInternalForwardDependenciesTable,
+		sqlbase.CrdbInternalGossipNodesTableID:                crdbInternalGossipNodesTable,
+		sqlbase.CrdbInternalGossipAlertsTableID:               crdbInternalGossipAlertsTable,
+		sqlbase.CrdbInternalGossipLivenessTableID:             crdbInternalGossipLivenessTable,
+		sqlbase.CrdbInternalGossipNetworkTableID:              crdbInternalGossipNetworkTable,
+		sqlbase.CrdbInternalIndexColumnsTableID:               crdbInternalIndexColumnsTable,
+		sqlbase.CrdbInternalIndexUsageStatisticsTableID:       crdbInternalIndexUsageStatisticsTable,
+		sqlbase.CrdbInternalJobsTableID:                       crdbInternalJobsTable,
+		sqlbase.CrdbInternalKVNodeStatusTableID:               crdbInternalKVNodeStatusTable,
+		sqlbase.CrdbInternalKVStoreStatusTableID:              crdbInternalKVStoreStatusTable,
+		sqlbase.CrdbInternalLeasesTableID:                     crdbInternalLeasesTable,
+		sqlbase.CrdbInternalLocalQueriesTableID:               crdbInternalLocalQueriesTable,
+		sqlbase.CrdbInternalLocalTransactionsTableID:          crdbInternalLocalTxnsTable,
+		sqlbase.CrdbInternalLocalSessionsTableID:              crdbInternalLocalSessionsTable,
+		sqlbase.CrdbInternalLocalMetricsTableID:               crdbInternalLocalMetricsTable,
+		sqlbase.CrdbInternalPartitionsTableID:                 crdbInternalPartitionsTable,
+		sqlbase.CrdbInternalPredefinedCommentsTableID:         crdbInternalPredefinedCommentsTable,
+		sqlbase.CrdbInternalRangesNoLeasesTableID:             crdbInternalRangesNoLeasesTable,
+		sqlbase.CrdbInternalRangesViewID:                      crdbInternalRangesView,
+		sqlbase.CrdbInternalRuntimeInfoTableID:                crdbInternalRuntimeInfoTable,
+		sqlbase.CrdbInternalSchemaChangesTableID:              crdbInternalSchemaChangesTable,
+		sqlbase.CrdbInternalSessionLastQueryStatisticsTableID: crdbInternalSessionLastQueryStatisticsTable,
+		sqlbase.CrdbInternalSessionTraceTableID:               crdbInternalSessionTraceTable,
+		sqlbase.CrdbInternalSessionVariablesTableID:           crdbInternalSessionVariablesTable,
+		sqlbase.CrdbInternalStatementStatisticsTableID:        crdbInternalStatementStatisticsTable,
+		sqlbase.CrdbInternalStmtStatsTableID:                  crdbInternalStmtStatsTable,
+		sqlbase.CrdbInternalTableColumnsTableID:               crdbInternalTableColumnsTable,
+		sqlbase.CrdbInternalTableIndexesTableID:               crdbInternalTableIndexesTable,
+		sqlbase.CrdbInternalTablesTableID:                     crdbInternalTablesTable,
+		sqlbase.CrdbInternalTransactionStatisticsTableID:      crdbInternalTransactionStatisticsTable,
+		sqlbase.CrdbInternalTxnStatsTableID:                   crdbInternalTxnStatsTable,
+		sqlbase.CrdbInternalZonesTableID:                      crdbInternalZonesTable,
 	},
 	validWithNoDatabaseContext: true,
 }
@@ -642,7 +651,9 @@ CREATE TABLE crdb_internal.node_statement_statistics (
   overhead_lat_var    FLOAT NOT NULL,
   bytes_read          INT NOT NULL,
   rows_read           INT NOT NULL,
-  implicit_txn        BOOL NOT NULL
+  implicit_txn        BOOL NOT NULL,
+  contention_time_avg FLOAT,
+  contention_time_var FLOAT
 )`,
 	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
 		if err := p.RequireAdminRole(ctx, "access application statistics"); err != nil {
@@ -696,6 +707,7 @@ CREATE TABLE crdb_internal.node_statement_statistics (
 				if s.data.SensitiveInfo.LastErr != "" {
 					errString = tree.NewDString(s.data.SensitiveInfo.LastErr)
 				}
+				contentionTimeAvg, contentionTimeVar := contentionTimeDatums(&s.data)
 				err := addRow(
 					nodeID,
 					tree.NewDString(appName),
@@ -721,6 +733,8 @@ CREATE TABLE crdb_internal.node_statement_statistics (
 					tree.NewDInt(tree.DInt(s.data.BytesRead)),
 					tree.NewDInt(tree.DInt(s.data.RowsRead)),
 					tree.MakeDBool(tree.DBool(stmtKey.implicitTxn)),
+					contentionTimeAvg,
+					contentionTimeVar,
 				)
 				s.Unlock()
 				if err != nil {
@@ -788,6 +802,175 @@ CREATE TABLE crdb_internal.node_txn_stats (
 	},
 }
 
+var crdbInternalStatementStatisticsTable = virtualSchemaTable{
+	comment: `statement statistics of the cluster, aggregated over time intervals ` +
+		`(persisted statistics of all nodes and in-memory statistics of the local node)`,
+	schema: `
+CREATE TABLE crdb_internal.statement_statistics (
+  aggregated_ts       TIMESTAMPTZ NOT NULL,
+  application_name    STRING NOT NULL,
+  flags               STRING NOT NULL,
+  key                 STRING NOT NULL,
+  count               INT NOT NULL,
+  first_attempt_count INT NOT NULL,
+  max_retries         INT NOT NULL,
+  last_error          STRING,
+  rows_avg            FLOAT NOT NULL,
+  rows_var            FLOAT NOT NULL,
+  parse_lat_avg       FLOAT NOT NULL,
+  parse_lat_var       FLOAT NOT NULL,
+  plan_lat_avg        FLOAT NOT NULL,
+  plan_lat_var        FLOAT NOT NULL,
+  run_lat_avg         FLOAT NOT NULL,
+  run_lat_var         FLOAT NOT NULL,
+  service_lat_avg     FLOAT NOT NULL,
+  service_lat_var     FLOAT NOT NULL,
+  overhead_lat_avg    FLOAT NOT NULL,
+  overhead_lat_var    FLOAT NOT NULL,
+  bytes_read          INT NOT NULL,
+  rows_read           INT NOT NULL,
+  implicit_txn        BOOL NOT NULL,
+  contention_time_avg FLOAT,
+  contention_time_var FLOAT
+)`,
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "access application statistics"); err != nil {
+			return err
+		}
+
+		sqlStats := p.extendedEvalCtx.sqlStatsCollector.sqlStats
+		if sqlStats == nil {
+			return errors.AssertionFailedf(
+				"cannot access sql statistics from this context")
+		}
+
+		stats, err := collectStmtStatistics(ctx, p, sqlStats)
+		if err != nil {
+			return err
+		}
+
+		// Sort the keys to ensure the output is deterministic.
+		keys := make([]persistedStmtKey, 0, len(stats))
+		for k := range stats {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			a, b := keys[i], keys[j]
+			if !a.aggregatedTS.Equal(b.aggregatedTS) {
+				return a.aggregatedTS.Before(b.aggregatedTS)
+			}
+			if a.appName != b.appName {
+				return a.appName < b.appName
+			}
+			if a.stmt != b.stmt {
+				return a.stmt < b.stmt
+			}
+			if a.flags() != b.flags() {
+				return a.flags() < b.flags()
+			}
+			return !a.implicitTxn && b.implicitTxn
+		})
+
+		for _, k := range keys {
+			s := stats[k]
+			errString := tree.DNull
+			if s.SensitiveInfo.LastErr != "" {
+				errString = tree.NewDString(s.SensitiveInfo.LastErr)
+			}
+			contentionTimeAvg, contentionTimeVar := contentionTimeDatums(s)
+			if err := addRow(
+				tree.MakeDTimestampTZ(k.aggregatedTS, time.Microsecond),
+				tree.NewDString(k.appName),
+				tree.NewDString(k.flags()),
+				tree.NewDString(k.stmt),
+				tree.NewDInt(tree.DInt(s.Count)),
+				tree.NewDInt(tree.DInt(s.FirstAttemptCount)),
+				tree.NewDInt(tree.DInt(s.MaxRetries)),
+				errString,
+				tree.NewDFloat(tree.DFloat(s.NumRows.Mean)),
+				tree.NewDFloat(tree.DFloat(s.NumRows.GetVariance(s.Count))),
+				tree.NewDFloat(tree.DFloat(s.ParseLat.Mean)),
+				tree.NewDFloat(tree.DFloat(s.ParseLat.GetVariance(s.Count))),
+				tree.NewDFloat(tree.DFloat(s.PlanLat.Mean)),
+				tree.NewDFloat(tree.DFloat(s.PlanLat.GetVariance(s.Count))),
+				tree.NewDFloat(tree.DFloat(s.RunLat.Mean)),
+				tree.NewDFloat(tree.DFloat(s.RunLat.GetVariance(s.Count))),
+				tree.NewDFloat(tree.DFloat(s.ServiceLat.Mean)),
+				tree.NewDFloat(tree.DFloat(s.ServiceLat.GetVariance(s.Count))),
+				tree.NewDFloat(tree.DFloat(s.OverheadLat.Mean)),
+				tree.NewDFloat(tree.DFloat(s.OverheadLat.GetVariance(s.Count))),
+				tree.NewDInt(tree.DInt(s.BytesRead)),
+				tree.NewDInt(tree.DInt(s.RowsRead)),
+				tree.MakeDBool(tree.DBool(k.implicitTxn)),
+				contentionTimeAvg,
+				contentionTimeVar,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var crdbInternalTransactionStatisticsTable = virtualSchemaTable{
+	comment: `per-application transaction statistics of the cluster, aggregated over time intervals ` +
+		`(persisted statistics of all nodes and in-memory statistics of the local node)`,
+	schema: `
+CREATE TABLE crdb_internal.transaction_statistics (
+  aggregated_ts      TIMESTAMPTZ NOT NULL,
+  application_name   STRING NOT NULL,
+  txn_count          INT NOT NULL,
+  txn_time_avg_sec   FLOAT NOT NULL,
+  txn_time_var_sec   FLOAT NOT NULL,
+  committed_count    INT NOT NULL,
+  implicit_count     INT NOT NULL
+)`,
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "access application statistics"); err != nil {
+			return err
+		}
+
+		sqlStats := p.extendedEvalCtx.sqlStatsCollector.sqlStats
+		if sqlStats == nil {
+			return errors.AssertionFailedf(
+				"cannot access sql statistics from this context")
+		}
+
+		stats, err := collectTxnStatistics(ctx, p, sqlStats)
+		if err != nil {
+			return err
+		}
+
+		// Sort the keys to ensure the output is deterministic.
+		keys := make([]persistedTxnKey, 0, len(stats))
+		for k := range stats {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if !keys[i].aggregatedTS.Equal(keys[j].aggregatedTS) {
+				return keys[i].aggregatedTS.Before(keys[j].aggregatedTS)
+			}
+			return keys[i].appName < keys[j].appName
+		})
+
+		for _, k := range keys {
+			s := stats[k]
+			if err := addRow(
+				tree.MakeDTimestampTZ(k.aggregatedTS, time.Microsecond),
+				tree.NewDString(k.appName),
+				tree.NewDInt(tree.DInt(s.TxnCount)),
+				tree.NewDFloat(tree.DFloat(s.TxnTimeSec.Mean)),
+				tree.NewDFloat(tree.DFloat(s.TxnTimeSec.GetVariance(s.TxnCount))),
+				tree.NewDInt(tree.DInt(s.CommittedCount)),
+				tree.NewDInt(tree.DInt(s.ImplicitCount)),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 // crdbInternalSessionTraceTable exposes the latest trace collected on this
 // session (via SET TRACING={ON/OFF})
 //
@@ -823,6 +1006,38 @@ CREATE TABLE crdb_internal.session_trace (
 	},
 }
 
+// crdbInternalSessionLastQueryStatisticsTable exposes the latencies of the
+// statement executed by the session before the current one.
+var crdbInternalSessionLastQueryStatisticsTable = virtualSchemaTable{
+	comment: `latencies of the previous statement of the session (RAM)`,
+	schema: `
+CREATE TABLE crdb_internal.session_last_query_statistics (
+  parse_latency   INTERVAL NOT NULL, -- The time spent parsing the statement.
+  plan_latency    INTERVAL NOT NULL, -- The time spent planning the statement.
+  exec_latency    INTERVAL NOT NULL, -- The time spent executing the statement.
+  service_latency INTERVAL NOT NULL  -- The time spent on the statement overall,
+                                     -- from its receipt to the end of its execution.
+)`,
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		phaseTimes := &p.extendedEvalCtx.sqlStatsCollector.previousPhaseTimes
+		if phaseTimes[plannerEndExecStmt].IsZero() {
+			// No statement of the session was executed before this one.
+			return nil
+		}
+		interval := func(start, end sessionPhase) tree.Datum {
+			return &tree.DInterval{
+				Duration: duration.MakeDuration(phaseTimes[end].Sub(phaseTimes[start]).Nanoseconds(), 0, 0),
+			}
+		}
+		return addRow(
+			interval(sessionStartParse, sessionEndParse),
+			interval(plannerStartLogicalPlan, plannerEndLogicalPlan),
+			interval(plannerStartExecStmt, plannerEndExecStmt),
+			interval(sessionQueryReceived, plannerEndExecStmt),
+		)
+	},
+}
+
 // crdbInternalClusterSettingsTable exposes the list of current
 // cluster settings.
 //
@@ -1150,6 +1365,83 @@ var crdbInternalLocalSessionsTable = virtualSchemaTable{
 
 // crdbInternalClusterSessionsTable exposes the list of running sessions
 // on the entire cluster. The result is dependent on the current user.
+var crdbInternalClusterContentionEventsTable = virtualSchemaTable{
+	comment: `recent contention events encountered by sampled statements, ` +
+		`see sql.contention.sample_rate (cluster RPC; expensive!)`,
+	schema: `
+CREATE TABLE crdb_internal.cluster_contention_events (
+  node_id           INT NOT NULL,      -- the gateway node of the waiting statement
+  waiting_txn_id    UUID,              -- the ID of the waiting transaction
+  application_name  STRING,            -- the application name of the waiting session
+  waiting_statement STRING,            -- the fingerprint of the waiting statement
+  blocking_txn_id   UUID,              -- the ID of the transaction that was waited on
+  key               BYTES,             -- the key on which the conflict occurred
+  table_id          INT,               -- the table of the key, if any
+  index_id          INT,               -- the index of the key, if any
+  duration          INTERVAL,          -- the duration of the wait
+  recorded_at       TIMESTAMP          -- the end of the waiting statement
+)`,
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.cluster_contention_events"); err != nil {
+			return err
+		}
+		response, err := p.extendedEvalCtx.StatusServer.ListContentionEvents(
+			ctx, &serverpb.ListContentionEventsRequest{})
+		if err != nil {
+			return err
+		}
+		for _, rec := range response.Events {
+			waitingTxnID := tree.DNull
+			if rec.WaitingTxnID != uuid.Nil {
+				waitingTxnID = tree.NewDUuid(tree.DUuid{UUID: rec.WaitingTxnID})
+			}
+			tableID, indexID := tree.DNull, tree.DNull
+			if rem, id, err := keys.DecodeTablePrefix(rec.Event.Key); err == nil {
+				tableID = tree.NewDInt(tree.DInt(id))
+				if _, idxID, err := encoding.DecodeUvarintAscending(rem); err == nil {
+					indexID = tree.NewDInt(tree.DInt(idxID))
+				}
+			}
+			if err := addRow(
+				tree.NewDInt(tree.DInt(rec.NodeID)),
+				waitingTxnID,
+				tree.NewDString(rec.ApplicationName),
+				tree.NewDString(rec.WaitingStatement),
+				tree.NewDUuid(tree.DUuid{UUID: rec.Event.TxnMeta.ID}),
+				tree.NewDBytes(tree.DBytes(rec.Event.Key)),
+				tableID,
+				indexID,
+				&tree.DInterval{Duration: duration.MakeDuration(rec.Event.Duration.Nanoseconds(), 0, 0)},
+				tree.MakeDTimestamp(rec.RecordedAt, time.Microsecond),
+			); err != nil {
+				return err
+			}
+		}
+		for _, rpcErr := range response.Errors {
+			log.Warning(ctx, rpcErr.Message)
+			if rpcErr.NodeID != 0 {
+				// Add a row with this node ID, the error for the statement,
+				// and nulls for all other columns.
+				if err := addRow(
+					tree.NewDInt(tree.DInt(rpcErr.NodeID)), // node ID
+					tree.DNull,                             // waiting txn ID
+					tree.DNull,                             // application name
+					tree.NewDString("-- "+rpcErr.Message),  // waiting statement
+					tree.DNull,                             // blocking txn ID
+					tree.DNull,                             // key
+					tree.DNull,                             // table ID
+					tree.DNull,                             // index ID
+					tree.DNull,                             // duration
+					tree.DNull,                             // recorded at
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
+
 var crdbInternalClusterSessionsTable = virtualSchemaTable{
 	comment: "running sessions visible to current user (cluster RPC; expensive!)",
 	schema:  fmt.Sprintf(sessionsSchemaPattern, "cluster_sessions"),
@@ -1340,6 +1632,7 @@ CREATE TABLE crdb_internal.create_statements (
 
 		// Prepare the row populate function.
 		typeView := tree.NewDString("view")
+		typeMaterializedView := tree.NewDString("materialized view")
 		typeTable := tree.NewDString("table")
 		typeSequence := tree.NewDString("sequence")
 
@@ -1400,6 +1693,9 @@ CREATE TABLE crdb_internal.create_statements (
 				var err error
 				if table.IsView() {
 					descType = typeView
+					if table.MaterializedView() {
+						descType = typeMaterializedView
+					}
 					stmt, err = ShowCreateView(ctx, (*tree.Name)(&table.Name), table)
 				} else if table.IsSequence() {
 					descType = typeSequence
@@ -1466,6 +1762,45 @@ CREATE TABLE crdb_internal.create_statements (
 	},
 }
 
+// crdbInternalCreateFunctionStmtsTable exposes the CREATE FUNCTION
+// statements of the user-defined functions.
+var crdbInternalCreateFunctionStmtsTable = virtualSchemaTable{
+	comment: `CREATE statements for all user-defined functions accessible by current user in current database (KV scan)`,
+	schema: `
+CREATE TABLE crdb_internal.create_function_statements (
+  database_id      INT,
+  database_name    STRING,
+  schema_name      STRING NOT NULL,
+  function_id      INT,
+  function_name    STRING NOT NULL,
+  create_statement STRING NOT NULL
+)
+`,
+	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		scNameStr := tree.NewDString(tree.PublicSchema)
+		return forEachDatabaseDesc(ctx, p, dbContext, func(db *sqlbase.DatabaseDescriptor) error {
+			dbNameStr := tree.NewDString(db.Name)
+			for i := range db.Functions {
+				fn := &db.Functions[i]
+				if p.CheckAnyPrivilege(ctx, fn) != nil {
+					continue
+				}
+				if err := addRow(
+					tree.NewDInt(tree.DInt(db.ID)),
+					dbNameStr,
+					scNameStr,
+					tree.NewDInt(tree.DInt(fn.ID)),
+					tree.NewDString(fn.Name),
+					tree.NewDString(showCreateFunction(fn)),
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	},
+}
+
 func showAlterStatementWithInterleave(
 	ctx context.Context,
 	tn *tree.Name,
@@ -1668,6 +2003,70 @@ CREATE TABLE crdb_internal.table_indexes (
 	},
 }
 
+// crdbInternalIndexUsageStatisticsTable exposes the number of reads of the
+// indexes, aggregated over all the nodes since they were last restarted.
+var crdbInternalIndexUsageStatisticsTable = virtualSchemaTable{
+	comment: "usage statistics of the indexes accessible by current user in current database " +
+		"(cluster RPC; expensive!)",
+	schema: `
+CREATE TABLE crdb_internal.index_usage_statistics (
+  descriptor_id     INT,
+  descriptor_name   STRING NOT NULL,
+  index_id          INT NOT NULL,
+  index_name        STRING NOT NULL,
+  total_reads       INT NOT NULL, -- The number of processor instances that
+                                  -- read the index, by any of the means below.
+  scan_count        INT NOT NULL, -- The number of scan processor instances.
+  lookup_join_count INT NOT NULL, -- The number of lookup join and index join
+                                  -- processor instances, regardless of the
+                                  -- number of rows they looked up.
+  zigzag_join_count INT NOT NULL, -- The number of zigzag join processor
+                                  -- instances.
+  last_read         TIMESTAMP     -- The time a processor last started reading
+                                  -- the index.
+)
+`,
+	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		response, err := p.extendedEvalCtx.StatusServer.IndexUsageStatistics(
+			ctx, &serverpb.IndexUsageStatisticsRequest{})
+		if err != nil {
+			return err
+		}
+		stats := make(map[roachpb.IndexUsageKey]*roachpb.IndexUsageStatistics, len(response.Statistics))
+		for i := range response.Statistics {
+			stats[response.Statistics[i].Key] = &response.Statistics[i].Stats
+		}
+
+		return forEachTableDescAll(ctx, p, dbContext, hideVirtual,
+			func(db *DatabaseDescriptor, _ string, table *TableDescriptor) error {
+				tableID := tree.NewDInt(tree.DInt(table.ID))
+				tableName := tree.NewDString(table.Name)
+				return table.ForeachNonDropIndex(func(idx *sqlbase.IndexDescriptor) error {
+					key := roachpb.IndexUsageKey{TableID: uint32(table.ID), IndexID: uint32(idx.ID)}
+					var s roachpb.IndexUsageStatistics
+					if idxStats, ok := stats[key]; ok {
+						s = *idxStats
+					}
+					lastRead := tree.DNull
+					if !s.LastRead.IsZero() {
+						lastRead = tree.MakeDTimestamp(s.LastRead, time.Microsecond)
+					}
+					return addRow(
+						tableID,
+						tableName,
+						tree.NewDInt(tree.DInt(idx.ID)),
+						tree.NewDString(idx.Name),
+						tree.NewDInt(tree.DInt(s.TotalReadCount)),
+						tree.NewDInt(tree.DInt(s.ScanCount)),
+						tree.NewDInt(tree.DInt(s.LookupJoinCount)),
+						tree.NewDInt(tree.DInt(s.ZigzagJoinCount)),
+						lastRead,
+					)
+				})
+			})
+	},
+}
+
 // crdbInternalIndexColumnsTable exposes the index columns.
 //
 // TODO(tbg): prefix with kv_.
@@ -369,6 +369,61 @@ WHERE
 	}
 }
 
+// TestClusterContentionEvents verifies that a statement blocked on the locks
+// of another transaction reports the contention event.
+func TestClusterContentionEvents(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	r := sqlutils.MakeSQLRunner(sqlDB)
+	r.Exec(t, `SET CLUSTER SETTING sql.contention.sample_rate = 1`)
+	r.Exec(t, `CREATE DATABASE t; CREATE TABLE t.t (k INT PRIMARY KEY, v INT)`)
+	r.Exec(t, `INSERT INTO t.t VALUES (1, 1)`)
+	var tableID int
+	r.QueryRow(t, `SELECT 't.t'::REGCLASS::INT`).Scan(&tableID)
+
+	blocker, err := sqlDB.BeginTx(ctx, nil /* opts */)
+	require.NoError(t, err)
+	_, err = blocker.Exec(`UPDATE t.t SET v = 2 WHERE k = 1`)
+	require.NoError(t, err)
+
+	conn, err := sqlDB.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, `SET application_name = 'contention_test'`)
+	require.NoError(t, err)
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := conn.ExecContext(ctx, `UPDATE t.t SET v = 3 WHERE k = 1`)
+		errCh <- err
+	}()
+	// Let the second update block on the lock held by the first transaction.
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, blocker.Commit())
+	require.NoError(t, <-errCh)
+
+	var waitingStmt string
+	var indexID int
+	r.QueryRow(t, `
+SELECT waiting_statement, index_id
+  FROM crdb_internal.cluster_contention_events
+ WHERE application_name = 'contention_test' AND table_id = $1`, tableID,
+	).Scan(&waitingStmt, &indexID)
+	require.Equal(t, `UPDATE t.t SET v = _ WHERE k = _`, waitingStmt)
+	require.Equal(t, 1, indexID)
+
+	var contentionTime float64
+	r.QueryRow(t, `
+SELECT contention_time_avg
+  FROM crdb_internal.node_statement_statistics
+ WHERE application_name = 'contention_test' AND key LIKE 'UPDATE%'`,
+	).Scan(&contentionTime)
+	require.Greater(t, contentionTime, 0.0)
+}
+
 // TestCrdbInternalJobsOOM verifies that the memory budget works correctly for
 // crdb_internal.jobs.
 func TestCrdbInternalJobsOOM(t *testing.T) {
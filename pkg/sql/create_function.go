@@ -0,0 +1,170 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+type createFunctionNode struct {
+	n      *tree.CreateFunction
+	dbDesc *sqlbase.DatabaseDescriptor
+}
+
+// CreateFunction creates a user-defined function.
+// Privileges: CREATE on database.
+//   notes: postgres requires CREATE on the schema.
+func (p *planner) CreateFunction(ctx context.Context, n *tree.CreateFunction) (planNode, error) {
+	tn := n.Name.ToTableName()
+	dbDesc, err := p.ResolveUncachedDatabase(ctx, &tn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &createFunctionNode{n: n, dbDesc: dbDesc}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE FUNCTION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createFunctionNode) ReadingOwnWrites() {}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+	dbDesc := n.dbDesc
+
+	fn, err := makeFunctionDescriptor(n.n)
+	if err != nil {
+		return err
+	}
+	// Compile the body of the function now, so that invalid bodies are
+	// reported when the function is created rather than when it is called.
+	if _, err := compileFunctionBody(&fn); err != nil {
+		return err
+	}
+	for _, other := range dbDesc.FindFunctionsByName(fn.Name) {
+		if other.ReturnsSet != fn.ReturnsSet {
+			return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"cannot overload set-returning function %s with a function returning a single value, or vice versa",
+				other.Signature())
+		}
+	}
+
+	if existing := dbDesc.FindFunction(fn.Name, fn.ArgTypes()); existing != nil {
+		if !n.n.Replace {
+			return pgerror.Newf(pgcode.DuplicateFunction,
+				"function %s already exists with same argument types", existing.Signature())
+		}
+		if existing.ReturnsSet != fn.ReturnsSet || !existing.ReturnType.Identical(&fn.ReturnType) {
+			return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"cannot change return type of existing function %s", existing.Signature())
+		}
+		if err := p.CheckPrivilege(ctx, existing, privilege.DROP); err != nil {
+			return err
+		}
+		fn.ID = existing.ID
+		fn.Privileges = existing.Privileges
+		dbDesc.RemoveFunction(existing.ID)
+	} else {
+		id, err := GenerateUniqueDescID(ctx, p.ExecCfg().DB)
+		if err != nil {
+			return err
+		}
+		fn.ID = id
+		// As in Postgres, anybody can call a new function.
+		fn.Privileges = sqlbase.NewDefaultPrivilegeDescriptor()
+		fn.Privileges.Grant(sqlbase.PublicRole, privilege.List{privilege.EXECUTE})
+		if user := p.SessionData().User; user != security.RootUser {
+			fn.Privileges.Grant(user, privilege.List{privilege.ALL})
+		}
+	}
+	dbDesc.AddFunction(fn)
+
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("function"))
+
+	if err := dbDesc.Validate(); err != nil {
+		return err
+	}
+
+	b := p.txn.NewBatch()
+	if err := writeDescToBatch(
+		ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(), p.execCfg.Settings, b, dbDesc.ID, dbDesc,
+	); err != nil {
+		return err
+	}
+	return p.txn.Run(ctx, b)
+}
+
+func (n *createFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createFunctionNode) Close(context.Context)        {}
+
+// makeFunctionDescriptor builds the descriptor of the function defined by a
+// CREATE FUNCTION statement. The ID and privileges of the function are not
+// set.
+func makeFunctionDescriptor(n *tree.CreateFunction) (sqlbase.FunctionDescriptor, error) {
+	fn := sqlbase.FunctionDescriptor{
+		Name:           n.Name.Parts[0],
+		ParentSchemaID: keys.PublicSchemaID,
+		ReturnType:     *n.ReturnType,
+		ReturnsSet:     n.ReturnsSet,
+		Volatility:     functionVolatilityToDesc[n.Options.Volatility],
+		Language:       strings.ToLower(n.Options.Language),
+		Body:           n.Options.Body,
+	}
+	if fn.Language == "" {
+		fn.Language = sqlbase.FunctionLanguageSQL
+	}
+	if fn.Language != sqlbase.FunctionLanguageSQL {
+		return fn, pgerror.Newf(pgcode.FeatureNotSupported,
+			"unsupported function language %q", n.Options.Language)
+	}
+	if !n.Options.HasBody {
+		return fn, pgerror.New(pgcode.InvalidFunctionDefinition, "no function body specified")
+	}
+	if n.ReturnType.Family() == types.TupleFamily {
+		return fn, pgerror.Newf(pgcode.FeatureNotSupported,
+			"functions cannot return %s", n.ReturnType.SQLString())
+	}
+
+	seen := make(map[tree.Name]bool, len(n.Args))
+	for _, arg := range n.Args {
+		if arg.Name != "" {
+			if seen[arg.Name] {
+				return fn, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"parameter name %q used more than once", arg.Name)
+			}
+			seen[arg.Name] = true
+		}
+		fn.Args = append(fn.Args, sqlbase.FunctionDescriptor_Argument{
+			Name: string(arg.Name),
+			Type: *arg.Type,
+		})
+	}
+	return fn, nil
+}
@@ -36,12 +36,29 @@ type createIndexNode struct {
 //          mysql requires INDEX on the table.
 func (p *planner) CreateIndex(ctx context.Context, n *tree.CreateIndex) (planNode, error) {
 	tableDesc, err := p.ResolveMutableTableDescriptor(
-		ctx, &n.Table, true /*required*/, ResolveRequireTableDesc,
+		ctx, &n.Table, true /*required*/, ResolveRequireTableOrViewDesc,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if tableDesc.IsView() && !tableDesc.MaterializedView() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a table or materialized view", tableDesc.Name)
+	}
+	if tableDesc.MaterializedView() {
+		// REFRESH MATERIALIZED VIEW rebuilds all of the view's indexes from the
+		// view query, which does not produce shard columns and cannot write
+		// into other tables' interleaved data.
+		if n.Sharded != nil {
+			return nil, pgerror.New(pgcode.FeatureNotSupported,
+				"cannot create hash sharded index on materialized view")
+		}
+		if n.Interleave != nil {
+			return nil, pgerror.New(pgcode.FeatureNotSupported,
+				"cannot create interleaved index on materialized view")
+		}
+	}
+
 	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
 		return nil, err
 	}
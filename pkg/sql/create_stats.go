@@ -147,7 +147,7 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 	var err error
 	switch t := n.Table.(type) {
 	case *tree.UnresolvedObjectName:
-		tableDesc, err = n.p.ResolveExistingObjectEx(ctx, t, true /*required*/, ResolveRequireTableDesc)
+		tableDesc, err = n.p.ResolveExistingObjectEx(ctx, t, true /*required*/, ResolveRequireTableOrViewDesc)
 		if err != nil {
 			return nil, err
 		}
@@ -173,7 +173,7 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 		)
 	}
 
-	if tableDesc.IsView() {
+	if tableDesc.IsView() && !tableDesc.MaterializedView() {
 		return nil, pgerror.New(
 			pgcode.WrongObjectType, "cannot create statistics on views",
 		)
@@ -201,6 +201,10 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 				return nil, unimplemented.NewWithIssuef(35844,
 					"CREATE STATISTICS is not supported for JSON columns")
 			}
+			if columns[i].Virtual {
+				return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					"CREATE STATISTICS is not supported for virtual column %q", columns[i].Name)
+			}
 			columnIDs[i] = columns[i].ID
 		}
 		colStats = []jobspb.CreateStatsDetails_ColStat{{ColumnIDs: columnIDs, HasHistogram: false}}
@@ -294,6 +298,15 @@ func createStatsDefaultColumns(
 			continue
 		}
 		idxCol := desc.Indexes[i].ColumnIDs[0]
+		col, err := desc.FindColumnByID(idxCol)
+		if err != nil {
+			return nil, err
+		}
+		if col.Virtual {
+			// Virtual columns are not stored in the primary index, which is where
+			// the statistics are sampled from.
+			continue
+		}
 		if !requestedCols.Contains(int(idxCol)) {
 			colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
 				ColumnIDs:    []sqlbase.ColumnID{idxCol},
@@ -307,7 +320,7 @@ func createStatsDefaultColumns(
 	nonIdxCols := 0
 	for i := 0; i < len(desc.Columns) && nonIdxCols < maxNonIndexCols; i++ {
 		col := &desc.Columns[i]
-		if col.Type.Family() != types.JsonFamily && !requestedCols.Contains(int(col.ID)) {
+		if col.Type.Family() != types.JsonFamily && !col.Virtual && !requestedCols.Contains(int(col.ID)) {
 			colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
 				ColumnIDs:    []sqlbase.ColumnID{col.ID},
 				HasHistogram: col.Type.Family() == types.BoolFamily,
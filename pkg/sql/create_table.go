@@ -215,15 +215,21 @@ func (n *createTableNode) startExec(params runParams) error {
 	if n.n.Interleave != nil {
 		telemetry.Inc(sqltelemetry.CreateInterleavedTableCounter)
 	}
+	// The tables created with ON COMMIT DELETE ROWS or ON COMMIT DROP are
+	// registered with the session, which runs their actions before the
+	// transactions commit. Note that UNSET and PRESERVE ROWS behave the same way.
+	var onCommit *onCommitTempTables
 	if isTemporary {
 		telemetry.Inc(sqltelemetry.CreateTempTableCounter)
 
-		// TODO(#46556): support ON COMMIT DROP and DELETE ROWS on TEMPORARY TABLE.
-		// If we do this, the n.n.OnCommit variable should probably be stored on the
-		// table descriptor.
-		// Note UNSET / PRESERVE ROWS behave the same way so we do not need to do that for now.
 		switch n.n.OnCommit {
 		case tree.CreateTableOnCommitUnset, tree.CreateTableOnCommitPreserveRows:
+		case tree.CreateTableOnCommitDeleteRows, tree.CreateTableOnCommitDrop:
+			onCommit = params.extendedEvalCtx.OnCommitTempTables
+			if onCommit == nil {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"ON COMMIT %s is not supported in this context", n.n.OnCommit)
+			}
 		default:
 			return errors.AssertionFailedf("ON COMMIT value %d is unrecognized", n.n.OnCommit)
 		}
@@ -285,8 +291,9 @@ func (n *createTableNode) startExec(params runParams) error {
 		}
 
 		// If we have an implicit txn we want to run CTAS async, and consequently
-		// ensure it gets queued as a SchemaChange.
-		if params.p.ExtendedEvalContext().TxnImplicit {
+		// ensure it gets queued as a SchemaChange. This is not the case if the
+		// table has an ON COMMIT action, which must apply to the rows.
+		if params.p.ExtendedEvalContext().TxnImplicit && onCommit == nil {
 			desc.State = sqlbase.TableDescriptor_ADD
 		}
 	} else {
@@ -362,9 +369,13 @@ func (n *createTableNode) startExec(params runParams) error {
 		return err
 	}
 
-	// If we are in an explicit txn or the source has placeholders, we execute the
-	// CTAS query synchronously.
-	if n.n.As() && !params.p.ExtendedEvalContext().TxnImplicit {
+	if onCommit != nil {
+		onCommit.register(desc.ID, n.n.OnCommit)
+	}
+
+	// If we are in an explicit txn, the source has placeholders or the table
+	// has an ON COMMIT action, we execute the CTAS query synchronously.
+	if n.n.As() && (!params.p.ExtendedEvalContext().TxnImplicit || onCommit != nil) {
 		err = func() error {
 			// The data fill portion of CREATE AS must operate on a read snapshot,
 			// so that it doesn't end up observing its own writes.
@@ -817,6 +828,8 @@ func ResolveFK(
 		Match:                 sqlbase.CompositeKeyMatchMethodValue[d.Match],
 		LegacyOriginIndex:     legacyOriginIndexID,
 		LegacyReferencedIndex: legacyReferencedIndexID,
+		Deferrable:            d.Deferrability != tree.NotDeferrable,
+		InitiallyDeferred:     d.Deferrability == tree.DeferrableInitiallyDeferred,
 	}
 
 	if ts == NewTable {
@@ -1450,10 +1463,10 @@ func MakeTableDesc(
 		case *tree.UniqueConstraintTableDef:
 			idx := sqlbase.IndexDescriptor{
 				Name:             string(d.Name),
-				Unique:           true,
 				StoreColumnNames: d.Storing.ToStrings(),
 				Version:          indexEncodingVersion,
 			}
+			idx.SetUniqueDeferrability(d.Deferrability)
 			if d.Sharded != nil {
 				if n.Interleave != nil && d.PrimaryKey {
 					return desc, pgerror.New(pgcode.FeatureNotSupported, "interleaved indexes cannot also be hash sharded")
@@ -1490,7 +1503,8 @@ func MakeTableDesc(
 			if d.Interleave != nil {
 				return desc, unimplemented.NewWithIssue(9148, "use CREATE INDEX to make interleaved indexes")
 			}
-		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef:
+		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef,
+			*tree.ExcludeConstraintTableDef:
 			// pass, handled below.
 
 		default:
@@ -1628,6 +1642,9 @@ func MakeTableDesc(
 	}
 
 	generatedNames := map[string]struct{}{}
+	// exclusionIndexes holds the name of the index backing each exclusion
+	// constraint, or "" if it uses an existing index.
+	var exclusionIndexes []string
 	for _, def := range n.Defs {
 		switch d := def.(type) {
 		case *tree.ColumnTableDef:
@@ -1643,6 +1660,27 @@ func MakeTableDesc(
 			}
 			desc.Checks = append(desc.Checks, ck)
 
+		case *tree.ExcludeConstraintTableDef:
+			c, err := MakeExclusionConstraint(ctx, &desc, d, generatedNames, semaCtx, n.Table)
+			if err != nil {
+				return desc, err
+			}
+			c.Validity = sqlbase.ConstraintValidity_Validated
+			idx, err := makeExclusionIndex(&desc, c)
+			if err != nil {
+				return desc, err
+			}
+			if idx != nil {
+				idx.Version = indexEncodingVersion
+				if err := desc.AddIndex(*idx, false); err != nil {
+					return desc, err
+				}
+				exclusionIndexes = append(exclusionIndexes, idx.Name)
+			} else {
+				exclusionIndexes = append(exclusionIndexes, "")
+			}
+			desc.Exclusions = append(desc.Exclusions, *c)
+
 		case *tree.ForeignKeyConstraintTableDef:
 			if err := ResolveFK(ctx, txn, fkResolver, &desc, d, affected, NewTable, tree.ValidationDefault, st); err != nil {
 				return desc, err
@@ -1672,6 +1710,19 @@ func MakeTableDesc(
 	// See https://github.com/golang/go/issues/23188.
 	err := desc.AllocateIDs()
 
+	// Record the IDs of the indexes backing the exclusion constraints, which
+	// were only allocated above.
+	for i, name := range exclusionIndexes {
+		if name == "" || err != nil {
+			continue
+		}
+		idx, _, findErr := desc.FindIndexByName(name)
+		if findErr != nil {
+			return desc, findErr
+		}
+		desc.Exclusions[i].IndexID = idx.ID
+	}
+
 	// Record the types of indexes that the table has.
 	if err := desc.ForeachNonDropIndex(func(idx *sqlbase.IndexDescriptor) error {
 		if idx.IsSharded() {
@@ -1875,6 +1926,7 @@ func replaceLikeTableOpts(n *tree.CreateTable, params runParams) (tree.TableDefs
 			if c.ComputeExpr != nil {
 				if opts.Has(tree.LikeTableOptGenerated) {
 					def.Computed.Computed = true
+					def.Computed.Virtual = c.Virtual
 					def.Computed.Expr, err = parser.ParseExpr(*c.ComputeExpr)
 					if err != nil {
 						return nil, err
@@ -1925,7 +1977,7 @@ func replaceLikeTableOpts(n *tree.CreateTable, params runParams) (tree.TableDefs
 					indexDef.Storing = append(indexDef.Storing, tree.Name(name))
 				}
 				var def tree.TableDef = &indexDef
-				if idx.Unique {
+				if idx.Unique || idx.UniqueDeferrable {
 					isPK := idx.ID == td.PrimaryIndex.ID
 					if isPK && td.IsPrimaryIndexDefaultRowID() {
 						continue
@@ -1934,6 +1986,7 @@ func replaceLikeTableOpts(n *tree.CreateTable, params runParams) (tree.TableDefs
 					def = &tree.UniqueConstraintTableDef{
 						IndexTableDef: indexDef,
 						PrimaryKey:    isPK,
+						Deferrability: idx.UniqueDeferrability(),
 					}
 				}
 				defs = append(defs, def)
@@ -2179,6 +2232,13 @@ func validateComputedColumn(
 		)
 	}
 
+	if d.IsVirtual() && d.HasColumnFamily() {
+		return pgerror.New(
+			pgcode.InvalidTableDefinition,
+			"virtual computed columns cannot be part of a column family",
+		)
+	}
+
 	dependencies := make(map[sqlbase.ColumnID]struct{})
 	// First, check that no column in the expression is a computed column.
 	if err := iterColDescriptorsInExpr(desc, d.Computed.Expr, func(c *sqlbase.ColumnDescriptor) error {
@@ -2323,12 +2383,226 @@ func MakeCheckConstraint(
 	}, nil
 }
 
+// generateNameForExclusionConstraint generates a unique name for an
+// exclusion constraint on the given columns.
+func generateNameForExclusionConstraint(
+	desc *MutableTableDescriptor, colIDs sqlbase.ColumnIDs, inuseNames map[string]struct{},
+) (string, error) {
+	colNames, err := desc.NamesForColumnIDs(colIDs)
+	if err != nil {
+		return "", err
+	}
+	name := "excl"
+	if len(colNames) > 0 {
+		name += "_" + strings.Join(colNames, "_")
+	}
+	// If generated name isn't unique, attempt to add a number to the end to
+	// get a unique name.
+	if _, ok := inuseNames[name]; ok {
+		i := 1
+		for {
+			appended := fmt.Sprintf("%s%d", name, i)
+			if _, ok := inuseNames[appended]; !ok {
+				name = appended
+				break
+			}
+			i++
+		}
+	}
+	if inuseNames != nil {
+		inuseNames[name] = struct{}{}
+	}
+	return name, nil
+}
+
+// MakeExclusionConstraint makes a descriptor representation of an exclusion
+// constraint from a def.
+func MakeExclusionConstraint(
+	ctx context.Context,
+	desc *sqlbase.MutableTableDescriptor,
+	d *tree.ExcludeConstraintTableDef,
+	inuseNames map[string]struct{},
+	semaCtx *tree.SemaContext,
+	tableName tree.TableName,
+) (*sqlbase.TableDescriptor_ExclusionConstraint, error) {
+	sourceInfo := sqlbase.NewSourceInfoForSingleTable(
+		tableName, sqlbase.ResultColumnsFromColDescs(desc.TableDesc().AllNonDropColumns()),
+	)
+	colIDsUsed := make(map[sqlbase.ColumnID]struct{})
+	// typeCheck verifies that the given expression only references columns of
+	// the table and that it has the expected type.
+	typeCheck := func(expr tree.Expr, typ *types.T) error {
+		expr, colIDs, err := replaceVars(desc, expr)
+		if err != nil {
+			return err
+		}
+		for colID := range colIDs {
+			colIDsUsed[colID] = struct{}{}
+		}
+		_, err = sqlbase.SanitizeVarFreeExpr(expr, typ, "EXCLUDE", semaCtx, false /* allowImpure */)
+		return err
+	}
+
+	c := &sqlbase.TableDescriptor_ExclusionConstraint{
+		Name:     string(d.Name),
+		Elements: make([]sqlbase.TableDescriptor_ExclusionConstraint_Element, len(d.Elems)),
+	}
+	for i, elem := range d.Elems {
+		r, isRange, err := sqlbase.MakeExclusionRange(elem.Expr)
+		if err != nil {
+			return nil, err
+		}
+		if isRange {
+			if elem.Operator != tree.Overlaps {
+				return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+					"operator %s is not supported for %s in EXCLUDE constraints", elem.Operator, r.Constructor)
+			}
+			if err := typeCheck(r.Lower, r.BoundType); err != nil {
+				return nil, err
+			}
+			if err := typeCheck(r.Upper, r.BoundType); err != nil {
+				return nil, err
+			}
+		} else {
+			// The element must support the operator when compared with itself.
+			dummy, colIDs, err := replaceVars(desc, elem.Expr)
+			if err != nil {
+				return nil, err
+			}
+			for colID := range colIDs {
+				colIDsUsed[colID] = struct{}{}
+			}
+			if _, err := sqlbase.SanitizeVarFreeExpr(
+				&tree.ComparisonExpr{Operator: elem.Operator, Left: dummy, Right: dummy},
+				types.Bool, "EXCLUDE", semaCtx, false, /* allowImpure */
+			); err != nil {
+				return nil, err
+			}
+		}
+		expr, err := dequalifyColumnRefs(ctx, sourceInfo, elem.Expr)
+		if err != nil {
+			return nil, err
+		}
+		c.Elements[i].Expr = tree.Serialize(expr)
+		if elem.Operator == tree.Overlaps {
+			c.Elements[i].Operator = sqlbase.TableDescriptor_ExclusionConstraint_Element_OVERLAPS
+		} else {
+			c.Elements[i].Operator = sqlbase.TableDescriptor_ExclusionConstraint_Element_EQUAL
+		}
+	}
+	if d.Where != nil {
+		if err := typeCheck(d.Where, types.Bool); err != nil {
+			return nil, err
+		}
+		pred, err := dequalifyColumnRefs(ctx, sourceInfo, d.Where)
+		if err != nil {
+			return nil, err
+		}
+		c.Predicate = tree.Serialize(pred)
+	}
+
+	c.ColumnIDs = make([]sqlbase.ColumnID, 0, len(colIDsUsed))
+	for colID := range colIDsUsed {
+		c.ColumnIDs = append(c.ColumnIDs, colID)
+	}
+	sort.Sort(sqlbase.ColumnIDs(c.ColumnIDs))
+
+	if c.Name == "" {
+		var err error
+		c.Name, err = generateNameForExclusionConstraint(desc, c.ColumnIDs, inuseNames)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// makeExclusionIndex returns an index that allows the conflicts of the given
+// exclusion constraint to be found efficiently. The index is keyed on the
+// columns compared for equality followed by the lower bound of the first
+// range, if those are plain columns. It returns nil if there are no such
+// columns or if an existing index already starts with them. The callers record
+// the ID of the new index in the constraint, so that the index can be dropped
+// together with the constraint.
+func makeExclusionIndex(
+	desc *sqlbase.MutableTableDescriptor, c *sqlbase.TableDescriptor_ExclusionConstraint,
+) (*sqlbase.IndexDescriptor, error) {
+	def, err := c.TableDef()
+	if err != nil {
+		return nil, err
+	}
+	var cols tree.IndexElemList
+	seen := make(map[tree.Name]struct{})
+	addColumn := func(expr tree.Expr) bool {
+		name, ok := expr.(*tree.UnresolvedName)
+		if !ok || name.NumParts != 1 {
+			return false
+		}
+		colName := tree.Name(name.Parts[0])
+		col, dropped, err := desc.FindColumnByName(colName)
+		if err != nil || dropped || !sqlbase.ColumnTypeIsIndexable(&col.Type) {
+			return false
+		}
+		if _, ok := seen[colName]; !ok {
+			seen[colName] = struct{}{}
+			cols = append(cols, tree.IndexElem{Column: colName, Direction: tree.Ascending})
+		}
+		return true
+	}
+	for _, elem := range def.Elems {
+		if elem.Operator == tree.EQ {
+			addColumn(elem.Expr)
+		}
+	}
+	for _, elem := range def.Elems {
+		r, isRange, err := sqlbase.MakeExclusionRange(elem.Expr)
+		if err != nil {
+			return nil, err
+		}
+		if isRange {
+			addColumn(r.Lower)
+			break
+		}
+	}
+	if len(cols) == 0 {
+		return nil, nil
+	}
+
+	found := false
+	if err := desc.ForeachNonDropIndex(func(idx *sqlbase.IndexDescriptor) error {
+		if found || len(idx.ColumnNames) < len(cols) {
+			return nil
+		}
+		for i := range cols {
+			if idx.ColumnNames[i] != string(cols[i].Column) {
+				return nil
+			}
+		}
+		found = true
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if found {
+		return nil, nil
+	}
+
+	idx := &sqlbase.IndexDescriptor{Name: c.Name + "_idx"}
+	if err := idx.FillColumns(cols); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
 // incTelemetryForNewColumn increments relevant telemetry every time a new column
 // is added to a table.
 func incTelemetryForNewColumn(d *tree.ColumnTableDef) {
 	telemetry.Inc(sqltelemetry.SchemaNewTypeCounter(d.Type.TelemetryName()))
 	if d.IsComputed() {
 		telemetry.Inc(sqltelemetry.SchemaNewColumnTypeQualificationCounter("computed"))
+		if d.IsVirtual() {
+			telemetry.Inc(sqltelemetry.SchemaNewColumnTypeQualificationCounter("virtual"))
+		}
 	}
 	if d.HasDefaultExpr() {
 		telemetry.Inc(sqltelemetry.SchemaNewColumnTypeQualificationCounter("default_expr"))
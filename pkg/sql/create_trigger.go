@@ -0,0 +1,182 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+)
+
+type createTriggerNode struct {
+	n         *tree.CreateTrigger
+	tableDesc *sqlbase.MutableTableDescriptor
+}
+
+// CreateTrigger creates a row-level trigger on a table.
+// Privileges: CREATE on table.
+//   notes: postgres requires TRIGGER on the table.
+func (p *planner) CreateTrigger(ctx context.Context, n *tree.CreateTrigger) (planNode, error) {
+	tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, true /* required */, ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &createTriggerNode{n: n, tableDesc: tableDesc}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE TRIGGER performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createTriggerNode) ReadingOwnWrites() {}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+	tableDesc := n.tableDesc
+
+	if _, err := tableDesc.FindTriggerByName(string(n.n.Name)); err == nil {
+		return pgerror.Newf(pgcode.DuplicateObject,
+			"trigger %q for relation %q already exists", string(n.n.Name), tableDesc.Name)
+	}
+
+	trigger, err := makeTriggerDescriptor(n.n, tableDesc)
+	if err != nil {
+		return err
+	}
+	// Type check the trigger's expressions now, so that a broken trigger
+	// cannot make the table unwritable.
+	if _, err := compileTrigger(&trigger, tableDesc.TableDesc(), p.SessionData().SearchPath); err != nil {
+		return err
+	}
+
+	tableDesc.Triggers = append(tableDesc.Triggers, trigger)
+	sort.Slice(tableDesc.Triggers, func(i, j int) bool {
+		return tableDesc.Triggers[i].Name < tableDesc.Triggers[j].Name
+	})
+
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("trigger"))
+
+	if err := tableDesc.Validate(ctx, p.txn); err != nil {
+		return err
+	}
+
+	return p.writeSchemaChange(
+		ctx, tableDesc, sqlbase.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()))
+}
+
+func (n *createTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createTriggerNode) Close(context.Context)        {}
+
+var triggerEventToDesc = map[tree.TriggerEvent]sqlbase.TriggerDescriptor_Event{
+	tree.TriggerInsert: sqlbase.TriggerDescriptor_INSERT,
+	tree.TriggerUpdate: sqlbase.TriggerDescriptor_UPDATE,
+	tree.TriggerDelete: sqlbase.TriggerDescriptor_DELETE,
+}
+
+var triggerEventFromDesc = map[sqlbase.TriggerDescriptor_Event]tree.TriggerEvent{
+	sqlbase.TriggerDescriptor_INSERT: tree.TriggerInsert,
+	sqlbase.TriggerDescriptor_UPDATE: tree.TriggerUpdate,
+	sqlbase.TriggerDescriptor_DELETE: tree.TriggerDelete,
+}
+
+// makeTriggerDescriptor builds the descriptor of the trigger defined by a
+// CREATE TRIGGER statement. The expressions of the trigger are stored but not
+// type checked.
+func makeTriggerDescriptor(
+	n *tree.CreateTrigger, tableDesc *sqlbase.MutableTableDescriptor,
+) (sqlbase.TriggerDescriptor, error) {
+	t := sqlbase.TriggerDescriptor{Name: string(n.Name)}
+
+	seen := make(map[tree.TriggerEvent]bool, len(n.Events))
+	for _, e := range n.Events {
+		if seen[e] {
+			return t, pgerror.Newf(pgcode.Syntax, "duplicate trigger event %s", e)
+		}
+		seen[e] = true
+		t.Events = append(t.Events, triggerEventToDesc[e])
+	}
+
+	switch n.ActionTime {
+	case tree.TriggerBefore:
+		t.ActionTime = sqlbase.TriggerDescriptor_BEFORE
+		if n.Stmt != nil {
+			return t, pgerror.New(pgcode.InvalidObjectDefinition,
+				"BEFORE triggers must use SET, not EXECUTE")
+		}
+		if seen[tree.TriggerDelete] {
+			return t, pgerror.New(pgcode.InvalidObjectDefinition,
+				"DELETE triggers cannot assign to the new row")
+		}
+	case tree.TriggerAfter:
+		t.ActionTime = sqlbase.TriggerDescriptor_AFTER
+		if n.Stmt == nil {
+			return t, pgerror.New(pgcode.InvalidObjectDefinition,
+				"AFTER triggers must use EXECUTE, not SET")
+		}
+		t.Statement = tree.AsStringWithFlags(n.Stmt, tree.FmtParsable)
+	}
+
+	if n.When != nil {
+		t.WhenExpr = tree.Serialize(n.When)
+	}
+
+	assigned := make(map[sqlbase.ColumnID]bool)
+	for _, set := range n.Set {
+		exprs := tree.Exprs{set.Expr}
+		if set.Tuple {
+			tuple, ok := set.Expr.(*tree.Tuple)
+			if !ok {
+				return t, pgerror.Newf(pgcode.FeatureNotSupported,
+					"source for a multiple-column trigger SET item must be a tuple")
+			}
+			exprs = tuple.Exprs
+		}
+		if len(exprs) != len(set.Names) {
+			return t, pgerror.Newf(pgcode.Syntax,
+				"number of columns (%d) does not match number of values (%d)",
+				len(set.Names), len(exprs))
+		}
+		for i, name := range set.Names {
+			col, err := tableDesc.FindActiveColumnByName(string(name))
+			if err != nil {
+				return t, err
+			}
+			if col.IsComputed() {
+				return t, sqlbase.CannotWriteToComputedColError(col.Name)
+			}
+			if assigned[col.ID] {
+				return t, pgerror.Newf(pgcode.Syntax,
+					"multiple assignments to the same column %q", col.Name)
+			}
+			assigned[col.ID] = true
+			t.Assignments = append(t.Assignments, sqlbase.TriggerDescriptor_Assignment{
+				ColumnID: col.ID,
+				Expr:     tree.Serialize(exprs[i]),
+			})
+		}
+	}
+	return t, nil
+}
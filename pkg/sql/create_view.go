@@ -30,12 +30,13 @@ type createViewNode struct {
 	viewName tree.Name
 	// viewQuery contains the view definition, with all table names fully
 	// qualified.
-	viewQuery   string
-	ifNotExists bool
-	replace     bool
-	temporary   bool
-	dbDesc      *sqlbase.DatabaseDescriptor
-	columns     sqlbase.ResultColumns
+	viewQuery    string
+	ifNotExists  bool
+	replace      bool
+	temporary    bool
+	materialized bool
+	dbDesc       *sqlbase.DatabaseDescriptor
+	columns      sqlbase.ResultColumns
 
 	// planDeps tracks which tables and views the view being created
 	// depends on. This is collected during the construction of
@@ -49,7 +50,11 @@ type createViewNode struct {
 func (n *createViewNode) ReadingOwnWrites() {}
 
 func (n *createViewNode) startExec(params runParams) error {
-	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("view"))
+	if n.materialized {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("materialized_view"))
+	} else {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("view"))
+	}
 
 	viewName := string(n.viewName)
 	isTemporary := n.temporary
@@ -96,7 +101,7 @@ func (n *createViewNode) startExec(params runParams) error {
 			if err := params.p.CheckPrivilege(params.ctx, desc, privilege.DROP); err != nil {
 				return err
 			}
-			if !desc.IsView() {
+			if !desc.IsView() || desc.MaterializedView() {
 				return pgerror.Newf(pgcode.WrongObjectType, `%q is not a view`, viewName)
 			}
 			replacingDesc = desc
@@ -142,6 +147,7 @@ func (n *createViewNode) startExec(params runParams) error {
 			&params.p.semaCtx,
 			params.p.EvalContext(),
 			isTemporary,
+			n.materialized,
 		)
 		if err != nil {
 			return err
@@ -154,9 +160,12 @@ func (n *createViewNode) startExec(params runParams) error {
 
 		// TODO (lucy): I think this needs a NodeFormatter implementation. For now,
 		// do some basic string formatting (not accurate in the general case).
+		jobDesc := fmt.Sprintf("CREATE VIEW %q AS %q", n.viewName, n.viewQuery)
+		if n.materialized {
+			jobDesc = fmt.Sprintf("CREATE MATERIALIZED VIEW %q AS %q", n.viewName, n.viewQuery)
+		}
 		if err = params.p.createDescriptorWithID(
-			params.ctx, tKey.Key(), id, &desc, params.EvalContext().Settings,
-			fmt.Sprintf("CREATE VIEW %q AS %q", n.viewName, n.viewQuery),
+			params.ctx, tKey.Key(), id, &desc, params.EvalContext().Settings, jobDesc,
 		); err != nil {
 			return err
 		}
@@ -229,6 +238,11 @@ func (n *createViewNode) Close(ctx context.Context)  {}
 // dependencies in the same transaction that the view is created and it
 // doesn't matter if reads/writes use a cached descriptor that doesn't
 // include the back-references.
+//
+// Materialized views are the exception: they are stored like tables, with
+// a hidden primary key, and are created in the ADDING state so that the
+// schema changer populates them from the view query the same way it
+// populates the table created by CREATE TABLE ... AS.
 func makeViewTableDesc(
 	viewName string,
 	viewQuery string,
@@ -241,6 +255,7 @@ func makeViewTableDesc(
 	semaCtx *tree.SemaContext,
 	evalCtx *tree.EvalContext,
 	temporary bool,
+	materialized bool,
 ) (sqlbase.MutableTableDescriptor, error) {
 	desc := InitTableDescriptor(
 		id,
@@ -252,6 +267,11 @@ func makeViewTableDesc(
 		temporary,
 	)
 	desc.ViewQuery = viewQuery
+	if materialized {
+		desc.IsMaterializedView = true
+		desc.State = sqlbase.TableDescriptor_ADD
+		desc.CreateQuery = viewQuery
+	}
 	if err := addResultColumns(semaCtx, evalCtx, &desc, resultColumns); err != nil {
 		return sqlbase.MutableTableDescriptor{}, err
 	}
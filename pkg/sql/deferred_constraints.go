@@ -0,0 +1,402 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// deferredCheckBatchSize is the maximum number of keys checked by a single
+// query when the deferred constraints are checked.
+const deferredCheckBatchSize = 100
+
+// constraintMode is the checking mode of deferrable constraints, as set by SET
+// CONSTRAINTS.
+type constraintMode int8
+
+const (
+	// constraintModeDefault means that the mode was not set, and that the
+	// INITIALLY DEFERRED / INITIALLY IMMEDIATE attribute of the constraint
+	// applies.
+	constraintModeDefault constraintMode = iota
+	// constraintModeImmediate means that the constraint is checked at the end
+	// of each statement.
+	constraintModeImmediate
+	// constraintModeDeferred means that the constraint is checked before the
+	// transaction commits.
+	constraintModeDeferred
+)
+
+// deferrableCheck describes the check of a deferrable foreign key or unique
+// constraint, performed by an errorIfRowsNode (see
+// execFactory.ConstructDeferrableFKCheck and
+// execFactory.ConstructDeferrableUniqueCheck).
+type deferrableCheck struct {
+	name          string
+	deferrability tree.ConstraintDeferrability
+
+	// unique is set for the check of a unique constraint. The constraint is on
+	// the origin columns, and the referenced table and columns are unused.
+	unique bool
+
+	originTableID     sqlbase.ID
+	originColIDs      []sqlbase.ColumnID
+	referencedTableID sqlbase.ID
+	referencedColIDs  []sqlbase.ColumnID
+
+	// keyCols are the ordinals of the key columns in the rows produced by the
+	// check query, in the order of the constraint columns.
+	keyCols []exec.ColumnOrdinal
+
+	// mkErr creates the foreign key violation error, given the key values.
+	mkErr func(keyVals tree.Datums) error
+}
+
+// keyVals extracts the values of the key columns from a row produced by the
+// check query.
+func (c *deferrableCheck) keyVals(row tree.Datums) tree.Datums {
+	keyVals := make(tree.Datums, len(c.keyCols))
+	for i, col := range c.keyCols {
+		keyVals[i] = row[col]
+	}
+	return keyVals
+}
+
+// deferredKey is a key saved by a deferred check. The key violated the
+// constraint at the time it was saved; the constraint is checked again for the
+// key before the transaction commits.
+type deferredKey struct {
+	check   *deferrableCheck
+	keyVals tree.Datums
+}
+
+// deferredConstraints holds the state of the deferred constraints of a SQL
+// transaction: the checking modes set by SET CONSTRAINTS, and the keys saved
+// by the deferred checks.
+//
+// The state is saved by savepoints and restored by ROLLBACK TO SAVEPOINT. To
+// make this cheap, keys are only ever appended and byName is never modified in
+// place, so a snapshot is a shallow copy.
+type deferredConstraints struct {
+	// all is the mode set by SET CONSTRAINTS ALL.
+	all constraintMode
+	// byName holds the modes set by SET CONSTRAINTS for constraints specified
+	// by name. They take precedence over all.
+	byName map[string]constraintMode
+	// keys holds the keys saved by the deferred checks.
+	keys []deferredKey
+}
+
+// isDeferred returns whether the checks of the constraint with the given name
+// and deferrability are currently deferred.
+func (dc *deferredConstraints) isDeferred(
+	name string, deferrability tree.ConstraintDeferrability,
+) bool {
+	if deferrability == tree.NotDeferrable {
+		return false
+	}
+	mode := dc.byName[name]
+	if mode == constraintModeDefault {
+		mode = dc.all
+	}
+	if mode == constraintModeDefault {
+		return deferrability == tree.DeferrableInitiallyDeferred
+	}
+	return mode == constraintModeDeferred
+}
+
+// setMode implements SET CONSTRAINTS. If names is empty, the mode is set for
+// all the constraints.
+func (dc *deferredConstraints) setMode(names []string, mode constraintMode) {
+	if len(names) == 0 {
+		dc.all = mode
+		dc.byName = nil
+		return
+	}
+	byName := make(map[string]constraintMode, len(dc.byName)+len(names))
+	for name, m := range dc.byName {
+		byName[name] = m
+	}
+	for _, name := range names {
+		byName[name] = mode
+	}
+	dc.byName = byName
+}
+
+// deferKey saves a key violating a deferred constraint.
+func (dc *deferredConstraints) deferKey(check *deferrableCheck, keyVals tree.Datums) {
+	dc.keys = append(dc.keys, deferredKey{check: check, keyVals: keyVals})
+}
+
+var _ row.FKDeferrer = &deferredConstraints{}
+
+// DeferFKViolation implements the row.FKDeferrer interface. It is used by the
+// foreign key checks performed by the row writers (the legacy foreign key
+// paths, e.g. with cascades).
+func (dc *deferredConstraints) DeferFKViolation(
+	fk *sqlbase.ForeignKeyConstraint, keyVals tree.Datums, mkErr func(keyVals tree.Datums) error,
+) bool {
+	if !dc.isDeferred(fk.Name, fk.Deferrability()) {
+		return false
+	}
+	for _, d := range keyVals {
+		if d == tree.DNull {
+			// The deferred check cannot look up NULLs; such violations
+			// (MATCH FULL) are reported immediately.
+			return false
+		}
+	}
+	dc.deferKey(&deferrableCheck{
+		name:              fk.Name,
+		deferrability:     fk.Deferrability(),
+		originTableID:     fk.OriginTableID,
+		originColIDs:      fk.OriginColumnIDs,
+		referencedTableID: fk.ReferencedTableID,
+		referencedColIDs:  fk.ReferencedColumnIDs,
+		mkErr:             mkErr,
+	}, keyVals)
+	return true
+}
+
+// fkDeferrer returns the row.FKDeferrer used by the foreign key checks of the
+// row writers, or nil if the checks of the statement cannot be deferred.
+func (p *planner) fkDeferrer() row.FKDeferrer {
+	if dc := p.extendedEvalCtx.DeferredConstraints; dc != nil {
+		return dc
+	}
+	return nil
+}
+
+// hasKeys returns whether keys were saved by the deferred checks. It is false
+// if dc is nil.
+func (dc *deferredConstraints) hasKeys() bool {
+	return dc != nil && len(dc.keys) > 0
+}
+
+// snapshot returns a copy of the state that is not affected by later changes
+// to dc.
+func (dc *deferredConstraints) snapshot() deferredConstraints {
+	s := *dc
+	s.keys = dc.keys[:len(dc.keys):len(dc.keys)]
+	return s
+}
+
+// reset clears the state at the end of a transaction.
+func (dc *deferredConstraints) reset() {
+	*dc = deferredConstraints{}
+}
+
+// check checks the constraints for the keys saved by the deferred checks. If
+// filter is not nil, only the constraints for which it returns true are
+// checked. The first violation found is returned as an error.
+//
+// The saved keys are not removed: they are checked again if check is called
+// again, and in particular before the transaction commits.
+func (dc *deferredConstraints) check(
+	ctx context.Context,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	filter func(check *deferrableCheck) bool,
+) error {
+	type groupKey struct {
+		originTableID sqlbase.ID
+		name          string
+	}
+	type group struct {
+		check   *deferrableCheck
+		keys    []tree.Datums
+		keySeen map[string]struct{}
+	}
+	var groups []*group
+	groupByKey := make(map[groupKey]*group)
+	for _, k := range dc.keys {
+		if filter != nil && !filter(k.check) {
+			continue
+		}
+		gk := groupKey{originTableID: k.check.originTableID, name: k.check.name}
+		g, ok := groupByKey[gk]
+		if !ok {
+			g = &group{check: k.check, keySeen: make(map[string]struct{})}
+			groupByKey[gk] = g
+			groups = append(groups, g)
+		}
+		keyStr := tree.AsString(&k.keyVals)
+		if _, ok := g.keySeen[keyStr]; ok {
+			continue
+		}
+		g.keySeen[keyStr] = struct{}{}
+		g.keys = append(g.keys, k.keyVals)
+	}
+
+	for _, g := range groups {
+		for len(g.keys) > 0 {
+			batch := g.keys
+			if len(batch) > deferredCheckBatchSize {
+				batch = batch[:deferredCheckBatchSize]
+			}
+			g.keys = g.keys[len(batch):]
+
+			var query string
+			var args []interface{}
+			if g.check.unique {
+				query, args = deferredUniqueCheckQuery(g.check, batch)
+			} else {
+				query, args = deferredFKCheckQuery(g.check, batch)
+			}
+			log.VEventf(ctx, 2, "checking deferred constraint %q with query %q", g.check.name, query)
+			row, err := ie.QueryRowEx(
+				ctx, "check deferred constraint", txn,
+				sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+				query, args...,
+			)
+			if err != nil {
+				return err
+			}
+			if row != nil {
+				return g.check.mkErr(row)
+			}
+		}
+	}
+	return nil
+}
+
+// deferredCheckQueryBuilder helps build the queries of the deferred checks.
+type deferredCheckQueryBuilder struct {
+	buf     bytes.Buffer
+	args    []interface{}
+	numCols int
+}
+
+func (b *deferredCheckQueryBuilder) sep(i int, s string) {
+	if i > 0 {
+		b.buf.WriteString(s)
+	}
+}
+
+// writeList writes the comma-separated list of the format, instantiated for
+// each key column.
+func (b *deferredCheckQueryBuilder) writeList(format string) {
+	for i := 0; i < b.numCols; i++ {
+		b.sep(i, ", ")
+		fmt.Fprintf(&b.buf, format, i+1)
+	}
+}
+
+// writeKeys writes the VALUES clause of the keys, as the data source k with
+// columns k1, k2, ...
+func (b *deferredCheckQueryBuilder) writeKeys(keys []tree.Datums) {
+	b.buf.WriteString("(VALUES ")
+	for i, key := range keys {
+		b.sep(i, ", ")
+		b.buf.WriteByte('(')
+		for j, d := range key {
+			b.sep(j, ", ")
+			b.args = append(b.args, d)
+			fmt.Fprintf(&b.buf, "$%d", len(b.args))
+		}
+		b.buf.WriteByte(')')
+	}
+	b.buf.WriteString(") AS k (")
+	b.writeList("k%d")
+	b.buf.WriteByte(')')
+}
+
+// writeTable writes a query on the columns of the table, aliased as c1, c2,
+// ..., that selects the rows equal to the key k.
+func (b *deferredCheckQueryBuilder) writeTable(
+	target string, tableID sqlbase.ID, colIDs []sqlbase.ColumnID, alias string,
+) {
+	fmt.Fprintf(&b.buf, "SELECT %s FROM [%d(", target, tableID)
+	for i, colID := range colIDs {
+		b.sep(i, ", ")
+		fmt.Fprintf(&b.buf, "%d", colID)
+	}
+	fmt.Fprintf(&b.buf, ") AS %s (", alias)
+	b.writeList("c%d")
+	b.buf.WriteString(")] WHERE ")
+	for i := 0; i < b.numCols; i++ {
+		b.sep(i, " AND ")
+		fmt.Fprintf(&b.buf, "%s.c%d = k.k%d", alias, i+1, i+1)
+	}
+}
+
+// deferredFKCheckQuery generates a query that returns the first of the given
+// keys that violates the foreign key constraint of the check, i.e. a key that
+// is used by a row of the origin table but does not exist in the referenced
+// table. The keys cannot contain NULLs.
+//
+// For example, for a constraint on columns (a, b) and two keys, the query is:
+//
+//   SELECT k1, k2 FROM (VALUES ($1, $2), ($3, $4)) AS k (k1, k2)
+//   WHERE
+//     EXISTS (SELECT 1 FROM [<origin table ID>(<a ID>, <b ID>) AS o (c1, c2)]
+//             WHERE o.c1 = k.k1 AND o.c2 = k.k2)
+//     AND NOT EXISTS (SELECT 1 FROM [<referenced table ID>(...) AS r (c1, c2)]
+//                     WHERE r.c1 = k.k1 AND r.c2 = k.k2)
+//   LIMIT 1
+//
+func deferredFKCheckQuery(
+	check *deferrableCheck, keys []tree.Datums,
+) (string, []interface{}) {
+	b := deferredCheckQueryBuilder{
+		args:    make([]interface{}, 0, len(keys)*len(check.originColIDs)),
+		numCols: len(check.originColIDs),
+	}
+	b.buf.WriteString("SELECT ")
+	b.writeList("k%d")
+	b.buf.WriteString(" FROM ")
+	b.writeKeys(keys)
+	b.buf.WriteString(" WHERE EXISTS (")
+	b.writeTable("1", check.originTableID, check.originColIDs, "o")
+	b.buf.WriteString(") AND NOT EXISTS (")
+	b.writeTable("1", check.referencedTableID, check.referencedColIDs, "r")
+	b.buf.WriteString(") LIMIT 1")
+	return b.buf.String(), b.args
+}
+
+// deferredUniqueCheckQuery generates a query that returns the first of the
+// given keys that violates the unique constraint of the check, i.e. a key that
+// is used by more than one row of the table. The keys cannot contain NULLs.
+//
+// For example, for a constraint on columns (a, b) and two keys, the query is:
+//
+//   SELECT k1, k2 FROM (VALUES ($1, $2), ($3, $4)) AS k (k1, k2)
+//   WHERE
+//     (SELECT count(*) FROM [<table ID>(<a ID>, <b ID>) AS o (c1, c2)]
+//      WHERE o.c1 = k.k1 AND o.c2 = k.k2) > 1
+//   LIMIT 1
+//
+func deferredUniqueCheckQuery(
+	check *deferrableCheck, keys []tree.Datums,
+) (string, []interface{}) {
+	b := deferredCheckQueryBuilder{
+		args:    make([]interface{}, 0, len(keys)*len(check.originColIDs)),
+		numCols: len(check.originColIDs),
+	}
+	b.buf.WriteString("SELECT ")
+	b.writeList("k%d")
+	b.buf.WriteString(" FROM ")
+	b.writeKeys(keys)
+	b.buf.WriteString(" WHERE (")
+	b.writeTable("count(*)", check.originTableID, check.originColIDs, "o")
+	b.buf.WriteString(") > 1 LIMIT 1")
+	return b.buf.String(), b.args
+}
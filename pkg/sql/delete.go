@@ -62,6 +62,11 @@ type deleteRun struct {
 	// of the mutation. Otherwise, the value at the i-th index refers to the
 	// index of the resultRowBuffer where the i-th column is to be returned.
 	rowIdxToRetIdx []int
+
+	// numPassthrough is the number of columns in addition to the set of
+	// columns of the target table being returned, that we must pass through
+	// from the input node.
+	numPassthrough int
 }
 
 // maxDeleteBatchSize is the max number of entries in the KV batch for
@@ -169,6 +174,15 @@ func (d *deleteNode) BatchedNext(params runParams) (bool, error) {
 // processSourceRow processes one row from the source for deletion and, if
 // result rows are needed, saves it in the result row container
 func (d *deleteNode) processSourceRow(params runParams, sourceVals tree.Datums) error {
+	// Remove extra columns for the RETURNING clause that refer to other tables
+	// (from the USING clause of the delete).
+	var passthroughValues tree.Datums
+	if d.run.numPassthrough > 0 {
+		passthroughBegin := len(sourceVals) - d.run.numPassthrough
+		passthroughValues = sourceVals[passthroughBegin:]
+		sourceVals = sourceVals[:passthroughBegin]
+	}
+
 	// Queue the deletion in the KV batch.
 	if err := d.run.td.row(params.ctx, sourceVals, d.run.traceKV); err != nil {
 		return err
@@ -184,12 +198,24 @@ func (d *deleteNode) processSourceRow(params runParams, sourceVals tree.Datums)
 		// d.run.rows.NumCols() is guaranteed to only contain the requested
 		// public columns.
 		resultValues := make(tree.Datums, d.run.rows.NumCols())
+		largestRetIdx := -1
 		for i, retIdx := range d.run.rowIdxToRetIdx {
 			if retIdx >= 0 {
+				if retIdx >= largestRetIdx {
+					largestRetIdx = retIdx
+				}
 				resultValues[retIdx] = sourceVals[i]
 			}
 		}
 
+		// At this point we've extracted all the RETURNING values that are part
+		// of the target table. We must now extract the columns in the RETURNING
+		// clause that refer to other tables (from the USING clause of the delete).
+		for i := range passthroughValues {
+			largestRetIdx++
+			resultValues[largestRetIdx] = passthroughValues[i]
+		}
+
 		if _, err := d.run.rows.AddRow(params.ctx, resultValues); err != nil {
 			return err
 		}
@@ -216,6 +242,11 @@ func (d *deleteNode) Close(ctx context.Context) {
 }
 
 func canDeleteFastInterleaved(table *ImmutableTableDescriptor, fkTables row.FkTableMetadata) bool {
+	// Triggers must see every deleted row.
+	if len(table.Triggers) > 0 {
+		return false
+	}
+
 	// If there are no interleaved tables then don't take the fast path.
 	// This avoids superfluous use of DelRange in cases where there isn't as much of a performance boost.
 	hasInterleaved := false
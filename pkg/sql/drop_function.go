@@ -0,0 +1,99 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+)
+
+type dropFunctionNode struct {
+	n *tree.DropFunction
+	// dbDescs holds the descriptors of the databases containing the
+	// functions to drop, and toDrop the IDs of these functions, by database
+	// ID.
+	dbDescs map[sqlbase.ID]*sqlbase.DatabaseDescriptor
+	toDrop  map[sqlbase.ID][]sqlbase.ID
+}
+
+// DropFunction drops user-defined functions.
+// Privileges: DROP on function.
+//   notes: postgres requires ownership of the function.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	node := &dropFunctionNode{
+		n:       n,
+		dbDescs: make(map[sqlbase.ID]*sqlbase.DatabaseDescriptor),
+		toDrop:  make(map[sqlbase.ID][]sqlbase.ID),
+	}
+	for i := range n.Functions {
+		obj := &n.Functions[i]
+		dbDesc, fn, err := p.resolveFunctionObject(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
+		if fn == nil {
+			if n.IfExists {
+				continue
+			}
+			return nil, newUndefinedFunctionError(obj)
+		}
+		if err := p.CheckPrivilege(ctx, fn, privilege.DROP); err != nil {
+			return nil, err
+		}
+		// The functions of a database must all be removed from the same copy
+		// of its descriptor.
+		if _, ok := node.dbDescs[dbDesc.ID]; !ok {
+			node.dbDescs[dbDesc.ID] = dbDesc
+		}
+		node.toDrop[dbDesc.ID] = append(node.toDrop[dbDesc.ID], fn.ID)
+	}
+	if len(node.toDrop) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+	return node, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP FUNCTION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *dropFunctionNode) ReadingOwnWrites() {}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+
+	b := p.txn.NewBatch()
+	for dbID, fnIDs := range n.toDrop {
+		dbDesc := n.dbDescs[dbID]
+		for _, id := range fnIDs {
+			dbDesc.RemoveFunction(id)
+			telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("function"))
+		}
+		if err := dbDesc.Validate(); err != nil {
+			return err
+		}
+		if err := writeDescToBatch(
+			ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(), p.execCfg.Settings, b, dbDesc.ID, dbDesc,
+		); err != nil {
+			return err
+		}
+	}
+	return p.txn.Run(ctx, b)
+}
+
+func (n *dropFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropFunctionNode) Close(context.Context)        {}
@@ -159,7 +159,8 @@ func (*dropTableNode) Close(context.Context)        {}
 func (p *planner) prepareDrop(
 	ctx context.Context, name *tree.TableName, required bool, requiredType ResolveRequiredType,
 ) (*sqlbase.MutableTableDescriptor, error) {
-	tableDesc, err := p.ResolveMutableTableDescriptor(ctx, name, required, requiredType)
+	// Tables left offline by a failed or canceled revert can be dropped.
+	tableDesc, err := p.resolveMutableTableDescriptorAllowFailedRevert(ctx, name, required, requiredType)
 	if err != nil {
 		return nil, err
 	}
@@ -347,7 +348,7 @@ func (p *planner) initiateDropTable(
 	//
 	// TODO(bram): If interleaved and ON DELETE CASCADE, we will be able to use
 	// this faster mechanism.
-	if tableDesc.IsTable() && !tableDesc.IsInterleaved() {
+	if (tableDesc.IsTable() || tableDesc.MaterializedView()) && !tableDesc.IsInterleaved() {
 		// Get the zone config applying to this table in order to
 		// ensure there is a GC TTL.
 		_, _, _, err := GetZoneConfigInTxn(
@@ -0,0 +1,90 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+)
+
+type dropTriggerNode struct {
+	n         *tree.DropTrigger
+	tableDesc *sqlbase.MutableTableDescriptor
+}
+
+// DropTrigger drops a trigger from a table.
+// Privileges: CREATE on table.
+//   notes: postgres requires ownership of the table.
+func (p *planner) DropTrigger(ctx context.Context, n *tree.DropTrigger) (planNode, error) {
+	tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, !n.IfExists /* required */, ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		// IfExists specified and table did not exist -- noop.
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	if _, err := tableDesc.FindTriggerByName(string(n.Name)); err != nil {
+		if n.IfExists {
+			// Noop.
+			return newZeroNode(nil /* columns */), nil
+		}
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"trigger %q for table %q does not exist", string(n.Name), tableDesc.Name)
+	}
+
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &dropTriggerNode{n: n, tableDesc: tableDesc}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP TRIGGER performs multiple KV operations on descriptors
+// and expects to see its own writes.
+func (n *dropTriggerNode) ReadingOwnWrites() {}
+
+func (n *dropTriggerNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+	tableDesc := n.tableDesc
+
+	for i := range tableDesc.Triggers {
+		if tableDesc.Triggers[i].Name == string(n.n.Name) {
+			tableDesc.Triggers = append(tableDesc.Triggers[:i], tableDesc.Triggers[i+1:]...)
+			break
+		}
+	}
+
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("trigger"))
+
+	if err := tableDesc.Validate(ctx, p.txn); err != nil {
+		return err
+	}
+
+	return p.writeSchemaChange(
+		ctx, tableDesc, sqlbase.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()))
+}
+
+func (n *dropTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropTriggerNode) Close(context.Context)        {}
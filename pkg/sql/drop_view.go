@@ -44,6 +44,15 @@ func (p *planner) DropView(ctx context.Context, n *tree.DropView) (planNode, err
 			// IfExists specified and the view did not exist.
 			continue
 		}
+		if n.IsMaterialized && !droppedDesc.MaterializedView() {
+			return nil, sqlbase.NewWrongObjectTypeError(tn, "materialized view")
+		}
+		if !n.IsMaterialized && droppedDesc.MaterializedView() {
+			return nil, errors.WithHint(
+				sqlbase.NewWrongObjectTypeError(tn, "view"),
+				"use DROP MATERIALIZED VIEW to remove a materialized view",
+			)
+		}
 
 		td = append(td, toDelete{tn, droppedDesc})
 	}
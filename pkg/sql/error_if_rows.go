@@ -25,6 +25,11 @@ type errorIfRowsNode struct {
 	// produced.
 	mkErr func(values tree.Datums) error
 
+	// fkCheck is set if the node performs the check of a deferrable foreign key
+	// constraint. If the constraint is deferred, the node saves the keys of all
+	// the rows produced by the wrapped node instead of returning an error.
+	check *deferrableCheck
+
 	nexted bool
 }
 
@@ -38,6 +43,13 @@ func (n *errorIfRowsNode) Next(params runParams) (bool, error) {
 	}
 	n.nexted = true
 
+	if n.check != nil {
+		dc := params.extendedEvalCtx.DeferredConstraints
+		if dc != nil && dc.isDeferred(n.check.name, n.check.deferrability) {
+			return false, n.deferKeys(params, dc)
+		}
+	}
+
 	ok, err := n.plan.Next(params)
 	if err != nil {
 		return false, err
@@ -48,6 +60,26 @@ func (n *errorIfRowsNode) Next(params runParams) (bool, error) {
 	return false, nil
 }
 
+// deferKeys saves the keys of all the rows produced by the wrapped node, to be
+// checked before the transaction commits.
+func (n *errorIfRowsNode) deferKeys(params runParams, dc *deferredConstraints) error {
+	for {
+		ok, err := n.plan.Next(params)
+		if err != nil || !ok {
+			return err
+		}
+		keyVals := n.check.keyVals(n.plan.Values())
+		for _, d := range keyVals {
+			if d == tree.DNull {
+				// A MATCH FULL key with both NULL and non-NULL values: the check
+				// doesn't depend on other rows and cannot be deferred.
+				return n.check.mkErr(keyVals)
+			}
+		}
+		dc.deferKey(n.check, keyVals)
+	}
+}
+
 func (n *errorIfRowsNode) Values() tree.Datums {
 	return nil
 }
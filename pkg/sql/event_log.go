@@ -108,6 +108,25 @@ type EventLogSetClusterSettingDetail struct {
 	User        string
 }
 
+// channel returns the logging channel of the events of the given type.
+func (t EventLogType) channel() log.ChannelLogger {
+	switch t {
+	case EventLogNodeJoin, EventLogNodeRestart, EventLogNodeDecommissioned,
+		EventLogNodeRecommissioned, EventLogSetClusterSetting, EventLogSetZoneConfig,
+		EventLogRemoveZoneConfig:
+		return log.Ops
+	default:
+		return log.SQLSchema
+	}
+}
+
+// eventLogPayload is the payload of the log entries of the events.
+type eventLogPayload struct {
+	EventType EventLogType
+	TargetID  int32
+	Info      interface{} `json:",omitempty"`
+}
+
 // An EventLogger exposes methods used to record events to the event table.
 type EventLogger struct {
 	*InternalExecutor
@@ -129,8 +148,13 @@ func (ev EventLogger) InsertEventRecord(
 ) error {
 	// Record event record insertion in local log output.
 	txn.AddCommitTrigger(func(ctx context.Context) {
-		log.Infof(
-			ctx, "Event: %q, target: %d, info: %+v",
+		eventType.channel().Structuredf(
+			ctx, log.Severity_INFO, eventLogPayload{
+				EventType: eventType,
+				TargetID:  targetID,
+				Info:      info,
+			},
+			"Event: %q, target: %d, info: %+v",
 			eventType,
 			targetID,
 			info,
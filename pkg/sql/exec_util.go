@@ -40,6 +40,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/contention"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsql"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -50,7 +51,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/ts/tspb"
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
@@ -599,6 +602,19 @@ type ExecutorConfig struct {
 
 	// StmtDiagnosticsRecorder deals with recording statement diagnostics.
 	StmtDiagnosticsRecorder StmtDiagnosticsRecorder
+
+	// ContentionRegistry retains the contention events encountered by the
+	// statements executed on this node.
+	ContentionRegistry *contention.Registry
+
+	// TimeSeriesServer serves the time series data queried through
+	// crdb_internal.timeseries_query().
+	TimeSeriesServer tspb.TimeSeriesServer
+
+	// IsMeta1Leaseholder returns whether this node holds the lease of the
+	// meta1 range. Background tasks which must run on a single node of the
+	// cluster only run on that node.
+	IsMeta1Leaseholder func(hlc.Timestamp) (bool, error)
 }
 
 // Organization returns the value of cluster.organization.
@@ -632,6 +648,13 @@ type StmtDiagnosticsRecorder interface {
 		traceJSON tree.Datum,
 		bundleZip []byte,
 	) (id int64, err error)
+
+	// InsertSlowQuery records a captured slow statement in
+	// system.slow_query_log, along with its diagnostics bundle, and trims the
+	// table to maxEntries rows. Returns the ID of the statement diagnostics.
+	InsertSlowQuery(
+		ctx context.Context, q stmtdiagnostics.SlowQuery, maxEntries int64,
+	) (diagID int64, err error)
 }
 
 // StmtDiagnosticsTraceFinishFunc is the type of function returned from
@@ -1882,6 +1905,14 @@ func (m *sessionDataMutator) SetForceSavepointRestart(val bool) {
 	m.data.ForceSavepointRestart = val
 }
 
+func (m *sessionDataMutator) SetProtectReadTimestamp(val bool) {
+	m.data.ProtectReadTimestamp = val
+}
+
+func (m *sessionDataMutator) SetTraceParent(val string) {
+	m.data.TraceParent = val
+}
+
 func (m *sessionDataMutator) SetZigzagJoinEnabled(val bool) {
 	m.data.ZigzagJoinEnabled = val
 }
@@ -1970,6 +2001,14 @@ type sqlStatsCollector struct {
 	appStats *appStats
 	// phaseTimes tracks session-level phase times.
 	phaseTimes phaseTimes
+	// previousPhaseTimes tracks the session-level phase times of the last
+	// statement executed by the execution engine. It is recorded once the
+	// execution of the statement ends, and is preserved by reset (which also
+	// runs for Prepare, COPY and the like). It is exposed through
+	// crdb_internal.session_last_query_statistics, which allows clients to
+	// tell the server-side latency of their statements from the network
+	// latency.
+	previousPhaseTimes phaseTimes
 }
 
 // newSQLStatsCollector creates an instance of sqlStatsCollector. Note that
@@ -1996,10 +2035,13 @@ func (s *sqlStatsCollector) recordStatement(
 	err error,
 	parseLat, planLat, runLat, svcLat, ovhLat float64,
 	bytesRead, rowsRead int64,
+	contentionSampled bool,
+	contentionTime float64,
 ) {
 	s.appStats.recordStatement(
 		stmt, samplePlanDescription, distSQLUsed, implicitTxn, automaticRetryCount, numRows, err,
-		parseLat, planLat, runLat, svcLat, ovhLat, bytesRead, rowsRead)
+		parseLat, planLat, runLat, svcLat, ovhLat, bytesRead, rowsRead,
+		contentionSampled, contentionTime)
 }
 
 // recordTransaction records stats for one transaction.
@@ -2008,9 +2050,11 @@ func (s *sqlStatsCollector) recordTransaction(txnTimeSec float64, ev txnEvent, i
 }
 
 func (s *sqlStatsCollector) reset(sqlStats *sqlStats, appStats *appStats, phaseTimes *phaseTimes) {
+	previousPhaseTimes := s.previousPhaseTimes
 	*s = sqlStatsCollector{
-		sqlStats:   sqlStats,
-		appStats:   appStats,
-		phaseTimes: *phaseTimes,
+		sqlStats:           sqlStats,
+		appStats:           appStats,
+		phaseTimes:         *phaseTimes,
+		previousPhaseTimes: previousPhaseTimes,
 	}
 }
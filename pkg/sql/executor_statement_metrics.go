@@ -17,6 +17,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/opentracing/opentracing-go"
 )
 
 // SQL execution is separated in 3+ phases:
@@ -105,6 +107,8 @@ func (ex *connExecutor) recordStatementSummary(
 	err error,
 	bytesRead int64,
 	rowsRead int64,
+	contentionSampled bool,
+	contentionTime time.Duration,
 ) {
 	phaseTimes := &ex.statsCollector.phaseTimes
 
@@ -141,8 +145,12 @@ func (ex *connExecutor) recordStatementSummary(
 			m.DistSQLExecLatency.RecordValue(runLatRaw.Nanoseconds())
 			m.DistSQLServiceLatency.RecordValue(svcLatRaw.Nanoseconds())
 		}
-		m.SQLExecLatency.RecordValue(runLatRaw.Nanoseconds())
-		m.SQLServiceLatency.RecordValue(svcLatRaw.Nanoseconds())
+		// If the statement was traced, its latencies become the exemplars of
+		// their buckets, which links them to the trace in the OpenMetrics
+		// exposition.
+		traceID, _ := tracing.ExemplarTraceID(opentracing.SpanFromContext(ctx))
+		m.SQLExecLatency.RecordValueWithExemplar(runLatRaw.Nanoseconds(), traceID)
+		m.SQLServiceLatency.RecordValueWithExemplar(svcLatRaw.Nanoseconds(), traceID)
 	}
 
 	ex.statsCollector.recordStatement(
@@ -150,6 +158,7 @@ func (ex *connExecutor) recordStatementSummary(
 		flags.IsSet(planFlagDistributed), flags.IsSet(planFlagImplicitTxn),
 		automaticRetryCount, rowsAffected, err,
 		parseLat, planLat, runLat, svcLat, execOverhead, bytesRead, rowsRead,
+		contentionSampled, contentionTime.Seconds(),
 	)
 
 	if log.V(2) {
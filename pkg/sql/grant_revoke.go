@@ -22,16 +22,18 @@ import (
 
 // Grant adds privileges to users.
 // Current status:
-// - Target: single database, table, or view.
+// - Target: single database, table, view, or function.
 // TODO(marc): open questions:
 // - should we have root always allowed and not present in the permissions list?
 // - should we make users case-insensitive?
-// Privileges: GRANT on database/table/view.
+// Privileges: GRANT on database/table/view/function.
 //   Notes: postgres requires the object owner.
 //          mysql requires the "grant option" and the same privileges, and sometimes superuser.
 func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
 	if n.Targets.Databases != nil {
 		sqltelemetry.IncIAMGrantPrivilegesCounter(sqltelemetry.OnDatabase)
+	} else if n.Targets.Functions != nil {
+		sqltelemetry.IncIAMGrantPrivilegesCounter(sqltelemetry.OnFunction)
 	} else {
 		sqltelemetry.IncIAMGrantPrivilegesCounter(sqltelemetry.OnTable)
 	}
@@ -40,7 +42,9 @@ func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
 		targets:      n.Targets,
 		grantees:     n.Grantees,
 		desiredprivs: n.Privileges,
-		changePrivilege: func(privDesc *sqlbase.PrivilegeDescriptor, grantee string) {
+		changePrivilege: func(
+			privDesc *sqlbase.PrivilegeDescriptor, grantee string, _ privilege.ObjectType,
+		) {
 			privDesc.Grant(grantee, n.Privileges)
 		},
 	}, nil
@@ -48,16 +52,18 @@ func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
 
 // Revoke removes privileges from users.
 // Current status:
-// - Target: single database, table, or view.
+// - Target: single database, table, view, or function.
 // TODO(marc): open questions:
 // - should we have root always allowed and not present in the permissions list?
 // - should we make users case-insensitive?
-// Privileges: GRANT on database/table/view.
+// Privileges: GRANT on database/table/view/function.
 //   Notes: postgres requires the object owner.
 //          mysql requires the "grant option" and the same privileges, and sometimes superuser.
 func (p *planner) Revoke(ctx context.Context, n *tree.Revoke) (planNode, error) {
 	if n.Targets.Databases != nil {
 		sqltelemetry.IncIAMRevokePrivilegesCounter(sqltelemetry.OnDatabase)
+	} else if n.Targets.Functions != nil {
+		sqltelemetry.IncIAMRevokePrivilegesCounter(sqltelemetry.OnFunction)
 	} else {
 		sqltelemetry.IncIAMRevokePrivilegesCounter(sqltelemetry.OnTable)
 	}
@@ -66,8 +72,10 @@ func (p *planner) Revoke(ctx context.Context, n *tree.Revoke) (planNode, error)
 		targets:      n.Targets,
 		grantees:     n.Grantees,
 		desiredprivs: n.Privileges,
-		changePrivilege: func(privDesc *sqlbase.PrivilegeDescriptor, grantee string) {
-			privDesc.Revoke(grantee, n.Privileges)
+		changePrivilege: func(
+			privDesc *sqlbase.PrivilegeDescriptor, grantee string, objectType privilege.ObjectType,
+		) {
+			privDesc.Revoke(grantee, n.Privileges, objectType)
 		},
 	}, nil
 }
@@ -76,7 +84,7 @@ type changePrivilegesNode struct {
 	targets         tree.TargetList
 	grantees        tree.NameList
 	desiredprivs    privilege.List
-	changePrivilege func(*sqlbase.PrivilegeDescriptor, string)
+	changePrivilege func(*sqlbase.PrivilegeDescriptor, string, privilege.ObjectType)
 }
 
 // ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
@@ -103,12 +111,29 @@ func (n *changePrivilegesNode) startExec(params runParams) error {
 		}
 	}
 
+	objectType := privilege.Table
+	if n.targets.Databases != nil {
+		objectType = privilege.Database
+	} else if n.targets.Functions != nil {
+		objectType = privilege.Function
+	}
+	if err := privilege.ValidatePrivileges(n.desiredprivs, objectType); err != nil {
+		return err
+	}
+
 	var descriptors []sqlbase.DescriptorProto
-	// DDL statements avoid the cache to avoid leases, and can view non-public descriptors.
-	// TODO(vivek): check if the cache can be used.
-	p.runWithOptions(resolveFlags{skipCache: true}, func() {
-		descriptors, err = getDescriptorsFromTargetList(ctx, p, n.targets)
-	})
+	// Functions are stored in the descriptor of their database, which is
+	// written instead of the descriptor of the function.
+	var fnDBDescs map[sqlbase.ID]*sqlbase.DatabaseDescriptor
+	if n.targets.Functions != nil {
+		descriptors, fnDBDescs, err = p.getFunctionDescriptorsFromTargetList(ctx, n.targets.Functions)
+	} else {
+		// DDL statements avoid the cache to avoid leases, and can view non-public descriptors.
+		// TODO(vivek): check if the cache can be used.
+		p.runWithOptions(resolveFlags{skipCache: true}, func() {
+			descriptors, err = getDescriptorsFromTargetList(ctx, p, n.targets)
+		})
+	}
 	if err != nil {
 		return err
 	}
@@ -131,7 +156,7 @@ func (n *changePrivilegesNode) startExec(params runParams) error {
 
 		privileges := descriptor.GetPrivileges()
 		for _, grantee := range n.grantees {
-			n.changePrivilege(privileges, string(grantee))
+			n.changePrivilege(privileges, string(grantee), objectType)
 		}
 
 		// Validate privilege descriptors directly as the db/table level Validate
@@ -166,6 +191,15 @@ func (n *changePrivilegesNode) startExec(params runParams) error {
 		}
 	}
 
+	for _, dbDesc := range fnDBDescs {
+		if err := dbDesc.Validate(); err != nil {
+			return err
+		}
+		if err := writeDescToBatch(ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(), p.execCfg.Settings, b, dbDesc.ID, dbDesc); err != nil {
+			return err
+		}
+	}
+
 	// Now update the descriptors transactionally.
 	return p.txn.Run(ctx, b)
 }
@@ -0,0 +1,124 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package idxusage
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// Enable determines whether the reads of the indexes are counted.
+var Enable = settings.RegisterPublicBoolSetting(
+	"sql.metrics.index_usage_stats.enabled",
+	"collect per-index usage statistics (see crdb_internal.index_usage_statistics)",
+	true,
+)
+
+// ReadKind describes how an index was read.
+type ReadKind int
+
+const (
+	// ScanRead is a scan of the index, by a table reader.
+	ScanRead ReadKind = iota
+	// LookupJoinRead is a lookup of rows in the index, by a lookup join or an
+	// index join.
+	LookupJoinRead
+	// ZigzagJoinRead is a read of the index by one side of a zigzag join.
+	ZigzagJoinRead
+)
+
+// LocalIndexUsageStats counts the reads of the indexes performed by the
+// execution engine on this node. The statistics are kept in memory and are
+// lost when the node restarts.
+type LocalIndexUsageStats struct {
+	st *cluster.Settings
+
+	mu struct {
+		syncutil.Mutex
+		stats map[roachpb.IndexUsageKey]*roachpb.IndexUsageStatistics
+	}
+}
+
+// NewLocalIndexUsageStats creates a new, empty, LocalIndexUsageStats.
+func NewLocalIndexUsageStats(st *cluster.Settings) *LocalIndexUsageStats {
+	s := &LocalIndexUsageStats{st: st}
+	s.mu.stats = make(map[roachpb.IndexUsageKey]*roachpb.IndexUsageStatistics)
+	return s
+}
+
+// RecordRead records a read of the given index. It is a no-op on a nil
+// LocalIndexUsageStats, which allows the processors to be set up without one
+// in tests.
+func (s *LocalIndexUsageStats) RecordRead(
+	tableID sqlbase.ID, indexID sqlbase.IndexID, kind ReadKind,
+) {
+	if s == nil || !Enable.Get(&s.st.SV) {
+		return
+	}
+	key := roachpb.IndexUsageKey{TableID: uint32(tableID), IndexID: uint32(indexID)}
+	now := timeutil.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats, ok := s.mu.stats[key]
+	if !ok {
+		stats = &roachpb.IndexUsageStatistics{}
+		s.mu.stats[key] = stats
+	}
+	stats.TotalReadCount++
+	switch kind {
+	case ScanRead:
+		stats.ScanCount++
+	case LookupJoinRead:
+		stats.LookupJoinCount++
+	case ZigzagJoinRead:
+		stats.ZigzagJoinCount++
+	}
+	stats.LastRead = now
+}
+
+// Get returns the statistics of the given index.
+func (s *LocalIndexUsageStats) Get(
+	tableID sqlbase.ID, indexID sqlbase.IndexID,
+) roachpb.IndexUsageStatistics {
+	key := roachpb.IndexUsageKey{TableID: uint32(tableID), IndexID: uint32(indexID)}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stats, ok := s.mu.stats[key]; ok {
+		return *stats
+	}
+	return roachpb.IndexUsageStatistics{}
+}
+
+// Serialize returns the statistics of all the indexes read on this node,
+// ordered by table and index ID.
+func (s *LocalIndexUsageStats) Serialize() []roachpb.CollectedIndexUsageStatistics {
+	s.mu.Lock()
+	res := make([]roachpb.CollectedIndexUsageStatistics, 0, len(s.mu.stats))
+	for key, stats := range s.mu.stats {
+		res = append(res, roachpb.CollectedIndexUsageStatistics{Key: key, Stats: *stats})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Key.TableID != res[j].Key.TableID {
+			return res[i].Key.TableID < res[j].Key.TableID
+		}
+		return res[i].Key.IndexID < res[j].Key.IndexID
+	})
+	return res
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package idxusage
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalIndexUsageStats(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	st := cluster.MakeTestingClusterSettings()
+	s := NewLocalIndexUsageStats(st)
+
+	s.RecordRead(53, 1, ScanRead)
+	s.RecordRead(53, 1, LookupJoinRead)
+	s.RecordRead(53, 2, ZigzagJoinRead)
+	s.RecordRead(52, 1, ScanRead)
+
+	stats := s.Get(53, 1)
+	require.Equal(t, int64(2), stats.TotalReadCount)
+	require.Equal(t, int64(1), stats.ScanCount)
+	require.Equal(t, int64(1), stats.LookupJoinCount)
+	require.Equal(t, int64(0), stats.ZigzagJoinCount)
+	require.False(t, stats.LastRead.IsZero())
+
+	require.Equal(t, roachpb.IndexUsageStatistics{}, s.Get(53, 3))
+
+	var keys []roachpb.IndexUsageKey
+	for _, stats := range s.Serialize() {
+		keys = append(keys, stats.Key)
+	}
+	require.Equal(t, []roachpb.IndexUsageKey{
+		{TableID: 52, IndexID: 1},
+		{TableID: 53, IndexID: 1},
+		{TableID: 53, IndexID: 2},
+	}, keys)
+
+	// Reads are not recorded once the collection is disabled.
+	Enable.Override(&st.SV, false)
+	s.RecordRead(53, 2, ScanRead)
+	require.Equal(t, int64(1), s.Get(53, 2).TotalReadCount)
+
+	// A nil LocalIndexUsageStats ignores the reads.
+	var nilStats *LocalIndexUsageStats
+	nilStats.RecordRead(53, 1, ScanRead)
+}
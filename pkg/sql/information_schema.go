@@ -1213,6 +1213,13 @@ CREATE TABLE information_schema.table_constraints (
 				tbNameStr := tree.NewDString(table.Name)
 
 				for conName, c := range conInfo {
+					var deferrable, initiallyDeferred bool
+					switch c.Kind {
+					case sqlbase.ConstraintTypeFK:
+						deferrable, initiallyDeferred = c.FK.Deferrable, c.FK.InitiallyDeferred
+					case sqlbase.ConstraintTypeUnique:
+						deferrable, initiallyDeferred = c.Index.UniqueDeferrable, c.Index.UniqueInitiallyDeferred
+					}
 					if err := addRow(
 						dbNameStr,                       // constraint_catalog
 						scNameStr,                       // constraint_schema
@@ -1221,8 +1228,8 @@ CREATE TABLE information_schema.table_constraints (
 						scNameStr,                       // table_schema
 						tbNameStr,                       // table_name
 						tree.NewDString(string(c.Kind)), // constraint_type
-						yesOrNoDatum(false),             // is_deferrable
-						yesOrNoDatum(false),             // initially_deferred
+						yesOrNoDatum(deferrable),        // is_deferrable
+						yesOrNoDatum(initiallyDeferred), // initially_deferred
 					); err != nil {
 						return err
 					}
@@ -1277,7 +1284,7 @@ CREATE TABLE information_schema.user_privileges (
 			dbNameStr := tree.NewDString(dbDesc.Name)
 			for _, u := range []string{security.RootUser, sqlbase.AdminRole} {
 				grantee := tree.NewDString(u)
-				for _, p := range privilege.List(privilege.ByValue[:]).SortedNames() {
+				for _, p := range privilege.GetValidPrivilegesForObject(privilege.Database).SortedNames() {
 					if err := addRow(
 						grantee,            // grantee
 						dbNameStr,          // table_catalog
@@ -0,0 +1,189 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+var mergeNodePool = sync.Pool{
+	New: func() interface{} {
+		return &mergeNode{}
+	},
+}
+
+// mergeNode implements the MERGE statement. Each source row carries the action
+// (insert, update or delete) that the optimizer selected for it, along with the
+// values needed by that action.
+type mergeNode struct {
+	source planNode
+
+	run mergeRun
+}
+
+// mergeRun contains the run-time state of mergeNode during local execution.
+type mergeRun struct {
+	tw        optTableMerger
+	checkOrds checkSet
+
+	// insertCols are the columns being inserted into.
+	insertCols []sqlbase.ColumnDescriptor
+
+	// done informs a new call to BatchedNext() that the previous call to
+	// BatchedNext() has completed the work already.
+	done bool
+
+	// traceKV caches the current KV tracing flag.
+	traceKV bool
+}
+
+func (n *mergeNode) startExec(params runParams) error {
+	// cache traceKV during execution, to avoid re-evaluating it for every row.
+	n.run.traceKV = params.p.ExtendedEvalContext().Tracing.KVTracingEnabled()
+
+	return n.run.tw.init(params.ctx, params.p.txn, params.EvalContext())
+}
+
+// Next is required because batchedPlanNode inherits from planNode, but
+// batchedPlanNode doesn't really provide it. See the explanatory comments
+// in plan_batch.go.
+func (n *mergeNode) Next(params runParams) (bool, error) { panic("not valid") }
+
+// Values is required because batchedPlanNode inherits from planNode, but
+// batchedPlanNode doesn't really provide it. See the explanatory comments
+// in plan_batch.go.
+func (n *mergeNode) Values() tree.Datums { panic("not valid") }
+
+// maxMergeBatchSize is the max number of entries in the KV batch for the
+// merge operation (including secondary index updates, FK cascading updates,
+// etc), before the current KV batch is executed and a new batch is started.
+const maxMergeBatchSize = 10000
+
+// BatchedNext implements the batchedPlanNode interface.
+func (n *mergeNode) BatchedNext(params runParams) (bool, error) {
+	if n.run.done {
+		return false, nil
+	}
+
+	tracing.AnnotateTrace()
+
+	// Advance one batch. First, clear the count of the current batch.
+	n.run.tw.resultCount = 0
+
+	// Now consume/accumulate the rows for this batch.
+	lastBatch := false
+	for {
+		if err := params.p.cancelChecker.Check(); err != nil {
+			return false, err
+		}
+
+		// Advance one individual row.
+		if next, err := n.source.Next(params); !next {
+			lastBatch = true
+			if err != nil {
+				return false, err
+			}
+			break
+		}
+
+		// Process the action for the current source row.
+		if err := n.processSourceRow(params, n.source.Values()); err != nil {
+			return false, err
+		}
+
+		// Are we done yet with the current batch?
+		if n.run.tw.curBatchSize() >= maxMergeBatchSize {
+			break
+		}
+	}
+
+	if n.run.tw.curBatchSize() > 0 {
+		if err := n.run.tw.atBatchEnd(params.ctx, n.run.traceKV); err != nil {
+			return false, err
+		}
+
+		if !lastBatch {
+			// We only run/commit the batch if there were some rows processed
+			// in this batch.
+			if err := n.run.tw.flushAndStartNewBatch(params.ctx); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if lastBatch {
+		if _, err := n.run.tw.finalize(params.ctx, n.run.traceKV); err != nil {
+			return false, err
+		}
+		// Remember we're done for the next call to BatchedNext().
+		n.run.done = true
+	}
+
+	// Possibly initiate a run of CREATE STATISTICS.
+	params.ExecCfg().StatsRefresher.NotifyMutation(
+		n.run.tw.tableDesc().ID,
+		n.run.tw.batchedCount(),
+	)
+
+	return n.run.tw.batchedCount() > 0, nil
+}
+
+// processSourceRow processes one row from the source. The insert values of
+// the row are only populated for insert actions, so the column constraints are
+// only enforced for those; the table writer enforces the constraints of the
+// update values.
+func (n *mergeNode) processSourceRow(params runParams, rowVals tree.Datums) error {
+	action := n.run.tw.action(rowVals)
+	if action == tree.MergeInsert {
+		if err := enforceLocalColumnConstraints(rowVals, n.run.insertCols); err != nil {
+			return err
+		}
+	}
+
+	// Verify the CHECK constraints by inspecting boolean columns from the input
+	// that contain the results of evaluation. The check columns are computed
+	// from the new values of the row, so there is nothing to verify for deleted
+	// rows.
+	if !n.run.checkOrds.Empty() {
+		ord := n.run.tw.actionOrdinal + 1
+		if action != tree.MergeDelete {
+			checkVals := rowVals[ord:]
+			if err := checkMutationInput(n.run.tw.tableDesc(), n.run.checkOrds, checkVals); err != nil {
+				return err
+			}
+		}
+		rowVals = rowVals[:ord]
+	}
+
+	return n.run.tw.row(params.ctx, rowVals, n.run.traceKV)
+}
+
+// BatchedCount implements the batchedPlanNode interface.
+func (n *mergeNode) BatchedCount() int { return n.run.tw.batchedCount() }
+
+// BatchedValues implements the batchedPlanNode interface.
+func (n *mergeNode) BatchedValues(rowIdx int) tree.Datums { panic("not valid") }
+
+func (n *mergeNode) Close(ctx context.Context) {
+	n.source.Close(ctx)
+	n.run.tw.close(ctx)
+	*n = mergeNode{}
+	mergeNodePool.Put(n)
+}
+
+func (n *mergeNode) enableAutoCommit() {
+	n.run.tw.enableAutoCommit()
+}
@@ -77,6 +77,10 @@ func buildOpaque(
 		plan, err = p.CreateSequence(ctx, n)
 	case *tree.CreateStats:
 		plan, err = p.CreateStatistics(ctx, n)
+	case *tree.CreateTrigger:
+		plan, err = p.CreateTrigger(ctx, n)
+	case *tree.CreateFunction:
+		plan, err = p.CreateFunction(ctx, n)
 	case *tree.Deallocate:
 		plan, err = p.Deallocate(ctx, n)
 	case *tree.Discard:
@@ -93,10 +97,16 @@ func buildOpaque(
 		plan, err = p.DropView(ctx, n)
 	case *tree.DropSequence:
 		plan, err = p.DropSequence(ctx, n)
+	case *tree.DropTrigger:
+		plan, err = p.DropTrigger(ctx, n)
+	case *tree.DropFunction:
+		plan, err = p.DropFunction(ctx, n)
 	case *tree.Grant:
 		plan, err = p.Grant(ctx, n)
 	case *tree.GrantRole:
 		plan, err = p.GrantRole(ctx, n)
+	case *tree.RefreshMaterializedView:
+		plan, err = p.RefreshMaterializedView(ctx, n)
 	case *tree.RenameColumn:
 		plan, err = p.RenameColumn(ctx, n)
 	case *tree.RenameDatabase:
@@ -105,6 +115,8 @@ func buildOpaque(
 		plan, err = p.RenameIndex(ctx, n)
 	case *tree.RenameTable:
 		plan, err = p.RenameTable(ctx, n)
+	case *tree.RevertTable:
+		plan, err = p.RevertTable(ctx, n)
 	case *tree.Revoke:
 		plan, err = p.Revoke(ctx, n)
 	case *tree.RevokeRole:
@@ -115,6 +127,8 @@ func buildOpaque(
 		plan, err = p.Scrub(ctx, n)
 	case *tree.SetClusterSetting:
 		plan, err = p.SetClusterSetting(ctx, n)
+	case *tree.SetConstraints:
+		plan, err = p.SetConstraints(ctx, n)
 	case *tree.SetZoneConfig:
 		plan, err = p.SetZoneConfig(ctx, n)
 	case *tree.SetVar:
@@ -173,6 +187,8 @@ func init() {
 		&tree.CreateSchema{},
 		&tree.CreateSequence{},
 		&tree.CreateStats{},
+		&tree.CreateTrigger{},
+		&tree.CreateFunction{},
 		&tree.CreateType{},
 		&tree.CreateRole{},
 		&tree.Deallocate{},
@@ -183,17 +199,22 @@ func init() {
 		&tree.DropView{},
 		&tree.DropRole{},
 		&tree.DropSequence{},
+		&tree.DropTrigger{},
+		&tree.DropFunction{},
 		&tree.Grant{},
 		&tree.GrantRole{},
+		&tree.RefreshMaterializedView{},
 		&tree.RenameColumn{},
 		&tree.RenameDatabase{},
 		&tree.RenameIndex{},
 		&tree.RenameTable{},
+		&tree.RevertTable{},
 		&tree.Revoke{},
 		&tree.RevokeRole{},
 		&tree.Scatter{},
 		&tree.Scrub{},
 		&tree.SetClusterSetting{},
+		&tree.SetConstraints{},
 		&tree.SetZoneConfig{},
 		&tree.SetVar{},
 		&tree.SetTransaction{},
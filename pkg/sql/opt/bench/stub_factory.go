@@ -280,11 +280,26 @@ func (f *stubFactory) ConstructUpsert(
 	return struct{}{}, nil
 }
 
+func (f *stubFactory) ConstructMerge(
+	input exec.Node,
+	table cat.Table,
+	actionCol exec.ColumnOrdinal,
+	insertCols exec.ColumnOrdinalSet,
+	fetchCols exec.ColumnOrdinalSet,
+	updateCols exec.ColumnOrdinalSet,
+	checks exec.CheckOrdinalSet,
+	allowAutoCommit bool,
+	skipFKChecks bool,
+) (exec.Node, error) {
+	return struct{}{}, nil
+}
+
 func (f *stubFactory) ConstructDelete(
 	input exec.Node,
 	table cat.Table,
 	fetchCols exec.ColumnOrdinalSet,
 	returnCols exec.ColumnOrdinalSet,
+	passthrough sqlbase.ResultColumns,
 	allowAutoCommit bool,
 	skipFKChecks bool,
 ) (exec.Node, error) {
@@ -323,6 +338,27 @@ func (f *stubFactory) ConstructErrorIfRows(
 	return struct{}{}, nil
 }
 
+func (f *stubFactory) ConstructDeferrableFKCheck(
+	input exec.Node,
+	fk cat.ForeignKeyConstraint,
+	origin cat.Table,
+	referenced cat.Table,
+	keyCols []exec.ColumnOrdinal,
+	mkErr func(keyVals tree.Datums) error,
+) (exec.Node, error) {
+	return struct{}{}, nil
+}
+
+func (f *stubFactory) ConstructDeferrableUniqueCheck(
+	input exec.Node,
+	table cat.Table,
+	uniq cat.DeferrableUniqueConstraint,
+	keyCols []exec.ColumnOrdinal,
+	mkErr func(keyVals tree.Datums) error,
+) (exec.Node, error) {
+	return struct{}{}, nil
+}
+
 func (f *stubFactory) ConstructOpaque(metadata opt.OpaqueMetadata) (exec.Node, error) {
 	return struct{}{}, nil
 }
@@ -383,6 +419,7 @@ func (f *stubFactory) ConstructCreateView(
 	ifNotExists bool,
 	replace bool,
 	temporary bool,
+	materialized bool,
 	viewQuery string,
 	columns sqlbase.ResultColumns,
 	deps opt.ViewDeps,
@@ -80,6 +80,12 @@ type Column interface {
 	// computed columns, but they can depend on all other columns, including
 	// columns with default values.
 	ComputedExprStr() string
+
+	// IsVirtual returns true if the column is a virtual computed column. The
+	// values of virtual columns are not stored in the primary index; they are
+	// recomputed from ComputedExprStr when the table is read. A virtual column
+	// can be a key column of a secondary index.
+	IsVirtual() bool
 }
 
 // IsMutationColumn is a convenience function that returns true if the column at
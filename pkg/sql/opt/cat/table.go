@@ -41,10 +41,20 @@ type Table interface {
 	// information_schema tables.
 	IsVirtualTable() bool
 
+	// IsMaterializedView returns true if this table is actually a materialized
+	// view. Materialized views are the same as tables in all aspects, other
+	// than that they cannot be mutated.
+	IsMaterializedView() bool
+
 	// IsInterleaved returns true if any of this table's indexes are interleaved
 	// with index(es) from other table(s).
 	IsInterleaved() bool
 
+	// HasRowTriggers returns true if the table has row-level triggers. The
+	// triggers are run during execution and can read any column of the rows
+	// being mutated.
+	HasRowTriggers() bool
+
 	// ColumnCount returns the number of public columns in the table. Public
 	// columns are not currently being added or dropped from the table. This
 	// method should be used when mutation columns can be ignored (the common
@@ -112,6 +122,21 @@ type Table interface {
 	// Check returns the ith check constraint, where i < CheckCount.
 	Check(i int) CheckConstraint
 
+	// ExclusionCount returns the number of exclusion constraints present on the
+	// table.
+	ExclusionCount() int
+
+	// Exclusion returns the ith exclusion constraint, where i < ExclusionCount.
+	Exclusion(i int) ExclusionConstraint
+
+	// DeferrableUniqueCount returns the number of DEFERRABLE UNIQUE constraints
+	// present on the table.
+	DeferrableUniqueCount() int
+
+	// DeferrableUnique returns the ith DEFERRABLE UNIQUE constraint, where
+	// i < DeferrableUniqueCount.
+	DeferrableUnique(i int) DeferrableUniqueConstraint
+
 	// FamilyCount returns the number of column families present on the table.
 	// There is always at least one primary family (always family 0) where columns
 	// go if they are not explicitly assigned to another family. The primary
@@ -150,6 +175,47 @@ type CheckConstraint struct {
 	Validated  bool
 }
 
+// ExclusionConstraint contains the SQL text of the elements and predicate of
+// an exclusion constraint on a table. An exclusion constraint guarantees that
+// no two rows compare true on all of the elements. For example, this
+// constraint ensures that no two reservations for the same room overlap:
+//
+//   CREATE TABLE r (
+//     room INT, s TIMESTAMP, e TIMESTAMP,
+//     EXCLUDE USING gist (room WITH =, tsrange(s, e) WITH &&)
+//   )
+//
+type ExclusionConstraint struct {
+	Name     string
+	Elements []ExclusionElement
+	// Predicate is the SQL text of the WHERE clause of the constraint, or the
+	// empty string if there is none.
+	Predicate string
+	Validated bool
+}
+
+// ExclusionElement is a single element of an exclusion constraint.
+type ExclusionElement struct {
+	Expr string
+	// Operator is either tree.EQ or tree.Overlaps.
+	Operator tree.ComparisonOperator
+}
+
+// DeferrableUniqueConstraint is a UNIQUE constraint whose checks can be
+// deferred until the end of the transaction. Unlike the other UNIQUE
+// constraints, it is not enforced by a unique index: the index that backs it
+// is not unique, and the constraint is checked by queries that run after the
+// mutations (like the exclusion constraints). For example:
+//
+//   CREATE TABLE t (k INT PRIMARY KEY, v INT UNIQUE DEFERRABLE)
+//
+type DeferrableUniqueConstraint struct {
+	Name string
+	// ColumnOrdinals are the ordinals of the columns of the constraint.
+	ColumnOrdinals []int
+	Deferrability  tree.ConstraintDeferrability
+}
+
 // TableStatistic is an interface to a table statistic. Each statistic is
 // associated with a set of columns.
 type TableStatistic interface {
@@ -249,4 +315,9 @@ type ForeignKeyConstraint interface {
 	// UpdateReferenceAction returns the action to be performed if the foreign key
 	// constraint would be violated by an update.
 	UpdateReferenceAction() tree.ReferenceAction
+
+	// Deferrability returns whether the checks of the constraint can be deferred
+	// until the end of the transaction (see SET CONSTRAINTS), and whether they
+	// are by default.
+	Deferrability() tree.ConstraintDeferrability
 }
@@ -182,6 +182,34 @@ func FormatTable(cat Catalog, tab Table, tp treeprinter.Node) {
 		child.Childf("CHECK (%s)", tab.Check(i).Constraint)
 	}
 
+	for i := 0; i < tab.ExclusionCount(); i++ {
+		c := tab.Exclusion(i)
+		var buf bytes.Buffer
+		for j := range c.Elements {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "%s WITH %s", c.Elements[j].Expr, c.Elements[j].Operator)
+		}
+		if c.Predicate != "" {
+			child.Childf("EXCLUDE (%s) WHERE (%s)", buf.String(), c.Predicate)
+		} else {
+			child.Childf("EXCLUDE (%s)", buf.String())
+		}
+	}
+
+	for i := 0; i < tab.DeferrableUniqueCount(); i++ {
+		c := tab.DeferrableUnique(i)
+		var buf bytes.Buffer
+		for j, ord := range c.ColumnOrdinals {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(string(tab.Column(ord).ColName()))
+		}
+		child.Childf("CONSTRAINT %s UNIQUE (%s) %s", c.Name, buf.String(), c.Deferrability)
+	}
+
 	for i := 0; i < tab.DeletableIndexCount(); i++ {
 		formatCatalogIndex(tab, i, child)
 	}
@@ -288,6 +316,10 @@ func formatCatalogFKRef(
 		fmt.Fprintf(&extra, " ON DELETE %s", action.String())
 	}
 
+	if d := fkRef.Deferrability(); d != tree.NotDeferrable {
+		fmt.Fprintf(&extra, " %s", d)
+	}
+
 	tp.Childf(
 		"%s %s FOREIGN KEY %v %s REFERENCES %v %s%s",
 		title,
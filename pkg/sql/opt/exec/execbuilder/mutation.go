@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -144,15 +145,26 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 	tab := md.Table(ins.Table)
 
 	//  - there are no self-referencing foreign keys;
+	//  - there are no deferrable foreign keys;
+	//  - there are no exclusion constraints or deferrable unique constraints;
 	//  - all FK checks can be performed using direct lookups into unique indexes.
 	fkChecks := make([]exec.InsertFastPathFKCheck, len(ins.Checks))
 	for i := range ins.Checks {
 		c := &ins.Checks[i]
+		if c.Exclusion || c.Unique {
+			// Exclusion and unique constraint checks are not lookups into unique
+			// indexes.
+			return execPlan{}, false, nil
+		}
 		if md.Table(c.ReferencedTable).ID() == md.Table(ins.Table).ID() {
 			// Self-referencing FK.
 			return execPlan{}, false, nil
 		}
 		fk := tab.OutboundForeignKey(c.FKOrdinal)
+		if fk.Deferrability() != tree.NotDeferrable {
+			// The check might have to be deferred until the end of the transaction.
+			return execPlan{}, false, nil
+		}
 		lookupJoin, isLookupJoin := c.Check.(*memo.LookupJoinExpr)
 		if !isLookupJoin || lookupJoin.JoinType != opt.AntiJoinOp {
 			// Not a lookup anti-join.
@@ -415,6 +427,54 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 	return ep, nil
 }
 
+func (b *Builder) buildMerge(mrg *memo.MergeExpr) (execPlan, error) {
+	// As with Upsert, the execution engine requires one input column for each
+	// insert, fetch, and update expression. The action column selects which of
+	// them are used for each input row.
+	cnt := len(mrg.InsertCols) + len(mrg.FetchCols) + len(mrg.UpdateCols) + len(mrg.CheckCols) + 1
+	colList := make(opt.ColList, 0, cnt)
+	colList = appendColsWhenPresent(colList, mrg.InsertCols)
+	colList = appendColsWhenPresent(colList, mrg.FetchCols)
+	colList = appendColsWhenPresent(colList, mrg.UpdateCols)
+	colList = append(colList, mrg.ActionCol)
+	colList = appendColsWhenPresent(colList, mrg.CheckCols)
+
+	input, err := b.buildMutationInput(mrg, mrg.Input, colList, &mrg.MutationPrivate)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	// Construct the Merge node.
+	md := b.mem.Metadata()
+	tab := md.Table(mrg.Table)
+	actionCol := input.getColumnOrdinal(mrg.ActionCol)
+	insertColOrds := ordinalSetFromColList(mrg.InsertCols)
+	fetchColOrds := ordinalSetFromColList(mrg.FetchCols)
+	updateColOrds := ordinalSetFromColList(mrg.UpdateCols)
+	checkOrds := ordinalSetFromColList(mrg.CheckCols)
+	disableExecFKs := !mrg.FKFallback
+	node, err := b.factory.ConstructMerge(
+		input.root,
+		tab,
+		actionCol,
+		insertColOrds,
+		fetchColOrds,
+		updateColOrds,
+		checkOrds,
+		b.allowAutoCommit && len(mrg.Checks) == 0,
+		disableExecFKs,
+	)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	if err := b.buildFKChecks(mrg.Checks); err != nil {
+		return execPlan{}, err
+	}
+
+	return execPlan{root: node}, nil
+}
+
 func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	// Check for the fast-path delete case that can use a range delete.
 	if b.canUseDeleteRange(del) {
@@ -425,8 +485,14 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	//
 	// TODO(andyk): Using ensureColumns here can result in an extra Render.
 	// Upgrade execution engine to not require this.
-	colList := make(opt.ColList, 0, len(del.FetchCols))
+	colList := make(opt.ColList, 0, len(del.FetchCols)+len(del.PassthroughCols))
 	colList = appendColsWhenPresent(colList, del.FetchCols)
+	// The RETURNING clause of the Delete can refer to the columns in any of the
+	// USING tables. As a result, the Delete may need to passthrough those
+	// columns so the projection above can use them.
+	if del.NeedResults() {
+		colList = appendColsWhenPresent(colList, del.PassthroughCols)
+	}
 
 	input, err := b.buildMutationInput(del, del.Input, colList, &del.MutationPrivate)
 	if err != nil {
@@ -438,12 +504,23 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	tab := md.Table(del.Table)
 	fetchColOrds := ordinalSetFromColList(del.FetchCols)
 	returnColOrds := ordinalSetFromColList(del.ReturnCols)
+
+	// Construct the result columns for the passthrough set.
+	var passthroughCols sqlbase.ResultColumns
+	if del.NeedResults() {
+		for _, passthroughCol := range del.PassthroughCols {
+			colMeta := b.mem.Metadata().ColumnMeta(passthroughCol)
+			passthroughCols = append(passthroughCols, sqlbase.ResultColumn{Name: colMeta.Alias, Typ: colMeta.Type})
+		}
+	}
+
 	disableExecFKs := !del.FKFallback
 	node, err := b.factory.ConstructDelete(
 		input.root,
 		tab,
 		fetchColOrds,
 		returnColOrds,
+		passthroughCols,
 		b.allowAutoCommit && len(del.Checks) == 0,
 		disableExecFKs,
 	)
@@ -489,6 +566,10 @@ func (b *Builder) canUseDeleteRange(del *memo.DeleteExpr) bool {
 		// is possible, because the integrity of those references must be checked.
 		return false
 	}
+	if tab.HasRowTriggers() {
+		// Triggers must be fired for every deleted row.
+		return false
+	}
 
 	// Check for simple Scan input operator without a limit; anything else is not
 	// supported by a range delete.
@@ -591,15 +672,56 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 		if err != nil {
 			return err
 		}
-		// Wrap the query in an error node.
-		mkErr := func(row tree.Datums) error {
-			keyVals := make(tree.Datums, len(c.KeyCols))
+		var node exec.Node
+		if c.Exclusion {
+			// Wrap the query in an error node.
+			mkErr := func(row tree.Datums) error {
+				keyVals := make(tree.Datums, len(c.KeyCols))
+				for i, col := range c.KeyCols {
+					keyVals[i] = row[query.getColumnOrdinal(col)]
+				}
+				return mkExclusionCheckErr(md, c, keyVals)
+			}
+			node, err = b.factory.ConstructErrorIfRows(query.root, mkErr)
+		} else if c.Unique {
+			// Wrap the query in a node that either errors out or saves the keys
+			// for a check at the end of the transaction. The constraint is
+			// always deferrable.
+			keyCols := make([]exec.ColumnOrdinal, len(c.KeyCols))
 			for i, col := range c.KeyCols {
-				keyVals[i] = row[query.getColumnOrdinal(col)]
+				keyCols[i] = query.getColumnOrdinal(col)
+			}
+			mkErr := func(keyVals tree.Datums) error {
+				return mkUniqueCheckErr(md, c, keyVals)
 			}
-			return mkFKCheckErr(md, c, keyVals)
+			origin := md.Table(c.OriginTable)
+			node, err = b.factory.ConstructDeferrableUniqueCheck(
+				query.root, origin, origin.DeferrableUnique(c.UniqueOrdinal), keyCols, mkErr,
+			)
+		} else if fk, ok := deferrableFKCheck(md, c); ok {
+			// Wrap the query in a node that either errors out or saves the keys
+			// for a check at the end of the transaction.
+			keyCols := make([]exec.ColumnOrdinal, len(c.KeyCols))
+			for i, col := range c.KeyCols {
+				keyCols[i] = query.getColumnOrdinal(col)
+			}
+			mkErr := func(keyVals tree.Datums) error {
+				return mkFKCheckErr(md, c, keyVals)
+			}
+			node, err = b.factory.ConstructDeferrableFKCheck(
+				query.root, fk, md.Table(c.OriginTable), md.Table(c.ReferencedTable), keyCols, mkErr,
+			)
+		} else {
+			// Wrap the query in an error node.
+			mkErr := func(row tree.Datums) error {
+				keyVals := make(tree.Datums, len(c.KeyCols))
+				for i, col := range c.KeyCols {
+					keyVals[i] = row[query.getColumnOrdinal(col)]
+				}
+				return mkFKCheckErr(md, c, keyVals)
+			}
+			node, err = b.factory.ConstructErrorIfRows(query.root, mkErr)
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
 		if err != nil {
 			return err
 		}
@@ -608,6 +730,34 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 	return nil
 }
 
+// deferrableFKCheck returns the constraint of the given FK check if the check
+// can be deferred until the end of the transaction. This is the case if the
+// constraint is DEFERRABLE, except that (as in Postgres) the checks of ON
+// DELETE/UPDATE RESTRICT actions are never deferred.
+func deferrableFKCheck(
+	md *opt.Metadata, c *memo.FKChecksItem,
+) (_ cat.ForeignKeyConstraint, ok bool) {
+	var fk cat.ForeignKeyConstraint
+	if c.FKOutbound {
+		fk = md.Table(c.OriginTable).OutboundForeignKey(c.FKOrdinal)
+	} else {
+		fk = md.Table(c.ReferencedTable).InboundForeignKey(c.FKOrdinal)
+	}
+	if fk.Deferrability() == tree.NotDeferrable {
+		return nil, false
+	}
+	if !c.FKOutbound {
+		action := fk.UpdateReferenceAction()
+		if c.OpName == "delete" {
+			action = fk.DeleteReferenceAction()
+		}
+		if action == tree.Restrict {
+			return nil, false
+		}
+	}
+	return fk, true
+}
+
 // mkFKCheckErr generates a user-friendly error describing a foreign key
 // violation. The keyVals are the values that correspond to the
 // cat.ForeignKeyConstraint columns.
@@ -695,6 +845,81 @@ func mkFKCheckErr(md *opt.Metadata, c *memo.FKChecksItem, keyVals tree.Datums) e
 	)
 }
 
+// mkExclusionCheckErr generates a user-friendly error describing an exclusion
+// constraint violation. The keyVals are the values of the primary key columns
+// of the new row.
+func mkExclusionCheckErr(md *opt.Metadata, c *memo.FKChecksItem, keyVals tree.Datums) error {
+	origin := md.TableMeta(c.OriginTable)
+	excl := origin.Table.Exclusion(c.ExclusionOrdinal)
+
+	// Generate an error of the form:
+	//   ERROR:  conflicting key value violates exclusion constraint "foo"
+	//   DETAIL: Key (id)=(2) conflicts with an existing row in table "r".
+	var msg, details bytes.Buffer
+	msg.WriteString("conflicting key value violates exclusion constraint ")
+	lex.EncodeEscapedSQLIdent(&msg, excl.Name)
+
+	primary := origin.Table.Index(cat.PrimaryIndex)
+	details.WriteString("Key (")
+	for i, n := 0, primary.KeyColumnCount(); i < n; i++ {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(string(primary.Column(i).ColName()))
+	}
+	details.WriteString(")=(")
+	for i, d := range keyVals {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(d.String())
+	}
+	details.WriteString(") conflicts with an existing row in table ")
+	lex.EncodeEscapedSQLIdent(&details, string(origin.Alias.TableName))
+	details.WriteByte('.')
+
+	return errors.WithDetail(
+		pgerror.New(pgcode.ExclusionViolation, msg.String()),
+		details.String(),
+	)
+}
+
+// mkUniqueCheckErr generates a user-friendly error describing a unique
+// constraint violation. The keyVals are the values of the columns of the
+// constraint.
+func mkUniqueCheckErr(md *opt.Metadata, c *memo.FKChecksItem, keyVals tree.Datums) error {
+	origin := md.TableMeta(c.OriginTable)
+	uniq := origin.Table.DeferrableUnique(c.UniqueOrdinal)
+
+	// Generate an error of the form:
+	//   ERROR:  duplicate key value violates unique constraint "foo"
+	//   DETAIL: Key (v)=(2) already exists.
+	var msg, details bytes.Buffer
+	msg.WriteString("duplicate key value violates unique constraint ")
+	lex.EncodeEscapedSQLIdent(&msg, uniq.Name)
+
+	details.WriteString("Key (")
+	for i, ord := range uniq.ColumnOrdinals {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(string(origin.Table.Column(ord).ColName()))
+	}
+	details.WriteString(")=(")
+	for i, d := range keyVals {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(d.String())
+	}
+	details.WriteString(") already exists.")
+
+	return errors.WithDetail(
+		pgerror.New(pgcode.UniqueViolation, msg.String()),
+		details.String(),
+	)
+}
+
 // canAutoCommit determines if it is safe to auto commit the mutation contained
 // in the expression.
 //
@@ -722,7 +947,7 @@ func (b *Builder) canAutoCommit(rel memo.RelExpr) bool {
 	}
 
 	switch rel.Op() {
-	case opt.InsertOp, opt.UpsertOp, opt.UpdateOp, opt.DeleteOp:
+	case opt.InsertOp, opt.UpsertOp, opt.UpdateOp, opt.DeleteOp, opt.MergeOp:
 		// Check that there aren't any more mutations in the input.
 		// TODO(radu): this can go away when all mutations are under top-level
 		// With ops.
@@ -770,6 +995,11 @@ func (b *Builder) shouldApplyImplicitLockingToMutationInput(mutExpr memo.RelExpr
 	case *memo.DeleteExpr:
 		return b.shouldApplyImplicitLockingToDeleteInput(t)
 
+	case *memo.MergeExpr:
+		// The input of a MERGE is an outer join of the source with the target
+		// table, which is not locked for now.
+		return false
+
 	default:
 		panic(errors.AssertionFailedf("unexpected mutation expression %T", t))
 	}
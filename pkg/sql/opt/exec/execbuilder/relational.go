@@ -246,6 +246,9 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.DeleteExpr:
 		ep, err = b.buildDelete(t)
 
+	case *memo.MergeExpr:
+		ep, err = b.buildMerge(t)
+
 	case *memo.CreateTableExpr:
 		ep, err = b.buildCreateTable(t)
 
@@ -1004,16 +1007,11 @@ func (b *Builder) buildDistinct(distinct memo.RelExpr) (execPlan, error) {
 		nullsAreDistinct = true
 	}
 
-	// If duplicate input rows are not allowed, raise an error at runtime if
-	// duplicates are detected.
-	var errorOnDup string
-	if private.ErrorOnDup {
-		errorOnDup = sqlbase.DuplicateUpsertErrText
-	}
-
+	// If duplicate input rows are not allowed, ErrorOnDup holds the text of the
+	// error raised at runtime if duplicates are detected.
 	reqOrdering := ep.reqOrdering(distinct)
 	ep.root, err = b.factory.ConstructDistinct(
-		input.root, distinctCols, orderedCols, reqOrdering, nullsAreDistinct, errorOnDup)
+		input.root, distinctCols, orderedCols, reqOrdering, nullsAreDistinct, private.ErrorOnDup)
 	if err != nil {
 		return execPlan{}, err
 	}
@@ -262,7 +262,7 @@ func (b *Builder) buildFunction(
 			return nil, err
 		}
 	}
-	funcRef := tree.WrapFunction(fn.Name)
+	funcRef := tree.WrapResolvedFunction(fn.Name, fn.Properties, fn.Overload)
 	return tree.NewTypedFuncExpr(
 		funcRef,
 		0, /* aggQualifier */
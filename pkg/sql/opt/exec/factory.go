@@ -339,6 +339,7 @@ type Factory interface {
 	//    insert is not processed through side-effecting expressions (see
 	//    allowAutoCommit flag for ConstructInsert);
 	//  - there are no self-referencing foreign keys;
+	//  - there are no deferrable foreign keys;
 	//  - all FK checks can be performed using direct lookups into unique indexes.
 	//
 	// In this case, the foreign-key checks can run before (or even concurrently
@@ -436,6 +437,32 @@ type Factory interface {
 		skipFKChecks bool,
 	) (Node, error)
 
+	// ConstructMerge creates a node that implements a MERGE statement. For each
+	// input row, Merge reads the actionCol, which contains a
+	// tree.MergeActionType value, and then inserts a new row, updates an
+	// existing row, or deletes an existing row. The input columns are laid out
+	// as for Upsert: the columns to be inserted, followed by the columns
+	// containing existing values, and finally the columns containing new values.
+	//
+	// If allowAutoCommit is set, the operator is allowed to commit the
+	// transaction (if appropriate, i.e. if it is in an implicit transaction).
+	// This is false if there are multiple mutations in a statement.
+	//
+	// If skipFKChecks is set, foreign keys are not checked as part of the
+	// execution of the merge. This is used when the FK checks are planned by the
+	// optimizer and are run separately as plan postqueries.
+	ConstructMerge(
+		input Node,
+		table cat.Table,
+		actionCol ColumnOrdinal,
+		insertCols ColumnOrdinalSet,
+		fetchCols ColumnOrdinalSet,
+		updateCols ColumnOrdinalSet,
+		checks CheckOrdinalSet,
+		allowAutoCommit bool,
+		skipFKChecks bool,
+	) (Node, error)
+
 	// ConstructDelete creates a node that implements a DELETE statement. The
 	// input contains columns that were fetched from the target table, and that
 	// will be deleted.
@@ -444,6 +471,11 @@ type Factory interface {
 	// the target table. The input must contain those columns in the same order
 	// as they appear in the table schema.
 	//
+	// The passthrough parameter contains all the result columns that are part of
+	// the input node that the delete node needs to return (passing through from
+	// the input). The pass through columns are used to return any column from the
+	// USING tables that are referenced in the RETURNING clause.
+	//
 	// If allowAutoCommit is set, the operator is allowed to commit the
 	// transaction (if appropriate, i.e. if it is in an implicit transaction).
 	// This is false if there are multiple mutations in a statement, or the output
@@ -457,6 +489,7 @@ type Factory interface {
 		table cat.Table,
 		fetchCols ColumnOrdinalSet,
 		returnCols ColumnOrdinalSet,
+		passthrough sqlbase.ResultColumns,
 		allowAutoCommit bool,
 		skipFKChecks bool,
 	) (Node, error)
@@ -481,6 +514,7 @@ type Factory interface {
 		ifNotExists bool,
 		replace bool,
 		temporary bool,
+		materialized bool,
 		viewQuery string,
 		columns sqlbase.ResultColumns,
 		deps opt.ViewDeps,
@@ -499,6 +533,35 @@ type Factory interface {
 	// is used to create the error.
 	ConstructErrorIfRows(input Node, mkErr func(tree.Datums) error) (Node, error)
 
+	// ConstructDeferrableFKCheck is like ConstructErrorIfRows, but is used for
+	// the check of a deferrable foreign key constraint; the input returns the
+	// rows violating the constraint. If the constraint is deferred in the
+	// current transaction, the node does not error out: instead, it saves the
+	// values of the key columns of the rows (at the given ordinals, in the
+	// order of the constraint columns), and the constraint is checked again for
+	// these keys before the transaction commits. The mkErr function creates the
+	// error from the key values.
+	ConstructDeferrableFKCheck(
+		input Node,
+		fk cat.ForeignKeyConstraint,
+		origin cat.Table,
+		referenced cat.Table,
+		keyCols []ColumnOrdinal,
+		mkErr func(keyVals tree.Datums) error,
+	) (Node, error)
+
+	// ConstructDeferrableUniqueCheck is like ConstructDeferrableFKCheck, but is
+	// used for the check of a DEFERRABLE UNIQUE constraint of the table; the
+	// input returns the rows violating the constraint, and keyCols are the
+	// ordinals of the columns of the constraint in these rows.
+	ConstructDeferrableUniqueCheck(
+		input Node,
+		table cat.Table,
+		uniq cat.DeferrableUniqueConstraint,
+		keyCols []ColumnOrdinal,
+		mkErr func(keyVals tree.Datums) error,
+	) (Node, error)
+
 	// ConstructOpaque creates a node for an opaque operator.
 	ConstructOpaque(metadata opt.OpaqueMetadata) (Node, error)
 
@@ -1160,11 +1160,15 @@ type Instance struct {
 // they need not generate remaining filters. This is e.g. used for check
 // constraints that can help generate better spans but don't actually need to be
 // enforced.
+//
+// computedCols optionally maps computed columns to their expressions; see
+// indexConstraintCtx.computedCols.
 func (ic *Instance) Init(
 	requiredFilters memo.FiltersExpr,
 	optionalFilters memo.FiltersExpr,
 	columns []opt.OrderingColumn,
 	notNullCols opt.ColSet,
+	computedCols map[opt.ColumnID]opt.ScalarExpr,
 	isInverted bool,
 	evalCtx *tree.EvalContext,
 	factory *norm.Factory,
@@ -1179,7 +1183,7 @@ func (ic *Instance) Init(
 		ic.allFilters = requiredFilters[:len(requiredFilters):len(requiredFilters)]
 		ic.allFilters = append(ic.allFilters, optionalFilters...)
 	}
-	ic.indexConstraintCtx.init(columns, notNullCols, isInverted, evalCtx, factory)
+	ic.indexConstraintCtx.init(columns, notNullCols, computedCols, isInverted, evalCtx, factory)
 	if isInverted {
 		tight, constraints := ic.makeInvertedIndexSpansForExpr(
 			&ic.allFilters, nil /* constraints */, false,
@@ -1261,6 +1265,12 @@ type indexConstraintCtx struct {
 
 	notNullCols opt.ColSet
 
+	// computedCols maps computed index columns to their expressions. A filter
+	// expression that is identical to the expression of a computed index column
+	// is treated as a reference to that column. This allows constraining indexes
+	// on virtual columns, which are not produced by the scan.
+	computedCols map[opt.ColumnID]opt.ScalarExpr
+
 	// isInverted indicates if the index is an inverted index (e.g. JSONB).
 	// An inverted index behaves differently than a normal index because a PK
 	// can appear in multiple index entries. For example, `a @> x AND a @> y` is
@@ -1279,6 +1289,7 @@ type indexConstraintCtx struct {
 func (c *indexConstraintCtx) init(
 	columns []opt.OrderingColumn,
 	notNullCols opt.ColSet,
+	computedCols map[opt.ColumnID]opt.ScalarExpr,
 	isInverted bool,
 	evalCtx *tree.EvalContext,
 	factory *norm.Factory,
@@ -1286,6 +1297,7 @@ func (c *indexConstraintCtx) init(
 	c.md = factory.Metadata()
 	c.columns = columns
 	c.notNullCols = notNullCols
+	c.computedCols = computedCols
 	c.isInverted = isInverted
 	c.evalCtx = evalCtx
 	c.factory = factory
@@ -1298,11 +1310,16 @@ func (c *indexConstraintCtx) init(
 }
 
 // isIndexColumn returns true if ev is a variable on the n indexed var that
-// corresponds to index column <offset>.
+// corresponds to index column <offset>, or if it is the expression of that
+// column when the column is computed. Scalar expressions are interned, so
+// identical expressions are the same object.
 func (c *indexConstraintCtx) isIndexColumn(nd opt.Expr, offset int) bool {
 	if v, ok := nd.(*memo.VariableExpr); ok && v.Col == c.columns[offset].ID() {
 		return true
 	}
+	if expr, ok := c.computedCols[c.columns[offset].ID()]; ok && nd == opt.Expr(expr) {
+		return true
+	}
 	return false
 }
 
@@ -138,7 +138,10 @@ func TestIndexConstraints(t *testing.T) {
 					varNames[i] = fmt.Sprintf("@%d", i+1)
 				}
 				var ic idxconstraint.Instance
-				ic.Init(filters, optionalFilters, indexCols, notNullCols, invertedIndex, &evalCtx, &f)
+				ic.Init(
+					filters, optionalFilters, indexCols, notNullCols, nil /* computedCols */, invertedIndex,
+					&evalCtx, &f,
+				)
 				result := ic.Constraint()
 				var buf bytes.Buffer
 				for i := 0; i < result.Spans.Count(); i++ {
@@ -242,7 +245,10 @@ func BenchmarkIndexConstraints(b *testing.B) {
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
 				var ic idxconstraint.Instance
-				ic.Init(filters, nil /* optionalFilters */, indexCols, notNullCols, false /*isInverted */, &evalCtx, &f)
+				ic.Init(
+					filters, nil /* optionalFilters */, indexCols, notNullCols,
+					nil /* computedCols */, false /*isInverted */, &evalCtx, &f,
+				)
 				_ = ic.Constraint()
 				_ = ic.RemainingFilters()
 			}
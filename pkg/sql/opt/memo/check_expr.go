@@ -294,9 +294,9 @@ func checkFilters(filters FiltersExpr) {
 }
 
 func checkErrorOnDup(e RelExpr) {
-	// Only UpsertDistinctOn should set the ErrorOnDup field to true.
-	if e.Op() != opt.UpsertDistinctOnOp && e.Private().(*GroupingPrivate).ErrorOnDup {
-		panic(errors.AssertionFailedf("%s should never set ErrorOnDup to true", log.Safe(e.Op())))
+	// Only UpsertDistinctOn should set the ErrorOnDup field.
+	if e.Op() != opt.UpsertDistinctOnOp && e.Private().(*GroupingPrivate).ErrorOnDup != "" {
+		panic(errors.AssertionFailedf("%s should never set ErrorOnDup", log.Safe(e.Op())))
 	}
 }
 
@@ -198,7 +198,7 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		f.Buffer.WriteByte(')')
 
 	case *ScanExpr, *IndexJoinExpr, *ShowTraceForSessionExpr,
-		*InsertExpr, *UpdateExpr, *UpsertExpr, *DeleteExpr, *SequenceSelectExpr,
+		*InsertExpr, *UpdateExpr, *UpsertExpr, *DeleteExpr, *MergeExpr, *SequenceSelectExpr,
 		*WindowExpr, *OpaqueRelExpr, *OpaqueMutationExpr, *OpaqueDDLExpr,
 		*AlterTableSplitExpr, *AlterTableUnsplitExpr, *AlterTableUnsplitAllExpr,
 		*AlterTableRelocateExpr, *ControlJobsExpr, *CancelQueriesExpr,
@@ -293,7 +293,7 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		if !f.HasFlags(ExprFmtHidePhysProps) && !private.Ordering.Any() {
 			tp.Childf("internal-ordering: %s", private.Ordering)
 		}
-		if !f.HasFlags(ExprFmtHideMiscProps) && private.ErrorOnDup {
+		if !f.HasFlags(ExprFmtHideMiscProps) && private.ErrorOnDup != "" {
 			tp.Childf("error-on-dup")
 		}
 
@@ -496,6 +496,19 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			f.formatMutationCommon(tp, &t.MutationPrivate)
 		}
 
+	case *MergeExpr:
+		if !f.HasFlags(ExprFmtHideColumns) {
+			if len(colList) == 0 {
+				tp.Child("columns: <none>")
+			}
+			tp.Childf("action column: %d", t.ActionCol)
+			f.formatColList(e, tp, "fetch columns:", t.FetchCols)
+			f.formatMutationCols(e, tp, "insert-mapping:", t.InsertCols, t.Table)
+			f.formatMutationCols(e, tp, "update-mapping:", t.UpdateCols, t.Table)
+			f.formatColList(e, tp, "check columns:", t.CheckCols)
+			f.formatMutationCommon(tp, &t.MutationPrivate)
+		}
+
 	case *WithScanExpr:
 		if !f.HasFlags(ExprFmtHideColumns) {
 			child := tp.Child("mapping:")
@@ -914,6 +927,18 @@ func (f *ExprFmtCtx) formatScalarPrivate(scalar opt.ScalarExpr) {
 
 	case *FKChecksItem:
 		origin := f.Memo.metadata.TableMeta(t.OriginTable)
+		if t.Exclusion {
+			// Print the exclusion constraint as:
+			//   exclusion excl_name
+			fmt.Fprintf(f.Buffer, ": exclusion %s", origin.Table.Exclusion(t.ExclusionOrdinal).Name)
+			break
+		}
+		if t.Unique {
+			// Print the unique constraint as:
+			//   unique t_a_key
+			fmt.Fprintf(f.Buffer, ": unique %s", origin.Table.DeferrableUnique(t.UniqueOrdinal).Name)
+			break
+		}
 		referenced := f.Memo.metadata.TableMeta(t.ReferencedTable)
 		var fk cat.ForeignKeyConstraint
 		if t.FKOutbound {
@@ -1181,6 +1181,10 @@ func (b *logicalPropsBuilder) buildDeleteProps(del *DeleteExpr, rel *props.Relat
 	b.buildMutationProps(del, rel)
 }
 
+func (b *logicalPropsBuilder) buildMergeProps(mrg *MergeExpr, rel *props.Relational) {
+	b.buildMutationProps(mrg, rel)
+}
+
 func (b *logicalPropsBuilder) buildMutationProps(mutation RelExpr, rel *props.Relational) {
 	BuildSharedProps(mutation, &rel.Shared)
 
@@ -183,6 +183,9 @@ func init() {
 	typingFuncMap[opt.ConstNotNullAggOp] = typeAsFirstArg
 	typingFuncMap[opt.AnyNotNullAggOp] = typeAsFirstArg
 	typingFuncMap[opt.FirstAggOp] = typeAsFirstArg
+	typingFuncMap[opt.ModeOp] = typeAsFirstArg
+	typingFuncMap[opt.PercentileDiscOp] = typePercentile
+	typingFuncMap[opt.PercentileContOp] = typePercentile
 
 	typingFuncMap[opt.LagOp] = typeAsFirstArg
 	typingFuncMap[opt.LeadOp] = typeAsFirstArg
@@ -235,6 +238,16 @@ func typeArrayAgg(e opt.ScalarExpr) *types.T {
 	return types.MakeArray(typ)
 }
 
+// typePercentile returns the type of the aggregate expression's ordering input,
+// or an array of that type if the fraction argument is an array.
+func typePercentile(e opt.ScalarExpr) *types.T {
+	typ := e.Child(1).(opt.ScalarExpr).DataType()
+	if e.Child(0).(opt.ScalarExpr).DataType().Family() == types.ArrayFamily {
+		return types.MakeArray(typ)
+	}
+	return typ
+}
+
 // typeIndirection returns the type of the element of the array.
 func typeIndirection(e opt.ScalarExpr) *types.T {
 	return e.Child(0).(opt.ScalarExpr).DataType().ArrayContents()
@@ -631,7 +631,7 @@ func (c *CustomFuncs) EnsureAggsCanIgnoreNulls(
 }
 
 // MakeGrouping constructs a new unordered GroupingPrivate using the given
-// grouping columns. ErrorOnDup is false.
+// grouping columns. ErrorOnDup is empty.
 func (c *CustomFuncs) MakeGrouping(groupingCols opt.ColSet) *memo.GroupingPrivate {
 	return &memo.GroupingPrivate{GroupingCols: groupingCols}
 }
@@ -325,11 +325,16 @@ func (c *CustomFuncs) FoldColumnAccess(input opt.ScalarExpr, idx memo.TupleOrdin
 
 // FoldFunction evaluates a function expression with constant inputs. It
 // returns a constant expression as long as the function is contained in the
-// FoldFunctionWhitelist, and the evaluation causes no error.
+// FoldFunctionWhitelist or is a user-defined function declared IMMUTABLE, and
+// the evaluation causes no error.
 func (c *CustomFuncs) FoldFunction(
 	args memo.ScalarListExpr, private *memo.FunctionPrivate,
 ) opt.ScalarExpr {
-	if _, ok := FoldFunctionWhitelist[private.Name]; !ok {
+	if udf := private.Overload.UDF; udf != nil {
+		if udf.Volatility != tree.FunctionImmutable {
+			return nil
+		}
+	} else if _, ok := FoldFunctionWhitelist[private.Name]; !ok {
 		return nil
 	}
 
@@ -337,7 +342,7 @@ func (c *CustomFuncs) FoldFunction(
 	for i := range exprs {
 		exprs[i] = memo.ExtractConstDatum(args[i])
 	}
-	funcRef := tree.WrapFunction(private.Name)
+	funcRef := tree.WrapResolvedFunction(private.Name, private.Properties, private.Overload)
 	fn := tree.NewTypedFuncExpr(
 		funcRef,
 		0, /* aggQualifier */
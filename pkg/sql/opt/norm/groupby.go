@@ -31,7 +31,7 @@ func (c *CustomFuncs) NullsAreDistinct(distinctOp opt.Operator) bool {
 // RaisesErrorOnDup returns true if an UpsertDistinct operator raises an error
 // when duplicate values are detected.
 func (c *CustomFuncs) RaisesErrorOnDup(private *memo.GroupingPrivate) bool {
-	return private.ErrorOnDup
+	return private.ErrorOnDup != ""
 }
 
 // RemoveGroupingCols returns a new grouping private struct with the given
@@ -188,10 +188,10 @@ func (c *CustomFuncs) ConstructProjectionFromDistinctOn(
 	return c.f.ConstructProject(input, projections, passthrough)
 }
 
-// DuplicateUpsertErrText returns the error text used when duplicate input rows
-// to the Upsert operator are detected.
-func (c *CustomFuncs) DuplicateUpsertErrText() string {
-	return sqlbase.DuplicateUpsertErrText
+// ErrorOnDupText returns the text of the error raised by an UpsertDistinctOn
+// operator when duplicate input rows are detected.
+func (c *CustomFuncs) ErrorOnDupText(private *memo.GroupingPrivate) string {
+	return private.ErrorOnDup
 }
 
 // AreValuesDistinct returns true if a constant Values operator input contains
@@ -181,16 +181,70 @@ func (c *CustomFuncs) HasDuplicateRefs(
 }
 
 // CanInlineProjections returns true if all projection expressions can be
-// inlined. See CanInline for details.
-func (c *CustomFuncs) CanInlineProjections(projections memo.ProjectionsExpr) bool {
+// inlined into the given filters. See CanInline for details. The expressions
+// of virtual computed columns are an exception: filters on virtual columns
+// must be pushed below the projection that computes them in order to constrain
+// indexes on those columns. So the expression of a virtual column can be
+// inlined whatever its cost if the filters do not reference the column, in
+// which case it is not inlined at all, or if the column is indexed.
+func (c *CustomFuncs) CanInlineProjections(
+	projections memo.ProjectionsExpr, filters memo.FiltersExpr,
+) bool {
 	for i := range projections {
-		if !c.CanInline(projections[i].Element) {
+		if c.CanInline(projections[i].Element) {
+			continue
+		}
+		col := projections[i].Col
+		if !c.isVirtualColumn(col) {
+			return false
+		}
+		if c.filtersReferenceCol(filters, col) && !c.isIndexedColumn(col) {
 			return false
 		}
 	}
 	return true
 }
 
+// isVirtualColumn returns true if the given column is a virtual computed column
+// of a table.
+func (c *CustomFuncs) isVirtualColumn(col opt.ColumnID) bool {
+	md := c.mem.Metadata()
+	tabID := md.ColumnMeta(col).Table
+	if tabID == 0 {
+		return false
+	}
+	return md.Table(tabID).Column(tabID.ColumnOrdinal(col)).IsVirtual()
+}
+
+// isIndexedColumn returns true if the given table column is a key column of
+// one of the indexes of its table.
+func (c *CustomFuncs) isIndexedColumn(col opt.ColumnID) bool {
+	md := c.mem.Metadata()
+	tabID := md.ColumnMeta(col).Table
+	tab := md.Table(tabID)
+	ord := tabID.ColumnOrdinal(col)
+	for i, n := 0, tab.IndexCount(); i < n; i++ {
+		index := tab.Index(i)
+		for j, m := 0, index.KeyColumnCount(); j < m; j++ {
+			if index.Column(j).Ordinal == ord {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// filtersReferenceCol returns true if any of the filters references the given
+// column.
+func (c *CustomFuncs) filtersReferenceCol(filters memo.FiltersExpr, col opt.ColumnID) bool {
+	for i := range filters {
+		if filters[i].ScalarProps().OuterCols.Contains(col) {
+			return true
+		}
+	}
+	return false
+}
+
 // CanInline returns true if the given expression consists only of "simple"
 // operators like Variable, Const, Eq, and Plus. These operators are assumed to
 // be relatively inexpensive to evaluate, and therefore potentially evaluating
@@ -65,6 +65,9 @@ func (c *CustomFuncs) NeededMutationCols(
 	if private.CanaryCol != 0 {
 		cols.Add(private.CanaryCol)
 	}
+	if private.ActionCol != 0 {
+		cols.Add(private.ActionCol)
+	}
 
 	if private.WithID != 0 {
 		for i := range checks {
@@ -182,6 +185,13 @@ func neededMutationFetchCols(
 		}
 	}
 
+	// Row-level triggers can reference any public column of the existing row.
+	if op != opt.InsertOp && tabMeta.Table.HasRowTriggers() {
+		for i, n := 0, tabMeta.Table.ColumnCount(); i < n; i++ {
+			cols.Add(tabMeta.MetaID.ColumnID(i))
+		}
+	}
+
 	return cols
 }
 
@@ -197,6 +197,14 @@ var AggregateOpReverseMap = map[Operator]string{
 	ConstAggOp:        "any_not_null",
 	ConstNotNullAggOp: "any_not_null",
 	AnyNotNullAggOp:   "any_not_null",
+
+	PercentileDiscOp:          "percentile_disc_impl",
+	PercentileContOp:          "percentile_cont_impl",
+	ModeOp:                    "mode_impl",
+	HypotheticalRankOp:        "rank_impl",
+	HypotheticalDenseRankOp:   "dense_rank_impl",
+	HypotheticalPercentRankOp: "percent_rank_impl",
+	HypotheticalCumeDistOp:    "cume_dist_impl",
 }
 
 // WindowOpReverseMap maps from an optimizer operator type to the name of a
@@ -275,11 +283,13 @@ func AggregateIgnoresNulls(op Operator) bool {
 
 	case AnyNotNullAggOp, AvgOp, BitAndAggOp, BitOrAggOp, BoolAndOp, BoolOrOp,
 		ConstNotNullAggOp, CorrOp, CountOp, MaxOp, MinOp, SqrDiffOp, StdDevOp,
-		StringAggOp, SumOp, SumIntOp, VarianceOp, XorAggOp:
+		StringAggOp, SumOp, SumIntOp, VarianceOp, XorAggOp, ModeOp,
+		HypotheticalRankOp, HypotheticalDenseRankOp, HypotheticalPercentRankOp,
+		HypotheticalCumeDistOp:
 		return true
 
 	case ArrayAggOp, ConcatAggOp, ConstAggOp, CountRowsOp, FirstAggOp, JsonAggOp,
-		JsonbAggOp:
+		JsonbAggOp, PercentileDiscOp, PercentileContOp:
 		return false
 
 	default:
@@ -297,10 +307,11 @@ func AggregateIsNullOnEmpty(op Operator) bool {
 		BitOrAggOp, BoolAndOp, BoolOrOp, ConcatAggOp, ConstAggOp,
 		ConstNotNullAggOp, CorrOp, FirstAggOp, JsonAggOp, JsonbAggOp,
 		MaxOp, MinOp, SqrDiffOp, StdDevOp, StringAggOp, SumOp, SumIntOp,
-		VarianceOp, XorAggOp:
+		VarianceOp, XorAggOp, PercentileDiscOp, PercentileContOp, ModeOp:
 		return true
 
-	case CountOp, CountRowsOp:
+	case CountOp, CountRowsOp, HypotheticalRankOp, HypotheticalDenseRankOp,
+		HypotheticalPercentRankOp, HypotheticalCumeDistOp:
 		return false
 
 	default:
@@ -322,7 +333,9 @@ func AggregateIsNeverNullOnNonNullInput(op Operator) bool {
 		BitOrAggOp, BoolAndOp, BoolOrOp, ConcatAggOp, ConstAggOp,
 		ConstNotNullAggOp, CountOp, CountRowsOp, FirstAggOp,
 		JsonAggOp, JsonbAggOp, MaxOp, MinOp, SqrDiffOp,
-		StringAggOp, SumOp, SumIntOp, XorAggOp:
+		StringAggOp, SumOp, SumIntOp, XorAggOp, PercentileDiscOp,
+		PercentileContOp, ModeOp, HypotheticalRankOp, HypotheticalDenseRankOp,
+		HypotheticalPercentRankOp, HypotheticalCumeDistOp:
 		return true
 
 	case VarianceOp, StdDevOp, CorrOp:
@@ -338,7 +351,8 @@ func AggregateIsNeverNullOnNonNullInput(op Operator) bool {
 // returns NULL, even if the input is empty, or one more more inputs are NULL.
 func AggregateIsNeverNull(op Operator) bool {
 	switch op {
-	case CountOp, CountRowsOp:
+	case CountOp, CountRowsOp, HypotheticalRankOp, HypotheticalDenseRankOp,
+		HypotheticalPercentRankOp, HypotheticalCumeDistOp:
 		return true
 	}
 	return false
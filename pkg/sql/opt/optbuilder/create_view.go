@@ -61,14 +61,15 @@ func (b *Builder) buildCreateView(cv *tree.CreateView, inScope *scope) (outScope
 	outScope = b.allocScope()
 	outScope.expr = b.factory.ConstructCreateView(
 		&memo.CreateViewPrivate{
-			Schema:      schID,
-			ViewName:    cv.Name.Table(),
-			IfNotExists: cv.IfNotExists,
-			Replace:     cv.Replace,
-			Temporary:   cv.Temporary,
-			ViewQuery:   tree.AsStringWithFlags(cv.AsSource, tree.FmtParsable),
-			Columns:     p,
-			Deps:        b.viewDeps,
+			Schema:       schID,
+			ViewName:     cv.Name.Table(),
+			IfNotExists:  cv.IfNotExists,
+			Replace:      cv.Replace,
+			Temporary:    cv.Temporary,
+			Materialized: cv.Materialized,
+			ViewQuery:    tree.AsStringWithFlags(cv.AsSource, tree.FmtParsable),
+			Columns:      p,
+			Deps:         b.viewDeps,
 		},
 	)
 	return outScope
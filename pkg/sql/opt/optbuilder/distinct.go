@@ -52,11 +52,11 @@ func (b *Builder) constructDistinct(inScope *scope) memo.RelExpr {
 // buildDistinctOn builds a set of memo groups that represent a DISTINCT ON
 // expression. If nullsAreDistinct is true, then construct the UpsertDistinctOn
 // operator rather than the DistinctOn operator (see the UpsertDistinctOn
-// operator comment for details on the differences). The errorOnDup parameter
-// controls whether multiple rows in the same distinct group trigger an error.
-// This can only be set to true in the UpsertDistinctOn case.
+// operator comment for details on the differences). If the errorOnDup parameter
+// is not empty, multiple rows in the same distinct group trigger an error with
+// that text. This can only be set in the UpsertDistinctOn case.
 func (b *Builder) buildDistinctOn(
-	distinctOnCols opt.ColSet, inScope *scope, nullsAreDistinct, errorOnDup bool,
+	distinctOnCols opt.ColSet, inScope *scope, nullsAreDistinct bool, errorOnDup string,
 ) (outScope *scope) {
 	// When there is a DISTINCT ON clause, the ORDER BY clause is restricted to either:
 	//  1. Contain a subset of columns from the ON list, or
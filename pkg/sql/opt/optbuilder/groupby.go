@@ -234,7 +234,8 @@ func (a *aggregateInfo) TypeCheck(ctx *tree.SemaContext, desired *types.T) (tree
 // values are fed to it.
 func (a aggregateInfo) isOrderingSensitive() bool {
 	switch a.def.Name {
-	case "array_agg", "concat_agg", "string_agg", "json_agg", "jsonb_agg":
+	case "array_agg", "concat_agg", "string_agg", "json_agg", "jsonb_agg",
+		"percentile_disc_impl", "percentile_cont_impl", "mode_impl":
 		return true
 	default:
 		return false
@@ -786,6 +787,20 @@ func (b *Builder) constructAggregate(name string, args []opt.ScalarExpr) opt.Sca
 		return b.factory.ConstructJsonbAgg(args[0])
 	case "string_agg":
 		return b.factory.ConstructStringAgg(args[0], args[1])
+	case "percentile_disc_impl":
+		return b.factory.ConstructPercentileDisc(args[0], args[1])
+	case "percentile_cont_impl":
+		return b.factory.ConstructPercentileCont(args[0], args[1])
+	case "mode_impl":
+		return b.factory.ConstructMode(args[0])
+	case "rank_impl":
+		return b.factory.ConstructHypotheticalRank(args[0])
+	case "dense_rank_impl":
+		return b.factory.ConstructHypotheticalDenseRank(args[0], args[1])
+	case "percent_rank_impl":
+		return b.factory.ConstructHypotheticalPercentRank(args[0])
+	case "cume_dist_impl":
+		return b.factory.ConstructHypotheticalCumeDist(args[0])
 	}
 	panic(errors.AssertionFailedf("unhandled aggregate: %s", name))
 }
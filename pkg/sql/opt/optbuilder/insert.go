@@ -357,6 +357,12 @@ func (mb *mutationBuilder) needExistingRows() bool {
 		return true
 	}
 
+	// Row-level triggers need to know whether each row is inserted or updated,
+	// and need the existing values of updated rows.
+	if mb.tab.HasRowTriggers() {
+		return true
+	}
+
 	// Key columns are never updated and are assumed to be the same as the insert
 	// values.
 	// TODO(andyk): This is not true in the case of composite key encodings. See
@@ -632,6 +638,8 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 	mb.addCheckConstraintCols()
 
 	mb.buildFKChecksForInsert()
+	mb.buildExclusionChecks(true /* hasInsert */)
+	mb.buildUniqueChecks(true /* hasInsert */)
 
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructInsert(mb.outScope.expr, mb.checks, private)
@@ -744,7 +752,7 @@ func (mb *mutationBuilder) buildInputForDoNothing(inScope *scope, conflictOrds u
 		// Treat NULL values as distinct from one another. And if duplicates are
 		// detected, remove them rather than raising an error.
 		mb.outScope = mb.b.buildDistinctOn(
-			conflictCols, mb.outScope, true /* nullsAreDistinct */, false /* errorOnDup */)
+			conflictCols, mb.outScope, true /* nullsAreDistinct */, "" /* errorOnDup */)
 	}
 
 	mb.targetColList = make(opt.ColList, 0, mb.tab.DeletableColumnCount())
@@ -780,7 +788,7 @@ func (mb *mutationBuilder) buildInputForUpsert(
 	}
 	mb.outScope.ordering = nil
 	mb.outScope = mb.b.buildDistinctOn(
-		conflictCols, mb.outScope, true /* nullsAreDistinct */, true /* errorOnDup */)
+		conflictCols, mb.outScope, true /* nullsAreDistinct */, sqlbase.DuplicateUpsertErrText)
 
 	// Re-alias all INSERT columns so that they are accessible as if they were
 	// part of a special data source named "crdb_internal.excluded".
@@ -921,6 +929,8 @@ func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
 	mb.addCheckConstraintCols()
 
 	mb.buildFKChecksForUpsert()
+	mb.buildExclusionChecks(true /* hasInsert */)
+	mb.buildUniqueChecks(true /* hasInsert */)
 
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructUpsert(mb.outScope.expr, mb.checks, private)
@@ -0,0 +1,437 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// buildMerge builds a memo group for a MergeOp expression. The input expression
+// left-joins the rows of the source with the rows of the target table, using
+// the ON condition as the join condition. Then, the first WHEN clause that
+// applies to each row is determined, and the values to insert or update are
+// projected depending on that clause. For example, if this is the schema and
+// MERGE statement:
+//
+//   CREATE TABLE abc (a INT PRIMARY KEY, b INT, c INT)
+//   MERGE INTO abc USING xy ON a = x
+//   WHEN MATCHED AND y = 0 THEN DELETE
+//   WHEN MATCHED THEN UPDATE SET b = y
+//   WHEN NOT MATCHED THEN INSERT VALUES (x, y)
+//
+// Then an input expression roughly equivalent to this would be built:
+//
+//   SELECT
+//     CASE clause WHEN 2 THEN x END AS insert_a,
+//     CASE clause WHEN 2 THEN y END AS insert_b,
+//     CASE clause WHEN 2 THEN NULL END AS insert_c,
+//     fetch_a,
+//     fetch_b,
+//     fetch_c,
+//     CASE clause WHEN 1 THEN y ELSE fetch_b END AS update_b,
+//     CASE clause WHEN 0 THEN 2 WHEN 1 THEN 1 WHEN 2 THEN 3 END AS action
+//   FROM (
+//     SELECT DISTINCT ON (fetch_a) *, CASE
+//       WHEN fetch_a IS NOT NULL AND y = 0 THEN 0
+//       WHEN fetch_a IS NOT NULL THEN 1
+//       WHEN fetch_a IS NULL THEN 2
+//     END AS clause
+//     FROM xy LEFT OUTER JOIN abc AS fetch(fetch_a, fetch_b, fetch_c)
+//     ON fetch_a = x
+//   )
+//   WHERE clause IS NOT NULL
+//
+// As with UPSERT, a not-null "canary" column of the target table tells matched
+// and unmatched rows apart. The action column contains the tree.MergeActionType
+// of the clause, which the Merge operator uses to decide whether to insert,
+// update or delete each row. Rows that match no WHEN clause, or a DO NOTHING
+// clause, are filtered out.
+//
+// The DISTINCT ON is really an UpsertDistinctOn operator that raises an error
+// if a target row is matched by more than one source row, since that would
+// cause the statement to modify the same row more than once.
+func (b *Builder) buildMerge(mrg *tree.Merge, inScope *scope) (outScope *scope) {
+	var hasInsert, hasUpdate, hasDelete bool
+	for _, when := range mrg.Whens {
+		switch when.Action {
+		case tree.MergeInsert:
+			hasInsert = true
+		case tree.MergeUpdate:
+			hasUpdate = true
+		case tree.MergeDelete:
+			hasDelete = true
+		}
+	}
+
+	// Find which table we're working on, check the permissions. The existing
+	// rows are always read in order to match them with the source rows.
+	tab, depName, alias, refColumns := b.resolveTableForMutation(mrg.Table, privilege.SELECT)
+
+	if refColumns != nil {
+		panic(pgerror.Newf(pgcode.Syntax,
+			"cannot specify a list of column IDs with MERGE"))
+	}
+
+	if hasInsert {
+		b.checkPrivilege(depName, tab, privilege.INSERT)
+	}
+	if hasUpdate {
+		b.checkPrivilege(depName, tab, privilege.UPDATE)
+	}
+	if hasDelete {
+		b.checkPrivilege(depName, tab, privilege.DELETE)
+	}
+
+	var mb mutationBuilder
+	mb.init(b, "merge", tab, alias)
+
+	// Build the input expression that joins the source rows with the rows of
+	// the target table.
+	sourceScope := mb.buildInputForMerge(inScope, mrg.Source, mrg.On)
+
+	// Project the action, insert, and update columns of each row, according to
+	// the WHEN clauses.
+	mb.addMergeActionCols(sourceScope, mrg.Whens)
+
+	// Add default and computed columns for inserts, and computed columns for
+	// updates. Computed column expressions refer to other columns by name, so
+	// the names of the source columns must not shadow those of the inserted or
+	// updated columns.
+	for i := range mb.outScope.cols {
+		mb.outScope.cols[i].clearName()
+	}
+	if hasInsert {
+		mb.nameMergeCols(mb.insertOrds)
+		mb.addSynthesizedColsForInsert()
+	}
+	if hasUpdate {
+		// Columns that are not updated keep their existing values.
+		mb.nameMergeCols(mb.fetchOrds)
+		mb.nameMergeCols(mb.updateOrds)
+		mb.addSynthesizedColsForUpdate()
+	}
+
+	// Build the final merge statement.
+	mb.buildMerge(hasInsert, hasDelete)
+
+	return mb.outScope
+}
+
+// buildInputForMerge left-joins the rows of the given source to the rows of the
+// target table, using the given ON condition as the join condition. It also
+// selects one of the table columns to be a "canary column" that can be tested
+// to determine whether a given source row matches an existing row in the table.
+// It returns the scope of the source.
+func (mb *mutationBuilder) buildInputForMerge(
+	inScope *scope, source tree.TableExpr, on tree.Expr,
+) (sourceScope *scope) {
+	sourceScope = mb.b.buildDataSource(source, nil /* indexFlags */, noRowLocking, inScope)
+
+	// Build the right side of the left outer join.
+	//
+	// NOTE: Include mutation columns, but be careful to never use them for any
+	//       reason other than as "fetch columns". See buildScan comment.
+	fetchScope := mb.b.buildScan(
+		mb.b.addTable(mb.tab, &mb.alias),
+		nil, /* ordinals */
+		nil, /* indexFlags */
+		noRowLocking,
+		includeMutations,
+		inScope,
+	)
+
+	// Check that the same table name is not used on both sides.
+	mb.b.validateJoinTableNames(sourceScope, fetchScope)
+
+	// Record a not-null "canary" column. After the left-join, this will be null
+	// if the source row has no matching row, or not null otherwise.
+	mb.canaryColID = fetchScope.cols[findNotNullIndexCol(mb.tab.Index(cat.PrimaryIndex))].id
+
+	// Both the source and the fetch columns are visible to the ON condition.
+	mb.outScope = sourceScope.replace()
+	mb.outScope.appendColumnsFromScope(sourceScope)
+	for i := range fetchScope.cols {
+		// Fetch columns come after source columns.
+		mb.fetchOrds[i] = scopeOrdinal(len(mb.outScope.cols) + i)
+	}
+	mb.outScope.appendColumnsFromScope(fetchScope)
+
+	filter := mb.b.resolveAndBuildScalar(
+		on,
+		types.Bool,
+		exprKindOn,
+		tree.RejectGenerators|tree.RejectWindowApplications,
+		mb.outScope,
+	)
+	mb.outScope.expr = mb.b.factory.ConstructLeftJoin(
+		sourceScope.expr,
+		fetchScope.expr,
+		memo.FiltersExpr{mb.b.factory.ConstructFiltersItem(filter)},
+		memo.EmptyJoinPrivate,
+	)
+	return sourceScope
+}
+
+// mergeValue is the value of a column of the target table that is inserted or
+// updated by a WHEN clause of a MERGE statement.
+type mergeValue struct {
+	clause int
+	value  opt.ScalarExpr
+}
+
+// addMergeActionCols evaluates the WHEN clauses for each row and projects the
+// action column, along with one column for each target table column that is
+// inserted or updated by some WHEN clause. See the comment header for
+// Builder.buildMerge for an example.
+func (mb *mutationBuilder) addMergeActionCols(sourceScope *scope, whens tree.MergeWhens) {
+	f := mb.b.factory
+
+	// WHEN conditions and values should reject aggregates, generators, etc.
+	scalarProps := &mb.b.semaCtx.Properties
+	defer scalarProps.Restore(*scalarProps)
+	mb.b.semaCtx.Properties.Require("MERGE", tree.RejectSpecial)
+
+	// The WHEN MATCHED clauses can refer to the source and target columns, but
+	// the WHEN NOT MATCHED clauses can only refer to the source columns.
+	joinScope := mb.outScope
+	srcScope := mb.outScope.replace()
+	srcScope.appendColumnsFromScope(sourceScope)
+
+	canary := f.ConstructVariable(mb.canaryColID)
+	clauseWhens := make(memo.ScalarListExpr, 0, len(whens))
+	actionWhens := make(memo.ScalarListExpr, 0, len(whens))
+	insertVals := make([][]mergeValue, mb.tab.ColumnCount())
+	updateVals := make([][]mergeValue, mb.tab.ColumnCount())
+	for i, when := range whens {
+		inScope := srcScope
+		var cond opt.ScalarExpr
+		if when.Matched {
+			inScope = joinScope
+			cond = f.ConstructIsNot(canary, memo.NullSingleton)
+		} else {
+			cond = f.ConstructIs(canary, memo.NullSingleton)
+		}
+		if when.Cond != nil {
+			texpr := inScope.resolveAndRequireType(when.Cond, types.Bool)
+			cond = f.ConstructAnd(cond, mb.b.buildScalar(texpr, inScope, nil, nil, nil))
+		}
+
+		// A DO NOTHING clause leaves its rows unchanged, so they are filtered out
+		// along with the rows that no clause applies to.
+		if when.Action == tree.MergeDoNothing {
+			clauseWhens = append(clauseWhens, f.ConstructWhen(cond, f.ConstructNull(types.Int)))
+			continue
+		}
+		clause := f.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int)
+		clauseWhens = append(clauseWhens, f.ConstructWhen(cond, clause))
+		actionWhens = append(actionWhens, f.ConstructWhen(
+			clause, f.ConstructConstVal(tree.NewDInt(tree.DInt(when.Action)), types.Int),
+		))
+
+		mb.targetColList = mb.targetColList[:0]
+		mb.targetColSet = opt.ColSet{}
+		switch when.Action {
+		case tree.MergeInsert:
+			switch {
+			case when.Values == nil:
+				// INSERT DEFAULT VALUES.
+			case len(when.Columns) != 0:
+				mb.addTargetNamedColsForInsert(when.Columns)
+				mb.checkNumCols(len(mb.targetColList), len(when.Values))
+			default:
+				mb.addTargetTableColsForInsert(len(when.Values))
+			}
+
+			// Columns that are not targeted get their default values. Computed
+			// columns are added later.
+			for ord := range insertVals {
+				if mb.tab.Column(ord).IsComputed() {
+					continue
+				}
+				var expr tree.Expr = tree.DefaultVal{}
+				if j, ok := mb.targetColList.Find(mb.tabID.ColumnID(ord)); ok {
+					expr = when.Values[j]
+				}
+				insertVals[ord] = append(insertVals[ord], mergeValue{
+					clause: i, value: mb.buildMergeValue(expr, ord, srcScope),
+				})
+			}
+
+		case tree.MergeUpdate:
+			for _, set := range when.Exprs {
+				if _, ok := set.Expr.(*tree.Subquery); ok && set.Tuple {
+					panic(unimplementedWithIssueDetailf(35713, "merge update subquery",
+						"source for a multiple-column UPDATE item in MERGE must be a ROW() expression"))
+				}
+			}
+			mb.addTargetColsForUpdate(when.Exprs)
+
+			n := 0
+			for _, set := range when.Exprs {
+				exprs := tree.Exprs{set.Expr}
+				if set.Tuple {
+					exprs = set.Expr.(*tree.Tuple).Exprs
+				}
+				for _, expr := range exprs {
+					ord := mb.tabID.ColumnOrdinal(mb.targetColList[n])
+					n++
+					updateVals[ord] = append(updateVals[ord], mergeValue{
+						clause: i, value: mb.buildMergeValue(expr, ord, joinScope),
+					})
+				}
+			}
+		}
+	}
+	mb.targetColList = mb.targetColList[:0]
+	mb.targetColSet = opt.ColSet{}
+
+	// Project the index of the clause that applies to each row, and filter out
+	// the rows that are left unchanged.
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	clauseCol := mb.b.synthesizeColumn(
+		projectionsScope,
+		"merge_clause",
+		types.Int,
+		nil, /* expr */
+		f.ConstructCase(memo.TrueSingleton, clauseWhens, f.ConstructNull(types.Int)),
+	)
+	clauseColID := clauseCol.id
+	clauseCol.clearName()
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+	mb.outScope.expr = f.ConstructSelect(
+		mb.outScope.expr,
+		memo.FiltersExpr{f.ConstructFiltersItem(
+			f.ConstructIsNot(f.ConstructVariable(clauseColID), memo.NullSingleton),
+		)},
+	)
+
+	// Ensure that each target row is affected at most once. Unmatched rows have
+	// null primary key values, which are considered distinct.
+	var pkCols opt.ColSet
+	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+	for i, n := 0, primaryIndex.KeyColumnCount(); i < n; i++ {
+		pkCols.Add(mb.scopeOrdToColID(mb.fetchOrds[primaryIndex.Column(i).Ordinal]))
+	}
+	mb.outScope.ordering = nil
+	mb.outScope = mb.b.buildDistinctOn(
+		pkCols, mb.outScope, true /* nullsAreDistinct */, sqlbase.DuplicateMergeErrText)
+
+	// Project the action column and the insert and update columns.
+	projectionsScope = mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	clause := f.ConstructVariable(clauseColID)
+	actionCol := mb.b.synthesizeColumn(
+		projectionsScope,
+		"merge_action",
+		types.Int,
+		nil, /* expr */
+		f.ConstructCase(clause, actionWhens, f.ConstructNull(types.Int)),
+	)
+	mb.actionColID = actionCol.id
+	actionCol.clearName()
+
+	addCol := func(
+		ord int, prefix string, vals []mergeValue, orElse opt.ScalarExpr,
+	) scopeOrdinal {
+		whens := make(memo.ScalarListExpr, len(vals))
+		for i := range vals {
+			whens[i] = f.ConstructWhen(
+				f.ConstructConstVal(tree.NewDInt(tree.DInt(vals[i].clause)), types.Int),
+				vals[i].value,
+			)
+		}
+		tabCol := mb.tab.Column(ord)
+		alias := fmt.Sprintf("%s_%s", prefix, tabCol.ColName())
+		mb.b.synthesizeColumn(
+			projectionsScope, alias, tabCol.DatumType(), nil /* expr */, f.ConstructCase(clause, whens, orElse),
+		)
+		return scopeOrdinal(len(projectionsScope.cols) - 1)
+	}
+	for ord := range insertVals {
+		if len(insertVals[ord]) != 0 {
+			mb.insertOrds[ord] = addCol(
+				ord, "insert", insertVals[ord], f.ConstructNull(mb.tab.Column(ord).DatumType()),
+			)
+		}
+	}
+	for ord := range updateVals {
+		if len(updateVals[ord]) != 0 {
+			// Rows that are not updated by the clause keep their existing value.
+			fetchCol := f.ConstructVariable(mb.scopeOrdToColID(mb.fetchOrds[ord]))
+			mb.updateOrds[ord] = addCol(ord, "update", updateVals[ord], fetchCol)
+		}
+	}
+
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+}
+
+// buildMergeValue builds the given value of a WHEN clause for the column at the
+// given ordinal position in the target table. The value can be DEFAULT.
+func (mb *mutationBuilder) buildMergeValue(expr tree.Expr, ord int, inScope *scope) opt.ScalarExpr {
+	if _, ok := expr.(tree.DefaultVal); ok {
+		expr = mb.parseDefaultOrComputedExpr(mb.tabID.ColumnID(ord))
+	}
+
+	// Type check the input expression against the corresponding table column.
+	tabCol := mb.tab.Column(ord)
+	texpr := inScope.resolveType(expr, tabCol.DatumType())
+	checkDatumTypeFitsColumnType(tabCol, texpr.ResolvedType())
+	return mb.b.buildScalar(texpr, inScope, nil, nil, nil)
+}
+
+// nameMergeCols assigns the names of the corresponding target table columns to
+// the given scope columns, so that computed column expressions can refer to
+// them.
+func (mb *mutationBuilder) nameMergeCols(scopeOrds []scopeOrdinal) {
+	for i, ord := range scopeOrds {
+		if ord != -1 {
+			mb.outScope.cols[ord].name = mb.tab.Column(i).ColName()
+		}
+	}
+}
+
+// buildMerge constructs a Merge operator.
+func (mb *mutationBuilder) buildMerge(hasInsert, hasDelete bool) {
+	// Merge input insert and update columns using CASE expressions, so that the
+	// constraints are checked against the final values of the rows.
+	if hasInsert {
+		mb.projectUpsertColumns()
+	}
+
+	// Add any check constraint boolean columns to the input.
+	mb.addCheckConstraintCols()
+
+	mb.buildFKChecksForMerge(hasInsert, hasDelete)
+	mb.buildExclusionChecks(hasInsert)
+	mb.buildUniqueChecks(hasInsert)
+
+	// The Merge operator uses the action column rather than the canary column
+	// to decide what to do with each row.
+	mb.canaryColID = 0
+
+	private := mb.makeMutationPrivate(false /* needResults */)
+	mb.outScope.expr = mb.b.factory.ConstructMerge(mb.outScope.expr, mb.checks, private)
+
+	mb.buildReturning(nil /* returning */)
+}
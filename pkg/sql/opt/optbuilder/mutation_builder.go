@@ -31,7 +31,7 @@ import (
 )
 
 // mutationBuilder is a helper struct that supports building Insert, Update,
-// Upsert, and Delete operators in stages.
+// Upsert, Delete, and Merge operators in stages.
 // TODO(andyk): Add support for Delete.
 type mutationBuilder struct {
 	b  *Builder
@@ -115,6 +115,11 @@ type mutationBuilder struct {
 	// an insert; otherwise it's an update.
 	canaryColID opt.ColumnID
 
+	// actionColID is the ID of the column that contains the action of each row
+	// of a Merge operator (see tree.MergeActionType). It is 0 for all other
+	// operators.
+	actionColID opt.ColumnID
+
 	// subqueries temporarily stores subqueries that were built during initial
 	// analysis of SET expressions. They will be used later when the subqueries
 	// are joined into larger LEFT OUTER JOIN expressions.
@@ -245,21 +250,7 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	// If there is a FROM clause present, we must join all the tables
 	// together with the table being updated.
 	if fromClausePresent {
-		fromScope := mb.b.buildFromTables(from, noRowLocking, inScope)
-
-		// Check that the same table name is not used multiple times.
-		mb.b.validateJoinTableNames(mb.outScope, fromScope)
-
-		// The FROM table columns can be accessed by the RETURNING clause of the
-		// query and so we have to make them accessible.
-		mb.extraAccessibleCols = fromScope.cols
-
-		// Add the columns in the FROM scope.
-		mb.outScope.appendColumnsFromScope(fromScope)
-
-		left := mb.outScope.expr.(memo.RelExpr)
-		right := fromScope.expr.(memo.RelExpr)
-		mb.outScope.expr = mb.b.factory.ConstructInnerJoin(left, right, memo.TrueFilter, memo.EmptyJoinPrivate)
+		mb.joinFromTables(inScope, from)
 	}
 
 	// WHERE
@@ -282,26 +273,7 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	// Build a distinct on to ensure there is at most one row in the joined output
 	// for every row in the table.
 	if fromClausePresent {
-		var pkCols opt.ColSet
-
-		// We need to ensure that the join has a maximum of one row for every row
-		// in the table and we ensure this by constructing a distinct on the primary
-		// key columns.
-		primaryIndex := mb.tab.Index(cat.PrimaryIndex)
-		for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
-			pkCol := mb.outScope.cols[primaryIndex.Column(i).Ordinal]
-
-			// If the primary key column is hidden, then we don't need to use it
-			// for the distinct on.
-			if !pkCol.hidden {
-				pkCols.Add(pkCol.id)
-			}
-		}
-
-		if !pkCols.Empty() {
-			mb.outScope = mb.b.buildDistinctOn(
-				pkCols, mb.outScope, false /* nullsAreDistinct */, false /* errorOnDup */)
-		}
+		mb.buildDistinctOnPrimaryKey()
 	}
 
 	// Set list of columns that will be fetched by the input expression.
@@ -310,6 +282,47 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	}
 }
 
+// joinFromTables builds each of the given table expressions and joins them
+// with the target table, which must already have been built into outScope.
+// This is used for the FROM clause of UPDATE and the USING clause of DELETE.
+// The join is built as an inner join with the WHERE conditions added on top,
+// so that the conditions can be pushed into the join and the join can be
+// planned like any other join (e.g. as a lookup or merge join).
+//
+// The columns of the joined tables are stored in the mutation builder so they
+// can be made accessible to other parts of the query (RETURNING clause).
+func (mb *mutationBuilder) joinFromTables(inScope *scope, from tree.TableExprs) {
+	fromScope := mb.b.buildFromTables(from, noRowLocking, inScope)
+
+	// Check that the same table name is not used multiple times.
+	mb.b.validateJoinTableNames(mb.outScope, fromScope)
+
+	// The FROM table columns can be accessed by the RETURNING clause of the
+	// query and so we have to make them accessible.
+	mb.extraAccessibleCols = fromScope.cols
+
+	// Add the columns in the FROM scope.
+	mb.outScope.appendColumnsFromScope(fromScope)
+
+	left := mb.outScope.expr.(memo.RelExpr)
+	right := fromScope.expr.(memo.RelExpr)
+	mb.outScope.expr = mb.b.factory.ConstructInnerJoin(left, right, memo.TrueFilter, memo.EmptyJoinPrivate)
+}
+
+// buildDistinctOnPrimaryKey wraps the input expression in a DistinctOn on the
+// primary key columns of the target table. This ensures that the joined output
+// of joinFromTables has at most one row for every row in the table, so that
+// each target row is mutated at most once.
+func (mb *mutationBuilder) buildDistinctOnPrimaryKey() {
+	var pkCols opt.ColSet
+	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+	for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
+		pkCols.Add(mb.outScope.cols[primaryIndex.Column(i).Ordinal].id)
+	}
+	mb.outScope = mb.b.buildDistinctOn(
+		pkCols, mb.outScope, false /* nullsAreDistinct */, "" /* errorOnDup */)
+}
+
 // buildInputForDelete constructs a Select expression from the fields in
 // the Delete operator, similar to this:
 //
@@ -320,9 +333,18 @@ func (mb *mutationBuilder) buildInputForUpdate(
 //   LIMIT <limit>
 //
 // All columns from the table to update are added to fetchColList.
+// If a USING clause is defined, the USING tables are joined with the target
+// table in the same way as the FROM tables of an UPDATE statement. Each target
+// row is deleted at most once, even if it joins with multiple rows of the USING
+// tables (consistent with the POSTGRES implementation).
 // TODO(andyk): Do needed column analysis to project fewer columns if possible.
 func (mb *mutationBuilder) buildInputForDelete(
-	inScope *scope, texpr tree.TableExpr, where *tree.Where, limit *tree.Limit, orderBy tree.OrderBy,
+	inScope *scope,
+	texpr tree.TableExpr,
+	using tree.TableExprs,
+	where *tree.Where,
+	limit *tree.Limit,
+	orderBy tree.OrderBy,
 ) {
 	var indexFlags *tree.IndexFlags
 	if source, ok := texpr.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
@@ -348,6 +370,15 @@ func (mb *mutationBuilder) buildInputForDelete(
 		inScope,
 	)
 
+	usingClausePresent := len(using) > 0
+	numCols := len(mb.outScope.cols)
+
+	// If there is a USING clause present, we must join all the tables
+	// together with the table being deleted from.
+	if usingClausePresent {
+		mb.joinFromTables(inScope, using)
+	}
+
 	// WHERE
 	mb.b.buildWhere(where, mb.outScope)
 
@@ -365,8 +396,14 @@ func (mb *mutationBuilder) buildInputForDelete(
 
 	mb.outScope = projectionsScope
 
+	// Build a distinct on to ensure there is at most one row in the joined output
+	// for every row in the table.
+	if usingClausePresent {
+		mb.buildDistinctOnPrimaryKey()
+	}
+
 	// Set list of columns that will be fetched by the input expression.
-	for i := range mb.outScope.cols {
+	for i := 0; i < numCols; i++ {
 		mb.fetchOrds[i] = scopeOrdinal(i)
 	}
 }
@@ -745,6 +782,7 @@ func (mb *mutationBuilder) makeMutationPrivate(needResults bool) *memo.MutationP
 		FetchCols:  makeColList(mb.fetchOrds),
 		UpdateCols: makeColList(mb.updateOrds),
 		CanaryCol:  mb.canaryColID,
+		ActionCol:  mb.actionColID,
 		CheckCols:  makeColList(mb.checkOrds),
 		FKFallback: mb.fkFallback,
 	}
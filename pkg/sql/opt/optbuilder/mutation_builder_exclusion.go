@@ -0,0 +1,253 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// buildExclusionChecks populates mb.checks with queries that check the
+// exclusion constraints of the table against the new or updated rows.
+//
+// Like the foreign key checks, the exclusion checks are queries that run after
+// the statement (including the relevant mutation) completes; any row that is
+// returned indicates a violation. Each check is a semi-join with the left side
+// being a WithScan of the new values and the right side being a scan of the
+// same table. The join filters require that the two rows are distinct and
+// that all elements of the constraint compare true. For example, the
+// constraint EXCLUDE (room WITH =, tsrange(s, e) WITH &&) would require:
+//
+//   insert r
+//    ├── ...
+//    ├── input binding: &1
+//    └── f-k-checks
+//         └── f-k-checks-item: exclusion excl_room_s_e
+//              └── semi-join (hash)
+//                   ├── with-scan &1
+//                   ├── scan r
+//                   └── filters
+//                        ├── id:8 != r.id:12
+//                        ├── room:9 = r.room:13
+//                        └── (s:10 IS NULL) OR (r.e:15 IS NULL) OR (s:10 < r.e:15) ...
+//
+// hasInsert is false if the statement only updates rows; in that case only
+// the constraints that involve updated columns are checked.
+//
+func (mb *mutationBuilder) buildExclusionChecks(hasInsert bool) {
+	if mb.tab.ExclusionCount() == 0 {
+		// No exclusion constraints.
+		return
+	}
+
+	for i, n := 0, mb.tab.ExclusionCount(); i < n; i++ {
+		excl := mb.tab.Exclusion(i)
+		elems := make([]tree.Expr, len(excl.Elements))
+		for j := range excl.Elements {
+			expr, err := parser.ParseExpr(excl.Elements[j].Expr)
+			if err != nil {
+				panic(err)
+			}
+			elems[j] = expr
+		}
+		var pred tree.Expr
+		if excl.Predicate != "" {
+			expr, err := parser.ParseExpr(excl.Predicate)
+			if err != nil {
+				panic(err)
+			}
+			pred = expr
+		}
+		if !hasInsert && !mb.exclusionColsUpdated(elems, pred) {
+			continue
+		}
+
+		if mb.withID == 0 {
+			mb.withID = mb.b.factory.Memo().NextWithID()
+		}
+		mb.checks = append(mb.checks, mb.buildExclusionCheck(i, elems, pred))
+	}
+}
+
+// buildExclusionCheck creates the check for the exclusion constraint with the
+// given ordinal, given its parsed elements and predicate.
+func (mb *mutationBuilder) buildExclusionCheck(
+	exclOrdinal int, elems []tree.Expr, pred tree.Expr,
+) memo.FKChecksItem {
+	f := mb.b.factory
+	excl := mb.tab.Exclusion(exclOrdinal)
+
+	// Build the scope of the new values, with the same column names as the
+	// table so that the constraint expressions can be resolved against it.
+	h := fkCheckHelper{mb: mb}
+	for i, n := 0, mb.tab.ColumnCount(); i < n; i++ {
+		if mb.mapToReturnScopeOrd(i) != -1 {
+			h.tabOrdinals = append(h.tabOrdinals, i)
+		}
+	}
+	newRows, newCols, _ := h.makeFKInputScan(fkInputScanNewVals)
+	newScope := mb.b.allocScope()
+	newScope.expr = newRows
+	newColByOrd := make(map[int]opt.ColumnID, len(newCols))
+	for i, tabOrd := range h.tabOrdinals {
+		col := mb.tab.Column(tabOrd)
+		newScope.cols = append(newScope.cols, scopeColumn{
+			name: col.ColName(),
+			typ:  col.DatumType(),
+			id:   newCols[i],
+		})
+		newColByOrd[tabOrd] = newCols[i]
+	}
+
+	// Build the scan of the existing rows.
+	tabMeta := mb.b.addTable(mb.tab, tree.NewUnqualifiedTableName(mb.tab.Name()))
+	scanScope := mb.b.buildScan(
+		tabMeta,
+		nil, /* ordinals */
+		&tree.IndexFlags{IgnoreForeignKeys: true},
+		noRowLocking,
+		excludeMutations,
+		mb.b.allocScope(),
+	)
+
+	build := func(expr tree.Expr, inScope *scope, typ *types.T) opt.ScalarExpr {
+		texpr := inScope.resolveAndRequireType(expr, typ)
+		return mb.b.buildScalar(texpr, inScope, nil, nil, nil)
+	}
+
+	// The two rows must be distinct:
+	//   (new.pk1 != existing.pk1) OR (new.pk2 != existing.pk2) ...
+	var filters memo.FiltersExpr
+	var distinct opt.ScalarExpr
+	var keyCols opt.ColList
+	primary := mb.tab.Index(cat.PrimaryIndex)
+	for i, n := 0, primary.KeyColumnCount(); i < n; i++ {
+		ord := primary.Column(i).Ordinal
+		newCol := newColByOrd[ord]
+		ne := f.ConstructNe(
+			f.ConstructVariable(newCol),
+			f.ConstructVariable(tabMeta.MetaID.ColumnID(ord)),
+		)
+		if distinct == nil {
+			distinct = ne
+		} else {
+			distinct = f.ConstructOr(distinct, ne)
+		}
+		keyCols = append(keyCols, newCol)
+	}
+	filters = append(filters, f.ConstructFiltersItem(distinct))
+
+	// All of the elements must compare true.
+	for i, elem := range elems {
+		r, isRange, err := sqlbase.MakeExclusionRange(elem)
+		if err != nil {
+			panic(err)
+		}
+		if !isRange {
+			newVal := build(elem, newScope, types.Any)
+			existingVal := build(elem, scanScope, types.Any)
+			var cmp opt.ScalarExpr
+			if excl.Elements[i].Operator == tree.Overlaps {
+				cmp = f.ConstructOverlaps(newVal, existingVal)
+			} else {
+				cmp = f.ConstructEq(newVal, existingVal)
+			}
+			filters = append(filters, f.ConstructFiltersItem(cmp))
+			continue
+		}
+
+		newLower := build(r.Lower, newScope, r.BoundType)
+		newUpper := build(r.Upper, newScope, r.BoundType)
+		existingLower := build(r.Lower, scanScope, r.BoundType)
+		existingUpper := build(r.Upper, scanScope, r.BoundType)
+
+		// A lower bound comes before an upper bound if either one is NULL
+		// (unbounded) or if it compares less than the upper bound:
+		//   (lower IS NULL) OR (upper IS NULL) OR (lower < upper)
+		before := func(lower, upper opt.ScalarExpr) opt.ScalarExpr {
+			var cmp opt.ScalarExpr
+			if r.BoundOperator() == tree.LE {
+				cmp = f.ConstructLe(lower, upper)
+			} else {
+				cmp = f.ConstructLt(lower, upper)
+			}
+			return f.ConstructOr(
+				f.ConstructOr(
+					f.ConstructIs(lower, memo.NullSingleton),
+					f.ConstructIs(upper, memo.NullSingleton),
+				),
+				cmp,
+			)
+		}
+		// Two ranges overlap if neither is empty and each one's lower bound comes
+		// before the other one's upper bound.
+		filters = append(filters,
+			f.ConstructFiltersItem(before(newLower, newUpper)),
+			f.ConstructFiltersItem(before(existingLower, existingUpper)),
+			f.ConstructFiltersItem(before(newLower, existingUpper)),
+			f.ConstructFiltersItem(before(existingLower, newUpper)),
+		)
+	}
+
+	// Only the rows that satisfy the predicate are constrained.
+	if pred != nil {
+		newRows = f.ConstructSelect(
+			newRows,
+			memo.FiltersExpr{f.ConstructFiltersItem(build(pred, newScope, types.Bool))},
+		)
+		filters = append(filters, f.ConstructFiltersItem(build(pred, scanScope, types.Bool)))
+	}
+
+	semiJoin := f.ConstructSemiJoin(newRows, scanScope.expr, filters, &memo.JoinPrivate{})
+
+	return f.ConstructFKChecksItem(semiJoin, &memo.FKChecksItemPrivate{
+		OriginTable:      mb.tabID,
+		ReferencedTable:  tabMeta.MetaID,
+		Exclusion:        true,
+		ExclusionOrdinal: exclOrdinal,
+		KeyCols:          keyCols,
+		OpName:           mb.opName,
+	})
+}
+
+// exclusionColsUpdated returns true if any of the columns referenced by the
+// given exclusion constraint elements or predicate are being updated
+// (according to updateOrds).
+func (mb *mutationBuilder) exclusionColsUpdated(elems []tree.Expr, pred tree.Expr) bool {
+	updated := false
+	visit := func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if n, ok := expr.(*tree.UnresolvedName); ok && n.NumParts == 1 && !n.Star {
+			for i, c := 0, mb.tab.ColumnCount(); i < c; i++ {
+				if string(mb.tab.Column(i).ColName()) == n.Parts[0] && mb.updateOrds[i] != -1 {
+					updated = true
+				}
+			}
+			return false, expr, nil
+		}
+		return !updated, expr, nil
+	}
+	for _, expr := range elems {
+		if _, err := tree.SimpleVisit(expr, visit); err != nil {
+			panic(err)
+		}
+	}
+	if pred != nil {
+		if _, err := tree.SimpleVisit(pred, visit); err != nil {
+			panic(err)
+		}
+	}
+	return updated
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/errors"
 )
 
@@ -396,6 +397,84 @@ func (mb *mutationBuilder) buildFKChecksForUpsert() {
 	telemetry.Inc(sqltelemetry.ForeignKeyChecksUseCounter)
 }
 
+// buildFKChecksForMerge builds FK check queries for a merge. The checks
+// combine those of an upsert with those of a delete:
+//
+//   - insertion-side checks are needed for the FK relations that involve
+//     inserted or updated columns;
+//
+//   - deletion-side checks are needed for the FK relations that involve
+//     updated columns, or for all inbound FK relations if rows are deleted.
+//
+// The input scans of the checks only include the rows with relevant actions;
+// see makeFKInputScan. The deletion-side checks use the set difference between
+// "old" FK values and "new" FK values, as for buildFKChecksForUpdate.
+//
+func (mb *mutationBuilder) buildFKChecksForMerge(hasInsert, hasDelete bool) {
+	numOutbound := mb.tab.OutboundForeignKeyCount()
+	numInbound := mb.tab.InboundForeignKeyCount()
+
+	if numOutbound == 0 && numInbound == 0 {
+		return
+	}
+
+	if !mb.b.evalCtx.SessionData.OptimizerFKs {
+		mb.fkFallback = true
+		telemetry.Inc(sqltelemetry.ForeignKeyLegacyUseCounter)
+		return
+	}
+
+	mb.withID = mb.b.factory.Memo().NextWithID()
+
+	h := &mb.fkCheckHelper
+	for i := 0; i < numOutbound; i++ {
+		if !hasInsert && !mb.outboundFKColsUpdated(i) {
+			continue
+		}
+		if h.initWithOutboundFK(mb, i) {
+			mb.checks = append(mb.checks, h.buildInsertionCheck())
+		}
+	}
+
+	for i := 0; i < numInbound; i++ {
+		if !hasDelete && !mb.inboundFKColsUpdated(i) {
+			continue
+		}
+
+		if !h.initWithInboundFK(mb, i) {
+			continue
+		}
+
+		delAction, updAction := h.fk.DeleteReferenceAction(), h.fk.UpdateReferenceAction()
+		if (hasDelete && delAction != tree.Restrict && delAction != tree.NoAction) ||
+			(mb.inboundFKColsUpdated(i) && updAction != tree.Restrict && updAction != tree.NoAction) {
+			// Bail, so that exec FK checks pick up on FK checks and perform them.
+			mb.checks = nil
+			mb.fkFallback = true
+			telemetry.Inc(sqltelemetry.ForeignKeyCascadesUseCounter)
+			telemetry.Inc(sqltelemetry.ForeignKeyLegacyUseCounter)
+			return
+		}
+
+		oldRows, colsForOldRow, _ := h.makeFKInputScan(fkInputScanFetchedVals)
+		newRows, colsForNewRow, _ := h.makeFKInputScan(fkInputScanNewVals)
+
+		// The rows that no longer exist are the ones that were deleted or updated
+		// _from_, minus the ones that were inserted or updated _to_.
+		deletedRows := mb.b.factory.ConstructExcept(
+			oldRows,
+			newRows,
+			&memo.SetPrivate{
+				LeftCols:  colsForOldRow,
+				RightCols: colsForNewRow,
+				OutCols:   colsForOldRow,
+			},
+		)
+		mb.checks = append(mb.checks, h.buildDeletionCheck(deletedRows, colsForOldRow))
+	}
+	telemetry.Inc(sqltelemetry.ForeignKeyChecksUseCounter)
+}
+
 // outboundFKColsUpdated returns true if any of the FK columns for an outbound
 // constraint are being updated (according to updateOrds).
 func (mb *mutationBuilder) outboundFKColsUpdated(fkOrdinal int) bool {
@@ -521,6 +600,9 @@ const (
 // The WithScan expression will scan either the new values or the fetched values
 // for the given table ordinals (which correspond to FK columns).
 //
+// For Merge, only the rows whose action produces new values (or removes the
+// fetched values) are scanned.
+//
 // Returns the output columns from the WithScan, which map 1-to-1 to
 // h.tabOrdinals. Also returns the subset of these columns that can be assumed
 // to be not null (either because they are not null in the mutation input or
@@ -531,7 +613,7 @@ func (h *fkCheckHelper) makeFKInputScan(
 ) (scan memo.RelExpr, outCols opt.ColList, notNullOutCols opt.ColSet) {
 	mb := h.mb
 	// inputCols are the column IDs from the mutation input that we are scanning.
-	inputCols := make(opt.ColList, len(h.tabOrdinals))
+	inputCols := make(opt.ColList, len(h.tabOrdinals), len(h.tabOrdinals)+1)
 	// outCols will store the newly synthesized output columns for WithScan.
 	outCols = make(opt.ColList, len(inputCols))
 	for i, tabOrd := range h.tabOrdinals {
@@ -557,13 +639,45 @@ func (h *fkCheckHelper) makeFKInputScan(
 		}
 	}
 
+	if mb.actionColID == 0 {
+		scan = mb.b.factory.ConstructWithScan(&memo.WithScanPrivate{
+			With:         mb.withID,
+			InCols:       inputCols,
+			OutCols:      outCols,
+			BindingProps: mb.outScope.expr.Relational(),
+			ID:           mb.b.factory.Metadata().NextUniqueID(),
+		})
+		return scan, outCols, notNullOutCols
+	}
+
+	// Also scan the Merge action column, and filter out the rows that don't
+	// insert or update new values (for new values), or that don't update or
+	// delete existing rows (for fetched values).
+	actionCol := mb.md.AddColumn("action", types.Int)
 	scan = mb.b.factory.ConstructWithScan(&memo.WithScanPrivate{
 		With:         mb.withID,
-		InCols:       inputCols,
-		OutCols:      outCols,
+		InCols:       append(inputCols, mb.actionColID),
+		OutCols:      append(outCols[:len(outCols):len(outCols)], actionCol),
 		BindingProps: mb.outScope.expr.Relational(),
 		ID:           mb.b.factory.Metadata().NextUniqueID(),
 	})
+	excluded := tree.MergeDelete
+	if typ == fkInputScanFetchedVals {
+		excluded = tree.MergeInsert
+	}
+	scan = mb.b.factory.ConstructProject(
+		mb.b.factory.ConstructSelect(
+			scan,
+			memo.FiltersExpr{mb.b.factory.ConstructFiltersItem(
+				mb.b.factory.ConstructNe(
+					mb.b.factory.ConstructVariable(actionCol),
+					mb.b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(excluded)), types.Int),
+				),
+			)},
+		),
+		memo.EmptyProjectionsExpr,
+		outCols.ToSet(),
+	)
 	return scan, outCols, notNullOutCols
 }
 
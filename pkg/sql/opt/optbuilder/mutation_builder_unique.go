@@ -0,0 +1,149 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
+)
+
+// buildUniqueChecks populates mb.checks with queries that check the
+// DEFERRABLE UNIQUE constraints of the table against the new or updated rows.
+//
+// These constraints are not enforced by unique indexes, so they are checked
+// like the exclusion constraints (see buildExclusionChecks): each check is a
+// semi-join with the left side being a WithScan of the new values and the
+// right side being a scan of the same table. The join filters require that the
+// two rows are distinct and that the columns of the constraint are equal. For
+// example, the constraint v INT UNIQUE DEFERRABLE would require:
+//
+//   insert t
+//    ├── ...
+//    ├── input binding: &1
+//    └── f-k-checks
+//         └── f-k-checks-item: unique t_v_key
+//              └── semi-join (hash)
+//                   ├── with-scan &1
+//                   ├── scan t
+//                   └── filters
+//                        ├── k:5 != t.k:7
+//                        └── v:6 = t.v:8
+//
+// Rows with NULLs in the columns of the constraint never conflict.
+//
+// hasInsert is false if the statement only updates rows; in that case only
+// the constraints that involve updated columns are checked.
+//
+func (mb *mutationBuilder) buildUniqueChecks(hasInsert bool) {
+	for i, n := 0, mb.tab.DeferrableUniqueCount(); i < n; i++ {
+		if !hasInsert && !mb.uniqueColsUpdated(i) {
+			continue
+		}
+
+		if mb.withID == 0 {
+			mb.withID = mb.b.factory.Memo().NextWithID()
+		}
+		mb.checks = append(mb.checks, mb.buildUniqueCheck(i))
+	}
+}
+
+// buildUniqueCheck creates the check for the DEFERRABLE UNIQUE constraint with
+// the given ordinal. The key columns of the check are the new values of the
+// columns of the constraint, so that the check can be deferred.
+func (mb *mutationBuilder) buildUniqueCheck(uniqueOrdinal int) memo.FKChecksItem {
+	f := mb.b.factory
+	c := mb.tab.DeferrableUnique(uniqueOrdinal)
+	primary := mb.tab.Index(cat.PrimaryIndex)
+
+	// Scan the new values of the primary key columns and of the columns of the
+	// constraint.
+	h := fkCheckHelper{mb: mb}
+	var ords util.FastIntSet
+	for i, n := 0, primary.KeyColumnCount(); i < n; i++ {
+		ords.Add(primary.Column(i).Ordinal)
+	}
+	for _, ord := range c.ColumnOrdinals {
+		ords.Add(ord)
+	}
+	ords.ForEach(func(ord int) {
+		h.tabOrdinals = append(h.tabOrdinals, ord)
+	})
+	newRows, newCols, _ := h.makeFKInputScan(fkInputScanNewVals)
+	newColByOrd := make(map[int]opt.ColumnID, len(newCols))
+	for i, ord := range h.tabOrdinals {
+		newColByOrd[ord] = newCols[i]
+	}
+
+	// Build the scan of the existing rows.
+	tabMeta := mb.b.addTable(mb.tab, tree.NewUnqualifiedTableName(mb.tab.Name()))
+	scanScope := mb.b.buildScan(
+		tabMeta,
+		nil, /* ordinals */
+		&tree.IndexFlags{IgnoreForeignKeys: true},
+		noRowLocking,
+		excludeMutations,
+		mb.b.allocScope(),
+	)
+
+	// The two rows must be distinct:
+	//   (new.pk1 != existing.pk1) OR (new.pk2 != existing.pk2) ...
+	var filters memo.FiltersExpr
+	var distinct opt.ScalarExpr
+	for i, n := 0, primary.KeyColumnCount(); i < n; i++ {
+		ord := primary.Column(i).Ordinal
+		ne := f.ConstructNe(
+			f.ConstructVariable(newColByOrd[ord]),
+			f.ConstructVariable(tabMeta.MetaID.ColumnID(ord)),
+		)
+		if distinct == nil {
+			distinct = ne
+		} else {
+			distinct = f.ConstructOr(distinct, ne)
+		}
+	}
+	filters = append(filters, f.ConstructFiltersItem(distinct))
+
+	// All of the columns of the constraint must be equal.
+	keyCols := make(opt.ColList, len(c.ColumnOrdinals))
+	for i, ord := range c.ColumnOrdinals {
+		keyCols[i] = newColByOrd[ord]
+		filters = append(filters, f.ConstructFiltersItem(f.ConstructEq(
+			f.ConstructVariable(keyCols[i]),
+			f.ConstructVariable(tabMeta.MetaID.ColumnID(ord)),
+		)))
+	}
+
+	semiJoin := f.ConstructSemiJoin(newRows, scanScope.expr, filters, &memo.JoinPrivate{})
+
+	return f.ConstructFKChecksItem(semiJoin, &memo.FKChecksItemPrivate{
+		OriginTable:     mb.tabID,
+		ReferencedTable: tabMeta.MetaID,
+		Unique:          true,
+		UniqueOrdinal:   uniqueOrdinal,
+		KeyCols:         keyCols,
+		OpName:          mb.opName,
+	})
+}
+
+// uniqueColsUpdated returns true if any of the columns of the DEFERRABLE
+// UNIQUE constraint with the given ordinal are being updated (according to
+// updateOrds).
+func (mb *mutationBuilder) uniqueColsUpdated(uniqueOrdinal int) bool {
+	for _, ord := range mb.tab.DeferrableUnique(uniqueOrdinal).ColumnOrdinals {
+		if mb.updateOrds[ord] != -1 {
+			return true
+		}
+	}
+	return false
+}
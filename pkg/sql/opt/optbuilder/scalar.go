@@ -460,7 +460,7 @@ func (b *Builder) buildFunction(
 		}
 	}
 
-	def, err := f.Func.Resolve(b.semaCtx.SearchPath)
+	def, err := b.semaCtx.ResolveFunction(&f.Func)
 	if err != nil {
 		panic(err)
 	}
@@ -473,6 +473,19 @@ func (b *Builder) buildFunction(
 		panic(errors.AssertionFailedf("window function should have been replaced"))
 	}
 
+	if udf := f.ResolvedOverload().UDF; udf != nil {
+		// Unlike data sources, user-defined functions are not versioned, so
+		// there is no way to tell whether a memo that uses one is stale.
+		b.DisableMemoReuse = true
+		if inlined := b.inlineFunction(f, udf); inlined != nil {
+			texpr, err := tree.TypeCheck(inlined, b.semaCtx, f.ResolvedType())
+			if err != nil {
+				panic(err)
+			}
+			return b.buildScalar(texpr, inScope, outScope, outCol, colRefs)
+		}
+	}
+
 	args := make(memo.ScalarListExpr, len(f.Exprs))
 	for i, pexpr := range f.Exprs {
 		args[i] = b.buildScalar(pexpr.(tree.TypedExpr), inScope, nil, nil, colRefs)
@@ -493,6 +506,45 @@ func (b *Builder) buildFunction(
 	return b.finishBuildScalar(f, out, inScope, outScope, outCol)
 }
 
+// inlineFunction returns the expression computed by a call of a
+// user-defined function whose body is a simple scalar expression, with the
+// references to the arguments of the function replaced by the arguments of
+// the call. It returns nil if the call cannot be inlined, which is the case
+// if an argument which is not a constant or a column is referenced multiple
+// times, since inlining would evaluate it multiple times.
+func (b *Builder) inlineFunction(f *tree.FuncExpr, udf *tree.UserDefinedFunction) tree.Expr {
+	if udf.InlineExpr == nil {
+		return nil
+	}
+	uses := make([]int, len(f.Exprs))
+	_, _ = tree.SimpleVisit(udf.InlineExpr, func(expr tree.Expr) (bool, tree.Expr, error) {
+		if p, ok := expr.(*tree.Placeholder); ok {
+			uses[p.Idx]++
+		}
+		return true, expr, nil
+	})
+	for i, n := range uses {
+		if n <= 1 {
+			continue
+		}
+		switch f.Exprs[i].(type) {
+		case tree.Datum, *scopeColumn, *tree.Placeholder:
+		default:
+			return nil
+		}
+	}
+	expr, err := tree.SimpleVisit(udf.InlineExpr, func(expr tree.Expr) (bool, tree.Expr, error) {
+		if p, ok := expr.(*tree.Placeholder); ok {
+			return false, f.Exprs[p.Idx], nil
+		}
+		return true, expr, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return &tree.CastExpr{Expr: expr, Type: f.ResolvedType(), SyntaxMode: tree.CastShort}
+}
+
 // buildRangeCond builds a RANGE clause as a simpler expression. Examples:
 // x BETWEEN a AND b                ->  x >= a AND x <= b
 // x NOT BETWEEN a AND b            ->  NOT (x >= a AND x <= b)
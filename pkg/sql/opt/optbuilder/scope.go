@@ -897,7 +897,7 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 		return false, colI.(*scopeColumn)
 
 	case *tree.FuncExpr:
-		def, err := t.Func.Resolve(s.builder.semaCtx.SearchPath)
+		def, err := s.builder.semaCtx.ResolveFunction(&t.Func)
 		if err != nil {
 			panic(err)
 		}
@@ -907,6 +907,12 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 			break
 		}
 
+		if t.AggType == tree.OrderedSetAgg {
+			expr = s.replaceOrderedSetAggregate(t, def)
+			break
+		}
+		checkWithinGroupRequired(t, def)
+
 		if isAggregate(def) && t.WindowDef == nil {
 			expr = s.replaceAggregate(t, def)
 			break
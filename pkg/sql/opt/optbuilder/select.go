@@ -15,6 +15,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -459,14 +460,18 @@ func (b *Builder) buildScan(
 		return ordinals[i]
 	}
 
-	var tabColIDs opt.ColSet
+	var tabColIDs, virtualColIDs opt.ColSet
 	outScope = inScope.push()
 	outScope.cols = make([]scopeColumn, 0, colCount)
 	for i := 0; i < colCount; i++ {
 		ord := getOrdinal(i)
 		col := tab.Column(ord)
 		colID := tabID.ColumnID(ord)
-		tabColIDs.Add(colID)
+		if col.IsVirtual() {
+			virtualColIDs.Add(colID)
+		} else {
+			tabColIDs.Add(colID)
+		}
 		name := col.ColName()
 		isMutation := cat.IsMutationColumn(tab, ord)
 		outScope.cols = append(outScope.cols, scopeColumn{
@@ -526,7 +531,11 @@ func (b *Builder) buildScan(
 		b.addCheckConstraintsForTable(tabMeta)
 		b.addComputedColsForTable(tabMeta)
 
-		outScope.expr = b.factory.ConstructScan(&private)
+		if virtualColIDs.Empty() {
+			outScope.expr = b.factory.ConstructScan(&private)
+		} else {
+			outScope.expr = b.buildVirtualColumns(tabMeta, &private, virtualColIDs)
+		}
 
 		if b.trackViewDeps {
 			dep := opt.ViewDep{DataSource: tab}
@@ -543,6 +552,31 @@ func (b *Builder) buildScan(
 	return outScope
 }
 
+// buildVirtualColumns constructs a Scan operator for the given private, wrapped
+// in a Project operator that computes the given virtual columns. Virtual
+// columns are not stored in the primary index, so they cannot be produced by
+// the scan itself. Any columns referenced by the virtual column expressions are
+// added to the scan, but are not passed through the projection unless they
+// were already part of the scan.
+func (b *Builder) buildVirtualColumns(
+	tabMeta *opt.TableMeta, private *memo.ScanPrivate, virtualColIDs opt.ColSet,
+) memo.RelExpr {
+	passthrough := private.Cols.Copy()
+	projections := make(memo.ProjectionsExpr, 0, virtualColIDs.Len())
+	virtualColIDs.ForEach(func(colID opt.ColumnID) {
+		expr, ok := tabMeta.ComputedCols[colID]
+		if !ok {
+			panic(errors.AssertionFailedf("virtual column %d has no computed expression", colID))
+		}
+		var p props.Shared
+		memo.BuildSharedProps(expr, &p)
+		private.Cols.UnionWith(p.OuterCols)
+		projections = append(projections, b.factory.ConstructProjectionsItem(expr, colID))
+	})
+	scan := b.factory.ConstructScan(private)
+	return b.factory.ConstructProject(scan, projections, passthrough)
+}
+
 // addCheckConstraintsForTable extracts filters from the check constraints that
 // apply to the table and adds them to the table metadata (see
 // TableMeta.Constraints). To do this, the scalar expressions of the check
@@ -1002,7 +1036,7 @@ func (b *Builder) buildSelectClause(
 				projectionsScope.distinctOnCols,
 				outScope,
 				false, /* nullsAreDistinct */
-				false, /* errorOnDup */
+				"",    /* errorOnDup */
 			)
 		}
 	}
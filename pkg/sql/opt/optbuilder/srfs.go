@@ -94,7 +94,7 @@ func (b *Builder) buildZip(exprs tree.Exprs, inScope *scope) (outScope *scope) {
 
 		var def *tree.FunctionDefinition
 		if funcExpr, ok := texpr.(*tree.FuncExpr); ok {
-			if def, err = funcExpr.Func.Resolve(b.semaCtx.SearchPath); err != nil {
+			if def, err = b.semaCtx.ResolveFunction(&funcExpr.Func); err != nil {
 				panic(err)
 			}
 		}
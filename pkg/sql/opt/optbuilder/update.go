@@ -323,6 +323,8 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 	mb.addCheckConstraintCols()
 
 	mb.buildFKChecksForUpdate()
+	mb.buildExclusionChecks(false /* hasInsert */)
+	mb.buildUniqueChecks(false /* hasInsert */)
 
 	private := mb.makeMutationPrivate(returning != nil)
 	for _, col := range mb.extraAccessibleCols {
@@ -533,6 +533,11 @@ func (b *Builder) resolveTableForMutation(
 			"%q does not resolve to a table", tree.ErrString(n)))
 	}
 
+	if tab.IsMaterializedView() {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"cannot mutate materialized view %q", tab.Name()))
+	}
+
 	if outerAlias != nil {
 		alias = *outerAlias
 	}
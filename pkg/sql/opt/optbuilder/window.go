@@ -526,6 +526,12 @@ func (b *Builder) overrideDefaultNullValue(agg aggregateInfo) (opt.ScalarExpr, b
 	switch agg.def.Name {
 	case "count", "count_rows":
 		return b.factory.ConstructConst(tree.NewDInt(0)), true
+	case "rank_impl", "dense_rank_impl":
+		return b.factory.ConstructConst(tree.NewDInt(1)), true
+	case "percent_rank_impl":
+		return b.factory.ConstructConst(tree.NewDFloat(0)), true
+	case "cume_dist_impl":
+		return b.factory.ConstructConst(tree.NewDFloat(1)), true
 	default:
 		return nil, false
 	}
@@ -0,0 +1,211 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// orderedSetAggregates maps the name of each ordered-set aggregate to the name
+// of the internal aggregate that implements it. An ordered-set aggregate such
+// as
+//
+//   percentile_disc(0.5) WITHIN GROUP (ORDER BY x)
+//
+// is rewritten to
+//
+//   percentile_disc_impl(0.5, x) WITHIN GROUP (ORDER BY x)
+//
+// The internal aggregate is ordering sensitive (see isOrderingSensitive), so it
+// is built as an ordered aggregation and receives the rows of each group in
+// the WITHIN GROUP order.
+var orderedSetAggregates = map[string]struct {
+	implName string
+	// numArgs is the number of direct arguments of the aggregate.
+	numArgs int
+}{
+	"percentile_disc": {implName: "percentile_disc_impl", numArgs: 1},
+	"percentile_cont": {implName: "percentile_cont_impl", numArgs: 1},
+	"mode":            {implName: "mode_impl", numArgs: 0},
+}
+
+// hypotheticalSetAggregates maps the name of each hypothetical-set aggregate
+// to the name of the internal aggregate that implements it. A hypothetical-set
+// aggregate such as
+//
+//   rank(a, b) WITHIN GROUP (ORDER BY x, y)
+//
+// computes the rank that the hypothetical row (a, b) would have if it was
+// added to the group. It is rewritten to an aggregate over a boolean
+// expression that is true for the rows that sort before the hypothetical row:
+//
+//   rank_impl((x < a OR x IS NOT DISTINCT FROM a AND y < b) IS TRUE)
+//
+// (the actual expressions also take the NULL ordering into account). The
+// dense_rank variant additionally receives the WITHIN GROUP ordering column (or
+// the tuple of ordering columns if there are several), so that it can count
+// the distinct keys of the preceding rows. None of the internal aggregates is
+// ordering sensitive, so the WITHIN GROUP ordering is dropped.
+var hypotheticalSetAggregates = map[string]string{
+	"rank":         "rank_impl",
+	"dense_rank":   "dense_rank_impl",
+	"percent_rank": "percent_rank_impl",
+	"cume_dist":    "cume_dist_impl",
+}
+
+// checkWithinGroupRequired panics if the given function is an ordered-set
+// aggregate that is used without a WITHIN GROUP clause.
+func checkWithinGroupRequired(f *tree.FuncExpr, def *tree.FunctionDefinition) {
+	if _, ok := orderedSetAggregates[def.Name]; ok && f.AggType != tree.OrderedSetAgg {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"WITHIN GROUP is required for ordered-set aggregate %s", def.Name))
+	}
+}
+
+// replaceOrderedSetAggregate rewrites an aggregate with a WITHIN GROUP clause
+// to the internal aggregate that implements it (see orderedSetAggregates and
+// hypotheticalSetAggregates), and replaces the result like any other
+// aggregate.
+func (s *scope) replaceOrderedSetAggregate(
+	f *tree.FuncExpr, def *tree.FunctionDefinition,
+) tree.Expr {
+	if f.WindowDef != nil {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"OVER is not supported for ordered-set aggregate %s", def.Name))
+	}
+	for _, o := range f.OrderBy {
+		if o.OrderType != tree.OrderByColumn {
+			panic(pgerror.Newf(pgcode.Syntax,
+				"WITHIN GROUP for %s must order by expressions", def.Name))
+		}
+	}
+
+	rewritten := *f
+	if agg, ok := orderedSetAggregates[def.Name]; ok {
+		if len(f.Exprs) != agg.numArgs {
+			panic(pgerror.Newf(pgcode.UndefinedFunction,
+				"ordered-set aggregate %s requires %d argument(s)", def.Name, agg.numArgs))
+		}
+		if len(f.OrderBy) != 1 {
+			panic(pgerror.Newf(pgcode.UndefinedFunction,
+				"ordered-set aggregate %s requires exactly one WITHIN GROUP ordering column", def.Name))
+		}
+		rewritten.Exprs = make(tree.Exprs, 0, len(f.Exprs)+1)
+		for _, e := range f.Exprs {
+			// The fraction of the percentile aggregates is a FLOAT or a FLOAT[]. An
+			// array of numeric constants would be typed as a DECIMAL[], so it is cast
+			// explicitly, like Postgres implicitly casts it.
+			if arr, ok := e.(*tree.Array); ok {
+				e = &tree.CastExpr{Expr: arr, Type: types.MakeArray(types.Float), SyntaxMode: tree.CastShort}
+			}
+			rewritten.Exprs = append(rewritten.Exprs, e)
+		}
+		rewritten.Exprs = append(rewritten.Exprs, f.OrderBy[0].Expr)
+		return s.replaceInternalAggregate(&rewritten, agg.implName)
+	}
+
+	if implName, ok := hypotheticalSetAggregates[def.Name]; ok {
+		if len(f.Exprs) != len(f.OrderBy) {
+			panic(pgerror.Newf(pgcode.UndefinedFunction,
+				"hypothetical-set aggregate %s requires as many arguments as WITHIN GROUP ordering columns",
+				def.Name,
+			))
+		}
+		precedes, peer := buildHypotheticalRowComparison(f.Exprs, f.OrderBy)
+		switch def.Name {
+		case "dense_rank":
+			// A single ordering column is passed as is rather than as a tuple, so
+			// that the aggregate can be executed by the vectorized engine.
+			var key tree.Expr = f.OrderBy[0].Expr
+			if len(f.OrderBy) > 1 {
+				tuple := &tree.Tuple{Exprs: make(tree.Exprs, len(f.OrderBy))}
+				for i := range f.OrderBy {
+					tuple.Exprs[i] = f.OrderBy[i].Expr
+				}
+				key = tuple
+			}
+			rewritten.Exprs = tree.Exprs{isTrue(precedes), key}
+		case "cume_dist":
+			rewritten.Exprs = tree.Exprs{isTrue(&tree.OrExpr{Left: precedes, Right: peer})}
+		default:
+			rewritten.Exprs = tree.Exprs{isTrue(precedes)}
+		}
+		rewritten.OrderBy = nil
+		return s.replaceInternalAggregate(&rewritten, implName)
+	}
+
+	panic(pgerror.Newf(pgcode.WrongObjectType,
+		"%s is not an ordered-set aggregate, so it cannot have WITHIN GROUP", def.Name))
+}
+
+// replaceInternalAggregate replaces the function of the given WITHIN GROUP
+// aggregate with the internal aggregate with the given name, and replaces the
+// result like any other aggregate.
+func (s *scope) replaceInternalAggregate(f *tree.FuncExpr, name string) tree.Expr {
+	f.Func = tree.WrapFunction(name)
+	def, err := f.Func.Resolve(s.builder.semaCtx.SearchPath)
+	if err != nil {
+		panic(err)
+	}
+	return s.replaceAggregate(f, def)
+}
+
+// buildHypotheticalRowComparison returns two boolean expressions for a
+// hypothetical-set aggregate: the first one is true for the rows which sort
+// before the hypothetical row formed by args according to the WITHIN GROUP
+// ordering, and the second one is true for the rows which are peers of the
+// hypothetical row.
+func buildHypotheticalRowComparison(
+	args tree.Exprs, orderBy tree.OrderBy,
+) (precedes, peer tree.Expr) {
+	for i := len(orderBy) - 1; i >= 0; i-- {
+		col, arg := orderBy[i].Expr, args[i]
+		colPeer := &tree.ComparisonExpr{Operator: tree.IsNotDistinctFrom, Left: col, Right: arg}
+
+		// NULLs sort before all other values in ascending order, and after them
+		// in descending order.
+		var before tree.Expr
+		if orderBy[i].Direction == tree.Descending {
+			before = &tree.OrExpr{
+				Left:  &tree.AndExpr{Left: isNull(arg), Right: isNotNull(col)},
+				Right: &tree.ComparisonExpr{Operator: tree.GT, Left: col, Right: arg},
+			}
+		} else {
+			before = &tree.OrExpr{
+				Left:  &tree.AndExpr{Left: isNull(col), Right: isNotNull(arg)},
+				Right: &tree.ComparisonExpr{Operator: tree.LT, Left: col, Right: arg},
+			}
+		}
+
+		if precedes == nil {
+			precedes, peer = before, colPeer
+		} else {
+			precedes = &tree.OrExpr{Left: before, Right: &tree.AndExpr{Left: colPeer, Right: precedes}}
+			peer = &tree.AndExpr{Left: colPeer, Right: peer}
+		}
+	}
+	return precedes, peer
+}
+
+func isNull(e tree.Expr) tree.Expr {
+	return &tree.ComparisonExpr{Operator: tree.IsNotDistinctFrom, Left: e, Right: tree.DNull}
+}
+
+func isNotNull(e tree.Expr) tree.Expr {
+	return &tree.ComparisonExpr{Operator: tree.IsDistinctFrom, Left: e, Right: tree.DNull}
+}
+
+func isTrue(e tree.Expr) tree.Expr {
+	return &tree.ComparisonExpr{Operator: tree.IsNotDistinctFrom, Left: e, Right: tree.DBoolTrue}
+}
@@ -187,6 +187,11 @@ func init() {
 		buildChildReqOrdering: mutationBuildChildReqOrdering,
 		buildProvidedOrdering: mutationBuildProvided,
 	}
+	funcMap[opt.MergeOp] = funcs{
+		canProvideOrdering:    mutationCanProvideOrdering,
+		buildChildReqOrdering: mutationBuildChildReqOrdering,
+		buildProvidedOrdering: mutationBuildProvided,
+	}
 	funcMap[opt.ExplainOp] = funcs{
 		canProvideOrdering:    canNeverProvideOrdering,
 		buildChildReqOrdering: explainBuildChildReqOrdering,
@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
 )
@@ -143,6 +144,23 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 				Constraint: serializeTableDefExpr(def.Expr),
 				Validated:  validatedCheckConstraint(def),
 			})
+
+		case *tree.ExcludeConstraintTableDef:
+			c := cat.ExclusionConstraint{
+				Name:      string(def.Name),
+				Elements:  make([]cat.ExclusionElement, len(def.Elems)),
+				Validated: true,
+			}
+			for i := range def.Elems {
+				c.Elements[i] = cat.ExclusionElement{
+					Expr:     serializeTableDefExpr(def.Elems[i].Expr),
+					Operator: def.Elems[i].Operator,
+				}
+			}
+			if def.Where != nil {
+				c.Predicate = serializeTableDefExpr(def.Where)
+			}
+			tab.Exclusions = append(tab.Exclusions, c)
 		}
 	}
 
@@ -150,7 +168,9 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 	for _, def := range stmt.Defs {
 		switch def := def.(type) {
 		case *tree.UniqueConstraintTableDef:
-			if !def.PrimaryKey {
+			if def.Deferrability != tree.NotDeferrable {
+				tab.addDeferrableUnique(&def.IndexTableDef, def.Deferrability)
+			} else if !def.PrimaryKey {
 				tab.addIndex(&def.IndexTableDef, uniqueIndex)
 			}
 
@@ -162,17 +182,28 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 
 		case *tree.ColumnTableDef:
 			if def.Unique {
-				tab.addIndex(
-					&tree.IndexTableDef{
-						Name:    tree.Name(fmt.Sprintf("%s_%s_key", stmt.Table.TableName, def.Name)),
-						Columns: tree.IndexElemList{{Column: def.Name}},
-					},
-					uniqueIndex,
-				)
+				indexDef := &tree.IndexTableDef{
+					Name:    tree.Name(fmt.Sprintf("%s_%s_key", stmt.Table.TableName, def.Name)),
+					Columns: tree.IndexElemList{{Column: def.Name}},
+				}
+				if def.UniqueDeferrability != tree.NotDeferrable {
+					tab.addDeferrableUnique(indexDef, def.UniqueDeferrability)
+				} else {
+					tab.addIndex(indexDef, uniqueIndex)
+				}
 			}
 		}
 	}
 
+	// Add the indexes that back the exclusion constraints, after all the other
+	// indexes (otherwise the constraints could add unnecessary indexes).
+	for _, def := range stmt.Defs {
+		switch def := def.(type) {
+		case *tree.ExcludeConstraintTableDef:
+			tab.addExclusionIndex(def)
+		}
+	}
+
 	// If there are columns missing from explicit family definitions, add them
 	// to family 0 (ensure that one exists).
 	if len(tab.Families) == 0 {
@@ -180,6 +211,10 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 	}
 OuterLoop:
 	for colOrd, col := range tab.Columns {
+		if col.Virtual {
+			// Virtual columns are not stored, so they are not part of any family.
+			continue
+		}
 		for _, fam := range tab.Families {
 			for _, famCol := range fam.Columns {
 				if col.Name == string(famCol.ColName()) {
@@ -372,11 +407,82 @@ func (tc *Catalog) resolveFK(tab *Table, d *tree.ForeignKeyConstraintTableDef) {
 		matchMethod:              d.Match,
 		deleteAction:             d.Actions.Delete,
 		updateAction:             d.Actions.Update,
+		deferrability:            d.Deferrability,
 	}
 	tab.outboundFKs = append(tab.outboundFKs, fk)
 	targetTable.inboundFKs = append(targetTable.inboundFKs, fk)
 }
 
+// addExclusionIndex adds the non-unique index that is used to look up the
+// conflicting rows of the given exclusion constraint, mirroring the index
+// created by CREATE TABLE: its key columns are the columns compared with =,
+// followed by the lower bound column of the first range. No index is added if
+// an existing index already has these columns as a prefix.
+func (tt *Table) addExclusionIndex(def *tree.ExcludeConstraintTableDef) {
+	var cols []int
+	addColumn := func(expr tree.Expr) {
+		name, ok := expr.(*tree.UnresolvedName)
+		if !ok || name.NumParts != 1 {
+			return
+		}
+		for i, col := range tt.Columns {
+			if col.Name == name.Parts[0] {
+				for _, c := range cols {
+					if c == i {
+						return
+					}
+				}
+				cols = append(cols, i)
+				return
+			}
+		}
+	}
+	for _, elem := range def.Elems {
+		if elem.Operator == tree.EQ {
+			addColumn(elem.Expr)
+		}
+	}
+	for _, elem := range def.Elems {
+		r, isRange, err := sqlbase.MakeExclusionRange(elem.Expr)
+		if err != nil {
+			panic(err)
+		}
+		if isRange {
+			addColumn(r.Lower)
+			break
+		}
+	}
+	if len(cols) == 0 {
+		return
+	}
+
+	for _, idx := range tt.Indexes {
+		if idx.LaxKeyColumnCount() < len(cols) {
+			continue
+		}
+		found := true
+		for i := range cols {
+			if idx.Column(i).Ordinal != cols[i] {
+				found = false
+				break
+			}
+		}
+		if found {
+			return
+		}
+	}
+
+	idx := tree.IndexTableDef{
+		Name:    tree.Name(fmt.Sprintf("%s_idx", def.Name)),
+		Columns: make(tree.IndexElemList, len(cols)),
+	}
+	for i, c := range cols {
+		idx.Columns[i].Column = tt.Columns[c].ColName()
+		idx.Columns[i].Direction = tree.Ascending
+	}
+	tt.addIndex(&idx, nonUniqueIndex)
+}
+
 func (tt *Table) addColumn(def *tree.ColumnTableDef) {
 	nullable := !def.PrimaryKey.IsPrimaryKey && def.Nullable.Nullability != tree.NotNull
 	col := &Column{
@@ -404,6 +510,7 @@ func (tt *Table) addColumn(def *tree.ColumnTableDef) {
 	if def.Computed.Expr != nil {
 		s := serializeTableDefExpr(def.Computed.Expr)
 		col.ComputedExpr = &s
+		col.Virtual = def.Computed.Virtual
 	}
 
 	tt.Columns = append(tt.Columns, col)
@@ -447,9 +554,10 @@ func (tt *Table) addIndex(def *tree.IndexTableDef, typ indexType) *Index {
 		for _, c := range idx.Columns {
 			pkOrdinals.Add(c.Ordinal)
 		}
-		// Add the rest of the columns in the table.
+		// Add the rest of the columns in the table, except for virtual columns,
+		// which are not stored in the primary index.
 		for i, n := 0, tt.DeletableColumnCount(); i < n; i++ {
-			if !pkOrdinals.Contains(i) {
+			if !pkOrdinals.Contains(i) && !tt.Columns[i].Virtual {
 				idx.addColumnByOrdinal(tt, i, tree.Ascending, nonKeyCol)
 			}
 		}
@@ -515,6 +623,23 @@ func (tt *Table) addIndex(def *tree.IndexTableDef, typ indexType) *Index {
 	return idx
 }
 
+// addDeferrableUnique adds a DEFERRABLE UNIQUE constraint, and the non-unique
+// index that backs it, the same way CREATE TABLE does.
+func (tt *Table) addDeferrableUnique(
+	def *tree.IndexTableDef, deferrability tree.ConstraintDeferrability,
+) {
+	idx := tt.addIndex(def, nonUniqueIndex)
+	c := cat.DeferrableUniqueConstraint{
+		Name:           idx.IdxName,
+		ColumnOrdinals: make([]int, len(def.Columns)),
+		Deferrability:  deferrability,
+	}
+	for i := range def.Columns {
+		c.ColumnOrdinals[i] = tt.FindOrdinal(string(def.Columns[i].Column))
+	}
+	tt.DeferrableUniques = append(tt.DeferrableUniques, c)
+}
+
 func (tt *Table) makeIndexName(defName tree.Name, typ indexType) string {
 	name := string(defName)
 	if name == "" {
@@ -532,10 +532,13 @@ type Table struct {
 	Indexes    []*Index
 	Stats      TableStats
 	Checks     []cat.CheckConstraint
+	Exclusions []cat.ExclusionConstraint
 	Families   []*Family
 	IsVirtual  bool
 	Catalog    cat.Catalog
 
+	DeferrableUniques []cat.DeferrableUniqueConstraint
+
 	// If Revoked is true, then the user has had privileges on the table revoked.
 	Revoked bool
 
@@ -594,11 +597,21 @@ func (tt *Table) IsVirtualTable() bool {
 	return tt.IsVirtual
 }
 
+// IsMaterializedView is part of the cat.Table interface.
+func (tt *Table) IsMaterializedView() bool {
+	return false
+}
+
 // IsInterleaved is part of the cat.Table interface.
 func (tt *Table) IsInterleaved() bool {
 	return false
 }
 
+// HasRowTriggers is part of the cat.Table interface.
+func (tt *Table) HasRowTriggers() bool {
+	return false
+}
+
 // ColumnCount is part of the cat.Table interface.
 func (tt *Table) ColumnCount() int {
 	return len(tt.Columns) - tt.writeOnlyColCount - tt.deleteOnlyColCount
@@ -659,6 +672,26 @@ func (tt *Table) Check(i int) cat.CheckConstraint {
 	return tt.Checks[i]
 }
 
+// ExclusionCount is part of the cat.Table interface.
+func (tt *Table) ExclusionCount() int {
+	return len(tt.Exclusions)
+}
+
+// Exclusion is part of the cat.Table interface.
+func (tt *Table) Exclusion(i int) cat.ExclusionConstraint {
+	return tt.Exclusions[i]
+}
+
+// DeferrableUniqueCount is part of the cat.Table interface.
+func (tt *Table) DeferrableUniqueCount() int {
+	return len(tt.DeferrableUniques)
+}
+
+// DeferrableUnique is part of the cat.Table interface.
+func (tt *Table) DeferrableUnique(i int) cat.DeferrableUniqueConstraint {
+	return tt.DeferrableUniques[i]
+}
+
 // FamilyCount is part of the cat.Table interface.
 func (tt *Table) FamilyCount() int {
 	return len(tt.Families)
@@ -869,6 +902,7 @@ type Column struct {
 	ColType      types.T
 	DefaultExpr  *string
 	ComputedExpr *string
+	Virtual      bool
 }
 
 var _ cat.Column = &Column{}
@@ -945,6 +979,11 @@ func (tc *Column) ComputedExprStr() string {
 	return *tc.ComputedExpr
 }
 
+// IsVirtual is part of the cat.Column interface.
+func (tc *Column) IsVirtual() bool {
+	return tc.Virtual
+}
+
 // TableStat implements the cat.TableStatistic interface for testing purposes.
 type TableStat struct {
 	js stats.JSONStatistic
@@ -1041,10 +1080,11 @@ type ForeignKeyConstraint struct {
 	originColumnOrdinals     []int
 	referencedColumnOrdinals []int
 
-	validated    bool
-	matchMethod  tree.CompositeKeyMatchMethod
-	deleteAction tree.ReferenceAction
-	updateAction tree.ReferenceAction
+	validated     bool
+	matchMethod   tree.CompositeKeyMatchMethod
+	deleteAction  tree.ReferenceAction
+	updateAction  tree.ReferenceAction
+	deferrability tree.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &ForeignKeyConstraint{}
@@ -1112,6 +1152,11 @@ func (fk *ForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction {
 	return fk.updateAction
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *ForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return fk.deferrability
+}
+
 // Sequence implements the cat.Sequence interface for testing purposes.
 type Sequence struct {
 	SeqID      cat.StableID
@@ -912,7 +912,10 @@ func (c *CustomFuncs) initIdxConstraintForIndex(
 	}
 
 	// Generate index constraints.
-	ic.Init(requiredFilters, optionalFilters, columns, notNullCols, isInverted, c.e.evalCtx, c.e.f)
+	ic.Init(
+		requiredFilters, optionalFilters, columns, notNullCols, md.TableMeta(tabID).ComputedCols,
+		isInverted, c.e.evalCtx, c.e.f,
+	)
 	return ic
 }
 
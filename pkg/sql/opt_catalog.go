@@ -310,8 +310,9 @@ func (oc *optCatalog) dataSourceForDesc(
 	desc *sqlbase.ImmutableTableDescriptor,
 	name *cat.DataSourceName,
 ) (cat.DataSource, error) {
-	if desc.IsTable() {
-		// Tables require invalidation logic for cached wrappers.
+	if desc.IsTable() || desc.MaterializedView() {
+		// Tables require invalidation logic for cached wrappers. Materialized
+		// views store their data like tables and are planned as such.
 		return oc.dataSourceForTable(ctx, flags, desc, name)
 	}
 
@@ -537,6 +538,9 @@ type optTable struct {
 	outboundFKs []optForeignKeyConstraint
 	inboundFKs  []optForeignKeyConstraint
 
+	// deferrableUniques are the DEFERRABLE UNIQUE constraints of the table.
+	deferrableUniques []cat.DeferrableUniqueConstraint
+
 	// colMap is a mapping from unique ColumnID to column ordinal within the
 	// table. This is a common lookup that needs to be fast.
 	colMap map[sqlbase.ColumnID]int
@@ -598,6 +602,7 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability:     fk.Deferrability(),
 		})
 	}
 	for i := range ot.desc.InboundFKs {
@@ -612,9 +617,32 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability:     fk.Deferrability(),
 		})
 	}
 
+	// The DEFERRABLE UNIQUE constraints that are being added are checked as
+	// soon as their columns are public, like the unique indexes that are being
+	// added.
+	for _, idx := range ot.desc.AllNonDropIndexes() {
+		if !idx.UniqueDeferrable {
+			continue
+		}
+		c := cat.DeferrableUniqueConstraint{
+			Name:           idx.Name,
+			ColumnOrdinals: make([]int, len(idx.ColumnIDs)),
+			Deferrability:  idx.UniqueDeferrability(),
+		}
+		public := true
+		for i, id := range idx.ColumnIDs {
+			c.ColumnOrdinals[i] = ot.colMap[id]
+			public = public && c.ColumnOrdinals[i] < ot.ColumnCount()
+		}
+		if public {
+			ot.deferrableUniques = append(ot.deferrableUniques, c)
+		}
+	}
+
 	ot.primaryFamily.init(ot, &desc.Families[0])
 	ot.families = make([]optFamily, len(desc.Families)-1)
 	for i := range ot.families {
@@ -721,11 +749,21 @@ func (ot *optTable) IsVirtualTable() bool {
 	return false
 }
 
+// IsMaterializedView is part of the cat.Table interface.
+func (ot *optTable) IsMaterializedView() bool {
+	return ot.desc.MaterializedView()
+}
+
 // IsInterleaved is part of the cat.Table interface.
 func (ot *optTable) IsInterleaved() bool {
 	return ot.desc.IsInterleaved()
 }
 
+// HasRowTriggers is part of the cat.Table interface.
+func (ot *optTable) HasRowTriggers() bool {
+	return len(ot.desc.Triggers) > 0
+}
+
 // ColumnCount is part of the cat.Table interface.
 func (ot *optTable) ColumnCount() int {
 	return len(ot.desc.Columns)
@@ -793,6 +831,39 @@ func (ot *optTable) Check(i int) cat.CheckConstraint {
 	}
 }
 
+// ExclusionCount is part of the cat.Table interface.
+func (ot *optTable) ExclusionCount() int {
+	return len(ot.desc.ActiveExclusions())
+}
+
+// Exclusion is part of the cat.Table interface.
+func (ot *optTable) Exclusion(i int) cat.ExclusionConstraint {
+	c := &ot.desc.ActiveExclusions()[i]
+	res := cat.ExclusionConstraint{
+		Name:      c.Name,
+		Elements:  make([]cat.ExclusionElement, len(c.Elements)),
+		Predicate: c.Predicate,
+		Validated: c.Validity == sqlbase.ConstraintValidity_Validated,
+	}
+	for j := range c.Elements {
+		res.Elements[j] = cat.ExclusionElement{
+			Expr:     c.Elements[j].Expr,
+			Operator: c.Elements[j].Operator.ComparisonOperator(),
+		}
+	}
+	return res
+}
+
+// DeferrableUniqueCount is part of the cat.Table interface.
+func (ot *optTable) DeferrableUniqueCount() int {
+	return len(ot.deferrableUniques)
+}
+
+// DeferrableUnique is part of the cat.Table interface.
+func (ot *optTable) DeferrableUnique(i int) cat.DeferrableUniqueConstraint {
+	return ot.deferrableUniques[i]
+}
+
 // FamilyCount is part of the cat.Table interface.
 func (ot *optTable) FamilyCount() int {
 	return 1 + len(ot.families)
@@ -866,21 +937,22 @@ func (oi *optIndex) init(
 	oi.zone = zone
 	oi.indexOrdinal = indexOrdinal
 	if desc == &tab.desc.PrimaryIndex {
-		// Although the primary index contains all columns in the table, the index
-		// descriptor does not contain columns that are not explicitly part of the
-		// primary key. Retrieve those columns from the table descriptor.
+		// Although the primary index contains all stored columns in the table, the
+		// index descriptor does not contain columns that are not explicitly part of
+		// the primary key. Retrieve those columns from the table descriptor.
+		// Virtual columns are not stored in the primary index.
 		oi.storedCols = make([]sqlbase.ColumnID, 0, tab.DeletableColumnCount()-len(desc.ColumnIDs))
 		var pkCols util.FastIntSet
 		for i := range desc.ColumnIDs {
 			pkCols.Add(int(desc.ColumnIDs[i]))
 		}
 		for i, n := 0, tab.DeletableColumnCount(); i < n; i++ {
-			id := tab.Column(i).ColID()
-			if !pkCols.Contains(int(id)) {
-				oi.storedCols = append(oi.storedCols, sqlbase.ColumnID(id))
+			col := tab.Column(i)
+			if !pkCols.Contains(int(col.ColID())) && !col.IsVirtual() {
+				oi.storedCols = append(oi.storedCols, sqlbase.ColumnID(col.ColID()))
 			}
 		}
-		oi.numCols = tab.DeletableColumnCount()
+		oi.numCols = len(desc.ColumnIDs) + len(oi.storedCols)
 	} else {
 		oi.storedCols = desc.StoreColumnIDs
 		oi.numCols = len(desc.ColumnIDs) + len(desc.ExtraColumnIDs) + len(desc.StoreColumnIDs)
@@ -1158,10 +1230,11 @@ type optForeignKeyConstraint struct {
 	referencedTable   cat.StableID
 	referencedColumns []sqlbase.ColumnID
 
-	validity     sqlbase.ConstraintValidity
-	match        sqlbase.ForeignKeyReference_Match
-	deleteAction sqlbase.ForeignKeyReference_Action
-	updateAction sqlbase.ForeignKeyReference_Action
+	validity      sqlbase.ConstraintValidity
+	match         sqlbase.ForeignKeyReference_Match
+	deleteAction  sqlbase.ForeignKeyReference_Action
+	updateAction  sqlbase.ForeignKeyReference_Action
+	deferrability tree.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -1233,6 +1306,11 @@ func (fk *optForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction
 	return sqlbase.ForeignKeyReferenceActionType[fk.updateAction]
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return fk.deferrability
+}
+
 // optVirtualTable is similar to optTable but is used with virtual tables.
 type optVirtualTable struct {
 	desc *sqlbase.ImmutableTableDescriptor
@@ -1346,11 +1424,21 @@ func (ot *optVirtualTable) IsVirtualTable() bool {
 	return true
 }
 
+// IsMaterializedView is part of the cat.Table interface.
+func (ot *optVirtualTable) IsMaterializedView() bool {
+	return false
+}
+
 // IsInterleaved is part of the cat.Table interface.
 func (ot *optVirtualTable) IsInterleaved() bool {
 	return ot.desc.IsInterleaved()
 }
 
+// HasRowTriggers is part of the cat.Table interface.
+func (ot *optVirtualTable) HasRowTriggers() bool {
+	return false
+}
+
 // ColumnCount is part of the cat.Table interface.
 func (ot *optVirtualTable) ColumnCount() int {
 	// Virtual tables expose an extra (bogus) PK column.
@@ -1423,6 +1511,26 @@ func (ot *optVirtualTable) Check(i int) cat.CheckConstraint {
 	}
 }
 
+// ExclusionCount is part of the cat.Table interface.
+func (ot *optVirtualTable) ExclusionCount() int {
+	return 0
+}
+
+// Exclusion is part of the cat.Table interface.
+func (ot *optVirtualTable) Exclusion(i int) cat.ExclusionConstraint {
+	panic(errors.AssertionFailedf("no exclusion constraints"))
+}
+
+// DeferrableUniqueCount is part of the cat.Table interface.
+func (ot *optVirtualTable) DeferrableUniqueCount() int {
+	return 0
+}
+
+// DeferrableUnique is part of the cat.Table interface.
+func (ot *optVirtualTable) DeferrableUnique(i int) cat.DeferrableUniqueConstraint {
+	panic(errors.AssertionFailedf("no deferrable unique constraints"))
+}
+
 // FamilyCount is part of the cat.Table interface.
 func (ot *optVirtualTable) FamilyCount() int {
 	return 1
@@ -1517,6 +1625,11 @@ func (optDummyVirtualPKColumn) ComputedExprStr() string {
 	return ""
 }
 
+// IsVirtual is part of the cat.Column interface.
+func (optDummyVirtualPKColumn) IsVirtual() bool {
+	return false
+}
+
 // optVirtualIndex is a dummy implementation of cat.Index for the only index
 // reported by a virtual table. The index assumes that table column 0 is a dummy
 // PK column.
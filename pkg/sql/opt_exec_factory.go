@@ -1188,6 +1188,7 @@ func (ef *execFactory) ConstructInsert(
 	if err := ef.planner.maybeSetSystemConfig(tabDesc.GetID()); err != nil {
 		return nil, err
 	}
+	ef.planner.recordTempTableWrite(tabDesc)
 
 	var fkTables row.FkTableMetadata
 	checkFKs := row.SkipFKs
@@ -1207,13 +1208,18 @@ func (ef *execFactory) ConstructInsert(
 	if err != nil {
 		return nil, err
 	}
+	ri.SetFKDeferrer(ef.planner.fkDeferrer())
+	triggers, err := makeRowTriggers(ctx, ef.planner, tabDesc)
+	if err != nil {
+		return nil, err
+	}
 
 	// Regular path for INSERT.
 	ins := insertNodePool.Get().(*insertNode)
 	*ins = insertNode{
 		source: input.(planNode),
 		run: insertRun{
-			ti:         tableInserter{ri: ri},
+			ti:         tableInserter{tableWriterBase: ef.planner.makeTableWriterBase(triggers), ri: ri},
 			checkOrds:  checkOrdSet,
 			insertCols: ri.InsertCols,
 		},
@@ -1264,6 +1270,7 @@ func (ef *execFactory) ConstructInsertFastPath(
 	if err := ef.planner.maybeSetSystemConfig(tabDesc.GetID()); err != nil {
 		return nil, err
 	}
+	ef.planner.recordTempTableWrite(tabDesc)
 
 	// Create the table inserter, which does the bulk of the work.
 	ri, err := row.MakeInserter(
@@ -1272,6 +1279,10 @@ func (ef *execFactory) ConstructInsertFastPath(
 	if err != nil {
 		return nil, err
 	}
+	triggers, err := makeRowTriggers(ctx, ef.planner, tabDesc)
+	if err != nil {
+		return nil, err
+	}
 
 	// Regular path for INSERT.
 	ins := insertFastPathNodePool.Get().(*insertFastPathNode)
@@ -1279,7 +1290,7 @@ func (ef *execFactory) ConstructInsertFastPath(
 		input: rows,
 		run: insertFastPathRun{
 			insertRun: insertRun{
-				ti:         tableInserter{ri: ri},
+				ti:         tableInserter{tableWriterBase: tableWriterBase{triggers: triggers}, ri: ri},
 				checkOrds:  checkOrdSet,
 				insertCols: ri.InsertCols,
 			},
@@ -1287,9 +1298,9 @@ func (ef *execFactory) ConstructInsertFastPath(
 	}
 
 	if len(fkChecks) > 0 {
-		ins.run.fkChecks = make([]insertFastPathFKCheck, len(fkChecks))
+		ins.run.checks = make([]insertFastPathFKCheck, len(fkChecks))
 		for i := range fkChecks {
-			ins.run.fkChecks[i].InsertFastPathFKCheck = fkChecks[i]
+			ins.run.checks[i].InsertFastPathFKCheck = fkChecks[i]
 		}
 	}
 
@@ -1338,13 +1349,14 @@ func (ef *execFactory) ConstructUpdate(
 	ctx := ef.planner.extendedEvalCtx.Context
 
 	// Derive table and column descriptors.
-	rowsNeeded := !returnColOrdSet.Empty()
+	rowsNeeded := !returnColOrdSet.Empty() || len(passthrough) > 0
 	tabDesc := table.(*optTable).desc
 	fetchColDescs := makeColDescList(table, fetchColOrdSet)
 
 	if err := ef.planner.maybeSetSystemConfig(tabDesc.GetID()); err != nil {
 		return nil, err
 	}
+	ef.planner.recordTempTableWrite(tabDesc)
 
 	// Add each column to update as a sourceSlot. The CBO only uses scalarSlot,
 	// since it compiles tuples and subqueries into a simple sequence of target
@@ -1367,13 +1379,20 @@ func (ef *execFactory) ConstructUpdate(
 		}
 	}
 
+	// Row-level triggers may modify columns that are not updated by the
+	// statement itself.
+	triggers, err := makeRowTriggers(ctx, ef.planner, tabDesc)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the table updater, which does the bulk of the work.
 	ru, err := row.MakeUpdater(
 		ctx,
 		ef.planner.txn,
 		tabDesc,
 		fkTables,
-		updateColDescs,
+		triggers.extendUpdateCols(updateColDescs),
 		fetchColDescs,
 		row.UpdaterDefault,
 		checkFKs,
@@ -1383,6 +1402,7 @@ func (ef *execFactory) ConstructUpdate(
 	if err != nil {
 		return nil, err
 	}
+	ru.SetFKDeferrer(ef.planner.fkDeferrer())
 
 	// Truncate any FetchCols added by MakeUpdater. The optimizer has already
 	// computed a correct set that can sometimes be smaller.
@@ -1400,7 +1420,7 @@ func (ef *execFactory) ConstructUpdate(
 	*upd = updateNode{
 		source: input.(planNode),
 		run: updateRun{
-			tu:        tableUpdater{ru: ru},
+			tu:        tableUpdater{tableWriterBase: ef.planner.makeTableWriterBase(triggers), ru: ru},
 			checkOrds: checks,
 			iVarContainerForComputedCols: sqlbase.RowIndexedVarContainer{
 				CurSourceRow: make(tree.Datums, len(ru.FetchCols)),
@@ -1501,6 +1521,7 @@ func (ef *execFactory) ConstructUpsert(
 	if err := ef.planner.maybeSetSystemConfig(tabDesc.GetID()); err != nil {
 		return nil, err
 	}
+	ef.planner.recordTempTableWrite(tabDesc)
 
 	var fkTables row.FkTableMetadata
 	checkFKs := row.SkipFKs
@@ -1522,6 +1543,14 @@ func (ef *execFactory) ConstructUpsert(
 	if err != nil {
 		return nil, err
 	}
+	ri.SetFKDeferrer(ef.planner.fkDeferrer())
+
+	// Row-level triggers may modify columns that are not updated by the
+	// statement itself.
+	triggers, err := makeRowTriggers(ctx, ef.planner, tabDesc)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create the table updater, which does the bulk of the update-related work.
 	ru, err := row.MakeUpdater(
@@ -1529,7 +1558,7 @@ func (ef *execFactory) ConstructUpsert(
 		ef.planner.txn,
 		tabDesc,
 		fkTables,
-		updateColDescs,
+		triggers.extendUpdateCols(updateColDescs),
 		fetchColDescs,
 		row.UpdaterDefault,
 		checkFKs,
@@ -1539,6 +1568,7 @@ func (ef *execFactory) ConstructUpsert(
 	if err != nil {
 		return nil, err
 	}
+	ru.SetFKDeferrer(ef.planner.fkDeferrer())
 
 	// Truncate any FetchCols added by MakeUpdater. The optimizer has already
 	// computed a correct set that can sometimes be smaller.
@@ -1560,13 +1590,14 @@ func (ef *execFactory) ConstructUpsert(
 			checkOrds:  checks,
 			insertCols: ri.InsertCols,
 			tw: optTableUpserter{
-				ri:            ri,
-				alloc:         &ef.planner.alloc,
-				canaryOrdinal: int(canaryCol),
-				fkTables:      fkTables,
-				fetchCols:     fetchColDescs,
-				updateCols:    updateColDescs,
-				ru:            ru,
+				tableWriterBase: ef.planner.makeTableWriterBase(triggers),
+				ri:              ri,
+				alloc:           &ef.planner.alloc,
+				canaryOrdinal:   int(canaryCol),
+				fkTables:        fkTables,
+				fetchCols:       fetchColDescs,
+				updateCols:      updateColDescs,
+				ru:              ru,
 			},
 		},
 	}
@@ -1600,24 +1631,148 @@ func (ef *execFactory) ConstructUpsert(
 	return &rowCountNode{source: ups}, nil
 }
 
+func (ef *execFactory) ConstructMerge(
+	input exec.Node,
+	table cat.Table,
+	actionCol exec.ColumnOrdinal,
+	insertColOrdSet exec.ColumnOrdinalSet,
+	fetchColOrdSet exec.ColumnOrdinalSet,
+	updateColOrdSet exec.ColumnOrdinalSet,
+	checks exec.CheckOrdinalSet,
+	allowAutoCommit bool,
+	skipFKChecks bool,
+) (exec.Node, error) {
+	ctx := ef.planner.extendedEvalCtx.Context
+
+	// Derive table and column descriptors.
+	tabDesc := table.(*optTable).desc
+	insertColDescs := makeColDescList(table, insertColOrdSet)
+	fetchColDescs := makeColDescList(table, fetchColOrdSet)
+	updateColDescs := makeColDescList(table, updateColOrdSet)
+
+	if err := ef.planner.maybeSetSystemConfig(tabDesc.GetID()); err != nil {
+		return nil, err
+	}
+	ef.planner.recordTempTableWrite(tabDesc)
+
+	var fkTables row.FkTableMetadata
+	checkFKs := row.SkipFKs
+	if !skipFKChecks {
+		checkFKs = row.CheckFKs
+
+		// Determine the foreign key tables involved in the merge. CheckUpdates
+		// covers the checks of both inserted and deleted values.
+		var err error
+		fkTables, err = ef.makeFkMetadata(tabDesc, row.CheckUpdates)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Create the table inserter, updater and deleter, which do the bulk of the
+	// work for each kind of action.
+	ri, err := row.MakeInserter(
+		ctx, ef.planner.txn, tabDesc, insertColDescs, checkFKs, fkTables, &ef.planner.alloc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	ri.SetFKDeferrer(ef.planner.fkDeferrer())
+
+	triggers, err := makeRowTriggers(ctx, ef.planner, tabDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	ru, err := row.MakeUpdater(
+		ctx,
+		ef.planner.txn,
+		tabDesc,
+		fkTables,
+		triggers.extendUpdateCols(updateColDescs),
+		fetchColDescs,
+		row.UpdaterDefault,
+		checkFKs,
+		ef.planner.EvalContext(),
+		&ef.planner.alloc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	ru.SetFKDeferrer(ef.planner.fkDeferrer())
+
+	// Truncate any FetchCols added by MakeUpdater. The optimizer has already
+	// computed a correct set that can sometimes be smaller.
+	ru.FetchCols = ru.FetchCols[:len(fetchColDescs)]
+
+	rd, err := row.MakeDeleter(
+		ctx,
+		ef.planner.txn,
+		tabDesc,
+		fkTables,
+		fetchColDescs,
+		checkFKs,
+		ef.planner.EvalContext(),
+		&ef.planner.alloc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rd.SetFKDeferrer(ef.planner.fkDeferrer())
+	rd.FetchCols = rd.FetchCols[:len(fetchColDescs)]
+
+	// Instantiate the merge node.
+	mrg := mergeNodePool.Get().(*mergeNode)
+	*mrg = mergeNode{
+		source: input.(planNode),
+		run: mergeRun{
+			checkOrds:  checks,
+			insertCols: ri.InsertCols,
+			tw: optTableMerger{
+				optTableUpserter: optTableUpserter{
+					tableWriterBase: ef.planner.makeTableWriterBase(triggers),
+					ri:              ri,
+					alloc:           &ef.planner.alloc,
+					fkTables:        fkTables,
+					fetchCols:       fetchColDescs,
+					updateCols:      updateColDescs,
+					ru:              ru,
+				},
+				rd:            rd,
+				actionOrdinal: int(actionCol),
+			},
+		},
+	}
+
+	if allowAutoCommit && ef.planner.autoCommit {
+		mrg.enableAutoCommit()
+	}
+
+	// We could use serializeNode here, but using rowCountNode is an
+	// optimization that saves on calls to Next() by the caller.
+	return &rowCountNode{source: mrg}, nil
+}
+
 func (ef *execFactory) ConstructDelete(
 	input exec.Node,
 	table cat.Table,
 	fetchColOrdSet exec.ColumnOrdinalSet,
 	returnColOrdSet exec.ColumnOrdinalSet,
+	passthrough sqlbase.ResultColumns,
 	allowAutoCommit bool,
 	skipFKChecks bool,
 ) (exec.Node, error) {
 	ctx := ef.planner.extendedEvalCtx.Context
 
 	// Derive table and column descriptors.
-	rowsNeeded := !returnColOrdSet.Empty()
+	rowsNeeded := !returnColOrdSet.Empty() || len(passthrough) > 0
 	tabDesc := table.(*optTable).desc
 	fetchColDescs := makeColDescList(table, fetchColOrdSet)
 
 	if err := ef.planner.maybeSetSystemConfig(tabDesc.GetID()); err != nil {
 		return nil, err
 	}
+	ef.planner.recordTempTableWrite(tabDesc)
 
 	// Determine the foreign key tables involved in the delete.
 	// This will include all the interleaved child tables as we need them
@@ -1654,17 +1809,28 @@ func (ef *execFactory) ConstructDelete(
 	if err != nil {
 		return nil, err
 	}
+	rd.SetFKDeferrer(ef.planner.fkDeferrer())
 
 	// Truncate any FetchCols added by MakeUpdater. The optimizer has already
 	// computed a correct set that can sometimes be smaller.
 	rd.FetchCols = rd.FetchCols[:len(fetchColDescs)]
 
+	triggers, err := makeRowTriggers(ctx, ef.planner, tabDesc)
+	if err != nil {
+		return nil, err
+	}
+
 	// Now make a delete node. We use a pool.
 	del := deleteNodePool.Get().(*deleteNode)
 	*del = deleteNode{
 		source: input.(planNode),
 		run: deleteRun{
-			td: tableDeleter{rd: rd, alloc: &ef.planner.alloc},
+			td: tableDeleter{
+				tableWriterBase: ef.planner.makeTableWriterBase(triggers),
+				rd:              rd,
+				alloc:           &ef.planner.alloc,
+			},
+			numPassthrough: len(passthrough),
 		},
 	}
 
@@ -1674,6 +1840,8 @@ func (ef *execFactory) ConstructDelete(
 		// Delete returns the non-mutation columns specified, in the same
 		// order they are defined in the table.
 		del.columns = sqlbase.ResultColumnsFromColDescs(returnColDescs)
+		// Add the passthrough columns to the returning columns.
+		del.columns = append(del.columns, passthrough...)
 
 		del.run.rowIdxToRetIdx = row.ColMapping(rd.FetchCols, returnColDescs)
 		del.run.rowsNeeded = true
@@ -1709,6 +1877,7 @@ func (ef *execFactory) ConstructDeleteRange(
 	if err := ef.planner.maybeSetSystemConfig(tabDesc.GetID()); err != nil {
 		return nil, err
 	}
+	ef.planner.recordTempTableWrite(tabDesc)
 
 	// Setting the "forDelete" flag includes all column families in case where a
 	// single record is deleted.
@@ -1761,6 +1930,7 @@ func (ef *execFactory) ConstructCreateView(
 	ifNotExists bool,
 	replace bool,
 	temporary bool,
+	materialized bool,
 	viewQuery string,
 	columns sqlbase.ResultColumns,
 	deps opt.ViewDeps,
@@ -1790,14 +1960,15 @@ func (ef *execFactory) ConstructCreateView(
 	}
 
 	return &createViewNode{
-		viewName:    tree.Name(viewName),
-		ifNotExists: ifNotExists,
-		replace:     replace,
-		temporary:   temporary,
-		viewQuery:   viewQuery,
-		dbDesc:      schema.(*optSchema).desc,
-		columns:     columns,
-		planDeps:    planDeps,
+		viewName:     tree.Name(viewName),
+		ifNotExists:  ifNotExists,
+		replace:      replace,
+		temporary:    temporary,
+		materialized: materialized,
+		viewQuery:    viewQuery,
+		dbDesc:       schema.(*optSchema).desc,
+		columns:      columns,
+		planDeps:     planDeps,
 	}, nil
 }
 
@@ -1823,6 +1994,69 @@ func (ef *execFactory) ConstructErrorIfRows(
 	}, nil
 }
 
+// ConstructDeferrableFKCheck is part of the exec.Factory interface.
+func (ef *execFactory) ConstructDeferrableFKCheck(
+	input exec.Node,
+	fk cat.ForeignKeyConstraint,
+	origin cat.Table,
+	referenced cat.Table,
+	keyCols []exec.ColumnOrdinal,
+	mkErr func(keyVals tree.Datums) error,
+) (exec.Node, error) {
+	check := &deferrableCheck{
+		name:              fk.Name(),
+		deferrability:     fk.Deferrability(),
+		originTableID:     sqlbase.ID(origin.ID()),
+		originColIDs:      make([]sqlbase.ColumnID, fk.ColumnCount()),
+		referencedTableID: sqlbase.ID(referenced.ID()),
+		referencedColIDs:  make([]sqlbase.ColumnID, fk.ColumnCount()),
+		keyCols:           keyCols,
+		mkErr:             mkErr,
+	}
+	for i := range check.originColIDs {
+		originCol := origin.Column(fk.OriginColumnOrdinal(origin, i))
+		referencedCol := referenced.Column(fk.ReferencedColumnOrdinal(referenced, i))
+		check.originColIDs[i] = sqlbase.ColumnID(originCol.ColID())
+		check.referencedColIDs[i] = sqlbase.ColumnID(referencedCol.ColID())
+	}
+	return &errorIfRowsNode{
+		plan: input.(planNode),
+		mkErr: func(row tree.Datums) error {
+			return mkErr(check.keyVals(row))
+		},
+		check: check,
+	}, nil
+}
+
+// ConstructDeferrableUniqueCheck is part of the exec.Factory interface.
+func (ef *execFactory) ConstructDeferrableUniqueCheck(
+	input exec.Node,
+	table cat.Table,
+	uniq cat.DeferrableUniqueConstraint,
+	keyCols []exec.ColumnOrdinal,
+	mkErr func(keyVals tree.Datums) error,
+) (exec.Node, error) {
+	check := &deferrableCheck{
+		name:          uniq.Name,
+		deferrability: uniq.Deferrability,
+		unique:        true,
+		originTableID: sqlbase.ID(table.ID()),
+		originColIDs:  make([]sqlbase.ColumnID, len(uniq.ColumnOrdinals)),
+		keyCols:       keyCols,
+		mkErr:         mkErr,
+	}
+	for i, ord := range uniq.ColumnOrdinals {
+		check.originColIDs[i] = sqlbase.ColumnID(table.Column(ord).ColID())
+	}
+	return &errorIfRowsNode{
+		plan: input.(planNode),
+		mkErr: func(row tree.Datums) error {
+			return mkErr(check.keyVals(row))
+		},
+		check: check,
+	}, nil
+}
+
 // ConstructOpaque is part of the exec.Factory interface.
 func (ef *execFactory) ConstructOpaque(metadata opt.OpaqueMetadata) (exec.Node, error) {
 	o, ok := metadata.(*opaqueMetadata)
@@ -112,6 +112,14 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE VIEW blah AS (SELECT c FROM x) ??`, `CREATE VIEW`},
 		{`CREATE VIEW blah AS SELECT c FROM x ??`, `SELECT`},
 		{`CREATE VIEW blah AS (??`, `<SELECTCLAUSE>`},
+		{`CREATE MATERIALIZED VIEW blah (??`, `CREATE VIEW`},
+
+		{`CREATE TRIGGER ??`, `CREATE TRIGGER`},
+		{`CREATE TRIGGER blah BEFORE INSERT ON ??`, `CREATE TRIGGER`},
+
+		{`CREATE FUNCTION ??`, `CREATE FUNCTION`},
+		{`CREATE FUNCTION blah(a INT) RETURNS ??`, `CREATE FUNCTION`},
+		{`CREATE OR REPLACE FUNCTION blah ??`, `CREATE FUNCTION`},
 
 		{`CREATE SEQUENCE ??`, `CREATE SEQUENCE`},
 
@@ -133,6 +141,7 @@ func TestContextualHelp(t *testing.T) {
 		{`DELETE FROM blah ??`, `DELETE`},
 		{`DELETE FROM blah WHERE ??`, `DELETE`},
 		{`DELETE FROM blah WHERE x > 3 ??`, `DELETE`},
+		{`DELETE FROM blah USING ??`, `DELETE`},
 
 		{`DISCARD ALL ??`, `DISCARD`},
 		{`DISCARD ??`, `DISCARD`},
@@ -157,6 +166,12 @@ func TestContextualHelp(t *testing.T) {
 		{`DROP SEQUENCE IF ??`, `DROP SEQUENCE`},
 		{`DROP SEQUENCE IF EXISTS blih, bloh ??`, `DROP SEQUENCE`},
 
+		{`DROP TRIGGER ??`, `DROP TRIGGER`},
+		{`DROP TRIGGER blah ON ??`, `DROP TRIGGER`},
+
+		{`DROP FUNCTION ??`, `DROP FUNCTION`},
+		{`DROP FUNCTION IF EXISTS blah(??`, `DROP FUNCTION`},
+
 		{`DROP TABLE blah ??`, `DROP TABLE`},
 		{`DROP TABLE IF ??`, `DROP TABLE`},
 		{`DROP TABLE IF EXISTS blih, bloh ??`, `DROP TABLE`},
@@ -209,6 +224,10 @@ func TestContextualHelp(t *testing.T) {
 		{`UPDATE blah SET x = 3 ??`, `UPDATE`},
 		{`UPDATE blah SET x = 3 WHERE ??`, `UPDATE`},
 
+		{`MERGE ??`, `MERGE`},
+		{`MERGE INTO blah USING foo ON ??`, `MERGE`},
+		{`MERGE INTO blah USING foo ON true WHEN ??`, `MERGE`},
+
 		{`GRANT ALL ??`, `GRANT`},
 		{`GRANT ALL ON foo TO ??`, `GRANT`},
 		{`GRANT ALL ON foo TO bar ??`, `GRANT`},
@@ -217,6 +236,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`RESUME ??`, `RESUME JOBS`},
 
+		{`REFRESH ??`, `REFRESH`},
+		{`REFRESH MATERIALIZED VIEW blah ??`, `REFRESH`},
+
 		{`REVOKE ALL ??`, `REVOKE`},
 		{`REVOKE ALL ON foo FROM ??`, `REVOKE`},
 		{`REVOKE ALL ON foo FROM bar ??`, `REVOKE`},
@@ -339,6 +361,9 @@ func TestContextualHelp(t *testing.T) {
 		{`SET SESSION blah TO ??`, `SET SESSION`},
 		{`SET SESSION blah TO 42 ??`, `SET SESSION`},
 
+		{`SET CONSTRAINTS ??`, `SET CONSTRAINTS`},
+		{`SET CONSTRAINTS ALL ??`, `SET CONSTRAINTS`},
+
 		{`SET TRANSACTION ??`, `SET TRANSACTION`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT ??`, `SET TRANSACTION`},
 		{`SET TIME ??`, `SET SESSION`},
@@ -95,7 +95,7 @@ func (l *lexer) Lex(lval *sqlSymType) int {
 			}
 		case NOT:
 			switch nextID {
-			case BETWEEN, IN, LIKE, ILIKE, SIMILAR:
+			case BETWEEN, IN, LIKE, ILIKE, SIMILAR, DEFERRABLE:
 				lval.id = NOT_LA
 			}
 
@@ -102,6 +102,12 @@ func TestParse(t *testing.T) {
 
 		{`CREATE TABLE a ()`},
 		{`CREATE TEMPORARY TABLE a (b INT8)`},
+		{`CREATE TEMPORARY TABLE a (b INT8) ON COMMIT PRESERVE ROWS`},
+		{`CREATE TEMPORARY TABLE a (b INT8) ON COMMIT DELETE ROWS`},
+		{`CREATE TEMPORARY TABLE a (b INT8) ON COMMIT DROP`},
+		{`CREATE TEMPORARY TABLE IF NOT EXISTS a (b INT8) ON COMMIT DELETE ROWS`},
+		{`CREATE TEMPORARY TABLE a AS SELECT b FROM c ON COMMIT DELETE ROWS`},
+		{`CREATE TEMPORARY TABLE IF NOT EXISTS a AS SELECT b FROM c ON COMMIT DROP`},
 		{`EXPLAIN CREATE TABLE a ()`},
 		{`CREATE TABLE a (b INT8)`},
 		{`CREATE TABLE a (b INT8, c INT8)`},
@@ -143,6 +149,8 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8 CONSTRAINT one DEFAULT 1)`},
 		{`CREATE TABLE a (b INT8 DEFAULT now())`},
 		{`CREATE TABLE a (a INT8 CHECK (a > 0))`},
+		{`CREATE TABLE a (a INT8, b INT8, EXCLUDE USING gist (a WITH =, int8range(a, b) WITH &&))`},
+		{`CREATE TABLE a (a INT8, b INT8, CONSTRAINT c EXCLUDE (a WITH =) WHERE (b > 0))`},
 		{`CREATE TABLE a (a INT8 CONSTRAINT positive CHECK (a > 0))`},
 		{`CREATE TABLE a (a INT8 DEFAULT 1 CHECK (a > 0))`},
 		{`CREATE TABLE a (a INT8 CONSTRAINT one DEFAULT 1 CHECK (a > 0))`},
@@ -182,6 +190,9 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON UPDATE SET NULL)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON DELETE SET DEFAULT)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON DELETE SET DEFAULT ON UPDATE SET NULL)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other MATCH FULL ON DELETE RESTRICT DEFERRABLE INITIALLY DEFERRED)`},
 		{`CREATE TABLE a (b INT8, c STRING, INDEX (b, c))`},
 		{`CREATE TABLE a (b INT8, c STRING, INDEX d (b, c))`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE (b, c))`},
@@ -216,6 +227,8 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo MATCH FULL ON DELETE RESTRICT)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo MATCH FULL ON DELETE RESTRICT ON UPDATE RESTRICT)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo (bar) MATCH FULL)`},
+		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, c INT8 NOT NULL REFERENCES foo ON DELETE RESTRICT DEFERRABLE INITIALLY DEFERRED)`},
 		{`CREATE TABLE a (b INT8, INDEX (b) STORING (c))`},
 		{`CREATE TABLE a (b INT8, c STRING, INDEX (b ASC, c DESC) STORING (c))`},
 		{`CREATE TABLE a (b INT8, INDEX (b) INTERLEAVE IN PARENT c (d, e))`},
@@ -226,6 +239,7 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a.b (b INT8)`},
 		{`CREATE TABLE IF NOT EXISTS a (b INT8)`},
 		{`CREATE TABLE a (b INT8 AS (a + b) STORED)`},
+		{`CREATE TABLE a (b INT8 AS (a + b) VIRTUAL)`},
 		{`CREATE TABLE view (view INT8)`},
 
 		{`CREATE TABLE a (b INT8 CONSTRAINT c PRIMARY KEY)`},
@@ -302,6 +316,21 @@ func TestParse(t *testing.T) {
 		{`CREATE VIEW a (x, y) AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a AS TABLE b`},
 		{`CREATE TEMPORARY VIEW a AS SELECT b`},
+		{`CREATE MATERIALIZED VIEW a AS SELECT * FROM b`},
+		{`CREATE MATERIALIZED VIEW IF NOT EXISTS a AS SELECT * FROM b`},
+		{`CREATE MATERIALIZED VIEW a (x, y) AS SELECT c, d FROM b`},
+		{`REFRESH MATERIALIZED VIEW a.b`},
+		{`REFRESH MATERIALIZED VIEW CONCURRENTLY a`},
+		{`CREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW SET b = new.b + 1`},
+		{`CREATE TRIGGER t BEFORE INSERT OR UPDATE ON a.b FOR EACH ROW WHEN (new.c IS NULL) SET c = now(), d = old.d`},
+		{`CREATE TRIGGER t AFTER DELETE ON a FOR EACH ROW EXECUTE INSERT INTO log VALUES (old.k, 'deleted')`},
+		{`CREATE TRIGGER t AFTER UPDATE OR DELETE ON a FOR EACH ROW WHEN (old.b != 0) EXECUTE UPDATE counts SET n = n - 1 WHERE k = old.b`},
+		{`CREATE TRIGGER t AFTER INSERT ON a FOR EACH ROW EXECUTE SELECT crdb_internal.force_error('', 'x')`},
+
+		{`CREATE FUNCTION f() RETURNS INT8 AS 'SELECT 1'`},
+		{`CREATE FUNCTION a.b.f(x INT8, STRING) RETURNS STRING LANGUAGE sql IMMUTABLE AS 'SELECT x::STRING || $2'`},
+		{`CREATE OR REPLACE FUNCTION f(x INT8) RETURNS SETOF INT8 LANGUAGE sql STABLE AS 'SELECT generate_series(1, x)'`},
+		{`CREATE FUNCTION f(x DECIMAL(10,2)[]) RETURNS INT8 VOLATILE AS 'SELECT 1'`},
 
 		{`CREATE SEQUENCE a`},
 		{`EXPLAIN CREATE SEQUENCE a`},
@@ -352,6 +381,10 @@ func TestParse(t *testing.T) {
 		{`DELETE FROM a WHERE a = b RETURNING a, b`},
 		{`DELETE FROM a WHERE a = b RETURNING 1, 2`},
 		{`DELETE FROM a WHERE a = b RETURNING a + b`},
+		{`DELETE FROM a USING b`},
+		{`DELETE FROM a USING b, c WHERE a.x = b.x AND b.y = c.y`},
+		{`DELETE FROM a AS x USING a AS y WHERE x.b = y.b RETURNING x.c, y.c`},
+		{`DELETE FROM a USING b JOIN c ON b.x = c.x WHERE a.x = b.x ORDER BY c LIMIT d`},
 		{`DELETE FROM a WHERE a = b RETURNING NOTHING`},
 		{`DELETE FROM a WHERE a = b ORDER BY c LIMIT d RETURNING e`},
 
@@ -391,6 +424,13 @@ func TestParse(t *testing.T) {
 		{`DROP VIEW IF EXISTS a, b RESTRICT`},
 		{`DROP VIEW a.b CASCADE`},
 		{`DROP VIEW a, b CASCADE`},
+		{`DROP MATERIALIZED VIEW a`},
+		{`DROP MATERIALIZED VIEW IF EXISTS a, b CASCADE`},
+		{`DROP TRIGGER t ON a`},
+		{`DROP TRIGGER IF EXISTS t ON a.b`},
+		{`DROP FUNCTION f`},
+		{`DROP FUNCTION f()`},
+		{`DROP FUNCTION IF EXISTS a.f(INT8, STRING), g`},
 		{`DROP SEQUENCE a`},
 		{`EXPLAIN DROP SEQUENCE a`},
 		{`DROP SEQUENCE a.b`},
@@ -603,6 +643,8 @@ func TestParse(t *testing.T) {
 		// GRANT x ON TABLE y. However, the stringer does not output TABLE.
 		{`GRANT SELECT ON TABLE foo TO root`},
 		{`GRANT SELECT, DELETE, UPDATE ON TABLE foo, db.foo TO root, bar`},
+		{`GRANT EXECUTE ON FUNCTION f(INT8), g TO bar`},
+		{`REVOKE EXECUTE ON FUNCTION db.public.f() FROM bar`},
 		{`GRANT DROP ON DATABASE foo TO root`},
 		{`GRANT ALL ON DATABASE foo TO root, test`},
 		{`GRANT SELECT, INSERT ON DATABASE bar TO foo, bar, baz`},
@@ -666,6 +708,14 @@ func TestParse(t *testing.T) {
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2) RETURNING a + b`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2) RETURNING NOTHING`},
 
+		{`MERGE INTO a USING b ON a.x = b.x WHEN MATCHED THEN DELETE`},
+		{`MERGE INTO a AS t USING b AS s ON t.x = s.x WHEN MATCHED AND s.y > 0 THEN UPDATE SET y = s.y WHEN MATCHED THEN DELETE WHEN NOT MATCHED THEN INSERT VALUES (s.x, s.y)`},
+		{`MERGE INTO a USING b ON a.x = b.x WHEN MATCHED THEN UPDATE SET (y, z) = (b.y, DEFAULT) WHEN NOT MATCHED AND b.y > 0 THEN INSERT (x, y) VALUES (b.x, b.y) WHEN NOT MATCHED THEN DO NOTHING`},
+		{`MERGE INTO a USING (SELECT x FROM b) AS s ON a.x = s.x WHEN MATCHED THEN DO NOTHING WHEN NOT MATCHED THEN INSERT DEFAULT VALUES`},
+		{`MERGE INTO a USING b ON a.x = b.x WHEN NOT MATCHED THEN INSERT (x) VALUES ($1)`},
+		{`WITH s AS (SELECT 1 AS x) MERGE INTO a USING s ON a.x = s.x WHEN MATCHED THEN DELETE`},
+		{`EXPLAIN MERGE INTO a USING b ON a.x = b.x WHEN MATCHED THEN DELETE`},
+
 		{`SELECT 1 + 1`},
 		{`SELECT -1`},
 		{`SELECT .1`},
@@ -1006,6 +1056,11 @@ func TestParse(t *testing.T) {
 		{`SELECT avg(1) FILTER (WHERE a > b)`},
 		{`SELECT avg(1) FILTER (WHERE a > b) OVER (ORDER BY c)`},
 
+		{`SELECT percentile_disc(0.5) WITHIN GROUP (ORDER BY a)`},
+		{`SELECT percentile_cont(ARRAY[0.25, 0.75]) WITHIN GROUP (ORDER BY a DESC) FROM t`},
+		{`SELECT mode() WITHIN GROUP (ORDER BY a) FILTER (WHERE a > b) FROM t`},
+		{`SELECT rank(1, 2) WITHIN GROUP (ORDER BY a, b DESC) FROM t GROUP BY c`},
+
 		{`SELECT a FROM t UNION SELECT 1 FROM t`},
 		{`SELECT a FROM t UNION SELECT 1 FROM t UNION SELECT 1 FROM t`},
 		{`SELECT a FROM t UNION ALL SELECT 1 FROM t`},
@@ -1059,6 +1114,10 @@ func TestParse(t *testing.T) {
 		{`SET TRANSACTION PRIORITY NORMAL`},
 		{`SET TRANSACTION PRIORITY HIGH`},
 		{`SET TRANSACTION ISOLATION LEVEL SERIALIZABLE, PRIORITY HIGH`},
+		{`SET CONSTRAINTS ALL DEFERRED`},
+		{`SET CONSTRAINTS ALL IMMEDIATE`},
+		{`SET CONSTRAINTS a, b DEFERRED`},
+		{`SET CONSTRAINTS a IMMEDIATE`},
 
 		{`SET TRACING = off`},
 		{`EXPLAIN SET TRACING = off`},
@@ -1202,6 +1261,10 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE a ADD PRIMARY KEY (x, y, z) USING HASH WITH BUCKET_COUNT = 10 INTERLEAVE IN PARENT b (x, y)`},
 		{`ALTER TABLE a ADD CONSTRAINT "primary" PRIMARY KEY (x, y, z)`},
 		{`ALTER TABLE a ADD CONSTRAINT "primary" PRIMARY KEY (x, y, z) USING HASH WITH BUCKET_COUNT = 10 INTERLEAVE IN PARENT b (x, y)`},
+		{`ALTER TABLE a ADD CONSTRAINT foo EXCLUDE USING gist (bar WITH =)`},
+		{`ALTER TABLE a ADD CONSTRAINT foo EXCLUDE (bar WITH =, tsrange(s, e) WITH &&)`},
+		{`ALTER TABLE a ADD CONSTRAINT foo EXCLUDE USING gist (room WITH =, tstzrange(s, e, '[]') WITH &&) WHERE (NOT cancelled)`},
+		{`ALTER TABLE a ADD CONSTRAINT foo EXCLUDE USING gist (g WITH &&) NOT VALID`},
 
 		{`ALTER TABLE a ALTER COLUMN b SET DEFAULT 42`},
 		{`ALTER TABLE a ALTER COLUMN b SET DEFAULT NULL`},
@@ -1263,6 +1326,10 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE d.a SCATTER`},
 		{`ALTER INDEX d.i SCATTER FROM (1) TO (2)`},
 
+		{`ALTER TABLE a REVERT TO SYSTEM TIME '2020-01-01 00:00:00'`},
+		{`ALTER TABLE d.a REVERT TO SYSTEM TIME '-10s'`},
+		{`ALTER TABLE a REVERT TO SYSTEM TIME 1580361670629466905.0000000001`},
+
 		{`ALTER RANGE default CONFIGURE ZONE = 'foo'`},
 		{`EXPLAIN ALTER RANGE default CONFIGURE ZONE = 'foo'`},
 		{`ALTER RANGE meta CONFIGURE ZONE = 'foo'`},
@@ -1377,6 +1444,15 @@ func TestParse(t *testing.T) {
 		{`SHOW BACKUP RANGES 'bar'`},
 		{`SHOW BACKUP FILES 'bar'`},
 		{`SHOW BACKUP FILES 'bar' WITH foo = 'bar'`},
+		{`SHOW BACKUP 'foo' IN 'bar'`},
+		{`SHOW BACKUP 'foo' IN 'bar' WITH foo = 'bar'`},
+		{`SHOW BACKUP CHECK 'bar'`},
+		{`SHOW BACKUP CHECK 'bar' WITH verify_rows`},
+		{`SHOW BACKUP CHECK 'foo' IN 'bar'`},
+		{`SHOW BACKUP CHECK $1 IN $2 WITH encryption_passphrase = 'secret'`},
+		{`SHOW BACKUPS IN 'bar'`},
+		{`SHOW BACKUPS IN $1`},
+		{`EXPLAIN SHOW BACKUPS IN 'bar'`},
 
 		{`BACKUP TABLE foo TO 'bar' AS OF SYSTEM TIME '1' INCREMENTAL FROM 'baz'`},
 		{`BACKUP TABLE foo TO $1 INCREMENTAL FROM 'bar', $2, 'baz'`},
@@ -1389,7 +1465,16 @@ func TestParse(t *testing.T) {
 		{`BACKUP DATABASE foo TO ($1, $2)`},
 		{`BACKUP DATABASE foo TO ($1, $2) INCREMENTAL FROM 'baz'`},
 
+		{`BACKUP TABLE foo INTO 'bar'`},
+		{`BACKUP TABLE foo INTO LATEST IN 'bar'`},
+		{`BACKUP DATABASE foo INTO 'bar' AS OF SYSTEM TIME '1' WITH revision_history`},
+		{`BACKUP DATABASE foo INTO LATEST IN ($1, $2)`},
+		{`EXPLAIN BACKUP DATABASE foo INTO 'bar'`},
+
 		{`RESTORE TABLE foo FROM 'bar'`},
+		{`RESTORE TABLE foo AS foo_recovered FROM 'bar'`},
+		{`RESTORE TABLE db.foo AS db.foo_recovered FROM 'bar' AS OF SYSTEM TIME '1' WITH skip_missing_foreign_keys`},
+		{`EXPLAIN RESTORE TABLE foo AS bar FROM $1, $2`},
 		{`EXPLAIN RESTORE TABLE foo FROM 'bar'`},
 		{`RESTORE TABLE foo FROM $1`},
 		{`RESTORE TABLE foo FROM $1, $2, 'bar'`},
@@ -1407,6 +1492,11 @@ func TestParse(t *testing.T) {
 		{`RESTORE DATABASE foo FROM ($1, $2), ($3, $4)`},
 		{`RESTORE DATABASE foo FROM ($1, $2), ($3, $4) AS OF SYSTEM TIME '1'`},
 
+		{`RESTORE TABLE foo FROM 'subdir' IN 'bar'`},
+		{`RESTORE TABLE foo FROM $1 IN ($2, $3) AS OF SYSTEM TIME '1'`},
+		{`RESTORE DATABASE foo FROM 'subdir' IN 'bar' WITH key1, key2 = 'value'`},
+		{`EXPLAIN RESTORE DATABASE foo FROM 'subdir' IN 'bar'`},
+
 		{`BACKUP TABLE foo TO 'bar' WITH key1, key2 = 'value'`},
 		{`RESTORE TABLE foo FROM 'bar' WITH key1, key2 = 'value'`},
 
@@ -1483,6 +1573,9 @@ func TestParse2(t *testing.T) {
 		{`CREATE TABLE a (UNIQUE INDEX (b) PARTITION BY LIST (c) (PARTITION d VALUES IN (1)))`,
 			`CREATE TABLE a (UNIQUE (b) PARTITION BY LIST (c) (PARTITION d VALUES IN (1)))`},
 		{`CREATE INDEX ON a (b) COVERING (c)`, `CREATE INDEX ON a (b) STORING (c)`},
+
+		{`MERGE INTO a t USING b s ON t.x = s.x WHEN MATCHED THEN DELETE`,
+			`MERGE INTO a AS t USING b AS s ON t.x = s.x WHEN MATCHED THEN DELETE`},
 		{`CREATE INDEX ON a (b) INCLUDE (c)`, `CREATE INDEX ON a (b) STORING (c)`},
 
 		{`CREATE INDEX a ON b USING GIN (c)`,
@@ -1519,6 +1612,11 @@ func TestParse2(t *testing.T) {
 
 		{`CREATE STATISTICS a ON col1 FROM t AS OF SYSTEM TIME '2016-01-01'`,
 			`CREATE STATISTICS a ON col1 FROM t WITH OPTIONS AS OF SYSTEM TIME '2016-01-01'`},
+		{`CREATE FUNCTION f(a INT, b int) RETURNS int LANGUAGE SQL AS 'SELECT a + b'`,
+			`CREATE FUNCTION f(a INT8, b INT8) RETURNS INT8 LANGUAGE sql AS 'SELECT a + b'`},
+		{`CREATE FUNCTION f() RETURNS SETOF STRING AS 'SELECT ''a''' IMMUTABLE`,
+			`CREATE FUNCTION f() RETURNS SETOF STRING IMMUTABLE AS e'SELECT \'a\''`},
+		{`DROP FUNCTION f(a INT)`, `DROP FUNCTION f(INT8)`},
 
 		{`SELECT TIMESTAMP WITHOUT TIME ZONE 'foo'`, `SELECT TIMESTAMP 'foo'`},
 		{`SELECT CAST('foo' AS TIMESTAMP WITHOUT TIME ZONE)`, `SELECT CAST('foo' AS TIMESTAMP)`},
@@ -1959,6 +2057,8 @@ $function$`,
 		{`RESTORE DATABASE foo FROM ($1)`, `RESTORE DATABASE foo FROM $1`},
 		{`RESTORE DATABASE foo FROM ($1), ($2)`, `RESTORE DATABASE foo FROM $1, $2`},
 		{`RESTORE DATABASE foo FROM ($1), ($2, $3)`, `RESTORE DATABASE foo FROM $1, ($2, $3)`},
+		{`RESTORE DATABASE foo FROM LATEST IN 'bar'`, `RESTORE DATABASE foo FROM 'latest' IN 'bar'`},
+		{`SHOW BACKUP LATEST IN 'bar'`, `SHOW BACKUP 'latest' IN 'bar'`},
 
 		{`CREATE CHANGEFEED FOR TABLE foo INTO sink`,
 			`CREATE CHANGEFEED FOR TABLE foo INTO 'sink'`},
@@ -2137,6 +2237,30 @@ $function$`,
 			`CREATE TABLE a (b INT8, c INT8 REFERENCES foo MATCH SIMPLE)`,
 			`CREATE TABLE a (b INT8, c INT8 REFERENCES foo)`,
 		},
+		{
+			`CREATE TABLE a (b INT8, c INT8 REFERENCES foo NOT DEFERRABLE)`,
+			`CREATE TABLE a (b INT8, c INT8 REFERENCES foo)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, c INT8 REFERENCES foo NOT DEFERRABLE NOT NULL)`,
+			`CREATE TABLE a (b INT8, c INT8 NOT NULL REFERENCES foo)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES foo INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES foo)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES foo NOT DEFERRABLE INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES foo)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES foo DEFERRABLE INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES foo DEFERRABLE)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES foo INITIALLY DEFERRED)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES foo DEFERRABLE INITIALLY DEFERRED)`,
+		},
 		{
 			`CREATE TABLE a (b INT8, c INT8 REFERENCES foo MATCH SIMPLE ON UPDATE RESTRICT)`,
 			`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON UPDATE RESTRICT)`,
@@ -2416,6 +2540,16 @@ SET TIME ZONE INTERVAL 'foobar'
 DETAIL: source SQL:
 SELECT INTERVAL 'foo'
                      ^`},
+		{`SELECT a(DISTINCT b) WITHIN GROUP (ORDER BY c)`,
+			`at or near "EOF": syntax error: cannot use DISTINCT with WITHIN GROUP
+DETAIL: source SQL:
+SELECT a(DISTINCT b) WITHIN GROUP (ORDER BY c)
+                                              ^`},
+		{`SELECT a(b ORDER BY c) WITHIN GROUP (ORDER BY c)`,
+			`at or near "EOF": syntax error: cannot use multiple ORDER BY clauses with WITHIN GROUP
+DETAIL: source SQL:
+SELECT a(b ORDER BY c) WITHIN GROUP (ORDER BY c)
+                                                ^`},
 		{`SELECT 1 /* hello`,
 			`lexical error: unterminated comment
 DETAIL: source SQL:
@@ -2892,6 +3026,20 @@ DETAIL: source SQL:
 CREATE STATISTICS a ON col1 FROM t WITH OPTIONS THROTTLING 2.0
                                                            ^`,
 		},
+		{
+			`CREATE TABLE a (b INT8, CHECK (b > 0) DEFERRABLE)`,
+			`at or near ")": syntax error: CHECK constraints cannot be marked DEFERRABLE
+DETAIL: source SQL:
+CREATE TABLE a (b INT8, CHECK (b > 0) DEFERRABLE)
+                                                ^`,
+		},
+		{
+			`CREATE TABLE a (b INT8 REFERENCES c NOT DEFERRABLE INITIALLY DEFERRED)`,
+			`at or near "deferred": syntax error: constraint declared INITIALLY DEFERRED must be DEFERRABLE
+DETAIL: source SQL:
+CREATE TABLE a (b INT8 REFERENCES c NOT DEFERRABLE INITIALLY DEFERRED)
+                                                             ^`,
+		},
 		{
 			`CREATE STATISTICS a ON col1 FROM t WITH OPTIONS THROTTLING 0.1 THROTTLING 0.5`,
 			`at or near "0.5": syntax error: THROTTLING specified multiple times
@@ -3170,7 +3318,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		hint     string
 	}{
 		{`ALTER TABLE a ALTER CONSTRAINT foo`, 31632, `alter constraint`, ``},
-		{`ALTER TABLE a ADD CONSTRAINT foo EXCLUDE USING gist (bar WITH =)`, 46657, `add constraint exclude using`, ``},
 
 		{`CREATE AGGREGATE a`, 0, `create aggregate`, ``},
 		{`CREATE CAST a`, 0, `create cast`, ``},
@@ -3180,17 +3327,13 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE EXTENSION a`, 0, `create extension a`, ``},
 		{`CREATE FOREIGN DATA WRAPPER a`, 0, `create fdw`, ``},
 		{`CREATE FOREIGN TABLE a`, 0, `create foreign table`, ``},
-		{`CREATE FUNCTION a`, 17511, `create`, ``},
-		{`CREATE OR REPLACE FUNCTION a`, 17511, `create`, ``},
 		{`CREATE LANGUAGE a`, 17511, `create language a`, ``},
-		{`CREATE MATERIALIZED VIEW a`, 41649, ``, ``},
 		{`CREATE OPERATOR a`, 0, `create operator`, ``},
 		{`CREATE PUBLICATION a`, 0, `create publication`, ``},
 		{`CREATE RULE a`, 0, `create rule`, ``},
 		{`CREATE SERVER a`, 0, `create server`, ``},
 		{`CREATE SUBSCRIPTION a`, 0, `create subscription`, ``},
 		{`CREATE TEXT SEARCH a`, 7821, `create text`, ``},
-		{`CREATE TRIGGER a`, 28296, `create`, ``},
 
 		{`DROP AGGREGATE a`, 0, `drop aggregate`, ``},
 		{`DROP CAST a`, 0, `drop cast`, ``},
@@ -3200,7 +3343,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DROP EXTENSION a`, 0, `drop extension a`, ``},
 		{`DROP FOREIGN TABLE a`, 0, `drop foreign table`, ``},
 		{`DROP FOREIGN DATA WRAPPER a`, 0, `drop fdw`, ``},
-		{`DROP FUNCTION a`, 17511, `drop `, ``},
 		{`DROP LANGUAGE a`, 17511, `drop language a`, ``},
 		{`DROP OPERATOR a`, 0, `drop operator`, ``},
 		{`DROP PUBLICATION a`, 0, `drop publication`, ``},
@@ -3209,7 +3351,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DROP SERVER a`, 0, `drop server`, ``},
 		{`DROP SUBSCRIPTION a`, 0, `drop subscription`, ``},
 		{`DROP TEXT SEARCH a`, 7821, `drop text`, ``},
-		{`DROP TRIGGER a`, 28296, `drop`, ``},
 		{`DROP TYPE a`, 27793, `drop type`, ``},
 
 		{`DISCARD PLANS`, 0, `discard plans`, ``},
@@ -3217,7 +3358,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DISCARD TEMP`, 0, `discard temp`, ``},
 		{`DISCARD TEMPORARY`, 0, `discard temp`, ``},
 
-		{`SET CONSTRAINTS foo`, 0, `set constraints`, ``},
 		{`SET LOCAL foo = bar`, 32562, ``, ``},
 		{`SET foo FROM CURRENT`, 0, `set from current`, ``},
 
@@ -3231,32 +3371,17 @@ func TestUnimplementedSyntax(t *testing.T) {
 
 		{`CREATE TABLE a AS SELECT b WITH NO DATA`, 0, `create table as with no data`, ``},
 
-		{`CREATE TABLE a(b INT8 AS (123) VIRTUAL)`, 0, `virtual computed columns`, ``},
 		{`CREATE TABLE a(b INT8 REFERENCES c(x) MATCH PARTIAL`, 20305, `match partial`, ``},
 		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) MATCH PARTIAL)`, 20305, `match partial`, ``},
 
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE)`, 31632, `deferrable`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY DEFERRED)`, 31632, `initially deferred`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY IMMEDIATE)`, 31632, `initially immediate`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY DEFERRED)`, 31632, `initially deferred`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY IMMEDIATE)`, 31632, `initially immediate`, ``},
-		{`CREATE TABLE a(b INT8, UNIQUE (b) DEFERRABLE)`, 31632, `deferrable`, ``},
-		{`CREATE TABLE a(b INT8, CHECK (b > 0) DEFERRABLE)`, 31632, `deferrable`, ``},
+		{`CREATE TABLE a(b INT8, UNIQUE (b) DEFERRABLE)`, 31632, `deferrable unique`, ``},
+		{`CREATE TABLE a(b INT8, UNIQUE (b) INITIALLY DEFERRED)`, 31632, `deferrable unique`, ``},
 
 		{`CREATE TABLE a (LIKE b INCLUDING COMMENTS)`, 47071, `like table`, ``},
 		{`CREATE TABLE a (LIKE b INCLUDING IDENTITY)`, 47071, `like table`, ``},
 		{`CREATE TABLE a (LIKE b INCLUDING STATISTICS)`, 47071, `like table`, ``},
 		{`CREATE TABLE a (LIKE b INCLUDING STORAGE)`, 47071, `like table`, ``},
 
-		{`CREATE TEMP TABLE a (a int) ON COMMIT DROP`, 46556, `drop`, ``},
-		{`CREATE TEMP TABLE a (a int) ON COMMIT DELETE ROWS`, 46556, `delete rows`, ``},
-		{`CREATE TEMP TABLE IF NOT EXISTS a (a int) ON COMMIT DROP`, 46556, `drop`, ``},
-		{`CREATE TEMP TABLE IF NOT EXISTS a (a int) ON COMMIT DELETE ROWS`, 46556, `delete rows`, ``},
-		{`CREATE TEMP TABLE b AS SELECT a FROM a ON COMMIT DROP`, 46556, `drop`, ``},
-		{`CREATE TEMP TABLE b AS SELECT a FROM a ON COMMIT DELETE ROWS`, 46556, `delete rows`, ``},
-		{`CREATE TEMP TABLE IF NOT EXISTS b AS SELECT a FROM a ON COMMIT DROP`, 46556, `drop`, ``},
-		{`CREATE TEMP TABLE IF NOT EXISTS b AS SELECT a FROM a ON COMMIT DELETE ROWS`, 46556, `delete rows`, ``},
-
 		{`CREATE SEQUENCE a AS DOUBLE PRECISION`, 25110, `FLOAT8`, ``},
 
 		{`CREATE RECURSIVE VIEW a AS SELECT b`, 0, `create recursive view`, ``},
@@ -3283,6 +3408,9 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`INSERT INTO foo(a, a.b) VALUES (1,2)`, 27792, ``, ``},
 		{`INSERT INTO foo VALUES (1,2) ON CONFLICT ON CONSTRAINT a DO NOTHING`, 28161, ``, ``},
 
+		{`MERGE INTO a USING b ON a.x = b.x WHEN MATCHED THEN DELETE RETURNING x`, 0, `merge returning`, ``},
+		{`MERGE INTO a USING b ON a.x = b.x WHEN NOT MATCHED THEN DO NOTHING RETURNING NOTHING`, 0, `merge returning`, ``},
+
 		{`SELECT * FROM ROWS FROM (a(b) AS (d))`, 0, `ROWS FROM with col_def_list`, ``},
 
 		{`SELECT a(b) 'c'`, 0, `a(...) SCONST`, ``},
@@ -3292,7 +3420,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`SELECT a(VARIADIC b)`, 0, `variadic`, ``},
 		{`SELECT a(b, c, VARIADIC b)`, 0, `variadic`, ``},
 		{`SELECT TREAT (a AS INT8)`, 0, `treat`, ``},
-		{`SELECT a(b) WITHIN GROUP (ORDER BY c)`, 0, `within group`, ``},
 
 		{`SELECT 1::schem.typ`, 0, `qualified types`, ``},
 		{`SELECT 1::int4.typ`, 0, `qualified types`, ``},
@@ -337,6 +337,12 @@ func (u *sqlSymUnion) updateExpr() *tree.UpdateExpr {
 func (u *sqlSymUnion) updateExprs() tree.UpdateExprs {
     return u.val.(tree.UpdateExprs)
 }
+func (u *sqlSymUnion) mergeWhen() *tree.MergeWhen {
+    return u.val.(*tree.MergeWhen)
+}
+func (u *sqlSymUnion) mergeWhens() tree.MergeWhens {
+    return u.val.(tree.MergeWhens)
+}
 func (u *sqlSymUnion) limit() *tree.Limit {
     return u.val.(*tree.Limit)
 }
@@ -415,6 +421,12 @@ func (u *sqlSymUnion) idxElem() tree.IndexElem {
 func (u *sqlSymUnion) idxElems() tree.IndexElemList {
     return u.val.(tree.IndexElemList)
 }
+func (u *sqlSymUnion) excludeElem() tree.ExcludeElem {
+    return u.val.(tree.ExcludeElem)
+}
+func (u *sqlSymUnion) excludeElems() tree.ExcludeElemList {
+    return u.val.(tree.ExcludeElemList)
+}
 func (u *sqlSymUnion) dropBehavior() tree.DropBehavior {
     return u.val.(tree.DropBehavior)
 }
@@ -481,6 +493,9 @@ func (u *sqlSymUnion) transactionModes() tree.TransactionModes {
 func (u *sqlSymUnion) compositeKeyMatchMethod() tree.CompositeKeyMatchMethod {
   return u.val.(tree.CompositeKeyMatchMethod)
 }
+func (u *sqlSymUnion) constraintDeferrability() tree.ConstraintDeferrability {
+    return u.val.(tree.ConstraintDeferrability)
+}
 func (u *sqlSymUnion) referenceAction() tree.ReferenceAction {
     return u.val.(tree.ReferenceAction)
 }
@@ -511,6 +526,27 @@ func (u *sqlSymUnion) partitionedBackups() []tree.PartitionedBackup {
 func (u *sqlSymUnion) geoFigure() geopb.Shape {
   return u.val.(geopb.Shape)
 }
+func (u *sqlSymUnion) triggerActionTime() tree.TriggerActionTime {
+    return u.val.(tree.TriggerActionTime)
+}
+func (u *sqlSymUnion) triggerEvents() tree.TriggerEvents {
+    return u.val.(tree.TriggerEvents)
+}
+func (u *sqlSymUnion) funcArg() tree.FuncArg {
+    return u.val.(tree.FuncArg)
+}
+func (u *sqlSymUnion) funcArgs() tree.FuncArgs {
+    return u.val.(tree.FuncArgs)
+}
+func (u *sqlSymUnion) functionOptions() *tree.FunctionOptions {
+    return u.val.(*tree.FunctionOptions)
+}
+func (u *sqlSymUnion) funcObj() tree.FuncObj {
+    return u.val.(tree.FuncObj)
+}
+func (u *sqlSymUnion) funcObjs() tree.FuncObjs {
+    return u.val.(tree.FuncObjs)
+}
 func newNameFromStr(s string) *tree.Name {
     return (*tree.Name)(&s)
 }
@@ -533,11 +569,11 @@ func newNameFromStr(s string) *tree.Name {
 // below; search this file for "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str> ABORT ACTION ADD ADMIN AGGREGATE
+%token <str> ABORT ACTION ADD ADMIN AFTER AGGREGATE
 %token <str> ALL ALTER ALWAYS ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC
 %token <str> ASYMMETRIC AT AUTHORIZATION AUTOMATIC
 
-%token <str> BACKUP BEGIN BETWEEN BIGINT BIGSERIAL BIT
+%token <str> BACKUP BACKUPS BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BIT
 %token <str> BUCKET_COUNT
 %token <str> BOOLEAN BOTH BUNDLE BY
 
@@ -554,7 +590,7 @@ func newNameFromStr(s string) *tree.Name {
 %token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DESC
 %token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
-%token <str> ELSE ENCODING END ENUM ESCAPE EXCEPT EXCLUDE EXCLUDING
+%token <str> EACH ELSE ENCODING END ENUM ESCAPE EXCEPT EXCLUDE EXCLUDING
 %token <str> EXISTS EXECUTE EXPERIMENTAL
 %token <str> EXPERIMENTAL_FINGERPRINTS EXPERIMENTAL_REPLICA
 %token <str> EXPERIMENTAL_AUDIT
@@ -570,7 +606,7 @@ func newNameFromStr(s string) *tree.Name {
 %token <str> HAVING HASH HIGH HISTOGRAM HOUR
 
 %token <str> IDENTITY
-%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMPORT IN INCLUDE INCLUDING INCREMENT INCREMENTAL
+%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMMUTABLE IMPORT IN INCLUDE INCLUDING INCREMENT INCREMENTAL
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INJECT INTERLEAVE INITIALLY
 %token <str> INNER INSERT INT INTEGER
@@ -580,11 +616,11 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str> KEY KEYS KV
 
-%token <str> LANGUAGE LAST LATERAL LC_CTYPE LC_COLLATE
+%token <str> LANGUAGE LAST LATERAL LATEST LC_CTYPE LC_COLLATE
 %token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT LINESTRING LIST LOCAL
 %token <str> LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
 
-%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE MINUTE MONTH
+%token <str> MATCH MATCHED MATERIALIZED MERGE MINVALUE MAXVALUE MINUTE MONTH
 %token <str> MULTILINESTRING MULTIPOINT MULTIPOLYGON
 
 %token <str> NAN NAME NAMES NATURAL NEXT NO NOCREATEROLE NOLOGIN NO_INDEX_JOIN
@@ -599,17 +635,17 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str> QUERIES QUERY
 
-%token <str> RANGE RANGES READ REAL RECURSIVE REF REFERENCES
+%token <str> RANGE RANGES READ REAL RECURSIVE REF REFERENCES REFRESH
 %token <str> REGCLASS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE REINDEX
 %token <str> REMOVE_PATH RENAME REPEATABLE REPLACE
-%token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING REVOKE RIGHT
+%token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING RETURNS REVERT REVOKE RIGHT
 %token <str> ROLE ROLES ROLLBACK ROLLUP ROW ROWS RSHIFT RULE
 
 %token <str> SAVEPOINT SCATTER SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
-%token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETTING SETTINGS
+%token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETOF SETTING SETTINGS
 %token <str> SHARE SHOW SIMILAR SIMPLE SKIP SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
-%token <str> START STATISTICS STATUS STDIN STRICT STRING STORAGE STORE STORED STORING SUBSTRING
+%token <str> STABLE START STATISTICS STATUS STDIN STRICT STRING STORAGE STORE STORED STORING SUBSTRING
 %token <str> SYMMETRIC SYNTAX SYSTEM SUBSCRIPTION
 
 %token <str> TABLE TABLES TEMP TEMPLATE TEMPORARY TESTING_RELOCATE EXPERIMENTAL_RELOCATE TEXT THEN
@@ -620,7 +656,7 @@ func newNameFromStr(s string) *tree.Name {
 %token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLOGGED UNSPLIT
 %token <str> UPDATE UPSERT UNTIL USE USER USERS USING UUID
 
-%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIRTUAL
+%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIRTUAL VOLATILE
 
 %token <str> WHEN WHERE WINDOW WITH WITHIN WITHOUT WORK WRITE
 
@@ -668,6 +704,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> alter_unsplit_stmt
 %type <tree.Statement> alter_rename_table_stmt
 %type <tree.Statement> alter_scatter_stmt
+%type <tree.Statement> alter_revert_table_stmt
 %type <tree.Statement> alter_relocate_stmt
 %type <tree.Statement> alter_relocate_lease_stmt
 %type <tree.Statement> alter_zone_table_stmt
@@ -726,6 +763,17 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> create_table_stmt
 %type <tree.Statement> create_table_as_stmt
 %type <tree.Statement> create_view_stmt
+%type <tree.Statement> create_trigger_stmt
+%type <tree.Statement> create_function_stmt
+%type <tree.FuncArgs> opt_func_arg_list func_arg_list
+%type <tree.FuncArg> func_arg
+%type <*tree.FunctionOptions> func_option_list func_option
+%type <bool> opt_setof
+%type <tree.TriggerActionTime> trigger_action_time
+%type <tree.TriggerEvents> trigger_event_list trigger_event
+%type <tree.Expr> opt_trigger_when
+%type <tree.UpdateExprs> trigger_set_action
+%type <tree.Statement> trigger_statement
 %type <tree.Statement> create_sequence_stmt
 
 %type <tree.Statement> create_stats_stmt
@@ -745,6 +793,10 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> drop_table_stmt
 %type <tree.Statement> drop_view_stmt
 %type <tree.Statement> drop_sequence_stmt
+%type <tree.Statement> drop_trigger_stmt
+%type <tree.Statement> drop_function_stmt
+%type <tree.FuncObjs> func_obj_list
+%type <tree.FuncObj> func_obj
 
 %type <tree.Statement> explain_stmt
 %type <tree.Statement> prepare_stmt
@@ -755,6 +807,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> deallocate_stmt
 %type <tree.Statement> grant_stmt
 %type <tree.Statement> insert_stmt
+%type <tree.Statement> merge_stmt
 %type <tree.Statement> import_stmt
 %type <tree.Statement> pause_stmt
 %type <tree.Statement> release_stmt
@@ -773,6 +826,8 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> set_session_stmt
 %type <tree.Statement> set_csetting_stmt
 %type <tree.Statement> set_transaction_stmt
+%type <tree.Statement> set_constraints_stmt
+%type <bool> constraints_set_mode
 %type <tree.Statement> set_exprs_internal
 %type <tree.Statement> generic_set
 %type <tree.Statement> set_rest_more
@@ -819,6 +874,7 @@ func newNameFromStr(s string) *tree.Name {
 
 %type <tree.Statement> close_cursor_stmt
 %type <tree.Statement> declare_cursor_stmt
+%type <tree.Statement> refresh_stmt
 %type <tree.Statement> reindex_stmt
 
 %type <[]string> opt_incremental
@@ -870,7 +926,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <str> schema_name
 %type <*tree.UnresolvedName> table_pattern complex_table_pattern
 %type <*tree.UnresolvedName> column_path prefixed_column_path column_path_with_star
-%type <tree.TableExpr> insert_target create_stats_target
+%type <tree.TableExpr> insert_target merge_target create_stats_target
 
 %type <*tree.TableIndexName> table_index_name
 %type <tree.TableIndexNames> table_index_name_list
@@ -901,7 +957,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.NameList> name_list privilege_list
 %type <[]int32> opt_array_bounds
 %type <tree.From> from_clause
-%type <tree.TableExprs> from_list rowsfrom_list opt_from_list
+%type <tree.TableExprs> from_list rowsfrom_list opt_from_list opt_using_clause
 %type <tree.TablePatterns> table_pattern_list single_table_pattern_list
 %type <tree.TableNames> table_name_list opt_locked_rels
 %type <tree.Exprs> expr_list opt_expr_list tuple1_ambiguous_values tuple1_unambiguous_values
@@ -909,13 +965,15 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.NameList> attrs
 %type <tree.SelectExprs> target_list
 %type <tree.UpdateExprs> set_clause_list
+%type <tree.MergeWhens> merge_when_list
+%type <*tree.MergeWhen> merge_when_clause merge_not_matched_action
+%type <tree.Expr> opt_merge_condition
 %type <*tree.UpdateExpr> set_clause multiple_set_clause
 %type <tree.ArraySubscripts> array_subscripts
 %type <tree.GroupBy> group_clause
 %type <*tree.Limit> select_limit opt_select_limit
 %type <tree.TableNames> relation_expr_list
 %type <tree.ReturningClause> returning_clause
-%type <empty> opt_using_clause
 
 %type <[]tree.SequenceOption> sequence_option_list opt_sequence_option_list
 %type <tree.SequenceOption> sequence_option_elem
@@ -979,6 +1037,10 @@ func newNameFromStr(s string) *tree.Name {
 %type <bool> opt_ordinality opt_compact
 %type <*tree.Order> sortby
 %type <tree.IndexElem> index_elem create_as_param
+%type <tree.ExcludeElem> exclude_elem
+%type <tree.ExcludeElemList> exclude_elem_list
+%type <str> opt_exclude_using
+%type <tree.Expr> opt_exclude_where
 %type <tree.TableExpr> table_ref numeric_table_ref func_table
 %type <tree.Exprs> rowsfrom_list
 %type <tree.Expr> rowsfrom_item
@@ -1034,6 +1096,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.NamedColumnQualification> col_qualification create_as_col_qualification
 %type <tree.ColumnQualification> col_qualification_elem create_as_col_qualification_elem
 %type <tree.CompositeKeyMatchMethod> key_match
+%type <tree.ConstraintDeferrability> opt_deferrable deferrable_mode
 %type <tree.ReferenceActions> reference_actions
 %type <tree.ReferenceAction> reference_action reference_on_delete reference_on_update
 
@@ -1044,7 +1107,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <[]*tree.CTE> cte_list
 %type <*tree.CTE> common_table_expr
 
-%type <empty> within_group_clause
+%type <tree.OrderBy> within_group_clause
 %type <tree.Expr> filter_clause
 %type <tree.Exprs> opt_partition_clause
 %type <tree.Window> window_clause window_definition_list
@@ -1209,6 +1272,7 @@ alter_ddl_stmt:
 //   ALTER TABLE ... UNSPLIT AT <selectclause>
 //   ALTER TABLE ... UNSPLIT ALL
 //   ALTER TABLE ... SCATTER [ FROM ( <exprs...> ) TO ( <exprs...> ) ]
+//   ALTER TABLE ... REVERT TO SYSTEM TIME <expr>
 //   ALTER TABLE ... INJECT STATISTICS ...  (experimental)
 //   ALTER TABLE ... PARTITION BY RANGE ( <name...> ) ( <rangespec> )
 //   ALTER TABLE ... PARTITION BY LIST ( <name...> ) ( <listspec> )
@@ -1235,6 +1299,7 @@ alter_table_stmt:
 | alter_split_stmt
 | alter_unsplit_stmt
 | alter_scatter_stmt
+| alter_revert_table_stmt
 | alter_zone_table_stmt
 | alter_rename_table_stmt
 // ALTER TABLE has its error help token here because the ALTER TABLE
@@ -1620,6 +1685,12 @@ alter_scatter_stmt:
     }
   }
 
+alter_revert_table_stmt:
+  ALTER TABLE table_name REVERT TO SYSTEM TIME a_expr
+  {
+    $$.val = &tree.RevertTable{Table: $3.unresolvedObjectName(), Timestamp: $8.expr()}
+  }
+
 alter_scatter_index_stmt:
   ALTER INDEX table_index_name SCATTER
   {
@@ -1862,6 +1933,10 @@ opt_validate_behavior:
 //        [ INCREMENTAL FROM <location...> ]
 //        [ WITH <option> [= <value>] [, ...] ]
 //
+// BACKUP <targets...> INTO [ LATEST IN ] <collection...>
+//        [ AS OF SYSTEM TIME <expr> ]
+//        [ WITH <option> [= <value>] [, ...] ]
+//
 // Targets:
 //    TABLE <pattern> [, ...]
 //    DATABASE <databasename> [, ...]
@@ -1883,6 +1958,22 @@ backup_stmt:
   {
     $$.val = &tree.Backup{Targets: $2.targetList(), To: $4.partitionedBackup(), IncrementalFrom: $6.exprs(), AsOf: $5.asOfClause(), Options: $7.kvOptions()}
   }
+| BACKUP INTO partitioned_backup opt_as_of_clause opt_with_options
+  {
+    $$.val = &tree.Backup{DescriptorCoverage: tree.AllDescriptors, To: $3.partitionedBackup(), Nested: true, AsOf: $4.asOfClause(), Options: $5.kvOptions()}
+  }
+| BACKUP targets INTO partitioned_backup opt_as_of_clause opt_with_options
+  {
+    $$.val = &tree.Backup{Targets: $2.targetList(), To: $4.partitionedBackup(), Nested: true, AsOf: $5.asOfClause(), Options: $6.kvOptions()}
+  }
+| BACKUP INTO LATEST IN partitioned_backup opt_as_of_clause opt_with_options
+  {
+    $$.val = &tree.Backup{DescriptorCoverage: tree.AllDescriptors, To: $5.partitionedBackup(), Nested: true, AppendToLatest: true, AsOf: $6.asOfClause(), Options: $7.kvOptions()}
+  }
+| BACKUP targets INTO LATEST IN partitioned_backup opt_as_of_clause opt_with_options
+  {
+    $$.val = &tree.Backup{Targets: $2.targetList(), To: $6.partitionedBackup(), Nested: true, AppendToLatest: true, AsOf: $7.asOfClause(), Options: $8.kvOptions()}
+  }
 | BACKUP error // SHOW HELP: BACKUP
 
 // %Help: RESTORE - restore data from external storage
@@ -1892,6 +1983,14 @@ backup_stmt:
 //         [ AS OF SYSTEM TIME <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
 //
+// RESTORE <targets...> FROM { LATEST | <subdirectory> } IN <collection...>
+//         [ AS OF SYSTEM TIME <expr> ]
+//         [ WITH <option> [= <value>] [, ...] ]
+//
+// RESTORE TABLE <tablename> AS <newname> FROM <location...>
+//         [ AS OF SYSTEM TIME <expr> ]
+//         [ WITH <option> [= <value>] [, ...] ]
+//
 // Targets:
 //    TABLE <pattern> [, ...]
 //    DATABASE <databasename> [, ...]
@@ -1902,6 +2001,8 @@ backup_stmt:
 // Options:
 //    INTO_DB
 //    SKIP_MISSING_FOREIGN_KEYS
+//    VERIFY_ONLY: check the backup could be restored, without restoring it
+//    VERIFY_ROWS: with VERIFY_ONLY, also check every row in the backup
 //
 // %SeeAlso: BACKUP, WEBDOCS/restore.html
 restore_stmt:
@@ -1913,6 +2014,24 @@ restore_stmt:
   {
     $$.val = &tree.Restore{Targets: $2.targetList(), From: $4.partitionedBackups(), AsOf: $5.asOfClause(), Options: $6.kvOptions()}
   }
+| RESTORE FROM string_or_placeholder IN partitioned_backup opt_as_of_clause opt_with_options
+  {
+    $$.val = &tree.Restore{DescriptorCoverage: tree.AllDescriptors, Subdir: $3.expr(), From: []tree.PartitionedBackup{$5.partitionedBackup()}, AsOf: $6.asOfClause(), Options: $7.kvOptions()}
+  }
+| RESTORE targets FROM string_or_placeholder IN partitioned_backup opt_as_of_clause opt_with_options
+  {
+    $$.val = &tree.Restore{Targets: $2.targetList(), Subdir: $4.expr(), From: []tree.PartitionedBackup{$6.partitionedBackup()}, AsOf: $7.asOfClause(), Options: $8.kvOptions()}
+  }
+| RESTORE TABLE table_name AS table_name FROM partitioned_backup_list opt_as_of_clause opt_with_options
+  {
+    $$.val = &tree.Restore{
+      Targets: tree.TargetList{Tables: tree.TablePatterns{$3.unresolvedObjectName().ToUnresolvedName()}},
+      AsTable: $5.unresolvedObjectName(),
+      From: $7.partitionedBackups(),
+      AsOf: $8.asOfClause(),
+      Options: $9.kvOptions(),
+    }
+  }
 | RESTORE error // SHOW HELP: RESTORE
 
 partitioned_backup:
@@ -2268,7 +2387,7 @@ comment_text:
 // %Text:
 // CREATE DATABASE, CREATE TABLE, CREATE INDEX, CREATE TABLE AS,
 // CREATE USER, CREATE VIEW, CREATE SEQUENCE, CREATE STATISTICS,
-// CREATE ROLE
+// CREATE TRIGGER, CREATE FUNCTION, CREATE ROLE
 create_stmt:
   create_role_stmt     // EXTEND WITH HELP: CREATE ROLE
 | create_ddl_stmt      // help texts in sub-rule
@@ -2286,17 +2405,13 @@ create_unsupported:
 | CREATE EXTENSION name error { return unimplemented(sqllex, "create extension " + $3) }
 | CREATE FOREIGN TABLE error { return unimplemented(sqllex, "create foreign table") }
 | CREATE FOREIGN DATA error { return unimplemented(sqllex, "create fdw") }
-| CREATE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
-| CREATE OR REPLACE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
 | CREATE opt_or_replace opt_trusted opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "create language " + $6) }
-| CREATE MATERIALIZED VIEW error { return unimplementedWithIssue(sqllex, 41649) }
 | CREATE OPERATOR error { return unimplemented(sqllex, "create operator") }
 | CREATE PUBLICATION error { return unimplemented(sqllex, "create publication") }
 | CREATE opt_or_replace RULE error { return unimplemented(sqllex, "create rule") }
 | CREATE SERVER error { return unimplemented(sqllex, "create server") }
 | CREATE SUBSCRIPTION error { return unimplemented(sqllex, "create subscription") }
 | CREATE TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "create text") }
-| CREATE TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create") }
 
 opt_or_replace:
   OR REPLACE {}
@@ -2320,7 +2435,6 @@ drop_unsupported:
 | DROP EXTENSION name error { return unimplemented(sqllex, "drop extension " + $3) }
 | DROP FOREIGN TABLE error { return unimplemented(sqllex, "drop foreign table") }
 | DROP FOREIGN DATA error { return unimplemented(sqllex, "drop fdw") }
-| DROP FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "drop function") }
 | DROP opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "drop language " + $4) }
 | DROP OPERATOR error { return unimplemented(sqllex, "drop operator") }
 | DROP PUBLICATION error { return unimplemented(sqllex, "drop publication") }
@@ -2330,7 +2444,6 @@ drop_unsupported:
 | DROP SUBSCRIPTION error { return unimplemented(sqllex, "drop subscription") }
 | DROP TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "drop text") }
 | DROP TYPE error { return unimplementedWithIssueDetail(sqllex, 27793, "drop type") }
-| DROP TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "drop") }
 
 create_ddl_stmt:
   create_changefeed_stmt
@@ -2344,6 +2457,8 @@ create_ddl_stmt:
 | create_type_stmt     { /* SKIP DOC */ }
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
+| create_trigger_stmt  // EXTEND WITH HELP: CREATE TRIGGER
+| create_function_stmt // EXTEND WITH HELP: CREATE FUNCTION
 
 // %Help: CREATE STATISTICS - create a new table statistic
 // %Category: Misc
@@ -2492,7 +2607,7 @@ opt_changefeed_sink:
 
 // %Help: DELETE - delete rows from a table
 // %Category: DML
-// %Text: DELETE FROM <tablename> [WHERE <expr>]
+// %Text: DELETE FROM <tablename> [USING <tablerefs...>] [WHERE <expr>]
 //               [ORDER BY <exprs...>]
 //               [LIMIT <expr>]
 //               [RETURNING <exprs...>]
@@ -2503,6 +2618,7 @@ delete_stmt:
     $$.val = &tree.Delete{
       With: $1.with(),
       Table: $4.tblExpr(),
+      Using: $5.tblExprs(),
       Where: tree.NewWhere(tree.AstWhere, $6.expr()),
       OrderBy: $7.orderBy(),
       Limit: $8.limit(),
@@ -2512,8 +2628,14 @@ delete_stmt:
 | opt_with_clause DELETE error // SHOW HELP: DELETE
 
 opt_using_clause:
-  USING from_list { return unimplementedWithIssueDetail(sqllex, 40963, "delete using") }
-| /* EMPTY */ { }
+  USING from_list
+  {
+    $$.val = $2.tblExprs()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.TableExprs{}
+  }
 
 
 // %Help: DISCARD - reset the session to its initial state
@@ -2534,7 +2656,7 @@ discard_stmt:
 // %Category: Group
 // %Text:
 // DROP DATABASE, DROP INDEX, DROP TABLE, DROP VIEW, DROP SEQUENCE,
-// DROP USER, DROP ROLE
+// DROP TRIGGER, DROP FUNCTION, DROP USER, DROP ROLE
 drop_stmt:
   drop_ddl_stmt      // help texts in sub-rule
 | drop_role_stmt     // EXTEND WITH HELP: DROP ROLE
@@ -2547,10 +2669,12 @@ drop_ddl_stmt:
 | drop_table_stmt    // EXTEND WITH HELP: DROP TABLE
 | drop_view_stmt     // EXTEND WITH HELP: DROP VIEW
 | drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
+| drop_trigger_stmt  // EXTEND WITH HELP: DROP TRIGGER
+| drop_function_stmt // EXTEND WITH HELP: DROP FUNCTION
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
-// %Text: DROP VIEW [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
+// %Text: DROP [MATERIALIZED] VIEW [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
 // %SeeAlso: WEBDOCS/drop-index.html
 drop_view_stmt:
   DROP VIEW table_name_list opt_drop_behavior
@@ -2561,6 +2685,24 @@ drop_view_stmt:
   {
     $$.val = &tree.DropView{Names: $5.tableNames(), IfExists: true, DropBehavior: $6.dropBehavior()}
   }
+| DROP MATERIALIZED VIEW table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropView{
+      Names: $4.tableNames(),
+      IfExists: false,
+      DropBehavior: $5.dropBehavior(),
+      IsMaterialized: true,
+    }
+  }
+| DROP MATERIALIZED VIEW IF EXISTS table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropView{
+      Names: $6.tableNames(),
+      IfExists: true,
+      DropBehavior: $7.dropBehavior(),
+      IsMaterialized: true,
+    }
+  }
 | DROP VIEW error // SHOW HELP: DROP VIEW
 
 // %Help: DROP SEQUENCE - remove a sequence
@@ -2578,6 +2720,70 @@ drop_sequence_stmt:
   }
 | DROP SEQUENCE error // SHOW HELP: DROP VIEW
 
+// %Help: DROP TRIGGER - remove a trigger
+// %Category: DDL
+// %Text: DROP TRIGGER [IF EXISTS] <name> ON <tablename>
+// %SeeAlso: CREATE TRIGGER
+drop_trigger_stmt:
+  DROP TRIGGER name ON table_name
+  {
+    $$.val = &tree.DropTrigger{
+      Name: tree.Name($3),
+      Table: $5.unresolvedObjectName().ToTableName(),
+      IfExists: false,
+    }
+  }
+| DROP TRIGGER IF EXISTS name ON table_name
+  {
+    $$.val = &tree.DropTrigger{
+      Name: tree.Name($5),
+      Table: $7.unresolvedObjectName().ToTableName(),
+      IfExists: true,
+    }
+  }
+| DROP TRIGGER error // SHOW HELP: DROP TRIGGER
+
+// %Help: DROP FUNCTION - remove a user-defined function
+// %Category: DDL
+// %Text: DROP FUNCTION [IF EXISTS] <name> [ ( [<argtype> [, ...]] ) ] [, ...]
+// %SeeAlso: CREATE FUNCTION
+drop_function_stmt:
+  DROP FUNCTION func_obj_list
+  {
+    $$.val = &tree.DropFunction{Functions: $3.funcObjs(), IfExists: false}
+  }
+| DROP FUNCTION IF EXISTS func_obj_list
+  {
+    $$.val = &tree.DropFunction{Functions: $5.funcObjs(), IfExists: true}
+  }
+| DROP FUNCTION error // SHOW HELP: DROP FUNCTION
+
+func_obj_list:
+  func_obj
+  {
+    $$.val = tree.FuncObjs{$1.funcObj()}
+  }
+| func_obj_list ',' func_obj
+  {
+    $$.val = append($1.funcObjs(), $3.funcObj())
+  }
+
+func_obj:
+  db_object_name
+  {
+    $$.val = tree.FuncObj{Name: $1.unresolvedObjectName()}
+  }
+| db_object_name '(' opt_func_arg_list ')'
+  {
+    // As in Postgres, the names of the arguments are accepted but ignored.
+    args := $3.funcArgs()
+    argTypes := make([]*types.T, len(args))
+    for i := range args {
+      argTypes[i] = args[i].Type
+    }
+    $$.val = tree.FuncObj{Name: $1.unresolvedObjectName(), Args: argTypes, ArgsSpecified: true}
+  }
+
 // %Help: DROP TABLE - remove a table
 // %Category: DDL
 // %Text: DROP TABLE [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
@@ -2750,7 +2956,9 @@ preparable_stmt:
 | explain_stmt      // EXTEND WITH HELP: EXPLAIN
 | import_stmt       // EXTEND WITH HELP: IMPORT
 | insert_stmt       // EXTEND WITH HELP: INSERT
+| merge_stmt        // EXTEND WITH HELP: MERGE
 | pause_stmt        // EXTEND WITH HELP: PAUSE JOBS
+| refresh_stmt      // EXTEND WITH HELP: REFRESH
 | reset_stmt        // help texts in sub-rule
 | restore_stmt      // EXTEND WITH HELP: RESTORE
 | resume_stmt       // EXTEND WITH HELP: RESUME JOBS
@@ -2889,11 +3097,12 @@ deallocate_stmt:
 //   GRANT <roles...> TO <grantees...> [WITH ADMIN OPTION]
 //
 // Privileges:
-//   CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE
+//   CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE, EXECUTE
 //
 // Targets:
 //   DATABASE <databasename> [, ...]
 //   [TABLE] [<databasename> .] { <tablename> | * } [, ...]
+//   FUNCTION <funcname> [ ( [<argtype> [, ...]] ) ] [, ...]
 //
 // %SeeAlso: REVOKE, WEBDOCS/grant.html
 grant_stmt:
@@ -2920,11 +3129,12 @@ grant_stmt:
 //   REVOKE [ADMIN OPTION FOR] <roles...> FROM <grantees...>
 //
 // Privileges:
-//   CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE
+//   CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE, EXECUTE
 //
 // Targets:
 //   DATABASE <databasename> [, <databasename>]...
 //   [TABLE] [<databasename> .] { <tablename> | * } [, ...]
+//   FUNCTION <funcname> [ ( [<argtype> [, ...]] ) ] [, ...]
 //
 // %SeeAlso: GRANT, WEBDOCS/revoke.html
 revoke_stmt:
@@ -3024,7 +3234,7 @@ use_stmt:
 nonpreparable_set_stmt:
   set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
 | set_exprs_internal   { /* SKIP DOC */ }
-| SET CONSTRAINTS error { return unimplemented(sqllex, "set constraints") }
+| set_constraints_stmt // EXTEND WITH HELP: SET CONSTRAINTS
 | SET LOCAL error { return unimplementedWithIssue(sqllex, 32562) }
 
 // SET SESSION / SET CLUSTER SETTING
@@ -3207,6 +3417,35 @@ set_transaction_stmt:
   }
 | SET SESSION TRANSACTION error // SHOW HELP: SET TRANSACTION
 
+// %Help: SET CONSTRAINTS - set the checking mode of deferrable constraints
+// %Category: Txn
+// %Text:
+// SET CONSTRAINTS { ALL | <constraintname> [, ...] } { DEFERRED | IMMEDIATE }
+//
+// The checks of deferred constraints are postponed until the end of the
+// current transaction. Only constraints declared DEFERRABLE can be deferred.
+// %SeeAlso: SET TRANSACTION, CREATE TABLE
+set_constraints_stmt:
+  SET CONSTRAINTS ALL constraints_set_mode
+  {
+    $$.val = &tree.SetConstraints{All: true, Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS name_list constraints_set_mode
+  {
+    $$.val = &tree.SetConstraints{Names: $3.nameList(), Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS error // SHOW HELP: SET CONSTRAINTS
+
+constraints_set_mode:
+  DEFERRED
+  {
+    $$.val = true
+  }
+| IMMEDIATE
+  {
+    $$.val = false
+  }
+
 generic_set:
   var_name to_or_eq var_list
   {
@@ -3433,6 +3672,20 @@ close_cursor_stmt:
 declare_cursor_stmt:
 	DECLARE { return unimplementedWithIssue(sqllex, 41412) }
 
+// %Help: REFRESH - recompute a materialized view
+// %Category: Misc
+// %Text: REFRESH MATERIALIZED VIEW [CONCURRENTLY] <viewname>
+// %SeeAlso: CREATE VIEW
+refresh_stmt:
+  REFRESH MATERIALIZED VIEW opt_concurrently view_name
+  {
+    $$.val = &tree.RefreshMaterializedView{
+      Name: $5.unresolvedObjectName(),
+      Concurrently: $4.bool(),
+    }
+  }
+| REFRESH error // SHOW HELP: REFRESH
+
 reindex_stmt:
   REINDEX TABLE error
   {
@@ -3522,10 +3775,21 @@ show_histogram_stmt:
 
 // %Help: SHOW BACKUP - list backup contents
 // %Category: CCL
-// %Text: SHOW BACKUP [SCHEMAS|FILES|RANGES] <location>
+// %Text:
+// SHOW BACKUP [SCHEMAS|FILES|RANGES] <location>
+// SHOW BACKUP { LATEST | <subdirectory> } IN <collection>
+// SHOW BACKUP CHECK <location> [WITH verify_rows]
+// SHOW BACKUP CHECK { LATEST | <subdirectory> } IN <collection> [WITH verify_rows]
+// SHOW BACKUPS IN <collection>
 // %SeeAlso: WEBDOCS/show-backup.html
 show_backup_stmt:
-  SHOW BACKUP string_or_placeholder opt_with_options
+  SHOW BACKUPS IN string_or_placeholder
+  {
+    $$.val = &tree.ShowBackup{
+      InCollection: $4.expr(),
+    }
+  }
+| SHOW BACKUP string_or_placeholder opt_with_options
   {
     $$.val = &tree.ShowBackup{
       Details: tree.BackupDefaultDetails,
@@ -3533,6 +3797,15 @@ show_backup_stmt:
       Options: $4.kvOptions(),
     }
   }
+| SHOW BACKUP string_or_placeholder IN string_or_placeholder opt_with_options
+  {
+    $$.val = &tree.ShowBackup{
+      Details:      tree.BackupDefaultDetails,
+      Path:         $3.expr(),
+      InCollection: $5.expr(),
+      Options:      $6.kvOptions(),
+    }
+  }
 | SHOW BACKUP SCHEMAS string_or_placeholder opt_with_options
   {
     $$.val = &tree.ShowBackup{
@@ -3560,6 +3833,23 @@ show_backup_stmt:
       Options: $5.kvOptions(),
     }
   }
+| SHOW BACKUP CHECK string_or_placeholder opt_with_options
+  {
+    $$.val = &tree.ShowBackup{
+      Details: tree.BackupCheckDetails,
+      Path:    $4.expr(),
+      Options: $5.kvOptions(),
+    }
+  }
+| SHOW BACKUP CHECK string_or_placeholder IN string_or_placeholder opt_with_options
+  {
+    $$.val = &tree.ShowBackup{
+      Details:      tree.BackupCheckDetails,
+      Path:         $4.expr(),
+      InCollection: $6.expr(),
+      Options:      $7.kvOptions(),
+    }
+  }
 | SHOW BACKUP error // SHOW HELP: SHOW BACKUP
 
 // %Help: SHOW CLUSTER SETTING - display cluster settings
@@ -4258,6 +4548,10 @@ targets:
   {
     $$.val = tree.TargetList{Databases: $2.nameList()}
   }
+| FUNCTION func_obj_list
+  {
+    $$.val = tree.TargetList{Functions: $2.funcObjs()}
+  }
 
 // target_roles is the variant of targets which recognizes ON ROLES
 // with a name list. This cannot be included in targets directly
@@ -4419,15 +4713,15 @@ opt_create_table_on_commit:
     /* SKIP DOC */
     $$.val = tree.CreateTableOnCommitPreserveRows
   }
-| ON COMMIT DELETE ROWS error
+| ON COMMIT DELETE ROWS
   {
     /* SKIP DOC */
-    return unimplementedWithIssueDetail(sqllex, 46556, "delete rows")
+    $$.val = tree.CreateTableOnCommitDeleteRows
   }
-| ON COMMIT DROP error
+| ON COMMIT DROP
   {
     /* SKIP DOC */
-    return unimplementedWithIssueDetail(sqllex, 46556, "drop")
+    $$.val = tree.CreateTableOnCommitDrop
   }
 
 storage_parameter:
@@ -4767,6 +5061,10 @@ col_qualification_elem:
   {
     $$.val = tree.UniqueConstraint{}
   }
+| UNIQUE deferrable_mode
+  {
+    $$.val = tree.UniqueConstraint{Deferrability: $2.constraintDeferrability()}
+  }
 | PRIMARY KEY
   {
     $$.val = tree.PrimaryKeyConstraint{}
@@ -4786,7 +5084,7 @@ col_qualification_elem:
   {
     $$.val = &tree.ColumnDefault{Expr: $2.expr()}
   }
-| REFERENCES table_name opt_name_parens key_match reference_actions
+| REFERENCES table_name opt_name_parens key_match reference_actions opt_deferrable
  {
     name := $2.unresolvedObjectName().ToTableName()
     $$.val = &tree.ColumnFKConstraint{
@@ -4794,6 +5092,7 @@ col_qualification_elem:
       Col: tree.Name($3),
       Actions: $5.referenceActions(),
       Match: $4.compositeKeyMatchMethod(),
+      Deferrability: $6.constraintDeferrability(),
     }
  }
 | generated_as '(' a_expr ')' STORED
@@ -4802,11 +5101,11 @@ col_qualification_elem:
  }
 | generated_as '(' a_expr ')' VIRTUAL
  {
-    return unimplemented(sqllex, "virtual computed columns")
+    $$.val = &tree.ColumnComputedDef{Expr: $3.expr(), Virtual: true}
  }
 | generated_as error
  {
-    sqllex.Error("use AS ( <expr> ) STORED")
+    sqllex.Error("use AS ( <expr> ) STORED or AS ( <expr> ) VIRTUAL")
     return 1
  }
 
@@ -4876,11 +5175,26 @@ table_constraint:
 constraint_elem:
   CHECK '(' a_expr ')' opt_deferrable
   {
+    if $5.constraintDeferrability() != tree.NotDeferrable {
+      sqllex.Error("CHECK constraints cannot be marked DEFERRABLE")
+      return 1
+    }
     $$.val = &tree.CheckConstraintTableDef{
       Expr: $3.expr(),
     }
   }
-| UNIQUE '(' index_params ')' opt_storing opt_interleave opt_partition_by  opt_deferrable
+| UNIQUE '(' index_params ')' opt_storing opt_interleave opt_partition_by
+  {
+    $$.val = &tree.UniqueConstraintTableDef{
+      IndexTableDef: tree.IndexTableDef{
+        Columns: $3.idxElems(),
+        Storing: $5.nameList(),
+        Interleave: $6.interleave(),
+        PartitionBy: $7.partitionBy(),
+      },
+    }
+  }
+| UNIQUE '(' index_params ')' opt_storing opt_interleave opt_partition_by deferrable_mode
   {
     $$.val = &tree.UniqueConstraintTableDef{
       IndexTableDef: tree.IndexTableDef{
@@ -4889,6 +5203,7 @@ constraint_elem:
         Interleave: $6.interleave(),
         PartitionBy: $7.partitionBy(),
       },
+      Deferrability: $8.constraintDeferrability(),
     }
   }
 | PRIMARY KEY '(' index_params ')' opt_hash_sharded opt_interleave
@@ -4912,11 +5227,64 @@ constraint_elem:
       ToCols: $8.nameList(),
       Match: $9.compositeKeyMatchMethod(),
       Actions: $10.referenceActions(),
+      Deferrability: $11.constraintDeferrability(),
     }
   }
-| EXCLUDE USING error
+| EXCLUDE opt_exclude_using '(' exclude_elem_list ')' opt_exclude_where
   {
-    return unimplementedWithIssueDetail(sqllex, 46657, "add constraint exclude using")
+    $$.val = &tree.ExcludeConstraintTableDef{
+      Using: tree.Name($2),
+      Elems: $4.excludeElems(),
+      Where: $6.expr(),
+    }
+  }
+
+opt_exclude_using:
+  USING name
+  {
+    switch $2 {
+      case "gist", "btree":
+        $$ = $2
+      default:
+        sqllex.Error("access method \"" + $2 + "\" is not supported for EXCLUDE constraints")
+        return 1
+    }
+  }
+| /* EMPTY */
+  {
+    $$ = ""
+  }
+
+exclude_elem_list:
+  exclude_elem
+  {
+    $$.val = tree.ExcludeElemList{$1.excludeElem()}
+  }
+| exclude_elem_list ',' exclude_elem
+  {
+    $$.val = append($1.excludeElems(), $3.excludeElem())
+  }
+
+// An EXCLUDE constraint element compares an expression between two rows
+// using either equality or overlap (&&).
+exclude_elem:
+  a_expr WITH '='
+  {
+    $$.val = tree.ExcludeElem{Expr: $1.expr(), Operator: tree.EQ}
+  }
+| a_expr WITH AND_AND
+  {
+    $$.val = tree.ExcludeElem{Expr: $1.expr(), Operator: tree.Overlaps}
+  }
+
+opt_exclude_where:
+  WHERE '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
   }
 
 
@@ -5021,13 +5389,48 @@ create_as_col_qualification_elem:
     $$.val = tree.PrimaryKeyConstraint{}
   }
 
+// INITIALLY DEFERRED implies DEFERRABLE, as in Postgres.
 opt_deferrable:
-  /* EMPTY */ { /* no error */ }
-| DEFERRABLE { return unimplementedWithIssueDetail(sqllex, 31632, "deferrable") }
-| DEFERRABLE INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "def initially deferred") }
-| DEFERRABLE INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "def initially immediate") }
-| INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "initially deferred") }
-| INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "initially immediate") }
+  /* EMPTY */
+  {
+    $$.val = tree.NotDeferrable
+  }
+| deferrable_mode
+
+deferrable_mode:
+  DEFERRABLE
+  {
+    $$.val = tree.DeferrableInitiallyImmediate
+  }
+| DEFERRABLE INITIALLY DEFERRED
+  {
+    $$.val = tree.DeferrableInitiallyDeferred
+  }
+| DEFERRABLE INITIALLY IMMEDIATE
+  {
+    $$.val = tree.DeferrableInitiallyImmediate
+  }
+| INITIALLY DEFERRED
+  {
+    $$.val = tree.DeferrableInitiallyDeferred
+  }
+| INITIALLY IMMEDIATE
+  {
+    $$.val = tree.NotDeferrable
+  }
+| NOT_LA DEFERRABLE
+  {
+    $$.val = tree.NotDeferrable
+  }
+| NOT_LA DEFERRABLE INITIALLY IMMEDIATE
+  {
+    $$.val = tree.NotDeferrable
+  }
+| NOT_LA DEFERRABLE INITIALLY DEFERRED
+  {
+    sqllex.Error("constraint declared INITIALLY DEFERRED must be DEFERRABLE")
+    return 1
+  }
 
 storing:
   COVERING
@@ -5315,8 +5718,10 @@ role_or_group_or_user:
 
 // %Help: CREATE VIEW - create a new view
 // %Category: DDL
-// %Text: CREATE [TEMPORARY | TEMP] VIEW <viewname> [( <colnames...> )] AS <source>
-// %SeeAlso: CREATE TABLE, SHOW CREATE, WEBDOCS/create-view.html
+// %Text:
+// CREATE [TEMPORARY | TEMP] VIEW <viewname> [( <colnames...> )] AS <source>
+// CREATE MATERIALIZED VIEW [IF NOT EXISTS] <viewname> [( <colnames...> )] AS <source>
+// %SeeAlso: CREATE TABLE, REFRESH, SHOW CREATE, WEBDOCS/create-view.html
 create_view_stmt:
   CREATE opt_temp opt_view_recursive VIEW view_name opt_column_list AS select_stmt
   {
@@ -5356,7 +5761,237 @@ create_view_stmt:
       Replace: false,
     }
   }
+| CREATE MATERIALIZED VIEW view_name opt_column_list AS select_stmt
+  {
+    name := $4.unresolvedObjectName().ToTableName()
+    $$.val = &tree.CreateView{
+      Name: name,
+      ColumnNames: $5.nameList(),
+      AsSource: $7.slct(),
+      Materialized: true,
+    }
+  }
+| CREATE MATERIALIZED VIEW IF NOT EXISTS view_name opt_column_list AS select_stmt
+  {
+    name := $7.unresolvedObjectName().ToTableName()
+    $$.val = &tree.CreateView{
+      Name: name,
+      ColumnNames: $8.nameList(),
+      AsSource: $10.slct(),
+      IfNotExists: true,
+      Materialized: true,
+    }
+  }
 | CREATE opt_temp opt_view_recursive VIEW error // SHOW HELP: CREATE VIEW
+| CREATE MATERIALIZED VIEW error // SHOW HELP: CREATE VIEW
+
+// %Help: CREATE TRIGGER - create a new row-level trigger
+// %Category: DDL
+// %Text:
+// CREATE TRIGGER <name> { BEFORE | AFTER } <event> [OR <event> ...] ON <tablename>
+//   FOR EACH ROW [WHEN ( <condition> )] <action>
+//
+// Events:
+//   INSERT | UPDATE | DELETE
+//
+// Actions:
+//   SET <colname> = <expr> [, ...]  (BEFORE triggers; modifies the new row)
+//   EXECUTE <statement>             (AFTER triggers; runs an INSERT, UPSERT,
+//                                    UPDATE, DELETE or SELECT statement)
+//
+// The condition, the SET expressions and the statement can refer to the
+// columns of the row being written as NEW.<colname> and OLD.<colname>.
+// %SeeAlso: DROP TRIGGER, SHOW CREATE
+create_trigger_stmt:
+  CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name FOR EACH ROW opt_trigger_when trigger_set_action
+  {
+    $$.val = &tree.CreateTrigger{
+      Name: tree.Name($3),
+      Table: $7.unresolvedObjectName().ToTableName(),
+      ActionTime: $4.triggerActionTime(),
+      Events: $5.triggerEvents(),
+      When: $11.expr(),
+      Set: $12.updateExprs(),
+    }
+  }
+| CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name FOR EACH ROW opt_trigger_when EXECUTE trigger_statement
+  {
+    $$.val = &tree.CreateTrigger{
+      Name: tree.Name($3),
+      Table: $7.unresolvedObjectName().ToTableName(),
+      ActionTime: $4.triggerActionTime(),
+      Events: $5.triggerEvents(),
+      When: $11.expr(),
+      Stmt: $13.stmt(),
+    }
+  }
+| CREATE TRIGGER error // SHOW HELP: CREATE TRIGGER
+
+trigger_action_time:
+  BEFORE
+  {
+    $$.val = tree.TriggerBefore
+  }
+| AFTER
+  {
+    $$.val = tree.TriggerAfter
+  }
+
+trigger_event_list:
+  trigger_event
+  {
+    $$.val = tree.TriggerEvents{$1.triggerEvents()[0]}
+  }
+| trigger_event_list OR trigger_event
+  {
+    $$.val = append($1.triggerEvents(), $3.triggerEvents()[0])
+  }
+
+trigger_event:
+  INSERT
+  {
+    $$.val = tree.TriggerEvents{tree.TriggerInsert}
+  }
+| UPDATE
+  {
+    $$.val = tree.TriggerEvents{tree.TriggerUpdate}
+  }
+| DELETE
+  {
+    $$.val = tree.TriggerEvents{tree.TriggerDelete}
+  }
+
+opt_trigger_when:
+  WHEN '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+trigger_set_action:
+  SET set_clause_list
+  {
+    $$.val = $2.updateExprs()
+  }
+
+trigger_statement:
+  insert_stmt
+| upsert_stmt
+| update_stmt
+| delete_stmt
+| select_stmt
+  {
+    $$.val = $1.slct()
+  }
+
+// %Help: CREATE FUNCTION - define a new user-defined function
+// %Category: DDL
+// %Text:
+// CREATE [OR REPLACE] FUNCTION <name> ( [ [<argname>] <argtype> [, ...] ] )
+//   RETURNS [SETOF] <rettype>
+//   { LANGUAGE SQL | IMMUTABLE | STABLE | VOLATILE | AS '<definition>' } ...
+//
+// The definition is a single SQL statement, in which the arguments can be
+// referred to by name or as $1, $2, ...
+//
+// %SeeAlso: DROP FUNCTION, GRANT
+create_function_stmt:
+  CREATE FUNCTION db_object_name '(' opt_func_arg_list ')' RETURNS opt_setof typename func_option_list
+  {
+    $$.val = &tree.CreateFunction{
+      Name: $3.unresolvedObjectName(),
+      Args: $5.funcArgs(),
+      ReturnType: $9.colType(),
+      ReturnsSet: $8.bool(),
+      Options: *$10.functionOptions(),
+    }
+  }
+| CREATE OR REPLACE FUNCTION db_object_name '(' opt_func_arg_list ')' RETURNS opt_setof typename func_option_list
+  {
+    $$.val = &tree.CreateFunction{
+      Name: $5.unresolvedObjectName(),
+      Replace: true,
+      Args: $7.funcArgs(),
+      ReturnType: $11.colType(),
+      ReturnsSet: $10.bool(),
+      Options: *$12.functionOptions(),
+    }
+  }
+| CREATE FUNCTION error // SHOW HELP: CREATE FUNCTION
+| CREATE OR REPLACE FUNCTION error // SHOW HELP: CREATE FUNCTION
+
+opt_func_arg_list:
+  func_arg_list
+| /* EMPTY */
+  {
+    $$.val = tree.FuncArgs(nil)
+  }
+
+func_arg_list:
+  func_arg
+  {
+    $$.val = tree.FuncArgs{$1.funcArg()}
+  }
+| func_arg_list ',' func_arg
+  {
+    $$.val = append($1.funcArgs(), $3.funcArg())
+  }
+
+func_arg:
+  typename
+  {
+    $$.val = tree.FuncArg{Type: $1.colType()}
+  }
+| type_function_name_no_crdb_extra typename
+  {
+    $$.val = tree.FuncArg{Name: tree.Name($1), Type: $2.colType()}
+  }
+
+opt_setof:
+  SETOF
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+func_option_list:
+  func_option
+| func_option_list func_option
+  {
+    a := $1.functionOptions()
+    if err := a.CombineWith($2.functionOptions()); err != nil {
+      return setErr(sqllex, err)
+    }
+    $$.val = a
+  }
+
+func_option:
+  LANGUAGE name
+  {
+    $$.val = &tree.FunctionOptions{Language: $2}
+  }
+| IMMUTABLE
+  {
+    $$.val = &tree.FunctionOptions{Volatility: tree.FunctionImmutable, HasVolatility: true}
+  }
+| STABLE
+  {
+    $$.val = &tree.FunctionOptions{Volatility: tree.FunctionStable, HasVolatility: true}
+  }
+| VOLATILE
+  {
+    $$.val = &tree.FunctionOptions{Volatility: tree.FunctionVolatile, HasVolatility: true}
+  }
+| AS SCONST
+  {
+    $$.val = &tree.FunctionOptions{Body: $2, HasBody: true}
+  }
 
 role_option:
   CREATEROLE
@@ -6023,6 +6658,117 @@ upsert_stmt:
   }
 | opt_with_clause UPSERT error // SHOW HELP: UPSERT
 
+// %Help: MERGE - insert, update or delete rows of a table depending on a join
+// %Category: DML
+// %Text:
+// MERGE INTO <tablename> [[AS] <name>]
+//        USING <source> ON <expr>
+//        WHEN [NOT] MATCHED [AND <expr>] THEN <action>
+//        [WHEN ...]
+//
+// Actions for WHEN MATCHED clauses:
+//   UPDATE SET ...
+//   DELETE
+//   DO NOTHING
+//
+// Actions for WHEN NOT MATCHED clauses:
+//   INSERT [( <colnames...> )] VALUES ( <exprs...> )
+//   INSERT DEFAULT VALUES
+//   DO NOTHING
+//
+// %SeeAlso: INSERT, UPDATE, DELETE, UPSERT
+merge_stmt:
+  opt_with_clause MERGE INTO merge_target USING table_ref ON a_expr merge_when_list
+  {
+    $$.val = &tree.Merge{
+      With: $1.with(),
+      Table: $4.tblExpr(),
+      Source: $6.tblExpr(),
+      On: $8.expr(),
+      Whens: $9.mergeWhens(),
+    }
+  }
+| opt_with_clause MERGE INTO merge_target USING table_ref ON a_expr merge_when_list RETURNING error
+  {
+    return unimplemented(sqllex, "merge returning")
+  }
+| opt_with_clause MERGE error // SHOW HELP: MERGE
+
+merge_target:
+  table_name
+  {
+    name := $1.unresolvedObjectName().ToTableName()
+    $$.val = &name
+  }
+| table_name table_alias_name
+  {
+    name := $1.unresolvedObjectName().ToTableName()
+    $$.val = &tree.AliasedTableExpr{Expr: &name, As: tree.AliasClause{Alias: tree.Name($2)}}
+  }
+| table_name AS table_alias_name
+  {
+    name := $1.unresolvedObjectName().ToTableName()
+    $$.val = &tree.AliasedTableExpr{Expr: &name, As: tree.AliasClause{Alias: tree.Name($3)}}
+  }
+
+merge_when_list:
+  merge_when_clause
+  {
+    $$.val = tree.MergeWhens{$1.mergeWhen()}
+  }
+| merge_when_list merge_when_clause
+  {
+    $$.val = append($1.mergeWhens(), $2.mergeWhen())
+  }
+
+merge_when_clause:
+  WHEN MATCHED opt_merge_condition THEN UPDATE SET set_clause_list
+  {
+    $$.val = &tree.MergeWhen{Matched: true, Cond: $3.expr(), Action: tree.MergeUpdate, Exprs: $7.updateExprs()}
+  }
+| WHEN MATCHED opt_merge_condition THEN DELETE
+  {
+    $$.val = &tree.MergeWhen{Matched: true, Cond: $3.expr(), Action: tree.MergeDelete}
+  }
+| WHEN MATCHED opt_merge_condition THEN DO NOTHING
+  {
+    $$.val = &tree.MergeWhen{Matched: true, Cond: $3.expr(), Action: tree.MergeDoNothing}
+  }
+| WHEN NOT MATCHED opt_merge_condition THEN merge_not_matched_action
+  {
+    when := $6.mergeWhen()
+    when.Cond = $4.expr()
+    $$.val = when
+  }
+
+merge_not_matched_action:
+  INSERT VALUES '(' expr_list ')'
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeInsert, Values: $4.exprs()}
+  }
+| INSERT '(' insert_column_list ')' VALUES '(' expr_list ')'
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeInsert, Columns: $3.nameList(), Values: $7.exprs()}
+  }
+| INSERT DEFAULT VALUES
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeInsert}
+  }
+| DO NOTHING
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeDoNothing}
+  }
+
+opt_merge_condition:
+  AND a_expr
+  {
+    $$.val = $2.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
 insert_target:
   table_name
   {
@@ -6127,6 +6873,7 @@ returning_clause:
 // %Text:
 // UPDATE <tablename> [[AS] <name>]
 //        SET ...
+//        [FROM <tablerefs...>]
 //        [WHERE <expr>]
 //        [ORDER BY <exprs...>]
 //        [LIMIT <expr>]
@@ -8613,6 +9360,19 @@ func_expr:
   func_application within_group_clause filter_clause over_clause
   {
     f := $1.expr().(*tree.FuncExpr)
+    w := $2.orderBy()
+    if w != nil {
+      if len(f.OrderBy) > 0 {
+        sqllex.Error("cannot use multiple ORDER BY clauses with WITHIN GROUP")
+        return 1
+      }
+      if f.Type == tree.DistinctFuncType {
+        sqllex.Error("cannot use DISTINCT with WITHIN GROUP")
+        return 1
+      }
+      f.OrderBy = w
+      f.AggType = tree.OrderedSetAgg
+    }
     f.Filter = $3.expr()
     f.WindowDef = $4.windowDef()
     $$.val = f
@@ -8833,8 +9593,14 @@ special_function:
 
 // Aggregate decoration clauses
 within_group_clause:
-WITHIN GROUP '(' sort_clause ')' { return unimplemented(sqllex, "within group") }
-| /* EMPTY */ {}
+  WITHIN GROUP '(' sort_clause ')'
+  {
+    $$.val = $4.orderBy()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.OrderBy(nil)
+  }
 
 filter_clause:
   FILTER '(' WHERE a_expr ')'
@@ -9934,6 +10700,7 @@ unreserved_keyword:
 | ACTION
 | ADD
 | ADMIN
+| AFTER
 | AGGREGATE
 | ALTER
 | ALWAYS
@@ -9941,6 +10708,8 @@ unreserved_keyword:
 | AUTOMATIC
 | AUTHORIZATION
 | BACKUP
+| BACKUPS
+| BEFORE
 | BEGIN
 | BUCKET_COUNT
 | BUNDLE
@@ -9983,6 +10752,7 @@ unreserved_keyword:
 | DOMAIN
 | DOUBLE
 | DROP
+| EACH
 | ENCODING
 | ENUM
 | ESCAPE
@@ -10015,6 +10785,7 @@ unreserved_keyword:
 | HOUR
 | IDENTITY
 | IMMEDIATE
+| IMMUTABLE
 | IMPORT
 | INCLUDE
 | INCLUDING
@@ -10034,6 +10805,7 @@ unreserved_keyword:
 | KV
 | LANGUAGE
 | LAST
+| LATEST
 | LC_COLLATE
 | LC_CTYPE
 | LEASE
@@ -10047,6 +10819,7 @@ unreserved_keyword:
 | LOOKUP
 | LOW
 | MATCH
+| MATCHED
 | MATERIALIZED
 | MAXVALUE
 | MERGE
@@ -10100,6 +10873,7 @@ unreserved_keyword:
 | READ
 | RECURSIVE
 | REF
+| REFRESH
 | REINDEX
 | RELEASE
 | RENAME
@@ -10109,6 +10883,8 @@ unreserved_keyword:
 | RESTORE
 | RESTRICT
 | RESUME
+| RETURNS
+| REVERT
 | REVOKE
 | ROLE
 | ROLES
@@ -10140,6 +10916,7 @@ unreserved_keyword:
 | SNAPSHOT
 | SPLIT
 | SQL
+| STABLE
 | START
 | STATISTICS
 | STDIN
@@ -10180,6 +10957,7 @@ unreserved_keyword:
 | VALUE
 | VARYING
 | VIEW
+| VOLATILE
 | WITHIN
 | WITHOUT
 | WRITE
@@ -10233,6 +11011,7 @@ col_name_keyword:
 | PRECISION
 | REAL
 | ROW
+| SETOF
 | SMALLINT
 | STRING
 | SUBSTRING
@@ -0,0 +1,607 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// The statement and transaction statistics collected in memory by each node
+// are flushed into system.statement_statistics and
+// system.transaction_statistics when they are reset. The rows are keyed by
+// the start of the aggregation interval during which the statistics were
+// collected, so that the rows written by the different nodes for the same
+// interval can be combined into cluster-wide statistics. Old rows are
+// periodically compacted into per-day rows (with node_id 0), and eventually
+// deleted, by the node which holds the lease of the meta1 range.
+
+// persistedStatsEnabled determines whether the SQL statistics are persisted.
+var persistedStatsEnabled = settings.RegisterPublicBoolSetting(
+	"sql.metrics.persisted_stats.enabled",
+	"persist the collected statement and transaction statistics in system tables",
+	true,
+)
+
+// persistedStatsAggregationInterval is the granularity of the persisted
+// statistics. The in-memory statistics are flushed at the end of each interval,
+// which resets the statement statistics, in addition to the resets controlled
+// by diagnostics.sql_stat_reset.interval.
+var persistedStatsAggregationInterval = func() *settings.DurationSetting {
+	s := settings.RegisterValidatedDurationSetting(
+		"sql.metrics.persisted_stats.aggregation_interval",
+		"the time interval over which the persisted statement and transaction statistics are aggregated; "+
+			"the in-memory statement statistics are reset at the end of each interval",
+		time.Hour,
+		func(v time.Duration) error {
+			if v <= 0 {
+				return errors.Errorf("sql.metrics.persisted_stats.aggregation_interval must be positive: %s", v)
+			}
+			return nil
+		},
+	)
+	s.SetVisibility(settings.Public)
+	return s
+}()
+
+// persistedStatsTTL is the age after which the persisted statistics are
+// deleted.
+var persistedStatsTTL = settings.RegisterPublicNonNegativeDurationSetting(
+	"sql.metrics.persisted_stats.ttl",
+	"the age after which the persisted statement and transaction statistics are deleted (0 disables)",
+	7*24*time.Hour,
+)
+
+// persistedStatsCompactionAge is the age after which the persisted
+// statistics of the different nodes are combined into per-day rows.
+var persistedStatsCompactionAge = settings.RegisterPublicNonNegativeDurationSetting(
+	"sql.metrics.persisted_stats.compaction_age",
+	"the age after which the persisted statement and transaction statistics "+
+		"are compacted into per-day rows (0 disables)",
+	24*time.Hour,
+)
+
+// persistedStatsDeleteBatchSize is the maximum number of rows deleted by a
+// single statement when the old statistics are cleaned up.
+const persistedStatsDeleteBatchSize = 1000
+
+// persistedStatsCompactionBatchSize is the maximum number of rows compacted by
+// a single transaction.
+const persistedStatsCompactionBatchSize = 1000
+
+// compactedStatsNodeID is the node_id of the rows which combine the
+// statistics of all the nodes over a day.
+const compactedStatsNodeID = 0
+
+// statsSnapshot holds a copy of the statistics collected by a node since
+// start, taken when the statistics are reset.
+type statsSnapshot struct {
+	start time.Time
+	stmts map[string]map[stmtKey]roachpb.StatementStatistics
+	txns  map[string]roachpb.TxnStats
+}
+
+// makeStatsSnapshot copies the statement statistics which were cleared by a
+// reset into a statsSnapshot.
+func makeStatsSnapshot(
+	start time.Time, stmts map[string]map[stmtKey]*stmtStats, txns map[string]roachpb.TxnStats,
+) *statsSnapshot {
+	snap := &statsSnapshot{
+		start: start,
+		stmts: make(map[string]map[stmtKey]roachpb.StatementStatistics, len(stmts)),
+		txns:  txns,
+	}
+	for appName, appStmts := range stmts {
+		data := make(map[stmtKey]roachpb.StatementStatistics, len(appStmts))
+		for k, stats := range appStmts {
+			stats.Lock()
+			data[k] = stats.data
+			stats.Unlock()
+		}
+		snap.stmts[appName] = data
+	}
+	return snap
+}
+
+// persistedStmtKey identifies the rows of system.statement_statistics.
+type persistedStmtKey struct {
+	aggregatedTS time.Time
+	appName      string
+	stmtKey
+	nodeID int64
+}
+
+// persistedTxnKey identifies the rows of system.transaction_statistics.
+type persistedTxnKey struct {
+	aggregatedTS time.Time
+	appName      string
+	nodeID       int64
+}
+
+// persistedStatsActive returns whether the SQL statistics can be persisted.
+func (s *Server) persistedStatsActive(ctx context.Context) bool {
+	return persistedStatsEnabled.Get(&s.cfg.Settings.SV) &&
+		s.cfg.Settings.Version.IsActive(ctx, clusterversion.VersionPersistedSQLStats)
+}
+
+// persistedStatsAggregatedTS returns the start of the aggregation interval of
+// the statistics collected since start.
+func persistedStatsAggregatedTS(sv *settings.Values, start time.Time) time.Time {
+	if start.IsZero() {
+		start = timeutil.Now()
+	}
+	return start.Truncate(persistedStatsAggregationInterval.Get(sv))
+}
+
+// periodicallyPersistSQLStats spawns a loop which resets the SQL stats at the
+// end of each aggregation interval, which flushes them into the system
+// tables.
+func (s *Server) periodicallyPersistSQLStats(ctx context.Context, stopper *stop.Stopper) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			interval := persistedStatsAggregationInterval.Get(&s.cfg.Settings.SV)
+			now := timeutil.Now()
+			timer.Reset(now.Truncate(interval).Add(interval).Sub(now))
+			select {
+			case <-stopper.ShouldQuiesce():
+				return
+			case <-timer.C:
+				timer.Read = true
+			}
+			if s.persistedStatsActive(ctx) {
+				s.ResetSQLStats(ctx)
+			}
+		}
+	})
+}
+
+// persistSQLStats writes the statistics of the snapshot into the system
+// tables, merging them with the statistics already persisted by this node for
+// the same aggregation interval. The node which holds the lease of the meta1
+// range then cleans up the old statistics of all the nodes.
+func (s *Server) persistSQLStats(ctx context.Context, snap *statsSnapshot) {
+	if !s.persistedStatsActive(ctx) {
+		return
+	}
+	aggregatedTS := persistedStatsAggregatedTS(&s.cfg.Settings.SV, snap.start)
+	nodeID := int64(s.cfg.NodeID.Get())
+
+	for appName, stmts := range snap.stmts {
+		if len(stmts) == 0 {
+			continue
+		}
+		if err := s.cfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			for k, stats := range stmts {
+				key := persistedStmtKey{
+					aggregatedTS: aggregatedTS, appName: appName, stmtKey: k, nodeID: nodeID,
+				}
+				if err := upsertStmtStats(ctx, s.cfg.InternalExecutor, txn, key, stats); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			log.Warningf(ctx, "failed to persist statement statistics for %q: %v", appName, err)
+		}
+	}
+	for appName, stats := range snap.txns {
+		if stats.TxnCount == 0 {
+			continue
+		}
+		key := persistedTxnKey{aggregatedTS: aggregatedTS, appName: appName, nodeID: nodeID}
+		if err := s.cfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			return upsertTxnStats(ctx, s.cfg.InternalExecutor, txn, key, stats)
+		}); err != nil {
+			log.Warningf(ctx, "failed to persist transaction statistics for %q: %v", appName, err)
+		}
+	}
+
+	// Only one node cleans up the statistics, since the flushes of all the
+	// nodes happen at the same time.
+	isLeaseholder, err := s.cfg.IsMeta1Leaseholder(s.cfg.Clock.Now())
+	if err != nil {
+		log.Warningf(ctx, "failed to check the meta1 lease: %v", err)
+		return
+	}
+	if !isLeaseholder {
+		return
+	}
+	if err := s.cleanupPersistedStats(ctx); err != nil {
+		log.Warningf(ctx, "failed to clean up persisted statistics: %v", err)
+	}
+}
+
+// upsertStmtStats adds stats to the row of system.statement_statistics with
+// the given key.
+func upsertStmtStats(
+	ctx context.Context,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	key persistedStmtKey,
+	stats roachpb.StatementStatistics,
+) error {
+	ts := tree.MakeDTimestampTZ(key.aggregatedTS, time.Microsecond)
+	row, err := ie.QueryRowEx(ctx, "read-stmt-stats", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`SELECT statistics FROM system.statement_statistics
+WHERE aggregated_ts = $1 AND app_name = $2 AND fingerprint = $3
+  AND failed = $4 AND dist_sql = $5 AND implicit_txn = $6 AND node_id = $7`,
+		ts, key.appName, key.stmt, key.failed, key.distSQLUsed, key.implicitTxn, key.nodeID,
+	)
+	if err != nil {
+		return err
+	}
+	if row != nil {
+		var prev roachpb.StatementStatistics
+		if err := protoutil.Unmarshal([]byte(tree.MustBeDBytes(row[0])), &prev); err != nil {
+			return err
+		}
+		prev.Add(&stats)
+		stats = prev
+	}
+	data, err := protoutil.Marshal(&stats)
+	if err != nil {
+		return err
+	}
+	_, err = ie.ExecEx(ctx, "write-stmt-stats", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`UPSERT INTO system.statement_statistics VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		ts, key.appName, key.stmt, key.failed, key.distSQLUsed, key.implicitTxn, key.nodeID,
+		tree.NewDBytes(tree.DBytes(data)),
+	)
+	return err
+}
+
+// upsertTxnStats adds stats to the row of system.transaction_statistics with
+// the given key.
+func upsertTxnStats(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, key persistedTxnKey, stats roachpb.TxnStats,
+) error {
+	ts := tree.MakeDTimestampTZ(key.aggregatedTS, time.Microsecond)
+	row, err := ie.QueryRowEx(ctx, "read-txn-stats", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`SELECT statistics FROM system.transaction_statistics
+WHERE aggregated_ts = $1 AND app_name = $2 AND node_id = $3`,
+		ts, key.appName, key.nodeID,
+	)
+	if err != nil {
+		return err
+	}
+	if row != nil {
+		var prev roachpb.TxnStats
+		if err := protoutil.Unmarshal([]byte(tree.MustBeDBytes(row[0])), &prev); err != nil {
+			return err
+		}
+		prev.Add(stats)
+		stats = prev
+	}
+	data, err := protoutil.Marshal(&stats)
+	if err != nil {
+		return err
+	}
+	_, err = ie.ExecEx(ctx, "write-txn-stats", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`UPSERT INTO system.transaction_statistics VALUES ($1, $2, $3, $4)`,
+		ts, key.appName, key.nodeID, tree.NewDBytes(tree.DBytes(data)),
+	)
+	return err
+}
+
+// cleanupPersistedStats deletes the persisted statistics older than the TTL,
+// then compacts the statistics older than the compaction age.
+func (s *Server) cleanupPersistedStats(ctx context.Context) error {
+	now := timeutil.Now()
+	if ttl := persistedStatsTTL.Get(&s.cfg.Settings.SV); ttl > 0 {
+		cutoff := tree.MakeDTimestampTZ(now.Add(-ttl), time.Microsecond)
+		for _, table := range []string{"statement_statistics", "transaction_statistics"} {
+			for {
+				n, err := s.cfg.InternalExecutor.ExecEx(ctx, "delete-old-stats", nil, /* txn */
+					sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+					`DELETE FROM system.`+table+` WHERE aggregated_ts < $1 LIMIT $2`,
+					cutoff, persistedStatsDeleteBatchSize,
+				)
+				if err != nil {
+					return err
+				}
+				if n < persistedStatsDeleteBatchSize {
+					break
+				}
+			}
+		}
+	}
+	if age := persistedStatsCompactionAge.Get(&s.cfg.Settings.SV); age > 0 {
+		cutoff := now.Add(-age).Truncate(24 * time.Hour)
+		if err := compactPersistedStats(ctx, s.cfg.InternalExecutor, s.cfg.DB, cutoff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The rows to compact are read and deleted in the order of the primary keys,
+// so that a read and a delete with the same LIMIT in the same transaction
+// apply to the same rows.
+const (
+	persistedStmtStatsOrder = `ORDER BY aggregated_ts, app_name, fingerprint, failed, dist_sql, implicit_txn, node_id`
+	persistedTxnStatsOrder  = `ORDER BY aggregated_ts, app_name, node_id`
+)
+
+// compactPersistedStats combines the rows older than cutoff into one row per
+// day, application and statement with the compactedStatsNodeID. The rows are
+// compacted in batches of persistedStatsCompactionBatchSize rows, each in its
+// own transaction.
+func compactPersistedStats(
+	ctx context.Context, ie *InternalExecutor, db *kv.DB, cutoff time.Time,
+) error {
+	ts := tree.MakeDTimestampTZ(cutoff, time.Microsecond)
+	for _, compactBatch := range []func(context.Context, *InternalExecutor, *kv.Txn, tree.Datum) (int, error){
+		compactPersistedStmtStatsBatch,
+		compactPersistedTxnStatsBatch,
+	} {
+		for {
+			var n int
+			if err := db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+				var err error
+				n, err = compactBatch(ctx, ie, txn, ts)
+				return err
+			}); err != nil {
+				return err
+			}
+			if n < persistedStatsCompactionBatchSize {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// compactPersistedStmtStatsBatch compacts up to
+// persistedStatsCompactionBatchSize rows of system.statement_statistics older
+// than cutoff, and returns the number of compacted rows.
+func compactPersistedStmtStatsBatch(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, cutoff tree.Datum,
+) (int, error) {
+	filter := `aggregated_ts < $1 AND node_id != $2 ` + persistedStmtStatsOrder + ` LIMIT $3`
+	stmts, err := readPersistedStmtStats(ctx, ie, txn, filter,
+		cutoff, compactedStatsNodeID, persistedStatsCompactionBatchSize)
+	if err != nil || len(stmts) == 0 {
+		return 0, err
+	}
+	if _, err := ie.ExecEx(ctx, "delete-compacted-stmt-stats", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`DELETE FROM system.statement_statistics WHERE `+filter,
+		cutoff, compactedStatsNodeID, persistedStatsCompactionBatchSize,
+	); err != nil {
+		return 0, err
+	}
+
+	compacted := make(map[persistedStmtKey]roachpb.StatementStatistics)
+	for k, stats := range stmts {
+		k.aggregatedTS = k.aggregatedTS.Truncate(24 * time.Hour)
+		k.nodeID = compactedStatsNodeID
+		prev := compacted[k]
+		prev.Add(stats)
+		compacted[k] = prev
+	}
+	for k, stats := range compacted {
+		if err := upsertStmtStats(ctx, ie, txn, k, stats); err != nil {
+			return 0, err
+		}
+	}
+	return len(stmts), nil
+}
+
+// compactPersistedTxnStatsBatch is like compactPersistedStmtStatsBatch for
+// system.transaction_statistics.
+func compactPersistedTxnStatsBatch(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, cutoff tree.Datum,
+) (int, error) {
+	filter := `aggregated_ts < $1 AND node_id != $2 ` + persistedTxnStatsOrder + ` LIMIT $3`
+	txns, err := readPersistedTxnStats(ctx, ie, txn, filter,
+		cutoff, compactedStatsNodeID, persistedStatsCompactionBatchSize)
+	if err != nil || len(txns) == 0 {
+		return 0, err
+	}
+	if _, err := ie.ExecEx(ctx, "delete-compacted-txn-stats", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`DELETE FROM system.transaction_statistics WHERE `+filter,
+		cutoff, compactedStatsNodeID, persistedStatsCompactionBatchSize,
+	); err != nil {
+		return 0, err
+	}
+
+	compacted := make(map[persistedTxnKey]roachpb.TxnStats)
+	for k, stats := range txns {
+		k.aggregatedTS = k.aggregatedTS.Truncate(24 * time.Hour)
+		k.nodeID = compactedStatsNodeID
+		prev := compacted[k]
+		prev.Add(*stats)
+		compacted[k] = prev
+	}
+	for k, stats := range compacted {
+		if err := upsertTxnStats(ctx, ie, txn, k, stats); err != nil {
+			return 0, err
+		}
+	}
+	return len(txns), nil
+}
+
+// readPersistedStmtStats returns the rows of system.statement_statistics
+// which satisfy the given filter.
+func readPersistedStmtStats(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, filter string, args ...interface{},
+) (map[persistedStmtKey]*roachpb.StatementStatistics, error) {
+	rows, err := ie.QueryEx(ctx, "read-persisted-stmt-stats", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`SELECT aggregated_ts, app_name, fingerprint, failed, dist_sql, implicit_txn, node_id, statistics
+FROM system.statement_statistics WHERE `+filter,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[persistedStmtKey]*roachpb.StatementStatistics, len(rows))
+	for _, row := range rows {
+		k := persistedStmtKey{
+			aggregatedTS: tree.MustBeDTimestampTZ(row[0]).Time,
+			appName:      string(tree.MustBeDString(row[1])),
+			stmtKey: stmtKey{
+				stmt:        string(tree.MustBeDString(row[2])),
+				failed:      bool(tree.MustBeDBool(row[3])),
+				distSQLUsed: bool(tree.MustBeDBool(row[4])),
+				implicitTxn: bool(tree.MustBeDBool(row[5])),
+			},
+			nodeID: int64(tree.MustBeDInt(row[6])),
+		}
+		stats := &roachpb.StatementStatistics{}
+		if err := protoutil.Unmarshal([]byte(tree.MustBeDBytes(row[7])), stats); err != nil {
+			return nil, err
+		}
+		res[k] = stats
+	}
+	return res, nil
+}
+
+// readPersistedTxnStats returns the rows of system.transaction_statistics
+// which satisfy the given filter.
+func readPersistedTxnStats(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, filter string, args ...interface{},
+) (map[persistedTxnKey]*roachpb.TxnStats, error) {
+	rows, err := ie.QueryEx(ctx, "read-persisted-txn-stats", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`SELECT aggregated_ts, app_name, node_id, statistics
+FROM system.transaction_statistics WHERE `+filter,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[persistedTxnKey]*roachpb.TxnStats, len(rows))
+	for _, row := range rows {
+		k := persistedTxnKey{
+			aggregatedTS: tree.MustBeDTimestampTZ(row[0]).Time,
+			appName:      string(tree.MustBeDString(row[1])),
+			nodeID:       int64(tree.MustBeDInt(row[2])),
+		}
+		stats := &roachpb.TxnStats{}
+		if err := protoutil.Unmarshal([]byte(tree.MustBeDBytes(row[3])), stats); err != nil {
+			return nil, err
+		}
+		res[k] = stats
+	}
+	return res, nil
+}
+
+// collectStmtStatistics returns the persisted statement statistics of all the
+// nodes, combined with the in-memory statistics of the local node. The
+// statistics of the different nodes are merged, so the keys have no node ID.
+func collectStmtStatistics(
+	ctx context.Context, p *planner, sqlStats *sqlStats,
+) (map[persistedStmtKey]*roachpb.StatementStatistics, error) {
+	res := make(map[persistedStmtKey]*roachpb.StatementStatistics)
+	merge := func(k persistedStmtKey, stats *roachpb.StatementStatistics) {
+		k.nodeID = 0
+		if prev, ok := res[k]; ok {
+			prev.Add(stats)
+		} else {
+			res[k] = stats
+		}
+	}
+
+	execCfg := p.ExecCfg()
+	if execCfg.Settings.Version.IsActive(ctx, clusterversion.VersionPersistedSQLStats) {
+		persisted, err := readPersistedStmtStats(ctx, execCfg.InternalExecutor, p.txn, "true")
+		if err != nil {
+			return nil, err
+		}
+		for k, stats := range persisted {
+			merge(k, stats)
+		}
+	}
+
+	aggregatedTS := persistedStatsAggregatedTS(&execCfg.Settings.SV, sqlStats.getLastReset())
+	sqlStats.Lock()
+	apps := make(map[string]*appStats, len(sqlStats.apps))
+	for appName, a := range sqlStats.apps {
+		apps[appName] = a
+	}
+	sqlStats.Unlock()
+	for appName, a := range apps {
+		a.Lock()
+		for k, s := range a.stmts {
+			s.Lock()
+			data := s.data
+			s.Unlock()
+			merge(persistedStmtKey{aggregatedTS: aggregatedTS, appName: appName, stmtKey: k}, &data)
+		}
+		a.Unlock()
+	}
+	return res, nil
+}
+
+// collectTxnStatistics is like collectStmtStatistics for the transaction
+// statistics.
+func collectTxnStatistics(
+	ctx context.Context, p *planner, sqlStats *sqlStats,
+) (map[persistedTxnKey]*roachpb.TxnStats, error) {
+	res := make(map[persistedTxnKey]*roachpb.TxnStats)
+	merge := func(k persistedTxnKey, stats *roachpb.TxnStats) {
+		k.nodeID = 0
+		if prev, ok := res[k]; ok {
+			prev.Add(*stats)
+		} else {
+			res[k] = stats
+		}
+	}
+
+	execCfg := p.ExecCfg()
+	if execCfg.Settings.Version.IsActive(ctx, clusterversion.VersionPersistedSQLStats) {
+		persisted, err := readPersistedTxnStats(ctx, execCfg.InternalExecutor, p.txn, "true")
+		if err != nil {
+			return nil, err
+		}
+		for k, stats := range persisted {
+			merge(k, stats)
+		}
+	}
+
+	aggregatedTS := persistedStatsAggregatedTS(&execCfg.Settings.SV, sqlStats.getLastReset())
+	sqlStats.Lock()
+	apps := make(map[string]*appStats, len(sqlStats.apps))
+	for appName, a := range sqlStats.apps {
+		apps[appName] = a
+	}
+	sqlStats.Unlock()
+	for appName, a := range apps {
+		// Only the statistics recorded since the last reset are added to the
+		// persisted ones; the earlier ones were flushed by the reset.
+		data := a.txns.getUnflushed()
+		if data.TxnCount > 0 {
+			merge(persistedTxnKey{aggregatedTS: aggregatedTS, appName: appName}, &data)
+		}
+	}
+	return res, nil
+}
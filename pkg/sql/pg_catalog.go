@@ -619,10 +619,11 @@ CREATE TABLE pg_catalog.pg_available_extensions (
 }
 
 var (
-	relKindTable    = tree.NewDString("r")
-	relKindIndex    = tree.NewDString("i")
-	relKindView     = tree.NewDString("v")
-	relKindSequence = tree.NewDString("S")
+	relKindTable            = tree.NewDString("r")
+	relKindIndex            = tree.NewDString("i")
+	relKindView             = tree.NewDString("v")
+	relKindMaterializedView = tree.NewDString("m")
+	relKindSequence         = tree.NewDString("S")
 
 	relPersistencePermanent = tree.NewDString("p")
 )
@@ -668,7 +669,9 @@ CREATE TABLE pg_catalog.pg_class (
 				// The only difference between tables, views and sequences are the relkind and relam columns.
 				relKind := relKindTable
 				relAm := forwardIndexOid
-				if table.IsView() {
+				if table.MaterializedView() {
+					relKind = relKindMaterializedView
+				} else if table.IsView() {
 					relKind = relKindView
 					relAm = oidZero
 				} else if table.IsSequence() {
@@ -895,6 +898,8 @@ CREATE TABLE pg_catalog.pg_constraint (
 				consrc := tree.DNull
 				conbin := tree.DNull
 				condef := tree.DNull
+				condeferrable := tree.DBoolFalse
+				condeferred := tree.DBoolFalse
 
 				// Determine constraint kind-specific fields.
 				var err error
@@ -935,6 +940,8 @@ CREATE TABLE pg_catalog.pg_constraint (
 					if r, ok := fkMatchMap[con.FK.Match]; ok {
 						confmatchtype = r
 					}
+					condeferrable = tree.MakeDBool(tree.DBool(con.FK.Deferrable))
+					condeferred = tree.MakeDBool(tree.DBool(con.FK.InitiallyDeferred))
 					if conkey, err = colIDArrayToDatum(con.FK.OriginColumnIDs); err != nil {
 						return err
 					}
@@ -959,7 +966,10 @@ CREATE TABLE pg_catalog.pg_constraint (
 					f.WriteString("UNIQUE (")
 					con.Index.ColNamesFormat(f)
 					f.WriteByte(')')
+					f.FormatNode(con.Index.UniqueDeferrability())
 					condef = tree.NewDString(f.CloseAndGetString())
+					condeferrable = tree.MakeDBool(tree.DBool(con.Index.UniqueDeferrable))
+					condeferred = tree.MakeDBool(tree.DBool(con.Index.UniqueInitiallyDeferred))
 
 				case sqlbase.ConstraintTypeCheck:
 					oid = h.CheckConstraintOid(db, scName, table, con.CheckConstraint)
@@ -970,6 +980,14 @@ CREATE TABLE pg_catalog.pg_constraint (
 					consrc = tree.NewDString(fmt.Sprintf("(%s)", con.Details))
 					conbin = consrc
 					condef = tree.NewDString(fmt.Sprintf("CHECK ((%s))", con.Details))
+
+				case sqlbase.ConstraintTypeExclusion:
+					oid = h.ExclusionConstraintOid(db, scName, table, con.ExclusionConstraint)
+					contype = conTypeExclusion
+					if conkey, err = colIDArrayToDatum(con.ExclusionConstraint.ColumnIDs); err != nil {
+						return err
+					}
+					condef = tree.NewDString(con.Details)
 				}
 
 				if err := addRow(
@@ -977,8 +995,8 @@ CREATE TABLE pg_catalog.pg_constraint (
 					dNameOrNull(conName), // conname
 					namespaceOid,         // connamespace
 					contype,              // contype
-					tree.DBoolFalse,      // condeferrable
-					tree.DBoolFalse,      // condeferred
+					condeferrable,        // condeferrable
+					condeferred,          // condeferred
 					tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
 					tblOid,         // conrelid
 					oidZero,        // contypid
@@ -1514,7 +1532,7 @@ CREATE TABLE pg_catalog.pg_index (
 						h.IndexOid(table.ID, index.ID), // indexrelid
 						tableOid,                       // indrelid
 						tree.NewDInt(tree.DInt(len(index.ColumnNames))),                                          // indnatts
-						tree.MakeDBool(tree.DBool(index.Unique)),                                                 // indisunique
+						tree.MakeDBool(tree.DBool(index.Unique || index.UniqueDeferrable)),                       // indisunique
 						tree.MakeDBool(tree.DBool(table.IsPhysicalTable() && index.ID == table.PrimaryIndex.ID)), // indisprimary
 						tree.DBoolFalse,                          // indisexclusion
 						tree.MakeDBool(tree.DBool(index.Unique)), // indimmediate
@@ -1698,7 +1716,7 @@ CREATE TABLE pg_catalog.pg_locks (
 }
 
 var pgCatalogMatViewsTable = virtualSchemaTable{
-	comment: `available materialized views (empty - feature does not exist)
+	comment: `available materialized views
 https://www.postgresql.org/docs/9.6/view-pg-matviews.html`,
 	schema: `
 CREATE TABLE pg_catalog.pg_matviews (
@@ -1711,7 +1729,23 @@ CREATE TABLE pg_catalog.pg_matviews (
   definition TEXT
 )`,
 	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /* virtual schemas do not have views */
+			func(db *sqlbase.DatabaseDescriptor, scName string, desc *sqlbase.TableDescriptor) error {
+				if !desc.MaterializedView() {
+					return nil
+				}
+				// A materialized view is only listed once it has been populated,
+				// which happens when the schema change creating it completes.
+				return addRow(
+					tree.NewDName(scName),    // schemaname
+					tree.NewDName(desc.Name), // matviewname
+					tree.DNull,               // matviewowner
+					tree.DNull,               // tablespace
+					tree.MakeDBool(tree.DBool(len(desc.Indexes) > 0)), // hasindexes
+					tree.DBoolTrue,                  // ispopulated
+					tree.NewDString(desc.ViewQuery), // definition
+				)
+			})
 	},
 }
 
@@ -2793,7 +2827,7 @@ CREATE TABLE pg_catalog.pg_views (
 		// because it does not distinguish views in separate databases.
 		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /*virtual schemas do not have views*/
 			func(db *sqlbase.DatabaseDescriptor, scName string, desc *sqlbase.TableDescriptor) error {
-				if !desc.IsView() {
+				if !desc.IsView() || desc.MaterializedView() {
 					return nil
 				}
 				// Note that the view query printed will not include any column aliases
@@ -2886,6 +2920,7 @@ const (
 	userTypeTag
 	collationTypeTag
 	operatorTypeTag
+	exclusionConstraintTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -2919,6 +2954,13 @@ func (h oidHasher) writeCheckConstraint(check *sqlbase.TableDescriptor_CheckCons
 	h.writeStr(check.Expr)
 }
 
+func (h oidHasher) writeExclusionConstraint(c *sqlbase.TableDescriptor_ExclusionConstraint) {
+	h.writeStr(c.Name)
+	for i := range c.Elements {
+		h.writeStr(c.Elements[i].Expr)
+	}
+}
+
 func (h oidHasher) writeForeignKeyConstraint(fk *sqlbase.ForeignKeyConstraint) {
 	h.writeUInt32(uint32(fk.ReferencedTableID))
 	h.writeStr(fk.Name)
@@ -2987,6 +3029,20 @@ func (h oidHasher) ForeignKeyConstraintOid(
 	return h.getOid()
 }
 
+func (h oidHasher) ExclusionConstraintOid(
+	db *sqlbase.DatabaseDescriptor,
+	scName string,
+	table *sqlbase.TableDescriptor,
+	c *sqlbase.TableDescriptor_ExclusionConstraint,
+) *tree.DOid {
+	h.writeTypeTag(exclusionConstraintTypeTag)
+	h.writeDB(db)
+	h.writeSchema(scName)
+	h.writeTable(table.ID)
+	h.writeExclusionConstraint(c)
+	return h.getOid()
+}
+
 func (h oidHasher) UniqueConstraintOid(
 	db *sqlbase.DatabaseDescriptor,
 	scName string,
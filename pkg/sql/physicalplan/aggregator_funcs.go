@@ -287,4 +287,132 @@ var DistAggregationTable = map[execinfrapb.AggregatorSpec_Func]DistAggregationIn
 			},
 		},
 	},
+
+	// The hypothetical-set aggregates only count rows. Each local RANK_IMPL
+	// returns one plus the number of rows for which its input is true, so the
+	// final stage sums these results and counts them (there is one per local
+	// aggregation) in order to subtract the extra ones:
+	//  - RANK_IMPL is rendered as SUM_INT - COUNT + 1;
+	//  - PERCENT_RANK_IMPL and CUME_DIST_IMPL additionally sum the local COUNTs
+	//    of non-NULL inputs, and divide the number of rows for which the input
+	//    is true by that total.
+	execinfrapb.AggregatorSpec_RANK_IMPL: {
+		LocalStage: []execinfrapb.AggregatorSpec_Func{execinfrapb.AggregatorSpec_RANK_IMPL},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        execinfrapb.AggregatorSpec_SUM_INT,
+				LocalIdxs: []uint32{0},
+			},
+			{
+				Fn:        execinfrapb.AggregatorSpec_COUNT,
+				LocalIdxs: []uint32{0},
+			},
+		},
+		FinalRendering: func(h *tree.IndexedVarHelper, varIdxs []int) (tree.TypedExpr, error) {
+			if len(varIdxs) < 2 {
+				panic("fewer than two final aggregation values passed into final render")
+			}
+			expr := &tree.BinaryExpr{
+				Operator: tree.Plus,
+				Left:     hypotheticalCount(h, varIdxs),
+				Right:    tree.NewDInt(1),
+			}
+			ctx := &tree.SemaContext{IVarContainer: h.Container()}
+			return expr.TypeCheck(ctx, types.Int)
+		},
+	},
+
+	execinfrapb.AggregatorSpec_PERCENT_RANK_IMPL: {
+		LocalStage: []execinfrapb.AggregatorSpec_Func{
+			execinfrapb.AggregatorSpec_RANK_IMPL,
+			execinfrapb.AggregatorSpec_COUNT,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        execinfrapb.AggregatorSpec_SUM_INT,
+				LocalIdxs: []uint32{0},
+			},
+			{
+				Fn:        execinfrapb.AggregatorSpec_COUNT,
+				LocalIdxs: []uint32{0},
+			},
+			{
+				Fn:        execinfrapb.AggregatorSpec_SUM_INT,
+				LocalIdxs: []uint32{1},
+			},
+		},
+		FinalRendering: func(h *tree.IndexedVarHelper, varIdxs []int) (tree.TypedExpr, error) {
+			if len(varIdxs) < 3 {
+				panic("fewer than three final aggregation values passed into final render")
+			}
+			total := h.IndexedVar(varIdxs[2])
+			// The percent rank of the hypothetical row in an empty group is 0.
+			expr := &tree.CaseExpr{
+				Whens: []*tree.When{{
+					Cond: &tree.ComparisonExpr{Operator: tree.EQ, Left: total, Right: tree.NewDInt(0)},
+					Val:  tree.NewDFloat(0),
+				}},
+				Else: &tree.BinaryExpr{
+					Operator: tree.Div,
+					Left:     &tree.CastExpr{Expr: hypotheticalCount(h, varIdxs), Type: types.Float},
+					Right:    &tree.CastExpr{Expr: total, Type: types.Float},
+				},
+			}
+			ctx := &tree.SemaContext{IVarContainer: h.Container()}
+			return expr.TypeCheck(ctx, types.Float)
+		},
+	},
+
+	execinfrapb.AggregatorSpec_CUME_DIST_IMPL: {
+		LocalStage: []execinfrapb.AggregatorSpec_Func{
+			execinfrapb.AggregatorSpec_RANK_IMPL,
+			execinfrapb.AggregatorSpec_COUNT,
+		},
+		FinalStage: []FinalStageInfo{
+			{
+				Fn:        execinfrapb.AggregatorSpec_SUM_INT,
+				LocalIdxs: []uint32{0},
+			},
+			{
+				Fn:        execinfrapb.AggregatorSpec_COUNT,
+				LocalIdxs: []uint32{0},
+			},
+			{
+				Fn:        execinfrapb.AggregatorSpec_SUM_INT,
+				LocalIdxs: []uint32{1},
+			},
+		},
+		FinalRendering: func(h *tree.IndexedVarHelper, varIdxs []int) (tree.TypedExpr, error) {
+			if len(varIdxs) < 3 {
+				panic("fewer than three final aggregation values passed into final render")
+			}
+			// The hypothetical row itself is counted in both the numerator and the
+			// denominator.
+			one := tree.NewDInt(1)
+			expr := &tree.BinaryExpr{
+				Operator: tree.Div,
+				Left: &tree.CastExpr{
+					Expr: &tree.BinaryExpr{Operator: tree.Plus, Left: hypotheticalCount(h, varIdxs), Right: one},
+					Type: types.Float,
+				},
+				Right: &tree.CastExpr{
+					Expr: &tree.BinaryExpr{Operator: tree.Plus, Left: h.IndexedVar(varIdxs[2]), Right: one},
+					Type: types.Float,
+				},
+			}
+			ctx := &tree.SemaContext{IVarContainer: h.Container()}
+			return expr.TypeCheck(ctx, types.Float)
+		},
+	},
+}
+
+// hypotheticalCount returns an expression for the number of rows for which the
+// input of a hypothetical-set aggregate is true, given the final SUM_INT and
+// COUNT of the local RANK_IMPL results in the first two final stage results.
+func hypotheticalCount(h *tree.IndexedVarHelper, varIdxs []int) tree.Expr {
+	return &tree.BinaryExpr{
+		Operator: tree.Minus,
+		Left:     h.IndexedVar(varIdxs[0]),
+		Right:    h.IndexedVar(varIdxs[1]),
+	}
 }
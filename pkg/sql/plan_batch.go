@@ -72,6 +72,7 @@ type batchedPlanNode interface {
 }
 
 var _ batchedPlanNode = &deleteNode{}
+var _ batchedPlanNode = &mergeNode{}
 var _ batchedPlanNode = &updateNode{}
 
 // serializeNode serializes the results of a batchedPlanNode into a
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
@@ -69,6 +70,20 @@ type extendedEvalContext struct {
 
 	Jobs *jobsCollection
 
+	// DeferredConstraints points to the state of the deferred constraints of
+	// the transaction. It is nil if the constraint checks cannot be deferred, in
+	// which case all the checks are immediate.
+	DeferredConstraints *deferredConstraints
+
+	// OnCommitTempTables points to the temporary tables created by the
+	// transaction with ON COMMIT DELETE ROWS or ON COMMIT DROP. It is nil if
+	// such tables cannot be created.
+	OnCommitTempTables *onCommitTempTables
+	// DeleteRowsTempTables points to the set of the IDs of the temporary tables
+	// created by the earlier transactions of the session with ON COMMIT DELETE
+	// ROWS. It is nil if OnCommitTempTables is nil.
+	DeleteRowsTempTables *util.FastIntSet
+
 	schemaAccessors *schemaInterface
 
 	sqlStatsCollector *sqlStatsCollector
@@ -278,6 +293,7 @@ func newInternalPlanner(
 	p.semaCtx = tree.MakeSemaContext()
 	p.semaCtx.Location = &sd.DataConversion.Location
 	p.semaCtx.SearchPath = sd.SearchPath
+	p.semaCtx.FunctionResolver = p
 
 	plannerMon := mon.MakeUnlimitedMonitor(ctx,
 		fmt.Sprintf("internal-planner.%s.%s", user, opName),
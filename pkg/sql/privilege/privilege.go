@@ -15,6 +15,8 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/pkg/errors"
 )
 
@@ -37,6 +39,7 @@ const (
 	DELETE
 	UPDATE
 	ZONECONFIG
+	EXECUTE
 )
 
 // Predefined sets of privileges.
@@ -45,6 +48,36 @@ var (
 	ReadWriteData = List{GRANT, SELECT, INSERT, DELETE, UPDATE}
 )
 
+// ObjectType represents objects that can have privileges.
+type ObjectType string
+
+const (
+	// Database represents a database object.
+	Database ObjectType = "database"
+	// Table represents a table object.
+	Table ObjectType = "table"
+	// Function represents a user-defined function object.
+	Function ObjectType = "function"
+)
+
+var (
+	// DBTablePrivileges is the list of privileges that can be held on
+	// databases and tables.
+	DBTablePrivileges = List{ALL, CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE, ZONECONFIG}
+	// FunctionPrivileges is the list of privileges that can be held on
+	// user-defined functions.
+	FunctionPrivileges = List{ALL, DROP, GRANT, EXECUTE}
+)
+
+// GetValidPrivilegesForObject returns the list of privileges that can be
+// held on objects of the given type.
+func GetValidPrivilegesForObject(objectType ObjectType) List {
+	if objectType == Function {
+		return FunctionPrivileges
+	}
+	return DBTablePrivileges
+}
+
 // Mask returns the bitmask for a given privilege.
 func (k Kind) Mask() uint32 {
 	return 1 << k
@@ -52,7 +85,7 @@ func (k Kind) Mask() uint32 {
 
 // ByValue is just an array of privilege kinds sorted by value.
 var ByValue = [...]Kind{
-	ALL, CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE, ZONECONFIG,
+	ALL, CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE, ZONECONFIG, EXECUTE,
 }
 
 // ByName is a map of string -> kind value.
@@ -66,6 +99,7 @@ var ByName = map[string]Kind{
 	"DELETE":     DELETE,
 	"UPDATE":     UPDATE,
 	"ZONECONFIG": ZONECONFIG,
+	"EXECUTE":    EXECUTE,
 }
 
 // List is a list of privileges.
@@ -163,3 +197,16 @@ func ListFromStrings(strs []string) (List, error) {
 	}
 	return ret, nil
 }
+
+// ValidatePrivileges returns an error if any privilege in privileges
+// cannot be held on objects of the given type.
+func ValidatePrivileges(privileges List, objectType ObjectType) error {
+	validPrivs := GetValidPrivilegesForObject(objectType).ToBitField()
+	for _, priv := range privileges {
+		if validPrivs&priv.Mask() == 0 {
+			return pgerror.Newf(pgcode.InvalidGrantOperation,
+				"invalid privilege type %s for %s", priv, objectType)
+		}
+	}
+	return nil
+}
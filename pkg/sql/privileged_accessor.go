@@ -20,6 +20,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/ts/tspb"
+	"github.com/cockroachdb/errors"
 )
 
 // LookupNamespaceID implements tree.PrivilegedAccessor.
@@ -94,6 +96,20 @@ func (p *planner) LookupZoneConfigByNamespaceID(
 	return tree.MustBeDBytes(r[0]), true, nil
 }
 
+// QueryTimeSeries implements tree.PrivilegedAccessor.
+func (p *planner) QueryTimeSeries(
+	ctx context.Context, req *tspb.TimeSeriesQueryRequest,
+) (*tspb.TimeSeriesQueryResponse, error) {
+	if err := p.RequireAdminRole(ctx, "query time series"); err != nil {
+		return nil, err
+	}
+	tsServer := p.ExecCfg().TimeSeriesServer
+	if tsServer == nil {
+		return nil, errors.New("time series are not available on this server")
+	}
+	return tsServer.Query(ctx, req)
+}
+
 // checkDescriptorPermissions returns nil if the executing user has permissions
 // to check the permissions of a descriptor given its ID, or the id given
 // is not a descriptor of a table or database.
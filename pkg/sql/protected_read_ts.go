@@ -0,0 +1,151 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+)
+
+// ProtectedReadMetaType is the value used in the ptpb.Record.MetaType field
+// for records that protect the read timestamp of a SQL statement on behalf of
+// a session with protect_read_timestamp set.
+//
+// This value must not be changed as it is used durably in the database.
+const ProtectedReadMetaType = "sql_reads"
+
+var protectReadTimestampMaxDuration = settings.RegisterPublicNonNegativeDurationSetting(
+	"sql.protect_read_timestamp.max_duration",
+	"the maximum amount of time that a statement run with protect_read_timestamp "+
+		"set keeps its read timestamp protected from garbage collection",
+	time.Hour,
+)
+
+// protectReadTimestamp protects ts from garbage collection over the spans of
+// the tables read by the planner's current plan, and returns a function that
+// releases the protection. The record expires after
+// sql.protect_read_timestamp.max_duration even if it is never released, e.g.
+// because the gateway crashed: the protected timestamp reconciler removes it
+// then, or as soon as the gateway is no longer live.
+func (ex *connExecutor) protectReadTimestamp(
+	ctx context.Context, p *planner, ts hlc.Timestamp,
+) (release func(), _ error) {
+	spans := protectedReadSpans(p.curPlan.mem)
+	if len(spans) == 0 {
+		return func() {}, nil
+	}
+
+	cfg := ex.server.cfg
+	deadline := cfg.Clock.Now().Add(protectReadTimestampMaxDuration.Get(&cfg.Settings.SV).Nanoseconds(), 0)
+	rec := &ptpb.Record{
+		ID:        uuid.MakeV4(),
+		Timestamp: ts,
+		Mode:      ptpb.PROTECT_AFTER,
+		MetaType:  ProtectedReadMetaType,
+		Meta:      encodeProtectedReadMeta(cfg.NodeID.Get(), deadline),
+		Spans:     spans,
+	}
+	if err := cfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		return cfg.ProtectedTimestampProvider.Protect(ctx, txn, rec)
+	}); err != nil {
+		return nil, err
+	}
+
+	release = func() {
+		// The statement's context may have been canceled by now, which must not
+		// prevent the record from being released.
+		ctx := logtags.WithTags(context.Background(), logtags.FromContext(ctx))
+		if err := cfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			return cfg.ProtectedTimestampProvider.Release(ctx, txn, rec.ID)
+		}); err != nil && !errors.Is(err, protectedts.ErrNotExists) {
+			// The record is cleaned up by the reconciler once it expires.
+			log.Warningf(ctx, "failed to release protected timestamp record %v: %v", rec.ID, err)
+		}
+	}
+
+	// The record only guarantees that ts stays readable once it is verified;
+	// this fails if the data was already garbage collected.
+	if err := cfg.ProtectedTimestampProvider.Verify(ctx, rec.ID); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// protectedReadSpans returns the spans of the non-virtual tables referenced
+// by the query in mem.
+func protectedReadSpans(mem *memo.Memo) []roachpb.Span {
+	if mem == nil {
+		return nil
+	}
+	var spans []roachpb.Span
+	for _, tab := range mem.Metadata().AllTables() {
+		if tab.Table.IsVirtualTable() {
+			continue
+		}
+		prefix := roachpb.Key(keys.MakeTablePrefix(uint32(tab.Table.ID())))
+		spans = append(spans, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+	}
+	spans, _ = roachpb.MergeSpans(spans)
+	return spans
+}
+
+// MakeProtectedReadStatusFunc returns a function which determines whether a
+// protected timestamp record made on behalf of a SQL statement should be
+// removed by the reconciler, which is the case once it has expired or once the
+// gateway node that made it is no longer live. It is meant to be used as a
+// ptreconcile.StatusFunc, which is not referenced here to keep the
+// reconciler's dependencies out of this package.
+func MakeProtectedReadStatusFunc(
+	clock *hlc.Clock, isLive func(roachpb.NodeID) (bool, error),
+) func(ctx context.Context, txn *kv.Txn, meta []byte) (shouldRemove bool, _ error) {
+	return func(ctx context.Context, _ *kv.Txn, meta []byte) (shouldRemove bool, _ error) {
+		nodeID, deadline, err := decodeProtectedReadMeta(meta)
+		if err != nil {
+			return false, err
+		}
+		if deadline.Less(clock.Now()) {
+			return true, nil
+		}
+		live, err := isLive(nodeID)
+		if err != nil {
+			return false, err
+		}
+		return !live, nil
+	}
+}
+
+func encodeProtectedReadMeta(nodeID roachpb.NodeID, deadline hlc.Timestamp) []byte {
+	return []byte(fmt.Sprintf("%d,%d", nodeID, deadline.WallTime))
+}
+
+func decodeProtectedReadMeta(meta []byte) (roachpb.NodeID, hlc.Timestamp, error) {
+	var nodeID roachpb.NodeID
+	var wallTime int64
+	if _, err := fmt.Sscanf(string(meta), "%d,%d", &nodeID, &wallTime); err != nil {
+		return 0, hlc.Timestamp{}, errors.Wrapf(err, "failed to interpret meta %q", meta)
+	}
+	return nodeID, hlc.Timestamp{WallTime: wallTime}, nil
+}
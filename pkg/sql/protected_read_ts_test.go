@@ -0,0 +1,127 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	gosql "database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtectReadTimestamp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	const query = `SELECT count(*) FROM d.t AS OF SYSTEM TIME '-1us'`
+
+	// While the query runs, count the protected timestamp records made on
+	// behalf of SQL statements. The query is run on another connection from
+	// the pool.
+	var otherDB *gosql.DB
+	var recordsDuringQuery int
+	params := base.TestServerArgs{}
+	params.Knobs.SQLExecutor = &ExecutorTestingKnobs{
+		BeforeExecute: func(ctx context.Context, stmt string) {
+			if !strings.Contains(stmt, "FROM d.t") {
+				return
+			}
+			recordsDuringQuery = -1
+			if err := otherDB.QueryRow(
+				`SELECT count(*) FROM system.protected_ts_records WHERE meta_type = $1`, ProtectedReadMetaType,
+			).Scan(&recordsDuringQuery); err != nil {
+				t.Error(err)
+			}
+		},
+	}
+	s, sqlDB, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(ctx)
+	otherDB = sqlDB
+
+	db := sqlutils.MakeSQLRunner(sqlDB)
+	db.Exec(t, `CREATE DATABASE d`)
+	db.Exec(t, `CREATE TABLE d.t (k INT PRIMARY KEY)`)
+	db.Exec(t, `INSERT INTO d.t VALUES (1), (2), (3)`)
+
+	t.Run("off", func(t *testing.T) {
+		db.CheckQueryResults(t, query, [][]string{{"3"}})
+		require.Equal(t, 0, recordsDuringQuery)
+	})
+
+	t.Run("on", func(t *testing.T) {
+		// The session variable has to be set on the connection that runs the
+		// query.
+		conn, err := sqlDB.Conn(ctx)
+		require.NoError(t, err)
+		defer conn.Close()
+		connDB := sqlutils.MakeSQLRunner(conn)
+		connDB.Exec(t, `SET protect_read_timestamp = on`)
+		connDB.CheckQueryResults(t, query, [][]string{{"3"}})
+		require.Equal(t, 1, recordsDuringQuery)
+		db.CheckQueryResults(t, `SELECT count(*) FROM system.protected_ts_records`, [][]string{{"0"}})
+	})
+
+	t.Run("historical transaction", func(t *testing.T) {
+		// The statements of a BEGIN AS OF SYSTEM TIME transaction read at the
+		// transaction's timestamp without specifying it themselves.
+		conn, err := sqlDB.Conn(ctx)
+		require.NoError(t, err)
+		defer conn.Close()
+		connDB := sqlutils.MakeSQLRunner(conn)
+		connDB.Exec(t, `SET protect_read_timestamp = on`)
+		connDB.Exec(t, `BEGIN AS OF SYSTEM TIME '-1us'`)
+		connDB.CheckQueryResults(t, `SELECT count(*) FROM d.t`, [][]string{{"3"}})
+		require.Equal(t, 1, recordsDuringQuery)
+		connDB.Exec(t, `COMMIT`)
+		db.CheckQueryResults(t, `SELECT count(*) FROM system.protected_ts_records`, [][]string{{"0"}})
+	})
+}
+
+func TestProtectedReadStatusFunc(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	manual := hlc.NewManualClock(123)
+	clock := hlc.NewClock(manual.UnixNano, time.Nanosecond)
+	live := map[roachpb.NodeID]bool{1: true}
+	shouldRemove := MakeProtectedReadStatusFunc(clock, func(id roachpb.NodeID) (bool, error) {
+		return live[id], nil
+	})
+
+	deadline := clock.Now().Add(time.Hour.Nanoseconds(), 0)
+	for _, tc := range []struct {
+		name   string
+		meta   []byte
+		remove bool
+	}{
+		{"live gateway", encodeProtectedReadMeta(1, deadline), false},
+		{"dead gateway", encodeProtectedReadMeta(2, deadline), true},
+		{"expired", encodeProtectedReadMeta(1, clock.Now().Add(-1, 0)), true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			remove, err := shouldRemove(ctx, nil /* txn */, tc.meta)
+			require.NoError(t, err)
+			require.Equal(t, tc.remove, remove)
+		})
+	}
+
+	_, err := shouldRemove(ctx, nil /* txn */, []byte("garbage"))
+	require.Error(t, err)
+}
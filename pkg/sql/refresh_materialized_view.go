@@ -0,0 +1,259 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+type refreshMaterializedViewNode struct {
+	n *tree.RefreshMaterializedView
+}
+
+// RefreshMaterializedView recomputes the contents of a materialized view.
+// Privileges: CREATE on view.
+func (p *planner) RefreshMaterializedView(
+	ctx context.Context, n *tree.RefreshMaterializedView,
+) (planNode, error) {
+	return &refreshMaterializedViewNode{n: n}, nil
+}
+
+// startExec allocates a fresh set of indexes for the view and queues the job
+// that fills them from the view query and swaps them in once the transaction
+// commits. The view keeps serving its old contents until then, so
+// CONCURRENTLY, which only exists to avoid blocking readers in Postgres, is
+// accepted but has no effect.
+func (n *refreshMaterializedViewNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+
+	tn := n.n.Name.ToTableName()
+	desc, err := p.ResolveMutableTableDescriptor(ctx, &tn, true /* required */, ResolveRequireViewDesc)
+	if err != nil {
+		return err
+	}
+	if !desc.MaterializedView() {
+		return pgerror.Newf(pgcode.WrongObjectType, "%q is not a materialized view", desc.Name)
+	}
+	if err := p.CheckPrivilege(ctx, desc, privilege.CREATE); err != nil {
+		return err
+	}
+	if desc.Adding() || len(desc.Mutations) > 0 {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"cannot refresh materialized view %q while a schema change is in progress", desc.Name)
+	}
+
+	telemetry.Inc(sqltelemetry.SchemaChangeAlterCounter("materialized_view_refresh"))
+
+	// The new indexes are copies of the current ones under new IDs. They are
+	// not referenced by the descriptor until the job swaps them in, so writing
+	// the descriptor here only reserves their IDs.
+	details := jobspb.RefreshMaterializedViewDetails{TableID: desc.ID}
+	newIndex := func(idx sqlbase.IndexDescriptor) sqlbase.IndexDescriptor {
+		details.OldIndexIDs = append(details.OldIndexIDs, idx.ID)
+		idx.ID = desc.NextIndexID
+		desc.NextIndexID++
+		return idx
+	}
+	details.NewPrimaryIndex = newIndex(desc.PrimaryIndex)
+	for _, idx := range desc.Indexes {
+		details.NewIndexes = append(details.NewIndexes, newIndex(idx))
+	}
+	if err := p.writeTableDesc(ctx, desc); err != nil {
+		return err
+	}
+
+	_, err = p.extendedEvalCtx.QueueJob(jobs.Record{
+		Description:   tree.AsStringWithFQNames(n.n, params.Ann()),
+		Username:      p.User(),
+		DescriptorIDs: sqlbase.IDs{desc.ID},
+		Details:       details,
+		Progress:      jobspb.RefreshMaterializedViewProgress{},
+	})
+	return err
+}
+
+func (*refreshMaterializedViewNode) Next(runParams) (bool, error) { return false, nil }
+func (*refreshMaterializedViewNode) Values() tree.Datums          { return tree.Datums{} }
+func (*refreshMaterializedViewNode) Close(context.Context)        {}
+
+// refreshMaterializedViewResumer implements the jobs.Resumer interface for
+// RefreshMaterializedView jobs. A new instance is created for each job.
+type refreshMaterializedViewResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &refreshMaterializedViewResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+func (r *refreshMaterializedViewResumer) Resume(
+	ctx context.Context, phs interface{}, resultsCh chan<- tree.Datums,
+) error {
+	p := phs.(*planner)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.RefreshMaterializedViewDetails)
+
+	var desc *sqlbase.TableDescriptor
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		var err error
+		desc, err = sqlbase.GetTableDescFromID(ctx, txn, details.TableID)
+		return err
+	}); err != nil {
+		return err
+	}
+	if desc.Dropped() {
+		return sqlbase.NewUndefinedRelationError(tree.NewUnqualifiedTableName(tree.Name(desc.Name)))
+	}
+
+	// A previous attempt at this job may have written some rows already.
+	if err := clearIndexes(ctx, execCfg.DB, desc, newIndexIDs(details)); err != nil {
+		return err
+	}
+
+	// Backfill a copy of the descriptor that only has the new indexes, at a
+	// single timestamp so that the contents are a consistent snapshot of the
+	// view query.
+	backfillDesc := protoutil.Clone(desc).(*sqlbase.TableDescriptor)
+	backfillDesc.PrimaryIndex = details.NewPrimaryIndex
+	backfillDesc.Indexes = details.NewIndexes
+	backfillDesc.CreateAsOfTime = execCfg.Clock.Now()
+	if err := backfillQueryIntoTable(ctx, execCfg, backfillDesc, desc.ViewQuery); err != nil {
+		return err
+	}
+
+	if _, err := execCfg.LeaseManager.Publish(ctx, details.TableID,
+		func(desc *sqlbase.MutableTableDescriptor) error {
+			if desc.Dropped() {
+				return sqlbase.NewUndefinedRelationError(tree.NewUnqualifiedTableName(tree.Name(desc.Name)))
+			}
+			if !sameIndexIDs(desc.TableDesc(), details.OldIndexIDs) {
+				return errors.Errorf("materialized view %q was modified while being refreshed", desc.Name)
+			}
+			for _, id := range details.OldIndexIDs {
+				desc.GCMutations = append(desc.GCMutations,
+					sqlbase.TableDescriptor_GCDescriptorMutation{IndexID: id})
+			}
+			desc.PrimaryIndex = details.NewPrimaryIndex
+			desc.Indexes = details.NewIndexes
+			return nil
+		}, nil /* logEvent */); err != nil {
+		return err
+	}
+
+	if err := r.gcIndexes(ctx, execCfg, details.OldIndexIDs); err != nil {
+		return err
+	}
+	execCfg.StatsRefresher.NotifyMutation(details.TableID, math.MaxInt32 /* rowsAffected */)
+	return nil
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. The view was never
+// changed to use the new indexes, so only whatever was written into them has
+// to be cleaned up.
+func (r *refreshMaterializedViewResumer) OnFailOrCancel(ctx context.Context, phs interface{}) error {
+	execCfg := phs.(*planner).ExecCfg()
+	details := r.job.Details().(jobspb.RefreshMaterializedViewDetails)
+	var desc *sqlbase.TableDescriptor
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		var err error
+		desc, err = sqlbase.GetTableDescFromID(ctx, txn, details.TableID)
+		return err
+	}); err != nil {
+		if errors.Is(err, sqlbase.ErrDescriptorNotFound) {
+			return nil
+		}
+		return err
+	}
+	if desc.Dropped() {
+		// Dropping the view cleans up all of its data.
+		return nil
+	}
+	return r.gcIndexes(ctx, execCfg, newIndexIDs(details))
+}
+
+// gcIndexes starts a GC job that deletes the data of the given indexes of the
+// view once the GC TTL has passed.
+func (r *refreshMaterializedViewResumer) gcIndexes(
+	ctx context.Context, execCfg *ExecutorConfig, indexIDs []sqlbase.IndexID,
+) error {
+	details := r.job.Details().(jobspb.RefreshMaterializedViewDetails)
+	gcDetails := jobspb.SchemaChangeGCDetails{ParentID: details.TableID}
+	dropTime := timeutil.Now().UnixNano()
+	for _, id := range indexIDs {
+		gcDetails.Indexes = append(gcDetails.Indexes,
+			jobspb.SchemaChangeGCDetails_DroppedIndex{IndexID: id, DropTime: dropTime})
+	}
+	payload := r.job.Payload()
+	return startGCJob(ctx, execCfg.DB, execCfg.JobRegistry, payload.Username, payload.Description, gcDetails)
+}
+
+// newIndexIDs returns the IDs of the indexes a refresh job fills.
+func newIndexIDs(details jobspb.RefreshMaterializedViewDetails) []sqlbase.IndexID {
+	ids := []sqlbase.IndexID{details.NewPrimaryIndex.ID}
+	for i := range details.NewIndexes {
+		ids = append(ids, details.NewIndexes[i].ID)
+	}
+	return ids
+}
+
+// sameIndexIDs returns whether desc's primary and secondary indexes have the
+// given IDs, primary index first.
+func sameIndexIDs(desc *sqlbase.TableDescriptor, ids []sqlbase.IndexID) bool {
+	if len(ids) != len(desc.Indexes)+1 || ids[0] != desc.PrimaryIndex.ID {
+		return false
+	}
+	for i := range desc.Indexes {
+		if ids[i+1] != desc.Indexes[i].ID {
+			return false
+		}
+	}
+	return true
+}
+
+// clearIndexes deletes all data in the given indexes of desc.
+func clearIndexes(
+	ctx context.Context, db *kv.DB, desc *sqlbase.TableDescriptor, indexIDs []sqlbase.IndexID,
+) error {
+	// ClearRange cannot be run in a transaction, so create a
+	// non-transactional batch to send the request.
+	b := &kv.Batch{}
+	for _, id := range indexIDs {
+		sp := desc.IndexSpan(id)
+		b.AddRawRequest(&roachpb.ClearRangeRequest{
+			RequestHeader: roachpb.RequestHeader{Key: sp.Key, EndKey: sp.EndKey},
+		})
+	}
+	return db.Run(ctx, b)
+}
+
+func init() {
+	jobs.RegisterConstructor(jobspb.TypeRefreshMaterializedView,
+		func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+			return &refreshMaterializedViewResumer{job: job}
+		})
+}
@@ -109,6 +109,9 @@ func (p *planner) renameColumn(
 				ctx, "column", oldName.String(), tableDesc.ParentID, tableRef.ID)
 		}
 	}
+	if err := checkColumnHasNoTriggerDependencies(tableDesc, col); err != nil {
+		return false, err
+	}
 	if *oldName == *newName {
 		// Noop.
 		return false, nil
@@ -162,6 +165,25 @@ func (p *planner) renameColumn(
 		}
 	}
 
+	// Rename the column in EXCLUDE constraints.
+	for i := range tableDesc.Exclusions {
+		c := &tableDesc.Exclusions[i]
+		for j := range c.Elements {
+			var err error
+			c.Elements[j].Expr, err = renameIn(c.Elements[j].Expr)
+			if err != nil {
+				return false, err
+			}
+		}
+		if c.Predicate != "" {
+			var err error
+			c.Predicate, err = renameIn(c.Predicate)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
 	// Rename the column in computed columns.
 	for i := range tableDesc.Columns {
 		if otherCol := &tableDesc.Columns[i]; otherCol.IsComputed() {
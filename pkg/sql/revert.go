@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
@@ -27,6 +28,10 @@ import (
 // TODO(dt): tune this via experimentation.
 const RevertTableDefaultBatchSize = 500000
 
+// revertTableMaxConcurrency is the number of ranges that RevertTables reverts
+// at once.
+const revertTableMaxConcurrency = 8
+
 // RevertTables reverts the passed table to the target time.
 func RevertTables(
 	ctx context.Context,
@@ -73,37 +78,98 @@ func RevertTables(
 		log.Infof(ctx, "reverting table %s (%d) to time %v", tables[i].Name, tables[i].ID, targetTime)
 	}
 
-	// TODO(dt): pre-split requests up using a rangedesc cache and run batches in
-	// parallel (since we're passing a key limit, distsender won't do its usual
-	// splitting/parallel sending to separate ranges).
-	for len(spans) != 0 {
-		var b kv.Batch
+	// Split the spans up along range boundaries and revert each piece on its
+	// own, since passing a key limit stops distsender from doing its usual
+	// splitting and parallel sending to separate ranges. Interleaved tables'
+	// spans overlap, so merge them first so no key is reverted twice.
+	spans, _ = roachpb.MergeSpans(spans)
+	pieces, err := splitSpansByRange(ctx, db, spans)
+	if err != nil {
+		return err
+	}
+
+	sem := make(chan struct{}, revertTableMaxConcurrency)
+	g := ctxgroup.WithContext(ctx)
+	for i := range pieces {
+		span := pieces[i]
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			_ = g.Wait()
+			return ctx.Err()
+		}
+		g.GoCtx(func(ctx context.Context) error {
+			defer func() { <-sem }()
+			return revertSpan(ctx, db, span, targetTime, batchSize)
+		})
+	}
+	return g.Wait()
+}
+
+// splitSpansByRange splits spans at the boundaries of the ranges they
+// currently touch. The boundaries are only a hint: ranges may split or merge
+// before the pieces are reverted, which is harmless.
+func splitSpansByRange(
+	ctx context.Context, db *kv.DB, spans []roachpb.Span,
+) ([]roachpb.Span, error) {
+	var pieces []roachpb.Span
+	if err := db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		pieces = pieces[:0]
 		for _, span := range spans {
-			b.AddRawRequest(&roachpb.RevertRangeRequest{
-				RequestHeader: roachpb.RequestHeader{
-					Key:    span.Key,
-					EndKey: span.EndKey,
-				},
-				TargetTime: targetTime,
-			})
+			ranges, err := ScanMetaKVs(ctx, txn, span)
+			if err != nil {
+				return err
+			}
+			// Table spans are never locally addressed, so they can be used as
+			// RSpans directly.
+			rspan := roachpb.RSpan{Key: roachpb.RKey(span.Key), EndKey: roachpb.RKey(span.EndKey)}
+			for _, r := range ranges {
+				var desc roachpb.RangeDescriptor
+				if err := r.ValueProto(&desc); err != nil {
+					return err
+				}
+				piece, err := rspan.Intersect(&desc)
+				if err != nil {
+					// The meta scan may return a range just past the span's end.
+					continue
+				}
+				pieces = append(pieces, piece.AsRawSpanWithNoLocals())
+			}
 		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return pieces, nil
+}
+
+// revertSpan reverts a single span to targetTime, issuing RevertRange requests
+// of at most batchSize keys until there is nothing left to revert.
+func revertSpan(
+	ctx context.Context, db *kv.DB, span roachpb.Span, targetTime hlc.Timestamp, batchSize int64,
+) error {
+	for {
+		var b kv.Batch
+		b.AddRawRequest(&roachpb.RevertRangeRequest{
+			RequestHeader: roachpb.RequestHeader{
+				Key:    span.Key,
+				EndKey: span.EndKey,
+			},
+			TargetTime: targetTime,
+		})
 		b.Header.MaxSpanRequestKeys = batchSize
 
 		if err := db.Run(ctx, &b); err != nil {
 			return err
 		}
 
-		spans = spans[:0]
-		for _, raw := range b.RawResponse().Responses {
-			r := raw.GetRevertRange()
-			if r.ResumeSpan != nil {
-				if !r.ResumeSpan.Valid() {
-					return errors.Errorf("invalid resume span: %s", r.ResumeSpan)
-				}
-				spans = append(spans, *r.ResumeSpan)
-			}
+		r := b.RawResponse().Responses[0].GetRevertRange()
+		if r.ResumeSpan == nil {
+			return nil
 		}
+		if !r.ResumeSpan.Valid() {
+			return errors.Errorf("invalid resume span: %s", r.ResumeSpan)
+		}
+		span = *r.ResumeSpan
 	}
-
-	return nil
 }
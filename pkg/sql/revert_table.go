@@ -0,0 +1,391 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprotectedts"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+type revertTableNode struct {
+	n *tree.RevertTable
+}
+
+// RevertTable rolls the contents of a table back to a past timestamp.
+// Privileges: DROP on table.
+func (p *planner) RevertTable(ctx context.Context, n *tree.RevertTable) (planNode, error) {
+	return &revertTableNode{n: n}, nil
+}
+
+// startExec takes the table offline, protects its revisions since the target
+// time from garbage collection and queues the job that performs the revert
+// once the transaction commits.
+func (n *revertTableNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+
+	// A table left offline by a failed or canceled revert can be reverted
+	// again.
+	tn := n.n.Table.ToTableName()
+	tableDesc, err := p.resolveMutableTableDescriptorAllowFailedRevert(
+		ctx, &tn, true /* required */, ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return err
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.DROP); err != nil {
+		return err
+	}
+
+	targetTime, err := p.EvalAsOfTimestamp(tree.AsOfClause{Expr: n.n.Timestamp})
+	if err != nil {
+		return err
+	}
+
+	if err := checkRevertableTable(tableDesc.TableDesc()); err != nil {
+		return err
+	}
+	// The descriptor as of the target time is read in its own transaction,
+	// which also fails with a useful error if the target time is already
+	// below the table's GC threshold.
+	var oldDesc *sqlbase.TableDescriptor
+	if err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		txn.SetFixedTimestamp(ctx, targetTime)
+		var err error
+		oldDesc, err = sqlbase.GetTableDescFromID(ctx, txn, tableDesc.ID)
+		return err
+	}); err != nil {
+		if errors.Is(err, sqlbase.ErrDescriptorNotFound) {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"cannot revert table %q to %s: it did not exist at that time", tableDesc.Name, targetTime)
+		}
+		return errors.Wrapf(err, "reading descriptor of table %q as of %s", tableDesc.Name, targetTime)
+	}
+	if err := checkRevertTableSchema(tableDesc.TableDesc(), oldDesc); err != nil {
+		return pgerror.Wrapf(err, pgcode.ObjectNotInPrerequisiteState,
+			"cannot revert table %q to %s", tableDesc.Name, targetTime)
+	}
+
+	telemetry.Inc(sqltelemetry.SchemaChangeAlterCounter("table_revert"))
+
+	tableDesc.State = sqlbase.TableDescriptor_OFFLINE
+	tableDesc.OfflineReason = "reverting"
+	if err := p.writeTableDesc(ctx, tableDesc); err != nil {
+		return err
+	}
+
+	ptsID := uuid.MakeV4()
+	job, err := p.extendedEvalCtx.QueueJob(jobs.Record{
+		Description:   tree.AsStringWithFQNames(n.n, params.Ann()),
+		Username:      p.User(),
+		DescriptorIDs: sqlbase.IDs{tableDesc.ID},
+		Details: jobspb.RevertTableDetails{
+			TableID:                  tableDesc.ID,
+			TargetTime:               targetTime,
+			ProtectedTimestampRecord: ptsID,
+		},
+		Progress: jobspb.RevertTableProgress{},
+	})
+	if err != nil {
+		return err
+	}
+	rec := jobsprotectedts.MakeRecord(ptsID, *job.ID(), targetTime,
+		[]roachpb.Span{tableDesc.TableSpan()})
+	return p.ExecCfg().ProtectedTimestampProvider.Protect(ctx, p.txn, rec)
+}
+
+// revertFailedOfflineReason is the offline reason of a table whose revert
+// failed or was canceled part way through.
+const revertFailedOfflineReason = "the revert to a previous time failed or was canceled, " +
+	"leaving the table partially reverted; revert it again with ALTER TABLE ... REVERT TO SYSTEM TIME " +
+	"or drop it"
+
+// resolveMutableTableDescriptorAllowFailedRevert is like
+// ResolveMutableTableDescriptor, except that it also resolves tables left
+// offline by a failed or canceled revert, which can only be reverted again or
+// dropped.
+func (p *planner) resolveMutableTableDescriptorAllowFailedRevert(
+	ctx context.Context, tn *ObjectName, required bool, requiredType ResolveRequiredType,
+) (*MutableTableDescriptor, error) {
+	lookupFlags := tree.ObjectLookupFlags{
+		CommonLookupFlags: tree.CommonLookupFlags{Required: required},
+		RequireMutable:    true,
+		IncludeOffline:    true,
+	}
+	desc, err := resolveExistingObjectImpl(ctx, p, tn, lookupFlags, requiredType)
+	if err != nil || desc == nil {
+		return nil, err
+	}
+	table := desc.(*MutableTableDescriptor)
+	if table.State == sqlbase.TableDescriptor_OFFLINE && table.OfflineReason != revertFailedOfflineReason {
+		// Other offline tables remain invisible.
+		if !required {
+			return nil, nil
+		}
+		return nil, sqlbase.NewUndefinedRelationError(tn)
+	}
+	return table, nil
+}
+
+func (*revertTableNode) Next(runParams) (bool, error) { return false, nil }
+func (*revertTableNode) Values() tree.Datums          { return tree.Datums{} }
+func (*revertTableNode) Close(context.Context)        {}
+
+// checkRevertableTable checks that the table can be reverted on its own:
+// rolling back one side of a foreign key or interleave would leave the other
+// side inconsistent, so such tables are refused.
+func checkRevertableTable(desc *sqlbase.TableDescriptor) error {
+	if !desc.IsPhysicalTable() {
+		return pgerror.Newf(pgcode.WrongObjectType, "%q is not a table", desc.Name)
+	}
+	if desc.IsInterleaved() {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot revert interleaved table %q", desc.Name)
+	}
+	for _, idx := range desc.AllNonDropIndexes() {
+		if len(idx.InterleavedBy) > 0 {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot revert table %q, which is interleaved by other tables", desc.Name)
+		}
+	}
+	if len(desc.OutboundFKs) > 0 || len(desc.InboundFKs) > 0 {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot revert table %q, which is part of a foreign key relationship", desc.Name)
+	}
+	if len(desc.Mutations) > 0 {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"cannot revert table %q while a schema change is in progress", desc.Name)
+	}
+	return nil
+}
+
+// checkRevertTableSchema checks that the data written under old can be read
+// back under cur, i.e. that no schema change that affects how rows are encoded
+// or which rows are valid happened in between. Renames and changes to column
+// defaults, partitioning and zone configs are allowed.
+func checkRevertTableSchema(cur, old *sqlbase.TableDescriptor) error {
+	if len(old.Mutations) > 0 {
+		return errors.New("a schema change was in progress at that time")
+	}
+	if old.PrimaryIndex.ID != cur.PrimaryIndex.ID {
+		return errors.New("the primary key has changed since then")
+	}
+
+	normalizeColumn := func(c sqlbase.ColumnDescriptor) sqlbase.ColumnDescriptor {
+		c.Name = ""
+		c.DefaultExpr = nil
+		c.UsesSequenceIds = nil
+		c.OwnsSequenceIds = nil
+		return c
+	}
+	oldCols := make(map[sqlbase.ColumnID]sqlbase.ColumnDescriptor, len(old.Columns))
+	for _, c := range old.Columns {
+		oldCols[c.ID] = normalizeColumn(c)
+	}
+	if len(oldCols) != len(cur.Columns) {
+		return errors.New("columns have been added or dropped since then")
+	}
+	for _, c := range cur.Columns {
+		oc, ok := oldCols[c.ID]
+		if !ok {
+			return errors.Newf("column %q has been added since then", c.Name)
+		}
+		if nc := normalizeColumn(c); !nc.Equal(&oc) {
+			return errors.Newf("column %q has been altered since then", c.Name)
+		}
+	}
+
+	normalizeIndex := func(idx sqlbase.IndexDescriptor) sqlbase.IndexDescriptor {
+		idx.Name = ""
+		idx.ColumnNames = nil
+		idx.StoreColumnNames = nil
+		idx.Partitioning = sqlbase.PartitioningDescriptor{}
+		return idx
+	}
+	oldIndexes := make(map[sqlbase.IndexID]sqlbase.IndexDescriptor)
+	for _, idx := range old.AllNonDropIndexes() {
+		oldIndexes[idx.ID] = normalizeIndex(*idx)
+	}
+	curIndexes := cur.AllNonDropIndexes()
+	if len(oldIndexes) != len(curIndexes) {
+		return errors.New("indexes have been added or dropped since then")
+	}
+	for _, idx := range curIndexes {
+		oi, ok := oldIndexes[idx.ID]
+		if !ok {
+			return errors.Newf("index %q has been added since then", idx.Name)
+		}
+		if ni := normalizeIndex(*idx); !ni.Equal(&oi) {
+			return errors.Newf("index %q has been altered since then", idx.Name)
+		}
+	}
+
+	normalizeFamily := func(f sqlbase.ColumnFamilyDescriptor) sqlbase.ColumnFamilyDescriptor {
+		f.Name = ""
+		f.ColumnNames = nil
+		return f
+	}
+	if len(old.Families) != len(cur.Families) {
+		return errors.New("column families have changed since then")
+	}
+	for i := range cur.Families {
+		nf, of := normalizeFamily(cur.Families[i]), normalizeFamily(old.Families[i])
+		if !nf.Equal(&of) {
+			return errors.New("column families have changed since then")
+		}
+	}
+
+	// Rows written before a CHECK constraint was added may violate it.
+	oldChecks := make(map[string]struct{}, len(old.Checks))
+	for _, c := range old.Checks {
+		oldChecks[c.Expr] = struct{}{}
+	}
+	for _, c := range cur.Checks {
+		if _, ok := oldChecks[c.Expr]; !ok {
+			return errors.Newf("check constraint %q has been added since then", c.Name)
+		}
+	}
+	return nil
+}
+
+// revertTableResumer implements the jobs.Resumer interface for RevertTable
+// jobs. A new instance is created for each job.
+type revertTableResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &revertTableResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+func (r *revertTableResumer) Resume(
+	ctx context.Context, phs interface{}, resultsCh chan<- tree.Datums,
+) error {
+	p := phs.(*planner)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.RevertTableDetails)
+
+	// Check that the revisions being reverted to were not garbage collected
+	// before the protected timestamp record took effect.
+	if err := execCfg.ProtectedTimestampProvider.Verify(ctx, details.ProtectedTimestampRecord); err != nil {
+		return err
+	}
+
+	// Wait for every node to see the table offline so that nothing writes to it
+	// while it is being reverted.
+	if _, err := execCfg.LeaseManager.WaitForOneVersion(
+		ctx, details.TableID, base.DefaultRetryOptions(),
+	); err != nil {
+		return err
+	}
+
+	var tableDesc *sqlbase.TableDescriptor
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		var err error
+		tableDesc, err = sqlbase.GetTableDescFromID(ctx, txn, details.TableID)
+		return err
+	}); err != nil {
+		return err
+	}
+	if err := RevertTables(
+		ctx, execCfg.DB, []*sqlbase.TableDescriptor{tableDesc}, details.TargetTime, RevertTableDefaultBatchSize,
+	); err != nil {
+		return err
+	}
+
+	if err := r.publishTable(ctx, execCfg); err != nil {
+		return err
+	}
+	r.releaseProtectedTimestamp(ctx, execCfg)
+	execCfg.StatsRefresher.NotifyMutation(details.TableID, math.MaxInt32 /* rowsAffected */)
+	return nil
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. The ranges of the table
+// are reverted independently, so the table may have been partially reverted:
+// some of its rows and index entries may be as of the target time and others
+// current, and its indexes may be inconsistent with each other. The table is
+// thus kept offline, with an offline reason telling the user to revert it
+// again or to drop it.
+func (r *revertTableResumer) OnFailOrCancel(ctx context.Context, phs interface{}) error {
+	execCfg := phs.(*planner).ExecCfg()
+	details := r.job.Details().(jobspb.RevertTableDetails)
+	if _, err := execCfg.LeaseManager.Publish(ctx, details.TableID,
+		func(desc *sqlbase.MutableTableDescriptor) error {
+			if desc.State != sqlbase.TableDescriptor_OFFLINE {
+				return errDidntUpdateDescriptor
+			}
+			desc.OfflineReason = revertFailedOfflineReason
+			return nil
+		}, nil /* logEvent */); err != nil {
+		return err
+	}
+	r.releaseProtectedTimestamp(ctx, execCfg)
+	return nil
+}
+
+// publishTable brings the table being reverted back online.
+func (r *revertTableResumer) publishTable(ctx context.Context, execCfg *ExecutorConfig) error {
+	details := r.job.Details().(jobspb.RevertTableDetails)
+	_, err := execCfg.LeaseManager.Publish(ctx, details.TableID,
+		func(desc *sqlbase.MutableTableDescriptor) error {
+			if desc.State != sqlbase.TableDescriptor_OFFLINE {
+				return errDidntUpdateDescriptor
+			}
+			desc.State = sqlbase.TableDescriptor_PUBLIC
+			desc.OfflineReason = ""
+			return nil
+		}, nil /* logEvent */)
+	return err
+}
+
+// releaseProtectedTimestamp releases the job's protected timestamp record. A
+// failure to do so is only logged, since the reconciliation loop cleans up
+// records of finished jobs.
+func (r *revertTableResumer) releaseProtectedTimestamp(
+	ctx context.Context, execCfg *ExecutorConfig,
+) {
+	details := r.job.Details().(jobspb.RevertTableDetails)
+	if details.ProtectedTimestampRecord == uuid.Nil {
+		return
+	}
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		return execCfg.ProtectedTimestampProvider.Release(ctx, txn, details.ProtectedTimestampRecord)
+	}); err != nil && !errors.Is(err, protectedts.ErrNotExists) {
+		log.Warningf(ctx, "failed to release protected timestamp record %v: %v",
+			details.ProtectedTimestampRecord, err)
+	}
+}
+
+func init() {
+	jobs.RegisterConstructor(jobspb.TypeRevertTable,
+		func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+			return &revertTableResumer{job: job}
+		})
+}
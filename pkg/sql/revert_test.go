@@ -13,13 +13,20 @@ package sql
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -114,3 +121,165 @@ func TestRevertTable(t *testing.T) {
 		})
 	})
 }
+
+func TestRevertTableStmt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	db := sqlutils.MakeSQLRunner(sqlDB)
+	db.Exec(t, `CREATE DATABASE d`)
+	db.Exec(t, `CREATE TABLE d.t (k INT PRIMARY KEY, v INT, INDEX (v))`)
+	db.Exec(t, `INSERT INTO d.t SELECT i, i FROM generate_series(1, 1000) AS g(i)`)
+	db.Exec(t, `ALTER TABLE d.t SPLIT AT VALUES (100), (500), (900)`)
+
+	var ts string
+	var before int
+	db.QueryRow(t, `SELECT cluster_logical_timestamp(), xor_agg(k # v) FROM d.t`).Scan(&ts, &before)
+
+	t.Run("revert", func(t *testing.T) {
+		db.Exec(t, `UPDATE d.t SET v = v + 1 WHERE k % 3 = 0`)
+		db.Exec(t, `DELETE FROM d.t WHERE k % 7 = 0`)
+		db.Exec(t, `INSERT INTO d.t SELECT i, i FROM generate_series(1001, 1500) AS g(i)`)
+		db.Exec(t, `ALTER TABLE d.t RENAME TO d.renamed`)
+
+		db.Exec(t, fmt.Sprintf(`ALTER TABLE d.renamed REVERT TO SYSTEM TIME %s`, ts))
+
+		var after int
+		db.QueryRow(t, `SELECT xor_agg(k # v) FROM d.renamed`).Scan(&after)
+		require.Equal(t, before, after, "expected reverted table to match its contents at the target time")
+		db.CheckQueryResults(t, `SELECT count(*) FROM d.renamed@t_v_idx WHERE v > 0`, [][]string{{"1000"}})
+
+		db.CheckQueryResults(t,
+			`SELECT status FROM [SHOW JOBS] WHERE job_type = 'REVERT TABLE'`, [][]string{{"succeeded"}})
+		db.CheckQueryResults(t, `SELECT count(*) FROM system.protected_ts_records`, [][]string{{"0"}})
+		db.Exec(t, `ALTER TABLE d.renamed RENAME TO d.t`)
+	})
+
+	t.Run("schema change", func(t *testing.T) {
+		db.Exec(t, `ALTER TABLE d.t ADD COLUMN w INT`)
+		db.ExpectErr(t, `columns have been added or dropped since then`,
+			fmt.Sprintf(`ALTER TABLE d.t REVERT TO SYSTEM TIME %s`, ts))
+		db.Exec(t, `ALTER TABLE d.t DROP COLUMN w`)
+	})
+
+	t.Run("did not exist", func(t *testing.T) {
+		db.Exec(t, `CREATE TABLE d.new (k INT PRIMARY KEY)`)
+		db.ExpectErr(t, `did not exist at that time`,
+			fmt.Sprintf(`ALTER TABLE d.new REVERT TO SYSTEM TIME %s`, ts))
+	})
+
+	t.Run("foreign key", func(t *testing.T) {
+		db.Exec(t, `CREATE TABLE d.child (k INT PRIMARY KEY REFERENCES d.t (k))`)
+		db.ExpectErr(t, `part of a foreign key relationship`,
+			`ALTER TABLE d.t REVERT TO SYSTEM TIME '-1us'`)
+	})
+
+	t.Run("future", func(t *testing.T) {
+		db.ExpectErr(t, `cannot specify timestamp in the future`,
+			`ALTER TABLE d.new REVERT TO SYSTEM TIME '+1h'`)
+	})
+}
+
+func TestRevertTableStmtCancel(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	defer func(oldInterval time.Duration) {
+		jobs.DefaultAdoptInterval = oldInterval
+	}(jobs.DefaultAdoptInterval)
+	jobs.DefaultAdoptInterval = 100 * time.Millisecond
+
+	// Stall the first RevertRange request sent to the table until the job is
+	// canceled, so that the job is canceled after the other ranges of the
+	// table have been reverted.
+	var tableSpan atomic.Value
+	tableSpan.Store(roachpb.Span{})
+	var stall int64
+	stalled := make(chan struct{}, 1)
+	params := base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			Store: &kvserver.StoreTestingKnobs{
+				TestingRequestFilter: func(ctx context.Context, ba roachpb.BatchRequest) *roachpb.Error {
+					for _, ru := range ba.Requests {
+						req, ok := ru.GetInner().(*roachpb.RevertRangeRequest)
+						if !ok || !tableSpan.Load().(roachpb.Span).ContainsKey(req.Key) {
+							continue
+						}
+						if atomic.CompareAndSwapInt64(&stall, 1, 0) {
+							stalled <- struct{}{}
+							<-ctx.Done()
+							return roachpb.NewError(ctx.Err())
+						}
+					}
+					return nil
+				},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	s, sqlDB, kvDB := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(ctx)
+
+	db := sqlutils.MakeSQLRunner(sqlDB)
+	db.Exec(t, `CREATE DATABASE d`)
+	db.Exec(t, `CREATE TABLE d.t (k INT PRIMARY KEY, v INT, INDEX (v))`)
+	db.Exec(t, `INSERT INTO d.t SELECT i, i FROM generate_series(1, 1000) AS g(i)`)
+	db.Exec(t, `ALTER TABLE d.t SPLIT AT VALUES (100), (500), (900)`)
+	tableSpan.Store(sqlbase.GetTableDescriptor(kvDB, "d", "t").TableSpan())
+
+	var ts string
+	var before int
+	db.QueryRow(t, `SELECT cluster_logical_timestamp(), xor_agg(k # v) FROM d.t`).Scan(&ts, &before)
+	db.Exec(t, `UPDATE d.t SET v = v + 1`)
+	revert := fmt.Sprintf(`ALTER TABLE d.t REVERT TO SYSTEM TIME %s`, ts)
+
+	// cancelRevert runs the revert and cancels its job part way through.
+	cancelRevert := func(t *testing.T) {
+		atomic.StoreInt64(&stall, 1)
+		errCh := make(chan error, 1)
+		go func() {
+			_, err := sqlDB.Exec(revert)
+			errCh <- err
+		}()
+		<-stalled
+		db.Exec(t, `CANCEL JOB (
+			SELECT job_id FROM [SHOW JOBS]
+			WHERE job_type = 'REVERT TABLE' AND status = $1
+		)`, jobs.StatusRunning)
+		require.Error(t, <-errCh)
+		testutils.SucceedsSoon(t, func() error {
+			var status string
+			db.QueryRow(t, `SELECT status FROM [SHOW JOBS]
+				WHERE job_type = 'REVERT TABLE' ORDER BY created DESC LIMIT 1`).Scan(&status)
+			if status != string(jobs.StatusCanceled) {
+				return errors.Errorf("expected job to be canceled, got %s", status)
+			}
+			return nil
+		})
+
+		// The partially reverted table is kept offline.
+		db.ExpectErr(t, `table "t" is offline: the revert to a previous time failed or was canceled`,
+			`SELECT * FROM d.t`)
+		db.CheckQueryResults(t, `SELECT count(*) FROM system.protected_ts_records`, [][]string{{"0"}})
+	}
+
+	t.Run("revert again", func(t *testing.T) {
+		cancelRevert(t)
+
+		db.Exec(t, revert)
+		var after int
+		db.QueryRow(t, `SELECT xor_agg(k # v) FROM d.t`).Scan(&after)
+		require.Equal(t, before, after, "expected reverted table to match its contents at the target time")
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		db.Exec(t, `UPDATE d.t SET v = v + 1`)
+		cancelRevert(t)
+
+		db.Exec(t, `DROP TABLE d.t`)
+		db.CheckQueryResults(t, `SELECT count(*) FROM [SHOW TABLES FROM d]`, [][]string{{"0"}})
+	})
+}
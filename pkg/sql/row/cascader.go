@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
 	"github.com/cockroachdb/cockroach/pkg/sql/schema"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
@@ -35,6 +36,10 @@ type cascader struct {
 	alloc    *sqlbase.DatumAlloc
 	evalCtx  *tree.EvalContext
 
+	// fkDeferrer, if set, is used by the foreign key checks of the row
+	// deleters and updaters (see FKDeferrer).
+	fkDeferrer FKDeferrer
+
 	indexPKRowFetchers map[TableID]map[sqlbase.IndexID]Fetcher // PK RowFetchers by Table ID and Index ID
 
 	// Row Deleters
@@ -47,6 +52,20 @@ type cascader struct {
 	updaterRowFetchers map[TableID]Fetcher                    // RowFetchers for rowUpdaters by Table ID
 	originalRows       map[TableID]*rowcontainer.RowContainer // Original values for rows that have been updated by Table ID
 	updatedRows        map[TableID]*rowcontainer.RowContainer // New values for rows that have been updated by Table ID
+
+	virtualCols map[TableID]*virtualColumns // Virtual column expressions by Table ID
+}
+
+// virtualColumns computes the values of the virtual computed columns of a
+// table. Virtual columns are not stored in the primary index, so they are
+// fetched as NULLs by the row fetchers of the cascader; their values are needed
+// to maintain the secondary indexes on them.
+type virtualColumns struct {
+	// exprs contains the computed expressions of the virtual columns, indexed
+	// like the columns of the table. It is nil if the table has no virtual
+	// columns.
+	exprs []tree.TypedExpr
+	iv    sqlbase.RowIndexedVarContainer
 }
 
 // makeDeleteCascader only creates a cascader if there is a chance that there is
@@ -120,6 +139,7 @@ func makeDeleteCascader(
 		updaterRowFetchers: make(map[TableID]Fetcher),
 		originalRows:       make(map[TableID]*rowcontainer.RowContainer),
 		updatedRows:        make(map[TableID]*rowcontainer.RowContainer),
+		virtualCols:        make(map[TableID]*virtualColumns),
 		evalCtx:            evalCtx,
 		alloc:              alloc,
 	}, nil
@@ -211,6 +231,7 @@ func makeUpdateCascader(
 		updaterRowFetchers: make(map[TableID]Fetcher),
 		originalRows:       make(map[TableID]*rowcontainer.RowContainer),
 		updatedRows:        make(map[TableID]*rowcontainer.RowContainer),
+		virtualCols:        make(map[TableID]*virtualColumns),
 		evalCtx:            evalCtx,
 		alloc:              alloc,
 	}, nil
@@ -450,6 +471,13 @@ func (c *cascader) addRowDeleter(
 		return rowDeleter, rowFetcher, nil
 	}
 
+	// The virtual columns are computed from the other columns of the table, so
+	// all the columns are needed if there are any.
+	var requestedCols []sqlbase.ColumnDescriptor
+	if hasVirtualColumns(table) {
+		requestedCols = table.Columns
+	}
+
 	// Create the row deleter. The row deleter is needed prior to the row fetcher
 	// as it will dictate what columns are required in the row fetcher.
 	rowDeleter, err := makeRowDeleterWithoutCascader(
@@ -457,13 +485,14 @@ func (c *cascader) addRowDeleter(
 		c.txn,
 		table,
 		c.fkTables,
-		nil, /* requestedCol */
+		requestedCols,
 		CheckFKs,
 		c.alloc,
 	)
 	if err != nil {
 		return Deleter{}, Fetcher{}, err
 	}
+	rowDeleter.SetFKDeferrer(c.fkDeferrer)
 
 	// Create the row fetcher that will retrive the rows and columns needed for
 	// deletion.
@@ -530,6 +559,7 @@ func (c *cascader) addRowUpdater(
 	if err != nil {
 		return Updater{}, Fetcher{}, err
 	}
+	rowUpdater.SetFKDeferrer(c.fkDeferrer)
 
 	// Create the row fetcher that will retrive the rows and columns needed for
 	// deletion.
@@ -564,6 +594,90 @@ func (c *cascader) addRowUpdater(
 	return rowUpdater, rowFetcher, nil
 }
 
+// addVirtualColumns creates the expressions used to compute the virtual
+// columns of the table.
+func (c *cascader) addVirtualColumns(
+	table *sqlbase.ImmutableTableDescriptor,
+) (*virtualColumns, error) {
+	// Are the expressions cached?
+	if vc, exists := c.virtualCols[table.ID]; exists {
+		return vc, nil
+	}
+
+	vc := &virtualColumns{}
+	if hasVirtualColumns(table) {
+		var txCtx transform.ExprTransformContext
+		computedExprs, err := sqlbase.MakeComputedExprs(
+			table.Columns,
+			table,
+			tree.NewUnqualifiedTableName(tree.Name(table.Name)),
+			&txCtx,
+			c.evalCtx,
+			false, /* addingCols */
+		)
+		if err != nil {
+			return nil, err
+		}
+		vc.exprs = make([]tree.TypedExpr, len(table.Columns))
+		vc.iv.Cols = table.Columns
+		for i := range table.Columns {
+			if table.Columns[i].Virtual {
+				vc.exprs[i] = computedExprs[i]
+			}
+		}
+	}
+
+	// Cache the expressions.
+	c.virtualCols[table.ID] = vc
+	return vc, nil
+}
+
+// hasVirtualColumns returns whether the table has virtual computed columns.
+func hasVirtualColumns(table *sqlbase.ImmutableTableDescriptor) bool {
+	for i := range table.Columns {
+		if table.Columns[i].Virtual {
+			return true
+		}
+	}
+	return false
+}
+
+// computeVirtualColumns sets the values of the virtual columns in the given
+// row, whose columns are laid out according to colIDtoRowIndex, from the values
+// of the other columns.
+func (c *cascader) computeVirtualColumns(
+	table *sqlbase.ImmutableTableDescriptor,
+	colIDtoRowIndex map[sqlbase.ColumnID]int,
+	row tree.Datums,
+) error {
+	vc, err := c.addVirtualColumns(table)
+	if err != nil {
+		return err
+	}
+	if vc.exprs == nil {
+		return nil
+	}
+	vc.iv.Mapping = colIDtoRowIndex
+	vc.iv.CurSourceRow = row
+	c.evalCtx.PushIVarContainer(&vc.iv)
+	defer c.evalCtx.PopIVarContainer()
+	for i, expr := range vc.exprs {
+		if expr == nil {
+			continue
+		}
+		rowIndex, exists := colIDtoRowIndex[table.Columns[i].ID]
+		if !exists {
+			continue
+		}
+		val, err := expr.Eval(c.evalCtx)
+		if err != nil {
+			return err
+		}
+		row[rowIndex] = val
+	}
+	return nil
+}
+
 // deleteRows performs row deletions on a single table for all rows that match
 // the values. Returns the values of the rows that were deleted. This deletion
 // happens in a single batch.
@@ -696,6 +810,11 @@ func (c *cascader) deleteRows(
 			if err != nil {
 				return nil, nil, 0, err
 			}
+			if err := c.computeVirtualColumns(
+				referencingTable, rowDeleter.FetchColIDtoRowIndex, rowToDelete,
+			); err != nil {
+				return nil, nil, 0, err
+			}
 
 			// Add the row to be checked for consistency changes.
 			if _, err := deletedRows.AddRow(ctx, rowToDelete); err != nil {
@@ -929,6 +1048,11 @@ func (c *cascader) updateRows(
 				if err != nil {
 					return nil, nil, nil, 0, err
 				}
+				if err := c.computeVirtualColumns(
+					referencingTable, rowUpdater.FetchColIDtoRowIndex, rowToUpdate,
+				); err != nil {
+					return nil, nil, nil, 0, err
+				}
 
 				updateRow := make(tree.Datums, len(rowUpdater.UpdateColIDtoRowIndex))
 				switch action {
@@ -992,6 +1116,14 @@ func (c *cascader) updateRows(
 					}
 				}
 
+				// The virtual columns that depend on the updated columns have new
+				// values.
+				if err := c.computeVirtualColumns(
+					referencingTable, rowUpdater.UpdateColIDtoRowIndex, updateRow,
+				); err != nil {
+					return nil, nil, nil, 0, err
+				}
+
 				// Is there something to update?  If not, skip it.
 				if !rowToUpdate.IsDistinctFrom(c.evalCtx, updateRow) {
 					continue
@@ -85,6 +85,17 @@ func MakeDeleter(
 	return rowDeleter, nil
 }
 
+// SetFKDeferrer sets the FKDeferrer used to defer the foreign key checks of
+// deferrable constraints, including the checks of the cascaded mutations.
+func (rd *Deleter) SetFKDeferrer(d FKDeferrer) {
+	if rd.Fks.checker != nil {
+		rd.Fks.checker.deferrer = d
+	}
+	if rd.cascader != nil {
+		rd.cascader.fkDeferrer = d
+	}
+}
+
 // makeRowDeleterWithoutCascader creates a rowDeleter but does not create an
 // additional cascader.
 func makeRowDeleterWithoutCascader(
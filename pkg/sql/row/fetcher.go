@@ -1427,8 +1427,10 @@ func (rf *Fetcher) finalizeRow() error {
 		}
 		if table.neededCols.Contains(int(table.cols[i].ID)) && table.row[i].IsUnset() {
 			// If the row was deleted, we'll be missing any non-primary key
-			// columns, including nullable ones, but this is expected.
-			if !table.cols[i].Nullable && !table.rowIsDeleted {
+			// columns, including nullable ones, but this is expected. Virtual
+			// columns are never stored in the primary index, so they are only
+			// present when they are decoded from a secondary index.
+			if !table.cols[i].Nullable && !table.cols[i].Virtual && !table.rowIsDeleted {
 				var indexColValues []string
 				for _, idx := range table.indexColIdx {
 					if idx != -1 {
@@ -73,6 +73,13 @@ type fkExistenceCheckBaseHelper struct {
 	// mutation, only the match style and name. Simplify this.
 	ref *sqlbase.ForeignKeyConstraint
 
+	// constraint is the foreign key constraint being checked. It is the same
+	// as ref for forward checks; for backward checks, ref is a fake
+	// constraint pointing to the referencing table and constraint is the
+	// backref it was derived from. This is used to defer the check (see
+	// FKDeferrer).
+	constraint *sqlbase.ForeignKeyConstraint
+
 	// searchTable is the descriptor of the searched table. Stored only
 	// for error messages; lookups use the pre-computed searchPrefix.
 	searchTable *sqlbase.ImmutableTableDescriptor
@@ -162,6 +169,7 @@ func makeFkExistenceCheckBaseHelper(
 		dir:           dir,
 		rf:            rf,
 		ref:           ref,
+		constraint:    ref,
 		searchTable:   searchTable,
 		searchIdx:     searchIdx,
 		mutatedIdx:    mutatedIdx,
@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
 )
@@ -39,6 +40,48 @@ type fkExistenceBatchChecker struct {
 	// batchIdxToFk maps the index of the check request/response in the kv batch
 	// to the fkExistenceCheckBaseHelper that created it.
 	batchIdxToFk []*fkExistenceCheckBaseHelper
+
+	// deferrer, if set, is used to defer the checks of deferrable constraints
+	// instead of reporting their violations.
+	deferrer FKDeferrer
+}
+
+// FKDeferrer is used by the foreign key existence checks to defer the checks
+// of deferrable constraints until the end of the transaction (see SET
+// CONSTRAINTS).
+type FKDeferrer interface {
+	// DeferFKViolation is called when the check of the given constraint fails
+	// for the given key values, which are in the order of the constraint
+	// columns. It returns true if the check is deferred, in which case the
+	// violation is not reported. mkErr creates the error to report if the key
+	// still violates the constraint when the deferred check is performed.
+	DeferFKViolation(
+		fk *sqlbase.ForeignKeyConstraint, keyVals tree.Datums, mkErr func(keyVals tree.Datums) error,
+	) bool
+}
+
+// maybeDefer returns true if the violation of the constraint checked by fk,
+// for the key values currently in fk.valuesScratch, is deferred.
+//
+// Backward checks are not deferred for RESTRICT actions, which are always
+// checked immediately.
+func (f *fkExistenceBatchChecker) maybeDefer(
+	fk *fkExistenceCheckBaseHelper, isUpdate bool, mkErr func(keyVals tree.Datums) error,
+) bool {
+	if f.deferrer == nil || fk.constraint.Deferrability() == tree.NotDeferrable {
+		return false
+	}
+	if fk.dir == CheckDeletes {
+		action := fk.constraint.OnDelete
+		if isUpdate {
+			action = fk.constraint.OnUpdate
+		}
+		if action == sqlbase.ForeignKeyReference_RESTRICT {
+			return false
+		}
+	}
+	keyVals := append(tree.Datums(nil), fk.valuesScratch...)
+	return f.deferrer.DeferFKViolation(fk.constraint, keyVals, mkErr)
 }
 
 // reset starts a new batch.
@@ -103,6 +146,14 @@ func (f *fkExistenceBatchChecker) runCheck(
 				for valueIdx, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
 					fk.valuesScratch[valueIdx] = newRow[fk.ids[colID]]
 				}
+				searchTableName, searchIdx := fk.searchTable.Name, fk.searchIdx
+				if f.maybeDefer(fk, oldRow != nil, func(keyVals tree.Datums) error {
+					return pgerror.Newf(pgcode.ForeignKeyViolation,
+						"foreign key violation: value %s not found in %s@%s %s",
+						keyVals, searchTableName, searchIdx.Name, searchIdx.ColumnNames[:len(keyVals)])
+				}) {
+					continue
+				}
 				return pgerror.Newf(pgcode.ForeignKeyViolation,
 					"foreign key violation: value %s not found in %s@%s %s (txn=%s)",
 					fk.valuesScratch, fk.searchTable.Name, fk.searchIdx.Name,
@@ -121,6 +172,14 @@ func (f *fkExistenceBatchChecker) runCheck(
 				for valueIdx, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
 					fk.valuesScratch[valueIdx] = oldRow[fk.ids[colID]]
 				}
+				searchTableName, mutatedIdx := fk.searchTable.Name, fk.mutatedIdx
+				if f.maybeDefer(fk, newRow != nil, func(keyVals tree.Datums) error {
+					return pgerror.Newf(pgcode.ForeignKeyViolation,
+						"foreign key violation: values %v in columns %s referenced in table %q",
+						keyVals, mutatedIdx.ColumnNames[:len(keyVals)], searchTableName)
+				}) {
+					continue
+				}
 				return pgerror.Newf(pgcode.ForeignKeyViolation,
 					"foreign key violation: values %v in columns %s referenced in table %q",
 					fk.valuesScratch, fk.mutatedIdx.ColumnNames[:fk.prefixLen], fk.searchTable.Name)
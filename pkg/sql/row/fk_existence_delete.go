@@ -91,6 +91,7 @@ func makeFkExistenceCheckHelperForDelete(
 		if err != nil {
 			return fkExistenceCheckForDelete{}, err
 		}
+		fk.constraint = ref
 		if h.fks == nil {
 			h.fks = make(map[sqlbase.IndexID][]fkExistenceCheckBaseHelper)
 		}
@@ -71,6 +71,14 @@ func MakeInserter(
 	return ri, nil
 }
 
+// SetFKDeferrer sets the FKDeferrer used to defer the foreign key checks of
+// deferrable constraints.
+func (ri *Inserter) SetFKDeferrer(d FKDeferrer) {
+	if ri.Fks.checker != nil {
+		ri.Fks.checker.deferrer = d
+	}
+}
+
 // insertCPutFn is used by insertRow when conflicts (i.e. the key already exists)
 // should generate errors.
 func insertCPutFn(
@@ -102,6 +102,17 @@ func MakeUpdater(
 	return rowUpdater, nil
 }
 
+// SetFKDeferrer sets the FKDeferrer used to defer the foreign key checks of
+// deferrable constraints, including the checks of the cascaded mutations.
+func (ru *Updater) SetFKDeferrer(d FKDeferrer) {
+	if ru.Fks.checker != nil {
+		ru.Fks.checker.deferrer = d
+	}
+	if ru.cascader != nil {
+		ru.cascader.fkDeferrer = d
+	}
+}
+
 type returnTrue struct{}
 
 func (returnTrue) Error() string { panic(errors.AssertionFailedf("unimplemented")) }
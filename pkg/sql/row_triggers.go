@@ -0,0 +1,682 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/errors"
+)
+
+// maxTriggerDepth is the maximum nesting depth of triggers fired by the
+// statements that AFTER triggers execute. It guards against infinite
+// recursion, e.g. a trigger that inserts into its own table.
+const maxTriggerDepth = 16
+
+// triggerDepthKey is the context key for the current trigger nesting depth.
+type triggerDepthKey struct{}
+
+// triggerRowContainer is a tree.IndexedVarContainer for the row seen by the
+// expressions of a trigger: the new values of the table's public columns,
+// followed by their old values.
+type triggerRowContainer struct {
+	cols []sqlbase.ColumnDescriptor
+	row  tree.Datums
+}
+
+var _ tree.IndexedVarContainer = &triggerRowContainer{}
+
+// IndexedVarEval implements the tree.IndexedVarContainer interface.
+func (c *triggerRowContainer) IndexedVarEval(idx int, _ *tree.EvalContext) (tree.Datum, error) {
+	return c.row[idx], nil
+}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (c *triggerRowContainer) IndexedVarResolvedType(idx int) *types.T {
+	return &c.cols[idx%len(c.cols)].Type
+}
+
+// IndexedVarNodeFormatter implements the tree.IndexedVarContainer interface.
+func (c *triggerRowContainer) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	prefix := "new"
+	if idx >= len(c.cols) {
+		prefix = "old"
+	}
+	return tree.NewUnresolvedName(prefix, c.cols[idx%len(c.cols)].Name)
+}
+
+// triggerColumnOrdinal returns the ordinal in a triggerRowContainer row of
+// the column named by a NEW.col or OLD.col reference in trigger t. ok is
+// false if the name is not such a reference.
+func triggerColumnOrdinal(
+	n *tree.UnresolvedName, t *sqlbase.TriggerDescriptor, cols []sqlbase.ColumnDescriptor,
+) (ord int, ok bool, _ error) {
+	if n.NumParts != 2 || n.Star {
+		return 0, false, nil
+	}
+	switch n.Parts[1] {
+	case "new":
+		if t.HasEvent(sqlbase.TriggerDescriptor_DELETE) {
+			return 0, false, pgerror.New(pgcode.InvalidObjectDefinition,
+				"DELETE triggers cannot reference NEW values")
+		}
+	case "old":
+		if t.HasEvent(sqlbase.TriggerDescriptor_INSERT) {
+			return 0, false, pgerror.New(pgcode.InvalidObjectDefinition,
+				"INSERT triggers cannot reference OLD values")
+		}
+		ord = len(cols)
+	default:
+		return 0, false, nil
+	}
+	for i := range cols {
+		if cols[i].Name == n.Parts[0] {
+			return ord + i, true, nil
+		}
+	}
+	return 0, false, sqlbase.NewUndefinedColumnError(tree.ErrString(n))
+}
+
+// compiledTrigger is a trigger whose expressions have been prepared for
+// execution.
+type compiledTrigger struct {
+	desc *sqlbase.TriggerDescriptor
+	// ord is the position of the trigger in the table's list of triggers.
+	ord int
+
+	// when is the WHEN condition, or nil if the trigger fires for every row.
+	when tree.TypedExpr
+
+	// setOrds are the ordinals among the table's public columns of the
+	// columns assigned by a BEFORE trigger, and setExprs their new values.
+	setOrds  []int
+	setExprs []tree.TypedExpr
+
+	// stmt is the statement executed by an AFTER trigger, in which the
+	// references to NEW and OLD have been replaced by placeholders. args
+	// contains the ordinals in the trigger row of the placeholder values.
+	stmt string
+	args []int
+}
+
+// compileTrigger type checks the expressions of a trigger on the table. It is
+// used both to validate a new trigger and to prepare the triggers of a table
+// before it is mutated.
+func compileTrigger(
+	t *sqlbase.TriggerDescriptor, desc *sqlbase.TableDescriptor, searchPath sessiondata.SearchPath,
+) (*compiledTrigger, error) {
+	cols := desc.Columns
+	ct := &compiledTrigger{desc: t}
+	container := &triggerRowContainer{cols: cols}
+	ivarHelper := tree.MakeIndexedVarHelper(container, 2*len(cols))
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.IVarContainer = container
+	semaCtx.SearchPath = searchPath
+
+	typeCheck := func(s string, typ *types.T, context string) (tree.TypedExpr, error) {
+		expr, err := parser.ParseExpr(s)
+		if err != nil {
+			return nil, err
+		}
+		expr, err = tree.SimpleVisit(expr, func(expr tree.Expr) (bool, tree.Expr, error) {
+			switch e := expr.(type) {
+			case *tree.UnresolvedName:
+				ord, ok, err := triggerColumnOrdinal(e, t, cols)
+				if err != nil {
+					return false, nil, err
+				}
+				if !ok {
+					return false, nil, pgerror.Newf(pgcode.InvalidColumnReference,
+						"column reference %s in %s must be qualified with NEW or OLD", tree.ErrString(e), context)
+				}
+				return false, ivarHelper.IndexedVar(ord), nil
+			case *tree.Subquery:
+				return false, nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					"subqueries are not allowed in %s", context)
+			}
+			return true, expr, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		semaCtx.Properties.Require(context, tree.RejectSpecial)
+		typedExpr, err := tree.TypeCheck(expr, &semaCtx, typ)
+		if err != nil {
+			return nil, err
+		}
+		if actual := typedExpr.ResolvedType(); !typ.Equivalent(actual) && typedExpr != tree.DNull {
+			return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+				"expected %s expression to have type %s, but '%s' has type %s", context, typ, expr, actual)
+		}
+		return typedExpr, nil
+	}
+
+	if t.WhenExpr != "" {
+		var err error
+		if ct.when, err = typeCheck(t.WhenExpr, types.Bool, "trigger WHEN condition"); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, a := range t.Assignments {
+		ord := -1
+		for i := range cols {
+			if cols[i].ID == a.ColumnID {
+				ord = i
+				break
+			}
+		}
+		if ord == -1 {
+			return nil, errors.AssertionFailedf(
+				"trigger %q assigns to non-public column %d", t.Name, errors.Safe(a.ColumnID))
+		}
+		if err := checkTriggerAssignableColumn(desc, &cols[ord]); err != nil {
+			return nil, err
+		}
+		expr, err := typeCheck(a.Expr, &cols[ord].Type, "trigger SET")
+		if err != nil {
+			return nil, err
+		}
+		ct.setOrds = append(ct.setOrds, ord)
+		ct.setExprs = append(ct.setExprs, expr)
+	}
+
+	if t.Statement != "" {
+		stmt, err := parser.ParseOne(t.Statement)
+		if err != nil {
+			return nil, err
+		}
+		argIdx := make(map[int]int)
+		ast, err := tree.SimpleStmtVisit(stmt.AST, func(expr tree.Expr) (bool, tree.Expr, error) {
+			n, ok := expr.(*tree.UnresolvedName)
+			if !ok {
+				return true, expr, nil
+			}
+			ord, ok, err := triggerColumnOrdinal(n, t, cols)
+			if err != nil || !ok {
+				return false, expr, err
+			}
+			idx, ok := argIdx[ord]
+			if !ok {
+				idx = len(ct.args)
+				argIdx[ord] = idx
+				ct.args = append(ct.args, ord)
+			}
+			return false, &tree.CastExpr{
+				Expr:       &tree.Placeholder{Idx: tree.PlaceholderIdx(idx)},
+				Type:       &cols[ord%len(cols)].Type,
+				SyntaxMode: tree.CastShort,
+			}, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		ct.stmt = tree.AsStringWithFlags(ast, tree.FmtParsable)
+	}
+	return ct, nil
+}
+
+// checkTriggerAssignableColumn returns an error if a BEFORE trigger cannot
+// assign to the column. The foreign key checks and the conflict detection of
+// a mutation are planned on the values of the row before the triggers fire,
+// so the triggers must not change the primary key, the keys of the unique
+// indexes, or the columns of the foreign keys.
+func checkTriggerAssignableColumn(
+	desc *sqlbase.TableDescriptor, col *sqlbase.ColumnDescriptor,
+) error {
+	for _, idx := range desc.AllNonDropIndexes() {
+		if !idx.Unique && !idx.UniqueDeferrable {
+			continue
+		}
+		for _, id := range idx.ColumnIDs {
+			if id == col.ID {
+				return pgerror.Newf(pgcode.InvalidObjectDefinition,
+					"trigger cannot assign to column %q of unique index %q", col.Name, idx.Name)
+			}
+		}
+	}
+	for i := range desc.OutboundFKs {
+		fk := &desc.OutboundFKs[i]
+		for _, id := range fk.OriginColumnIDs {
+			if id == col.ID {
+				return pgerror.Newf(pgcode.InvalidObjectDefinition,
+					"trigger cannot assign to column %q of foreign key %q", col.Name, fk.Name)
+			}
+		}
+	}
+	for i := range desc.InboundFKs {
+		fk := &desc.InboundFKs[i]
+		for _, id := range fk.ReferencedColumnIDs {
+			if id == col.ID {
+				return pgerror.Newf(pgcode.InvalidObjectDefinition,
+					"trigger cannot assign to column %q referenced by foreign key %q", col.Name, fk.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// shouldFire evaluates the WHEN condition of the trigger on the row in the
+// current IVarContainer.
+func (ct *compiledTrigger) shouldFire(evalCtx *tree.EvalContext) (bool, error) {
+	if ct.when == nil {
+		return true, nil
+	}
+	d, err := ct.when.Eval(evalCtx)
+	if err != nil {
+		return false, err
+	}
+	return d == tree.DBoolTrue, nil
+}
+
+// rowTriggers fires the row-level triggers of a table for the rows written
+// by a tableWriter. BEFORE triggers run when a row is queued in the KV batch
+// and can modify its new values. AFTER triggers are queued as well, and run
+// in the order their rows were written once the last batch of the statement
+// has been sent, in the same transaction.
+//
+// Rows written by foreign key cascades do not fire triggers, so the foreign
+// keys of a table cannot have actions that write rows which would fire its
+// triggers (see TableDescriptor.validateTriggerFKActions).
+type rowTriggers struct {
+	desc *sqlbase.ImmutableTableDescriptor
+	// triggers contains the compiled triggers of the table, in firing order.
+	triggers []*compiledTrigger
+	// before and after contain the triggers fired by each event.
+	before, after map[sqlbase.TriggerDescriptor_Event][]*compiledTrigger
+
+	// dbName is the name of the table's database, which is the current
+	// database of the statements executed by AFTER triggers.
+	dbName string
+
+	// colIdx maps the IDs of the table's public columns to their ordinals.
+	colIdx map[sqlbase.ColumnID]int
+	// computeExprs and checkHelper are used to recompute the computed columns
+	// and to re-validate the CHECK constraints of a row after BEFORE triggers
+	// have modified it. computeExprs is nil if no trigger assigns to a column
+	// or if the table has no computed columns.
+	computeExprs []tree.TypedExpr
+	checkHelper  *sqlbase.CheckHelper
+
+	evalCtx *tree.EvalContext
+	// container holds the row seen by the expressions of the triggers.
+	container triggerRowContainer
+	// setValues is a scratch buffer for the values assigned by a trigger.
+	setValues tree.Datums
+
+	// pending contains the AFTER trigger firings that have not run yet. Each
+	// row is a trigger row followed by the ordinal of the trigger.
+	pending    *rowcontainer.RowContainer
+	pendingRow tree.Datums
+}
+
+// makeRowTriggers compiles the triggers of a table that is about to be
+// mutated. It returns nil if the table has no triggers.
+func makeRowTriggers(
+	ctx context.Context, p *planner, desc *sqlbase.ImmutableTableDescriptor,
+) (*rowTriggers, error) {
+	if len(desc.Triggers) == 0 {
+		return nil, nil
+	}
+	rt := &rowTriggers{
+		desc:   desc,
+		before: make(map[sqlbase.TriggerDescriptor_Event][]*compiledTrigger),
+		after:  make(map[sqlbase.TriggerDescriptor_Event][]*compiledTrigger),
+		colIdx: make(map[sqlbase.ColumnID]int, len(desc.Columns)),
+	}
+	for i := range desc.Columns {
+		rt.colIdx[desc.Columns[i].ID] = i
+	}
+
+	assigns := false
+	for i := range desc.Triggers {
+		t := &desc.Triggers[i]
+		ct, err := compileTrigger(t, desc.TableDesc(), p.SessionData().SearchPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "trigger %q", t.Name)
+		}
+		ct.ord = i
+		rt.triggers = append(rt.triggers, ct)
+		byEvent := rt.after
+		if t.ActionTime == sqlbase.TriggerDescriptor_BEFORE {
+			byEvent = rt.before
+			assigns = assigns || len(ct.setOrds) > 0
+		}
+		for _, e := range t.Events {
+			byEvent[e] = append(byEvent[e], ct)
+		}
+	}
+
+	if assigns {
+		tn := tree.MakeUnqualifiedTableName(tree.Name(desc.Name))
+		var err error
+		rt.computeExprs, err = sqlbase.MakeComputedExprs(
+			desc.Columns, desc, &tn, &p.txCtx, p.EvalContext(), false, /* addingCols */
+		)
+		if err != nil {
+			return nil, err
+		}
+		if rt.checkHelper, err = sqlbase.NewEvalCheckHelper(ctx, p.analyzeExpr, desc); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(rt.after) > 0 {
+		dbDesc, err := p.Tables().databaseCache.getDatabaseDescByID(ctx, p.txn, desc.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		rt.dbName = dbDesc.Name
+	}
+	return rt, nil
+}
+
+// hasAfterTriggers returns whether any AFTER triggers can fire. It is nil
+// safe.
+func (rt *rowTriggers) hasAfterTriggers() bool {
+	return rt != nil && len(rt.after) > 0
+}
+
+// extendUpdateCols returns updateCols extended with the columns that BEFORE
+// UPDATE triggers can modify: the columns they assign to, and the computed
+// columns that have to be recomputed when they do. The values of the added
+// columns start out as their existing values.
+func (rt *rowTriggers) extendUpdateCols(
+	updateCols []sqlbase.ColumnDescriptor,
+) []sqlbase.ColumnDescriptor {
+	if rt == nil {
+		return updateCols
+	}
+	var ords util.FastIntSet
+	for _, ct := range rt.before[sqlbase.TriggerDescriptor_UPDATE] {
+		for _, ord := range ct.setOrds {
+			ords.Add(ord)
+		}
+	}
+	if ords.Empty() {
+		return updateCols
+	}
+	for i := range rt.desc.Columns {
+		if rt.desc.Columns[i].IsComputed() {
+			ords.Add(i)
+		}
+	}
+	for i := range updateCols {
+		if ord, ok := rt.colIdx[updateCols[i].ID]; ok {
+			ords.Remove(ord)
+		}
+	}
+	if ords.Empty() {
+		return updateCols
+	}
+	extended := append([]sqlbase.ColumnDescriptor(nil), updateCols...)
+	ords.ForEach(func(ord int) {
+		extended = append(extended, rt.desc.Columns[ord])
+	})
+	return extended
+}
+
+// init prepares the triggers for execution. It is nil safe.
+func (rt *rowTriggers) init(evalCtx *tree.EvalContext) {
+	if rt == nil {
+		return
+	}
+	rt.evalCtx = evalCtx
+	cols := rt.desc.Columns
+	rt.container = triggerRowContainer{cols: cols, row: make(tree.Datums, 2*len(cols))}
+	if len(rt.after) > 0 {
+		typs := make([]types.T, 0, 2*len(cols)+1)
+		for i := 0; i < 2; i++ {
+			for j := range cols {
+				typs = append(typs, cols[j].Type)
+			}
+		}
+		typs = append(typs, *types.Int)
+		rt.pending = rowcontainer.NewRowContainer(
+			evalCtx.Mon.MakeBoundAccount(), sqlbase.ColTypeInfoFromColTypes(typs), 0,
+		)
+		rt.pendingRow = make(tree.Datums, len(typs))
+	}
+}
+
+// fire runs the BEFORE triggers of a row and queues its AFTER triggers.
+// newVals and oldVals contain the new and old values of the row, at the
+// positions given by newColIdx and oldColIdx. newVals is nil for deleted
+// rows, and oldVals is nil for inserted rows. Columns of an updated row that
+// are missing from newVals keep their old values.
+//
+// The values assigned by BEFORE triggers are written back into newVals,
+// which must therefore contain all the columns they can modify; see
+// extendUpdateCols.
+func (rt *rowTriggers) fire(
+	ctx context.Context,
+	event sqlbase.TriggerDescriptor_Event,
+	newVals tree.Datums,
+	newColIdx map[sqlbase.ColumnID]int,
+	oldVals tree.Datums,
+	oldColIdx map[sqlbase.ColumnID]int,
+) error {
+	before, after := rt.before[event], rt.after[event]
+	if len(before) == 0 && len(after) == 0 {
+		return nil
+	}
+
+	cols := rt.desc.Columns
+	row := rt.container.row
+	for i := range cols {
+		newVal, oldVal := tree.Datum(tree.DNull), tree.Datum(tree.DNull)
+		if j, ok := oldColIdx[cols[i].ID]; ok && j < len(oldVals) {
+			oldVal = oldVals[j]
+		}
+		if j, ok := newColIdx[cols[i].ID]; ok && j < len(newVals) {
+			newVal = newVals[j]
+		} else if event == sqlbase.TriggerDescriptor_UPDATE {
+			newVal = oldVal
+		}
+		row[i], row[len(cols)+i] = newVal, oldVal
+	}
+
+	rt.evalCtx.PushIVarContainer(&rt.container)
+	defer rt.evalCtx.PopIVarContainer()
+
+	modified := false
+	for _, ct := range before {
+		if ok, err := ct.shouldFire(rt.evalCtx); err != nil {
+			return errors.Wrapf(err, "trigger %q", ct.desc.Name)
+		} else if !ok {
+			continue
+		}
+		// All the new values are computed before any of them is assigned,
+		// like in an UPDATE.
+		rt.setValues = rt.setValues[:0]
+		for _, expr := range ct.setExprs {
+			d, err := expr.Eval(rt.evalCtx)
+			if err != nil {
+				return errors.Wrapf(err, "trigger %q", ct.desc.Name)
+			}
+			rt.setValues = append(rt.setValues, d)
+		}
+		for i, ord := range ct.setOrds {
+			row[ord] = rt.setValues[i]
+			modified = true
+		}
+	}
+
+	if modified {
+		if err := rt.validateNewRow(); err != nil {
+			return err
+		}
+		for i := range cols {
+			if j, ok := newColIdx[cols[i].ID]; ok && j < len(newVals) {
+				newVals[j] = row[i]
+			}
+		}
+	}
+
+	for _, ct := range after {
+		if ok, err := ct.shouldFire(rt.evalCtx); err != nil {
+			return errors.Wrapf(err, "trigger %q", ct.desc.Name)
+		} else if !ok {
+			continue
+		}
+		copy(rt.pendingRow, row)
+		rt.pendingRow[len(row)] = tree.NewDInt(tree.DInt(ct.ord))
+		if _, err := rt.pending.AddRow(ctx, rt.pendingRow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateNewRow recomputes the computed columns of the new row after it was
+// modified by BEFORE triggers, and checks it against the constraints of the
+// table.
+func (rt *rowTriggers) validateNewRow() error {
+	cols := rt.desc.Columns
+	newRow := rt.container.row[:len(cols)]
+	for i, expr := range rt.computeExprs {
+		if !cols[i].IsComputed() {
+			continue
+		}
+		d, err := expr.Eval(rt.evalCtx)
+		if err != nil {
+			return errors.Wrapf(err, "computed column %s", tree.ErrString((*tree.Name)(&cols[i].Name)))
+		}
+		newRow[i] = d
+	}
+	if err := enforceLocalColumnConstraints(newRow, cols); err != nil {
+		return err
+	}
+	if rt.checkHelper != nil {
+		if err := rt.checkHelper.LoadEvalRow(rt.colIdx, newRow, false /* merge */); err != nil {
+			return err
+		}
+		if err := rt.checkHelper.CheckEval(rt.evalCtx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runAfterTriggers executes the statements of the queued AFTER triggers in
+// the given transaction. It is nil safe.
+func (rt *rowTriggers) runAfterTriggers(ctx context.Context, txn *kv.Txn) error {
+	if rt == nil || rt.pending == nil || rt.pending.Len() == 0 {
+		return nil
+	}
+	depth, _ := ctx.Value(triggerDepthKey{}).(int)
+	if depth >= maxTriggerDepth {
+		return pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"triggers on table %q exceed the maximum nesting depth of %d", rt.desc.Name, maxTriggerDepth)
+	}
+	ctx = context.WithValue(ctx, triggerDepthKey{}, depth+1)
+
+	ie := rt.evalCtx.InternalExecutor.(*InternalExecutor)
+	ordIdx := 2 * len(rt.desc.Columns)
+	for i := 0; i < rt.pending.Len(); i++ {
+		row := rt.pending.At(i)
+		ct := rt.triggers[int(tree.MustBeDInt(row[ordIdx]))]
+		args := make([]interface{}, len(ct.args))
+		for j, ord := range ct.args {
+			args[j] = row[ord]
+		}
+		if _, err := ie.ExecEx(
+			ctx, "trigger", txn,
+			sqlbase.InternalExecutorSessionDataOverride{Database: rt.dbName},
+			ct.stmt, args...,
+		); err != nil {
+			return errors.Wrapf(err, "trigger %q", ct.desc.Name)
+		}
+	}
+	rt.pending.Clear(ctx)
+	return nil
+}
+
+// close releases the memory held by the triggers. It is nil safe.
+func (rt *rowTriggers) close(ctx context.Context) {
+	if rt != nil && rt.pending != nil {
+		rt.pending.Close(ctx)
+		rt.pending = nil
+	}
+}
+
+// checkColumnHasNoTriggerDependencies returns an error if a trigger of the
+// table assigns to or references the given column.
+func checkColumnHasNoTriggerDependencies(
+	desc *sqlbase.MutableTableDescriptor, col *sqlbase.ColumnDescriptor,
+) error {
+	refErr := func(t *sqlbase.TriggerDescriptor) error {
+		return pgerror.Newf(pgcode.InvalidColumnReference,
+			"column %q is referenced by trigger %q", col.Name, t.Name)
+	}
+	visit := func(expr tree.Expr) (bool, tree.Expr, error) {
+		if n, ok := expr.(*tree.UnresolvedName); ok {
+			if n.NumParts == 2 && !n.Star && n.Parts[0] == col.Name &&
+				(n.Parts[1] == "new" || n.Parts[1] == "old") {
+				return false, expr, errColumnReferenced
+			}
+			return false, expr, nil
+		}
+		return true, expr, nil
+	}
+	for i := range desc.Triggers {
+		t := &desc.Triggers[i]
+		exprs := make([]string, 0, len(t.Assignments)+1)
+		if t.WhenExpr != "" {
+			exprs = append(exprs, t.WhenExpr)
+		}
+		for _, a := range t.Assignments {
+			if a.ColumnID == col.ID {
+				return refErr(t)
+			}
+			exprs = append(exprs, a.Expr)
+		}
+		for _, s := range exprs {
+			expr, err := parser.ParseExpr(s)
+			if err != nil {
+				return errors.WithAssertionFailure(err)
+			}
+			if _, err := tree.SimpleVisit(expr, visit); err != nil {
+				if errors.Is(err, errColumnReferenced) {
+					return refErr(t)
+				}
+				return err
+			}
+		}
+		if t.Statement != "" {
+			stmt, err := parser.ParseOne(t.Statement)
+			if err != nil {
+				return errors.WithAssertionFailure(err)
+			}
+			if _, err := tree.SimpleStmtVisit(stmt.AST, visit); err != nil {
+				if errors.Is(err, errColumnReferenced) {
+					return refErr(t)
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// errColumnReferenced is used by checkColumnHasNoTriggerDependencies to stop
+// walking an expression once a reference to the column has been found.
+var errColumnReferenced = errors.New("column referenced")
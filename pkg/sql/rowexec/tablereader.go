@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
@@ -54,6 +55,11 @@ type tableReader struct {
 
 	// rowsRead is the number of rows read and is tracked unconditionally.
 	rowsRead int64
+
+	// tableID and indexID identify the scanned index, for the index usage
+	// statistics.
+	tableID sqlbase.ID
+	indexID sqlbase.IndexID
 }
 
 var _ execinfra.Processor = &tableReader{}
@@ -115,12 +121,15 @@ func newTableReader(
 
 	var fetcher row.Fetcher
 	columnIdxMap := spec.Table.ColumnIdxMapWithMutations(returnMutations)
-	if _, _, err := initRowFetcher(
+	index, _, err := initRowFetcher(
 		&fetcher, &spec.Table, int(spec.IndexIdx), columnIdxMap, spec.Reverse,
 		neededColumns, spec.IsCheck, &tr.alloc, spec.Visibility, spec.LockingStrength,
-	); err != nil {
+	)
+	if err != nil {
 		return nil, err
 	}
+	tr.tableID = spec.Table.ID
+	tr.indexID = index.ID
 
 	nSpans := len(spec.Spans)
 	if cap(tr.spans) >= nSpans {
@@ -155,6 +164,7 @@ func (tr *tableReader) Start(ctx context.Context) context.Context {
 	}
 
 	ctx = tr.StartInternal(ctx, tableReaderProcName)
+	tr.FlowCtx.Cfg.IndexUsageStats.RecordRead(tr.tableID, tr.indexID, idxusage.ScanRead)
 
 	limitBatches := execinfra.ScanShouldLimitBatches(tr.maxResults, tr.limitHint, tr.FlowCtx)
 	log.VEventf(ctx, 1, "starting scan with limitBatches %t", limitBatches)
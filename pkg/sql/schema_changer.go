@@ -207,16 +207,24 @@ func (sc *SchemaChanger) maybeBackfillCreateTableAs(
 	if !(table.Adding() && table.IsAs()) {
 		return nil
 	}
+	return backfillQueryIntoTable(ctx, sc.execCfg, table, table.CreateQuery)
+}
 
-	return sc.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+// backfillQueryIntoTable runs query as of table.CreateAsOfTime and writes its
+// results into all of table's indexes, at that same timestamp. It is used to
+// populate the tables created by CREATE TABLE ... AS and materialized views.
+func backfillQueryIntoTable(
+	ctx context.Context, execCfg *ExecutorConfig, table *sqlbase.TableDescriptor, query string,
+) error {
+	return execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
 		txn.SetFixedTimestamp(ctx, table.CreateAsOfTime)
 
 		// Create an internal planner as the planner used to serve the user query
 		// would have committed by this point.
-		p, cleanup := NewInternalPlanner("ctasBackfill", txn, security.RootUser, &MemoryMetrics{}, sc.execCfg)
+		p, cleanup := NewInternalPlanner("ctasBackfill", txn, security.RootUser, &MemoryMetrics{}, execCfg)
 		defer cleanup()
 		localPlanner := p.(*planner)
-		stmt, err := parser.ParseOne(table.CreateQuery)
+		stmt, err := parser.ParseOne(query)
 		if err != nil {
 			return err
 		}
@@ -249,11 +257,11 @@ func (sc *SchemaChanger) maybeBackfillCreateTableAs(
 			ctx,
 			rw,
 			tree.Rows,
-			sc.execCfg.RangeDescriptorCache,
-			sc.execCfg.LeaseHolderCache,
+			execCfg.RangeDescriptorCache,
+			execCfg.LeaseHolderCache,
 			txn,
 			func(ts hlc.Timestamp) {
-				sc.clock.Update(ts)
+				execCfg.Clock.Update(ts)
 			},
 			// Make a session tracing object on-the-fly. This is OK
 			// because it sets "enabled: false" and thus none of the
@@ -262,12 +270,12 @@ func (sc *SchemaChanger) maybeBackfillCreateTableAs(
 		)
 		defer recv.Release()
 
-		rec, err := sc.distSQLPlanner.checkSupportForNode(localPlanner.curPlan.plan)
+		rec, err := execCfg.DistSQLPlanner.checkSupportForNode(localPlanner.curPlan.plan)
 		var planAndRunErr error
 		localPlanner.runWithOptions(resolveFlags{skipCache: true}, func() {
 			// Resolve subqueries before running the queries' physical plan.
 			if len(localPlanner.curPlan.subqueryPlans) != 0 {
-				if !sc.distSQLPlanner.PlanAndRunSubqueries(
+				if !execCfg.DistSQLPlanner.PlanAndRunSubqueries(
 					ctx, localPlanner, localPlanner.ExtendedEvalContextCopy,
 					localPlanner.curPlan.subqueryPlans, recv, rec == canDistribute,
 				) {
@@ -285,7 +293,7 @@ func (sc *SchemaChanger) maybeBackfillCreateTableAs(
 				Table: *table,
 			}}
 
-			PlanAndRunCTAS(ctx, sc.distSQLPlanner, localPlanner,
+			PlanAndRunCTAS(ctx, execCfg.DistSQLPlanner, localPlanner,
 				txn, isLocal, localPlanner.curPlan.plan, out, recv)
 			if planAndRunErr = rw.Err(); planAndRunErr != nil {
 				return
@@ -1277,6 +1285,23 @@ func (sc *SchemaChanger) maybeDropValidatingConstraint(
 				constraint.ForeignKey.Name,
 			)
 		}
+	case sqlbase.ConstraintToUpdate_EXCLUSION:
+		if constraint.Exclusion.Validity == sqlbase.ConstraintValidity_Unvalidated {
+			return nil
+		}
+		for i, c := range desc.Exclusions {
+			if c.Name == constraint.Exclusion.Name {
+				desc.Exclusions = append(desc.Exclusions[:i], desc.Exclusions[i+1:]...)
+				return nil
+			}
+		}
+		if log.V(2) {
+			log.Infof(
+				ctx,
+				"attempted to drop constraint %s, but it hadn't been added to the table descriptor yet",
+				constraint.Exclusion.Name,
+			)
+		}
 	default:
 		return errors.AssertionFailedf("unsupported constraint type: %d", errors.Safe(constraint.ConstraintType))
 	}
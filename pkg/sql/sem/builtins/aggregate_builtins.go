@@ -214,6 +214,32 @@ var aggregates = map[string]builtinDefinition{
 				"Identifies the minimum selected value.")
 		}),
 
+	"mode": makeBuiltin(aggProps(),
+		makeOrderedSetAggOverload(nil, types.Any,
+			"Returns the most frequent value of the WITHIN GROUP ordering expression. "+
+				"Ties are broken by picking the first value in the WITHIN GROUP order."),
+	),
+
+	"percentile_disc": makeBuiltin(aggProps(),
+		makeOrderedSetAggOverload([]*types.T{types.Float}, types.Any,
+			"Returns the first value of the WITHIN GROUP ordering expression whose position "+
+				"in the ordering equals or exceeds the specified fraction."),
+		makeOrderedSetAggOverload([]*types.T{types.MakeArray(types.Float)}, types.AnyArray,
+			"Returns an array of the first values of the WITHIN GROUP ordering expression "+
+				"whose positions in the ordering equal or exceed the specified fractions."),
+	),
+
+	"percentile_cont": makeBuiltin(aggProps(),
+		makeOrderedSetAggOverload([]*types.T{types.Float}, types.Float,
+			"Returns the value of the FLOAT or INTERVAL WITHIN GROUP ordering expression "+
+				"corresponding to the specified fraction, interpolating between adjacent values "+
+				"if needed."),
+		makeOrderedSetAggOverload([]*types.T{types.MakeArray(types.Float)}, types.MakeArray(types.Float),
+			"Returns an array of the values of the FLOAT or INTERVAL WITHIN GROUP ordering "+
+				"expression corresponding to the specified fractions, interpolating between "+
+				"adjacent values if needed."),
+	),
+
 	"string_agg": makeBuiltin(aggPropsNullableArgs(),
 		makeAggOverload([]*types.T{types.String, types.String}, types.String, newStringConcatAggregate,
 			"Concatenates all selected values using the provided delimiter."),
@@ -288,6 +314,55 @@ var aggregates = map[string]builtinDefinition{
 		),
 	)),
 
+	// The *_impl aggregates implement the ordered-set and hypothetical-set
+	// aggregates used with WITHIN GROUP, to which they are rewritten by the
+	// optimizer. See ordered_set_aggregate_builtins.go.
+	"percentile_disc_impl": makePrivate(makeBuiltin(aggPropsNullableArgs(),
+		makePercentileImplOverloads(types.Scalar, newPercentileDiscAggregate)...,
+	)),
+
+	"percentile_cont_impl": makePrivate(makeBuiltin(aggPropsNullableArgs(),
+		makePercentileImplOverloads(
+			[]*types.T{types.Float, types.Interval}, newPercentileContAggregate,
+		)...,
+	)),
+
+	"mode_impl": makePrivate(collectOverloads(aggProps(), types.Scalar,
+		func(t *types.T) tree.Overload {
+			return makeAggOverloadWithReturnType(
+				[]*types.T{t},
+				func(args []tree.TypedExpr) *types.T {
+					if len(args) == 0 {
+						return t
+					}
+					return args[0].ResolvedType()
+				},
+				newModeAggregate,
+				"Returns the most frequent of the selected values.",
+			)
+		})),
+
+	"rank_impl": makePrivate(makeBuiltin(aggPropsNullableArgs(),
+		makeAggOverload([]*types.T{types.Bool}, types.Int, newHypotheticalRankAggregate,
+			"Calculates the rank of a hypothetical row."),
+	)),
+
+	"dense_rank_impl": makePrivate(makeBuiltin(aggPropsNullableArgs(),
+		makeAggOverload([]*types.T{types.Bool, types.Any}, types.Int,
+			newHypotheticalDenseRankAggregate,
+			"Calculates the rank of a hypothetical row, without gaps."),
+	)),
+
+	"percent_rank_impl": makePrivate(makeBuiltin(aggPropsNullableArgs(),
+		makeAggOverload([]*types.T{types.Bool}, types.Float, newHypotheticalPercentRankAggregate,
+			"Calculates the relative rank of a hypothetical row."),
+	)),
+
+	"cume_dist_impl": makePrivate(makeBuiltin(aggPropsNullableArgs(),
+		makeAggOverload([]*types.T{types.Bool}, types.Float, newHypotheticalCumeDistAggregate,
+			"Calculates the cumulative distribution of a hypothetical row."),
+	)),
+
 	"variance": makeBuiltin(aggProps(),
 		makeAggOverload([]*types.T{types.Int}, types.Decimal, newIntVarianceAggregate,
 			"Calculates the variance of the selected values."),
@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -24,9 +25,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/ts/tspb"
 	"github.com/cockroachdb/cockroach/pkg/util/arith"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -274,6 +277,50 @@ var generators = map[string]builtinDefinition{
 				"SELECT * FROM crdb_internal.check_consistency(true, '\\x02', '\\x04')",
 		),
 	),
+
+	"crdb_internal.timeseries_query": makeBuiltin(
+		tree.FunctionProperties{
+			Impure:           true,
+			Class:            tree.GeneratorClass,
+			Category:         categorySystemInfo,
+			DistsqlBlacklist: true,
+		},
+		makeGeneratorOverload(
+			tree.ArgTypes{
+				{Name: "name", Typ: types.String},
+				{Name: "start", Typ: types.TimestampTZ},
+				{Name: "end", Typ: types.TimestampTZ},
+			},
+			timeSeriesQueryGeneratorType,
+			makeTimeSeriesQueryGenerator,
+			"Returns the datapoints of the given time series metric between start and end, "+
+				"averaged over 10s periods and summed across all its sources. Requires the "+
+				"admin role.\n\n"+
+				"Example usage:\n"+
+				"SELECT * FROM crdb_internal.timeseries_query('cr.node.sql.conns', now() - '1h'::INTERVAL, now())",
+		),
+		makeGeneratorOverload(
+			tree.ArgTypes{
+				{Name: "name", Typ: types.String},
+				{Name: "sources", Typ: types.StringArray},
+				{Name: "start", Typ: types.TimestampTZ},
+				{Name: "end", Typ: types.TimestampTZ},
+				{Name: "downsampler", Typ: types.String},
+				{Name: "aggregator", Typ: types.String},
+			},
+			timeSeriesQueryGeneratorType,
+			makeTimeSeriesQueryGenerator,
+			"Returns the datapoints of the given time series metric between start and end, "+
+				"downsampled to 10s periods with the given downsampler. The datapoints of each "+
+				"of the given sources (e.g. node IDs) are returned separately; if no sources are "+
+				"given, the datapoints of all the sources are combined with the given aggregator. "+
+				"The downsampler and the aggregator are one of avg, sum, max, min, first, last "+
+				"and variance. Requires the admin role.\n\n"+
+				"Example usage:\n"+
+				"SELECT * FROM crdb_internal.timeseries_query('cr.node.sql.conns', ARRAY['1', '2'], "+
+				"now() - '1h'::INTERVAL, now(), 'max', 'sum')",
+		),
+	),
 }
 
 func makeGeneratorOverload(
@@ -1127,3 +1174,136 @@ func (c *checkConsistencyGenerator) Values() tree.Datums {
 
 // Close is part of the tree.ValueGenerator interface.
 func (c *checkConsistencyGenerator) Close() {}
+
+// timeSeriesQueryGenerator supports the execution of
+// crdb_internal.timeseries_query().
+type timeSeriesQueryGenerator struct {
+	p   tree.PrivilegedAccessor
+	req tspb.TimeSeriesQueryRequest
+	// results is populated by Start(). Each Next() call moves to the next
+	// datapoint of the current result, or to the first datapoint of the next
+	// non-empty result.
+	results  []tspb.TimeSeriesQueryResponse_Result
+	curIdx   int
+	pointIdx int
+}
+
+var _ tree.ValueGenerator = &timeSeriesQueryGenerator{}
+
+var timeSeriesQueryGeneratorType = types.MakeLabeledTuple(
+	[]types.T{*types.String, *types.String, *types.TimestampTZ, *types.Float},
+	[]string{"name", "source", "timestamp", "value"},
+)
+
+func makeTimeSeriesQueryGenerator(
+	ctx *tree.EvalContext, args tree.Datums,
+) (tree.ValueGenerator, error) {
+	name := string(tree.MustBeDString(args[0]))
+	query := tspb.Query{Name: name}
+	var start, end tree.Datum
+	if len(args) == 3 {
+		start, end = args[1], args[2]
+	} else {
+		start, end = args[2], args[3]
+		downsampler, err := parseTimeSeriesAggregator("downsampler", args[4])
+		if err != nil {
+			return nil, err
+		}
+		aggregator, err := parseTimeSeriesAggregator("aggregator", args[5])
+		if err != nil {
+			return nil, err
+		}
+		query.Downsampler = &downsampler
+		query.SourceAggregator = &aggregator
+	}
+
+	req := tspb.TimeSeriesQueryRequest{
+		StartNanos: start.(*tree.DTimestampTZ).UnixNano(),
+		EndNanos:   end.(*tree.DTimestampTZ).UnixNano(),
+	}
+	if req.StartNanos >= req.EndNanos {
+		return nil, pgerror.New(pgcode.InvalidParameterValue, "start must be before end")
+	}
+	var sources []string
+	if len(args) == 6 {
+		for _, d := range tree.MustBeDArray(args[1]).Array {
+			if d == tree.DNull {
+				return nil, pgerror.New(pgcode.NullValueNotAllowed, "sources must not contain NULLs")
+			}
+			sources = append(sources, string(tree.MustBeDString(d)))
+		}
+	}
+	if len(sources) == 0 {
+		req.Queries = []tspb.Query{query}
+	} else {
+		// Each source is queried separately so that its datapoints are not
+		// aggregated with the ones of the other sources.
+		req.Queries = make([]tspb.Query, len(sources))
+		for i, source := range sources {
+			req.Queries[i] = query
+			req.Queries[i].Sources = []string{source}
+		}
+	}
+	return &timeSeriesQueryGenerator{p: ctx.PrivilegedAccessor, req: req}, nil
+}
+
+// parseTimeSeriesAggregator parses the name of a time series aggregator, which
+// is case-insensitive.
+func parseTimeSeriesAggregator(
+	argName string, d tree.Datum,
+) (tspb.TimeSeriesQueryAggregator, error) {
+	s := string(tree.MustBeDString(d))
+	v, ok := tspb.TimeSeriesQueryAggregator_value[strings.ToUpper(s)]
+	if !ok {
+		return 0, pgerror.Newf(pgcode.InvalidParameterValue, "unknown %s %q", argName, s)
+	}
+	return tspb.TimeSeriesQueryAggregator(v), nil
+}
+
+// ResolvedType is part of the tree.ValueGenerator interface.
+func (*timeSeriesQueryGenerator) ResolvedType() *types.T {
+	return timeSeriesQueryGeneratorType
+}
+
+// Start is part of the tree.ValueGenerator interface.
+func (g *timeSeriesQueryGenerator) Start(ctx context.Context, _ *kv.Txn) error {
+	resp, err := g.p.QueryTimeSeries(ctx, &g.req)
+	if err != nil {
+		return err
+	}
+	g.results = resp.Results
+	g.curIdx = 0
+	g.pointIdx = -1
+	return nil
+}
+
+// Next is part of the tree.ValueGenerator interface.
+func (g *timeSeriesQueryGenerator) Next(_ context.Context) (bool, error) {
+	g.pointIdx++
+	for g.curIdx < len(g.results) && g.pointIdx >= len(g.results[g.curIdx].Datapoints) {
+		g.curIdx++
+		g.pointIdx = 0
+	}
+	return g.curIdx < len(g.results), nil
+}
+
+// Values is part of the tree.ValueGenerator interface.
+func (g *timeSeriesQueryGenerator) Values() tree.Datums {
+	res := &g.results[g.curIdx]
+	dp := res.Datapoints[g.pointIdx]
+	// The results are in the order of the queries, each of which is either
+	// for a single source or aggregated over all of them.
+	source := tree.DNull
+	if sources := g.req.Queries[g.curIdx].Sources; len(sources) == 1 {
+		source = tree.NewDString(sources[0])
+	}
+	return tree.Datums{
+		tree.NewDString(res.Name),
+		source,
+		tree.MakeDTimestampTZ(timeutil.Unix(0, dp.TimestampNanos), time.Microsecond),
+		tree.NewDFloat(tree.DFloat(dp.Value)),
+	}
+}
+
+// Close is part of the tree.ValueGenerator interface.
+func (g *timeSeriesQueryGenerator) Close() {}
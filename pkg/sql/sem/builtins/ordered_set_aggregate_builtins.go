@@ -0,0 +1,465 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"context"
+	"math"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// Ordered-set aggregates (percentile_disc, percentile_cont and mode) and
+// hypothetical-set aggregates (rank, dense_rank, percent_rank and cume_dist
+// with a WITHIN GROUP clause) are rewritten by the optimizer to the internal
+// aggregates defined in this file. The ordered-set aggregates receive the
+// value of the WITHIN GROUP ordering column as their last argument, and are
+// fed the rows of each group in that order. The hypothetical-set aggregates
+// receive a boolean that indicates whether the row sorts before (or, for
+// cume_dist, is not after) the hypothetical row, and don't depend on the order
+// of the rows.
+
+var _ tree.AggregateFunc = &percentileAggregate{}
+var _ tree.AggregateFunc = &modeAggregate{}
+var _ tree.AggregateFunc = &hypotheticalRankAggregate{}
+var _ tree.AggregateFunc = &hypotheticalDenseRankAggregate{}
+var _ tree.AggregateFunc = &hypotheticalDistAggregate{}
+
+const sizeOfPercentileAggregate = int64(unsafe.Sizeof(percentileAggregate{}))
+const sizeOfModeAggregate = int64(unsafe.Sizeof(modeAggregate{}))
+const sizeOfHypotheticalRankAggregate = int64(unsafe.Sizeof(hypotheticalRankAggregate{}))
+const sizeOfHypotheticalDenseRankAggregate = int64(unsafe.Sizeof(hypotheticalDenseRankAggregate{}))
+const sizeOfHypotheticalDistAggregate = int64(unsafe.Sizeof(hypotheticalDistAggregate{}))
+
+// makeOrderedSetAggOverload returns the user-facing overload of an ordered-set
+// aggregate. Such an aggregate can only be used with a WITHIN GROUP clause, and
+// the optimizer always replaces it with the internal aggregate implementing
+// it, so the overload only serves for name resolution and documentation.
+func makeOrderedSetAggOverload(in []*types.T, ret *types.T, info string) tree.Overload {
+	return makeAggOverload(in, ret,
+		func([]*types.T, *tree.EvalContext, tree.Datums) tree.AggregateFunc {
+			panic(errors.AssertionFailedf("ordered-set aggregate was not replaced by its implementation"))
+		},
+		info,
+	)
+}
+
+// makePercentileImplOverloads returns the overloads of an internal percentile
+// aggregate for the given types of the WITHIN GROUP ordering column. Each type
+// gets an overload with a single fraction, which returns a value of that type,
+// and an overload with an array of fractions, which returns an array.
+func makePercentileImplOverloads(
+	typs []*types.T, f func([]*types.T, *tree.EvalContext, tree.Datums) tree.AggregateFunc,
+) []tree.Overload {
+	overloads := make([]tree.Overload, 0, 2*len(typs))
+	for _, fractionTyp := range []*types.T{types.Float, types.MakeArray(types.Float)} {
+		for _, t := range typs {
+			t, isArray := t, fractionTyp.Family() == types.ArrayFamily
+			overloads = append(overloads, makeAggOverloadWithReturnType(
+				[]*types.T{fractionTyp, t},
+				func(args []tree.TypedExpr) *types.T {
+					typ := t
+					if len(args) > 1 {
+						// Whenever possible, use the expression's type, so we can properly
+						// handle aliased types that don't explicitly have overloads.
+						typ = args[1].ResolvedType()
+					}
+					if isArray {
+						return types.MakeArray(typ)
+					}
+					return typ
+				},
+				f,
+				"Computes the requested percentile(s) of the selected values.",
+			))
+		}
+	}
+	return overloads
+}
+
+// percentileAggregate implements percentile_disc_impl and
+// percentile_cont_impl. It buffers all non-NULL values of a group, which
+// arrive sorted, and picks (or interpolates) the requested percentiles once
+// all of them have been seen.
+type percentileAggregate struct {
+	// continuous is true for percentile_cont_impl.
+	continuous bool
+	valueTyp   *types.T
+	// fraction is the fraction argument of the first row, or nil if no row has
+	// been added yet.
+	fraction tree.Datum
+	values   tree.Datums
+	acc      mon.BoundAccount
+}
+
+func newPercentileDiscAggregate(
+	params []*types.T, evalCtx *tree.EvalContext, _ tree.Datums,
+) tree.AggregateFunc {
+	return &percentileAggregate{
+		valueTyp: params[1],
+		acc:      evalCtx.Mon.MakeBoundAccount(),
+	}
+}
+
+func newPercentileContAggregate(
+	params []*types.T, evalCtx *tree.EvalContext, _ tree.Datums,
+) tree.AggregateFunc {
+	return &percentileAggregate{
+		continuous: true,
+		valueTyp:   params[1],
+		acc:        evalCtx.Mon.MakeBoundAccount(),
+	}
+}
+
+// Add records the fraction of the first row and buffers the non-NULL value.
+func (a *percentileAggregate) Add(
+	ctx context.Context, fraction tree.Datum, others ...tree.Datum,
+) error {
+	if a.fraction == nil {
+		a.fraction = fraction
+		if err := a.acc.Grow(ctx, int64(fraction.Size())); err != nil {
+			return err
+		}
+	}
+	value := others[0]
+	if value == tree.DNull {
+		return nil
+	}
+	if err := a.acc.Grow(ctx, int64(value.Size())); err != nil {
+		return err
+	}
+	a.values = append(a.values, value)
+	return nil
+}
+
+// Result returns the percentile (or the array of percentiles) of the values
+// passed to Add, or NULL if there were no such values.
+func (a *percentileAggregate) Result() (tree.Datum, error) {
+	if a.fraction == nil || a.fraction == tree.DNull || len(a.values) == 0 {
+		return tree.DNull, nil
+	}
+	fractions, ok := a.fraction.(*tree.DArray)
+	if !ok {
+		return a.percentile(float64(tree.MustBeDFloat(a.fraction)))
+	}
+	res := tree.NewDArray(a.valueTyp)
+	for _, f := range fractions.Array {
+		d := tree.DNull
+		if f != tree.DNull {
+			var err error
+			if d, err = a.percentile(float64(tree.MustBeDFloat(f))); err != nil {
+				return nil, err
+			}
+		}
+		if err := res.Append(d); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// percentile computes a single percentile of the (sorted) buffered values in
+// the same way as Postgres.
+func (a *percentileAggregate) percentile(fraction float64) (tree.Datum, error) {
+	if math.IsNaN(fraction) || fraction < 0 || fraction > 1 {
+		return nil, pgerror.Newf(pgcode.NumericValueOutOfRange,
+			"percentile value %g is not between 0 and 1", fraction)
+	}
+	n := len(a.values)
+	if !a.continuous {
+		idx := int(math.Ceil(fraction*float64(n))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return a.values[idx], nil
+	}
+
+	pos := fraction * float64(n-1)
+	lo, hi := int(math.Floor(pos)), int(math.Ceil(pos))
+	if lo == hi {
+		return a.values[lo], nil
+	}
+	proportion := pos - float64(lo)
+	switch first := a.values[lo].(type) {
+	case *tree.DFloat:
+		second := float64(tree.MustBeDFloat(a.values[hi]))
+		return tree.NewDFloat(tree.DFloat(float64(*first) + proportion*(second-float64(*first)))), nil
+	case *tree.DInterval:
+		second := a.values[hi].(*tree.DInterval).Duration
+		return &tree.DInterval{
+			Duration: first.Duration.Add(second.Sub(first.Duration).MulFloat(proportion)),
+		}, nil
+	default:
+		return nil, errors.AssertionFailedf("unexpected type %s for percentile_cont", first.ResolvedType())
+	}
+}
+
+// Reset implements tree.AggregateFunc interface.
+func (a *percentileAggregate) Reset(ctx context.Context) {
+	a.fraction = nil
+	a.values = nil
+	a.acc.Empty(ctx)
+}
+
+// Close allows the aggregate to release the memory it requested during
+// operation.
+func (a *percentileAggregate) Close(ctx context.Context) {
+	a.acc.Close(ctx)
+}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *percentileAggregate) Size() int64 {
+	return sizeOfPercentileAggregate
+}
+
+// modeAggregate implements mode_impl. Since the values of a group arrive
+// sorted, equal values are adjacent, and it suffices to find the longest run
+// of equal values. The first of several longest runs wins.
+type modeAggregate struct {
+	singleDatumAggregateBase
+
+	evalCtx *tree.EvalContext
+	// cur is the value of the current run of equal values, and curCount is its
+	// length.
+	cur      tree.Datum
+	curCount int
+	// best is the value of the longest run seen so far, and bestCount is its
+	// length.
+	best      tree.Datum
+	bestCount int
+}
+
+func newModeAggregate(_ []*types.T, evalCtx *tree.EvalContext, _ tree.Datums) tree.AggregateFunc {
+	return &modeAggregate{
+		singleDatumAggregateBase: makeSingleDatumAggregateBase(evalCtx),
+		evalCtx:                  evalCtx,
+	}
+}
+
+// Add extends the current run of equal values, or starts a new one.
+func (a *modeAggregate) Add(ctx context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	if a.cur != nil && a.cur.Compare(a.evalCtx, datum) == 0 {
+		a.curCount++
+	} else {
+		a.cur, a.curCount = datum, 1
+	}
+	if a.curCount > a.bestCount {
+		a.best, a.bestCount = a.cur, a.curCount
+	}
+	return a.updateMemoryUsage(ctx, int64(a.cur.Size())+int64(a.best.Size()))
+}
+
+// Result returns the most frequent value passed to Add, or NULL if there were
+// no non-NULL values.
+func (a *modeAggregate) Result() (tree.Datum, error) {
+	if a.best == nil {
+		return tree.DNull, nil
+	}
+	return a.best, nil
+}
+
+// Reset implements tree.AggregateFunc interface.
+func (a *modeAggregate) Reset(ctx context.Context) {
+	a.cur, a.curCount = nil, 0
+	a.best, a.bestCount = nil, 0
+	a.reset(ctx)
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *modeAggregate) Close(ctx context.Context) {
+	a.close(ctx)
+}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *modeAggregate) Size() int64 {
+	return sizeOfModeAggregate
+}
+
+// hypotheticalRankAggregate implements rank_impl: the rank of the
+// hypothetical row is one plus the number of rows that sort before it.
+type hypotheticalRankAggregate struct {
+	count int64
+}
+
+func newHypotheticalRankAggregate([]*types.T, *tree.EvalContext, tree.Datums) tree.AggregateFunc {
+	return &hypotheticalRankAggregate{}
+}
+
+// Add counts the row if it sorts before the hypothetical row.
+func (a *hypotheticalRankAggregate) Add(_ context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if isTrueDatum(datum) {
+		a.count++
+	}
+	return nil
+}
+
+// Result returns the rank of the hypothetical row.
+func (a *hypotheticalRankAggregate) Result() (tree.Datum, error) {
+	return tree.NewDInt(tree.DInt(a.count + 1)), nil
+}
+
+// Reset implements tree.AggregateFunc interface.
+func (a *hypotheticalRankAggregate) Reset(context.Context) {
+	a.count = 0
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *hypotheticalRankAggregate) Close(context.Context) {}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *hypotheticalRankAggregate) Size() int64 {
+	return sizeOfHypotheticalRankAggregate
+}
+
+// hypotheticalDenseRankAggregate implements dense_rank_impl: the dense rank
+// of the hypothetical row is one plus the number of distinct ordering keys of
+// the rows that sort before it. The keys are fingerprinted like the arguments
+// of a DISTINCT aggregate, so the rows can arrive in any order.
+type hypotheticalDenseRankAggregate struct {
+	singleDatumAggregateBase
+
+	keyTyp *types.T
+	alloc  sqlbase.DatumAlloc
+	// seen contains the fingerprints of the keys counted so far.
+	seen map[string]struct{}
+	size int64
+}
+
+func newHypotheticalDenseRankAggregate(
+	params []*types.T, evalCtx *tree.EvalContext, _ tree.Datums,
+) tree.AggregateFunc {
+	return &hypotheticalDenseRankAggregate{
+		singleDatumAggregateBase: makeSingleDatumAggregateBase(evalCtx),
+		keyTyp:                   params[1],
+		seen:                     make(map[string]struct{}),
+	}
+}
+
+// Add counts the key of the row if the row sorts before the hypothetical row
+// and the key has not been counted yet.
+func (a *hypotheticalDenseRankAggregate) Add(
+	ctx context.Context, precedes tree.Datum, others ...tree.Datum,
+) error {
+	if !isTrueDatum(precedes) {
+		return nil
+	}
+	ed := sqlbase.DatumToEncDatum(a.keyTyp, others[0])
+	fingerprint, err := ed.Fingerprint(a.keyTyp, &a.alloc, nil /* appendTo */)
+	if err != nil {
+		return err
+	}
+	if _, ok := a.seen[string(fingerprint)]; ok {
+		return nil
+	}
+	a.seen[string(fingerprint)] = struct{}{}
+	a.size += int64(len(fingerprint))
+	return a.updateMemoryUsage(ctx, a.size)
+}
+
+// Result returns the dense rank of the hypothetical row.
+func (a *hypotheticalDenseRankAggregate) Result() (tree.Datum, error) {
+	return tree.NewDInt(tree.DInt(len(a.seen) + 1)), nil
+}
+
+// Reset implements tree.AggregateFunc interface.
+func (a *hypotheticalDenseRankAggregate) Reset(ctx context.Context) {
+	a.seen = make(map[string]struct{})
+	a.size = 0
+	a.reset(ctx)
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *hypotheticalDenseRankAggregate) Close(ctx context.Context) {
+	a.close(ctx)
+}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *hypotheticalDenseRankAggregate) Size() int64 {
+	return sizeOfHypotheticalDenseRankAggregate
+}
+
+// hypotheticalDistAggregate implements percent_rank_impl and cume_dist_impl,
+// which compute the relative rank of the hypothetical row from the number of
+// rows that satisfy the argument and the total number of rows.
+type hypotheticalDistAggregate struct {
+	// cumeDist is true for cume_dist_impl.
+	cumeDist bool
+	count    int64
+	total    int64
+}
+
+func newHypotheticalPercentRankAggregate(
+	[]*types.T, *tree.EvalContext, tree.Datums,
+) tree.AggregateFunc {
+	return &hypotheticalDistAggregate{}
+}
+
+func newHypotheticalCumeDistAggregate(
+	[]*types.T, *tree.EvalContext, tree.Datums,
+) tree.AggregateFunc {
+	return &hypotheticalDistAggregate{cumeDist: true}
+}
+
+// Add counts the row, and whether it satisfies the argument.
+func (a *hypotheticalDistAggregate) Add(_ context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	if isTrueDatum(datum) {
+		a.count++
+	}
+	a.total++
+	return nil
+}
+
+// Result returns the relative rank of the hypothetical row. For
+// percent_rank, that is (rank - 1) / (total rows - 1), and for cume_dist it is
+// (rows preceding or peers of the hypothetical row) / (total rows), where the
+// hypothetical row is included in the total rows.
+func (a *hypotheticalDistAggregate) Result() (tree.Datum, error) {
+	if a.cumeDist {
+		return tree.NewDFloat(tree.DFloat(float64(a.count+1) / float64(a.total+1))), nil
+	}
+	if a.total == 0 {
+		return tree.NewDFloat(0), nil
+	}
+	return tree.NewDFloat(tree.DFloat(float64(a.count) / float64(a.total))), nil
+}
+
+// Reset implements tree.AggregateFunc interface.
+func (a *hypotheticalDistAggregate) Reset(context.Context) {
+	a.count = 0
+	a.total = 0
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *hypotheticalDistAggregate) Close(context.Context) {}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *hypotheticalDistAggregate) Size() int64 {
+	return sizeOfHypotheticalDistAggregate
+}
+
+// isTrueDatum returns whether the given datum is the boolean true.
+func isTrueDatum(d tree.Datum) bool {
+	b, ok := d.(*tree.DBool)
+	return ok && bool(*b)
+}
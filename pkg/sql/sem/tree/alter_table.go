@@ -543,3 +543,17 @@ func (node *AlterTableInjectStats) Format(ctx *FmtCtx) {
 	ctx.WriteString(" INJECT STATISTICS ")
 	ctx.FormatNode(node.Stats)
 }
+
+// RevertTable represents an ALTER TABLE ... REVERT TO SYSTEM TIME statement.
+type RevertTable struct {
+	Table     *UnresolvedObjectName
+	Timestamp Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *RevertTable) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER TABLE ")
+	ctx.FormatNode(node.Table)
+	ctx.WriteString(" REVERT TO SYSTEM TIME ")
+	ctx.FormatNode(node.Timestamp)
+}
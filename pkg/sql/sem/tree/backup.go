@@ -37,6 +37,14 @@ type Backup struct {
 	IncrementalFrom    Exprs
 	AsOf               AsOfClause
 	Options            KVOptions
+
+	// Nested is set for BACKUP INTO, in which case To is a collection that the
+	// backup is written into a subdirectory of.
+	Nested bool
+	// AppendToLatest is set for BACKUP INTO LATEST IN, in which case the backup
+	// is appended as an incremental layer to the latest full backup in the
+	// collection.
+	AppendToLatest bool
 }
 
 var _ Statement = &Backup{}
@@ -47,7 +55,14 @@ func (node *Backup) Format(ctx *FmtCtx) {
 	if node.DescriptorCoverage == RequestedDescriptors {
 		ctx.FormatNode(&node.Targets)
 	}
-	ctx.WriteString(" TO ")
+	if node.Nested {
+		ctx.WriteString(" INTO ")
+		if node.AppendToLatest {
+			ctx.WriteString("LATEST IN ")
+		}
+	} else {
+		ctx.WriteString(" TO ")
+	}
 	ctx.FormatNode(&node.To)
 	if node.AsOf.Expr != nil {
 		ctx.WriteString(" ")
@@ -70,6 +85,13 @@ type Restore struct {
 	From               []PartitionedBackup
 	AsOf               AsOfClause
 	Options            KVOptions
+
+	// Subdir, if set, names the backup within the collection in From to
+	// restore, either by its subdirectory or as LATEST.
+	Subdir Expr
+	// AsTable, if set, is the name to restore the single table in Targets
+	// under.
+	AsTable *UnresolvedObjectName
 }
 
 var _ Statement = &Restore{}
@@ -80,7 +102,15 @@ func (node *Restore) Format(ctx *FmtCtx) {
 	if node.DescriptorCoverage == RequestedDescriptors {
 		ctx.FormatNode(&node.Targets)
 	}
+	if node.AsTable != nil {
+		ctx.WriteString(" AS ")
+		ctx.FormatNode(node.AsTable)
+	}
 	ctx.WriteString(" FROM ")
+	if node.Subdir != nil {
+		ctx.FormatNode(node.Subdir)
+		ctx.WriteString(" IN ")
+	}
 	for i := range node.From {
 		if i > 0 {
 			ctx.WriteString(", ")
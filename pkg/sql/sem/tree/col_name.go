@@ -11,6 +11,8 @@
 package tree
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
@@ -67,6 +69,13 @@ func ComputeColNameInternal(sp sessiondata.SearchPath, target Expr) (int, string
 	case *FuncExpr:
 		fd, err := e.Func.Resolve(sp)
 		if err != nil {
+			// User-defined functions are not known at this point. Name the
+			// column after the function, like Postgres does; if the function
+			// does not exist, type checking will report it.
+			if n, ok := e.Func.FunctionReference.(*UnresolvedName); ok &&
+				pgerror.GetPGCode(err) == pgcode.UndefinedFunction {
+				return 2, n.Parts[0], nil
+			}
 			return 0, "", err
 		}
 		return 2, fd.Name, nil
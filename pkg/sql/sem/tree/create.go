@@ -234,6 +234,7 @@ func (*IndexTableDef) tableDef()                {}
 func (*FamilyTableDef) tableDef()               {}
 func (*ForeignKeyConstraintTableDef) tableDef() {}
 func (*CheckConstraintTableDef) tableDef()      {}
+func (*ExcludeConstraintTableDef) tableDef()    {}
 func (*LikeTableDef) tableDef()                 {}
 
 // TableDefs represents a list of table definitions.
@@ -277,6 +278,7 @@ type ColumnTableDef struct {
 	}
 	Unique               bool
 	UniqueConstraintName Name
+	UniqueDeferrability  ConstraintDeferrability
 	DefaultExpr          struct {
 		Expr           Expr
 		ConstraintName Name
@@ -288,10 +290,12 @@ type ColumnTableDef struct {
 		ConstraintName Name
 		Actions        ReferenceActions
 		Match          CompositeKeyMatchMethod
+		Deferrability  ConstraintDeferrability
 	}
 	Computed struct {
 		Computed bool
 		Expr     Expr
+		Virtual  bool
 	}
 	Family struct {
 		Name        Name
@@ -381,6 +385,7 @@ func NewColumnTableDef(
 		case UniqueConstraint:
 			d.Unique = true
 			d.UniqueConstraintName = c.Name
+			d.UniqueDeferrability = t.Deferrability
 		case *ColumnCheckConstraint:
 			d.CheckExprs = append(d.CheckExprs, ColumnTableDefCheckExpr{
 				Expr:           t.Expr,
@@ -396,9 +401,11 @@ func NewColumnTableDef(
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
 			d.References.Match = t.Match
+			d.References.Deferrability = t.Deferrability
 		case *ColumnComputedDef:
 			d.Computed.Computed = true
 			d.Computed.Expr = t.Expr
+			d.Computed.Virtual = t.Virtual
 		case *ColumnFamilyConstraint:
 			if d.HasColumnFamily() {
 				return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
@@ -429,6 +436,11 @@ func (node *ColumnTableDef) IsComputed() bool {
 	return node.Computed.Computed
 }
 
+// IsVirtual returns if the ColumnTableDef is a virtual computed column.
+func (node *ColumnTableDef) IsVirtual() bool {
+	return node.Computed.Virtual
+}
+
 // HasColumnFamily returns if the ColumnTableDef has a column family.
 func (node *ColumnTableDef) HasColumnFamily() bool {
 	return node.Family.Name != "" || node.Family.Create
@@ -468,6 +480,7 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			}
 		} else if node.Unique {
 			ctx.WriteString(" UNIQUE")
+			ctx.FormatNode(node.UniqueDeferrability)
 		}
 	}
 	if node.HasDefaultExpr() {
@@ -504,11 +517,16 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.WriteString(node.References.Match.String())
 		}
 		ctx.FormatNode(&node.References.Actions)
+		ctx.FormatNode(node.References.Deferrability)
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
 		ctx.FormatNode(node.Computed.Expr)
-		ctx.WriteString(") STORED")
+		if node.Computed.Virtual {
+			ctx.WriteString(") VIRTUAL")
+		} else {
+			ctx.WriteString(") STORED")
+		}
 	}
 	if node.HasColumnFamily() {
 		if node.Family.Create {
@@ -590,7 +608,9 @@ type ShardedPrimaryKeyConstraint struct {
 }
 
 // UniqueConstraint represents UNIQUE on a column.
-type UniqueConstraint struct{}
+type UniqueConstraint struct {
+	Deferrability ConstraintDeferrability
+}
 
 // ColumnCheckConstraint represents either a check on a column.
 type ColumnCheckConstraint struct {
@@ -599,15 +619,17 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table   TableName
-	Col     Name // empty-string means use PK
-	Actions ReferenceActions
-	Match   CompositeKeyMatchMethod
+	Table         TableName
+	Col           Name // empty-string means use PK
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // ColumnComputedDef represents the description of a computed column.
 type ColumnComputedDef struct {
-	Expr Expr
+	Expr    Expr
+	Virtual bool
 }
 
 // ColumnFamilyConstraint represents FAMILY on a column.
@@ -673,12 +695,14 @@ type ConstraintTableDef interface {
 func (*UniqueConstraintTableDef) constraintTableDef()     {}
 func (*ForeignKeyConstraintTableDef) constraintTableDef() {}
 func (*CheckConstraintTableDef) constraintTableDef()      {}
+func (*ExcludeConstraintTableDef) constraintTableDef()    {}
 
 // UniqueConstraintTableDef represents a unique constraint within a CREATE
 // TABLE statement.
 type UniqueConstraintTableDef struct {
 	IndexTableDef
-	PrimaryKey bool
+	PrimaryKey    bool
+	Deferrability ConstraintDeferrability
 }
 
 // SetName implements the TableDef interface.
@@ -715,6 +739,7 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionBy != nil {
 		ctx.FormatNode(node.PartitionBy)
 	}
+	ctx.FormatNode(node.Deferrability)
 }
 
 // ReferenceAction is the method used to maintain referential integrity through
@@ -784,14 +809,46 @@ func (c CompositeKeyMatchMethod) String() string {
 	return compositeKeyMatchMethodName[c]
 }
 
+// ConstraintDeferrability describes whether the checks of a constraint can be
+// deferred until the end of the transaction, and whether they are by default.
+// See https://www.postgresql.org/docs/12/sql-set-constraints.html.
+type ConstraintDeferrability int
+
+// The values for ConstraintDeferrability.
+const (
+	NotDeferrable ConstraintDeferrability = iota
+	DeferrableInitiallyImmediate
+	DeferrableInitiallyDeferred
+)
+
+var constraintDeferrabilityName = [...]string{
+	NotDeferrable:                "NOT DEFERRABLE",
+	DeferrableInitiallyImmediate: "DEFERRABLE",
+	DeferrableInitiallyDeferred:  "DEFERRABLE INITIALLY DEFERRED",
+}
+
+func (d ConstraintDeferrability) String() string {
+	return constraintDeferrabilityName[d]
+}
+
+// Format implements the NodeFormatter interface. NOT DEFERRABLE is the
+// default, so nothing is printed for it.
+func (d ConstraintDeferrability) Format(ctx *FmtCtx) {
+	if d != NotDeferrable {
+		ctx.WriteByte(' ')
+		ctx.WriteString(d.String())
+	}
+}
+
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name     Name
-	Table    TableName
-	FromCols NameList
-	ToCols   NameList
-	Actions  ReferenceActions
-	Match    CompositeKeyMatchMethod
+	Name          Name
+	Table         TableName
+	FromCols      NameList
+	ToCols        NameList
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -819,6 +876,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(node.Deferrability)
 }
 
 // SetName implements the ConstraintTableDef interface.
@@ -851,6 +909,77 @@ func (node *CheckConstraintTableDef) Format(ctx *FmtCtx) {
 	ctx.WriteByte(')')
 }
 
+// ExcludeElem is a single element of an EXCLUDE constraint: an expression
+// and the operator used to compare it between two rows.
+type ExcludeElem struct {
+	Expr Expr
+	// Operator is either EQ or Overlaps.
+	Operator ComparisonOperator
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExcludeElem) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.Expr)
+	ctx.WriteString(" WITH ")
+	ctx.WriteString(node.Operator.String())
+}
+
+// ExcludeElemList is a list of EXCLUDE constraint elements.
+type ExcludeElemList []ExcludeElem
+
+// Format implements the NodeFormatter interface.
+func (l *ExcludeElemList) Format(ctx *FmtCtx) {
+	for i := range *l {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*l)[i])
+	}
+}
+
+// ExcludeConstraintTableDef represents an EXCLUDE constraint within a CREATE
+// TABLE statement. The constraint is violated by any two rows for which all
+// of the elements compare true using their respective operators.
+type ExcludeConstraintTableDef struct {
+	Name Name
+	// Using is the access method named in the constraint, if any. It is only
+	// retained for compatibility; the constraint is always backed by a
+	// regular index.
+	Using Name
+	Elems ExcludeElemList
+	// Where, if non-nil, restricts the constraint to the rows that satisfy
+	// the predicate.
+	Where Expr
+}
+
+// SetName implements the ConstraintTableDef interface.
+func (node *ExcludeConstraintTableDef) SetName(name Name) {
+	node.Name = name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExcludeConstraintTableDef) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteString("EXCLUDE ")
+	if node.Using != "" {
+		ctx.WriteString("USING ")
+		ctx.FormatNode(&node.Using)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Elems)
+	ctx.WriteByte(')')
+	if node.Where != nil {
+		ctx.WriteString(" WHERE (")
+		ctx.FormatNode(node.Where)
+		ctx.WriteByte(')')
+	}
+}
+
 // FamilyTableDef represents a family definition within a CREATE TABLE
 // statement.
 type FamilyTableDef struct {
@@ -1029,8 +1158,23 @@ const (
 	CreateTableOnCommitUnset CreateTableOnCommitSetting = iota
 	// CreateTableOnCommitPreserveRows indicates that ON COMMIT PRESERVE ROWS was set.
 	CreateTableOnCommitPreserveRows
+	// CreateTableOnCommitDeleteRows indicates that ON COMMIT DELETE ROWS was set.
+	CreateTableOnCommitDeleteRows
+	// CreateTableOnCommitDrop indicates that ON COMMIT DROP was set.
+	CreateTableOnCommitDrop
 )
 
+var createTableOnCommitName = [...]string{
+	CreateTableOnCommitUnset:        "",
+	CreateTableOnCommitPreserveRows: "PRESERVE ROWS",
+	CreateTableOnCommitDeleteRows:   "DELETE ROWS",
+	CreateTableOnCommitDrop:         "DROP",
+}
+
+func (s CreateTableOnCommitSetting) String() string {
+	return createTableOnCommitName[s]
+}
+
 // CreateTable represents a CREATE TABLE statement.
 type CreateTable struct {
 	IfNotExists   bool
@@ -1106,6 +1250,10 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 		// No storage parameters are implemented, so we never list the storage
 		// parameters in the output format.
 	}
+	if node.OnCommit != CreateTableOnCommitUnset {
+		ctx.WriteString(" ON COMMIT ")
+		ctx.WriteString(node.OnCommit.String())
+	}
 }
 
 // HoistConstraints finds column check and foreign key constraints defined
@@ -1154,12 +1302,13 @@ func (node *CreateTable) HoistConstraints() {
 					targetCol = append(targetCol, col.References.Col)
 				}
 				node.Defs = append(node.Defs, &ForeignKeyConstraintTableDef{
-					Table:    *col.References.Table,
-					FromCols: NameList{col.Name},
-					ToCols:   targetCol,
-					Name:     col.References.ConstraintName,
-					Actions:  col.References.Actions,
-					Match:    col.References.Match,
+					Table:         *col.References.Table,
+					FromCols:      NameList{col.Name},
+					ToCols:        targetCol,
+					Name:          col.References.ConstraintName,
+					Actions:       col.References.Actions,
+					Match:         col.References.Match,
+					Deferrability: col.References.Deferrability,
 				})
 				col.References.Table = nil
 			}
@@ -1498,6 +1647,9 @@ type CreateView struct {
 	IfNotExists bool
 	Temporary   bool
 	Replace     bool
+	// Materialized is set for CREATE MATERIALIZED VIEW, which stores the
+	// result of AsSource instead of evaluating it on every read.
+	Materialized bool
 }
 
 // Format implements the NodeFormatter interface.
@@ -1512,6 +1664,10 @@ func (node *CreateView) Format(ctx *FmtCtx) {
 		ctx.WriteString("TEMPORARY ")
 	}
 
+	if node.Materialized {
+		ctx.WriteString("MATERIALIZED ")
+	}
+
 	ctx.WriteString("VIEW ")
 
 	if node.IfNotExists {
@@ -1530,6 +1686,253 @@ func (node *CreateView) Format(ctx *FmtCtx) {
 	ctx.FormatNode(node.AsSource)
 }
 
+// RefreshMaterializedView represents a REFRESH MATERIALIZED VIEW statement.
+type RefreshMaterializedView struct {
+	Name         *UnresolvedObjectName
+	Concurrently bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *RefreshMaterializedView) Format(ctx *FmtCtx) {
+	ctx.WriteString("REFRESH MATERIALIZED VIEW ")
+	if node.Concurrently {
+		ctx.WriteString("CONCURRENTLY ")
+	}
+	ctx.FormatNode(node.Name)
+}
+
+// TriggerActionTime specifies whether a trigger fires before or after the
+// row it fires for is written.
+type TriggerActionTime int
+
+// TriggerActionTime values.
+const (
+	TriggerBefore TriggerActionTime = iota
+	TriggerAfter
+)
+
+var triggerActionTimeName = [...]string{
+	TriggerBefore: "BEFORE",
+	TriggerAfter:  "AFTER",
+}
+
+func (t TriggerActionTime) String() string {
+	return triggerActionTimeName[t]
+}
+
+// TriggerEvent is a kind of row modification that can fire a trigger.
+type TriggerEvent int
+
+// TriggerEvent values.
+const (
+	TriggerInsert TriggerEvent = iota
+	TriggerUpdate
+	TriggerDelete
+)
+
+var triggerEventName = [...]string{
+	TriggerInsert: "INSERT",
+	TriggerUpdate: "UPDATE",
+	TriggerDelete: "DELETE",
+}
+
+func (t TriggerEvent) String() string {
+	return triggerEventName[t]
+}
+
+// TriggerEvents is a list of trigger events.
+type TriggerEvents []TriggerEvent
+
+// Format implements the NodeFormatter interface.
+func (node *TriggerEvents) Format(ctx *FmtCtx) {
+	for i, e := range *node {
+		if i > 0 {
+			ctx.WriteString(" OR ")
+		}
+		ctx.WriteString(e.String())
+	}
+}
+
+// CreateTrigger represents a CREATE TRIGGER statement.
+type CreateTrigger struct {
+	Name       Name
+	Table      TableName
+	ActionTime TriggerActionTime
+	Events     TriggerEvents
+	// When is the condition under which the trigger fires, or nil.
+	When Expr
+	// Exactly one of Set, the assignments to the new row made by a BEFORE
+	// trigger, and Stmt, the statement run by an AFTER trigger, is set.
+	Set  UpdateExprs
+	Stmt Statement
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TRIGGER ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.ActionTime.String())
+	ctx.WriteByte(' ')
+	ctx.FormatNode(&node.Events)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	ctx.WriteString(" FOR EACH ROW")
+	if node.When != nil {
+		ctx.WriteString(" WHEN (")
+		ctx.FormatNode(node.When)
+		ctx.WriteByte(')')
+	}
+	if node.Stmt != nil {
+		ctx.WriteString(" EXECUTE ")
+		ctx.FormatNode(node.Stmt)
+	} else {
+		ctx.WriteString(" SET ")
+		ctx.FormatNode(&node.Set)
+	}
+}
+
+// FunctionVolatility describes how the result of a user-defined function
+// depends on its environment.
+type FunctionVolatility int
+
+// FunctionVolatility values.
+const (
+	FunctionVolatile FunctionVolatility = iota
+	FunctionStable
+	FunctionImmutable
+)
+
+var functionVolatilityName = [...]string{
+	FunctionVolatile:  "VOLATILE",
+	FunctionStable:    "STABLE",
+	FunctionImmutable: "IMMUTABLE",
+}
+
+func (v FunctionVolatility) String() string {
+	return functionVolatilityName[v]
+}
+
+// FuncArg is an argument in the definition of a user-defined function.
+type FuncArg struct {
+	// Name is the name of the argument, which may be empty.
+	Name Name
+	Type *types.T
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArg) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteString(node.Type.SQLString())
+}
+
+// FuncArgs is a list of function arguments.
+type FuncArgs []FuncArg
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArgs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// FunctionOptions contains the options of a CREATE FUNCTION statement.
+type FunctionOptions struct {
+	// Language is the language the body is written in. It is empty if it was
+	// not specified.
+	Language string
+	// Volatility is only meaningful if HasVolatility is set. Functions are
+	// VOLATILE by default.
+	Volatility    FunctionVolatility
+	HasVolatility bool
+	// Body is the definition of the function.
+	Body    string
+	HasBody bool
+}
+
+// Format implements the NodeFormatter interface.
+func (o *FunctionOptions) Format(ctx *FmtCtx) {
+	sep := ""
+	if o.Language != "" {
+		ctx.WriteString("LANGUAGE ")
+		ctx.FormatNameP(&o.Language)
+		sep = " "
+	}
+	if o.HasVolatility {
+		ctx.WriteString(sep)
+		ctx.WriteString(o.Volatility.String())
+		sep = " "
+	}
+	if o.HasBody {
+		ctx.WriteString(sep)
+		ctx.WriteString("AS ")
+		lex.EncodeSQLStringWithFlags(&ctx.Buffer, o.Body, ctx.flags.EncodeFlags())
+	}
+}
+
+// CombineWith combines two options, erroring out if the two options contain
+// incompatible settings.
+func (o *FunctionOptions) CombineWith(other *FunctionOptions) error {
+	if other.Language != "" {
+		if o.Language != "" {
+			return errors.New("LANGUAGE specified multiple times")
+		}
+		o.Language = other.Language
+	}
+	if other.HasVolatility {
+		if o.HasVolatility {
+			return errors.New("conflicting or redundant volatility options")
+		}
+		o.Volatility = other.Volatility
+		o.HasVolatility = true
+	}
+	if other.HasBody {
+		if o.HasBody {
+			return errors.New("AS specified multiple times")
+		}
+		o.Body = other.Body
+		o.HasBody = true
+	}
+	return nil
+}
+
+// CreateFunction represents a CREATE FUNCTION statement.
+type CreateFunction struct {
+	Name    *UnresolvedObjectName
+	Replace bool
+	Args    FuncArgs
+	// ReturnsSet is set if the function returns a set of rows of type
+	// ReturnType rather than a single value.
+	ReturnType *types.T
+	ReturnsSet bool
+	Options    FunctionOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("FUNCTION ")
+	ctx.FormatNode(node.Name)
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Args)
+	ctx.WriteString(") RETURNS ")
+	if node.ReturnsSet {
+		ctx.WriteString("SETOF ")
+	}
+	ctx.WriteString(node.ReturnType.SQLString())
+	ctx.WriteByte(' ')
+	ctx.FormatNode(&node.Options)
+}
+
 // CreateStats represents a CREATE STATISTICS statement.
 type CreateStats struct {
 	Name        Name
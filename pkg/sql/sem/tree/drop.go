@@ -19,6 +19,8 @@
 
 package tree
 
+import "github.com/cockroachdb/cockroach/pkg/sql/types"
+
 // DropBehavior represents options for dropping schema elements.
 type DropBehavior int
 
@@ -105,14 +107,19 @@ func (node *DropTable) Format(ctx *FmtCtx) {
 
 // DropView represents a DROP VIEW statement.
 type DropView struct {
-	Names        TableNames
-	IfExists     bool
-	DropBehavior DropBehavior
+	Names          TableNames
+	IfExists       bool
+	DropBehavior   DropBehavior
+	IsMaterialized bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *DropView) Format(ctx *FmtCtx) {
-	ctx.WriteString("DROP VIEW ")
+	ctx.WriteString("DROP ")
+	if node.IsMaterialized {
+		ctx.WriteString("MATERIALIZED ")
+	}
+	ctx.WriteString("VIEW ")
 	if node.IfExists {
 		ctx.WriteString("IF EXISTS ")
 	}
@@ -143,6 +150,77 @@ func (node *DropSequence) Format(ctx *FmtCtx) {
 	}
 }
 
+// DropTrigger represents a DROP TRIGGER statement.
+type DropTrigger struct {
+	Name     Name
+	Table    TableName
+	IfExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP TRIGGER ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+}
+
+// FuncObj identifies a user-defined function in DROP FUNCTION and GRANT
+// statements.
+type FuncObj struct {
+	Name *UnresolvedObjectName
+	// Args are the types of the arguments of the function. If ArgsSpecified
+	// is not set, the name alone must identify the function.
+	Args          []*types.T
+	ArgsSpecified bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObj) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.Name)
+	if node.ArgsSpecified {
+		ctx.WriteByte('(')
+		for i, t := range node.Args {
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.WriteString(t.SQLString())
+		}
+		ctx.WriteByte(')')
+	}
+}
+
+// FuncObjs is a list of functions.
+type FuncObjs []FuncObj
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObjs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// DropFunction represents a DROP FUNCTION statement.
+type DropFunction struct {
+	Functions FuncObjs
+	IfExists  bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP FUNCTION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Functions)
+}
+
 // DropRole represents a DROP ROLE statement
 type DropRole struct {
 	Names    Exprs
@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/ts/tspb"
 	"github.com/cockroachdb/cockroach/pkg/util/arith"
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
@@ -2258,6 +2259,17 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 				return MakeDBool(DBool(ipAddr.ContainsOrContainedBy(&other))), nil
 			},
 		},
+		&CmpOp{
+			LeftType:  types.Geometry,
+			RightType: types.Geometry,
+			Fn: func(_ *EvalContext, left, right Datum) (Datum, error) {
+				intersects, err := left.(*DGeometry).BoundingBoxIntersects(right.(*DGeometry).Geometry)
+				if err != nil {
+					return nil, err
+				}
+				return MakeDBool(DBool(intersects)), nil
+			},
+		},
 	},
 })
 
@@ -2705,6 +2717,13 @@ type PrivilegedAccessor interface {
 	// Returns the config byte array, a bool representing whether the namespace exists,
 	// and an error if there is one.
 	LookupZoneConfigByNamespaceID(ctx context.Context, id int64) (DBytes, bool, error)
+
+	// QueryTimeSeries queries the time series data stored by the cluster. It
+	// is meant as a replacement for the ts server's Query endpoint and
+	// requires the admin role.
+	QueryTimeSeries(
+		ctx context.Context, req *tspb.TimeSeriesQueryRequest,
+	) (*tspb.TimeSeriesQueryResponse, error)
 }
 
 // SequenceOperators is used for various sql related functions that can
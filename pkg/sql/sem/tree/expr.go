@@ -1227,7 +1227,10 @@ type FuncExpr struct {
 
 	// OrderBy is used for aggregations that specify an order:
 	// array_agg(col1 ORDER BY col2)
+	// or for ordered-set aggregations (see AggType):
+	// percentile_disc(0.5) WITHIN GROUP (ORDER BY col)
 	OrderBy OrderBy
+	AggType AggType
 	typeAnnotation
 	fnProps *FunctionProperties
 	fn      *Overload
@@ -1308,6 +1311,20 @@ var funcTypeName = [...]string{
 	AllFuncType:      "ALL",
 }
 
+// AggType specifies the type of aggregation.
+type AggType int
+
+// FuncExpr.AggType
+const (
+	// GeneralAgg is used for general-purpose aggregate functions.
+	// array_agg(col1 ORDER BY col2)
+	GeneralAgg AggType = iota
+	// OrderedSetAgg is used for ordered-set and hypothetical-set aggregate
+	// functions, whose ordering is specified in a WITHIN GROUP clause.
+	// percentile_disc(0.5) WITHIN GROUP (ORDER BY col)
+	OrderedSetAgg
+)
+
 // Format implements the NodeFormatter interface.
 func (node *FuncExpr) Format(ctx *FmtCtx) {
 	var typ string
@@ -1324,11 +1341,16 @@ func (node *FuncExpr) Format(ctx *FmtCtx) {
 	ctx.WriteByte('(')
 	ctx.WriteString(typ)
 	ctx.FormatNode(&node.Exprs)
-	if len(node.OrderBy) > 0 {
+	if len(node.OrderBy) > 0 && node.AggType != OrderedSetAgg {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(&node.OrderBy)
 	}
 	ctx.WriteByte(')')
+	if len(node.OrderBy) > 0 && node.AggType == OrderedSetAgg {
+		ctx.WriteString(" WITHIN GROUP (")
+		ctx.FormatNode(&node.OrderBy)
+		ctx.WriteByte(')')
+	}
 	if ctx.HasFlags(FmtParsable) && node.typ != nil {
 		if node.fnProps.AmbiguousReturnType {
 			// There's no type annotation available for tuples.
@@ -134,6 +134,41 @@ func NewFunctionDefinition(
 	}
 }
 
+// UserDefinedFunction describes an overload of a user-defined function.
+type UserDefinedFunction struct {
+	// ID is the ID of the descriptor of the function, and ParentID the ID of
+	// the database it belongs to.
+	ID       uint32
+	ParentID uint32
+
+	Volatility FunctionVolatility
+
+	// Body is the statement run by the function. It refers to the arguments
+	// of the function as placeholders.
+	Body string
+
+	// InlineExpr, if set, is a scalar expression equivalent to the body of the
+	// function, which calls of the function can be replaced with. Like Body,
+	// it refers to the arguments as placeholders.
+	InlineExpr Expr
+}
+
+// NewUserDefinedFunctionDefinition allocates a function definition for the
+// overloads of a user-defined function.
+func NewUserDefinedFunctionDefinition(
+	name string, props *FunctionProperties, def []Overload,
+) *FunctionDefinition {
+	overloads := make([]overloadImpl, len(def))
+	for i := range def {
+		overloads[i] = &def[i]
+	}
+	return &FunctionDefinition{
+		Name:               name,
+		Definition:         overloads,
+		FunctionProperties: *props,
+	}
+}
+
 // FunDefs holds pre-allocated FunctionDefinition instances
 // for every builtin function. Initialized by builtins.init().
 var FunDefs map[string]*FunctionDefinition
@@ -72,6 +72,21 @@ func WrapFunction(n string) ResolvableFunctionReference {
 	return ResolvableFunctionReference{fd}
 }
 
+// WrapResolvedFunction creates a new ResolvableFunctionReference holding
+// the function with the given name and properties, which has been resolved to
+// the given overload. Unlike WrapFunction, it supports user-defined functions,
+// which are not known to FunDefs.
+func WrapResolvedFunction(
+	name string, props *FunctionProperties, overload *Overload,
+) ResolvableFunctionReference {
+	if overload.UDF == nil {
+		return WrapFunction(name)
+	}
+	return ResolvableFunctionReference{
+		NewUserDefinedFunctionDefinition(name, props, []Overload{*overload}),
+	}
+}
+
 // FunctionReference is the common interface to UnresolvedName and QualifiedFunctionName.
 type FunctionReference interface {
 	fmt.Stringer
@@ -33,6 +33,7 @@ type Grant struct {
 type TargetList struct {
 	Databases NameList
 	Tables    TablePatterns
+	Functions FuncObjs
 
 	// ForRoles and Roles are used internally in the parser and not used
 	// in the AST. Therefore they do not participate in pretty-printing,
@@ -46,6 +47,9 @@ func (tl *TargetList) Format(ctx *FmtCtx) {
 	if tl.Databases != nil {
 		ctx.WriteString("DATABASE ")
 		ctx.FormatNode(&tl.Databases)
+	} else if tl.Functions != nil {
+		ctx.WriteString("FUNCTION ")
+		ctx.FormatNode(&tl.Functions)
 	} else {
 		ctx.WriteString("TABLE ")
 		ctx.FormatNode(&tl.Tables)
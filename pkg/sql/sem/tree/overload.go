@@ -74,6 +74,9 @@ type Overload struct {
 	// SpecializedVecBuiltin is used to let the vectorized engine
 	// know when an Overload has a specialized vectorized operator.
 	SpecializedVecBuiltin SpecializedVectorizedBuiltin
+
+	// UDF is set if this is an overload of a user-defined function.
+	UDF *UserDefinedFunction
 }
 
 // params implements the overloadImpl interface.
@@ -691,13 +691,19 @@ func (node *FuncExpr) doc(p *PrettyCfg) pretty.Doc {
 			)
 		}
 
-		if len(node.OrderBy) > 0 {
+		if len(node.OrderBy) > 0 && node.AggType != OrderedSetAgg {
 			args = pretty.ConcatSpace(args, node.OrderBy.doc(p))
 		}
 		d = pretty.Concat(d, p.bracket("(", args, ")"))
 	} else {
 		d = pretty.Concat(d, pretty.Text("()"))
 	}
+	if len(node.OrderBy) > 0 && node.AggType == OrderedSetAgg {
+		d = pretty.Fold(pretty.ConcatSpace,
+			d,
+			pretty.Keyword("WITHIN GROUP"),
+			p.bracket("(", node.OrderBy.doc(p), ")"))
+	}
 	if node.Filter != nil {
 		d = pretty.Fold(pretty.ConcatSpace,
 			d,
@@ -1110,10 +1116,15 @@ func (node *Update) doc(p *PrettyCfg) pretty.Doc {
 }
 
 func (node *Delete) doc(p *PrettyCfg) pretty.Doc {
-	items := make([]pretty.TableRow, 6)
+	items := make([]pretty.TableRow, 7)
 	items = append(items,
 		node.With.docRow(p),
-		p.row("DELETE FROM", p.Doc(node.Table)),
+		p.row("DELETE FROM", p.Doc(node.Table)))
+	if len(node.Using) > 0 {
+		items = append(items,
+			p.row("USING", p.Doc(&node.Using)))
+	}
+	items = append(items,
 		node.Where.docRow(p),
 		node.OrderBy.docRow(p))
 	items = append(items, node.Limit.docTable(p)...)
@@ -1225,7 +1236,7 @@ func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 func (node *CreateView) doc(p *PrettyCfg) pretty.Doc {
 	// Final layout:
 	//
-	// CREATE [TEMP] VIEW name ( ... ) AS
+	// CREATE [TEMP | MATERIALIZED] VIEW name ( ... ) AS
 	//     SELECT ...
 	//
 	title := pretty.Keyword("CREATE")
@@ -1235,6 +1246,9 @@ func (node *CreateView) doc(p *PrettyCfg) pretty.Doc {
 	if node.Temporary {
 		title = pretty.ConcatSpace(title, pretty.Keyword("TEMPORARY"))
 	}
+	if node.Materialized {
+		title = pretty.ConcatSpace(title, pretty.Keyword("MATERIALIZED"))
+	}
 	title = pretty.ConcatSpace(title, pretty.Keyword("VIEW"))
 	if node.IfNotExists {
 		title = pretty.ConcatSpace(title, pretty.Keyword("IF NOT EXISTS"))
@@ -1629,6 +1643,7 @@ func (node *UniqueConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    [STORING ( ... )]
 	//    [INTERLEAVE ...]
 	//    [PARTITION BY ...]
+	//    [DEFERRABLE ...]
 	//
 	// or (no constraint name):
 	//
@@ -1636,8 +1651,9 @@ func (node *UniqueConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    [STORING ( ... )]
 	//    [INTERLEAVE ...]
 	//    [PARTITION BY ...]
+	//    [DEFERRABLE ...]
 	//
-	clauses := make([]pretty.Doc, 0, 5)
+	clauses := make([]pretty.Doc, 0, 6)
 	var title pretty.Doc
 	if node.PrimaryKey {
 		title = pretty.Keyword("PRIMARY KEY")
@@ -1664,6 +1680,9 @@ func (node *UniqueConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	if node.PartitionBy != nil {
 		clauses = append(clauses, p.Doc(node.PartitionBy))
 	}
+	if node.Deferrability != NotDeferrable {
+		clauses = append(clauses, pretty.Keyword(node.Deferrability.String()))
+	}
 
 	if len(clauses) == 0 {
 		return title
@@ -1678,6 +1697,7 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    REFERENCES tbl (...)
 	//    [MATCH ...]
 	//    [ACTIONS ...]
+	//    [DEFERRABLE ...]
 	//
 	// or (no constraint name):
 	//
@@ -1685,6 +1705,7 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    REFERENCES tbl [(...)]
 	//    [MATCH ...]
 	//    [ACTIONS ...]
+	//    [DEFERRABLE ...]
 	//
 	clauses := make([]pretty.Doc, 0, 4)
 	title := pretty.ConcatSpace(
@@ -1711,6 +1732,11 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 		clauses = append(clauses, actions)
 	}
 
+	// We omit NOT DEFERRABLE because it is the default.
+	if node.Deferrability != NotDeferrable {
+		clauses = append(clauses, pretty.Keyword(node.Deferrability.String()))
+	}
+
 	return p.nestUnder(title, pretty.Group(pretty.Stack(clauses...)))
 }
 
@@ -1754,8 +1780,12 @@ func (node *ColumnTableDef) docRow(p *PrettyCfg) pretty.TableRow {
 
 	// Compute expression (for computed columns).
 	if node.IsComputed() {
+		suffix := ") STORED"
+		if node.IsVirtual() {
+			suffix = ") VIRTUAL"
+		}
 		clauses = append(clauses, pretty.ConcatSpace(pretty.Keyword("AS"),
-			p.bracket("(", p.Doc(node.Computed.Expr), ") STORED"),
+			p.bracket("(", p.Doc(node.Computed.Expr), suffix),
 		))
 	}
 
@@ -1799,6 +1829,9 @@ func (node *ColumnTableDef) docRow(p *PrettyCfg) pretty.TableRow {
 		pkConstraint = pretty.Keyword("PRIMARY KEY")
 	} else if node.Unique {
 		pkConstraint = pretty.Keyword("UNIQUE")
+		if node.UniqueDeferrability != NotDeferrable {
+			pkConstraint = pretty.ConcatSpace(pkConstraint, pretty.Keyword(node.UniqueDeferrability.String()))
+		}
 	}
 	if pkConstraint != pretty.Nil {
 		clauses = append(clauses, p.maybePrependConstraintName(&node.UniqueConstraintName, pkConstraint))
@@ -1828,6 +1861,9 @@ func (node *ColumnTableDef) docRow(p *PrettyCfg) pretty.TableRow {
 		if ref := p.Doc(&node.References.Actions); ref != pretty.Nil {
 			fkDetails = append(fkDetails, ref)
 		}
+		if node.References.Deferrability != NotDeferrable {
+			fkDetails = append(fkDetails, pretty.Keyword(node.References.Deferrability.String()))
+		}
 		fk := fkHead
 		if len(fkDetails) > 0 {
 			fk = p.nestUnder(fk, pretty.Group(pretty.Stack(fkDetails...)))
@@ -1904,7 +1940,13 @@ func (node *Backup) doc(p *PrettyCfg) pretty.Doc {
 
 	items = append(items, p.row("BACKUP", pretty.Nil))
 	items = append(items, node.Targets.docRow(p))
-	items = append(items, p.row("TO", p.Doc(&node.To)))
+	if node.AppendToLatest {
+		items = append(items, p.row("INTO LATEST IN", p.Doc(&node.To)))
+	} else if node.Nested {
+		items = append(items, p.row("INTO", p.Doc(&node.To)))
+	} else {
+		items = append(items, p.row("TO", p.Doc(&node.To)))
+	}
 
 	if node.AsOf.Expr != nil {
 		items = append(items, node.AsOf.docRow(p))
@@ -1923,11 +1965,19 @@ func (node *Restore) doc(p *PrettyCfg) pretty.Doc {
 
 	items = append(items, p.row("RESTORE", pretty.Nil))
 	items = append(items, node.Targets.docRow(p))
+	if node.AsTable != nil {
+		items = append(items, p.row("AS", p.Doc(node.AsTable)))
+	}
 	from := make([]pretty.Doc, len(node.From))
 	for i := range node.From {
 		from[i] = p.Doc(&node.From[i])
 	}
-	items = append(items, p.row("FROM", p.commaSeparated(from...)))
+	if node.Subdir != nil {
+		items = append(items, p.row("FROM", p.Doc(node.Subdir)))
+		items = append(items, p.row("IN", p.commaSeparated(from...)))
+	} else {
+		items = append(items, p.row("FROM", p.commaSeparated(from...)))
+	}
 
 	if node.AsOf.Expr != nil {
 		items = append(items, node.AsOf.docRow(p))
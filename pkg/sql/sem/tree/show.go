@@ -79,6 +79,8 @@ const (
 	BackupRangeDetails
 	// BackupFileDetails identifies a SHOW BACKUP FILES statement.
 	BackupFileDetails
+	// BackupCheckDetails identifies a SHOW BACKUP CHECK statement.
+	BackupCheckDetails
 )
 
 // ShowBackup represents a SHOW BACKUP statement.
@@ -87,20 +89,35 @@ type ShowBackup struct {
 	Details              BackupDetails
 	ShouldIncludeSchemas bool
 	Options              KVOptions
+
+	// InCollection, if set, is the collection that Path is a subdirectory of.
+	// If Path is nil, the statement is SHOW BACKUPS IN InCollection.
+	InCollection Expr
 }
 
 // Format implements the NodeFormatter interface.
 func (node *ShowBackup) Format(ctx *FmtCtx) {
+	if node.Path == nil {
+		ctx.WriteString("SHOW BACKUPS IN ")
+		ctx.FormatNode(node.InCollection)
+		return
+	}
 	ctx.WriteString("SHOW BACKUP ")
 	if node.Details == BackupRangeDetails {
 		ctx.WriteString("RANGES ")
 	} else if node.Details == BackupFileDetails {
 		ctx.WriteString("FILES ")
+	} else if node.Details == BackupCheckDetails {
+		ctx.WriteString("CHECK ")
 	}
 	if node.ShouldIncludeSchemas {
 		ctx.WriteString("SCHEMAS ")
 	}
 	ctx.FormatNode(node.Path)
+	if node.InCollection != nil {
+		ctx.WriteString(" IN ")
+		ctx.FormatNode(node.InCollection)
+	}
 	if len(node.Options) > 0 {
 		ctx.WriteString(" WITH ")
 		ctx.FormatNode(&node.Options)
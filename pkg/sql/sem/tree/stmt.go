@@ -99,7 +99,7 @@ func CanModifySchema(stmt Statement) bool {
 func CanWriteData(stmt Statement) bool {
 	switch stmt.(type) {
 	// Normal write operations.
-	case *Insert, *Delete, *Update, *Truncate:
+	case *Insert, *Delete, *Update, *Merge, *Truncate, *RevertTable, *RefreshMaterializedView:
 		return true
 	// Import operations.
 	case *CopyFrom, *Import, *Restore:
@@ -354,7 +354,12 @@ func (*CreateRole) hiddenFromShowQueries() {}
 func (*CreateView) StatementType() StatementType { return DDL }
 
 // StatementTag returns a short string identifying the type of statement.
-func (*CreateView) StatementTag() string { return "CREATE VIEW" }
+func (n *CreateView) StatementTag() string {
+	if n.Materialized {
+		return "CREATE MATERIALIZED VIEW"
+	}
+	return "CREATE VIEW"
+}
 
 // StatementType implements the Statement interface.
 func (*CreateSequence) StatementType() StatementType { return DDL }
@@ -362,6 +367,18 @@ func (*CreateSequence) StatementType() StatementType { return DDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*CreateSequence) StatementTag() string { return "CREATE SEQUENCE" }
 
+// StatementType implements the Statement interface.
+func (*CreateFunction) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateFunction) StatementTag() string { return "CREATE FUNCTION" }
+
+// StatementType implements the Statement interface.
+func (*CreateTrigger) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateTrigger) StatementTag() string { return "CREATE TRIGGER" }
+
 // StatementType implements the Statement interface.
 func (*CreateStats) StatementType() StatementType { return DDL }
 
@@ -414,7 +431,12 @@ func (*DropTable) StatementTag() string { return "DROP TABLE" }
 func (*DropView) StatementType() StatementType { return DDL }
 
 // StatementTag returns a short string identifying the type of statement.
-func (*DropView) StatementTag() string { return "DROP VIEW" }
+func (n *DropView) StatementTag() string {
+	if n.IsMaterialized {
+		return "DROP MATERIALIZED VIEW"
+	}
+	return "DROP VIEW"
+}
 
 // StatementType implements the Statement interface.
 func (*DropSequence) StatementType() StatementType { return DDL }
@@ -422,6 +444,18 @@ func (*DropSequence) StatementType() StatementType { return DDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*DropSequence) StatementTag() string { return "DROP SEQUENCE" }
 
+// StatementType implements the Statement interface.
+func (*DropFunction) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropFunction) StatementTag() string { return "DROP FUNCTION" }
+
+// StatementType implements the Statement interface.
+func (*DropTrigger) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropTrigger) StatementTag() string { return "DROP TRIGGER" }
+
 // StatementType implements the Statement interface.
 func (*DropRole) StatementType() StatementType { return Ack }
 
@@ -486,6 +520,12 @@ func (*Import) StatementTag() string { return "IMPORT" }
 
 func (*Import) cclOnlyStatement() {}
 
+// StatementType implements the Statement interface.
+func (*Merge) StatementType() StatementType { return RowsAffected }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*Merge) StatementTag() string { return "MERGE" }
+
 // StatementType implements the Statement interface.
 func (*ParenSelect) StatementType() StatementType { return Rows }
 
@@ -498,6 +538,12 @@ func (*Prepare) StatementType() StatementType { return Ack }
 // StatementTag returns a short string identifying the type of statement.
 func (*Prepare) StatementTag() string { return "PREPARE" }
 
+// StatementType implements the Statement interface.
+func (*RefreshMaterializedView) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*RefreshMaterializedView) StatementTag() string { return "REFRESH MATERIALIZED VIEW" }
+
 // StatementType implements the Statement interface.
 func (*ReleaseSavepoint) StatementType() StatementType { return Ack }
 
@@ -556,6 +602,14 @@ func (*Restore) cclOnlyStatement() {}
 
 func (*Restore) hiddenFromShowQueries() {}
 
+// StatementType implements the Statement interface.
+func (*RevertTable) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*RevertTable) StatementTag() string { return "REVERT TABLE" }
+
+func (*RevertTable) modifiesSchema() bool { return true }
+
 // StatementType implements the Statement interface.
 func (*Revoke) StatementType() StatementType { return DDL }
 
@@ -624,6 +678,12 @@ func (*SetClusterSetting) StatementType() StatementType { return Ack }
 // StatementTag returns a short string identifying the type of statement.
 func (*SetClusterSetting) StatementTag() string { return "SET CLUSTER SETTING" }
 
+// StatementType implements the Statement interface.
+func (*SetConstraints) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*SetConstraints) StatementTag() string { return "SET CONSTRAINTS" }
+
 // StatementType implements the Statement interface.
 func (*SetTransaction) StatementType() StatementType { return Ack }
 
@@ -918,20 +978,24 @@ func (n *CommitTransaction) String() string              { return AsString(n) }
 func (n *CopyFrom) String() string                       { return AsString(n) }
 func (n *CreateChangefeed) String() string               { return AsString(n) }
 func (n *CreateDatabase) String() string                 { return AsString(n) }
+func (n *CreateFunction) String() string                 { return AsString(n) }
 func (n *CreateIndex) String() string                    { return AsString(n) }
 func (n *CreateRole) String() string                     { return AsString(n) }
 func (n *CreateTable) String() string                    { return AsString(n) }
 func (n *CreateSchema) String() string                   { return AsString(n) }
 func (n *CreateSequence) String() string                 { return AsString(n) }
 func (n *CreateStats) String() string                    { return AsString(n) }
+func (n *CreateTrigger) String() string                  { return AsString(n) }
 func (n *CreateView) String() string                     { return AsString(n) }
 func (n *Deallocate) String() string                     { return AsString(n) }
 func (n *Delete) String() string                         { return AsString(n) }
 func (n *DropDatabase) String() string                   { return AsString(n) }
+func (n *DropFunction) String() string                   { return AsString(n) }
 func (n *DropIndex) String() string                      { return AsString(n) }
 func (n *DropTable) String() string                      { return AsString(n) }
 func (n *DropView) String() string                       { return AsString(n) }
 func (n *DropSequence) String() string                   { return AsString(n) }
+func (n *DropTrigger) String() string                    { return AsString(n) }
 func (n *DropRole) String() string                       { return AsString(n) }
 func (n *Execute) String() string                        { return AsString(n) }
 func (n *Explain) String() string                        { return AsString(n) }
@@ -941,8 +1005,10 @@ func (n *Grant) String() string                          { return AsString(n) }
 func (n *GrantRole) String() string                      { return AsString(n) }
 func (n *Insert) String() string                         { return AsString(n) }
 func (n *Import) String() string                         { return AsString(n) }
+func (n *Merge) String() string                          { return AsString(n) }
 func (n *ParenSelect) String() string                    { return AsString(n) }
 func (n *Prepare) String() string                        { return AsString(n) }
+func (n *RefreshMaterializedView) String() string        { return AsString(n) }
 func (n *ReleaseSavepoint) String() string               { return AsString(n) }
 func (n *Relocate) String() string                       { return AsString(n) }
 func (n *RenameColumn) String() string                   { return AsString(n) }
@@ -950,6 +1016,7 @@ func (n *RenameDatabase) String() string                 { return AsString(n) }
 func (n *RenameIndex) String() string                    { return AsString(n) }
 func (n *RenameTable) String() string                    { return AsString(n) }
 func (n *Restore) String() string                        { return AsString(n) }
+func (n *RevertTable) String() string                    { return AsString(n) }
 func (n *Revoke) String() string                         { return AsString(n) }
 func (n *RevokeRole) String() string                     { return AsString(n) }
 func (n *RollbackToSavepoint) String() string            { return AsString(n) }
@@ -960,6 +1027,7 @@ func (n *Scrub) String() string                          { return AsString(n) }
 func (n *Select) String() string                         { return AsString(n) }
 func (n *SelectClause) String() string                   { return AsString(n) }
 func (n *SetClusterSetting) String() string              { return AsString(n) }
+func (n *SetConstraints) String() string                 { return AsString(n) }
 func (n *SetZoneConfig) String() string                  { return AsString(n) }
 func (n *SetSessionAuthorizationDefault) String() string { return AsString(n) }
 func (n *SetSessionCharacteristics) String() string      { return AsString(n) }
@@ -58,9 +58,61 @@ type SemaContext struct {
 	// globally for the entire txn and this field would not be needed.
 	AsOfTimestamp *hlc.Timestamp
 
+	// FunctionResolver is used to resolve the names of user-defined
+	// functions. If it is nil, only builtin functions can be used.
+	FunctionResolver FunctionResolver
+
 	Properties SemaProperties
 }
 
+// FunctionResolver resolves the names of user-defined functions.
+type FunctionResolver interface {
+	// ResolveUserDefinedFunction returns the definition of the user-defined
+	// function with the given name, or nil if there is no such function.
+	ResolveUserDefinedFunction(
+		name *UnresolvedName, searchPath sessiondata.SearchPath,
+	) (*FunctionDefinition, error)
+
+	// CheckUserDefinedFunctionPrivilege returns an error if the current user
+	// is not allowed to call the given user-defined function.
+	CheckUserDefinedFunctionPrivilege(fn *UserDefinedFunction) error
+}
+
+// ResolveFunction resolves a function reference to its definition. Builtin
+// functions are tried first; if there is no builtin function with the given
+// name, the FunctionResolver, if any, is used. Unlike
+// ResolvableFunctionReference.Resolve, the definitions of user-defined
+// functions are not memoized in fn, as they can change between executions of
+// a prepared statement.
+func (sc *SemaContext) ResolveFunction(
+	fn *ResolvableFunctionReference,
+) (*FunctionDefinition, error) {
+	var searchPath sessiondata.SearchPath
+	if sc != nil {
+		searchPath = sc.SearchPath
+	}
+	def, err := fn.Resolve(searchPath)
+	if err == nil || sc == nil || sc.FunctionResolver == nil {
+		return def, err
+	}
+	name, ok := fn.FunctionReference.(*UnresolvedName)
+	if !ok || pgerror.GetPGCode(err) != pgcode.UndefinedFunction {
+		return nil, err
+	}
+	udf, udfErr := sc.FunctionResolver.ResolveUserDefinedFunction(name, searchPath)
+	if udfErr != nil {
+		return nil, udfErr
+	}
+	if udf == nil {
+		return nil, err
+	}
+	if sc.Properties.required.rejectFlags&RejectUserDefinedFunctions != 0 {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"user-defined functions are not allowed in %s", sc.Properties.required.context)
+	}
+	return udf, nil
+}
+
 // SemaProperties is a holder for required and derived properties
 // during semantic analysis. It provides scoping semantics via its
 // Restore() method, see below.
@@ -140,6 +192,11 @@ const (
 	// RejectSubqueries rejects subqueries in scalar contexts.
 	RejectSubqueries
 
+	// RejectUserDefinedFunctions rejects calls of user-defined functions. It
+	// is used for expressions stored in descriptors, which are type checked
+	// again without access to user-defined functions.
+	RejectUserDefinedFunctions
+
 	// RejectSpecial is used in common places like the LIMIT clause.
 	RejectSpecial = RejectAggregates | RejectGenerators | RejectWindowApplications
 )
@@ -735,7 +792,9 @@ func (sc *SemaContext) checkFunctionUsage(expr *FuncExpr, def *FunctionDefinitio
 		}
 		return unimplemented.NewWithIssueDetail(def.UnsupportedWithIssue, def.Name, msg)
 	}
-	if def.Private {
+	// The internal implementations of ordered-set aggregates are private, but
+	// they are reachable through the rewrite of WITHIN GROUP aggregations.
+	if def.Private && expr.AggType != OrderedSetAgg {
 		return pgerror.Wrapf(errPrivateFunction, pgcode.ReservedName,
 			"%s()", errors.Safe(def.Name))
 	}
@@ -807,11 +866,7 @@ func CheckIsWindowOrAgg(def *FunctionDefinition) error {
 
 // TypeCheck implements the Expr interface.
 func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, error) {
-	var searchPath sessiondata.SearchPath
-	if ctx != nil {
-		searchPath = ctx.SearchPath
-	}
-	def, err := expr.Func.Resolve(searchPath)
+	def, err := ctx.ResolveFunction(&expr.Func)
 	if err != nil {
 		return nil, err
 	}
@@ -916,6 +971,12 @@ func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr,
 	}
 	overloadImpl := fns[0].(*Overload)
 
+	if overloadImpl.UDF != nil && ctx != nil && ctx.FunctionResolver != nil {
+		if err := ctx.FunctionResolver.CheckUserDefinedFunctionPrivilege(overloadImpl.UDF); err != nil {
+			return nil, err
+		}
+	}
+
 	if expr.IsWindowFunctionApplication() {
 		// Make sure the window function application is of either a built-in window
 		// function or of a builtin aggregate function.
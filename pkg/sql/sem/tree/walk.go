@@ -871,6 +871,60 @@ func (stmt *Insert) walkStmt(v Visitor) Statement {
 	return ret
 }
 
+// copyNode makes a copy of this Statement without recursing in any child Statements.
+func (stmt *Merge) copyNode() *Merge {
+	stmtCopy := *stmt
+	stmtCopy.Whens = make(MergeWhens, len(stmt.Whens))
+	for i, w := range stmt.Whens {
+		wCopy := *w
+		wCopy.Exprs = make(UpdateExprs, len(w.Exprs))
+		for j, e := range w.Exprs {
+			eCopy := *e
+			wCopy.Exprs[j] = &eCopy
+		}
+		stmtCopy.Whens[i] = &wCopy
+	}
+	return &stmtCopy
+}
+
+// walkStmt is part of the walkableStmt interface.
+func (stmt *Merge) walkStmt(v Visitor) Statement {
+	ret := stmt
+	e, changed := WalkExpr(v, stmt.On)
+	if changed {
+		ret = stmt.copyNode()
+		ret.On = e
+	}
+	for i, w := range stmt.Whens {
+		if w.Cond != nil {
+			e, changed := WalkExpr(v, w.Cond)
+			if changed {
+				if ret == stmt {
+					ret = stmt.copyNode()
+				}
+				ret.Whens[i].Cond = e
+			}
+		}
+		for j, expr := range w.Exprs {
+			e, changed := WalkExpr(v, expr.Expr)
+			if changed {
+				if ret == stmt {
+					ret = stmt.copyNode()
+				}
+				ret.Whens[i].Exprs[j].Expr = e
+			}
+		}
+		values, changed := walkExprSlice(v, w.Values)
+		if changed {
+			if ret == stmt {
+				ret = stmt.copyNode()
+			}
+			ret.Whens[i].Values = values
+		}
+	}
+	return ret
+}
+
 // copyNode makes a copy of this Statement without recursing in any child Statements.
 func (stmt *CreateTable) copyNode() *CreateTable {
 	stmtCopy := *stmt
@@ -1008,6 +1062,15 @@ func (stmt *Restore) walkStmt(v Visitor) Statement {
 			ret.AsOf.Expr = e
 		}
 	}
+	if stmt.Subdir != nil {
+		e, changed := WalkExpr(v, stmt.Subdir)
+		if changed {
+			if ret == stmt {
+				ret = stmt.copyNode()
+			}
+			ret.Subdir = e
+		}
+	}
 	for i, backup := range stmt.From {
 		for j, expr := range backup {
 			e, changed := WalkExpr(v, expr)
@@ -1368,6 +1431,7 @@ var _ walkableStmt = &Delete{}
 var _ walkableStmt = &Explain{}
 var _ walkableStmt = &Insert{}
 var _ walkableStmt = &Import{}
+var _ walkableStmt = &Merge{}
 var _ walkableStmt = &ParenSelect{}
 var _ walkableStmt = &Restore{}
 var _ walkableStmt = &Select{}
@@ -1435,6 +1499,17 @@ func SimpleVisit(expr Expr, preFn SimpleVisitFn) (Expr, error) {
 	return newExpr, nil
 }
 
+// SimpleStmtVisit is like SimpleVisit, but visits the expressions of a
+// statement. See walkStmt for the parts of a statement that are not visited.
+func SimpleStmtVisit(stmt Statement, preFn SimpleVisitFn) (Statement, error) {
+	v := simpleVisitor{fn: preFn}
+	newStmt, _ := walkStmt(&v, stmt)
+	if v.err != nil {
+		return nil, v.err
+	}
+	return newStmt, nil
+}
+
 type debugVisitor struct {
 	buf   bytes.Buffer
 	level int
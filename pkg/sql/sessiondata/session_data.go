@@ -76,6 +76,13 @@ type SessionData struct {
 	// identifier `cockroach_restart` in order use a restartable
 	// transaction.
 	ForceSavepointRestart bool
+	// ProtectReadTimestamp, if set, makes AS OF SYSTEM TIME statements protect
+	// their read timestamp from garbage collection while they run, so that
+	// long-running historical reads can outlive the GC TTL.
+	ProtectReadTimestamp bool
+	// TraceParent, if set, is the W3C Trace Context traceparent of the span of
+	// the client that the traces of the transactions of the session continue.
+	TraceParent string
 	// DefaultIntSize specifies the size in bits or bytes (preferred)
 	// of how a "naked" INT type should be parsed.
 	DefaultIntSize int
@@ -0,0 +1,113 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type setConstraintsNode struct {
+	n *tree.SetConstraints
+	// names are the names of the constraints listed in the statement (empty for
+	// SET CONSTRAINTS ALL).
+	names []string
+}
+
+// SetConstraints implements the SET CONSTRAINTS statement.
+// See https://www.postgresql.org/docs/current/sql-set-constraints.html.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	node := &setConstraintsNode{n: n}
+	if n.All {
+		return node, nil
+	}
+
+	// The constraints must be foreign key or unique constraints of the tables of
+	// the current database.
+	deferrable := make(map[string]bool)
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	if err := forEachTableDesc(ctx, p, dbDesc, hideVirtual,
+		func(_ *sqlbase.DatabaseDescriptor, _ string, table *sqlbase.TableDescriptor) error {
+			for i := range table.OutboundFKs {
+				fk := &table.OutboundFKs[i]
+				deferrable[fk.Name] = deferrable[fk.Name] || fk.Deferrable
+			}
+			for _, idx := range table.AllNonDropIndexes() {
+				if idx.Unique || idx.UniqueDeferrable {
+					deferrable[idx.Name] = deferrable[idx.Name] || idx.UniqueDeferrable
+				}
+			}
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+	for _, name := range n.Names {
+		isDeferrable, ok := deferrable[string(name)]
+		if !ok {
+			return nil, pgerror.Newf(pgcode.UndefinedObject,
+				"constraint %q does not exist", string(name))
+		}
+		if !isDeferrable {
+			return nil, pgerror.Newf(pgcode.WrongObjectType,
+				"constraint %q is not deferrable", string(name))
+		}
+		node.names = append(node.names, string(name))
+	}
+	return node, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because SET CONSTRAINTS ... IMMEDIATE checks the rows written by the
+// previous statements of the transaction.
+func (n *setConstraintsNode) ReadingOwnWrites() {}
+
+func (n *setConstraintsNode) startExec(params runParams) error {
+	dc := params.extendedEvalCtx.DeferredConstraints
+	if params.extendedEvalCtx.TxnImplicit || dc == nil {
+		params.p.SendClientNotice(
+			params.ctx,
+			pgerror.Noticef("SET CONSTRAINTS can only be used in transaction blocks"),
+		)
+		return nil
+	}
+
+	mode := constraintModeImmediate
+	if n.n.Deferred {
+		mode = constraintModeDeferred
+	}
+	dc.setMode(n.names, mode)
+	if mode == constraintModeDeferred {
+		return nil
+	}
+
+	// The constraints that become immediate are checked right away for the keys
+	// saved by the checks deferred so far in the transaction.
+	// The checks run on a copy of the session's internal executor that sees the
+	// descriptors modified by the transaction, so that the executor shared by
+	// the session is left untouched.
+	ie := *params.extendedEvalCtx.InternalExecutor.(*InternalExecutor)
+	ie.tcModifier = params.p.Tables()
+	return dc.check(params.ctx, &ie, params.p.txn, func(check *deferrableCheck) bool {
+		return !dc.isDeferred(check.name, check.deferrability)
+	})
+}
+
+func (*setConstraintsNode) Next(runParams) (bool, error) { return false, nil }
+func (*setConstraintsNode) Values() tree.Datums          { return nil }
+func (*setConstraintsNode) Close(context.Context)        {}
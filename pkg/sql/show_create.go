@@ -124,7 +124,11 @@ func ShowCreateTable(
 		if idx.ID != desc.PrimaryIndex.ID && includeInterleaveClause {
 			// Showing the primary index is handled above.
 			f.WriteString(",\n\t")
-			f.WriteString(idx.SQLString(&sqlbase.AnonymousTable))
+			if idx.UniqueDeferrable {
+				showDeferrableUniqueConstraint(idx, f)
+			} else {
+				f.WriteString(idx.SQLString(&sqlbase.AnonymousTable))
+			}
 			// Showing the INTERLEAVE and PARTITION BY for the primary index are
 			// handled last.
 
@@ -140,6 +144,7 @@ func ShowCreateTable(
 			); err != nil {
 				return "", err
 			}
+			f.FormatNode(idx.UniqueDeferrability())
 		}
 	}
 
@@ -156,6 +161,10 @@ func ShowCreateTable(
 		return "", err
 	}
 
+	if err := showTriggers(desc, f); err != nil {
+		return "", err
+	}
+
 	if err := showComments(desc, selectComment(ctx, p, desc.ID), &f.Buffer); err != nil {
 		return "", err
 	}
@@ -163,6 +172,22 @@ func ShowCreateTable(
 	return f.CloseAndGetString(), nil
 }
 
+// showDeferrableUniqueConstraint writes the DEFERRABLE UNIQUE constraint
+// backed by the index, up to its STORING clause. The index is not Unique, so
+// it can't be shown as a UNIQUE INDEX.
+func showDeferrableUniqueConstraint(idx *sqlbase.IndexDescriptor, f *tree.FmtCtx) {
+	f.WriteString("CONSTRAINT ")
+	f.FormatNameP(&idx.Name)
+	f.WriteString(" UNIQUE (")
+	idx.ColNamesFormat(f)
+	f.WriteByte(')')
+	if len(idx.StoreColumnNames) > 0 {
+		f.WriteString(" STORING (")
+		formatQuoteNames(&f.Buffer, idx.StoreColumnNames...)
+		f.WriteByte(')')
+	}
+}
+
 // formatQuoteNames quotes and adds commas between names.
 func formatQuoteNames(buf *bytes.Buffer, names ...string) {
 	f := tree.NewFmtCtx(tree.FmtSimple)
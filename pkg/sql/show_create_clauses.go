@@ -17,6 +17,7 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -73,7 +74,8 @@ func selectComment(ctx context.Context, p PlanHookState, tableID sqlbase.ID) (tc
 
 // ShowCreateView returns a valid SQL representation of the CREATE VIEW
 // statement used to create the given view. It is used in the implementation of
-// the crdb_internal.create_statements virtual table.
+// the crdb_internal.create_statements virtual table. For materialized views,
+// the CREATE INDEX statements for the view's secondary indexes follow.
 func ShowCreateView(
 	ctx context.Context, tn *tree.Name, desc *sqlbase.TableDescriptor,
 ) (string, error) {
@@ -82,17 +84,30 @@ func ShowCreateView(
 	if desc.Temporary {
 		f.WriteString("TEMP ")
 	}
+	if desc.MaterializedView() {
+		f.WriteString("MATERIALIZED ")
+	}
 	f.WriteString("VIEW ")
 	f.FormatNode(tn)
 	f.WriteString(" (")
-	for i := range desc.Columns {
+	// Materialized views have a hidden primary key column, which is not part
+	// of the view's definition.
+	cols := desc.VisibleColumns()
+	for i := range cols {
 		if i > 0 {
 			f.WriteString(", ")
 		}
-		f.FormatNameP(&desc.Columns[i].Name)
+		f.FormatNameP(&cols[i].Name)
 	}
 	f.WriteString(") AS ")
 	f.WriteString(desc.ViewQuery)
+	if desc.MaterializedView() {
+		tableName := tree.MakeUnqualifiedTableName(*tn)
+		for i := range desc.Indexes {
+			f.WriteString(";\nCREATE ")
+			f.WriteString(desc.Indexes[i].SQLString(&tableName))
+		}
+	}
 	return f.CloseAndGetString(), nil
 }
 
@@ -131,6 +146,52 @@ func showComments(table *sqlbase.TableDescriptor, tc *tableComments, buf *bytes.
 	return nil
 }
 
+// showTriggers appends a CREATE TRIGGER statement for each trigger of the
+// table to the buffer.
+func showTriggers(table *sqlbase.TableDescriptor, f *tree.FmtCtx) error {
+	for i := range table.Triggers {
+		t := &table.Triggers[i]
+		n := &tree.CreateTrigger{
+			Name:  tree.Name(t.Name),
+			Table: tree.MakeUnqualifiedTableName(tree.Name(table.Name)),
+		}
+		if t.ActionTime == sqlbase.TriggerDescriptor_AFTER {
+			n.ActionTime = tree.TriggerAfter
+		}
+		for _, e := range t.Events {
+			n.Events = append(n.Events, triggerEventFromDesc[e])
+		}
+		if t.WhenExpr != "" {
+			when, err := parser.ParseExpr(t.WhenExpr)
+			if err != nil {
+				return err
+			}
+			n.When = when
+		}
+		for _, a := range t.Assignments {
+			col, err := table.FindColumnByID(a.ColumnID)
+			if err != nil {
+				return err
+			}
+			expr, err := parser.ParseExpr(a.Expr)
+			if err != nil {
+				return err
+			}
+			n.Set = append(n.Set, &tree.UpdateExpr{Names: tree.NameList{tree.Name(col.Name)}, Expr: expr})
+		}
+		if t.Statement != "" {
+			stmt, err := parser.ParseOne(t.Statement)
+			if err != nil {
+				return err
+			}
+			n.Stmt = stmt.AST
+		}
+		f.WriteString(";\n")
+		f.FormatNode(n)
+	}
+	return nil
+}
+
 // showForeignKeyConstraint returns a valid SQL representation of a FOREIGN KEY
 // clause for a given index.
 func showForeignKeyConstraint(
@@ -190,6 +251,11 @@ func showForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	// We omit NOT DEFERRABLE because it is the default.
+	if d := fk.Deferrability(); d != tree.NotDeferrable {
+		buf.WriteByte(' ')
+		buf.WriteString(d.String())
+	}
 	return nil
 }
 
@@ -390,5 +456,24 @@ func showConstraintClause(desc *sqlbase.TableDescriptor, f *tree.FmtCtx) {
 		f.WriteString(e.Expr)
 		f.WriteString(")")
 	}
+	for _, e := range desc.AllActiveAndInactiveExclusions() {
+		f.WriteString(",\n\tCONSTRAINT ")
+		formatQuoteNames(&f.Buffer, e.Name)
+		f.WriteString(" EXCLUDE (")
+		for i := range e.Elements {
+			if i > 0 {
+				f.WriteString(", ")
+			}
+			f.WriteString(e.Elements[i].Expr)
+			f.WriteString(" WITH ")
+			f.WriteString(e.Elements[i].Operator.ComparisonOperator().String())
+		}
+		f.WriteString(")")
+		if e.Predicate != "" {
+			f.WriteString(" WHERE (")
+			f.WriteString(e.Predicate)
+			f.WriteString(")")
+		}
+	}
 	f.WriteString("\n)")
 }
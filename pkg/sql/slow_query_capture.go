@@ -0,0 +1,397 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// This file contains the capture of slow statements. Tracing every statement
+// to be able to report the slow ones would be too expensive, so the capture
+// happens in two steps: an (untraced) execution whose service latency exceeds
+// the capture threshold of its fingerprint arms the fingerprint, and the next
+// execution of the fingerprint on the node is traced, as if the statement
+// diagnostics had been requested for it. If that execution is slow too, its
+// plan, the execution statistics of its operators and a compact rendering of
+// its trace are reported to the SQL_PERF log channel and to
+// system.slow_query_log, and its diagnostics bundle is inserted into
+// system.statement_diagnostics.
+
+var slowQueryCaptureEnabled = settings.RegisterPublicBoolSetting(
+	"sql.log.slow_query.capture.enabled",
+	"set to true to capture the plan and trace of statements whose service latency "+
+		"exceeds sql.log.slow_query.latency_threshold (and the typical latency of "+
+		"their fingerprint) to the SQL_PERF log channel and system.slow_query_log",
+	false,
+)
+
+var slowQueryCaptureStddevFactor = func() *settings.FloatSetting {
+	s := settings.RegisterNonNegativeFloatSetting(
+		"sql.log.slow_query.capture.stddev_factor",
+		"the number of standard deviations above the mean service latency of its "+
+			"fingerprint that the service latency of a statement must exceed to be captured",
+		3,
+	)
+	s.SetVisibility(settings.Public)
+	return s
+}()
+
+var slowQueryCaptureMinInterval = settings.RegisterPublicNonNegativeDurationSetting(
+	"sql.log.slow_query.capture.min_interval",
+	"the minimum amount of time between two captures of the same statement "+
+		"fingerprint on a node",
+	time.Minute,
+)
+
+var slowQueryCaptureMaxEntries = func() *settings.IntSetting {
+	s := settings.RegisterPositiveIntSetting(
+		"sql.log.slow_query.capture.max_entries",
+		"the maximum number of captured statements retained in system.slow_query_log",
+		1000,
+	)
+	s.SetVisibility(settings.Public)
+	return s
+}()
+
+const (
+	// slowQueryCaptureMinSamples is the number of executions of a fingerprint
+	// recorded in the statement statistics above which its capture threshold
+	// adapts to the distribution of its service latency.
+	slowQueryCaptureMinSamples = 10
+	// slowQueryCaptureMaxFingerprints bounds the number of fingerprints tracked
+	// by a slowQueryCapturer.
+	slowQueryCaptureMaxFingerprints = 1000
+	// slowQueryCaptureMaxTraceSpans bounds the number of spans rendered in the
+	// compact trace of a captured statement.
+	slowQueryCaptureMaxTraceSpans = 100
+)
+
+// slowQueryCapturer tracks the fingerprints of the slow statements of a node.
+type slowQueryCapturer struct {
+	// stopper runs the tasks which persist the captured statements. It is set
+	// when the server is started.
+	stopper *stop.Stopper
+
+	syncutil.Mutex
+	fingerprints map[string]*slowQueryCaptureState
+}
+
+type slowQueryCaptureState struct {
+	// armed is set if the next execution of the fingerprint is to be traced.
+	armed bool
+	// lastCapture is the time at which the fingerprint was last captured.
+	lastCapture time.Time
+}
+
+// arm marks the next execution of the fingerprint to be traced, unless the
+// fingerprint was captured less than minInterval ago.
+func (c *slowQueryCapturer) arm(fingerprint string, now time.Time, minInterval time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	s, ok := c.fingerprints[fingerprint]
+	if !ok {
+		if c.fingerprints == nil {
+			c.fingerprints = make(map[string]*slowQueryCaptureState)
+		}
+		if len(c.fingerprints) >= slowQueryCaptureMaxFingerprints {
+			// Forget about the fingerprints which are not rate limited anymore.
+			for f, st := range c.fingerprints {
+				if !st.armed && now.Sub(st.lastCapture) >= minInterval {
+					delete(c.fingerprints, f)
+				}
+			}
+			if len(c.fingerprints) >= slowQueryCaptureMaxFingerprints {
+				return
+			}
+		}
+		s = &slowQueryCaptureState{}
+		c.fingerprints[fingerprint] = s
+	}
+	if now.Sub(s.lastCapture) < minInterval {
+		return
+	}
+	s.armed = true
+}
+
+// shouldTrace returns whether the execution about to start is to be traced. It
+// disarms the fingerprint, so that only one of its executions is traced.
+func (c *slowQueryCapturer) shouldTrace(fingerprint string) bool {
+	c.Lock()
+	defer c.Unlock()
+	s, ok := c.fingerprints[fingerprint]
+	if !ok || !s.armed {
+		return false
+	}
+	s.armed = false
+	return true
+}
+
+// recordCapture records the capture of the fingerprint, which is not armed
+// again until sql.log.slow_query.capture.min_interval has elapsed.
+func (c *slowQueryCapturer) recordCapture(fingerprint string, now time.Time) {
+	c.Lock()
+	defer c.Unlock()
+	if s, ok := c.fingerprints[fingerprint]; ok {
+		s.lastCapture = now
+	}
+}
+
+// slowQueryCaptureEnabledFor returns whether the statements executed by ex
+// are captured when slow. Statements are not captured until the cluster has
+// been upgraded to a version with the system.slow_query_log table.
+func (ex *connExecutor) slowQueryCaptureEnabledFor(ctx context.Context) bool {
+	if ex.executorType == executorTypeInternal {
+		return false
+	}
+	sv := &ex.server.cfg.Settings.SV
+	return slowQueryCaptureEnabled.Get(sv) && slowQueryLogThreshold.Get(sv) > 0 &&
+		ex.server.slowQueryLogActive(ctx)
+}
+
+// slowQueryLogActive returns whether the system.slow_query_log table exists
+// in the cluster.
+func (s *Server) slowQueryLogActive(ctx context.Context) bool {
+	return s.cfg.Settings.Version.IsActive(ctx, clusterversion.VersionSlowQueryLogTable)
+}
+
+// shouldTraceForSlowQueryCapture returns whether the statement about to be
+// executed is to be traced because a recent execution of its fingerprint was
+// slow.
+func (ex *connExecutor) shouldTraceForSlowQueryCapture(
+	ctx context.Context, stmt *Statement,
+) bool {
+	if !ex.slowQueryCaptureEnabledFor(ctx) {
+		return false
+	}
+	return ex.server.slowQueries.shouldTrace(stmtFingerprint(stmt))
+}
+
+// maybeArmSlowQueryCapture arms the capture of the fingerprint of the
+// statement that was just executed if its service latency exceeds its capture
+// threshold. It is called after the statistics of the execution have been
+// recorded.
+func (ex *connExecutor) maybeArmSlowQueryCapture(ctx context.Context, planner *planner) {
+	if !ex.slowQueryCaptureEnabledFor(ctx) {
+		return
+	}
+	svcLat, ok := ex.serviceLatency()
+	if !ok || svcLat <= ex.slowQueryCaptureThreshold(planner) {
+		return
+	}
+	ex.server.slowQueries.arm(
+		stmtFingerprint(planner.stmt), timeutil.Now(),
+		slowQueryCaptureMinInterval.Get(&ex.server.cfg.Settings.SV),
+	)
+}
+
+// serviceLatency returns the service latency of the statement that was last
+// executed, if its execution completed.
+func (ex *connExecutor) serviceLatency() (time.Duration, bool) {
+	phaseTimes := &ex.statsCollector.phaseTimes
+	end, start := phaseTimes[plannerEndExecStmt], phaseTimes[sessionQueryReceived]
+	if end.Before(start) {
+		return 0, false
+	}
+	return end.Sub(start), true
+}
+
+// slowQueryCaptureThreshold returns the service latency above which an
+// execution of the current statement is captured. It is the slow query log
+// threshold or, once enough executions of the fingerprint have been recorded
+// in the statement statistics, the mean service latency of the fingerprint
+// plus sql.log.slow_query.capture.stddev_factor standard deviations, whichever
+// is greater.
+func (ex *connExecutor) slowQueryCaptureThreshold(planner *planner) time.Duration {
+	sv := &ex.server.cfg.Settings.SV
+	threshold := slowQueryLogThreshold.Get(sv)
+
+	flags := planner.curPlan.flags
+	s := ex.appStats.getStatsForStmt(
+		planner.stmt, flags.IsSet(planFlagDistributed), flags.IsSet(planFlagImplicitTxn),
+		nil /* err */, false, /* createIfNonexistent */
+	)
+	if s == nil {
+		return threshold
+	}
+	s.Lock()
+	count, svcLat := s.data.Count, s.data.ServiceLat
+	s.Unlock()
+	if count < slowQueryCaptureMinSamples {
+		return threshold
+	}
+	stddev := math.Sqrt(svcLat.GetVariance(count))
+	adaptive := time.Duration(
+		(svcLat.Mean + slowQueryCaptureStddevFactor.Get(sv)*stddev) * float64(time.Second),
+	)
+	if adaptive > threshold {
+		return adaptive
+	}
+	return threshold
+}
+
+// slowQueryLogPayload is the payload of the log entries of the captured
+// statements.
+type slowQueryLogPayload struct {
+	ApplicationName        string
+	Fingerprint            string
+	Statement              string
+	ServiceLatency         time.Duration
+	LatencyThreshold       time.Duration
+	Plan                   string `json:",omitempty"`
+	Trace                  string `json:",omitempty"`
+	StatementDiagnosticsID int64  `json:",omitempty"`
+}
+
+// finishSlowQueryCapture is called once a statement traced for the capture of
+// slow statements has executed. If the execution was slow, the statement is
+// captured.
+func (ex *connExecutor) finishSlowQueryCapture(
+	ctx context.Context, planner *planner, trace tracing.Recording, ie *InternalExecutor,
+) {
+	svcLat, ok := ex.serviceLatency()
+	if !ok {
+		return
+	}
+	threshold := ex.slowQueryCaptureThreshold(planner)
+	if svcLat <= threshold {
+		return
+	}
+	fingerprint := stmtFingerprint(planner.stmt)
+	ex.server.slowQueries.recordCapture(fingerprint, timeutil.Now())
+
+	q := stmtdiagnostics.SlowQuery{
+		NodeID:           ex.server.cfg.NodeID.Get(),
+		ApplicationName:  ex.sessionData.ApplicationName,
+		Fingerprint:      fingerprint,
+		Statement:        tree.AsString(planner.stmt.AST),
+		ServiceLatency:   svcLat,
+		LatencyThreshold: threshold,
+	}
+	bundle, err := buildStatementBundle(ctx, ex.server.cfg.DB, ie, &planner.curPlan, trace)
+	if err != nil {
+		log.Warningf(ctx, "failed to build the diagnostics bundle of a slow statement: %v", err)
+	} else {
+		q.TraceJSON, q.Bundle = bundle.trace, bundle.zip
+	}
+	// The diagrams were annotated with the statistics of the trace when the
+	// bundle was built.
+	q.Plan = slowQueryPlan(&planner.curPlan)
+	q.Trace = compactTrace(trace)
+
+	// The statement is persisted asynchronously so that its execution is not
+	// delayed further by the writes to the system tables.
+	persistCtx := ex.server.cfg.AmbientCtx.AnnotateCtx(context.Background())
+	persist := func(ctx context.Context) {
+		ex.server.persistSlowQuery(ctx, q)
+	}
+	if stopper := ex.server.slowQueries.stopper; stopper == nil {
+		persist(persistCtx)
+	} else if err := stopper.RunAsyncTask(persistCtx, "sql.slowQueryCapture: persist", persist); err != nil {
+		log.Warningf(ctx, "failed to record slow statement: %v", err)
+	}
+}
+
+// persistSlowQuery records a captured statement in system.slow_query_log,
+// trimming the table to sql.log.slow_query.capture.max_entries, and logs it to
+// the SQL_PERF channel along with the ID of its diagnostics bundle.
+func (s *Server) persistSlowQuery(ctx context.Context, q stmtdiagnostics.SlowQuery) {
+	if !s.slowQueryLogActive(ctx) {
+		return
+	}
+	diagID, err := s.cfg.StmtDiagnosticsRecorder.InsertSlowQuery(
+		ctx, q, slowQueryCaptureMaxEntries.Get(&s.cfg.Settings.SV),
+	)
+	if err != nil {
+		log.Warningf(ctx, "failed to record slow statement: %v", err)
+	}
+	log.SQLPerf.Structuredf(ctx, log.Severity_INFO, slowQueryLogPayload{
+		ApplicationName:        q.ApplicationName,
+		Fingerprint:            q.Fingerprint,
+		Statement:              q.Statement,
+		ServiceLatency:         q.ServiceLatency,
+		LatencyThreshold:       q.LatencyThreshold,
+		Plan:                   q.Plan,
+		Trace:                  q.Trace,
+		StatementDiagnosticsID: diagID,
+	}, "slow statement captured: %q %q %s (threshold %s)",
+		q.ApplicationName, q.Statement, q.ServiceLatency, q.LatencyThreshold)
+}
+
+// stmtFingerprint returns the fingerprint of the statement under which its
+// statistics are recorded.
+func stmtFingerprint(stmt *Statement) string {
+	if stmt.AnonymizedStr != "" {
+		return stmt.AnonymizedStr
+	}
+	return anonymizeStmt(stmt.AST)
+}
+
+// slowQueryPlan renders the plan of a captured statement, followed by the
+// URLs of its DistSQL diagrams, which include the execution statistics of
+// each operator.
+func slowQueryPlan(plan *planTop) string {
+	var buf strings.Builder
+	buf.WriteString(plan.instrumentation.planString)
+	for _, d := range plan.distSQLDiagrams {
+		_, url, err := d.ToURL()
+		if err != nil {
+			fmt.Fprintf(&buf, "\ndiagram: %v", err)
+			continue
+		}
+		fmt.Fprintf(&buf, "\ndiagram: %s", url.String())
+	}
+	return buf.String()
+}
+
+// compactTrace renders a recording with one line per span, indented by the
+// depth of the span and annotated with its duration. At most
+// slowQueryCaptureMaxTraceSpans spans are rendered, followed by the number of
+// the other spans.
+func compactTrace(trace tracing.Recording) string {
+	if len(trace) == 0 {
+		return ""
+	}
+	var buf strings.Builder
+	var spans int
+	var visit func(sp *tracing.RecordedSpan, depth int)
+	visit = func(sp *tracing.RecordedSpan, depth int) {
+		spans++
+		// The spans beyond the limit are still visited to be counted.
+		if spans <= slowQueryCaptureMaxTraceSpans {
+			fmt.Fprintf(&buf, "%s%s: %s\n", strings.Repeat("  ", depth), sp.Operation, sp.Duration)
+		}
+		for i := range trace {
+			if trace[i].ParentSpanID == sp.SpanID {
+				visit(&trace[i], depth+1)
+			}
+		}
+	}
+	// The first span of the recording contains all the other ones.
+	visit(&trace[0], 0)
+	if spans > slowQueryCaptureMaxTraceSpans {
+		fmt.Fprintf(&buf, "... %d more spans\n", spans-slowQueryCaptureMaxTraceSpans)
+	}
+	return buf.String()
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// TestCompactTrace checks that the spans of a recording which are not rendered
+// because of slowQueryCaptureMaxTraceSpans are all counted, including the
+// descendants of the spans which are not rendered.
+func TestCompactTrace(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// The root span has slowQueryCaptureMaxTraceSpans children, and its last
+	// child has 10 children.
+	trace := tracing.Recording{{SpanID: 1, Operation: "root"}}
+	for i := 0; i < slowQueryCaptureMaxTraceSpans; i++ {
+		trace = append(trace, tracing.RecordedSpan{
+			SpanID: uint64(2 + i), ParentSpanID: 1, Operation: fmt.Sprintf("child%d", i),
+		})
+	}
+	lastChild := trace[len(trace)-1].SpanID
+	for i := 0; i < 10; i++ {
+		trace = append(trace, tracing.RecordedSpan{
+			SpanID: lastChild + 1 + uint64(i), ParentSpanID: lastChild, Operation: "grandchild",
+		})
+	}
+
+	res := compactTrace(trace)
+	lines := strings.Split(strings.TrimSuffix(res, "\n"), "\n")
+	if len(lines) != slowQueryCaptureMaxTraceSpans+1 {
+		t.Fatalf("expected %d lines, got %d:\n%s", slowQueryCaptureMaxTraceSpans+1, len(lines), res)
+	}
+	if lines[0] != "root: 0s" || lines[1] != "  child0: 0s" {
+		t.Errorf("unexpected first spans:\n%s", res)
+	}
+	if last := lines[len(lines)-1]; last != "... 11 more spans" {
+		t.Errorf("expected the last child and its children to be counted, got %q", last)
+	}
+}
@@ -158,5 +158,13 @@ const (
 	PgCatalogStatActivityTableID
 	PgCatalogSecurityLabelTableID
 	PgCatalogSharedSecurityLabelTableID
-	MinVirtualID = PgCatalogSharedSecurityLabelTableID
+	// The IDs below are appended out of order so that the IDs of the virtual
+	// tables above, which are exposed as OIDs, do not change.
+	CrdbInternalCreateFunctionStmtsTableID
+	CrdbInternalStatementStatisticsTableID
+	CrdbInternalTransactionStatisticsTableID
+	CrdbInternalClusterContentionEventsTableID
+	CrdbInternalIndexUsageStatisticsTableID
+	CrdbInternalSessionLastQueryStatisticsTableID
+	MinVirtualID = CrdbInternalSessionLastQueryStatisticsTableID
 )
@@ -23,6 +23,10 @@ const (
 	// an upsert statement.
 	DuplicateUpsertErrText = "UPSERT or INSERT...ON CONFLICT command cannot affect row a second time"
 
+	// DuplicateMergeErrText is error text used when a target row is matched by
+	// more than one source row in a merge statement.
+	DuplicateMergeErrText = "MERGE command cannot affect row a second time"
+
 	txnAbortedMsg = "current transaction is aborted, commands ignored " +
 		"until end of transaction block"
 	txnCommittedMsg = "current transaction is committed, commands ignored " +
@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/ts/tspb"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
@@ -132,6 +133,13 @@ func (ep *DummyPrivilegedAccessor) LookupZoneConfigByNamespaceID(
 	return "", false, errors.WithStack(errEvalPrivileged)
 }
 
+// QueryTimeSeries is part of the tree.PrivilegedAccessor interface.
+func (ep *DummyPrivilegedAccessor) QueryTimeSeries(
+	ctx context.Context, req *tspb.TimeSeriesQueryRequest,
+) (*tspb.TimeSeriesQueryResponse, error) {
+	return nil, errors.WithStack(errEvalPrivileged)
+}
+
 // DummySessionAccessor implements the tree.EvalSessionAccessor interface by returning errors.
 type DummySessionAccessor struct{}
 
@@ -0,0 +1,182 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sqlbase
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// UsesColumn returns whether the exclusion constraint uses the specified
+// column.
+func (c *TableDescriptor_ExclusionConstraint) UsesColumn(colID ColumnID) bool {
+	for _, id := range c.ColumnIDs {
+		if id == colID {
+			return true
+		}
+	}
+	return false
+}
+
+// ComparisonOperator returns the comparison operator corresponding to the
+// exclusion constraint element operator.
+func (op TableDescriptor_ExclusionConstraint_Element_Operator) ComparisonOperator() tree.ComparisonOperator {
+	if op == TableDescriptor_ExclusionConstraint_Element_OVERLAPS {
+		return tree.Overlaps
+	}
+	return tree.EQ
+}
+
+// TableDef reconstructs the AST of the exclusion constraint.
+func (c *TableDescriptor_ExclusionConstraint) TableDef() (*tree.ExcludeConstraintTableDef, error) {
+	def := &tree.ExcludeConstraintTableDef{
+		Name:  tree.Name(c.Name),
+		Elems: make(tree.ExcludeElemList, len(c.Elements)),
+	}
+	for i := range c.Elements {
+		expr, err := parser.ParseExpr(c.Elements[i].Expr)
+		if err != nil {
+			return nil, pgerror.Wrapf(err, pgcode.Syntax,
+				"could not parse exclusion constraint element %s", c.Elements[i].Expr)
+		}
+		def.Elems[i] = tree.ExcludeElem{Expr: expr, Operator: c.Elements[i].Operator.ComparisonOperator()}
+	}
+	if c.Predicate != "" {
+		pred, err := parser.ParseExpr(c.Predicate)
+		if err != nil {
+			return nil, pgerror.Wrapf(err, pgcode.Syntax,
+				"could not parse exclusion constraint predicate %s", c.Predicate)
+		}
+		def.Where = pred
+	}
+	return def, nil
+}
+
+// exclusionRangeConstructors maps the names of the range constructors that
+// can be used in exclusion constraint elements to the type of their bounds.
+var exclusionRangeConstructors = map[string]*types.T{
+	"int4range": types.Int4,
+	"int8range": types.Int,
+	"numrange":  types.Decimal,
+	"daterange": types.Date,
+	"tsrange":   types.Timestamp,
+	"tstzrange": types.TimestampTZ,
+}
+
+// ExclusionRange is an exclusion constraint element of the form
+//
+//   tstzrange(lower, upper [, bounds])
+//
+// There are no range types, so the range constructor is only recognized
+// syntactically; two such ranges overlap if each one's lower bound comes
+// before the other one's upper bound. As with ranges, a NULL bound is
+// unbounded and an empty range does not overlap anything.
+type ExclusionRange struct {
+	// Constructor is the name of the range constructor.
+	Constructor string
+	// BoundType is the type of the bounds of the range.
+	BoundType *types.T
+	Lower     tree.Expr
+	Upper     tree.Expr
+	// LowerInclusive and UpperInclusive are determined by the optional bounds
+	// argument, which defaults to '[)'.
+	LowerInclusive bool
+	UpperInclusive bool
+}
+
+// MakeExclusionRange returns the range described by an exclusion constraint
+// element if the element is a range constructor call. It returns ok=false if
+// the element is any other expression.
+func MakeExclusionRange(expr tree.Expr) (_ ExclusionRange, ok bool, _ error) {
+	fn, isFunc := expr.(*tree.FuncExpr)
+	if !isFunc {
+		return ExclusionRange{}, false, nil
+	}
+	name, isName := fn.Func.FunctionReference.(*tree.UnresolvedName)
+	if !isName || name.NumParts != 1 {
+		return ExclusionRange{}, false, nil
+	}
+	constructor := strings.ToLower(name.Parts[0])
+	boundType, isRange := exclusionRangeConstructors[constructor]
+	if !isRange {
+		return ExclusionRange{}, false, nil
+	}
+	if len(fn.Exprs) != 2 && len(fn.Exprs) != 3 {
+		return ExclusionRange{}, false, pgerror.Newf(pgcode.UndefinedFunction,
+			"%s requires 2 or 3 arguments", constructor)
+	}
+	r := ExclusionRange{
+		Constructor:    constructor,
+		BoundType:      boundType,
+		Lower:          fn.Exprs[0],
+		Upper:          fn.Exprs[1],
+		LowerInclusive: true,
+	}
+	if len(fn.Exprs) == 3 {
+		bounds, isStr := fn.Exprs[2].(*tree.StrVal)
+		if !isStr {
+			return ExclusionRange{}, false, pgerror.Newf(pgcode.InvalidParameterValue,
+				"the bounds of %s must be a string constant", constructor)
+		}
+		switch b := bounds.RawString(); b {
+		case "[)":
+		case "[]":
+			r.UpperInclusive = true
+		case "()":
+			r.LowerInclusive = false
+		case "(]":
+			r.LowerInclusive, r.UpperInclusive = false, true
+		default:
+			return ExclusionRange{}, false, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid range bound flags %q", b)
+		}
+	}
+	return r, true, nil
+}
+
+// BoundOperator returns the operator that compares a lower bound with an
+// upper bound: a lower bound comes before an upper bound if the comparison is
+// true. It is LE if both bounds are inclusive and LT otherwise.
+func (r *ExclusionRange) BoundOperator() tree.ComparisonOperator {
+	if r.LowerInclusive && r.UpperInclusive {
+		return tree.LE
+	}
+	return tree.LT
+}
+
+// OverlapsExpr returns an expression that is true if the range with bounds
+// lower1 and upper1 overlaps the range with bounds lower2 and upper2.
+func (r *ExclusionRange) OverlapsExpr(lower1, upper1, lower2, upper2 tree.Expr) tree.Expr {
+	before := func(lower, upper tree.Expr) tree.Expr {
+		return &tree.OrExpr{
+			Left: &tree.OrExpr{
+				Left:  &tree.ComparisonExpr{Operator: tree.IsNotDistinctFrom, Left: lower, Right: tree.DNull},
+				Right: &tree.ComparisonExpr{Operator: tree.IsNotDistinctFrom, Left: upper, Right: tree.DNull},
+			},
+			Right: &tree.ComparisonExpr{Operator: r.BoundOperator(), Left: lower, Right: upper},
+		}
+	}
+	return &tree.AndExpr{
+		Left: &tree.AndExpr{
+			Left:  before(lower1, upper1),
+			Right: before(lower2, upper2),
+		},
+		Right: &tree.AndExpr{
+			Left:  before(lower1, upper2),
+			Right: before(lower2, upper1),
+		},
+	}
+}
@@ -136,7 +136,11 @@ func (p *PrivilegeDescriptor) Grant(user string, privList privilege.List) {
 }
 
 // Revoke removes privileges from this descriptor for a given list of users.
-func (p *PrivilegeDescriptor) Revoke(user string, privList privilege.List) {
+// The object type determines the privileges that remain when some privileges
+// are revoked from a user holding ALL.
+func (p *PrivilegeDescriptor) Revoke(
+	user string, privList privilege.List, objectType privilege.ObjectType,
+) {
 	userPriv, ok := p.findUser(user)
 	if !ok || userPriv.Privileges == 0 {
 		// Removing privileges from a user without privileges is a no-op.
@@ -156,7 +160,7 @@ func (p *PrivilegeDescriptor) Revoke(user string, privList privilege.List) {
 		// User has 'ALL' privilege. Remove it and set
 		// all other privileges one.
 		userPriv.Privileges = 0
-		for _, v := range privilege.ByValue {
+		for _, v := range privilege.GetValidPrivilegesForObject(objectType) {
 			if v != privilege.ALL {
 				userPriv.Privileges |= v.Mask()
 			}
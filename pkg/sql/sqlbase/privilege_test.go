@@ -95,7 +95,7 @@ func TestPrivilege(t *testing.T) {
 				descriptor.Grant(tc.grantee, tc.grant)
 			}
 			if tc.revoke != nil {
-				descriptor.Revoke(tc.grantee, tc.revoke)
+				descriptor.Revoke(tc.grantee, tc.revoke, privilege.Table)
 			}
 		}
 		show := descriptor.Show()
@@ -201,7 +201,7 @@ func TestPrivilegeValidate(t *testing.T) {
 	if err := descriptor.Validate(id); err != nil {
 		t.Fatal(err)
 	}
-	descriptor.Revoke(security.RootUser, privilege.List{privilege.SELECT})
+	descriptor.Revoke(security.RootUser, privilege.List{privilege.SELECT}, privilege.Table)
 	if err := descriptor.Validate(id); err == nil {
 		t.Fatal("unexpected success")
 	}
@@ -211,7 +211,7 @@ func TestPrivilegeValidate(t *testing.T) {
 	if err := descriptor.Validate(id); err == nil {
 		t.Fatal("unexpected success")
 	}
-	descriptor.Revoke(security.RootUser, privilege.List{privilege.ALL})
+	descriptor.Revoke(security.RootUser, privilege.List{privilege.ALL}, privilege.Table)
 	if err := descriptor.Validate(id); err == nil {
 		t.Fatal("unexpected success")
 	}
@@ -273,7 +273,7 @@ func TestSystemPrivilegeValidate(t *testing.T) {
 		}
 
 		// Valid: foo can have privileges revoked, including privileges it doesn't currently have.
-		descriptor.Revoke("foo", privilege.List{privilege.GRANT, privilege.UPDATE, privilege.ALL})
+		descriptor.Revoke("foo", privilege.List{privilege.GRANT, privilege.UPDATE, privilege.ALL}, privilege.Table)
 		if err := descriptor.Validate(id); err != nil {
 			t.Fatal(err)
 		}
@@ -294,14 +294,14 @@ func TestSystemPrivilegeValidate(t *testing.T) {
 
 		// Invalid: root's invalid privileges are revoked and replaced with allowable privileges,
 		// but admin is still wrong.
-		descriptor.Revoke(security.RootUser, privilege.List{privilege.UPDATE})
+		descriptor.Revoke(security.RootUser, privilege.List{privilege.UPDATE}, privilege.Table)
 		descriptor.Grant(security.RootUser, privilege.List{privilege.SELECT, privilege.GRANT})
 		if err := descriptor.Validate(id); !testutils.IsError(err, adminWrongPrivilegesErr) {
 			t.Fatalf("expected err=%s, got err=%v", adminWrongPrivilegesErr, err)
 		}
 
 		// Valid: admin's invalid privileges are revoked and replaced with allowable privileges.
-		descriptor.Revoke(AdminRole, privilege.List{privilege.UPDATE})
+		descriptor.Revoke(AdminRole, privilege.List{privilege.UPDATE}, privilege.Table)
 		descriptor.Grant(AdminRole, privilege.List{privilege.SELECT, privilege.GRANT})
 		if err := descriptor.Validate(id); err != nil {
 			t.Fatal(err)
@@ -100,6 +100,18 @@ func (c ColumnIDs) Equals(input ColumnIDs) bool {
 	return true
 }
 
+// HasAnyOf returns true if any of the input column IDs is in this list.
+func (c ColumnIDs) HasAnyOf(input ColumnIDs) bool {
+	for _, id := range input {
+		for _, id2 := range c {
+			if id == id2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // FamilyID is a custom type for ColumnFamilyDescriptor IDs.
 type FamilyID uint32
 
@@ -480,7 +492,7 @@ func (desc *IndexDescriptor) allocateName(tableDesc *MutableTableDescriptor) {
 	segments := make([]string, 0, len(desc.ColumnNames)+2)
 	segments = append(segments, tableDesc.Name)
 	segments = append(segments, desc.ColumnNames...)
-	if desc.Unique {
+	if desc.Unique || desc.UniqueDeferrable {
 		segments = append(segments, "key")
 	} else {
 		segments = append(segments, "idx")
@@ -676,6 +688,12 @@ func (desc *TableDescriptor) IsView() bool {
 	return desc.ViewQuery != ""
 }
 
+// MaterializedView returns true if the TableDescriptor describes a
+// materialized view, whose data is stored in the KV layer.
+func (desc *TableDescriptor) MaterializedView() bool {
+	return desc.IsMaterializedView
+}
+
 // IsAs returns true if the TableDescriptor actually describes
 // a Table resource with an As source.
 func (desc *TableDescriptor) IsAs() bool {
@@ -718,10 +736,11 @@ func IsVirtualTable(id ID) bool {
 // physical Table that needs to be stored in the kv layer, as opposed to a
 // different resource like a view or a virtual table. Physical tables have
 // primary keys, column families, and indexes (unlike virtual tables).
-// Sequences count as physical tables because their values are stored in
-// the KV layer.
+// Sequences and materialized views count as physical tables because their
+// values are stored in the KV layer.
 func (desc *TableDescriptor) IsPhysicalTable() bool {
-	return desc.IsSequence() || (desc.IsTable() && !desc.IsVirtualTable())
+	return desc.IsSequence() || (desc.IsTable() && !desc.IsVirtualTable()) ||
+		desc.MaterializedView()
 }
 
 // KeysPerRow returns the maximum number of keys used to encode a row for the
@@ -805,6 +824,27 @@ func (desc *TableDescriptor) AllActiveAndInactiveChecks() []*TableDescriptor_Che
 	return checks
 }
 
+// AllActiveAndInactiveExclusions returns all exclusion constraints, including
+// both "active" ones on the table descriptor which are being enforced for all
+// writes, and "inactive" ones queued in the mutations list.
+func (desc *TableDescriptor) AllActiveAndInactiveExclusions() []*TableDescriptor_ExclusionConstraint {
+	exclusions := make([]*TableDescriptor_ExclusionConstraint, 0, len(desc.Exclusions))
+	for i := range desc.Exclusions {
+		c := &desc.Exclusions[i]
+		// Constraints that are being validated or dropped are also present in
+		// the mutations list, so they are excluded here to avoid double-counting.
+		if c.Validity != ConstraintValidity_Validating && c.Validity != ConstraintValidity_Dropping {
+			exclusions = append(exclusions, c)
+		}
+	}
+	for _, m := range desc.Mutations {
+		if c := m.GetConstraint(); c != nil && c.ConstraintType == ConstraintToUpdate_EXCLUSION {
+			exclusions = append(exclusions, &c.Exclusion)
+		}
+	}
+	return exclusions
+}
+
 // GetColumnFamilyForShard returns the column family that a newly added shard column
 // should be assigned to, given the set of columns it's computed from.
 //
@@ -1458,6 +1498,11 @@ func (desc *MutableTableDescriptor) allocateColumnFamilyIDs(columnNames map[stri
 	}
 
 	ensureColumnInFamily := func(col *ColumnDescriptor) {
+		if col.Virtual {
+			// Virtual columns are not stored in the primary index, so they do not
+			// belong to any column family.
+			return
+		}
 		if _, ok := columnsInFamilies[col.ID]; ok {
 			return
 		}
@@ -1844,12 +1889,31 @@ func (desc *TableDescriptor) ValidateTable() error {
 			return err
 		}
 
+		if err := desc.validateVirtualColumns(); err != nil {
+			return err
+		}
+
+		if err := desc.validateExclusionFKActions(); err != nil {
+			return err
+		}
+
+		if err := desc.validateDeferrableUniqueFKActions(); err != nil {
+			return err
+		}
+
+		if err := desc.validateTriggerFKActions(); err != nil {
+			return err
+		}
+
 		if err := desc.validateTableIndexes(columnNames); err != nil {
 			return err
 		}
 		if err := desc.validatePartitioning(); err != nil {
 			return err
 		}
+		if err := desc.validateTriggers(columnIDs); err != nil {
+			return err
+		}
 	}
 
 	// Fill in any incorrect privileges that may have been missed due to mixed-versions.
@@ -1886,6 +1950,30 @@ func (desc *TableDescriptor) ValidateTable() error {
 	return desc.Privileges.Validate(desc.GetID())
 }
 
+// validateTriggers checks that the triggers are sorted by name, which is the
+// order in which they fire, and that they only assign to known columns.
+func (desc *TableDescriptor) validateTriggers(columnIDs map[ColumnID]string) error {
+	for i := range desc.Triggers {
+		t := &desc.Triggers[i]
+		if err := validateName(t.Name, "trigger"); err != nil {
+			return err
+		}
+		if i > 0 && desc.Triggers[i-1].Name >= t.Name {
+			return errors.AssertionFailedf("triggers %q and %q are not sorted by name",
+				desc.Triggers[i-1].Name, t.Name)
+		}
+		if len(t.Events) == 0 {
+			return errors.AssertionFailedf("trigger %q has no events", t.Name)
+		}
+		for _, a := range t.Assignments {
+			if _, ok := columnIDs[a.ColumnID]; !ok {
+				return fmt.Errorf("trigger %q assigns to unknown column ID %d", t.Name, a.ColumnID)
+			}
+		}
+	}
+	return nil
+}
+
 func (desc *TableDescriptor) validateColumnFamilies(columnIDs map[ColumnID]string) error {
 	if len(desc.Families) < 1 {
 		return fmt.Errorf("at least 1 column family must be specified")
@@ -1894,6 +1982,13 @@ func (desc *TableDescriptor) validateColumnFamilies(columnIDs map[ColumnID]strin
 		return fmt.Errorf("the 0th family must have ID 0")
 	}
 
+	virtualColIDs := map[ColumnID]struct{}{}
+	for _, col := range desc.AllNonDropColumns() {
+		if col.Virtual {
+			virtualColIDs[col.ID] = struct{}{}
+		}
+	}
+
 	familyNames := map[string]struct{}{}
 	familyIDs := map[FamilyID]string{}
 	colIDToFamilyID := map[ColumnID]FamilyID{}
@@ -1948,10 +2043,17 @@ func (desc *TableDescriptor) validateColumnFamilies(columnIDs map[ColumnID]strin
 			if famID, ok := colIDToFamilyID[colID]; ok {
 				return fmt.Errorf("column %d is in both family %d and %d", colID, famID, family.ID)
 			}
+			if _, ok := virtualColIDs[colID]; ok {
+				return pgerror.Newf(pgcode.InvalidTableDefinition,
+					"virtual column %q cannot be part of family %q", columnIDs[colID], family.Name)
+			}
 			colIDToFamilyID[colID] = family.ID
 		}
 	}
 	for colID := range columnIDs {
+		if _, ok := virtualColIDs[colID]; ok {
+			continue
+		}
 		if _, ok := colIDToFamilyID[colID]; !ok {
 			return fmt.Errorf("column %d is not in any column family", colID)
 		}
@@ -1959,6 +2061,159 @@ func (desc *TableDescriptor) validateColumnFamilies(columnIDs map[ColumnID]strin
 	return nil
 }
 
+// validateExclusionFKActions checks that the foreign keys of a table with
+// exclusion constraints have no actions that update its rows: the rows updated
+// by foreign key cascades are not checked against the exclusion constraints.
+func (desc *TableDescriptor) validateExclusionFKActions() error {
+	var exclusion *TableDescriptor_ExclusionConstraint
+	for _, c := range desc.AllActiveAndInactiveExclusions() {
+		if c.Validity != ConstraintValidity_Dropping {
+			exclusion = c
+			break
+		}
+	}
+	if exclusion == nil {
+		return nil
+	}
+
+	for _, fk := range desc.AllActiveAndInactiveForeignKeys() {
+		if fk.Validity == ConstraintValidity_Dropping {
+			continue
+		}
+		var action string
+		switch {
+		case fk.OnUpdate != ForeignKeyReference_NO_ACTION && fk.OnUpdate != ForeignKeyReference_RESTRICT:
+			action = "ON UPDATE " + ForeignKeyReferenceActionType[fk.OnUpdate].String()
+		case fk.OnDelete == ForeignKeyReference_SET_NULL || fk.OnDelete == ForeignKeyReference_SET_DEFAULT:
+			action = "ON DELETE " + ForeignKeyReferenceActionType[fk.OnDelete].String()
+		default:
+			continue
+		}
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"foreign key %q cannot have an %s action because table %q has exclusion constraint %q",
+			fk.Name, action, desc.Name, exclusion.Name)
+	}
+	return nil
+}
+
+// validateDeferrableUniqueFKActions checks that the foreign keys of a table
+// have no actions that could set the columns of its DEFERRABLE UNIQUE
+// constraints to duplicate values: the rows updated by foreign key cascades
+// are not checked against these constraints, which are not enforced by unique
+// indexes. Actions that set the columns to NULL are allowed.
+func (desc *TableDescriptor) validateDeferrableUniqueFKActions() error {
+	for _, idx := range desc.AllNonDropIndexes() {
+		if !idx.UniqueDeferrable {
+			continue
+		}
+		for _, fk := range desc.AllActiveAndInactiveForeignKeys() {
+			if fk.Validity == ConstraintValidity_Dropping {
+				continue
+			}
+			if !ColumnIDs(idx.ColumnIDs).HasAnyOf(fk.OriginColumnIDs) {
+				continue
+			}
+			var action string
+			switch {
+			case fk.OnUpdate == ForeignKeyReference_CASCADE || fk.OnUpdate == ForeignKeyReference_SET_DEFAULT:
+				action = "ON UPDATE " + ForeignKeyReferenceActionType[fk.OnUpdate].String()
+			case fk.OnDelete == ForeignKeyReference_SET_DEFAULT:
+				action = "ON DELETE " + ForeignKeyReferenceActionType[fk.OnDelete].String()
+			default:
+				continue
+			}
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"foreign key %q cannot have an %s action because its columns are used by deferrable unique constraint %q",
+				fk.Name, action, idx.Name)
+		}
+	}
+	return nil
+}
+
+// validateTriggerFKActions checks that the foreign keys of a table with
+// triggers have no actions that write rows which would fire them: the rows
+// deleted or updated by foreign key cascades do not fire triggers. Actions
+// that delete rows conflict with DELETE triggers, and actions that update rows
+// conflict with UPDATE triggers.
+func (desc *TableDescriptor) validateTriggerFKActions() error {
+	if len(desc.Triggers) == 0 {
+		return nil
+	}
+	firedBy := func(event TriggerDescriptor_Event) *TriggerDescriptor {
+		for i := range desc.Triggers {
+			for _, e := range desc.Triggers[i].Events {
+				if e == event {
+					return &desc.Triggers[i]
+				}
+			}
+		}
+		return nil
+	}
+	deleteTrigger := firedBy(TriggerDescriptor_DELETE)
+	updateTrigger := firedBy(TriggerDescriptor_UPDATE)
+
+	for _, fk := range desc.AllActiveAndInactiveForeignKeys() {
+		if fk.Validity == ConstraintValidity_Dropping {
+			continue
+		}
+		var action string
+		var trigger *TriggerDescriptor
+		switch {
+		case fk.OnDelete == ForeignKeyReference_CASCADE && deleteTrigger != nil:
+			action, trigger = "ON DELETE CASCADE", deleteTrigger
+		case (fk.OnDelete == ForeignKeyReference_SET_NULL || fk.OnDelete == ForeignKeyReference_SET_DEFAULT) &&
+			updateTrigger != nil:
+			action, trigger = "ON DELETE "+ForeignKeyReferenceActionType[fk.OnDelete].String(), updateTrigger
+		case fk.OnUpdate != ForeignKeyReference_NO_ACTION && fk.OnUpdate != ForeignKeyReference_RESTRICT &&
+			updateTrigger != nil:
+			action, trigger = "ON UPDATE "+ForeignKeyReferenceActionType[fk.OnUpdate].String(), updateTrigger
+		default:
+			continue
+		}
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"foreign key %q cannot have an %s action because table %q has trigger %q",
+			fk.Name, action, desc.Name, trigger.Name)
+	}
+	return nil
+}
+
+// validateVirtualColumns checks that virtual computed columns are only used
+// where their value does not need to be stored: they must be computed, and they
+// cannot be part of a primary key or be stored by an index.
+func (desc *TableDescriptor) validateVirtualColumns() error {
+	virtualCols := map[ColumnID]string{}
+	for _, col := range desc.AllNonDropColumns() {
+		if !col.Virtual {
+			continue
+		}
+		if !col.IsComputed() {
+			return errors.AssertionFailedf("virtual column %q is not computed", col.Name)
+		}
+		virtualCols[col.ID] = col.Name
+	}
+	if len(virtualCols) == 0 {
+		return nil
+	}
+
+	for _, index := range desc.AllNonDropIndexes() {
+		if index.GetEncodingType(desc.PrimaryIndex.ID) == PrimaryIndexEncoding {
+			for _, colID := range index.ColumnIDs {
+				if name, ok := virtualCols[colID]; ok {
+					return pgerror.Newf(pgcode.InvalidTableDefinition,
+						"primary index column %q cannot be virtual", name)
+				}
+			}
+		}
+		for _, colID := range index.StoreColumnIDs {
+			if name, ok := virtualCols[colID]; ok {
+				return pgerror.Newf(pgcode.InvalidTableDefinition,
+					"index %q cannot store virtual column %q", index.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
 // validateTableIndexes validates that indexes are well formed. Checks include
 // validating the columns involved in the index, verifying the index names and
 // IDs are unique, and the family of the primary key is 0. This does not check
@@ -2652,6 +2907,38 @@ func (desc *TableDescriptor) FindCheckByName(
 	return nil, fmt.Errorf("check %q does not exist", name)
 }
 
+// FindExclusionByName finds the exclusion constraint with the specified name.
+func (desc *TableDescriptor) FindExclusionByName(
+	name string,
+) (*TableDescriptor_ExclusionConstraint, error) {
+	for i := range desc.Exclusions {
+		if desc.Exclusions[i].Name == name {
+			return &desc.Exclusions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("exclusion constraint %q does not exist", name)
+}
+
+// FindTriggerByName finds the trigger with the specified name.
+func (desc *TableDescriptor) FindTriggerByName(name string) (*TriggerDescriptor, error) {
+	for i := range desc.Triggers {
+		if desc.Triggers[i].Name == name {
+			return &desc.Triggers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("trigger %q does not exist", name)
+}
+
+// HasEvent returns whether the trigger fires for the given event.
+func (t *TriggerDescriptor) HasEvent(event TriggerDescriptor_Event) bool {
+	for _, e := range t.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
 // NamesForColumnIDs returns the names for the given column ids, or an error
 // if one or more column ids was missing. Note - this allocates! It's not for
 // hot path code.
@@ -2769,6 +3056,31 @@ func (desc *MutableTableDescriptor) DropConstraint(
 		}
 		return errors.AssertionFailedf("constraint %q not found on table %q", name, desc.Name)
 
+	case ConstraintTypeExclusion:
+		if detail.ExclusionConstraint.Validity == ConstraintValidity_Validating {
+			return unimplemented.NewWithIssueDetailf(42844, "drop-constraint-exclusion-mutation",
+				"constraint %q in the middle of being added, try again later", name)
+		}
+		if detail.ExclusionConstraint.Validity == ConstraintValidity_Dropping {
+			return unimplemented.NewWithIssueDetailf(42844, "drop-constraint-exclusion-mutation",
+				"constraint %q in the middle of being dropped", name)
+		}
+		for i := range desc.Exclusions {
+			c := &desc.Exclusions[i]
+			if c.Name == name {
+				// An unvalidated constraint can be dropped immediately, since there
+				// is no assumption that it holds for all rows.
+				if c.Validity == ConstraintValidity_Unvalidated {
+					desc.Exclusions = append(desc.Exclusions[:i], desc.Exclusions[i+1:]...)
+					return nil
+				}
+				c.Validity = ConstraintValidity_Dropping
+				desc.AddExclusionMutation(c, DescriptorMutation_DROP)
+				return nil
+			}
+		}
+		return errors.AssertionFailedf("constraint %q not found on table %q", name, desc.Name)
+
 	default:
 		return unimplemented.Newf(fmt.Sprintf("drop-constraint-%s", detail.Kind),
 			"constraint %q has unsupported type", tree.ErrNameString(name))
@@ -2822,6 +3134,16 @@ func (desc *MutableTableDescriptor) RenameConstraint(
 		detail.CheckConstraint.Name = newName
 		return nil
 
+	case ConstraintTypeExclusion:
+		if detail.ExclusionConstraint.Validity == ConstraintValidity_Validating {
+			return unimplemented.NewWithIssueDetailf(42844,
+				"rename-constraint-exclusion-mutation",
+				"constraint %q in the middle of being added, try again later",
+				tree.ErrNameStringP(&detail.ExclusionConstraint.Name))
+		}
+		detail.ExclusionConstraint.Name = newName
+		return nil
+
 	default:
 		return unimplemented.Newf(fmt.Sprintf("rename-constraint-%s", detail.Kind),
 			"constraint %q has unsupported type", tree.ErrNameString(oldName))
@@ -3020,6 +3342,18 @@ func (desc *MutableTableDescriptor) MakeMutationComplete(m DescriptorMutation) e
 					return err
 				}
 				col.Nullable = false
+			case ConstraintToUpdate_EXCLUSION:
+				switch t.Constraint.Exclusion.Validity {
+				case ConstraintValidity_Validating:
+					// Constraint already added, just mark it as Validated.
+					if c, err := desc.FindExclusionByName(t.Constraint.Name); err == nil {
+						c.Validity = ConstraintValidity_Validated
+					}
+				case ConstraintValidity_Unvalidated:
+					desc.Exclusions = append(desc.Exclusions, t.Constraint.Exclusion)
+				default:
+					return errors.AssertionFailedf("invalid constraint validity state: %d", t.Constraint.Exclusion.Validity)
+				}
 			default:
 				return errors.Errorf("unsupported constraint type: %d", t.Constraint.ConstraintType)
 			}
@@ -3041,6 +3375,9 @@ func (desc *MutableTableDescriptor) MakeMutationComplete(m DescriptorMutation) e
 			primaryIndexCopy := protoutil.Clone(&desc.PrimaryIndex).(*IndexDescriptor)
 			primaryIndexCopy.EncodingType = PrimaryIndexEncoding
 			for _, col := range desc.Columns {
+				if col.Virtual {
+					continue
+				}
 				containsCol := false
 				for _, colID := range primaryIndexCopy.ColumnIDs {
 					if colID == col.ID {
@@ -3126,6 +3463,22 @@ func (desc *MutableTableDescriptor) AddCheckMutation(
 	desc.addMutation(m)
 }
 
+// AddExclusionMutation adds an exclusion constraint mutation to
+// desc.Mutations.
+func (desc *MutableTableDescriptor) AddExclusionMutation(
+	c *TableDescriptor_ExclusionConstraint, direction DescriptorMutation_Direction,
+) {
+	m := DescriptorMutation{
+		Descriptor_: &DescriptorMutation_Constraint{
+			Constraint: &ConstraintToUpdate{
+				ConstraintType: ConstraintToUpdate_EXCLUSION, Name: c.Name, Exclusion: *c,
+			},
+		},
+		Direction: direction,
+	}
+	desc.addMutation(m)
+}
+
 // AddForeignKeyMutation adds a foreign key constraint mutation to desc.Mutations.
 func (desc *MutableTableDescriptor) AddForeignKeyMutation(
 	fk *ForeignKeyConstraint, direction DescriptorMutation_Direction,
@@ -3307,6 +3660,11 @@ func ColumnNeedsBackfill(desc *ColumnDescriptor) bool {
 	if desc.HasNullDefault() {
 		return false
 	}
+	if desc.Virtual {
+		// Virtual columns are not stored, so the backfill only needs to verify
+		// that the computed values satisfy a NOT NULL constraint.
+		return !desc.Nullable
+	}
 	return desc.HasDefault() || !desc.Nullable || desc.IsComputed()
 }
 
@@ -3446,7 +3804,167 @@ func (desc *DatabaseDescriptor) Validate() error {
 	desc.Privileges.MaybeFixPrivileges(desc.GetID())
 
 	// Validate the privilege descriptor.
-	return desc.Privileges.Validate(desc.GetID())
+	if err := desc.Privileges.Validate(desc.GetID()); err != nil {
+		return err
+	}
+
+	return desc.validateFunctions()
+}
+
+// validateFunctions checks that the functions of the database are well
+// formed, sorted by name and that no two functions have the same signature.
+func (desc *DatabaseDescriptor) validateFunctions() error {
+	ids := make(map[ID]struct{}, len(desc.Functions))
+	for i := range desc.Functions {
+		fn := &desc.Functions[i]
+		if err := validateName(fn.Name, "function"); err != nil {
+			return err
+		}
+		if fn.ID == 0 {
+			return fmt.Errorf("invalid function ID %d", fn.ID)
+		}
+		if _, ok := ids[fn.ID]; ok {
+			return fmt.Errorf("function %q duplicate ID %d", fn.Name, fn.ID)
+		}
+		ids[fn.ID] = struct{}{}
+		if fn.Language != FunctionLanguageSQL {
+			return fmt.Errorf("function %q has unsupported language %q", fn.Name, fn.Language)
+		}
+		if i > 0 && desc.Functions[i-1].Name > fn.Name {
+			return fmt.Errorf("functions are not sorted by name: %q before %q",
+				desc.Functions[i-1].Name, fn.Name)
+		}
+		for j := 0; j < i; j++ {
+			if other := &desc.Functions[j]; other.Name == fn.Name && other.HasArgTypes(fn.ArgTypes()) {
+				return fmt.Errorf("duplicate function signature %s", fn.Signature())
+			}
+		}
+		if fn.Privileges == nil {
+			return fmt.Errorf("function %q has no privileges", fn.Name)
+		}
+		if err := fn.Privileges.Validate(fn.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FunctionLanguageSQL is the language of functions whose body is a SQL
+// statement.
+const FunctionLanguageSQL = "sql"
+
+// FindFunctionsByName returns the overloads of the function with the given
+// name.
+func (desc *DatabaseDescriptor) FindFunctionsByName(name string) []*FunctionDescriptor {
+	var fns []*FunctionDescriptor
+	for i := range desc.Functions {
+		if desc.Functions[i].Name == name {
+			fns = append(fns, &desc.Functions[i])
+		}
+	}
+	return fns
+}
+
+// FindFunction returns the function with the given name and argument types,
+// or nil if there is none.
+func (desc *DatabaseDescriptor) FindFunction(name string, argTypes []*types.T) *FunctionDescriptor {
+	for _, fn := range desc.FindFunctionsByName(name) {
+		if fn.HasArgTypes(argTypes) {
+			return fn
+		}
+	}
+	return nil
+}
+
+// FindFunctionByID returns the function with the given ID, or nil if there
+// is none.
+func (desc *DatabaseDescriptor) FindFunctionByID(id ID) *FunctionDescriptor {
+	for i := range desc.Functions {
+		if desc.Functions[i].ID == id {
+			return &desc.Functions[i]
+		}
+	}
+	return nil
+}
+
+// AddFunction adds a function to the database, keeping the functions sorted
+// by name.
+func (desc *DatabaseDescriptor) AddFunction(fn FunctionDescriptor) {
+	desc.Functions = append(desc.Functions, fn)
+	sort.SliceStable(desc.Functions, func(i, j int) bool {
+		return desc.Functions[i].Name < desc.Functions[j].Name
+	})
+}
+
+// RemoveFunction removes the function with the given ID from the database.
+func (desc *DatabaseDescriptor) RemoveFunction(id ID) {
+	for i := range desc.Functions {
+		if desc.Functions[i].ID == id {
+			desc.Functions = append(desc.Functions[:i], desc.Functions[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetID implements the DescriptorProto interface.
+func (desc *FunctionDescriptor) SetID(id ID) {
+	desc.ID = id
+}
+
+// TypeName returns the plain type of this descriptor.
+func (desc *FunctionDescriptor) TypeName() string {
+	return "function"
+}
+
+// SetName implements the DescriptorProto interface.
+func (desc *FunctionDescriptor) SetName(name string) {
+	desc.Name = name
+}
+
+// GetAuditMode is part of the DescriptorProto interface.
+// Functions cannot be audited.
+func (desc *FunctionDescriptor) GetAuditMode() TableDescriptor_AuditMode {
+	return TableDescriptor_DISABLED
+}
+
+// ArgTypes returns the types of the arguments of the function.
+func (desc *FunctionDescriptor) ArgTypes() []*types.T {
+	argTypes := make([]*types.T, len(desc.Args))
+	for i := range desc.Args {
+		argTypes[i] = &desc.Args[i].Type
+	}
+	return argTypes
+}
+
+// HasArgTypes returns whether the function takes arguments of the given
+// types. As in Postgres, type modifiers such as the width of a string type
+// are not part of the signature of a function.
+func (desc *FunctionDescriptor) HasArgTypes(argTypes []*types.T) bool {
+	if len(argTypes) != len(desc.Args) {
+		return false
+	}
+	for i := range argTypes {
+		if argTypes[i].Oid() != desc.Args[i].Type.Oid() {
+			return false
+		}
+	}
+	return true
+}
+
+// Signature returns the name of the function followed by the types of its
+// arguments, for use in error messages.
+func (desc *FunctionDescriptor) Signature() string {
+	var buf strings.Builder
+	buf.WriteString(desc.Name)
+	buf.WriteByte('(')
+	for i := range desc.Args {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(desc.Args[i].Type.SQLString())
+	}
+	buf.WriteByte(')')
+	return buf.String()
 }
 
 // GetID returns the ID of the descriptor.
@@ -3610,7 +4128,11 @@ func (desc *ColumnDescriptor) SQLString() string {
 	if desc.IsComputed() {
 		f.WriteString(" AS (")
 		f.WriteString(*desc.ComputeExpr)
-		f.WriteString(") STORED")
+		if desc.Virtual {
+			f.WriteString(") VIRTUAL")
+		} else {
+			f.WriteString(") STORED")
+		}
 	}
 	return f.CloseAndGetString()
 }
@@ -3747,6 +4269,43 @@ func (x ForeignKeyReference_Action) String() string {
 	}
 }
 
+// Deferrability returns whether the checks of the foreign key constraint can
+// be deferred, and whether they are by default.
+func (fk *ForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	switch {
+	case fk.InitiallyDeferred:
+		return tree.DeferrableInitiallyDeferred
+	case fk.Deferrable:
+		return tree.DeferrableInitiallyImmediate
+	default:
+		return tree.NotDeferrable
+	}
+}
+
+// UniqueDeferrability returns whether the checks of the UNIQUE constraint
+// backed by the index can be deferred, and whether they are by default. It
+// returns NotDeferrable for the indexes that don't back a DEFERRABLE UNIQUE
+// constraint.
+func (desc *IndexDescriptor) UniqueDeferrability() tree.ConstraintDeferrability {
+	switch {
+	case desc.UniqueInitiallyDeferred:
+		return tree.DeferrableInitiallyDeferred
+	case desc.UniqueDeferrable:
+		return tree.DeferrableInitiallyImmediate
+	default:
+		return tree.NotDeferrable
+	}
+}
+
+// SetUniqueDeferrability marks the index as backing a UNIQUE constraint with
+// the given deferrability. The index is Unique only if the constraint is not
+// deferrable.
+func (desc *IndexDescriptor) SetUniqueDeferrability(d tree.ConstraintDeferrability) {
+	desc.Unique = d == tree.NotDeferrable
+	desc.UniqueDeferrable = d != tree.NotDeferrable
+	desc.UniqueInitiallyDeferred = d == tree.DeferrableInitiallyDeferred
+}
+
 var _ cat.Column = &ColumnDescriptor{}
 
 // IsNullable is part of the cat.Column interface.
@@ -3834,6 +4393,11 @@ func (desc *ColumnDescriptor) ComputedExprStr() string {
 	return *desc.ComputeExpr
 }
 
+// IsVirtual is part of the cat.Column interface.
+func (desc *ColumnDescriptor) IsVirtual() bool {
+	return desc.Virtual
+}
+
 // CheckCanBeFKRef returns whether the given column is computed.
 func (desc *ColumnDescriptor) CheckCanBeFKRef() error {
 	if desc.IsComputed() {
@@ -3936,6 +4500,12 @@ func (desc *ImmutableTableDescriptor) ActiveChecks() []TableDescriptor_CheckCons
 	return desc.allChecks
 }
 
+// ActiveExclusions returns a list of all exclusion constraints that should be
+// enforced on writes (including constraints being added/validated).
+func (desc *ImmutableTableDescriptor) ActiveExclusions() []TableDescriptor_ExclusionConstraint {
+	return desc.Exclusions
+}
+
 // WritableColumns returns a list of public and write-only mutation columns.
 func (desc *ImmutableTableDescriptor) WritableColumns() []ColumnDescriptor {
 	return desc.publicAndNonPublicCols[:len(desc.Columns)+desc.writeOnlyColCount]
@@ -1352,6 +1352,19 @@ func TestColumnNeedsBackfill(t *testing.T) {
 		t.Fatal("Expected explicit SET DEFAULT NULL to require a backfill," +
 			" ColumnNeedsBackfill states that it does not.")
 	}
+
+	// Virtual columns are not stored, so they only require a backfill when
+	// their values must be validated against a NOT NULL constraint.
+	virtualNull := &ColumnDescriptor{Name: "vn", ID: 5, Nullable: true, ComputeExpr: &four, Virtual: true}
+	virtualNotNull := &ColumnDescriptor{Name: "vnn", ID: 6, Nullable: false, ComputeExpr: &four, Virtual: true}
+	if ColumnNeedsBackfill(virtualNull) != false {
+		t.Fatal("Expected nullable virtual column to not require a backfill," +
+			" ColumnNeedsBackfill states that it does.")
+	}
+	if ColumnNeedsBackfill(virtualNotNull) != true {
+		t.Fatal("Expected NOT NULL virtual column to require a backfill," +
+			" ColumnNeedsBackfill states that it does not.")
+	}
 }
 
 func TestDefaultExprNil(t *testing.T) {
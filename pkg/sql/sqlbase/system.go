@@ -286,6 +286,62 @@ create table system.statement_diagnostics(
 
 	FAMILY "primary" (id, statement_fingerprint, statement, collected_at, trace, bundle_chunks, error)
 );`
+
+	// statement_statistics and transaction_statistics hold the SQL statistics
+	// saved by the nodes when they reset their in-memory statistics, in time
+	// buckets of sql.metrics.persisted_stats.aggregation_interval. The
+	// statistics are encoded protobufs (roachpb.StatementStatistics and
+	// roachpb.TxnStats). Rows with node_id 0 hold the statistics of all the
+	// nodes, compacted into one bucket per day.
+	StatementStatisticsTableSchema = `
+CREATE TABLE system.statement_statistics (
+	aggregated_ts TIMESTAMPTZ NOT NULL,
+	app_name      STRING NOT NULL,
+	fingerprint   STRING NOT NULL,
+	failed        BOOL NOT NULL,
+	dist_sql      BOOL NOT NULL,
+	implicit_txn  BOOL NOT NULL,
+	node_id       INT8 NOT NULL,
+	statistics    BYTES NOT NULL,
+	PRIMARY KEY (aggregated_ts, app_name, fingerprint, failed, dist_sql, implicit_txn, node_id),
+
+	FAMILY "primary" (aggregated_ts, app_name, fingerprint, failed, dist_sql, implicit_txn, node_id, statistics)
+);`
+
+	TransactionStatisticsTableSchema = `
+CREATE TABLE system.transaction_statistics (
+	aggregated_ts TIMESTAMPTZ NOT NULL,
+	app_name      STRING NOT NULL,
+	node_id       INT8 NOT NULL,
+	statistics    BYTES NOT NULL,
+	PRIMARY KEY (aggregated_ts, app_name, node_id),
+
+	FAMILY "primary" (aggregated_ts, app_name, node_id, statistics)
+);`
+
+	// slow_query_log holds the slow statements captured by the nodes, along
+	// with their plan and a compact trace (see
+	// sql.log.slow_query.capture.enabled). The full diagnostics bundle of a
+	// captured statement is in system.statement_diagnostics. The nodes trim
+	// the table to sql.log.slow_query.capture.max_entries rows.
+	SlowQueryLogTableSchema = `
+CREATE TABLE system.slow_query_log (
+	id                       INT8 DEFAULT unique_rowid() NOT NULL,
+	collected_at             TIMESTAMPTZ NOT NULL,
+	node_id                  INT8 NOT NULL,
+	application_name         STRING NOT NULL,
+	statement_fingerprint    STRING NOT NULL,
+	statement                STRING NOT NULL,
+	service_latency          INTERVAL NOT NULL,
+	latency_threshold        INTERVAL NOT NULL,
+	plan                     STRING,
+	trace                    STRING,
+	statement_diagnostics_id INT8,
+	PRIMARY KEY (id),
+
+	FAMILY "primary" (id, collected_at, node_id, application_name, statement_fingerprint,
+		statement, service_latency, latency_threshold, plan, trace, statement_diagnostics_id)
+);`
 )
 
 func pk(name string) IndexDescriptor {
@@ -338,6 +394,9 @@ var SystemAllowedPrivileges = map[ID]privilege.List{
 	keys.StatementBundleChunksTableID:         privilege.ReadWriteData,
 	keys.StatementDiagnosticsRequestsTableID:  privilege.ReadWriteData,
 	keys.StatementDiagnosticsTableID:          privilege.ReadWriteData,
+	keys.StatementStatisticsTableID:           privilege.ReadWriteData,
+	keys.TransactionStatisticsTableID:         privilege.ReadWriteData,
+	keys.SlowQueryLogTableID:                  privilege.ReadWriteData,
 }
 
 // Helpers used to make some of the TableDescriptor literals below more concise.
@@ -1454,6 +1513,127 @@ var (
 		FormatVersion:  InterleavedFormatVersion,
 		NextMutationID: 1,
 	}
+
+	StatementStatisticsTable = TableDescriptor{
+		Name:                    "statement_statistics",
+		ID:                      keys.StatementStatisticsTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []ColumnDescriptor{
+			{Name: "aggregated_ts", ID: 1, Type: *types.TimestampTZ, Nullable: false},
+			{Name: "app_name", ID: 2, Type: *types.String, Nullable: false},
+			{Name: "fingerprint", ID: 3, Type: *types.String, Nullable: false},
+			{Name: "failed", ID: 4, Type: *types.Bool, Nullable: false},
+			{Name: "dist_sql", ID: 5, Type: *types.Bool, Nullable: false},
+			{Name: "implicit_txn", ID: 6, Type: *types.Bool, Nullable: false},
+			{Name: "node_id", ID: 7, Type: *types.Int, Nullable: false},
+			{Name: "statistics", ID: 8, Type: *types.Bytes, Nullable: false},
+		},
+		NextColumnID: 9,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name: "primary",
+				ColumnNames: []string{"aggregated_ts", "app_name", "fingerprint", "failed",
+					"dist_sql", "implicit_txn", "node_id", "statistics"},
+				ColumnIDs: []ColumnID{1, 2, 3, 4, 5, 6, 7, 8},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: IndexDescriptor{
+			Name:   "primary",
+			ID:     1,
+			Unique: true,
+			ColumnNames: []string{"aggregated_ts", "app_name", "fingerprint", "failed",
+				"dist_sql", "implicit_txn", "node_id"},
+			ColumnDirections: []IndexDescriptor_Direction{
+				IndexDescriptor_ASC, IndexDescriptor_ASC, IndexDescriptor_ASC, IndexDescriptor_ASC,
+				IndexDescriptor_ASC, IndexDescriptor_ASC, IndexDescriptor_ASC,
+			},
+			ColumnIDs: []ColumnID{1, 2, 3, 4, 5, 6, 7},
+			Version:   SecondaryIndexFamilyFormatVersion,
+		},
+		NextIndexID: 2,
+		Privileges: NewCustomSuperuserPrivilegeDescriptor(
+			SystemAllowedPrivileges[keys.StatementStatisticsTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
+
+	TransactionStatisticsTable = TableDescriptor{
+		Name:                    "transaction_statistics",
+		ID:                      keys.TransactionStatisticsTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []ColumnDescriptor{
+			{Name: "aggregated_ts", ID: 1, Type: *types.TimestampTZ, Nullable: false},
+			{Name: "app_name", ID: 2, Type: *types.String, Nullable: false},
+			{Name: "node_id", ID: 3, Type: *types.Int, Nullable: false},
+			{Name: "statistics", ID: 4, Type: *types.Bytes, Nullable: false},
+		},
+		NextColumnID: 5,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name:        "primary",
+				ColumnNames: []string{"aggregated_ts", "app_name", "node_id", "statistics"},
+				ColumnIDs:   []ColumnID{1, 2, 3, 4},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: IndexDescriptor{
+			Name:             "primary",
+			ID:               1,
+			Unique:           true,
+			ColumnNames:      []string{"aggregated_ts", "app_name", "node_id"},
+			ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC, IndexDescriptor_ASC, IndexDescriptor_ASC},
+			ColumnIDs:        []ColumnID{1, 2, 3},
+			Version:          SecondaryIndexFamilyFormatVersion,
+		},
+		NextIndexID: 2,
+		Privileges: NewCustomSuperuserPrivilegeDescriptor(
+			SystemAllowedPrivileges[keys.TransactionStatisticsTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
+
+	SlowQueryLogTable = TableDescriptor{
+		Name:                    "slow_query_log",
+		ID:                      keys.SlowQueryLogTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []ColumnDescriptor{
+			{Name: "id", ID: 1, Type: *types.Int, DefaultExpr: &uniqueRowIDString, Nullable: false},
+			{Name: "collected_at", ID: 2, Type: *types.TimestampTZ, Nullable: false},
+			{Name: "node_id", ID: 3, Type: *types.Int, Nullable: false},
+			{Name: "application_name", ID: 4, Type: *types.String, Nullable: false},
+			{Name: "statement_fingerprint", ID: 5, Type: *types.String, Nullable: false},
+			{Name: "statement", ID: 6, Type: *types.String, Nullable: false},
+			{Name: "service_latency", ID: 7, Type: *types.Interval, Nullable: false},
+			{Name: "latency_threshold", ID: 8, Type: *types.Interval, Nullable: false},
+			{Name: "plan", ID: 9, Type: *types.String, Nullable: true},
+			{Name: "trace", ID: 10, Type: *types.String, Nullable: true},
+			{Name: "statement_diagnostics_id", ID: 11, Type: *types.Int, Nullable: true},
+		},
+		NextColumnID: 12,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name: "primary",
+				ColumnNames: []string{"id", "collected_at", "node_id", "application_name",
+					"statement_fingerprint", "statement", "service_latency", "latency_threshold",
+					"plan", "trace", "statement_diagnostics_id"},
+				ColumnIDs: []ColumnID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: pk("id"),
+		NextIndexID:  2,
+		Privileges: NewCustomSuperuserPrivilegeDescriptor(
+			SystemAllowedPrivileges[keys.SlowQueryLogTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
 )
 
 // Create a kv pair for the zone config for the given key and config value.
@@ -1512,6 +1692,11 @@ func addSystemDescriptorsToSchema(target *MetadataSchema) {
 	target.AddDescriptor(keys.SystemDatabaseID, &StatementBundleChunksTable)
 	target.AddDescriptor(keys.SystemDatabaseID, &StatementDiagnosticsRequestsTable)
 	target.AddDescriptor(keys.SystemDatabaseID, &StatementDiagnosticsTable)
+
+	// Tables introduced in 20.2.
+	target.AddDescriptor(keys.SystemDatabaseID, &StatementStatisticsTable)
+	target.AddDescriptor(keys.SystemDatabaseID, &TransactionStatisticsTable)
+	target.AddDescriptor(keys.SystemDatabaseID, &SlowQueryLogTable)
 }
 
 // addSystemDatabaseToSchema populates the supplied MetadataSchema with the
@@ -48,7 +48,7 @@ func SanitizeVarFreeExpr(
 	defer semaCtx.Properties.Restore(semaCtx.Properties)
 
 	// Ensure that the expression doesn't contain special functions.
-	flags := tree.RejectSpecial
+	flags := tree.RejectSpecial | tree.RejectUserDefinedFunctions
 	if !allowImpure {
 		flags |= tree.RejectImpureFunctions
 	}
@@ -186,6 +186,7 @@ func MakeColumnDefDescs(
 	if d.IsComputed() {
 		s := tree.Serialize(d.Computed.Expr)
 		col.ComputeExpr = &s
+		col.Virtual = d.IsVirtual()
 	}
 
 	var idx *IndexDescriptor
@@ -217,6 +218,9 @@ func MakeColumnDefDescs(
 		if d.UniqueConstraintName != "" {
 			idx.Name = string(d.UniqueConstraintName)
 		}
+		if !d.PrimaryKey.IsPrimaryKey {
+			idx.SetUniqueDeferrability(d.UniqueDeferrability)
+		}
 	}
 
 	return col, idx, typedExpr, nil
@@ -310,6 +314,8 @@ const (
 	ConstraintTypeUnique ConstraintType = "UNIQUE"
 	// ConstraintTypeCheck identifies a CHECK constraint.
 	ConstraintTypeCheck ConstraintType = "CHECK"
+	// ConstraintTypeExclusion identifies an EXCLUDE constraint.
+	ConstraintTypeExclusion ConstraintType = "EXCLUDE"
 )
 
 // ConstraintDetail describes a constraint.
@@ -328,6 +334,9 @@ type ConstraintDetail struct {
 
 	// Only populated for Check Constraints.
 	CheckConstraint *TableDescriptor_CheckConstraint
+
+	// Only populated for Exclusion Constraints.
+	ExclusionConstraint *TableDescriptor_ExclusionConstraint
 }
 
 type tableLookupFn func(ID) (*TableDescriptor, error)
@@ -397,7 +406,7 @@ func (desc *TableDescriptor) collectConstraintInfo(
 			detail.Columns = index.ColumnNames
 			detail.Index = index
 			info[index.Name] = detail
-		} else if index.Unique {
+		} else if index.Unique || index.UniqueDeferrable {
 			if _, ok := info[index.Name]; ok {
 				return nil, pgerror.Newf(pgcode.DuplicateObject,
 					"duplicate constraint name: %q", index.Name)
@@ -469,6 +478,30 @@ func (desc *TableDescriptor) collectConstraintInfo(
 		}
 		info[c.Name] = detail
 	}
+
+	for _, c := range desc.AllActiveAndInactiveExclusions() {
+		if _, ok := info[c.Name]; ok {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"duplicate constraint name: %q", c.Name)
+		}
+		detail := ConstraintDetail{Kind: ConstraintTypeExclusion}
+		// Constraints in the Validating state are considered Unvalidated for this purpose
+		detail.Unvalidated = c.Validity != ConstraintValidity_Validated
+		detail.ExclusionConstraint = c
+		if tableLookup != nil {
+			def, err := c.TableDef()
+			if err != nil {
+				return nil, err
+			}
+			def.Name = ""
+			detail.Details = tree.AsString(def)
+			detail.Columns, err = desc.NamesForColumnIDs(c.ColumnIDs)
+			if err != nil {
+				return nil, err
+			}
+		}
+		info[c.Name] = detail
+	}
 	return info, nil
 }
 
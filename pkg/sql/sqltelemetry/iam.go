@@ -30,6 +30,8 @@ const (
 	OnDatabase = "on_database"
 	// OnTable is used when a GRANT/REVOKE is happening on a table.
 	OnTable = "on_table"
+	// OnFunction is used when a GRANT/REVOKE is happening on a function.
+	OnFunction = "on_function"
 
 	iamRoles = "iam.roles"
 )
@@ -15,6 +15,7 @@ import (
 	"encoding/binary"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -343,6 +344,80 @@ func (r *Registry) InsertStatementDiagnostics(
 	return int64(id), err
 }
 
+// SlowQuery describes a slow statement execution captured by a node.
+type SlowQuery struct {
+	NodeID           roachpb.NodeID
+	ApplicationName  string
+	Fingerprint      string
+	Statement        string
+	ServiceLatency   time.Duration
+	LatencyThreshold time.Duration
+	// Plan is the EXPLAIN ANALYZE-style plan of the statement, with the
+	// execution statistics of each operator.
+	Plan string
+	// Trace is a compact rendering of the trace of the execution.
+	Trace string
+	// TraceJSON and Bundle are the trace and the diagnostics bundle inserted
+	// into system.statement_diagnostics. Bundle is empty if the bundle could
+	// not be built, in which case no diagnostics are inserted.
+	TraceJSON tree.Datum
+	Bundle    []byte
+}
+
+// InsertSlowQuery records a captured slow statement. Its diagnostics bundle is
+// inserted into system.statement_diagnostics, as for EXPLAIN ANALYZE (DEBUG),
+// and referenced from a new row of system.slow_query_log. The oldest rows of
+// system.slow_query_log are then deleted so that it retains at most
+// maxEntries rows.
+//
+// Returns the ID of the statement diagnostics, or 0 if there are none.
+func (r *Registry) InsertSlowQuery(
+	ctx context.Context, q SlowQuery, maxEntries int64,
+) (int64, error) {
+	if !r.st.Version.IsActive(ctx, clusterversion.VersionSlowQueryLogTable) {
+		return 0, errors.New("system.slow_query_log is not available until the cluster is upgraded")
+	}
+	diagIDVal := tree.DNull
+	var diagID stmtID
+	if len(q.Bundle) != 0 {
+		var err error
+		diagID, err = r.insertStatementDiagnostics(ctx, 0, /* requestID */
+			q.Fingerprint, q.Statement, q.TraceJSON, q.Bundle, nil /* collectionErr */)
+		if err != nil {
+			return 0, err
+		}
+		diagIDVal = tree.NewDInt(tree.DInt(diagID))
+	}
+
+	err := r.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		_, err := r.ie.ExecEx(ctx, "slow-query-log-insert", txn,
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			"INSERT INTO system.slow_query_log "+
+				"(collected_at, node_id, application_name, statement_fingerprint, statement, "+
+				"service_latency, latency_threshold, plan, trace, statement_diagnostics_id) "+
+				"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+			timeutil.Now(), int64(q.NodeID), q.ApplicationName, q.Fingerprint, q.Statement,
+			q.ServiceLatency, q.LatencyThreshold, q.Plan, q.Trace, diagIDVal,
+		)
+		if err != nil {
+			return err
+		}
+		// The IDs are generated by unique_rowid() and thus roughly ordered by
+		// insertion time.
+		_, err = r.ie.ExecEx(ctx, "slow-query-log-trim", txn,
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			"DELETE FROM system.slow_query_log WHERE id IN "+
+				"(SELECT id FROM system.slow_query_log ORDER BY id DESC OFFSET $1)",
+			maxEntries,
+		)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(diagID), nil
+}
+
 // insertStatementDiagnostics inserts a trace into system.statement_diagnostics.
 //
 // traceJSON is either DNull (when collectionErr should not be nil) or a *DJSON.
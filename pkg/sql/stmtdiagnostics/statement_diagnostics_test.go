@@ -228,3 +228,65 @@ func TestChangePollInterval(t *testing.T) {
 	require.NoError(t, err)
 	waitForScans(10) // ensure several scans occur
 }
+
+// Test that the slow statements are captured in system.slow_query_log.
+func TestSlowQueryCapture(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	ctx := context.Background()
+	defer s.Stopper().Stop(ctx)
+
+	for _, stmt := range []string{
+		"SET CLUSTER SETTING sql.log.slow_query.latency_threshold = '10ms'",
+		"SET CLUSTER SETTING sql.log.slow_query.capture.enabled = true",
+		"SET CLUSTER SETTING sql.log.slow_query.capture.min_interval = '0s'",
+	} {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err)
+	}
+
+	// A slow execution arms the capture of the fingerprint, the next one is
+	// traced and captured.
+	var id int64
+	var fingerprint, trace string
+	var plan gosql.NullString
+	var diagID gosql.NullInt64
+	testutils.SucceedsSoon(t, func() error {
+		_, err := db.Exec("SELECT pg_sleep(0.05)")
+		require.NoError(t, err)
+		err = db.QueryRow(
+			"SELECT id, statement_fingerprint, plan, trace, statement_diagnostics_id "+
+				"FROM system.slow_query_log",
+		).Scan(&id, &fingerprint, &plan, &trace, &diagID)
+		if errors.Is(err, gosql.ErrNoRows) {
+			return errors.New("no statement captured yet")
+		}
+		return err
+	})
+	require.Equal(t, "SELECT pg_sleep(_)", fingerprint)
+	require.True(t, plan.Valid)
+	require.Contains(t, trace, "traced statement")
+	require.True(t, diagID.Valid)
+
+	var count int
+	row := db.QueryRow("SELECT count(*) FROM system.statement_diagnostics WHERE id = $1", diagID.Int64)
+	require.NoError(t, row.Scan(&count))
+	require.Equal(t, 1, count)
+
+	// Check that the table is trimmed.
+	_, err := db.Exec("SET CLUSTER SETTING sql.log.slow_query.capture.max_entries = 1")
+	require.NoError(t, err)
+	testutils.SucceedsSoon(t, func() error {
+		// Use another fingerprint, whose capture threshold has not adapted to
+		// its executions yet.
+		_, err := db.Exec("SELECT pg_sleep(0.05), 1")
+		require.NoError(t, err)
+		var minID int64
+		row := db.QueryRow("SELECT count(*), min(id) FROM system.slow_query_log")
+		require.NoError(t, row.Scan(&count, &minID))
+		if count != 1 || minID == id {
+			return errors.Errorf("expected a single, new, captured statement; found %d", count)
+		}
+		return nil
+	})
+}
@@ -114,11 +114,28 @@ type tableWriterBase struct {
 	b *kv.Batch
 	// batchSize is the current batch size (when known).
 	batchSize int
+	// triggers fires the row-level triggers of the table, if any.
+	triggers *rowTriggers
+	// deferredConstraints, if set, is the state of the deferred constraints
+	// of the transaction. The last batch cannot commit the transaction if the
+	// foreign key checks of the row writers saved keys to check before it
+	// commits.
+	deferredConstraints *deferredConstraints
 }
 
-func (tb *tableWriterBase) init(txn *kv.Txn) {
+// makeTableWriterBase returns a tableWriterBase for a statement of the
+// planner's transaction.
+func (p *planner) makeTableWriterBase(triggers *rowTriggers) tableWriterBase {
+	return tableWriterBase{
+		triggers:            triggers,
+		deferredConstraints: p.extendedEvalCtx.DeferredConstraints,
+	}
+}
+
+func (tb *tableWriterBase) init(txn *kv.Txn, evalCtx *tree.EvalContext) {
 	tb.txn = txn
 	tb.b = txn.NewBatch()
+	tb.triggers.init(evalCtx)
 }
 
 // flushAndStartNewBatch shares the common flushAndStartNewBatch() code between
@@ -141,6 +158,12 @@ func (tb *tableWriterBase) curBatchSize() int { return tb.batchSize }
 func (tb *tableWriterBase) finalize(
 	ctx context.Context, tableDesc *sqlbase.ImmutableTableDescriptor,
 ) (err error) {
+	if tb.autoCommit == autoCommitEnabled && tb.deferredConstraints.hasKeys() {
+		// The saved keys are checked by the connExecutor before it commits the
+		// transaction.
+		log.Event(ctx, "autocommit disabled by deferred constraint checks")
+		tb.autoCommit = autoCommitDisabled
+	}
 	if tb.autoCommit == autoCommitEnabled {
 		log.Event(ctx, "autocommit enabled")
 		// An auto-txn can commit the transaction with the batch. This is an
@@ -154,9 +177,19 @@ func (tb *tableWriterBase) finalize(
 	if err != nil {
 		return row.ConvertBatchError(ctx, tableDesc, tb.b)
 	}
-	return nil
+	return tb.triggers.runAfterTriggers(ctx, tb.txn)
 }
 
 func (tb *tableWriterBase) enableAutoCommit() {
+	if tb.triggers.hasAfterTriggers() {
+		// AFTER triggers run in the transaction once the last batch has been
+		// sent, so the batch cannot commit it.
+		return
+	}
 	tb.autoCommit = autoCommitEnabled
 }
+
+// close shares the common close() code between tableWriters.
+func (tb *tableWriterBase) close(ctx context.Context) {
+	tb.triggers.close(ctx)
+}
@@ -43,8 +43,8 @@ func (*tableDeleter) desc() string { return "deleter" }
 func (td *tableDeleter) walkExprs(_ func(desc string, index int, expr tree.TypedExpr)) {}
 
 // init is part of the tableWriter interface.
-func (td *tableDeleter) init(_ context.Context, txn *kv.Txn, _ *tree.EvalContext) error {
-	td.tableWriterBase.init(txn)
+func (td *tableDeleter) init(_ context.Context, txn *kv.Txn, evalCtx *tree.EvalContext) error {
+	td.tableWriterBase.init(txn, evalCtx)
 	return nil
 }
 
@@ -62,6 +62,13 @@ func (td *tableDeleter) finalize(ctx context.Context, _ bool) (*rowcontainer.Row
 func (td *tableDeleter) atBatchEnd(_ context.Context, _ bool) error { return nil }
 
 func (td *tableDeleter) row(ctx context.Context, values tree.Datums, traceKV bool) error {
+	if td.triggers != nil {
+		if err := td.triggers.fire(
+			ctx, sqlbase.TriggerDescriptor_DELETE, nil, nil, values, td.rd.FetchColIDtoRowIndex,
+		); err != nil {
+			return err
+		}
+	}
 	td.batchSize++
 	return td.rd.DeleteRow(ctx, td.b, values, row.CheckFKs, traceKV)
 }
@@ -87,6 +94,12 @@ func fastPathDeleteAvailable(ctx context.Context, desc *ImmutableTableDescriptor
 		}
 		return false
 	}
+	if len(desc.Triggers) > 0 {
+		if log.V(2) {
+			log.Info(ctx, "delete forced to scan: table has triggers")
+		}
+		return false
+	}
 	return true
 }
 
@@ -327,4 +340,6 @@ func (td *tableDeleter) tableDesc() *sqlbase.ImmutableTableDescriptor {
 	return td.rd.Helper.TableDesc
 }
 
-func (td *tableDeleter) close(_ context.Context) {}
+func (td *tableDeleter) close(ctx context.Context) {
+	td.tableWriterBase.close(ctx)
+}
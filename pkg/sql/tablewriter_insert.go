@@ -32,13 +32,21 @@ var _ tableWriter = &tableInserter{}
 func (*tableInserter) desc() string { return "inserter" }
 
 // init is part of the tableWriter interface.
-func (ti *tableInserter) init(_ context.Context, txn *kv.Txn, _ *tree.EvalContext) error {
-	ti.tableWriterBase.init(txn)
+func (ti *tableInserter) init(_ context.Context, txn *kv.Txn, evalCtx *tree.EvalContext) error {
+	ti.tableWriterBase.init(txn, evalCtx)
 	return nil
 }
 
-// row is part of the tableWriter interface.
+// row is part of the tableWriter interface. BEFORE triggers can modify values
+// in place.
 func (ti *tableInserter) row(ctx context.Context, values tree.Datums, traceKV bool) error {
+	if ti.triggers != nil {
+		if err := ti.triggers.fire(
+			ctx, sqlbase.TriggerDescriptor_INSERT, values, ti.ri.InsertColIDtoRowIndex, nil, nil,
+		); err != nil {
+			return err
+		}
+	}
 	ti.batchSize++
 	return ti.ri.InsertRow(ctx, ti.b, values, false /* overwrite */, row.CheckFKs, traceKV)
 }
@@ -62,7 +70,9 @@ func (ti *tableInserter) tableDesc() *sqlbase.ImmutableTableDescriptor {
 }
 
 // close is part of the tableWriter interface.
-func (ti *tableInserter) close(_ context.Context) {}
+func (ti *tableInserter) close(ctx context.Context) {
+	ti.tableWriterBase.close(ctx)
+}
 
 // walkExprs is part of the tableWriter interface.
 func (ti *tableInserter) walkExprs(_ func(desc string, index int, expr tree.TypedExpr)) {}
@@ -0,0 +1,98 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/errors"
+)
+
+// optTableMerger implements the merge operation when it is planned by the
+// cost-based optimizer (CBO). Like optTableUpserter, it relies on the CBO to
+// join the source with the target table and to compute the values to insert
+// and update. The CBO also evaluates the WHEN clauses, and projects a column
+// containing the action to perform for each row. For example:
+//
+//   CREATE TABLE abc (a INT PRIMARY KEY, b INT, c INT)
+//   MERGE INTO abc USING xy ON a = x
+//   WHEN MATCHED AND y = 0 THEN DELETE
+//   WHEN MATCHED THEN UPDATE SET b = y
+//   WHEN NOT MATCHED THEN INSERT VALUES (x, y, NULL)
+//
+// Each input row contains the insert, fetch, and update columns, followed by
+// the action column. Rows that are not affected by any WHEN clause are filtered
+// out by the CBO.
+//
+// For more details on how the CBO compiles MERGE statements, see the block
+// comment on Builder.buildMerge in opt/optbuilder/merge.go.
+type optTableMerger struct {
+	optTableUpserter
+
+	// rd is used when deleting rows.
+	rd row.Deleter
+
+	// actionOrdinal is the ordinal position of the column within the input row
+	// that contains the tree.MergeActionType of the row.
+	actionOrdinal int
+}
+
+var _ tableWriter = &optTableMerger{}
+
+// desc is part of the tableWriter interface.
+func (*optTableMerger) desc() string { return "opt merger" }
+
+// curBatchSize is part of the tableWriter interface.
+func (tm *optTableMerger) curBatchSize() int { return tm.batchSize }
+
+// action returns the action of the given input row.
+func (tm *optTableMerger) action(row tree.Datums) tree.MergeActionType {
+	return tree.MergeActionType(tree.MustBeDInt(row[tm.actionOrdinal]))
+}
+
+// row is part of the tableWriter interface.
+func (tm *optTableMerger) row(ctx context.Context, row tree.Datums, traceKV bool) error {
+	tm.batchSize++
+	tm.resultCount++
+
+	insertEnd := len(tm.ri.InsertCols)
+	switch action := tm.action(row); action {
+	case tree.MergeInsert:
+		return tm.insertNonConflictingRow(ctx, tm.b, row[:insertEnd], false /* overwrite */, traceKV)
+
+	case tree.MergeUpdate:
+		return tm.updateFetchedRow(ctx, row, traceKV)
+
+	case tree.MergeDelete:
+		return tm.deleteFetchedRow(ctx, row[insertEnd:insertEnd+len(tm.fetchCols)], traceKV)
+
+	default:
+		return errors.AssertionFailedf("unexpected merge action %d", action)
+	}
+}
+
+// deleteFetchedRow deletes the existing row whose values are given in
+// fetchRow.
+func (tm *optTableMerger) deleteFetchedRow(
+	ctx context.Context, fetchRow tree.Datums, traceKV bool,
+) error {
+	if tm.triggers != nil {
+		if err := tm.triggers.fire(
+			ctx, sqlbase.TriggerDescriptor_DELETE, nil, nil, fetchRow, tm.rd.FetchColIDtoRowIndex,
+		); err != nil {
+			return err
+		}
+	}
+	return tm.rd.DeleteRow(ctx, tm.b, fetchRow, row.CheckFKs, traceKV)
+}
@@ -32,8 +32,8 @@ var _ tableWriter = &tableUpdater{}
 func (*tableUpdater) desc() string { return "updater" }
 
 // init is part of the tableWriter interface.
-func (tu *tableUpdater) init(_ context.Context, txn *kv.Txn, _ *tree.EvalContext) error {
-	tu.tableWriterBase.init(txn)
+func (tu *tableUpdater) init(_ context.Context, txn *kv.Txn, evalCtx *tree.EvalContext) error {
+	tu.tableWriterBase.init(txn, evalCtx)
 	return nil
 }
 
@@ -45,10 +45,19 @@ func (tu *tableUpdater) row(context.Context, tree.Datums, bool) error {
 	panic("unimplemented")
 }
 
-// rowForUpdate extends row() from the tableWriter interface.
+// rowForUpdate extends row() from the tableWriter interface. BEFORE triggers
+// can modify updateValues in place.
 func (tu *tableUpdater) rowForUpdate(
 	ctx context.Context, oldValues, updateValues tree.Datums, traceKV bool,
 ) (tree.Datums, error) {
+	if tu.triggers != nil {
+		if err := tu.triggers.fire(
+			ctx, sqlbase.TriggerDescriptor_UPDATE,
+			updateValues, tu.ru.UpdateColIDtoRowIndex, oldValues, tu.ru.FetchColIDtoRowIndex,
+		); err != nil {
+			return nil, err
+		}
+	}
 	tu.batchSize++
 	return tu.ru.UpdateRow(ctx, tu.b, oldValues, updateValues, row.CheckFKs, traceKV)
 }
@@ -72,7 +81,9 @@ func (tu *tableUpdater) tableDesc() *sqlbase.ImmutableTableDescriptor {
 }
 
 // close is part of the tableWriter interface.
-func (tu *tableUpdater) close(_ context.Context) {}
+func (tu *tableUpdater) close(ctx context.Context) {
+	tu.tableWriterBase.close(ctx)
+}
 
 // walkExprs is part of the tableWriter interface.
 func (tu *tableUpdater) walkExprs(_ func(desc string, index int, expr tree.TypedExpr)) {}
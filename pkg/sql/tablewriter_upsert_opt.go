@@ -103,6 +103,11 @@ type optTableUpserter struct {
 	// ru is used when updating rows.
 	ru row.Updater
 
+	// updateValues is a buffer for the values of the columns of ru that are
+	// not in updateCols. Row-level triggers can add such columns; see
+	// rowTriggers.extendUpdateCols.
+	updateValues tree.Datums
+
 	// tabColIdxToRetIdx is the mapping from the columns in the table to the
 	// columns in the resultRowBuffer. A value of -1 is used to indicate
 	// that the table column at that index is not part of the resultRowBuffer
@@ -118,7 +123,7 @@ var _ tableWriter = &optTableUpserter{}
 func (tu *optTableUpserter) init(
 	ctx context.Context, txn *kv.Txn, evalCtx *tree.EvalContext,
 ) error {
-	tu.tableWriterBase.init(txn)
+	tu.tableWriterBase.init(txn, evalCtx)
 	tableDesc := tu.tableDesc()
 
 	tu.insertRows.Init(
@@ -201,6 +206,7 @@ func (tu *optTableUpserter) curBatchSize() int { return tu.insertRows.Len() }
 
 // close is part of the tableWriter interface.
 func (tu *optTableUpserter) close(ctx context.Context) {
+	tu.tableWriterBase.close(ctx)
 	tu.insertRows.Close(ctx)
 	if tu.existingRows != nil {
 		tu.existingRows.Close(ctx)
@@ -262,10 +268,26 @@ func (tu *optTableUpserter) row(ctx context.Context, row tree.Datums, traceKV bo
 		// No conflict, so insert a new row.
 		return tu.insertNonConflictingRow(ctx, tu.b, row[:insertEnd], false /* overwrite */, traceKV)
 	}
+	return tu.updateFetchedRow(ctx, row, traceKV)
+}
 
+// updateFetchedRow updates the existing row whose values are fetched in the
+// given input row, using the update values that follow them.
+func (tu *optTableUpserter) updateFetchedRow(
+	ctx context.Context, row tree.Datums, traceKV bool,
+) error {
 	// If no columns need to be updated, then possibly collect the unchanged row.
+	insertEnd := len(tu.ri.InsertCols)
 	fetchEnd := insertEnd + len(tu.fetchCols)
-	if len(tu.updateCols) == 0 {
+	if len(tu.ru.UpdateCols) == 0 {
+		if tu.triggers != nil {
+			if err := tu.triggers.fire(
+				ctx, sqlbase.TriggerDescriptor_UPDATE,
+				nil, nil, row[insertEnd:fetchEnd], tu.ru.FetchColIDtoRowIndex,
+			); err != nil {
+				return err
+			}
+		}
 		if !tu.collectRows {
 			return nil
 		}
@@ -275,11 +297,21 @@ func (tu *optTableUpserter) row(ctx context.Context, row tree.Datums, traceKV bo
 
 	// Update the row.
 	updateEnd := fetchEnd + len(tu.updateCols)
+	fetchRow, updateValues := row[insertEnd:fetchEnd], row[fetchEnd:updateEnd]
+	if len(tu.ru.UpdateCols) > len(tu.updateCols) {
+		// The columns added by triggers keep their existing values unless the
+		// triggers assign to them.
+		tu.updateValues = append(tu.updateValues[:0], updateValues...)
+		for _, col := range tu.ru.UpdateCols[len(tu.updateCols):] {
+			tu.updateValues = append(tu.updateValues, fetchRow[tu.ru.FetchColIDtoRowIndex[col.ID]])
+		}
+		updateValues = tu.updateValues
+	}
 	return tu.updateConflictingRow(
 		ctx,
 		tu.b,
-		row[insertEnd:fetchEnd],
-		row[fetchEnd:updateEnd],
+		fetchRow,
+		updateValues,
 		tu.tableDesc(),
 		traceKV,
 	)
@@ -297,6 +329,14 @@ func (tu *optTableUpserter) atBatchEnd(ctx context.Context, traceKV bool) error
 func (tu *optTableUpserter) insertNonConflictingRow(
 	ctx context.Context, b *kv.Batch, insertRow tree.Datums, overwrite, traceKV bool,
 ) error {
+	if tu.triggers != nil {
+		if err := tu.triggers.fire(
+			ctx, sqlbase.TriggerDescriptor_INSERT, insertRow, tu.ri.InsertColIDtoRowIndex, nil, nil,
+		); err != nil {
+			return err
+		}
+	}
+
 	// Perform the insert proper.
 	if err := tu.ri.InsertRow(
 		ctx, b, insertRow, overwrite, row.CheckFKs, traceKV); err != nil {
@@ -358,6 +398,15 @@ func (tu *optTableUpserter) updateConflictingRow(
 		return err
 	}
 
+	if tu.triggers != nil {
+		if err := tu.triggers.fire(
+			ctx, sqlbase.TriggerDescriptor_UPDATE,
+			updateValues, tu.ru.UpdateColIDtoRowIndex, fetchRow, tu.ru.FetchColIDtoRowIndex,
+		); err != nil {
+			return err
+		}
+	}
+
 	// Queue the update in KV. This also returns an "update row"
 	// containing the updated values for every column in the
 	// table. This is useful for RETURNING, which we collect below.
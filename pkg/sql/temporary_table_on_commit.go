@@ -0,0 +1,244 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/schema"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/errors"
+)
+
+// onCommitTempTable is a temporary table created with ON COMMIT DELETE ROWS or
+// ON COMMIT DROP.
+type onCommitTempTable struct {
+	id     sqlbase.ID
+	action tree.CreateTableOnCommitSetting
+}
+
+// onCommitTempTables holds the temporary tables created by the transaction
+// with ON COMMIT DELETE ROWS or ON COMMIT DROP. Their actions are run right
+// before the transaction commits (see connExecutor.runTempTableOnCommitActions).
+//
+// Once the transaction commits, the session remembers the tables created with
+// ON COMMIT DELETE ROWS: their rows are deleted before each later transaction
+// of the session that wrote to them commits. The other transactions find them
+// empty, and neither scan them nor give up committing in one phase.
+type onCommitTempTables struct {
+	tables []onCommitTempTable
+	// written is the set of the IDs of the temporary tables written by the
+	// transaction.
+	written util.FastIntSet
+}
+
+// register records a table created with the given ON COMMIT action.
+func (t *onCommitTempTables) register(id sqlbase.ID, action tree.CreateTableOnCommitSetting) {
+	t.tables = append(t.tables, onCommitTempTable{id: id, action: action})
+}
+
+// snapshot returns a copy of the state that is not affected by later changes
+// to t.
+func (t *onCommitTempTables) snapshot() onCommitTempTables {
+	return onCommitTempTables{
+		tables:  t.tables[:len(t.tables):len(t.tables)],
+		written: t.written.Copy(),
+	}
+}
+
+// reset clears the state at the end of a transaction.
+func (t *onCommitTempTables) reset() {
+	*t = onCommitTempTables{}
+}
+
+// recordTempTableWrite records that the statement being planned writes to the
+// given table if it is temporary. The statement cannot commit the transaction
+// itself if the rows of the table are to be deleted before the transaction
+// commits.
+func (p *planner) recordTempTableWrite(desc *sqlbase.ImmutableTableDescriptor) {
+	onCommit := p.extendedEvalCtx.OnCommitTempTables
+	if !desc.Temporary || onCommit == nil {
+		return
+	}
+	onCommit.written.Add(int(desc.ID))
+	if p.extendedEvalCtx.DeleteRowsTempTables.Contains(int(desc.ID)) {
+		p.autoCommit = false
+		return
+	}
+	for _, t := range onCommit.tables {
+		if t.id == desc.ID && t.action == tree.CreateTableOnCommitDeleteRows {
+			p.autoCommit = false
+		}
+	}
+}
+
+// runTempTableOnCommitActions runs the ON COMMIT actions of the temporary
+// tables of the session, as part of the transaction that is about to commit:
+// the tables created by the transaction with ON COMMIT DROP are dropped, then
+// the rows of the tables created with ON COMMIT DELETE ROWS are deleted. Only
+// the tables created or written by the transaction can have rows: the rows
+// written by the earlier transactions were deleted when they committed.
+//
+// It returns the set of the ON COMMIT DELETE ROWS tables of the session, which
+// the session remembers once the transaction commits. The tables found to be
+// dropped are removed from it.
+func (ex *connExecutor) runTempTableOnCommitActions(
+	ctx context.Context,
+) (deleteRows util.FastIntSet, _ error) {
+	onCommit := &ex.extraTxnState.onCommitTempTables
+	deleteRows = ex.deleteRowsTempTables.Copy()
+	toDelete := deleteRows.Intersection(onCommit.written)
+	var drop []sqlbase.ID
+	for _, t := range onCommit.tables {
+		switch t.action {
+		case tree.CreateTableOnCommitDeleteRows:
+			deleteRows.Add(int(t.id))
+			toDelete.Add(int(t.id))
+		case tree.CreateTableOnCommitDrop:
+			drop = append(drop, t.id)
+		}
+	}
+	if toDelete.Empty() && len(drop) == 0 {
+		return deleteRows, nil
+	}
+
+	p := &ex.planner
+	if err := dropOnCommitTempTables(ctx, p, drop); err != nil {
+		return util.FastIntSet{}, err
+	}
+
+	// The rows are deleted with range deletions over the spans of the indexes
+	// of the tables, except when the table shares its spans with other tables
+	// or when the actions of foreign keys must run (see
+	// canDeleteRangeOnCommit); the rows of these tables are deleted with a
+	// DELETE statement.
+	var b *kv.Batch
+	var deleteStmts []sqlbase.ID
+	for _, i := range toDelete.Ordered() {
+		desc, err := p.Tables().getTableVersionByID(ctx, p.txn, sqlbase.ID(i), tree.ObjectLookupFlags{})
+		if err != nil {
+			if pgerror.GetPGCode(err) == pgcode.UndefinedTable {
+				// The table was dropped.
+				deleteRows.Remove(i)
+				continue
+			}
+			return util.FastIntSet{}, err
+		}
+		if !canDeleteRangeOnCommit(desc, deleteRows) {
+			deleteStmts = append(deleteStmts, desc.ID)
+			continue
+		}
+		// Skip the tables that are already empty, which avoids turning
+		// read-only transactions into read-write ones.
+		span := desc.PrimaryIndexSpan()
+		kvs, err := p.txn.Scan(ctx, span.Key, span.EndKey, 1 /* maxRows */)
+		if err != nil {
+			return util.FastIntSet{}, err
+		}
+		if len(kvs) == 0 {
+			continue
+		}
+		if b == nil {
+			b = p.txn.NewBatch()
+		}
+		for _, sp := range desc.AllIndexSpans() {
+			b.DelRange(sp.Key, sp.EndKey, false /* returnKeys */)
+		}
+	}
+	if b != nil {
+		if err := p.txn.Run(ctx, b); err != nil {
+			return util.FastIntSet{}, err
+		}
+	}
+
+	if len(deleteStmts) > 0 {
+		ie := MakeInternalExecutor(ctx, ex.server, ex.memMetrics, ex.server.cfg.Settings)
+		ie.SetSessionData(ex.sessionData)
+		ie.tcModifier = &ex.extraTxnState.tables
+		for _, id := range deleteStmts {
+			if _, err := ie.Exec(
+				ctx, "on-commit-delete-rows", p.txn, fmt.Sprintf("DELETE FROM [%d AS t]", id),
+			); err != nil {
+				return util.FastIntSet{}, err
+			}
+		}
+	}
+	return deleteRows, nil
+}
+
+// dropOnCommitTempTables drops the given ON COMMIT DROP tables, along with the
+// objects that depend on them. The tables that were already dropped by the
+// transaction are skipped.
+func dropOnCommitTempTables(ctx context.Context, p *planner, ids []sqlbase.ID) error {
+	var names tree.TableNames
+	for _, id := range ids {
+		desc, err := p.Tables().getMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			if errors.Is(err, sqlbase.ErrDescriptorNotFound) {
+				continue
+			}
+			return err
+		}
+		if desc.Dropped() {
+			continue
+		}
+		dbDesc, err := sqlbase.GetDatabaseDescFromID(ctx, p.txn, desc.ParentID)
+		if err != nil {
+			return err
+		}
+		scName, err := schema.ResolveNameByID(ctx, p.txn, desc.ParentID, desc.GetParentSchemaID())
+		if err != nil {
+			return err
+		}
+		names = append(names, tree.MakeTableNameWithSchema(
+			tree.Name(dbDesc.Name), tree.Name(scName), tree.Name(desc.Name),
+		))
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	plan, err := p.DropTable(ctx, &tree.DropTable{
+		Names:        names,
+		IfExists:     true,
+		DropBehavior: tree.DropCascade,
+	})
+	if err != nil {
+		return err
+	}
+	defer plan.Close(ctx)
+	return startExec(p.RunParams(ctx), plan)
+}
+
+// canDeleteRangeOnCommit returns whether the rows of the given ON COMMIT
+// DELETE ROWS table can be deleted with range deletions, given the set of all
+// the tables whose rows are deleted. This is not the case if the spans of the
+// table are shared with other tables through interleaving, or if the table is
+// referenced by the foreign key of a table whose rows are not deleted.
+func canDeleteRangeOnCommit(
+	desc *sqlbase.ImmutableTableDescriptor, deleteRows util.FastIntSet,
+) bool {
+	if desc.IsInterleaved() {
+		return false
+	}
+	for i := range desc.InboundFKs {
+		if !deleteRows.Contains(int(desc.InboundFKs[i].OriginTableID)) {
+			return false
+		}
+	}
+	return true
+}
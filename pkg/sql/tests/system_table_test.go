@@ -124,6 +124,9 @@ func TestSystemTableLiterals(t *testing.T) {
 		{keys.StatementBundleChunksTableID, sqlbase.StatementBundleChunksTableSchema, sqlbase.StatementBundleChunksTable},
 		{keys.StatementDiagnosticsRequestsTableID, sqlbase.StatementDiagnosticsRequestsTableSchema, sqlbase.StatementDiagnosticsRequestsTable},
 		{keys.StatementDiagnosticsTableID, sqlbase.StatementDiagnosticsTableSchema, sqlbase.StatementDiagnosticsTable},
+		{keys.StatementStatisticsTableID, sqlbase.StatementStatisticsTableSchema, sqlbase.StatementStatisticsTable},
+		{keys.TransactionStatisticsTableID, sqlbase.TransactionStatisticsTableSchema, sqlbase.TransactionStatisticsTable},
+		{keys.SlowQueryLogTableID, sqlbase.SlowQueryLogTableSchema, sqlbase.SlowQueryLogTable},
 	} {
 		privs := *test.pkg.Privileges
 		gen, err := sql.CreateTestTableDescriptor(
@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -168,6 +169,13 @@ func (ts *txnState) resetForNewSQLTxn(
 			opentracing.ChildOf(parentSp.Context()), tracing.Recordable,
 			tracing.LogTagsFromCtx(connCtx),
 		)
+	} else if remoteCtx := tranCtx.remoteParentSpanContext(); remoteCtx != nil {
+		// Create a span for this SQL txn which continues the trace of the client.
+		sp = tranCtx.tracer.StartSpan(
+			opName,
+			opentracing.ChildOf(remoteCtx), tracing.Recordable,
+			tracing.LogTagsFromCtx(connCtx),
+		)
 	} else {
 		// Create a root span for this SQL txn.
 		sp = tranCtx.tracer.(*tracing.Tracer).StartRootSpan(
@@ -411,7 +419,25 @@ type transitionCtx struct {
 	// sessionTracing provides access to the session's tracing interface. The
 	// state machine needs to see if session tracing is enabled.
 	sessionTracing *SessionTracing
-	settings       *cluster.Settings
+	// sessionData provides access to the session's traceparent, whose trace is
+	// continued by the root spans of new txns. It can be nil.
+	sessionData *sessiondata.SessionData
+	settings    *cluster.Settings
+}
+
+// remoteParentSpanContext returns the context of the client's span described
+// by the session's traceparent, or nil if there is none or if it can't be
+// continued.
+func (tc *transitionCtx) remoteParentSpanContext() opentracing.SpanContext {
+	if tc.sessionData == nil || tc.sessionData.TraceParent == "" {
+		return nil
+	}
+	remoteCtx, err := tc.tracer.(*tracing.Tracer).ExtractTraceParent(tc.sessionData.TraceParent)
+	if err != nil || tracing.IsNoopContext(remoteCtx) {
+		// The traceparent was validated when it was set.
+		return nil
+	}
+	return remoteCtx
 }
 
 var noRewind = rewindCapability{}
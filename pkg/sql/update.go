@@ -252,6 +252,14 @@ func (u *updateNode) processSourceRow(params runParams, sourceVals tree.Datums)
 		}
 	}
 
+	// Any remaining columns were added to the update by row-level triggers,
+	// which may or may not assign to them. They start out with their existing
+	// values.
+	for ; valueIdx < len(u.run.updateValues); valueIdx++ {
+		id := u.run.tu.ru.UpdateCols[valueIdx].ID
+		u.run.updateValues[valueIdx] = oldValues[u.run.tu.ru.FetchColIDtoRowIndex[id]]
+	}
+
 	// At this point, we have populated updateValues with the result of
 	// computing the RHS for every assignment.
 	//
@@ -299,7 +307,7 @@ func (u *updateNode) processSourceRow(params runParams, sourceVals tree.Datums)
 	// constraints itself, or else inspect boolean columns from the input that
 	// contain the results of evaluation.
 	if !u.run.checkOrds.Empty() {
-		checkVals := sourceVals[len(u.run.tu.ru.FetchCols)+len(u.run.tu.ru.UpdateCols)+u.run.numPassthrough:]
+		checkVals := sourceVals[len(u.run.tu.ru.FetchCols)+len(u.run.sourceSlots)+u.run.numPassthrough:]
 		if err := checkMutationInput(u.run.tu.tableDesc(), u.run.checkOrds, checkVals); err != nil {
 			return err
 		}
@@ -336,7 +344,7 @@ func (u *updateNode) processSourceRow(params runParams, sourceVals tree.Datums)
 		// of the target table. We must now extract the columns in the RETURNING
 		// clause that refer to other tables (from the FROM clause of the update).
 		if u.run.numPassthrough > 0 {
-			passthroughBegin := len(u.run.tu.ru.FetchCols) + len(u.run.tu.ru.UpdateCols)
+			passthroughBegin := len(u.run.tu.ru.FetchCols) + len(u.run.sourceSlots)
 			passthroughEnd := passthroughBegin + u.run.numPassthrough
 			passthroughValues := sourceVals[passthroughBegin:passthroughEnd]
 
@@ -0,0 +1,523 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// maxFunctionDepth is the maximum nesting depth of calls of user-defined
+// functions. It guards against infinite recursion, e.g. a function whose body
+// calls the function itself.
+const maxFunctionDepth = 16
+
+// functionDepthKey is the context key for the current nesting depth of calls
+// of user-defined functions.
+type functionDepthKey struct{}
+
+var _ tree.FunctionResolver = &planner{}
+
+// ResolveUserDefinedFunction implements the tree.FunctionResolver interface.
+func (p *planner) ResolveUserDefinedFunction(
+	name *tree.UnresolvedName, searchPath sessiondata.SearchPath,
+) (*tree.FunctionDefinition, error) {
+	if p.txn == nil || name.Star {
+		return nil, nil
+	}
+	dbName := p.functionDatabaseName(name, searchPath)
+	if dbName == "" {
+		return nil, nil
+	}
+	// The database descriptor is read in the transaction rather than from the
+	// cache, so that functions are usable as soon as they are created or
+	// replaced.
+	dbDesc, err := p.LogicalSchemaAccessor().GetDatabaseDesc(
+		p.EvalContext().Context, p.txn, dbName, tree.DatabaseLookupFlags{AvoidCached: true},
+	)
+	if err != nil || dbDesc == nil {
+		return nil, err
+	}
+	fns := dbDesc.FindFunctionsByName(name.Parts[0])
+	if len(fns) == 0 {
+		return nil, nil
+	}
+	return makeUserDefinedFunctionDefinition(dbDesc, fns)
+}
+
+// CheckUserDefinedFunctionPrivilege implements the tree.FunctionResolver
+// interface.
+func (p *planner) CheckUserDefinedFunctionPrivilege(fn *tree.UserDefinedFunction) error {
+	ctx := p.EvalContext().Context
+	dbDesc, err := sqlbase.GetDatabaseDescFromID(ctx, p.txn, sqlbase.ID(fn.ParentID))
+	if err != nil {
+		return err
+	}
+	fnDesc := dbDesc.FindFunctionByID(sqlbase.ID(fn.ID))
+	if fnDesc == nil {
+		return errors.AssertionFailedf("function %d not found in database %q", fn.ID, dbDesc.Name)
+	}
+	return p.CheckPrivilege(ctx, fnDesc, privilege.EXECUTE)
+}
+
+// functionDatabaseName returns the name of the database holding the
+// user-defined function with the given name. Functions live in the public
+// schema of their database, so an unqualified name only designates a
+// user-defined function if the public schema is on the search path, and a
+// two-part name is either schema-qualified (public.f) or database-qualified
+// (db.f). An empty string is returned if the name cannot designate a
+// user-defined function.
+func (p *planner) functionDatabaseName(
+	name *tree.UnresolvedName, searchPath sessiondata.SearchPath,
+) string {
+	switch name.NumParts {
+	case 1:
+		for iter := searchPath.Iter(); ; {
+			schema, ok := iter.Next()
+			if !ok {
+				return ""
+			}
+			if schema == tree.PublicSchema {
+				return p.CurrentDatabase()
+			}
+		}
+	case 2:
+		if name.Parts[1] == tree.PublicSchema {
+			return p.CurrentDatabase()
+		}
+		if isNonPublicSchemaName(name.Parts[1]) {
+			return ""
+		}
+		return name.Parts[1]
+	case 3:
+		if name.Parts[1] != tree.PublicSchema {
+			return ""
+		}
+		return name.Parts[2]
+	}
+	return ""
+}
+
+// isNonPublicSchemaName returns whether the given name designates one of the
+// virtual schemas or a temporary schema, none of which hold user-defined
+// functions.
+func isNonPublicSchemaName(name string) bool {
+	if strings.HasPrefix(name, sessiondata.PgTempSchemaName) {
+		return true
+	}
+	for _, schema := range virtualSchemas {
+		if name == schema.name {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveFunctionObject returns the database and the descriptor of the
+// function designated by a DROP FUNCTION or GRANT target. The returned
+// function descriptor is nil if the function does not exist.
+func (p *planner) resolveFunctionObject(
+	ctx context.Context, obj *tree.FuncObj,
+) (*sqlbase.DatabaseDescriptor, *sqlbase.FunctionDescriptor, error) {
+	dbName := p.functionDatabaseName(obj.Name.ToUnresolvedName(), p.CurrentSearchPath())
+	if dbName == "" {
+		return nil, nil, nil
+	}
+	dbDesc, err := p.LogicalSchemaAccessor().GetDatabaseDesc(
+		ctx, p.txn, dbName, tree.DatabaseLookupFlags{AvoidCached: true},
+	)
+	if err != nil || dbDesc == nil {
+		return nil, nil, err
+	}
+	fnName := obj.Name.Parts[0]
+	if obj.ArgsSpecified {
+		return dbDesc, dbDesc.FindFunction(fnName, obj.Args), nil
+	}
+	switch fns := dbDesc.FindFunctionsByName(fnName); len(fns) {
+	case 0:
+		return dbDesc, nil, nil
+	case 1:
+		return dbDesc, fns[0], nil
+	default:
+		return nil, nil, pgerror.Newf(pgcode.AmbiguousFunction,
+			"function name %q is not unique", fnName)
+	}
+}
+
+// getFunctionDescriptorsFromTargetList fetches the descriptors of the
+// functions targeted by a GRANT or REVOKE statement, as well as the
+// descriptors of the databases containing them, by database ID.
+func (p *planner) getFunctionDescriptorsFromTargetList(
+	ctx context.Context, objs tree.FuncObjs,
+) ([]sqlbase.DescriptorProto, map[sqlbase.ID]*sqlbase.DatabaseDescriptor, error) {
+	descs := make([]sqlbase.DescriptorProto, 0, len(objs))
+	dbDescs := make(map[sqlbase.ID]*sqlbase.DatabaseDescriptor)
+	for i := range objs {
+		dbDesc, fn, err := p.resolveFunctionObject(ctx, &objs[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		if fn == nil {
+			return nil, nil, newUndefinedFunctionError(&objs[i])
+		}
+		// The functions of a database must all be modified in the same copy
+		// of its descriptor.
+		if prev, ok := dbDescs[dbDesc.ID]; ok {
+			fn = prev.FindFunctionByID(fn.ID)
+		} else {
+			dbDescs[dbDesc.ID] = dbDesc
+		}
+		descs = append(descs, fn)
+	}
+	return descs, dbDescs, nil
+}
+
+// newUndefinedFunctionError returns an error for a DROP FUNCTION or GRANT
+// target which does not exist.
+func newUndefinedFunctionError(obj *tree.FuncObj) error {
+	if obj.ArgsSpecified {
+		return pgerror.Newf(pgcode.UndefinedFunction, "function %s does not exist", tree.ErrString(obj))
+	}
+	return pgerror.Newf(pgcode.UndefinedFunction, "function %s() does not exist", tree.ErrString(obj))
+}
+
+// compiledFunction is an overload of a user-defined function prepared for
+// execution.
+type compiledFunction struct {
+	desc   *sqlbase.FunctionDescriptor
+	dbName string
+	// stmt is the body of the function in which the references to the
+	// arguments have been replaced by placeholders.
+	stmt string
+}
+
+var functionVolatilityFromDesc = map[sqlbase.FunctionDescriptor_Volatility]tree.FunctionVolatility{
+	sqlbase.FunctionDescriptor_VOLATILE:  tree.FunctionVolatile,
+	sqlbase.FunctionDescriptor_STABLE:    tree.FunctionStable,
+	sqlbase.FunctionDescriptor_IMMUTABLE: tree.FunctionImmutable,
+}
+
+var functionVolatilityToDesc = map[tree.FunctionVolatility]sqlbase.FunctionDescriptor_Volatility{
+	tree.FunctionVolatile:  sqlbase.FunctionDescriptor_VOLATILE,
+	tree.FunctionStable:    sqlbase.FunctionDescriptor_STABLE,
+	tree.FunctionImmutable: sqlbase.FunctionDescriptor_IMMUTABLE,
+}
+
+// showCreateFunction returns a valid SQL representation of the CREATE
+// FUNCTION statement used to create the given function. The name of the
+// function is not qualified, as for the other CREATE statements exposed in
+// crdb_internal.
+func showCreateFunction(fn *sqlbase.FunctionDescriptor) string {
+	n := tree.CreateFunction{
+		Name:       &tree.UnresolvedObjectName{NumParts: 1, Parts: [3]string{fn.Name}},
+		ReturnType: &fn.ReturnType,
+		ReturnsSet: fn.ReturnsSet,
+		Options: tree.FunctionOptions{
+			Language:      fn.Language,
+			Volatility:    functionVolatilityFromDesc[fn.Volatility],
+			HasVolatility: true,
+			Body:          fn.Body,
+			HasBody:       true,
+		},
+	}
+	for i := range fn.Args {
+		n.Args = append(n.Args, tree.FuncArg{
+			Name: tree.Name(fn.Args[i].Name),
+			Type: &fn.Args[i].Type,
+		})
+	}
+	return tree.AsStringWithFlags(&n, tree.FmtSimple)
+}
+
+// makeUserDefinedFunctionDefinition builds the definition of a user-defined
+// function from the descriptors of its overloads.
+func makeUserDefinedFunctionDefinition(
+	dbDesc *sqlbase.DatabaseDescriptor, fns []*sqlbase.FunctionDescriptor,
+) (*tree.FunctionDefinition, error) {
+	props := tree.FunctionProperties{
+		NullableArgs: true,
+		// The body of the function is run by the internal executor of the
+		// gateway.
+		DistsqlBlacklist: true,
+	}
+	overloads := make([]tree.Overload, len(fns))
+	for i, fn := range fns {
+		if fn.Volatility == sqlbase.FunctionDescriptor_VOLATILE {
+			props.Impure = true
+		}
+		if fn.ReturnsSet {
+			props.Class = tree.GeneratorClass
+		}
+		stmt, err := compileFunctionBody(fn)
+		if err != nil {
+			return nil, errors.Wrapf(err, "function %s", fn.Signature())
+		}
+		cf := &compiledFunction{
+			desc:   fn,
+			dbName: dbDesc.Name,
+			stmt:   tree.AsStringWithFlags(stmt, tree.FmtParsable),
+		}
+		argTypes := make(tree.ArgTypes, len(fn.Args))
+		for j := range fn.Args {
+			argTypes[j].Name = fn.Args[j].Name
+			argTypes[j].Typ = &fn.Args[j].Type
+		}
+		o := tree.Overload{
+			Types:      argTypes,
+			ReturnType: tree.FixedReturnType(&fn.ReturnType),
+			UDF: &tree.UserDefinedFunction{
+				ID:         uint32(fn.ID),
+				ParentID:   uint32(dbDesc.ID),
+				Volatility: functionVolatilityFromDesc[fn.Volatility],
+				Body:       cf.stmt,
+			},
+		}
+		if fn.ReturnsSet {
+			o.Generator = cf.makeGenerator
+			o.Fn = func(*tree.EvalContext, tree.Datums) (tree.Datum, error) {
+				return nil, errors.AssertionFailedf("generator functions cannot be evaluated as scalars")
+			}
+		} else {
+			o.Fn = cf.eval
+			if fn.Volatility != sqlbase.FunctionDescriptor_VOLATILE {
+				o.UDF.InlineExpr = makeFunctionInlineExpr(stmt)
+			}
+		}
+		overloads[i] = o
+	}
+	name := tree.MakeTableNameWithSchema(
+		tree.Name(dbDesc.Name), tree.PublicSchemaName, tree.Name(fns[0].Name),
+	)
+	return tree.NewUserDefinedFunctionDefinition(name.String(), &props, overloads), nil
+}
+
+// compileFunctionBody parses the body of a function and replaces the
+// references to its arguments with placeholders cast to the types of the
+// arguments. Arguments are referenced by name, qualified or not with the
+// name of the function, or by position ($1, $2...). Unlike in Postgres, the
+// names of arguments take precedence over the names of columns.
+func compileFunctionBody(fn *sqlbase.FunctionDescriptor) (tree.Statement, error) {
+	stmt, err := parser.ParseOne(fn.Body)
+	if err != nil {
+		return nil, err
+	}
+	if stmt.AST.StatementType() != tree.Rows {
+		return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"the body of a function must be a statement returning rows, not %s", stmt.AST.StatementTag())
+	}
+	if _, ok := stmt.AST.(*tree.Select); !ok && fn.Volatility != sqlbase.FunctionDescriptor_VOLATILE {
+		return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"%s is not allowed in a non-volatile function", stmt.AST.StatementTag())
+	}
+
+	argIdx := make(map[string]int, len(fn.Args))
+	for i := range fn.Args {
+		if fn.Args[i].Name != "" {
+			argIdx[fn.Args[i].Name] = i
+		}
+	}
+	placeholder := func(idx int) tree.Expr {
+		return &tree.CastExpr{
+			Expr:       &tree.Placeholder{Idx: tree.PlaceholderIdx(idx)},
+			Type:       &fn.Args[idx].Type,
+			SyntaxMode: tree.CastShort,
+		}
+	}
+	return tree.SimpleStmtVisit(stmt.AST, func(expr tree.Expr) (bool, tree.Expr, error) {
+		switch e := expr.(type) {
+		case *tree.UnresolvedName:
+			if e.Star {
+				return false, expr, nil
+			}
+			var idx int
+			var ok bool
+			switch e.NumParts {
+			case 1:
+				idx, ok = argIdx[e.Parts[0]]
+			case 2:
+				if e.Parts[1] == fn.Name {
+					idx, ok = argIdx[e.Parts[0]]
+				}
+			}
+			if !ok {
+				return false, expr, nil
+			}
+			return false, placeholder(idx), nil
+		case *tree.Placeholder:
+			if int(e.Idx) >= len(fn.Args) {
+				return false, nil, pgerror.Newf(pgcode.UndefinedParameter,
+					"there is no parameter %s", e)
+			}
+			return false, placeholder(int(e.Idx)), nil
+		}
+		return true, expr, nil
+	})
+}
+
+// makeFunctionInlineExpr returns the scalar expression computed by the body
+// of a function, if the body is a SELECT of a single expression without any
+// other clause, which only references the arguments of the function and
+// calls builtin scalar functions. Otherwise, nil is returned.
+func makeFunctionInlineExpr(stmt tree.Statement) tree.Expr {
+	sel, ok := stmt.(*tree.Select)
+	if !ok || sel.With != nil || sel.OrderBy != nil || sel.Limit != nil || sel.Locking != nil {
+		return nil
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok || clause.Distinct || clause.DistinctOn != nil || len(clause.Exprs) != 1 ||
+		len(clause.From.Tables) != 0 || clause.From.AsOf.Expr != nil || clause.Where != nil ||
+		clause.GroupBy != nil || clause.Having != nil || clause.Window != nil || clause.TableSelect {
+		return nil
+	}
+	inlinable := true
+	expr := clause.Exprs[0].Expr
+	_, _ = tree.SimpleVisit(expr, func(expr tree.Expr) (bool, tree.Expr, error) {
+		switch e := expr.(type) {
+		case tree.VarName, *tree.Subquery:
+			inlinable = false
+		case *tree.FuncExpr:
+			def, err := e.Func.Resolve(sessiondata.SearchPath{})
+			if err != nil || def.Class != tree.NormalClass || e.WindowDef != nil {
+				inlinable = false
+			}
+		}
+		return inlinable, expr, nil
+	})
+	if !inlinable {
+		return nil
+	}
+	return expr
+}
+
+// run executes the body of the function with the given arguments and
+// returns the values of its single result column.
+func (cf *compiledFunction) run(
+	ctx context.Context, evalCtx *tree.EvalContext, txn *kv.Txn, args tree.Datums,
+) (tree.Datums, error) {
+	depth, _ := ctx.Value(functionDepthKey{}).(int)
+	if depth >= maxFunctionDepth {
+		return nil, pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"function %s exceeds the maximum nesting depth of %d", cf.desc.Name, maxFunctionDepth)
+	}
+	ctx = context.WithValue(ctx, functionDepthKey{}, depth+1)
+
+	qargs := make([]interface{}, len(args))
+	for i := range args {
+		qargs[i] = args[i]
+	}
+	ie, ok := evalCtx.InternalExecutor.(*InternalExecutor)
+	if !ok {
+		return nil, errors.AssertionFailedf("cannot call function %s without an internal executor", cf.desc.Name)
+	}
+	rows, cols, err := ie.QueryWithCols(
+		ctx, "user-defined function", txn,
+		sqlbase.InternalExecutorSessionDataOverride{
+			User:     evalCtx.SessionData.User,
+			Database: cf.dbName,
+		},
+		cf.stmt, qargs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(cols) != 1 {
+		return nil, errors.WithDetail(
+			pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"return type mismatch in function declared to return %s", cf.desc.ReturnType.SQLString()),
+			"The body of the function must return exactly one column.",
+		)
+	}
+	res := make(tree.Datums, len(rows))
+	for i, row := range rows {
+		if row[0] == tree.DNull {
+			res[i] = tree.DNull
+			continue
+		}
+		if res[i], err = tree.PerformCast(evalCtx, row[0], &cf.desc.ReturnType); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// eval evaluates a scalar function. As in Postgres, the result is the first
+// row returned by the body of the function, or NULL if it returns no rows.
+func (cf *compiledFunction) eval(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+	res, err := cf.run(evalCtx.Ctx(), evalCtx, evalCtx.Txn, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return tree.DNull, nil
+	}
+	return res[0], nil
+}
+
+// makeGenerator is the tree.GeneratorFactory of a set-returning function.
+func (cf *compiledFunction) makeGenerator(
+	evalCtx *tree.EvalContext, args tree.Datums,
+) (tree.ValueGenerator, error) {
+	return &functionValueGenerator{cf: cf, evalCtx: evalCtx, args: args}, nil
+}
+
+// functionValueGenerator is the tree.ValueGenerator returning the rows of a
+// set-returning user-defined function.
+type functionValueGenerator struct {
+	cf      *compiledFunction
+	evalCtx *tree.EvalContext
+	args    tree.Datums
+
+	res tree.Datums
+	idx int
+}
+
+var _ tree.ValueGenerator = &functionValueGenerator{}
+
+// ResolvedType implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) ResolvedType() *types.T {
+	return &g.cf.desc.ReturnType
+}
+
+// Start implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) Start(ctx context.Context, txn *kv.Txn) error {
+	res, err := g.cf.run(ctx, g.evalCtx, txn, g.args)
+	if err != nil {
+		return err
+	}
+	g.res = res
+	g.idx = -1
+	return nil
+}
+
+// Next implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) Next(context.Context) (bool, error) {
+	g.idx++
+	return g.idx < len(g.res), nil
+}
+
+// Values implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) Values() tree.Datums {
+	return tree.Datums{g.res[g.idx]}
+}
+
+// Close implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) Close() {}
@@ -577,6 +577,23 @@ var varGen = map[string]sessionVar{
 		},
 		GlobalDefault: globalFalse,
 	},
+
+	// CockroachDB extension.
+	`protect_read_timestamp`: {
+		Get: func(evalCtx *extendedEvalContext) string {
+			return formatBoolAsPostgresSetting(evalCtx.SessionData.ProtectReadTimestamp)
+		},
+		GetStringVal: makePostgresBoolGetStringValFn("protect_read_timestamp"),
+		Set: func(_ context.Context, m *sessionDataMutator, val string) error {
+			b, err := parseBoolVar("protect_read_timestamp", val)
+			if err != nil {
+				return err
+			}
+			m.SetProtectReadTimestamp(b)
+			return nil
+		},
+		GlobalDefault: globalFalse,
+	},
 	// See https://www.postgresql.org/docs/10/static/runtime-config-preset.html
 	`integer_datetimes`: makeReadOnlyVar("on"),
 
@@ -830,6 +847,26 @@ var varGen = map[string]sessionVar{
 		// Setting is done by the SetTracing statement.
 	},
 
+	// CockroachDB extension.
+	// The traces of the transactions continue the trace of the client, when
+	// the traces go to an OpenTelemetry collector.
+	`traceparent`: {
+		Get: func(evalCtx *extendedEvalContext) string {
+			return evalCtx.SessionData.TraceParent
+		},
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			if s != "" {
+				if err := tracing.ValidateTraceParent(s); err != nil {
+					return pgerror.Newf(pgcode.InvalidParameterValue,
+						"invalid value for parameter %q: %q", "traceparent", s)
+				}
+			}
+			m.SetTraceParent(s)
+			return nil
+		},
+		GlobalDefault: func(_ *settings.Values) string { return "" },
+	},
+
 	// CockroachDB extension.
 	`allow_prepare_as_opt_plan`: {
 		Hidden: true,
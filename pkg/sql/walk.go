@@ -554,6 +554,16 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 
 		n.source = v.visit(n.source)
 
+	case *mergeNode:
+		if v.observer.attr != nil {
+			v.observer.attr(name, "into", n.run.tw.tableDesc().Name)
+			v.observer.attr(name, "strategy", n.run.tw.desc())
+			if n.run.tw.autoCommit == autoCommitEnabled {
+				v.observer.attr(name, "auto commit", "")
+			}
+		}
+		n.source = v.visit(n.source)
+
 	case *updateNode:
 		if v.observer.attr != nil {
 			v.observer.attr(name, "table", n.run.tu.tableDesc().Name)
@@ -836,92 +846,100 @@ func joinTypeStr(t sqlbase.JoinType) string {
 // strings are constant and not precomputed so that the type names can
 // be changed without changing the output of "EXPLAIN".
 var planNodeNames = map[reflect.Type]string{
-	reflect.TypeOf(&alterIndexNode{}):        "alter index",
-	reflect.TypeOf(&alterSequenceNode{}):     "alter sequence",
-	reflect.TypeOf(&alterTableNode{}):        "alter table",
-	reflect.TypeOf(&alterRoleNode{}):         "alter role",
-	reflect.TypeOf(&applyJoinNode{}):         "apply-join",
-	reflect.TypeOf(&bufferNode{}):            "buffer node",
-	reflect.TypeOf(&cancelQueriesNode{}):     "cancel queries",
-	reflect.TypeOf(&cancelSessionsNode{}):    "cancel sessions",
-	reflect.TypeOf(&changePrivilegesNode{}):  "change privileges",
-	reflect.TypeOf(&commentOnColumnNode{}):   "comment on column",
-	reflect.TypeOf(&commentOnDatabaseNode{}): "comment on database",
-	reflect.TypeOf(&commentOnIndexNode{}):    "comment on index",
-	reflect.TypeOf(&commentOnTableNode{}):    "comment on table",
-	reflect.TypeOf(&controlJobsNode{}):       "control jobs",
-	reflect.TypeOf(&createDatabaseNode{}):    "create database",
-	reflect.TypeOf(&createIndexNode{}):       "create index",
-	reflect.TypeOf(&createSequenceNode{}):    "create sequence",
-	reflect.TypeOf(&createSchemaNode{}):      "create schema",
-	reflect.TypeOf(&createStatsNode{}):       "create statistics",
-	reflect.TypeOf(&createTableNode{}):       "create table",
-	reflect.TypeOf(&CreateRoleNode{}):        "create user/role",
-	reflect.TypeOf(&createViewNode{}):        "create view",
-	reflect.TypeOf(&delayedNode{}):           "virtual table",
-	reflect.TypeOf(&deleteNode{}):            "delete",
-	reflect.TypeOf(&deleteRangeNode{}):       "delete range",
-	reflect.TypeOf(&distinctNode{}):          "distinct",
-	reflect.TypeOf(&dropDatabaseNode{}):      "drop database",
-	reflect.TypeOf(&dropIndexNode{}):         "drop index",
-	reflect.TypeOf(&dropSequenceNode{}):      "drop sequence",
-	reflect.TypeOf(&dropTableNode{}):         "drop table",
-	reflect.TypeOf(&DropRoleNode{}):          "drop user/role",
-	reflect.TypeOf(&dropViewNode{}):          "drop view",
-	reflect.TypeOf(&errorIfRowsNode{}):       "error if rows",
-	reflect.TypeOf(&explainDistSQLNode{}):    "explain distsql",
-	reflect.TypeOf(&explainPlanNode{}):       "explain plan",
-	reflect.TypeOf(&explainVecNode{}):        "explain vectorized",
-	reflect.TypeOf(&exportNode{}):            "export",
-	reflect.TypeOf(&filterNode{}):            "filter",
-	reflect.TypeOf(&GrantRoleNode{}):         "grant role",
-	reflect.TypeOf(&groupNode{}):             "group",
-	reflect.TypeOf(&hookFnNode{}):            "plugin",
-	reflect.TypeOf(&indexJoinNode{}):         "index-join",
-	reflect.TypeOf(&insertNode{}):            "insert",
-	reflect.TypeOf(&insertFastPathNode{}):    "insert-fast-path",
-	reflect.TypeOf(&joinNode{}):              "join",
-	reflect.TypeOf(&limitNode{}):             "limit",
-	reflect.TypeOf(&lookupJoinNode{}):        "lookup-join",
-	reflect.TypeOf(&max1RowNode{}):           "max1row",
-	reflect.TypeOf(&ordinalityNode{}):        "ordinality",
-	reflect.TypeOf(&projectSetNode{}):        "project set",
-	reflect.TypeOf(&recursiveCTENode{}):      "recursive cte node",
-	reflect.TypeOf(&relocateNode{}):          "relocate",
-	reflect.TypeOf(&renameColumnNode{}):      "rename column",
-	reflect.TypeOf(&renameDatabaseNode{}):    "rename database",
-	reflect.TypeOf(&renameIndexNode{}):       "rename index",
-	reflect.TypeOf(&renameTableNode{}):       "rename table",
-	reflect.TypeOf(&renderNode{}):            "render",
-	reflect.TypeOf(&RevokeRoleNode{}):        "revoke role",
-	reflect.TypeOf(&rowCountNode{}):          "count",
-	reflect.TypeOf(&rowSourceToPlanNode{}):   "row source to plan node",
-	reflect.TypeOf(&saveTableNode{}):         "save table",
-	reflect.TypeOf(&scanBufferNode{}):        "scan buffer node",
-	reflect.TypeOf(&scanNode{}):              "scan",
-	reflect.TypeOf(&scatterNode{}):           "scatter",
-	reflect.TypeOf(&scrubNode{}):             "scrub",
-	reflect.TypeOf(&sequenceSelectNode{}):    "sequence select",
-	reflect.TypeOf(&serializeNode{}):         "run",
-	reflect.TypeOf(&setClusterSettingNode{}): "set cluster setting",
-	reflect.TypeOf(&setVarNode{}):            "set",
-	reflect.TypeOf(&setZoneConfigNode{}):     "configure zone",
-	reflect.TypeOf(&showFingerprintsNode{}):  "showFingerprints",
-	reflect.TypeOf(&showTraceNode{}):         "show trace for",
-	reflect.TypeOf(&showTraceReplicaNode{}):  "replica trace",
-	reflect.TypeOf(&sortNode{}):              "sort",
-	reflect.TypeOf(&splitNode{}):             "split",
-	reflect.TypeOf(&unsplitNode{}):           "unsplit",
-	reflect.TypeOf(&unsplitAllNode{}):        "unsplit all",
-	reflect.TypeOf(&spoolNode{}):             "spool",
-	reflect.TypeOf(&truncateNode{}):          "truncate",
-	reflect.TypeOf(&unaryNode{}):             "emptyrow",
-	reflect.TypeOf(&unionNode{}):             "union",
-	reflect.TypeOf(&updateNode{}):            "update",
-	reflect.TypeOf(&upsertNode{}):            "upsert",
-	reflect.TypeOf(&valuesNode{}):            "values",
-	reflect.TypeOf(&virtualTableNode{}):      "virtual table values",
-	reflect.TypeOf(&windowNode{}):            "window",
-	reflect.TypeOf(&zeroNode{}):              "norows",
-	reflect.TypeOf(&zigzagJoinNode{}):        "zigzag-join",
+	reflect.TypeOf(&alterIndexNode{}):              "alter index",
+	reflect.TypeOf(&alterSequenceNode{}):           "alter sequence",
+	reflect.TypeOf(&alterTableNode{}):              "alter table",
+	reflect.TypeOf(&alterRoleNode{}):               "alter role",
+	reflect.TypeOf(&applyJoinNode{}):               "apply-join",
+	reflect.TypeOf(&bufferNode{}):                  "buffer node",
+	reflect.TypeOf(&cancelQueriesNode{}):           "cancel queries",
+	reflect.TypeOf(&cancelSessionsNode{}):          "cancel sessions",
+	reflect.TypeOf(&changePrivilegesNode{}):        "change privileges",
+	reflect.TypeOf(&commentOnColumnNode{}):         "comment on column",
+	reflect.TypeOf(&commentOnDatabaseNode{}):       "comment on database",
+	reflect.TypeOf(&commentOnIndexNode{}):          "comment on index",
+	reflect.TypeOf(&commentOnTableNode{}):          "comment on table",
+	reflect.TypeOf(&controlJobsNode{}):             "control jobs",
+	reflect.TypeOf(&createDatabaseNode{}):          "create database",
+	reflect.TypeOf(&createFunctionNode{}):          "create function",
+	reflect.TypeOf(&createIndexNode{}):             "create index",
+	reflect.TypeOf(&createSequenceNode{}):          "create sequence",
+	reflect.TypeOf(&createSchemaNode{}):            "create schema",
+	reflect.TypeOf(&createStatsNode{}):             "create statistics",
+	reflect.TypeOf(&createTableNode{}):             "create table",
+	reflect.TypeOf(&createTriggerNode{}):           "create trigger",
+	reflect.TypeOf(&CreateRoleNode{}):              "create user/role",
+	reflect.TypeOf(&createViewNode{}):              "create view",
+	reflect.TypeOf(&delayedNode{}):                 "virtual table",
+	reflect.TypeOf(&deleteNode{}):                  "delete",
+	reflect.TypeOf(&deleteRangeNode{}):             "delete range",
+	reflect.TypeOf(&distinctNode{}):                "distinct",
+	reflect.TypeOf(&dropDatabaseNode{}):            "drop database",
+	reflect.TypeOf(&dropFunctionNode{}):            "drop function",
+	reflect.TypeOf(&dropIndexNode{}):               "drop index",
+	reflect.TypeOf(&dropSequenceNode{}):            "drop sequence",
+	reflect.TypeOf(&dropTableNode{}):               "drop table",
+	reflect.TypeOf(&dropTriggerNode{}):             "drop trigger",
+	reflect.TypeOf(&DropRoleNode{}):                "drop user/role",
+	reflect.TypeOf(&dropViewNode{}):                "drop view",
+	reflect.TypeOf(&errorIfRowsNode{}):             "error if rows",
+	reflect.TypeOf(&explainDistSQLNode{}):          "explain distsql",
+	reflect.TypeOf(&explainPlanNode{}):             "explain plan",
+	reflect.TypeOf(&explainVecNode{}):              "explain vectorized",
+	reflect.TypeOf(&exportNode{}):                  "export",
+	reflect.TypeOf(&filterNode{}):                  "filter",
+	reflect.TypeOf(&GrantRoleNode{}):               "grant role",
+	reflect.TypeOf(&groupNode{}):                   "group",
+	reflect.TypeOf(&hookFnNode{}):                  "plugin",
+	reflect.TypeOf(&indexJoinNode{}):               "index-join",
+	reflect.TypeOf(&insertNode{}):                  "insert",
+	reflect.TypeOf(&insertFastPathNode{}):          "insert-fast-path",
+	reflect.TypeOf(&joinNode{}):                    "join",
+	reflect.TypeOf(&limitNode{}):                   "limit",
+	reflect.TypeOf(&lookupJoinNode{}):              "lookup-join",
+	reflect.TypeOf(&max1RowNode{}):                 "max1row",
+	reflect.TypeOf(&mergeNode{}):                   "merge",
+	reflect.TypeOf(&ordinalityNode{}):              "ordinality",
+	reflect.TypeOf(&projectSetNode{}):              "project set",
+	reflect.TypeOf(&recursiveCTENode{}):            "recursive cte node",
+	reflect.TypeOf(&refreshMaterializedViewNode{}): "refresh materialized view",
+	reflect.TypeOf(&relocateNode{}):                "relocate",
+	reflect.TypeOf(&renameColumnNode{}):            "rename column",
+	reflect.TypeOf(&renameDatabaseNode{}):          "rename database",
+	reflect.TypeOf(&renameIndexNode{}):             "rename index",
+	reflect.TypeOf(&renameTableNode{}):             "rename table",
+	reflect.TypeOf(&renderNode{}):                  "render",
+	reflect.TypeOf(&revertTableNode{}):             "revert table",
+	reflect.TypeOf(&RevokeRoleNode{}):              "revoke role",
+	reflect.TypeOf(&rowCountNode{}):                "count",
+	reflect.TypeOf(&rowSourceToPlanNode{}):         "row source to plan node",
+	reflect.TypeOf(&saveTableNode{}):               "save table",
+	reflect.TypeOf(&scanBufferNode{}):              "scan buffer node",
+	reflect.TypeOf(&scanNode{}):                    "scan",
+	reflect.TypeOf(&scatterNode{}):                 "scatter",
+	reflect.TypeOf(&scrubNode{}):                   "scrub",
+	reflect.TypeOf(&sequenceSelectNode{}):          "sequence select",
+	reflect.TypeOf(&serializeNode{}):               "run",
+	reflect.TypeOf(&setClusterSettingNode{}):       "set cluster setting",
+	reflect.TypeOf(&setConstraintsNode{}):          "set constraints",
+	reflect.TypeOf(&setVarNode{}):                  "set",
+	reflect.TypeOf(&setZoneConfigNode{}):           "configure zone",
+	reflect.TypeOf(&showFingerprintsNode{}):        "showFingerprints",
+	reflect.TypeOf(&showTraceNode{}):               "show trace for",
+	reflect.TypeOf(&showTraceReplicaNode{}):        "replica trace",
+	reflect.TypeOf(&sortNode{}):                    "sort",
+	reflect.TypeOf(&splitNode{}):                   "split",
+	reflect.TypeOf(&unsplitNode{}):                 "unsplit",
+	reflect.TypeOf(&unsplitAllNode{}):              "unsplit all",
+	reflect.TypeOf(&spoolNode{}):                   "spool",
+	reflect.TypeOf(&truncateNode{}):                "truncate",
+	reflect.TypeOf(&unaryNode{}):                   "emptyrow",
+	reflect.TypeOf(&unionNode{}):                   "union",
+	reflect.TypeOf(&updateNode{}):                  "update",
+	reflect.TypeOf(&upsertNode{}):                  "upsert",
+	reflect.TypeOf(&valuesNode{}):                  "values",
+	reflect.TypeOf(&virtualTableNode{}):            "virtual table values",
+	reflect.TypeOf(&windowNode{}):                  "window",
+	reflect.TypeOf(&zeroNode{}):                    "norows",
+	reflect.TypeOf(&zigzagJoinNode{}):              "zigzag-join",
 }
@@ -312,6 +312,21 @@ var backwardCompatibleMigrations = []migrationDescriptor{
 		name:   "add CREATEROLE privilege to admin/root",
 		workFn: addCreateRoleToAdminAndRoot,
 	},
+	{
+		// Introduced in v20.2.
+		name:                "create system.statement_statistics and system.transaction_statistics tables",
+		workFn:              createPersistedSQLStatsTables,
+		includedInBootstrap: clusterversion.VersionByKey(clusterversion.VersionPersistedSQLStats),
+		newDescriptorIDs: staticIDs(keys.StatementStatisticsTableID,
+			keys.TransactionStatisticsTableID),
+	},
+	{
+		// Introduced in v20.2.
+		name:                "create system.slow_query_log table",
+		workFn:              createSlowQueryLogTable,
+		includedInBootstrap: clusterversion.VersionByKey(clusterversion.VersionSlowQueryLogTable),
+		newDescriptorIDs:    staticIDs(keys.SlowQueryLogTableID),
+	},
 }
 
 func staticIDs(ids ...sqlbase.ID) func(ctx context.Context, db db) ([]sqlbase.ID, error) {
@@ -1426,6 +1441,20 @@ func createStatementInfoSystemTables(ctx context.Context, r runner) error {
 	return nil
 }
 
+func createPersistedSQLStatsTables(ctx context.Context, r runner) error {
+	if err := createSystemTable(ctx, r, sqlbase.StatementStatisticsTable); err != nil {
+		return errors.Wrap(err, "failed to create system.statement_statistics")
+	}
+	if err := createSystemTable(ctx, r, sqlbase.TransactionStatisticsTable); err != nil {
+		return errors.Wrap(err, "failed to create system.transaction_statistics")
+	}
+	return nil
+}
+
+func createSlowQueryLogTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, sqlbase.SlowQueryLogTable)
+}
+
 // SettingsDefaultOverrides documents the effect of several migrations that add
 // an explicit value for a setting, effectively changing the "default value"
 // from what was defined in code.
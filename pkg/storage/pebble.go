@@ -334,6 +334,7 @@ var pebbleLog *log.SecondaryLogger
 func InitPebbleLogger(ctx context.Context) *log.SecondaryLogger {
 	pebbleLog = log.NewSecondaryLogger(ctx, nil, "pebble",
 		true /* enableGC */, false /* forceSyncWrites */, false /* enableMsgCount */)
+	pebbleLog.SetChannel(log.ChannelStorage)
 	return pebbleLog
 }
 
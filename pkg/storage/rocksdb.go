@@ -89,6 +89,7 @@ var rocksdbLogger *log.SecondaryLogger
 func InitRocksDBLogger(ctx context.Context) *log.SecondaryLogger {
 	rocksdbLogger = log.NewSecondaryLogger(ctx, nil, "rocksdb",
 		true /* enableGC */, false /* forceSyncWrites */, false /* enableMsgCount */)
+	rocksdbLogger.SetChannel(log.ChannelStorage)
 	return rocksdbLogger
 }
 
@@ -0,0 +1,160 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Channel is a logical logging channel. The channels group the log entries
+// by topic and audience, independently of the files they are written to:
+// the logging configuration (see Config) maps each channel to its sinks.
+type Channel int32
+
+const (
+	// ChannelDev is the channel of the entries logged with Infof, Warningf,
+	// etc. It is used during development and for the messages that don't
+	// belong to any of the other channels.
+	ChannelDev Channel = iota
+	// ChannelOps is the channel of the events of the cluster which concern
+	// its operators: node starts and stops, cluster setting changes, zone
+	// config changes, etc.
+	ChannelOps
+	// ChannelHealth is the channel of the reports of the health of the
+	// cluster: liveness heartbeats, resource usage, etc.
+	ChannelHealth
+	// ChannelStorage is the channel of the events of the storage engines.
+	ChannelStorage
+	// ChannelSessions is the channel of the connections and authentications
+	// of the SQL clients.
+	ChannelSessions
+	// ChannelSQLSchema is the channel of the changes of the SQL schema.
+	ChannelSQLSchema
+	// ChannelUserAdmin is the channel of the changes of the users and roles.
+	ChannelUserAdmin
+	// ChannelPrivileges is the channel of the changes of the privileges.
+	ChannelPrivileges
+	// ChannelSensitiveAccess is the channel of the accesses to the tables
+	// audited with ALTER TABLE ... EXPERIMENTAL_AUDIT.
+	ChannelSensitiveAccess
+	// ChannelSQLExec is the channel of the SQL statements executed by the
+	// clients (see the sql.trace.log_statement_execute cluster setting).
+	ChannelSQLExec
+	// ChannelSQLPerf is the channel of the reports of the performance of SQL
+	// statements, e.g. the slow query log.
+	ChannelSQLPerf
+
+	numChannels = iota
+)
+
+var channelNames = [numChannels]string{
+	ChannelDev:             "DEV",
+	ChannelOps:             "OPS",
+	ChannelHealth:          "HEALTH",
+	ChannelStorage:         "STORAGE",
+	ChannelSessions:        "SESSIONS",
+	ChannelSQLSchema:       "SQL_SCHEMA",
+	ChannelUserAdmin:       "USER_ADMIN",
+	ChannelPrivileges:      "PRIVILEGES",
+	ChannelSensitiveAccess: "SENSITIVE_ACCESS",
+	ChannelSQLExec:         "SQL_EXEC",
+	ChannelSQLPerf:         "SQL_PERF",
+}
+
+// String implements the fmt.Stringer interface.
+func (ch Channel) String() string {
+	if ch < 0 || ch >= numChannels {
+		return fmt.Sprintf("CHANNEL_%d", int32(ch))
+	}
+	return channelNames[ch]
+}
+
+// ChannelByName returns the channel with the given name (e.g. OPS).
+func ChannelByName(name string) (Channel, bool) {
+	for ch, chName := range channelNames {
+		if strings.EqualFold(name, chName) {
+			return Channel(ch), true
+		}
+	}
+	return 0, false
+}
+
+// ChannelLogger logs to a channel other than the DEV channel.
+type ChannelLogger struct {
+	ch Channel
+}
+
+// Loggers of the channels other than DEV, e.g.:
+//
+//   log.Ops.Infof(ctx, "node %d decommissioned", nodeID)
+var (
+	Ops             = ChannelLogger{ch: ChannelOps}
+	Health          = ChannelLogger{ch: ChannelHealth}
+	Storage         = ChannelLogger{ch: ChannelStorage}
+	Sessions        = ChannelLogger{ch: ChannelSessions}
+	SQLSchema       = ChannelLogger{ch: ChannelSQLSchema}
+	UserAdmin       = ChannelLogger{ch: ChannelUserAdmin}
+	Privileges      = ChannelLogger{ch: ChannelPrivileges}
+	SensitiveAccess = ChannelLogger{ch: ChannelSensitiveAccess}
+	SQLExec         = ChannelLogger{ch: ChannelSQLExec}
+	SQLPerf         = ChannelLogger{ch: ChannelSQLPerf}
+)
+
+// Channel returns the channel of the logger.
+func (c ChannelLogger) Channel() Channel {
+	return c.ch
+}
+
+// Infof logs to the channel at the INFO severity.
+// It extracts log tags from the context and logs them along with the given
+// message. Arguments are handled in the manner of fmt.Printf; a newline is
+// appended.
+func (c ChannelLogger) Infof(ctx context.Context, format string, args ...interface{}) {
+	addStructuredOnChannel(ctx, c.ch, Severity_INFO, 1, format, args, nil /* payload */)
+}
+
+// Warningf logs to the channel at the WARNING severity.
+// It extracts log tags from the context and logs them along with the given
+// message. Arguments are handled in the manner of fmt.Printf; a newline is
+// appended.
+func (c ChannelLogger) Warningf(ctx context.Context, format string, args ...interface{}) {
+	addStructuredOnChannel(ctx, c.ch, Severity_WARNING, 1, format, args, nil /* payload */)
+}
+
+// Errorf logs to the channel at the ERROR severity.
+// It extracts log tags from the context and logs them along with the given
+// message. Arguments are handled in the manner of fmt.Printf; a newline is
+// appended.
+func (c ChannelLogger) Errorf(ctx context.Context, format string, args ...interface{}) {
+	addStructuredOnChannel(ctx, c.ch, Severity_ERROR, 1, format, args, nil /* payload */)
+}
+
+// Structuredf logs to the channel at the given severity, attaching the given
+// payload to the entry. The payload is encoded as JSON. It is part of the
+// entries of the json format; the entries of the crdb-v1 format only
+// contain the message.
+func (c ChannelLogger) Structuredf(
+	ctx context.Context, sev Severity, payload interface{}, format string, args ...interface{},
+) {
+	var payloadJSON []byte
+	if payload != nil {
+		var err error
+		payloadJSON, err = json.Marshal(payload)
+		if err != nil {
+			// Keep the entry, but report the problem in its place.
+			payloadJSON, _ = json.Marshal(fmt.Sprintf("unable to encode payload: %v", err))
+		}
+	}
+	addStructuredOnChannel(ctx, c.ch, sev, 1, format, args, payloadJSON)
+}
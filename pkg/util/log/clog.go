@@ -40,6 +40,9 @@ type loggingT struct {
 	// Level flag for output to stderr. Handled atomically.
 	stderrThreshold Severity
 
+	// Format of the output to stderr. Handled atomically.
+	stderrFormat logFormat
+
 	// pool for entry formatting buffers.
 	bufPool sync.Pool
 
@@ -85,6 +88,9 @@ type loggerT struct {
 	// Level flag for output to files.
 	fileThreshold Severity
 
+	// Format of the output to files. Handled atomically.
+	format logFormat
+
 	// noStderrRedirect, when set, disables redirecting this logger's
 	// log entries to stderr (even when the shared stderr threshold is
 	// matched).
@@ -148,8 +154,9 @@ func SetClusterID(clusterID string) {
 	// new log files, even on the first log file. This ensures that grep
 	// will always find it.
 	file, line, _ := caller.Lookup(1)
-	mainLog.outputLogEntry(Severity_INFO, file, line,
-		fmt.Sprintf("[config] clusterID: %s", clusterID))
+	mainLog.outputLogEntry(makeLogEntry(ChannelOps, Severity_INFO, timeutil.Now().UnixNano(),
+		file, line, nil /* tags */, 0 /* tagsLen */, fmt.Sprintf("[config] clusterID: %s", clusterID),
+		nil /* payload */))
 
 	// Perform the change proper.
 	logging.mu.Lock()
@@ -184,18 +191,15 @@ func (l *loggerT) writeToFile(data []byte) error {
 	return nil
 }
 
-// outputLogEntry marshals a log entry proto into bytes, and writes
+// outputLogEntry marshals a log entry into bytes, and writes
 // the data to the log files. If a trace location is set, stack traces
 // are added to the entry before marshaling.
-func (l *loggerT) outputLogEntry(s Severity, file string, line int, msg string) {
-	// Set additional details in log entry.
-	now := timeutil.Now()
-	entry := MakeEntry(s, now.UnixNano(), file, line, msg)
-
+func (l *loggerT) outputLogEntry(entry logEntry) {
 	if f, ok := logging.interceptor.Load().(InterceptorFn); ok && f != nil {
-		f(entry)
+		f(entry.Entry)
 		return
 	}
+	s := entry.Severity
 
 	// TODO(tschottdorf): this is a pretty horrible critical section.
 	l.mu.Lock()
@@ -266,7 +270,7 @@ func (l *loggerT) outputLogEntry(s Severity, file string, line int, msg string)
 			return
 		}
 
-		buf := logging.processForFile(entry, stacks)
+		buf := logging.processForFile(l, entry, stacks)
 		data := buf.Bytes()
 
 		if err := l.writeToFile(data); err != nil {
@@ -327,7 +331,7 @@ func (l *loggerT) printPanicToFile(r interface{}) {
 	}
 }
 
-func (l *loggerT) outputToStderr(entry Entry, stacks []byte) {
+func (l *loggerT) outputToStderr(entry logEntry, stacks []byte) {
 	buf := logging.processForStderr(entry, stacks)
 	_, err := OrigStderr.Write(buf.Bytes())
 	putBuffer(buf)
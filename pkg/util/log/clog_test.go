@@ -138,7 +138,7 @@ func TestStandardLog(t *testing.T) {
 // Verify that a log can be fetched in JSON format.
 func TestEntryDecoder(t *testing.T) {
 	formatEntry := func(s Severity, now time.Time, gid int, file string, line int, msg string) string {
-		buf := logging.formatHeader(s, now, gid, file, line, nil)
+		buf := logging.formatHeader(s, now, gid, file, line, false /* redactable */, nil)
 		defer putBuffer(buf)
 		buf.WriteString(msg)
 		buf.WriteString("\n")
@@ -678,7 +678,7 @@ func TestExitOnFullDisk(t *testing.T) {
 
 func BenchmarkHeader(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		buf := logging.formatHeader(Severity_INFO, timeutil.Now(), 200, "file.go", 100, nil)
+		buf := logging.formatHeader(Severity_INFO, timeutil.Now(), 200, "file.go", 100, false /* redactable */, nil)
 		putBuffer(buf)
 	}
 }
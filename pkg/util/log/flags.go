@@ -19,7 +19,7 @@ import (
 func init() {
 	logflags.InitFlags(
 		&mainLog.noStderrRedirect,
-		&mainLog.logDir, &showLogs, &noColor,
+		&mainLog.logDir, &showLogs, &noColor, &redactableLogs,
 		&logging.vmoduleConfig.mu.vmodule,
 		&LogFileMaxSize, &LogFilesCombinedMaxSize,
 	)
@@ -0,0 +1,247 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// The json format writes each entry as a JSON object on a single line, with
+// the following fields:
+//
+//   timestamp  The time of the entry, in RFC 3339 format (UTC)
+//   severity   The severity of the entry (e.g. INFO)
+//   channel    The channel of the entry (e.g. OPS)
+//   goroutine  The goroutine id (omitted if zero for use by tests)
+//   file       The file name
+//   line       The line number
+//   tags       The log tags of the entry, e.g. {"n":"1","s":"2"} (if any)
+//   redactable Whether the message and tags contain redaction markers
+//              (omitted if false)
+//   message    The user-supplied message, without the log tags
+//   payload    The payload of structured entries (if any)
+//   stacks     The stack traces of fatal entries (if any)
+
+// jsonTimeFormat is the format of the timestamp of the json format.
+const jsonTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// maxJSONEntrySize is the size of the largest entry in the json format that
+// can be decoded by EntryDecoder.
+const maxJSONEntrySize = 16 << 20
+
+// formatJSONEntry formats a logEntry in the json format into a newly
+// allocated *buffer. The caller is responsible for calling putBuffer()
+// afterwards.
+func formatJSONEntry(entry logEntry, stacks []byte) *buffer {
+	buf := getBuffer()
+	s := entry.Severity
+	if s > Severity_FATAL || s <= Severity_UNKNOWN {
+		s = Severity_INFO // for safety.
+	}
+
+	buf.WriteString(`{"timestamp":"`)
+	buf.WriteString(timeutil.Unix(0, entry.Time).UTC().Format(jsonTimeFormat))
+	buf.WriteString(`","severity":"`)
+	buf.WriteString(s.String())
+	buf.WriteString(`","channel":"`)
+	buf.WriteString(entry.ch.String())
+	buf.WriteByte('"')
+	if entry.Goroutine > 0 {
+		buf.WriteString(`,"goroutine":`)
+		buf.WriteString(strconv.FormatInt(entry.Goroutine, 10))
+	}
+	buf.WriteString(`,"file":`)
+	writeJSONString(buf, entry.File)
+	buf.WriteString(`,"line":`)
+	buf.WriteString(strconv.FormatInt(entry.Line, 10))
+
+	msg := entry.Message
+	if entry.tags != nil && entry.tagsLen <= len(msg) {
+		msg = msg[entry.tagsLen:]
+		if tags := entry.tags.Get(); len(tags) > 0 {
+			buf.WriteString(`,"tags":{`)
+			for i := range tags {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				writeJSONString(buf, tags[i].Key())
+				buf.WriteByte(':')
+				if v := tags[i].Value(); entry.Redactable && v != nil && !isSafeValue(v) {
+					var sb strings.Builder
+					writeRedactable(&sb, tags[i].ValueStr())
+					writeJSONString(buf, sb.String())
+				} else {
+					writeJSONString(buf, tags[i].ValueStr())
+				}
+			}
+			buf.WriteByte('}')
+		}
+	}
+	if entry.Redactable {
+		buf.WriteString(`,"redactable":true`)
+	}
+	buf.WriteString(`,"message":`)
+	writeJSONString(buf, strings.TrimSuffix(msg, "\n"))
+	if len(entry.payload) > 0 {
+		buf.WriteString(`,"payload":`)
+		buf.Write(entry.payload)
+	}
+	if len(stacks) > 0 {
+		buf.WriteString(`,"stacks":`)
+		writeJSONString(buf, string(stacks))
+	}
+	buf.WriteString("}\n")
+	return buf
+}
+
+const hexDigits = "0123456789abcdef"
+
+// writeJSONString writes s as a JSON string. Invalid UTF-8 sequences are
+// replaced by U+FFFD.
+func writeJSONString(buf *buffer, s string) {
+	buf.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' {
+				i++
+				continue
+			}
+			buf.WriteString(s[start:i])
+			switch b {
+			case '"', '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(b)
+			case '\n':
+				buf.WriteString(`\n`)
+			case '\r':
+				buf.WriteString(`\r`)
+			case '\t':
+				buf.WriteString(`\t`)
+			default:
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[b>>4])
+				buf.WriteByte(hexDigits[b&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf.WriteString(s[start:i])
+			buf.WriteString(`\ufffd`)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf.WriteString(s[start:])
+	buf.WriteByte('"')
+}
+
+// jsonEntry is used to decode the entries of the json format.
+type jsonEntry struct {
+	Timestamp  string          `json:"timestamp"`
+	Severity   string          `json:"severity"`
+	Goroutine  int64           `json:"goroutine"`
+	File       string          `json:"file"`
+	Line       int64           `json:"line"`
+	Tags       json.RawMessage `json:"tags"`
+	Redactable bool            `json:"redactable"`
+	Message    string          `json:"message"`
+}
+
+// decodeJSONEntry decodes an entry of the json format into an Entry. As in
+// the crdb-v1 format, the message of the Entry starts with the log tags, in
+// their original order. Returns false if b is not an entry.
+func decodeJSONEntry(b []byte, entry *Entry) bool {
+	var je jsonEntry
+	if err := json.Unmarshal(b, &je); err != nil {
+		return false
+	}
+	t, err := time.Parse(jsonTimeFormat, je.Timestamp)
+	if err != nil {
+		return false
+	}
+	sev, ok := SeverityByName(je.Severity)
+	if !ok {
+		return false
+	}
+	*entry = Entry{
+		Severity:   sev,
+		Time:       t.UnixNano(),
+		Goroutine:  je.Goroutine,
+		File:       je.File,
+		Line:       je.Line,
+		Message:    je.Message,
+		Redactable: je.Redactable,
+	}
+	if len(je.Tags) > 0 {
+		tags, ok := decodeJSONTags(je.Tags)
+		if !ok {
+			return false
+		}
+		if tags != "" {
+			entry.Message = "[" + tags + "] " + je.Message
+		}
+	}
+	return true
+}
+
+// decodeJSONTags decodes the tags of an entry of the json format into their
+// crdb-v1 representation (see logtags.Buffer.FormatToString).
+func decodeJSONTags(b []byte) (string, bool) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return "", false
+	}
+	var sb strings.Builder
+	for dec.More() {
+		k, ok := nextJSONString(dec)
+		if !ok {
+			return "", false
+		}
+		v, ok := nextJSONString(dec)
+		if !ok {
+			return "", false
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(k)
+		if v != "" {
+			if len(k) > 1 {
+				sb.WriteByte('=')
+			}
+			sb.WriteString(v)
+		}
+	}
+	return sb.String(), true
+}
+
+// nextJSONString returns the next token of dec, if it is a string.
+func nextJSONString(dec *json.Decoder) (string, bool) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", false
+	}
+	s, ok := tok.(string)
+	return s, ok
+}
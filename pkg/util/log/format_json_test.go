@@ -0,0 +1,110 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/logtags"
+	"github.com/kr/pretty"
+)
+
+func TestFormatJSONEntry(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 30, 15, 123456789, time.UTC)
+	ctx := logtags.AddTag(context.Background(), "n", 1)
+	ctx = logtags.AddTag(ctx, "client", "127.0.0.1:1234")
+	ctx = logtags.AddTag(ctx, "x", nil)
+
+	testCases := []struct {
+		entry    logEntry
+		stacks   []byte
+		expected string
+		decoded  Entry
+	}{
+		{
+			entry: func() logEntry {
+				msg, tagsLen := makeMessage(ctx, "hello %q\n\tworld \xff", []interface{}{"you"},
+					false /* redactable */)
+				e := makeLogEntry(ChannelOps, Severity_WARNING, now.UnixNano(), "foo.go", 12,
+					logtags.FromContext(ctx), tagsLen, msg, []byte(`{"a":1}`))
+				// The goroutine is omitted if zero.
+				e.Goroutine = 0
+				return e
+			}(),
+			expected: `{"timestamp":"2020-06-01T12:30:15.123456789Z","severity":"WARNING",` +
+				`"channel":"OPS","file":"foo.go","line":12,` +
+				`"tags":{"n":"1","client":"127.0.0.1:1234","x":""},` +
+				`"message":"hello \"you\"\n\tworld \ufffd","payload":{"a":1}}` + "\n",
+			decoded: Entry{
+				Severity: Severity_WARNING,
+				Time:     now.UnixNano(),
+				File:     "foo.go",
+				Line:     12,
+				Message:  "[n1,client=127.0.0.1:1234,x] hello \"you\"\n\tworld \ufffd",
+			},
+		},
+		{
+			entry: func() logEntry {
+				e := makeLogEntry(ChannelDev, Severity_FATAL, now.UnixNano(), "bar.go", 34,
+					nil /* tags */, 0 /* tagsLen */, "boom", nil /* payload */)
+				e.Goroutine = 7
+				return e
+			}(),
+			stacks: []byte("goroutine 7:\nmain()\n"),
+			expected: `{"timestamp":"2020-06-01T12:30:15.123456789Z","severity":"FATAL",` +
+				`"channel":"DEV","goroutine":7,"file":"bar.go","line":34,` +
+				`"message":"boom","stacks":"goroutine 7:\nmain()\n"}` + "\n",
+			decoded: Entry{
+				Severity:  Severity_FATAL,
+				Time:      now.UnixNano(),
+				Goroutine: 7,
+				File:      "bar.go",
+				Line:      34,
+				Message:   "boom",
+			},
+		},
+	}
+
+	var contents strings.Builder
+	var expected []Entry
+	for _, tc := range testCases {
+		buf := formatJSONEntry(tc.entry, tc.stacks)
+		if actual := buf.String(); actual != tc.expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, actual)
+		}
+		contents.Write(buf.Bytes())
+		putBuffer(buf)
+		expected = append(expected, tc.decoded)
+	}
+
+	// The entries can be read back with an EntryDecoder; lines which are not
+	// entries are skipped.
+	contents.WriteString("not an entry\n")
+	decoder := NewEntryDecoder(strings.NewReader(contents.String()))
+	var entries []Entry
+	for {
+		var entry Entry
+		if err := decoder.Decode(&entry); err != nil {
+			if err == io.EOF {
+				break
+			}
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	if diff := pretty.Diff(expected, entries); len(diff) > 0 {
+		t.Errorf("unexpected entries:\n%s", strings.Join(diff, "\n"))
+	}
+}
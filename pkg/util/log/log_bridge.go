@@ -15,6 +15,8 @@ import (
 	"fmt"
 	stdLog "log"
 	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
 // NewStdLogger creates a *stdLog.Logger that forwards messages to the
@@ -71,6 +73,7 @@ func (lb logBridge) Write(b []byte) (n int, err error) {
 			line = 1
 		}
 	}
-	mainLog.outputLogEntry(Severity(lb), file, line, text)
+	outputToChannel(makeLogEntry(ChannelDev, Severity(lb), timeutil.Now().UnixNano(),
+		file, line, nil /* tags */, 0 /* tagsLen */, text, nil /* payload */))
 	return len(b), nil
 }
@@ -0,0 +1,485 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Config is the logging configuration, usually loaded from the file
+// specified with --log-config-file. It maps the logging channels to sinks,
+// e.g.:
+//
+//   format: json
+//   sinks:
+//   - type: file
+//     channels: [OPS, HEALTH]
+//     file-prefix: ops
+//   - type: file
+//     channels: [SENSITIVE_ACCESS]
+//     sync-writes: true
+//   - type: network
+//     channels: all
+//     threshold: WARNING
+//     address: 127.0.0.1:5170
+//
+// The entries of the channels which are not part of any file sink are
+// written to the main log files. If there are stderr sinks, they replace the
+// output to stderr configured with --logtostderr.
+type Config struct {
+	// Format is the format of the main log files (crdb-v1 or json).
+	Format string `yaml:"format"`
+	// Sinks are the sinks of the channels.
+	Sinks []SinkConfig `yaml:"sinks"`
+}
+
+// SinkConfig is the configuration of a sink.
+type SinkConfig struct {
+	// Type is the type of the sink: file, stderr or network.
+	Type string `yaml:"type"`
+	// Channels are the names of the channels written to the sink, or "all".
+	// For file sinks, "all" means all the channels except DEV, which is
+	// always written to the main log files.
+	Channels channelList `yaml:"channels"`
+	// Format is the format of the entries (crdb-v1 or json). The default is
+	// the format of the main log files for file sinks, and json for network
+	// sinks.
+	Format string `yaml:"format"`
+	// Threshold is the minimum severity of the entries written to the sink.
+	// The default is INFO.
+	Threshold string `yaml:"threshold"`
+
+	// Dir is the directory of the files of a file sink. The default is the
+	// directory of the main log files.
+	Dir string `yaml:"dir"`
+	// FilePrefix is the prefix of the files of a file sink, after
+	// "cockroach-". The default is the name of its first channel.
+	FilePrefix string `yaml:"file-prefix"`
+	// SyncWrites, for a file sink, causes every entry to be synced to disk.
+	SyncWrites bool `yaml:"sync-writes"`
+
+	// Network is the network of a network sink: tcp (the default) or udp.
+	Network string `yaml:"network"`
+	// Address is the address of a network sink.
+	Address string `yaml:"address"`
+}
+
+// channelList is a list of channel names. In the configuration file, it can
+// be a single name or a list of names.
+type channelList []string
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (l *channelList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err == nil {
+		*l = strings.Split(s, ",")
+		for i := range *l {
+			(*l)[i] = strings.TrimSpace((*l)[i])
+		}
+		return nil
+	}
+	var names []string
+	if err := unmarshal(&names); err != nil {
+		return err
+	}
+	*l = names
+	return nil
+}
+
+// sink types.
+const (
+	fileSinkType    = "file"
+	stderrSinkType  = "stderr"
+	networkSinkType = "network"
+)
+
+// ParseConfig parses and validates a logging configuration in the YAML
+// format.
+func ParseConfig(s string) (Config, error) {
+	var cfg Config
+	if err := yaml.UnmarshalStrict([]byte(s), &cfg); err != nil {
+		return Config{}, errors.Wrap(err, "invalid logging configuration")
+	}
+	if err := cfg.validate(); err != nil {
+		return Config{}, errors.Wrap(err, "invalid logging configuration")
+	}
+	return cfg, nil
+}
+
+// validate checks the configuration and fills in the default values.
+func (cfg *Config) validate() error {
+	if cfg.Format == "" {
+		cfg.Format = "crdb-v1"
+	}
+	if _, ok := logFormatNames[cfg.Format]; !ok {
+		return errors.Newf("unknown format: %q", cfg.Format)
+	}
+	var fileSinkOf [numChannels]int
+	for i := range cfg.Sinks {
+		s := &cfg.Sinks[i]
+		switch s.Type {
+		case fileSinkType:
+			if s.Format == "" {
+				s.Format = cfg.Format
+			}
+		case stderrSinkType:
+			if s.Format == "" {
+				s.Format = "crdb-v1"
+			}
+		case networkSinkType:
+			if s.Format == "" {
+				s.Format = "json"
+			}
+			if s.Network == "" {
+				s.Network = "tcp"
+			}
+			if s.Network != "tcp" && s.Network != "udp" {
+				return errors.Newf("sink %d: unknown network: %q", i+1, s.Network)
+			}
+			if s.Address == "" {
+				return errors.Newf("sink %d: the address of a network sink is required", i+1)
+			}
+		default:
+			return errors.Newf("sink %d: unknown sink type: %q", i+1, s.Type)
+		}
+		if _, ok := logFormatNames[s.Format]; !ok {
+			return errors.Newf("sink %d: unknown format: %q", i+1, s.Format)
+		}
+		if s.Threshold == "" {
+			s.Threshold = Severity_INFO.String()
+		}
+		if _, ok := SeverityByName(s.Threshold); !ok {
+			return errors.Newf("sink %d: unknown severity: %q", i+1, s.Threshold)
+		}
+		if s.Type != fileSinkType && (s.Dir != "" || s.FilePrefix != "" || s.SyncWrites) {
+			return errors.Newf("sink %d: dir, file-prefix and sync-writes only apply to file sinks", i+1)
+		}
+		if s.Type != networkSinkType && (s.Network != "" || s.Address != "") {
+			return errors.Newf("sink %d: network and address only apply to network sinks", i+1)
+		}
+
+		channels, err := s.channels()
+		if err != nil {
+			return errors.Wrapf(err, "sink %d", i+1)
+		}
+		if s.Type == fileSinkType {
+			for _, ch := range channels {
+				if ch == ChannelDev {
+					return errors.Newf("sink %d: the DEV channel is always written to the main log files", i+1)
+				}
+				if j := fileSinkOf[ch]; j != 0 {
+					return errors.Newf("sink %d: channel %s is already written to the files of sink %d", i+1, ch, j)
+				}
+				fileSinkOf[ch] = i + 1
+			}
+			if s.FilePrefix == "" {
+				s.FilePrefix = strings.Replace(strings.ToLower(channels[0].String()), "_", "-", -1)
+			}
+		}
+	}
+	return nil
+}
+
+// channels returns the channels of the sink.
+func (s *SinkConfig) channels() ([]Channel, error) {
+	if len(s.Channels) == 0 {
+		return nil, errors.New("no channels")
+	}
+	var res []Channel
+	for _, name := range s.Channels {
+		if strings.EqualFold(name, "all") {
+			res = res[:0]
+			for ch := Channel(0); ch < numChannels; ch++ {
+				if ch == ChannelDev && s.Type == fileSinkType {
+					continue
+				}
+				res = append(res, ch)
+			}
+			return res, nil
+		}
+		ch, ok := ChannelByName(name)
+		if !ok {
+			return nil, errors.Newf("unknown channel: %q", name)
+		}
+		res = append(res, ch)
+	}
+	return res, nil
+}
+
+// extraSink is a sink to which the entries of a channel are copied, in
+// addition to the log files.
+type extraSink interface {
+	output(entry logEntry)
+	close()
+}
+
+// channelSinks are the sinks of a channel.
+type channelSinks struct {
+	// file is the logger of the file sink of the channel, or nil if the
+	// entries of the channel are written to the main log files.
+	file *loggerT
+	// extra are the stderr and network sinks of the channel.
+	extra []extraSink
+}
+
+// channelRouting contains the *[numChannels]channelSinks of the current
+// configuration, if any.
+var channelRouting atomic.Value
+
+// ApplyConfig sets up the sinks of the given configuration, which must have
+// been returned by ParseConfig. The returned function reverts to the
+// previous configuration and releases the resources of the sinks.
+func ApplyConfig(ctx context.Context, cfg Config) (cleanup func(), err error) {
+	var routes [numChannels]channelSinks
+	var fileLoggers []*SecondaryLogger
+	var extraSinks []extraSink
+	cleanupSinks := func() {
+		for _, l := range fileLoggers {
+			l.logger.lockAndFlushAndSync(true /*doSync*/)
+			l.Close()
+		}
+		for _, s := range extraSinks {
+			s.close()
+		}
+	}
+
+	for i := range cfg.Sinks {
+		s := &cfg.Sinks[i]
+		channels, err := s.channels()
+		if err != nil {
+			cleanupSinks()
+			return nil, err
+		}
+		format := logFormatNames[s.Format]
+		threshold, _ := SeverityByName(s.Threshold)
+		switch s.Type {
+		case fileSinkType:
+			var dir *DirName
+			if s.Dir != "" {
+				dir = &DirName{}
+				if err := dir.Set(s.Dir); err != nil {
+					cleanupSinks()
+					return nil, errors.Wrapf(err, "sink %d", i+1)
+				}
+			}
+			l := NewSecondaryLogger(ctx, dir, s.FilePrefix,
+				true /* enableGc */, s.SyncWrites, false /* enableMsgCount */)
+			l.logger.format.set(format)
+			l.logger.fileThreshold.set(threshold)
+			fileLoggers = append(fileLoggers, l)
+			for _, ch := range channels {
+				routes[ch].file = &l.logger
+			}
+			continue
+		case stderrSinkType:
+			extraSinks = append(extraSinks, &stderrSink{format: format, threshold: threshold})
+		case networkSinkType:
+			extraSinks = append(extraSinks, newNetworkSink(format, threshold, s.Network, s.Address))
+		}
+		for _, ch := range channels {
+			routes[ch].extra = append(routes[ch].extra, extraSinks[len(extraSinks)-1])
+		}
+	}
+
+	prevFormat := mainLog.format.get()
+	prevStderrThreshold := logging.stderrThreshold.get()
+	mainLog.format.set(logFormatNames[cfg.Format])
+	for i := range cfg.Sinks {
+		if cfg.Sinks[i].Type == stderrSinkType {
+			logging.stderrThreshold.set(Severity_NONE)
+			break
+		}
+	}
+	channelRouting.Store(&routes)
+
+	return func() {
+		channelRouting.Store((*[numChannels]channelSinks)(nil))
+		mainLog.format.set(prevFormat)
+		logging.stderrThreshold.set(prevStderrThreshold)
+		cleanupSinks()
+	}, nil
+}
+
+// getChannelSinks returns the sinks of the channel, or nil if there is no
+// logging configuration.
+func getChannelSinks(ch Channel) *channelSinks {
+	routes, _ := channelRouting.Load().(*[numChannels]channelSinks)
+	if routes == nil || ch < 0 || ch >= numChannels {
+		return nil
+	}
+	return &routes[ch]
+}
+
+// outputToChannel writes a log entry to the sinks of its channel. Fatal
+// entries are always written to the main log files, which take care of
+// terminating the process.
+func outputToChannel(entry logEntry) {
+	sinks := getChannelSinks(entry.ch)
+	if sinks == nil {
+		mainLog.outputLogEntry(entry)
+		return
+	}
+	// The extra sinks come first, so that they also see fatal entries.
+	outputToExtraSinks(sinks, entry)
+	if sinks.file != nil && entry.Severity != Severity_FATAL {
+		sinks.file.outputLogEntry(entry)
+		return
+	}
+	mainLog.outputLogEntry(entry)
+}
+
+// outputToExtraSinks copies a log entry to the stderr and network sinks of
+// its channel.
+func outputToExtraSinks(sinks *channelSinks, entry logEntry) {
+	if sinks == nil {
+		return
+	}
+	if f, ok := logging.interceptor.Load().(InterceptorFn); ok && f != nil {
+		// The entries are intercepted by the log files.
+		return
+	}
+	for _, s := range sinks.extra {
+		s.output(entry)
+	}
+}
+
+// stderrSink writes the entries of its channels to stderr.
+type stderrSink struct {
+	format    logFormat
+	threshold Severity
+}
+
+func (s *stderrSink) output(entry logEntry) {
+	if entry.Severity < s.threshold {
+		return
+	}
+	buf := logging.formatEntry(s.format, entry, nil /* stacks */, nil /* cp */)
+	_, _ = OrigStderr.Write(buf.Bytes())
+	putBuffer(buf)
+}
+
+func (s *stderrSink) close() {}
+
+// networkSink sends the entries of its channels to a network address, e.g.
+// a log collector. The entries are queued and sent by a background
+// goroutine, so that a slow or unreachable collector never blocks the
+// logging goroutines: the entries are dropped when the queue is full or the
+// address cannot be reached.
+type networkSink struct {
+	format    logFormat
+	threshold Severity
+	network   string
+	addr      string
+
+	// entries is the queue of the formatted entries to send.
+	entries chan []byte
+	// dropped is the number of entries dropped because the queue was full.
+	dropped int64
+	// stop is closed by close to stop the sending goroutine, which closes
+	// stopped when it is done.
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+const (
+	// networkSinkTimeout is the timeout of the connections and writes of
+	// the network sinks.
+	networkSinkTimeout = time.Second
+	// networkSinkRetryInterval is the time between the attempts to
+	// reconnect a network sink.
+	networkSinkRetryInterval = 5 * time.Second
+	// networkSinkQueueSize is the maximum number of entries waiting to be
+	// sent by a network sink.
+	networkSinkQueueSize = 1024
+)
+
+// newNetworkSink creates a network sink and starts its sending goroutine,
+// which is stopped by close.
+func newNetworkSink(format logFormat, threshold Severity, network, addr string) *networkSink {
+	s := &networkSink{
+		format:    format,
+		threshold: threshold,
+		network:   network,
+		addr:      addr,
+		entries:   make(chan []byte, networkSinkQueueSize),
+		stop:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	go s.send()
+	return s
+}
+
+func (s *networkSink) output(entry logEntry) {
+	if entry.Severity < s.threshold {
+		return
+	}
+	buf := logging.formatEntry(s.format, entry, nil /* stacks */, nil /* cp */)
+	b := append([]byte(nil), buf.Bytes()...)
+	putBuffer(buf)
+	select {
+	case s.entries <- b:
+	default:
+		atomic.AddInt64(&s.dropped, 1)
+	}
+}
+
+// send writes the queued entries to the address until the sink is closed.
+func (s *networkSink) send() {
+	defer close(s.stopped)
+	var conn net.Conn
+	defer func() {
+		if conn != nil {
+			_ = conn.Close()
+		}
+	}()
+	var nextDial time.Time
+	for {
+		var b []byte
+		select {
+		case b = <-s.entries:
+		case <-s.stop:
+			return
+		}
+		now := timeutil.Now()
+		if conn == nil {
+			if now.Before(nextDial) {
+				continue
+			}
+			var err error
+			conn, err = net.DialTimeout(s.network, s.addr, networkSinkTimeout)
+			if err != nil {
+				nextDial = now.Add(networkSinkRetryInterval)
+				continue
+			}
+		}
+		if err := conn.SetWriteDeadline(now.Add(networkSinkTimeout)); err == nil {
+			if _, err = conn.Write(b); err == nil {
+				continue
+			}
+		}
+		// Drop the entry and reconnect for the next one.
+		_ = conn.Close()
+		conn = nil
+	}
+}
+
+func (s *networkSink) close() {
+	close(s.stop)
+	<-s.stopped
+}
@@ -0,0 +1,295 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/logtags"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		config string
+		err    string
+	}{
+		{``, ``},
+		{`format: json`, ``},
+		{`
+sinks:
+- type: file
+  channels: [OPS, HEALTH]
+- type: file
+  channels: SENSITIVE_ACCESS
+  sync-writes: true
+- type: stderr
+  channels: all
+  threshold: ERROR
+- type: network
+  channels: OPS, sql_schema
+  address: 127.0.0.1:5170
+`, ``},
+		{`format: xml`, `unknown format: "xml"`},
+		{`foo: bar`, `field foo not found`},
+		{`
+sinks:
+- type: syslog
+  channels: all
+`, `sink 1: unknown sink type: "syslog"`},
+		{`
+sinks:
+- type: file
+`, `sink 1: no channels`},
+		{`
+sinks:
+- type: stderr
+  channels: [OPS, FOO]
+`, `sink 1: unknown channel: "FOO"`},
+		{`
+sinks:
+- type: file
+  channels: [DEV]
+`, `sink 1: the DEV channel is always written to the main log files`},
+		{`
+sinks:
+- type: file
+  channels: [OPS]
+- type: file
+  channels: all
+`, `sink 2: channel OPS is already written to the files of sink 1`},
+		{`
+sinks:
+- type: network
+  channels: all
+`, `sink 1: the address of a network sink is required`},
+		{`
+sinks:
+- type: network
+  channels: all
+  network: sctp
+  address: 127.0.0.1:5170
+`, `sink 1: unknown network: "sctp"`},
+		{`
+sinks:
+- type: stderr
+  channels: all
+  dir: /tmp
+`, `sink 1: dir, file-prefix and sync-writes only apply to file sinks`},
+		{`
+sinks:
+- type: stderr
+  channels: all
+  threshold: LOUD
+`, `sink 1: unknown severity: "LOUD"`},
+	}
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			_, err := ParseConfig(tc.config)
+			if !testutils.IsError(err, tc.err) {
+				t.Fatalf("%s: expected error %q, got %v", tc.config, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestApplyConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s := ScopeWithoutShowLogs(t)
+	defer s.Close(t)
+	setFlags()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The network sink sends the entries to this listener.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	received := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			received <- line
+		}
+	}()
+
+	cfg, err := ParseConfig(fmt.Sprintf(`
+sinks:
+- type: file
+  channels: [OPS]
+  format: json
+  file-prefix: test-ops
+- type: network
+  channels: [HEALTH]
+  threshold: WARNING
+  address: %s
+`, ln.Addr()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup, err := ApplyConfig(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	ctx = logtags.AddTag(ctx, "n", 1)
+	Infof(ctx, "dev message")
+	Ops.Structuredf(ctx, Severity_INFO, struct{ Foo int }{Foo: 1}, "ops message")
+	Health.Infof(ctx, "health info")
+	Health.Warningf(ctx, "health warning")
+	Flush()
+
+	// The OPS channel goes to its own files, in the json format.
+	contents, err := readFilesWithPrefix(filepath.Join(s.logDir, removePeriods(program)+"-test-ops"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(contents, `"channel":"OPS"`) ||
+		!strings.Contains(contents, `"tags":{"n":"1"},"message":"ops message","payload":{"Foo":1}`) {
+		t.Errorf("unexpected OPS log:\n%s", contents)
+	}
+	if strings.Contains(contents, "dev message") || strings.Contains(contents, "health") {
+		t.Errorf("other channels spilled into the OPS log:\n%s", contents)
+	}
+
+	// The other channels go to the main log files.
+	contents, err = readFilesWithPrefix(filepath.Join(s.logDir, removePeriods(program)+"."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(contents, "dev message") || !strings.Contains(contents, "health info") {
+		t.Errorf("unexpected main log:\n%s", contents)
+	}
+	if strings.Contains(contents, "ops message") {
+		t.Errorf("OPS log spilled into the main log:\n%s", contents)
+	}
+
+	// Only the warning is sent to the network sink.
+	var line string
+	select {
+	case line = <-received:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the network sink")
+	}
+	if !strings.Contains(line, `"severity":"WARNING","channel":"HEALTH"`) ||
+		!strings.Contains(line, `"message":"health warning"`) {
+		t.Errorf("unexpected entry: %s", line)
+	}
+	select {
+	case line := <-received:
+		t.Errorf("unexpected entry: %s", line)
+	default:
+	}
+}
+
+// TestNetworkSinkDoesNotBlock checks that a network sink whose collector
+// does not read the entries drops them instead of blocking the logging
+// goroutines.
+func TestNetworkSinkDoesNotBlock(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	// The collector accepts the connections but never reads from them.
+	conns := make(chan net.Conn, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns <- conn
+		}
+	}()
+	defer func() {
+		_ = ln.Close()
+		for {
+			select {
+			case conn := <-conns:
+				_ = conn.Close()
+			default:
+				return
+			}
+		}
+	}()
+
+	s := newNetworkSink(formatCrdbV1, Severity_INFO, "tcp", ln.Addr().String())
+	defer s.close()
+
+	// Without the queue, the writes would block for networkSinkTimeout each
+	// once the socket buffers are full.
+	msg := strings.Repeat("x", 8<<10)
+	start := timeutil.Now()
+	for i := 0; i < 8*networkSinkQueueSize; i++ {
+		s.output(makeLogEntry(ChannelHealth, Severity_WARNING, start.UnixNano(), "foo.go", 12,
+			nil /* tags */, 0 /* tagsLen */, msg, nil /* payload */))
+	}
+	if elapsed := timeutil.Since(start); elapsed > 10*networkSinkTimeout {
+		t.Errorf("logging to a blocked network sink took %s", elapsed)
+	}
+	if dropped := atomic.LoadInt64(&s.dropped); dropped == 0 {
+		t.Errorf("expected entries to be dropped")
+	}
+}
+
+// readFilesWithPrefix returns the contents of the files with the given
+// prefix.
+func readFilesWithPrefix(prefix string) (string, error) {
+	matches, err := filepath.Glob(prefix + "*")
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, m := range matches {
+		if fi, err := os.Lstat(m); err != nil || fi.Mode()&os.ModeSymlink != 0 {
+			continue
+		}
+		f, err := os.Open(m)
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(&sb, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return sb.String(), nil
+}
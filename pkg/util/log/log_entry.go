@@ -16,9 +16,11 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/logtags"
 	"github.com/cockroachdb/ttycolor"
 	"github.com/petermattis/goid"
 )
@@ -26,11 +28,83 @@ import (
 // the --no-color flag.
 var noColor bool
 
+// logEntry is a log entry with the details which are only part of the json
+// format.
+type logEntry struct {
+	Entry
+	// ch is the channel of the entry.
+	ch Channel
+	// tags are the log tags of the entry, if any. Entry.Message starts with
+	// their crdb-v1 representation, whose length is tagsLen.
+	tags    *logtags.Buffer
+	tagsLen int
+	// payload is the JSON payload of the entry, if any.
+	payload []byte
+}
+
+// makeLogEntry creates a logEntry for a message that starts with the
+// formatted tags (see formatTags).
+func makeLogEntry(
+	ch Channel,
+	s Severity,
+	t int64,
+	file string,
+	line int,
+	tags *logtags.Buffer,
+	tagsLen int,
+	msg string,
+	payload []byte,
+) logEntry {
+	return logEntry{
+		Entry:   MakeEntry(s, t, file, line, msg),
+		ch:      ch,
+		tags:    tags,
+		tagsLen: tagsLen,
+		payload: payload,
+	}
+}
+
+// logFormat is the format of the log entries written to a sink.
+type logFormat int32
+
+const (
+	// formatCrdbV1 is the traditional text format (see formatHeader).
+	formatCrdbV1 logFormat = iota
+	// formatJSON writes one JSON object per line (see formatJSONEntry).
+	formatJSON
+)
+
+var logFormatNames = map[string]logFormat{
+	"crdb-v1": formatCrdbV1,
+	"json":    formatJSON,
+}
+
+// get returns the value of the logFormat.
+func (f *logFormat) get() logFormat {
+	return logFormat(atomic.LoadInt32((*int32)(f)))
+}
+
+// set sets the value of the logFormat.
+func (f *logFormat) set(val logFormat) {
+	atomic.StoreInt32((*int32)(f), int32(val))
+}
+
+// formatEntry formats a logEntry in the given format, into a newly allocated
+// *buffer. The caller is responsible for calling putBuffer() afterwards.
+func (l *loggingT) formatEntry(
+	format logFormat, entry logEntry, stacks []byte, cp ttycolor.Profile,
+) *buffer {
+	if format == formatJSON {
+		return formatJSONEntry(entry, stacks)
+	}
+	return l.formatLogEntry(entry.Entry, stacks, cp)
+}
+
 // formatLogEntry formats an Entry into a newly allocated *buffer.
 // The caller is responsible for calling putBuffer() afterwards.
 func (l *loggingT) formatLogEntry(entry Entry, stacks []byte, cp ttycolor.Profile) *buffer {
 	buf := l.formatHeader(entry.Severity, timeutil.Unix(0, entry.Time),
-		int(entry.Goroutine), entry.File, int(entry.Line), cp)
+		int(entry.Goroutine), entry.File, int(entry.Line), entry.Redactable, cp)
 	_, _ = buf.WriteString(entry.Message)
 	if buf.Bytes()[buf.Len()-1] != '\n' {
 		_ = buf.WriteByte('\n')
@@ -47,7 +121,7 @@ func (l *loggingT) formatLogEntry(entry Entry, stacks []byte, cp ttycolor.Profil
 // for calling putBuffer() afterwards.
 //
 // Log lines have this form:
-// 	Lyymmdd hh:mm:ss.uuuuuu goid file:line msg...
+// 	Lyymmdd hh:mm:ss.uuuuuu goid file:line [⋮] msg...
 // where the fields are defined as follows:
 // 	L                A single character, representing the log level (eg 'I' for INFO)
 // 	yy               The year (zero padded; ie 2016 is '16')
@@ -57,9 +131,16 @@ func (l *loggingT) formatLogEntry(entry Entry, stacks []byte, cp ttycolor.Profil
 // 	goid             The goroutine id (omitted if zero for use by tests)
 // 	file             The file name
 // 	line             The line number
+// 	⋮                Present if the message is redactable (see redact.go)
 // 	msg              The user-supplied message
 func (l *loggingT) formatHeader(
-	s Severity, now time.Time, gid int, file string, line int, cp ttycolor.Profile,
+	s Severity,
+	now time.Time,
+	gid int,
+	file string,
+	line int,
+	redactable bool,
+	cp ttycolor.Profile,
 ) *buffer {
 	if noColor {
 		cp = nil
@@ -127,6 +208,9 @@ func (l *loggingT) formatHeader(
 	tmp[n] = ' '
 	n++
 	n += copy(tmp[n:], cp[ttycolor.Reset])
+	if redactable {
+		n += copy(tmp[n:], redactableIndicator)
+	}
 	tmp[n] = ' '
 	n++
 	buf.Write(tmp[:n])
@@ -134,13 +218,14 @@ func (l *loggingT) formatHeader(
 }
 
 // processForStderr formats a log entry for output to standard error.
-func (l *loggingT) processForStderr(entry Entry, stacks []byte) *buffer {
-	return l.formatLogEntry(entry, stacks, ttycolor.StderrProfile)
+func (l *loggingT) processForStderr(entry logEntry, stacks []byte) *buffer {
+	return l.formatEntry(l.stderrFormat.get(), entry, stacks, ttycolor.StderrProfile)
 }
 
-// processForFile formats a log entry for output to a file.
-func (l *loggingT) processForFile(entry Entry, stacks []byte) *buffer {
-	return l.formatLogEntry(entry, stacks, nil)
+// processForFile formats a log entry for output to the files of the given
+// logger.
+func (l *loggingT) processForFile(logger *loggerT, entry logEntry, stacks []byte) *buffer {
+	return l.formatEntry(logger.format.get(), entry, stacks, nil)
 }
 
 // MakeEntry creates an Entry.
@@ -176,12 +261,22 @@ type EntryDecoder struct {
 	re                 *regexp.Regexp
 	scanner            *bufio.Scanner
 	truncatedLastEntry bool
+	// json is set if the entries are in the json format.
+	json bool
 }
 
-// NewEntryDecoder creates a new instance of EntryDecoder.
+// NewEntryDecoder creates a new instance of EntryDecoder. The format of the
+// entries (crdb-v1 or json) is detected from the first byte of the input.
 func NewEntryDecoder(in io.Reader) *EntryDecoder {
-	d := &EntryDecoder{scanner: bufio.NewScanner(in), re: entryRE}
-	d.scanner.Split(d.split)
+	r := bufio.NewReader(in)
+	d := &EntryDecoder{scanner: bufio.NewScanner(r), re: entryRE}
+	if b, err := r.Peek(1); err == nil && b[0] == '{' {
+		// The json format has one entry per line.
+		d.json = true
+		d.scanner.Buffer(make([]byte, 0, 4096), maxJSONEntrySize)
+	} else {
+		d.scanner.Split(d.split)
+	}
 	return d
 }
 
@@ -199,6 +294,12 @@ func (d *EntryDecoder) Decode(entry *Entry) error {
 			return io.EOF
 		}
 		b := d.scanner.Bytes()
+		if d.json {
+			if !decodeJSONEntry(b, entry) {
+				continue
+			}
+			return nil
+		}
 		m := d.re.FindSubmatch(b)
 		if m == nil {
 			continue
@@ -222,7 +323,12 @@ func (d *EntryDecoder) Decode(entry *Entry) error {
 			return err
 		}
 		entry.Line = int64(line)
-		entry.Message = strings.TrimSpace(string(b[len(m[0]):]))
+		msg := strings.TrimSpace(string(b[len(m[0]):]))
+		entry.Redactable = strings.HasPrefix(msg, redactableIndicator)
+		if entry.Redactable {
+			msg = strings.TrimSpace(msg[len(redactableIndicator):])
+		}
+		entry.Message = msg
 		return nil
 	}
 }
@@ -67,6 +67,7 @@ const (
 	LogFileMaxSizeName            = "log-file-max-size"
 	LogFilesCombinedMaxSizeName   = "log-dir-max-size"
 	LogFileVerbosityThresholdName = "log-file-verbosity"
+	RedactableLogsName            = "redactable-logs"
 )
 
 // InitFlags creates logging flags which update the given variables. The passed mutex is
@@ -76,6 +77,7 @@ func InitFlags(
 	logDir flag.Value,
 	showLogs *bool,
 	nocolor *bool,
+	redactableLogs *bool,
 	vmodule flag.Value,
 	logFileMaxSize, logFilesCombinedMaxSize *int64,
 ) {
@@ -84,6 +86,7 @@ func InitFlags(
 	flag.Var(vmodule, VModuleName, "comma-separated list of pattern=N settings for file-filtered logging (significantly hurts performance)")
 	flag.Var(logDir, LogDirName, "if non-empty, write log files in this directory")
 	flag.BoolVar(showLogs, ShowLogsName, *showLogs, "print logs instead of saving them in files")
+	flag.BoolVar(redactableLogs, RedactableLogsName, *redactableLogs, "enclose the sensitive information of the log messages between redaction markers")
 	flag.Var(humanizeutil.NewBytesValue(logFileMaxSize), LogFileMaxSizeName, "maximum size of each log file")
 	flag.Var(humanizeutil.NewBytesValue(logFilesCombinedMaxSize), LogFilesCombinedMaxSizeName, "maximum combined size of all log files")
 }
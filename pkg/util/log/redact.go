@@ -0,0 +1,197 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/logtags"
+)
+
+// When --redactable-logs is set, which is the default for the servers started
+// with `cockroach start` and `cockroach demo`, the arguments of the log messages are
+// enclosed between redaction markers, unless they are known to be safe:
+//
+//   I200601 12:30:15.123456 1 server/node.go:12 ⋮ [n1] found key ‹/Table/53/1/"foo"›
+//
+// The text outside of the markers, i.e. the format strings of the calls to
+// the logging functions and the safe values, is operational information
+// which does not depend on the data of the users. The log entries are then
+// marked as redactable (with ⋮ after the file and line in the crdb-v1
+// format), and RedactEntry can strip the sensitive information from them,
+// e.g. in the output of `cockroach debug zip --redact-logs`.
+const (
+	// startRedactable and endRedactable enclose the sensitive information.
+	startRedactable = "‹"
+	endRedactable   = "›"
+	// escapeMark replaces the redaction markers which are part of the
+	// sensitive information, so that the markers remain balanced.
+	escapeMark = "?"
+	// redactedMarker replaces the sensitive information in redacted entries.
+	redactedMarker = startRedactable + "×" + endRedactable
+	// redactableIndicator marks the redactable entries in the crdb-v1
+	// format.
+	redactableIndicator = "⋮"
+)
+
+// redactableLogs is set by the --redactable-logs flag.
+var redactableLogs bool
+
+// SafeValue is implemented by the types whose values can be included
+// verbatim in redactable logs, e.g. node and range IDs. The method is only a
+// marker. The values of the other types are considered sensitive, except
+// for time.Duration and the values wrapped with Safe.
+type SafeValue interface {
+	SafeValue()
+}
+
+// isSafeValue returns true if the value can be included verbatim in
+// redactable logs.
+func isSafeValue(v interface{}) bool {
+	switch v.(type) {
+	case SafeValue, SafeType, time.Duration:
+		return true
+	}
+	return false
+}
+
+// escapeMarkers replaces the redaction markers in s by escapeMark.
+var escapeMarkers = strings.NewReplacer(
+	startRedactable, escapeMark,
+	endRedactable, escapeMark,
+)
+
+// unsafeArg wraps the arguments of the log messages which are not known to
+// be safe, so that they are formatted between redaction markers.
+type unsafeArg struct {
+	v interface{}
+}
+
+// Format implements the fmt.Formatter interface.
+func (a unsafeArg) Format(s fmt.State, verb rune) {
+	var format strings.Builder
+	format.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if s.Flag(int(flag)) {
+			format.WriteRune(flag)
+		}
+	}
+	if w, ok := s.Width(); ok {
+		fmt.Fprintf(&format, "%d", w)
+	}
+	if p, ok := s.Precision(); ok {
+		fmt.Fprintf(&format, ".%d", p)
+	}
+	format.WriteRune(verb)
+	writeRedactable(s, fmt.Sprintf(format.String(), a.v))
+}
+
+// writeRedactable writes the string between redaction markers.
+func writeRedactable(w io.Writer, s string) {
+	_, _ = io.WriteString(w, startRedactable)
+	_, _ = io.WriteString(w, escapeMarkers.Replace(s))
+	_, _ = io.WriteString(w, endRedactable)
+}
+
+// makeRedactableArgs wraps the arguments which are not known to be safe.
+func makeRedactableArgs(args []interface{}) []interface{} {
+	res := make([]interface{}, len(args))
+	for i, arg := range args {
+		if isSafeValue(arg) {
+			res[i] = arg
+		} else {
+			res[i] = unsafeArg{v: arg}
+		}
+	}
+	return res
+}
+
+// fprintRedactable is like fmt.Fprint, but the arguments which are not known
+// to be safe are written between redaction markers.
+func fprintRedactable(buf *strings.Builder, args []interface{}) {
+	for i, arg := range args {
+		// Like fmt.Fprint, add spaces between operands when neither is a
+		// string.
+		if i > 0 {
+			_, prevIsString := args[i-1].(string)
+			if _, isString := arg.(string); !isString && !prevIsString {
+				buf.WriteByte(' ')
+			}
+		}
+		if isSafeValue(arg) {
+			fmt.Fprint(buf, arg)
+		} else {
+			writeRedactable(buf, fmt.Sprint(arg))
+		}
+	}
+}
+
+// formatRedactableTags appends the tags to a strings.Builder, with their
+// values between redaction markers unless they are known to be safe.
+func formatRedactableTags(tags *logtags.Buffer, buf *strings.Builder) {
+	buf.WriteByte('[')
+	for i, t := range tags.Get() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(escapeMarkers.Replace(t.Key()))
+		if v := t.Value(); v != nil {
+			if len(t.Key()) > 1 {
+				buf.WriteByte('=')
+			}
+			if isSafeValue(v) {
+				buf.WriteString(escapeMarkers.Replace(t.ValueStr()))
+			} else {
+				writeRedactable(buf, t.ValueStr())
+			}
+		}
+	}
+	buf.WriteString("] ")
+}
+
+// RedactEntry returns the entry without the sensitive information of its
+// message. In redactable entries, the text between redaction markers is
+// replaced by ‹×›. The message of the other entries can't be trusted at
+// all and is replaced entirely.
+func RedactEntry(e Entry) Entry {
+	if !e.Redactable {
+		if e.Message != "" {
+			e.Message = redactedMarker
+		}
+		e.Redactable = true
+		return e
+	}
+	var buf strings.Builder
+	msg := e.Message
+	for {
+		start := strings.Index(msg, startRedactable)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(msg[start:], endRedactable)
+		if end < 0 {
+			// Unbalanced marker: the rest of the message is sensitive.
+			buf.WriteString(msg[:start])
+			buf.WriteString(redactedMarker)
+			msg = ""
+			break
+		}
+		buf.WriteString(msg[:start])
+		buf.WriteString(redactedMarker)
+		msg = msg[start+end+len(endRedactable):]
+	}
+	buf.WriteString(msg)
+	e.Message = buf.String()
+	return e
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/logtags"
+)
+
+// safeID is a test type which implements SafeValue.
+type safeID int
+
+func (safeID) SafeValue() {}
+
+func TestRedactableMessage(t *testing.T) {
+	ctx := logtags.AddTag(context.Background(), "n", safeID(1))
+	ctx = logtags.AddTag(ctx, "client", "127.0.0.1:1234")
+	ctx = logtags.AddTag(ctx, "x", nil)
+
+	testCases := []struct {
+		format   string
+		args     []interface{}
+		expected string
+	}{
+		{"hello", nil, "hello"},
+		{"hello ‹world›", nil, "hello ?world?"},
+		{"key %s in r%d", []interface{}{"foo", safeID(12)}, "key ‹foo› in r12"},
+		{"%q took %s", []interface{}{"a‹b›c", 2 * time.Second}, "‹\"a?b?c\"› took 2s"},
+		{"%5.1f%%, %+v", []interface{}{3.14159, struct{ A int }{A: 1}}, "‹  3.1›%, ‹{A:1}›"},
+		{"%s", []interface{}{Safe("safe")}, "safe"},
+		{"%s", []interface{}{"built at run time"}, "‹built at run time›"},
+		{"", []interface{}{"a", 1, 2, "b"}, "‹a›‹1› ‹2›‹b›"},
+		{"", []interface{}{safeID(3), "x"}, "3‹x›"},
+	}
+	for _, tc := range testCases {
+		msg, tagsLen := makeMessage(ctx, tc.format, tc.args, true /* redactable */)
+		const expectedTags = "[n1,client=‹127.0.0.1:1234›,x] "
+		if tags := msg[:tagsLen]; tags != expectedTags {
+			t.Errorf("%q: expected tags %q, got %q", tc.format, expectedTags, tags)
+		}
+		if actual := msg[tagsLen:]; actual != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.format, tc.expected, actual)
+		}
+	}
+}
+
+func TestRedactEntry(t *testing.T) {
+	testCases := []struct {
+		entry    Entry
+		expected string
+	}{
+		{Entry{Message: "hello", Redactable: true}, "hello"},
+		{Entry{Message: "[n1,client=‹1.2.3.4›] key ‹foo› in r12", Redactable: true},
+			"[n1,client=‹×›] key ‹×› in r12"},
+		{Entry{Message: "unbalanced ‹foo", Redactable: true}, "unbalanced ‹×›"},
+		{Entry{Message: "not redactable"}, "‹×›"},
+	}
+	for _, tc := range testCases {
+		e := RedactEntry(tc.entry)
+		if e.Message != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.entry.Message, tc.expected, e.Message)
+		}
+		if !e.Redactable {
+			t.Errorf("%q: expected a redactable entry", tc.entry.Message)
+		}
+	}
+}
+
+func TestRedactableEntryDecoder(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 30, 15, 123456000, time.UTC)
+	var contents strings.Builder
+	for _, e := range []Entry{
+		{Severity: Severity_INFO, Time: now.UnixNano(), File: "foo.go", Line: 12,
+			Message: "key ‹foo›", Redactable: true},
+		{Severity: Severity_WARNING, Time: now.UnixNano(), File: "bar.go", Line: 34,
+			Message: "not redactable"},
+	} {
+		if err := e.Format(&contents); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const expected = "I200601 12:30:15.123456 foo.go:12 ⋮ key ‹foo›\n" +
+		"W200601 12:30:15.123456 bar.go:34  not redactable\n"
+	if contents.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, contents.String())
+	}
+
+	d := NewEntryDecoder(strings.NewReader(contents.String()))
+	for _, exp := range []struct {
+		msg        string
+		redactable bool
+	}{{"key ‹foo›", true}, {"not redactable", false}} {
+		var e Entry
+		if err := d.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		if e.Message != exp.msg || e.Redactable != exp.redactable {
+			t.Errorf("expected %q (redactable=%t), got %q (redactable=%t)",
+				exp.msg, exp.redactable, e.Message, e.Redactable)
+		}
+	}
+}
@@ -18,6 +18,8 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/util/caller"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/logtags"
 )
 
 // SecondaryLogger represents a secondary / auxiliary logging channel
@@ -28,6 +30,9 @@ type SecondaryLogger struct {
 	msgCount        uint64
 	enableMsgCount  bool
 	forceSyncWrites bool
+	// ch is the channel of the entries of the logger, or -1 if the entries
+	// are only written to the files of the logger. Handled atomically.
+	ch int32
 }
 
 var secondaryLogRegistry struct {
@@ -74,6 +79,7 @@ func NewSecondaryLogger(
 		},
 		forceSyncWrites: forceSyncWrites,
 		enableMsgCount:  enableMsgCount,
+		ch:              -1,
 	}
 	l.logger.mu.syncWrites = forceSyncWrites || mainLog.mu.syncWrites
 
@@ -106,12 +112,25 @@ func (l *SecondaryLogger) Close() {
 	}
 }
 
+// SetChannel sets the channel of the entries of the logger. The entries are
+// written to the files of the logger, and copied to the other sinks of the
+// channel configured with ApplyConfig.
+func (l *SecondaryLogger) SetChannel(ch Channel) {
+	atomic.StoreInt32(&l.ch, int32(ch))
+}
+
 func (l *SecondaryLogger) output(
 	ctx context.Context, depth int, sev Severity, format string, args ...interface{},
 ) {
 	file, line, _ := caller.Lookup(depth + 1)
+	redactable := redactableLogs
 	var buf strings.Builder
-	formatTags(ctx, &buf)
+	if !redactable {
+		formatTags(ctx, &buf)
+	} else if tags := logtags.FromContext(ctx); tags != nil {
+		formatRedactableTags(tags, &buf)
+	}
+	tagsLen := buf.Len()
 
 	if l.enableMsgCount {
 		// Add a counter. This is important for the SQL audit logs.
@@ -119,12 +138,22 @@ func (l *SecondaryLogger) output(
 		fmt.Fprintf(&buf, "%d ", counter)
 	}
 
-	if format == "" {
+	switch {
+	case format == "" && redactable:
+		fprintRedactable(&buf, args)
+	case format == "":
 		fmt.Fprint(&buf, args...)
-	} else {
+	case redactable:
+		fmt.Fprintf(&buf, escapeMarkers.Replace(format), makeRedactableArgs(args)...)
+	default:
 		fmt.Fprintf(&buf, format, args...)
 	}
-	l.logger.outputLogEntry(Severity_INFO, file, line, buf.String())
+	ch := Channel(atomic.LoadInt32(&l.ch))
+	entry := makeLogEntry(ch, Severity_INFO, timeutil.Now().UnixNano(), file, line,
+		logtags.FromContext(ctx), tagsLen, buf.String(), nil /* payload */)
+	entry.Redactable = redactable
+	outputToExtraSinks(getChannelSinks(ch), entry)
+	l.logger.outputLogEntry(entry)
 }
 
 // Logf logs an event on a secondary logger.
@@ -17,6 +17,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/util/caller"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/logtags"
 )
 
@@ -35,23 +36,63 @@ func formatTags(ctx context.Context, buf *strings.Builder) bool {
 
 // MakeMessage creates a structured log entry.
 func MakeMessage(ctx context.Context, format string, args []interface{}) string {
+	msg, _ := makeMessage(ctx, format, args, false /* redactable */)
+	return msg
+}
+
+// makeMessage is like MakeMessage, but also returns the length of the tags
+// at the start of the message. If redactable is set, the tags and arguments
+// which are not known to be safe are enclosed between redaction markers.
+// The format is always considered safe, even without arguments, so it must be
+// a constant: messages built at run time are passed as arguments instead, e.g.
+// Infof(ctx, "%s", Safe(msg)) if they are known to be safe.
+func makeMessage(
+	ctx context.Context, format string, args []interface{}, redactable bool,
+) (string, int) {
 	var buf strings.Builder
-	formatTags(ctx, &buf)
-	if len(args) == 0 {
+	if !redactable {
+		formatTags(ctx, &buf)
+	} else if tags := logtags.FromContext(ctx); tags != nil {
+		formatRedactableTags(tags, &buf)
+	}
+	tagsLen := buf.Len()
+	switch {
+	case len(args) == 0 && redactable:
+		buf.WriteString(escapeMarkers.Replace(format))
+	case len(args) == 0:
 		buf.WriteString(format)
-	} else if len(format) == 0 {
+	case len(format) == 0 && redactable:
+		fprintRedactable(&buf, args)
+	case len(format) == 0:
 		fmt.Fprint(&buf, args...)
-	} else {
+	case redactable:
+		fmt.Fprintf(&buf, escapeMarkers.Replace(format), makeRedactableArgs(args)...)
+	default:
 		fmt.Fprintf(&buf, format, args...)
 	}
-	return buf.String()
+	return buf.String(), tagsLen
 }
 
 // addStructured creates a structured log entry to be written to the
-// specified facility of the logger.
+// DEV channel.
 func addStructured(ctx context.Context, s Severity, depth int, format string, args []interface{}) {
+	addStructuredOnChannel(ctx, ChannelDev, s, depth+1, format, args, nil /* payload */)
+}
+
+// addStructuredOnChannel creates a structured log entry to be written to
+// the sinks of the specified channel.
+func addStructuredOnChannel(
+	ctx context.Context,
+	ch Channel,
+	s Severity,
+	depth int,
+	format string,
+	args []interface{},
+	payload []byte,
+) {
 	file, line, _ := caller.Lookup(depth + 1)
-	msg := MakeMessage(ctx, format, args)
+	redactable := redactableLogs
+	msg, tagsLen := makeMessage(ctx, format, args, redactable)
 
 	if s == Severity_FATAL {
 		// We load the ReportingSettings from the a global singleton in this
@@ -62,6 +103,14 @@ func addStructured(ctx context.Context, s Severity, depth int, format string, ar
 	}
 	// MakeMessage already added the tags when forming msg, we don't want
 	// eventInternal to prepend them again.
-	eventInternal(ctx, (s >= Severity_ERROR), false /*withTags*/, "%s:%d %s", file, line, msg)
-	mainLog.outputLogEntry(s, file, line, msg)
+	traceMsg := msg
+	if redactable {
+		// The traces don't use the redaction markers.
+		traceMsg = MakeMessage(ctx, format, args)
+	}
+	eventInternal(ctx, (s >= Severity_ERROR), false /*withTags*/, "%s:%d %s", file, line, traceMsg)
+	entry := makeLogEntry(ch, s, timeutil.Now().UnixNano(), file, line,
+		logtags.FromContext(ctx), tagsLen, msg, payload)
+	entry.Redactable = redactable
+	outputToChannel(entry)
 }
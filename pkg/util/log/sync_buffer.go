@@ -128,18 +128,25 @@ func (sb *syncBuffer) rotateFile(now time.Time) error {
 
 	// Including a non-ascii character in the first 1024 bytes of the log helps
 	// viewers that attempt to guess the character encoding.
-	messages = append(messages, fmt.Sprintf("line format: [IWEF]yymmdd hh:mm:ss.uuuuuu goid file:line msg utf8=\u2713\n"))
+	if sb.logger.format.get() == formatJSON {
+		messages = append(messages, fmt.Sprintf("line format: json utf8=\u2713\n"))
+	} else {
+		messages = append(messages, fmt.Sprintf("line format: [IWEF]yymmdd hh:mm:ss.uuuuuu goid file:line msg utf8=\u2713\n"))
+	}
 
 	f, l, _ := caller.Lookup(1)
 	for _, msg := range messages {
-		buf := logging.formatLogEntry(Entry{
-			Severity:  Severity_INFO,
-			Time:      now.UnixNano(),
-			Goroutine: goid.Get(),
-			File:      f,
-			Line:      int64(l),
-			Message:   msg,
-		}, nil, nil)
+		buf := logging.processForFile(sb.logger, logEntry{
+			Entry: Entry{
+				Severity:  Severity_INFO,
+				Time:      now.UnixNano(),
+				Goroutine: goid.Get(),
+				File:      f,
+				Line:      int64(l),
+				Message:   msg,
+			},
+			ch: ChannelOps,
+		}, nil)
 		var n int
 		n, err = sb.file.Write(buf.Bytes())
 		putBuffer(buf)
@@ -14,6 +14,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"sync/atomic"
 	"time"
 
@@ -39,6 +40,37 @@ const (
 	histWrapNum = 2
 )
 
+// LatencyBuckets is the bucket layout with which the histograms returned by
+// NewLatency are exposed in the OpenMetrics format. The upper bounds, in
+// nanoseconds, double from 100µs up to ~6.5s; slower values fall into the
+// +Inf bucket.
+var LatencyBuckets = ExponentialBuckets(float64(100*time.Microsecond), 2, 17)
+
+// ExponentialBuckets returns the upper bounds of count buckets, the first
+// one being start and each following one being factor times the previous
+// one.
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	if start <= 0 || factor <= 1 || count < 1 {
+		panic(fmt.Sprintf("invalid exponential buckets: start=%v factor=%v count=%d",
+			start, factor, count))
+	}
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start
+		start *= factor
+	}
+	return buckets
+}
+
+// An Exemplar is a value recorded in a histogram along with the ID of the
+// trace under which it was recorded, which allows going from an outlier in a
+// latency histogram to the trace of the operation.
+type Exemplar struct {
+	TraceID   string
+	Value     float64
+	Timestamp time.Time
+}
+
 // Iterable provides a method for synchronized access to interior objects.
 type Iterable interface {
 	// GetName returns the fully-qualified name of the metric.
@@ -171,13 +203,29 @@ func maybeTick(m periodic) {
 //
 // Top-level methods generally apply to the cumulative buckets; the windowed
 // variant is exposed through the Windowed method.
+//
+// A Histogram can also be given a fixed bucket layout, in which case it
+// additionally keeps exact (cumulative) counts for these buckets, the exact
+// sum of the recorded values and the latest exemplar of each bucket. These
+// are what the OpenMetrics exposition reports, as they can be aggregated
+// across nodes and used with rate() without losing precision.
 type Histogram struct {
 	Metadata
 	maxVal int64
-	mu     struct {
+	// buckets are the sorted upper bounds of the bucket layout, if any. The
+	// +Inf bucket is implicit.
+	buckets []float64
+	mu      struct {
 		syncutil.Mutex
 		cumulative *hdrhistogram.Histogram
 		sliding    *slidingHistogram
+		// counts has one (non-cumulative) count per bucket, followed by the
+		// count of the +Inf bucket. sum is the sum of all recorded values.
+		counts []uint64
+		sum    float64
+		// exemplars holds the latest exemplar recorded in each bucket, if
+		// any.
+		exemplars []*Exemplar
 	}
 }
 
@@ -186,13 +234,29 @@ type Histogram struct {
 // track nonnegative values up to 'maxVal' with 'sigFigs' decimal points of
 // precision.
 func NewHistogram(metadata Metadata, duration time.Duration, maxVal int64, sigFigs int) *Histogram {
+	return NewHistogramWithBuckets(metadata, duration, maxVal, sigFigs, nil /* buckets */)
+}
+
+// NewHistogramWithBuckets is like NewHistogram, but additionally gives the
+// histogram the bucket layout described by the given sorted upper bounds.
+func NewHistogramWithBuckets(
+	metadata Metadata, duration time.Duration, maxVal int64, sigFigs int, buckets []float64,
+) *Histogram {
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("histogram buckets must be sorted: %v", buckets))
+	}
 	dHist := newSlidingHistogram(duration, maxVal, sigFigs)
 	h := &Histogram{
 		Metadata: metadata,
 		maxVal:   maxVal,
+		buckets:  buckets,
 	}
 	h.mu.cumulative = hdrhistogram.New(0, maxVal, sigFigs)
 	h.mu.sliding = dHist
+	if len(buckets) > 0 {
+		h.mu.counts = make([]uint64, len(buckets)+1)
+		h.mu.exemplars = make([]*Exemplar, len(buckets)+1)
+	}
 	return h
 }
 
@@ -202,10 +266,10 @@ func NewHistogram(metadata Metadata, duration time.Duration, maxVal int64, sigFi
 // with one digit of precision (i.e. errors of <10ms at 100ms, <6s at 60s).
 //
 // The windowed portion of the Histogram retains values for approximately
-// histogramWindow.
+// histogramWindow. The histogram uses the LatencyBuckets bucket layout.
 func NewLatency(metadata Metadata, histogramWindow time.Duration) *Histogram {
-	return NewHistogram(
-		metadata, histogramWindow, MaxLatency.Nanoseconds(), 1,
+	return NewHistogramWithBuckets(
+		metadata, histogramWindow, MaxLatency.Nanoseconds(), 1, LatencyBuckets,
 	)
 }
 
@@ -229,6 +293,13 @@ func (h *Histogram) Snapshot() *hdrhistogram.Histogram {
 // excess of the configured maximum value for that histogram results in
 // recording the maximum value instead.
 func (h *Histogram) RecordValue(v int64) {
+	h.RecordValueWithExemplar(v, "" /* traceID */)
+}
+
+// RecordValueWithExemplar is like RecordValue, but additionally records the
+// value as the exemplar of its bucket if traceID is not empty and the
+// histogram has a bucket layout.
+func (h *Histogram) RecordValueWithExemplar(v int64, traceID string) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -238,6 +309,18 @@ func (h *Histogram) RecordValue(v int64) {
 	if h.mu.cumulative.RecordValue(v) != nil {
 		_ = h.mu.cumulative.RecordValue(h.maxVal)
 	}
+	if h.mu.counts == nil {
+		return
+	}
+	value := float64(v)
+	// The buckets are upper-inclusive, so this finds the first bucket whose
+	// upper bound is >= value, or the +Inf bucket.
+	i := sort.SearchFloat64s(h.buckets, value)
+	h.mu.counts[i]++
+	h.mu.sum += value
+	if traceID != "" {
+		h.mu.exemplars[i] = &Exemplar{TraceID: traceID, Value: value, Timestamp: now()}
+	}
 }
 
 // TotalCount returns the (cumulative) number of samples.
@@ -303,6 +386,65 @@ func (h *Histogram) ToPrometheusMetric() *prometheusgo.Metric {
 	}
 }
 
+// openMetricsQuantiles are the quantiles of the windowed histogram reported
+// alongside each histogram in the OpenMetrics exposition.
+var openMetricsQuantiles = []float64{0.5, 0.75, 0.9, 0.99, 0.999, 0.9999, 0.99999, 1}
+
+// toOpenMetrics returns the histogram as exposed in the OpenMetrics format:
+//  - hist reports the exact counts of the bucket layout if the histogram has
+//    one, or the buckets derived from the cumulative HDR histogram otherwise
+//    (see ToPrometheusMetric).
+//  - exemplars holds the exemplar of each bucket of hist, if any; it is nil
+//    if the histogram has no bucket layout.
+//  - quantiles reports the quantiles of the windowed HDR histogram.
+func (h *Histogram) toOpenMetrics() (
+	hist *prometheusgo.Metric,
+	exemplars []*Exemplar,
+	quantiles *prometheusgo.Metric,
+) {
+	windowed, _ := h.Windowed()
+	summary := &prometheusgo.Summary{
+		Quantile: make([]*prometheusgo.Quantile, len(openMetricsQuantiles)),
+	}
+	for i, q := range openMetricsQuantiles {
+		summary.Quantile[i] = &prometheusgo.Quantile{
+			Quantile: proto.Float64(q),
+			Value:    proto.Float64(float64(windowed.ValueAtQuantile(q * 100))),
+		}
+	}
+	quantiles = &prometheusgo.Metric{Summary: summary}
+
+	if h.buckets == nil {
+		return h.ToPrometheusMetric(), nil, quantiles
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	bucketHist := &prometheusgo.Histogram{
+		Bucket: make([]*prometheusgo.Bucket, len(h.mu.counts)),
+	}
+	exemplars = make([]*Exemplar, len(h.mu.exemplars))
+	var cumCount uint64
+	for i, count := range h.mu.counts {
+		cumCount += count
+		upperBound := math.Inf(1)
+		if i < len(h.buckets) {
+			upperBound = h.buckets[i]
+		}
+		bucketHist.Bucket[i] = &prometheusgo.Bucket{
+			CumulativeCount: proto.Uint64(cumCount),
+			UpperBound:      proto.Float64(upperBound),
+		}
+		if e := h.mu.exemplars[i]; e != nil {
+			ex := *e
+			exemplars[i] = &ex
+		}
+	}
+	bucketHist.SampleCount = proto.Uint64(cumCount)
+	bucketHist.SampleSum = proto.Float64(h.mu.sum)
+	return &prometheusgo.Metric{Histogram: bucketHist}, exemplars, quantiles
+}
+
 // GetMetadata returns the metric's metadata including the Prometheus
 // MetricType.
 func (h *Histogram) GetMetadata() Metadata {
@@ -0,0 +1,190 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package metric
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	prometheusgo "github.com/prometheus/client_model/go"
+)
+
+// OpenMetricsContentType is the content type of the OpenMetrics text format
+// written by PrintAsOpenMetrics.
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// PrintAsOpenMetrics writes all metrics in the families map to the io.Writer
+// in the OpenMetrics text format (https://openmetrics.io). The families are
+// written sorted by name, and the exemplars of the histograms are included.
+// Note that, as required by the format, the samples of counters are suffixed
+// with "_total". Like PrintAsText, it removes the individual metrics from the
+// families as it goes.
+//
+// The vendored prometheus libraries predate OpenMetrics, which is why the
+// format is written here rather than with expfmt.
+func (pm *PrometheusExporter) PrintAsOpenMetrics(w io.Writer) error {
+	names := make([]string, 0, len(pm.families))
+	for name, family := range pm.families {
+		if len(family.Metric) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		if err := pm.writeOpenMetricsFamily(&buf, pm.families[name]); err != nil {
+			return err
+		}
+	}
+	buf.WriteString("# EOF\n")
+	pm.clearMetrics()
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func (pm *PrometheusExporter) writeOpenMetricsFamily(
+	buf *bytes.Buffer, family *prometheusgo.MetricFamily,
+) error {
+	name := family.GetName()
+	var typ string
+	switch family.GetType() {
+	case prometheusgo.MetricType_COUNTER:
+		typ = "counter"
+		// The samples of a counter are named after the family with a "_total"
+		// suffix, which must not be repeated.
+		name = strings.TrimSuffix(name, "_total")
+	case prometheusgo.MetricType_GAUGE:
+		typ = "gauge"
+	case prometheusgo.MetricType_HISTOGRAM:
+		typ = "histogram"
+	case prometheusgo.MetricType_SUMMARY:
+		typ = "summary"
+	case prometheusgo.MetricType_UNTYPED:
+		typ = "unknown"
+	default:
+		return errors.Errorf("metric family %s has unsupported type %s", name, family.GetType())
+	}
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, typ)
+	if help := family.GetHelp(); help != "" {
+		fmt.Fprintf(buf, "# HELP %s %s\n", name, escapeOpenMetricsString(help))
+	}
+
+	for _, m := range family.Metric {
+		switch family.GetType() {
+		case prometheusgo.MetricType_COUNTER:
+			writeOpenMetricsSample(buf, name+"_total", m.Label, "", "", m.GetCounter().GetValue())
+		case prometheusgo.MetricType_GAUGE:
+			writeOpenMetricsSample(buf, name, m.Label, "", "", m.GetGauge().GetValue())
+		case prometheusgo.MetricType_UNTYPED:
+			writeOpenMetricsSample(buf, name, m.Label, "", "", m.GetUntyped().GetValue())
+		case prometheusgo.MetricType_SUMMARY:
+			s := m.GetSummary()
+			for _, q := range s.Quantile {
+				writeOpenMetricsSample(buf, name, m.Label,
+					"quantile", formatOpenMetricsFloat(q.GetQuantile()), q.GetValue())
+			}
+			if s.SampleCount != nil {
+				writeOpenMetricsSample(buf, name+"_sum", m.Label, "", "", s.GetSampleSum())
+				writeOpenMetricsSample(buf, name+"_count", m.Label, "", "", float64(s.GetSampleCount()))
+			}
+		case prometheusgo.MetricType_HISTOGRAM:
+			h := m.GetHistogram()
+			exemplars := pm.exemplars[m]
+			var sawInf bool
+			for i, b := range h.Bucket {
+				sawInf = math.IsInf(b.GetUpperBound(), +1)
+				writeOpenMetricsSample(buf, name+"_bucket", m.Label,
+					"le", formatOpenMetricsFloat(b.GetUpperBound()), float64(b.GetCumulativeCount()))
+				if i < len(exemplars) && exemplars[i] != nil {
+					writeOpenMetricsExemplar(buf, exemplars[i])
+				}
+			}
+			// The +Inf bucket is mandatory; the buckets derived from HDR
+			// histograms don't have one.
+			if !sawInf {
+				writeOpenMetricsSample(buf, name+"_bucket", m.Label,
+					"le", "+Inf", float64(h.GetSampleCount()))
+			}
+			writeOpenMetricsSample(buf, name+"_sum", m.Label, "", "", h.GetSampleSum())
+			writeOpenMetricsSample(buf, name+"_count", m.Label, "", "", float64(h.GetSampleCount()))
+		}
+	}
+	return nil
+}
+
+// writeOpenMetricsSample writes a sample line with the given labels, plus the
+// extra label if extraName is not empty.
+func writeOpenMetricsSample(
+	buf *bytes.Buffer,
+	name string,
+	labels []*prometheusgo.LabelPair,
+	extraName, extraValue string,
+	value float64,
+) {
+	buf.WriteString(name)
+	if len(labels) > 0 || extraName != "" {
+		buf.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			fmt.Fprintf(buf, "%s=\"%s\"", l.GetName(), escapeOpenMetricsString(l.GetValue()))
+		}
+		if extraName != "" {
+			if len(labels) > 0 {
+				buf.WriteByte(',')
+			}
+			fmt.Fprintf(buf, "%s=\"%s\"", extraName, extraValue)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(formatOpenMetricsFloat(value))
+	buf.WriteByte('\n')
+}
+
+// writeOpenMetricsExemplar appends the exemplar to the sample line that was
+// last written to the buffer.
+func writeOpenMetricsExemplar(buf *bytes.Buffer, e *Exemplar) {
+	// Drop the newline terminating the sample.
+	buf.Truncate(buf.Len() - 1)
+	fmt.Fprintf(buf, " # {trace_id=\"%s\"} %s %s\n",
+		escapeOpenMetricsString(e.TraceID),
+		formatOpenMetricsFloat(e.Value),
+		strconv.FormatFloat(float64(e.Timestamp.UnixNano())/1e9, 'f', 3, 64))
+}
+
+func formatOpenMetricsFloat(f float64) string {
+	switch {
+	case math.IsInf(f, +1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+var openMetricsEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+// escapeOpenMetricsString escapes label values and help texts.
+func escapeOpenMetricsString(s string) string {
+	return openMetricsEscaper.Replace(s)
+}
@@ -33,6 +33,12 @@ import (
 //  pe.Export(w)
 type PrometheusExporter struct {
 	families map[string]*prometheusgo.MetricFamily
+	// openMetrics is set if the exporter scrapes the metrics for the
+	// OpenMetrics exposition (see MakeOpenMetricsExporter).
+	openMetrics bool
+	// exemplars holds the per-bucket exemplars of the scraped histograms. It
+	// is only populated by OpenMetrics exporters.
+	exemplars map[*prometheusgo.Metric][]*Exemplar
 }
 
 // MakePrometheusExporter returns an initialized prometheus exporter.
@@ -40,19 +46,30 @@ func MakePrometheusExporter() PrometheusExporter {
 	return PrometheusExporter{families: map[string]*prometheusgo.MetricFamily{}}
 }
 
-// find the family for the passed-in metric, or create and return it if not found.
+// MakeOpenMetricsExporter returns an initialized exporter meant to be
+// printed with PrintAsOpenMetrics. Unlike with MakePrometheusExporter, the
+// histograms which have a bucket layout are scraped with their exact bucket
+// counts and their exemplars, and every histogram is accompanied by a
+// "<name>_quantiles" summary reporting the quantiles of its windowed data.
+func MakeOpenMetricsExporter() PrometheusExporter {
+	pm := MakePrometheusExporter()
+	pm.openMetrics = true
+	pm.exemplars = map[*prometheusgo.Metric][]*Exemplar{}
+	return pm
+}
+
+// find the family with the given name, or create and return it if not found.
 func (pm *PrometheusExporter) findOrCreateFamily(
-	prom PrometheusExportable,
+	familyName string, help string, typ *prometheusgo.MetricType,
 ) *prometheusgo.MetricFamily {
-	familyName := exportedName(prom.GetName())
 	if family, ok := pm.families[familyName]; ok {
 		return family
 	}
 
 	family := &prometheusgo.MetricFamily{
 		Name: proto.String(familyName),
-		Help: proto.String(prom.GetHelp()),
-		Type: prom.GetType(),
+		Help: proto.String(help),
+		Type: typ,
 	}
 
 	pm.families[familyName] = family
@@ -66,14 +83,34 @@ func (pm *PrometheusExporter) findOrCreateFamily(
 func (pm *PrometheusExporter) ScrapeRegistry(registry *Registry) {
 	labels := registry.getLabels()
 	registry.Each(func(_ string, v interface{}) {
-		if prom, ok := v.(PrometheusExportable); ok {
-			m := prom.ToPrometheusMetric()
-			// Set registry and metric labels.
+		prom, ok := v.(PrometheusExportable)
+		if !ok {
+			return
+		}
+		familyName := exportedName(prom.GetName())
+		if h, ok := prom.(*Histogram); ok && pm.openMetrics {
+			m, exemplars, quantiles := h.toOpenMetrics()
 			m.Label = append(labels, prom.GetLabels()...)
+			quantiles.Label = m.Label
+			if exemplars != nil {
+				pm.exemplars[m] = exemplars
+			}
 
-			family := pm.findOrCreateFamily(prom)
+			family := pm.findOrCreateFamily(familyName, prom.GetHelp(), prom.GetType())
 			family.Metric = append(family.Metric, m)
+			family = pm.findOrCreateFamily(familyName+"_quantiles",
+				prom.GetHelp()+" (quantiles of recent values)",
+				prometheusgo.MetricType_SUMMARY.Enum())
+			family.Metric = append(family.Metric, quantiles)
+			return
 		}
+
+		m := prom.ToPrometheusMetric()
+		// Set registry and metric labels.
+		m.Label = append(labels, prom.GetLabels()...)
+
+		family := pm.findOrCreateFamily(familyName, prom.GetHelp(), prom.GetType())
+		family.Metric = append(family.Metric, m)
 	})
 }
 
@@ -110,4 +147,7 @@ func (pm *PrometheusExporter) clearMetrics() {
 		// Set to nil to avoid allocation if the family never gets any metrics.
 		family.Metric = nil
 	}
+	for m := range pm.exemplars {
+		delete(pm.exemplars, m)
+	}
 }
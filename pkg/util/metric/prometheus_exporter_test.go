@@ -10,7 +10,13 @@
 
 package metric
 
-import "testing"
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
 
 func TestPrometheusExporter(t *testing.T) {
 	r1, r2 := NewRegistry(), NewRegistry()
@@ -112,3 +118,74 @@ func TestPrometheusExporter(t *testing.T) {
 		}
 	}
 }
+
+func TestOpenMetricsExporter(t *testing.T) {
+	defer TestingSetNow(func() time.Time {
+		return timeutil.Unix(1600000000, 0)
+	})()
+
+	r := NewRegistry()
+	r.AddLabel("registry", "r")
+
+	c := NewCounter(Metadata{Name: "one.counter", Help: "a counter"})
+	c.Inc(3)
+	r.AddMetric(c)
+
+	gMeta := Metadata{Name: "one.gauge"}
+	gMeta.AddLabel("kind", "g")
+	g := NewGauge(gMeta)
+	g.Update(2)
+	r.AddMetric(g)
+
+	h := NewHistogramWithBuckets(Metadata{Name: "one.latency", Help: `a "latency"`},
+		time.Hour, 100, 3, []float64{10, 50})
+	h.RecordValue(5)
+	h.RecordValueWithExemplar(20, "abc")
+	h.RecordValue(200) // counts as 100 in the quantiles
+	r.AddMetric(h)
+
+	pe := MakeOpenMetricsExporter()
+	pe.ScrapeRegistry(r)
+	var buf bytes.Buffer
+	if err := pe.PrintAsOpenMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	const expected = `# TYPE one_counter counter
+# HELP one_counter a counter
+one_counter_total{registry="r"} 3
+# TYPE one_gauge gauge
+one_gauge{registry="r",kind="g"} 2
+# TYPE one_latency histogram
+# HELP one_latency a \"latency\"
+one_latency_bucket{registry="r",le="10"} 1
+one_latency_bucket{registry="r",le="50"} 2 # {trace_id="abc"} 20 1600000000.000
+one_latency_bucket{registry="r",le="+Inf"} 3
+one_latency_sum{registry="r"} 225
+one_latency_count{registry="r"} 3
+# TYPE one_latency_quantiles summary
+# HELP one_latency_quantiles a \"latency\" (quantiles of recent values)
+one_latency_quantiles{registry="r",quantile="0.5"} 20
+one_latency_quantiles{registry="r",quantile="0.75"} 20
+one_latency_quantiles{registry="r",quantile="0.9"} 100
+one_latency_quantiles{registry="r",quantile="0.99"} 100
+one_latency_quantiles{registry="r",quantile="0.999"} 100
+one_latency_quantiles{registry="r",quantile="0.9999"} 100
+one_latency_quantiles{registry="r",quantile="0.99999"} 100
+one_latency_quantiles{registry="r",quantile="1"} 100
+# EOF
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+
+	// The metrics and exemplars are cleared once printed.
+	for _, fam := range pe.families {
+		if numPoints := len(fam.Metric); numPoints != 0 {
+			t.Errorf("%s has %d data points, want 0", fam.GetName(), numPoints)
+		}
+	}
+	if len(pe.exemplars) != 0 {
+		t.Errorf("expected no exemplars, got %d", len(pe.exemplars))
+	}
+}
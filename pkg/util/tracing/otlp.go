@@ -0,0 +1,681 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing/otlppb"
+	opentracing "github.com/opentracing/opentracing-go"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// The OpenTelemetry shadow tracer sends the spans to an OpenTelemetry
+// collector using the OpenTelemetry protocol (OTLP), over gRPC or HTTP. The
+// spans are propagated using the W3C Trace Context format
+// (https://www.w3.org/TR/trace-context/), which also allows SQL clients to
+// pass the context of their own spans (see Tracer.ExtractTraceParent).
+
+// otlpProtocol is the transport used to send the spans to the collector.
+type otlpProtocol int64
+
+const (
+	otlpProtocolGRPC otlpProtocol = iota
+	otlpProtocolHTTP
+)
+
+const (
+	// otlpHTTPPath is the path of the OTLP/HTTP endpoint of the collectors.
+	otlpHTTPPath = "/v1/traces"
+	// otlpMaxBufferedSpans is the number of finished spans that can wait to be
+	// sent; more spans are dropped.
+	otlpMaxBufferedSpans = 10000
+	// otlpMaxExportBatchSize is the number of spans after which they are sent
+	// without waiting for otlpFlushInterval.
+	otlpMaxExportBatchSize = 512
+	// otlpFlushInterval is the interval at which the spans are sent.
+	otlpFlushInterval = time.Second
+	// otlpExportTimeout bounds the time it takes to send a batch of spans.
+	otlpExportTimeout = 10 * time.Second
+)
+
+// These constants are the keys used to represent the context of an otlpSpan
+// in carriers supporting opentracing.HTTPHeaders format.
+const (
+	// fieldNameTraceParent is the W3C Trace Context header.
+	fieldNameTraceParent = "traceparent"
+	prefixOTLPBaggage    = "otlp-baggage-"
+)
+
+// Attributes added to the spans in addition to their tags.
+const (
+	otlpAttrNodeID  = "cockroach.node_id"
+	otlpAttrRangeID = "cockroach.range_id"
+)
+
+// otlpSpanContext is the context of an otlpSpan.
+type otlpSpanContext struct {
+	traceID [16]byte
+	spanID  [8]byte
+	sampled bool
+	baggage map[string]string
+}
+
+var _ opentracing.SpanContext = &otlpSpanContext{}
+
+// ForeachBaggageItem is part of the opentracing.SpanContext interface.
+func (c *otlpSpanContext) ForeachBaggageItem(handler func(k, v string) bool) {
+	for k, v := range c.baggage {
+		if !handler(k, v) {
+			break
+		}
+	}
+}
+
+// ValidateTraceParent returns an error if the given string is not a valid W3C
+// Trace Context traceparent value.
+func ValidateTraceParent(traceParent string) error {
+	_, err := parseTraceParent(traceParent)
+	return err
+}
+
+// parseTraceParent parses a traceparent value, which has the format
+// <version>-<trace-id>-<parent-id>-<trace-flags>, e.g.
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01.
+func parseTraceParent(traceParent string) (*otlpSpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 {
+		return nil, errors.Errorf("invalid traceparent %q: expected 4 fields", traceParent)
+	}
+	version, err := hex.DecodeString(parts[0])
+	if err != nil || len(version) != 1 || version[0] == 0xff {
+		return nil, errors.Errorf("invalid traceparent %q: invalid version", traceParent)
+	}
+	if version[0] == 0 && len(parts) != 4 {
+		return nil, errors.Errorf("invalid traceparent %q: expected 4 fields", traceParent)
+	}
+	var c otlpSpanContext
+	if n, err := hex.Decode(c.traceID[:], []byte(parts[1])); err != nil ||
+		n != len(c.traceID) || len(parts[1]) != 2*len(c.traceID) || c.traceID == ([16]byte{}) {
+		return nil, errors.Errorf("invalid traceparent %q: invalid trace ID", traceParent)
+	}
+	if n, err := hex.Decode(c.spanID[:], []byte(parts[2])); err != nil ||
+		n != len(c.spanID) || len(parts[2]) != 2*len(c.spanID) || c.spanID == ([8]byte{}) {
+		return nil, errors.Errorf("invalid traceparent %q: invalid parent ID", traceParent)
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return nil, errors.Errorf("invalid traceparent %q: invalid trace flags", traceParent)
+	}
+	c.sampled = flags[0]&1 != 0
+	return &c, nil
+}
+
+// traceParent returns the traceparent value for the context.
+func (c *otlpSpanContext) traceParent() string {
+	var flags byte
+	if c.sampled {
+		flags = 1
+	}
+	return fmt.Sprintf("00-%x-%x-%02x", c.traceID[:], c.spanID[:], flags)
+}
+
+// otlpTracer is an opentracing.Tracer which sends the finished spans to an
+// OpenTelemetry collector.
+type otlpTracer struct {
+	exporter *otlpExporter
+}
+
+var _ opentracing.Tracer = &otlpTracer{}
+
+// StartSpan is part of the opentracing.Tracer interface.
+func (t *otlpTracer) StartSpan(
+	operationName string, opts ...opentracing.StartSpanOption,
+) opentracing.Span {
+	var sso opentracing.StartSpanOptions
+	for _, o := range opts {
+		o.Apply(&sso)
+	}
+
+	s := &otlpSpan{tracer: t}
+	var parent *otlpSpanContext
+	for _, r := range sso.References {
+		if r.Type != opentracing.ChildOfRef && r.Type != opentracing.FollowsFromRef {
+			continue
+		}
+		if c, ok := r.ReferencedContext.(*otlpSpanContext); ok {
+			parent = c
+			break
+		}
+	}
+	if parent != nil {
+		s.ctx.traceID = parent.traceID
+		s.ctx.sampled = parent.sampled
+		if len(parent.baggage) > 0 {
+			s.ctx.baggage = make(map[string]string, len(parent.baggage))
+			for k, v := range parent.baggage {
+				s.ctx.baggage[k] = v
+			}
+		}
+	} else {
+		for s.ctx.traceID == ([16]byte{}) {
+			_, _ = rand.Read(s.ctx.traceID[:])
+		}
+		s.ctx.sampled = true
+	}
+	for s.ctx.spanID == ([8]byte{}) {
+		_, _ = rand.Read(s.ctx.spanID[:])
+	}
+
+	startTime := sso.StartTime
+	if startTime.IsZero() {
+		startTime = timeutil.Now()
+	}
+	s.mu.span = otlppb.Span{
+		TraceID:           s.ctx.traceID[:],
+		SpanID:            s.ctx.spanID[:],
+		Name:              operationName,
+		Kind:              otlppb.Span_SPAN_KIND_INTERNAL,
+		StartTimeUnixNano: uint64(startTime.UnixNano()),
+	}
+	if parent != nil {
+		s.mu.span.ParentSpanID = parent.spanID[:]
+	}
+	for k, v := range sso.Tags {
+		s.setTagLocked(k, v)
+	}
+	return s
+}
+
+// Inject is part of the opentracing.Tracer interface.
+func (t *otlpTracer) Inject(
+	osc opentracing.SpanContext, format interface{}, carrier interface{},
+) error {
+	if format != opentracing.HTTPHeaders && format != opentracing.TextMap {
+		return opentracing.ErrUnsupportedFormat
+	}
+	mapWriter, ok := carrier.(opentracing.TextMapWriter)
+	if !ok {
+		return opentracing.ErrInvalidCarrier
+	}
+	c, ok := osc.(*otlpSpanContext)
+	if !ok {
+		return opentracing.ErrInvalidSpanContext
+	}
+	mapWriter.Set(fieldNameTraceParent, c.traceParent())
+	for k, v := range c.baggage {
+		mapWriter.Set(prefixOTLPBaggage+k, v)
+	}
+	return nil
+}
+
+// Extract is part of the opentracing.Tracer interface.
+func (t *otlpTracer) Extract(
+	format interface{}, carrier interface{},
+) (opentracing.SpanContext, error) {
+	if format != opentracing.HTTPHeaders && format != opentracing.TextMap {
+		return nil, opentracing.ErrUnsupportedFormat
+	}
+	mapReader, ok := carrier.(opentracing.TextMapReader)
+	if !ok {
+		return nil, opentracing.ErrInvalidCarrier
+	}
+	var c *otlpSpanContext
+	var baggage map[string]string
+	err := mapReader.ForeachKey(func(k, v string) error {
+		switch k = strings.ToLower(k); {
+		case k == fieldNameTraceParent:
+			var err error
+			if c, err = parseTraceParent(v); err != nil {
+				return opentracing.ErrSpanContextCorrupted
+			}
+		case strings.HasPrefix(k, prefixOTLPBaggage):
+			if baggage == nil {
+				baggage = make(map[string]string)
+			}
+			baggage[strings.TrimPrefix(k, prefixOTLPBaggage)] = v
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+	c.baggage = baggage
+	return c, nil
+}
+
+// otlpSpan is the span of an otlpTracer.
+type otlpSpan struct {
+	tracer *otlpTracer
+	ctx    otlpSpanContext
+
+	mu struct {
+		syncutil.Mutex
+		// span is sent to the collector when the span is finished.
+		span     otlppb.Span
+		finished bool
+	}
+}
+
+var _ opentracing.Span = &otlpSpan{}
+
+// Finish is part of the opentracing.Span interface.
+func (s *otlpSpan) Finish() {
+	s.FinishWithOptions(opentracing.FinishOptions{})
+}
+
+// FinishWithOptions is part of the opentracing.Span interface.
+func (s *otlpSpan) FinishWithOptions(opts opentracing.FinishOptions) {
+	finishTime := opts.FinishTime
+	if finishTime.IsZero() {
+		finishTime = timeutil.Now()
+	}
+	s.mu.Lock()
+	if s.mu.finished {
+		s.mu.Unlock()
+		return
+	}
+	s.mu.finished = true
+	s.mu.span.EndTimeUnixNano = uint64(finishTime.UnixNano())
+	span := s.mu.span
+	s.mu.Unlock()
+
+	if s.ctx.sampled {
+		s.tracer.exporter.enqueue(span)
+	}
+}
+
+// Context is part of the opentracing.Span interface.
+func (s *otlpSpan) Context() opentracing.SpanContext {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.ctx
+	if len(s.ctx.baggage) > 0 {
+		c.baggage = make(map[string]string, len(s.ctx.baggage))
+		for k, v := range s.ctx.baggage {
+			c.baggage[k] = v
+		}
+	}
+	return &c
+}
+
+// SetOperationName is part of the opentracing.Span interface.
+func (s *otlpSpan) SetOperationName(operationName string) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.span.Name = operationName
+	return s
+}
+
+// SetTag is part of the opentracing.Span interface.
+func (s *otlpSpan) SetTag(key string, value interface{}) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setTagLocked(key, value)
+	return s
+}
+
+func (s *otlpSpan) setTagLocked(key string, value interface{}) {
+	if s.mu.finished {
+		return
+	}
+	s.mu.span.Attributes = append(s.mu.span.Attributes, makeOTLPAttribute(key, value))
+	// The node and range log tags are also exported as integer attributes, so
+	// that the collector can index them.
+	var extraKey string
+	switch key {
+	case "node":
+		extraKey = otlpAttrNodeID
+	case "range":
+		extraKey = otlpAttrRangeID
+	default:
+		return
+	}
+	if id, ok := parseLeadingID(fmt.Sprint(value)); ok {
+		s.mu.span.Attributes = append(s.mu.span.Attributes, makeOTLPAttribute(extraKey, id))
+	}
+}
+
+// parseLeadingID parses the integer at the start of s. For example, the value
+// of the range log tag is <range ID>/<replica ID>:<span>.
+func parseLeadingID(s string) (int64, bool) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	id, err := strconv.ParseInt(s[:i], 10, 64)
+	return id, err == nil
+}
+
+// makeOTLPAttribute converts a tag or a log field into an OTLP attribute.
+func makeOTLPAttribute(key string, value interface{}) otlppb.KeyValue {
+	var v otlppb.AnyValue
+	switch t := value.(type) {
+	case string:
+		v.Value = &otlppb.AnyValue_StringValue{StringValue: t}
+	case bool:
+		v.Value = &otlppb.AnyValue_BoolValue{BoolValue: t}
+	case int:
+		v.Value = &otlppb.AnyValue_IntValue{IntValue: int64(t)}
+	case int32:
+		v.Value = &otlppb.AnyValue_IntValue{IntValue: int64(t)}
+	case int64:
+		v.Value = &otlppb.AnyValue_IntValue{IntValue: t}
+	case uint32:
+		v.Value = &otlppb.AnyValue_IntValue{IntValue: int64(t)}
+	case float32:
+		v.Value = &otlppb.AnyValue_DoubleValue{DoubleValue: float64(t)}
+	case float64:
+		v.Value = &otlppb.AnyValue_DoubleValue{DoubleValue: t}
+	default:
+		v.Value = &otlppb.AnyValue_StringValue{StringValue: fmt.Sprint(value)}
+	}
+	return otlppb.KeyValue{Key: key, Value: &v}
+}
+
+// LogFields is part of the opentracing.Span interface. The fields are
+// exported as an event, named after the LogMessageField field if there is one.
+func (s *otlpSpan) LogFields(fields ...otlog.Field) {
+	s.logFields(timeutil.Now(), fields)
+}
+
+func (s *otlpSpan) logFields(t time.Time, fields []otlog.Field) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.finished {
+		return
+	}
+	if len(s.mu.span.Events) >= maxLogsPerSpan {
+		s.mu.span.DroppedEventsCount++
+		return
+	}
+	ev := otlppb.Span_Event{TimeUnixNano: uint64(t.UnixNano())}
+	for _, f := range fields {
+		if f.Key() == LogMessageField && ev.Name == "" {
+			ev.Name = fmt.Sprint(f.Value())
+			continue
+		}
+		ev.Attributes = append(ev.Attributes, makeOTLPAttribute(f.Key(), f.Value()))
+	}
+	s.mu.span.Events = append(s.mu.span.Events, ev)
+}
+
+// LogKV is part of the opentracing.Span interface.
+func (s *otlpSpan) LogKV(alternatingKeyValues ...interface{}) {
+	fields, err := otlog.InterleavedKVToFields(alternatingKeyValues...)
+	if err != nil {
+		s.LogFields(otlog.Error(err), otlog.String("function", "LogKV"))
+		return
+	}
+	s.LogFields(fields...)
+}
+
+// SetBaggageItem is part of the opentracing.Span interface.
+func (s *otlpSpan) SetBaggageItem(restrictedKey, value string) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx.baggage == nil {
+		s.ctx.baggage = make(map[string]string)
+	}
+	s.ctx.baggage[restrictedKey] = value
+	return s
+}
+
+// BaggageItem is part of the opentracing.Span interface.
+func (s *otlpSpan) BaggageItem(restrictedKey string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ctx.baggage[restrictedKey]
+}
+
+// Tracer is part of the opentracing.Span interface.
+func (s *otlpSpan) Tracer() opentracing.Tracer {
+	return s.tracer
+}
+
+// LogEvent is part of the opentracing.Span interface. Deprecated.
+func (s *otlpSpan) LogEvent(event string) {
+	s.LogFields(otlog.String(LogMessageField, event))
+}
+
+// LogEventWithPayload is part of the opentracing.Span interface. Deprecated.
+func (s *otlpSpan) LogEventWithPayload(event string, payload interface{}) {
+	s.LogFields(otlog.String(LogMessageField, event), otlog.Object("payload", payload))
+}
+
+// Log is part of the opentracing.Span interface. Deprecated.
+func (s *otlpSpan) Log(data opentracing.LogData) {
+	t := data.Timestamp
+	if t.IsZero() {
+		t = timeutil.Now()
+	}
+	fields := []otlog.Field{otlog.String(LogMessageField, data.Event)}
+	if data.Payload != nil {
+		fields = append(fields, otlog.Object("payload", data.Payload))
+	}
+	s.logFields(t, fields)
+}
+
+// otlpExporter sends the finished spans to the collector in batches, from a
+// background goroutine.
+type otlpExporter struct {
+	// send sends a request to the collector.
+	send func(ctx context.Context, req *otlppb.ExportTraceServiceRequest) error
+	// closeFn releases the resources of the transport.
+	closeFn func()
+
+	mu struct {
+		syncutil.Mutex
+		spans   []otlppb.Span
+		dropped int
+	}
+
+	// flushC is signaled when a batch is full.
+	flushC chan struct{}
+	stopC  chan struct{}
+	doneC  chan struct{}
+}
+
+var otlpLogEveryN = util.Every(5 * time.Second)
+
+func newOTLPExporter(
+	send func(ctx context.Context, req *otlppb.ExportTraceServiceRequest) error, closeFn func(),
+) *otlpExporter {
+	e := &otlpExporter{
+		send:    send,
+		closeFn: closeFn,
+		flushC:  make(chan struct{}, 1),
+		stopC:   make(chan struct{}),
+		doneC:   make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+// enqueue adds a finished span to the next batch.
+func (e *otlpExporter) enqueue(span otlppb.Span) {
+	e.mu.Lock()
+	if len(e.mu.spans) >= otlpMaxBufferedSpans {
+		e.mu.dropped++
+		e.mu.Unlock()
+		return
+	}
+	e.mu.spans = append(e.mu.spans, span)
+	full := len(e.mu.spans) >= otlpMaxExportBatchSize
+	e.mu.Unlock()
+	if full {
+		select {
+		case e.flushC <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (e *otlpExporter) run() {
+	defer close(e.doneC)
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-e.flushC:
+		case <-e.stopC:
+			e.flush()
+			return
+		}
+		e.flush()
+	}
+}
+
+// flush sends the buffered spans.
+func (e *otlpExporter) flush() {
+	e.mu.Lock()
+	spans, dropped := e.mu.spans, e.mu.dropped
+	e.mu.spans, e.mu.dropped = nil, 0
+	e.mu.Unlock()
+
+	var err error
+	if dropped > 0 {
+		err = errors.Errorf("dropped %d spans", dropped)
+	}
+	for len(spans) > 0 {
+		batch := spans
+		if len(batch) > otlpMaxExportBatchSize {
+			batch = batch[:otlpMaxExportBatchSize]
+		}
+		spans = spans[len(batch):]
+		req := &otlppb.ExportTraceServiceRequest{
+			ResourceSpans: []otlppb.ResourceSpans{{
+				Resource: otlppb.Resource{
+					Attributes: []otlppb.KeyValue{makeOTLPAttribute("service.name", "cockroach")},
+				},
+				InstrumentationLibrarySpans: []otlppb.InstrumentationLibrarySpans{{
+					InstrumentationLibrary: otlppb.InstrumentationLibrary{Name: "cockroach"},
+					Spans:                  batch,
+				}},
+			}},
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), otlpExportTimeout)
+		if sendErr := e.send(ctx, req); sendErr != nil {
+			err = sendErr
+		}
+		cancel()
+	}
+	if err != nil && otlpLogEveryN.ShouldProcess(timeutil.Now()) {
+		// We can't use `log` from this package so print the errors to stderr.
+		fmt.Fprintln(os.Stderr, "OpenTelemetry exporter:", err)
+	}
+}
+
+// close sends the buffered spans and stops the exporter.
+func (e *otlpExporter) close() {
+	close(e.stopC)
+	<-e.doneC
+	e.closeFn()
+}
+
+type otlpManager struct {
+	exporter *otlpExporter
+}
+
+func (*otlpManager) Name() string {
+	return "opentelemetry"
+}
+
+func (m *otlpManager) Close(tr opentracing.Tracer) {
+	m.exporter.close()
+}
+
+// createOTLPTracer creates a tracer which sends the spans to the collector
+// listening on the given address. For OTLP/HTTP, the address can also be the
+// URL of the endpoint (by default, http://<address>/v1/traces, or https if
+// useTLS is set). With TLS, the certificate of the collector is verified using
+// the root certificates of the host.
+func createOTLPTracer(
+	collectorAddr string, protocol otlpProtocol, useTLS bool,
+) (shadowTracerManager, opentracing.Tracer, error) {
+	var exporter *otlpExporter
+	switch protocol {
+	case otlpProtocolHTTP:
+		url := collectorAddr
+		if !strings.Contains(url, "://") {
+			scheme := "http://"
+			if useTLS {
+				scheme = "https://"
+			}
+			url = scheme + collectorAddr + otlpHTTPPath
+		}
+		client := &http.Client{Timeout: otlpExportTimeout}
+		exporter = newOTLPExporter(func(ctx context.Context, req *otlppb.ExportTraceServiceRequest) error {
+			return sendOTLPHTTP(ctx, client, url, req)
+		}, func() {})
+	default:
+		creds := grpc.WithInsecure()
+		if useTLS {
+			creds = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{}))
+		}
+		// The connection is established in the background and re-established
+		// if it fails; the errors are returned by Export.
+		conn, err := grpc.Dial(collectorAddr, creds)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "connecting to %s", collectorAddr)
+		}
+		client := otlppb.NewTraceServiceClient(conn)
+		exporter = newOTLPExporter(func(ctx context.Context, req *otlppb.ExportTraceServiceRequest) error {
+			_, err := client.Export(ctx, req)
+			return err
+		}, func() { _ = conn.Close() })
+	}
+	return &otlpManager{exporter: exporter}, &otlpTracer{exporter: exporter}, nil
+}
+
+// sendOTLPHTTP sends a request to an OTLP/HTTP endpoint.
+func sendOTLPHTTP(
+	ctx context.Context, client *http.Client, url string, req *otlppb.ExportTraceServiceRequest,
+) error {
+	body := make([]byte, req.Size())
+	if _, err := req.MarshalTo(body); err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	resp, err := client.Do(httpReq.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("%s: unexpected status %s", url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tracing_test
+
+import (
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing/otlppb"
+	"github.com/cockroachdb/logtags"
+	opentracing "github.com/opentracing/opentracing-go"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"google.golang.org/grpc"
+)
+
+// fakeCollector is an OpenTelemetry collector which records the spans it
+// receives.
+type fakeCollector struct {
+	mu struct {
+		syncutil.Mutex
+		spans []otlppb.Span
+	}
+}
+
+var _ otlppb.TraceServiceServer = &fakeCollector{}
+
+// Export is part of the otlppb.TraceServiceServer interface.
+func (c *fakeCollector) Export(
+	_ context.Context, req *otlppb.ExportTraceServiceRequest,
+) (*otlppb.ExportTraceServiceResponse, error) {
+	c.add(req)
+	return &otlppb.ExportTraceServiceResponse{}, nil
+}
+
+// ServeHTTP serves the OTLP/HTTP endpoint.
+func (c *fakeCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/x-protobuf" {
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req otlppb.ExportTraceServiceRequest
+	if err := protoutil.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.add(&req)
+}
+
+func (c *fakeCollector) add(req *otlppb.ExportTraceServiceRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rs := range req.ResourceSpans {
+		for _, ils := range rs.InstrumentationLibrarySpans {
+			c.mu.spans = append(c.mu.spans, ils.Spans...)
+		}
+	}
+}
+
+func (c *fakeCollector) spans() map[string]otlppb.Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res := make(map[string]otlppb.Span)
+	for _, s := range c.mu.spans {
+		res[s.Name] = s
+	}
+	return res
+}
+
+func attributes(kvs []otlppb.KeyValue) map[string]string {
+	res := make(map[string]string)
+	for _, kv := range kvs {
+		switch v := kv.Value.Value.(type) {
+		case *otlppb.AnyValue_StringValue:
+			res[kv.Key] = v.StringValue
+		case *otlppb.AnyValue_IntValue:
+			res[kv.Key] = strconv.FormatInt(v.IntValue, 10)
+		}
+	}
+	return res
+}
+
+func TestOTLPExporter(t *testing.T) {
+	testCases := []struct {
+		protocol string
+		start    func(t *testing.T, c *fakeCollector) (addr string, stop func())
+	}{
+		{
+			protocol: "0", // grpc
+			start: func(t *testing.T, c *fakeCollector) (string, func()) {
+				ln, err := net.Listen("tcp", "127.0.0.1:0")
+				if err != nil {
+					t.Fatal(err)
+				}
+				s := grpc.NewServer()
+				otlppb.RegisterTraceServiceServer(s, c)
+				go func() { _ = s.Serve(ln) }()
+				return ln.Addr().String(), s.Stop
+			},
+		},
+		{
+			protocol: "1", // http
+			start: func(t *testing.T, c *fakeCollector) (string, func()) {
+				s := httptest.NewServer(c)
+				return s.Listener.Addr().String(), s.Close
+			},
+		},
+	}
+
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	for _, tc := range testCases {
+		t.Run(tc.protocol, func(t *testing.T) {
+			c := &fakeCollector{}
+			addr, stop := tc.start(t, c)
+			defer stop()
+
+			st := cluster.MakeTestingClusterSettings()
+			u := settings.NewUpdater(&st.SV)
+			if err := u.Set("trace.opentelemetry.protocol", tc.protocol, "e"); err != nil {
+				t.Fatal(err)
+			}
+			if err := u.Set("trace.opentelemetry.collector", addr, "s"); err != nil {
+				t.Fatal(err)
+			}
+			tr := tracing.NewTracer()
+			tr.Configure(&st.SV)
+
+			remoteCtx, err := tr.ExtractTraceParent(traceParent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tracing.IsNoopContext(remoteCtx) {
+				t.Fatal("expected the traceparent to be continued")
+			}
+			sp := tr.StartSpan("txn", opentracing.ChildOf(remoteCtx),
+				tracing.LogTags(logtags.SingleTagBuffer("node", 1)))
+			if !tracing.HasShadowSpan(sp) {
+				t.Fatal("expected a shadow span")
+			}
+			sp.SetTag("stmt.fingerprint", "SELECT _")
+			child := tr.StartSpan("replica", opentracing.ChildOf(sp.Context()),
+				tracing.LogTags(logtags.SingleTagBuffer("range", "12/1:/M{in-ax}")))
+			child.LogFields(otlog.String(tracing.LogMessageField, "hello"))
+			child.Finish()
+			sp.Finish()
+			// Closing the tracer sends the remaining spans.
+			tr.Close()
+
+			spans := c.spans()
+			if len(spans) != 2 {
+				t.Fatalf("expected 2 spans, got %+v", spans)
+			}
+			txn, replica := spans["txn"], spans["replica"]
+			if id := hex.EncodeToString(txn.TraceID); id != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Errorf("expected the trace of the traceparent, got %s", id)
+			}
+			if id := hex.EncodeToString(txn.ParentSpanID); id != "00f067aa0ba902b7" {
+				t.Errorf("expected the span of the traceparent as parent, got %s", id)
+			}
+			if id := hex.EncodeToString(replica.TraceID); id != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Errorf("expected the trace of the traceparent, got %s", id)
+			}
+			if hex.EncodeToString(replica.ParentSpanID) != hex.EncodeToString(txn.SpanID) {
+				t.Errorf("expected the txn span as parent, got %x", replica.ParentSpanID)
+			}
+			if txn.EndTimeUnixNano < txn.StartTimeUnixNano {
+				t.Errorf("invalid span times: %+v", txn)
+			}
+
+			attrs := attributes(txn.Attributes)
+			if attrs["stmt.fingerprint"] != "SELECT _" || attrs["node"] != "1" ||
+				attrs["cockroach.node_id"] != "1" {
+				t.Errorf("unexpected attributes: %v", attrs)
+			}
+			attrs = attributes(replica.Attributes)
+			if attrs["range"] != "12/1:/M{in-ax}" || attrs["cockroach.range_id"] != "12" {
+				t.Errorf("unexpected attributes: %v", attrs)
+			}
+			if len(replica.Events) != 1 || replica.Events[0].Name != "hello" {
+				t.Errorf("unexpected events: %+v", replica.Events)
+			}
+		})
+	}
+}
+
+// TestOTLPExporterTLS checks that the exporter can be configured to use TLS
+// for both protocols. The collector is not reachable, so the spans are dropped.
+func TestOTLPExporterTLS(t *testing.T) {
+	for _, protocol := range []string{"0", "1"} {
+		t.Run(protocol, func(t *testing.T) {
+			st := cluster.MakeTestingClusterSettings()
+			u := settings.NewUpdater(&st.SV)
+			if err := u.Set("trace.opentelemetry.protocol", protocol, "e"); err != nil {
+				t.Fatal(err)
+			}
+			if err := u.Set("trace.opentelemetry.tls.enabled", "true", "b"); err != nil {
+				t.Fatal(err)
+			}
+			if err := u.Set("trace.opentelemetry.collector", "127.0.0.1:1", "s"); err != nil {
+				t.Fatal(err)
+			}
+			tr := tracing.NewTracer()
+			tr.Configure(&st.SV)
+
+			sp := tr.StartSpan("txn")
+			if !tracing.HasShadowSpan(sp) {
+				t.Fatal("expected a shadow span")
+			}
+			sp.Finish()
+			tr.Close()
+		})
+	}
+}
+
+func TestOTLPInjectExtract(t *testing.T) {
+	st := cluster.MakeTestingClusterSettings()
+	u := settings.NewUpdater(&st.SV)
+	// Nothing listens on this address; the spans are dropped.
+	if err := u.Set("trace.opentelemetry.collector", "127.0.0.1:65535", "s"); err != nil {
+		t.Fatal(err)
+	}
+	tr := tracing.NewTracer()
+	tr.Configure(&st.SV)
+	defer tr.Close()
+
+	sp := tr.StartSpan("test")
+	defer sp.Finish()
+	carrier := make(opentracing.HTTPHeadersCarrier)
+	if err := tr.Inject(sp.Context(), opentracing.HTTPHeaders, carrier); err != nil {
+		t.Fatal(err)
+	}
+	traceParent := http.Header(carrier).Get("crdb-shadow-traceparent")
+	if err := tracing.ValidateTraceParent(traceParent); err != nil {
+		t.Fatalf("expected a valid traceparent: %v", err)
+	}
+
+	wireContext, err := tr.Extract(opentracing.HTTPHeaders, carrier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child := tr.StartSpan("child", opentracing.ChildOf(wireContext))
+	defer child.Finish()
+	childCarrier := make(opentracing.HTTPHeadersCarrier)
+	if err := tr.Inject(child.Context(), opentracing.HTTPHeaders, childCarrier); err != nil {
+		t.Fatal(err)
+	}
+	// The child is part of the same OpenTelemetry trace.
+	childTraceParent := http.Header(childCarrier).Get("crdb-shadow-traceparent")
+	if traceParent[:36] != childTraceParent[:36] || traceParent == childTraceParent {
+		t.Errorf("expected a child of %s, got %s", traceParent, childTraceParent)
+	}
+}
+
+func TestValidateTraceParent(t *testing.T) {
+	testCases := []struct {
+		traceParent string
+		valid       bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true},
+		// Future versions can have more fields.
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-xyz", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-xyz", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01", false},
+		{"", false},
+	}
+	for _, tc := range testCases {
+		err := tracing.ValidateTraceParent(tc.traceParent)
+		if valid := err == nil; valid != tc.valid {
+			t.Errorf("%q: expected valid=%t, got %v", tc.traceParent, tc.valid, err)
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"os"
 	"regexp"
 	"sort"
 	"strconv"
@@ -70,6 +71,28 @@ var zipkinCollector = settings.RegisterPublicStringSetting(
 	envutil.EnvOrDefaultString("COCKROACH_TEST_ZIPKIN_COLLECTOR", ""),
 )
 
+var otlpCollector = settings.RegisterPublicStringSetting(
+	"trace.opentelemetry.collector",
+	"if set, traces go to the given OpenTelemetry collector (example: '127.0.0.1:4317'); ignored if trace.lightstep.token or trace.zipkin.collector is set",
+	envutil.EnvOrDefaultString("COCKROACH_TEST_OTLP_COLLECTOR", ""),
+)
+
+var otlpProtocolSetting = settings.RegisterPublicEnumSetting(
+	"trace.opentelemetry.protocol",
+	"the OTLP transport used to send traces to trace.opentelemetry.collector",
+	"grpc",
+	map[int64]string{
+		int64(otlpProtocolGRPC): "grpc",
+		int64(otlpProtocolHTTP): "http",
+	},
+)
+
+var otlpTLS = settings.RegisterPublicBoolSetting(
+	"trace.opentelemetry.tls.enabled",
+	"if set, traces are sent to trace.opentelemetry.collector over TLS, verifying its certificate with the root certificates of the host",
+	false,
+)
+
 // Tracer is our own custom implementation of opentracing.Tracer. It supports:
 //
 //  - forwarding events to x/net/trace instances
@@ -79,7 +102,8 @@ var zipkinCollector = settings.RegisterPublicStringSetting(
 //    events can be retrieved at any time.
 //
 //  - lightstep traces. This is implemented by maintaining a "shadow" lightstep
-//    span inside each of our spans.
+//    span inside each of our spans. Zipkin and OpenTelemetry traces are
+//    implemented the same way.
 //
 // Even when tracing is disabled, we still use this Tracer (with x/net/trace and
 // lightstep disabled) because of its recording capability (snowball
@@ -125,6 +149,15 @@ func (t *Tracer) Configure(sv *settings.Values) {
 			t.setShadowTracer(createLightStepTracer(lsToken))
 		} else if zipkinAddr := zipkinCollector.Get(sv); zipkinAddr != "" {
 			t.setShadowTracer(createZipkinTracer(zipkinAddr))
+		} else if otlpAddr := otlpCollector.Get(sv); otlpAddr != "" {
+			manager, tr, err := createOTLPTracer(
+				otlpAddr, otlpProtocol(otlpProtocolSetting.Get(sv)), otlpTLS.Get(sv),
+			)
+			if err != nil {
+				// We can't use `log` from this package so print the error to stderr.
+				fmt.Fprintln(os.Stderr, "OpenTelemetry exporter:", err)
+			}
+			t.setShadowTracer(manager, tr)
 		} else {
 			t.setShadowTracer(nil, nil)
 		}
@@ -140,6 +173,9 @@ func (t *Tracer) Configure(sv *settings.Values) {
 	enableNetTrace.SetOnChange(sv, reconfigure)
 	lightstepToken.SetOnChange(sv, reconfigure)
 	zipkinCollector.SetOnChange(sv, reconfigure)
+	otlpCollector.SetOnChange(sv, reconfigure)
+	otlpProtocolSetting.SetOnChange(sv, reconfigure)
+	otlpTLS.SetOnChange(sv, reconfigure)
 }
 
 func (t *Tracer) useNetTrace() bool {
@@ -337,6 +373,31 @@ func (t *Tracer) StartSpan(
 	return s
 }
 
+// ExtractTraceParent returns the context of the remote span described by the
+// given W3C Trace Context traceparent value (e.g. passed by a SQL client), so
+// that the spans started as its children continue the remote trace. This is
+// only possible when the traces go to an OpenTelemetry collector; otherwise, a
+// noop context is returned.
+func (t *Tracer) ExtractTraceParent(traceParent string) (opentracing.SpanContext, error) {
+	shadowCtx, err := parseTraceParent(traceParent)
+	if err != nil {
+		return noopSpanContext{}, err
+	}
+	shadowTr := t.getShadowTracer()
+	if shadowTr == nil {
+		return noopSpanContext{}, nil
+	}
+	if _, ok := shadowTr.manager.(*otlpManager); !ok {
+		return noopSpanContext{}, nil
+	}
+	return &spanContext{
+		// The remote span is the root of the trace of our spans.
+		spanMeta:  spanMeta{TraceID: uint64(rand.Int63())},
+		shadowTr:  shadowTr,
+		shadowCtx: shadowCtx,
+	}, nil
+}
+
 // RecordableOpt specifies whether a root span should be recordable.
 type RecordableOpt bool
 
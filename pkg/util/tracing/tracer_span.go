@@ -12,6 +12,7 @@ package tracing
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"regexp"
@@ -564,6 +565,37 @@ func IsBlackHoleSpan(s opentracing.Span) bool {
 	return !sp.isRecording() && sp.netTr == nil && sp.shadowTr == nil
 }
 
+// HasShadowSpan returns true if the span is mirrored by the span of a shadow
+// tracer (e.g. Lightstep), which sends it to an external tracing system.
+func HasShadowSpan(s opentracing.Span) bool {
+	sp, ok := s.(*span)
+	return ok && sp.shadowTr != nil
+}
+
+// ExemplarTraceID returns the ID of the trace to which the span belongs, for
+// use in metric exemplars, if the span is sampled: that is, if it is recording
+// or if it is sent to an external tracing system. For spans sent to an
+// OpenTelemetry collector, the ID of the OpenTelemetry trace is returned so
+// that the exemplars link to it.
+func ExemplarTraceID(os opentracing.Span) (string, bool) {
+	sp, ok := os.(*span)
+	if !ok {
+		return "", false
+	}
+	sampled := sp.isRecording()
+	if otlpSp, ok := sp.shadowSpan.(*otlpSpan); ok {
+		if otlpSp.ctx.sampled {
+			return hex.EncodeToString(otlpSp.ctx.traceID[:]), true
+		}
+	} else if sp.shadowSpan != nil {
+		sampled = true
+	}
+	if !sampled {
+		return "", false
+	}
+	return strconv.FormatUint(sp.TraceID, 16), true
+}
+
 // IsNoopContext returns true if the span context is from a "no-op" span. If
 // this is true, any span derived from this context will be a "black hole span".
 func IsNoopContext(spanCtx opentracing.SpanContext) bool {